@@ -18,14 +18,45 @@ package cmd
 
 import (
 	"encoding/xml"
+	"io"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	ACL "github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/policy"
-	"io"
 )
 
+// parseACLRequest - parses ACL from x-amz-acl or x-amz-grant-* headers,
+// or from the request body if none of the headers is present.
+func parseACLRequest(r *http.Request, bucket string) (*ACL.AccessControlPolicy, APIErrorCode) {
+	aclPolicy, err := parseACLHeaders(r)
+	if err != nil {
+		return nil, toAPIErrorCode(err)
+	}
+
+	if aclPolicy != nil {
+		if r.ContentLength > 0 {
+			return nil, ErrUnexpectedContent
+		}
+		return aclPolicy, ErrNone
+	}
+
+	// ACL in request body always needs Content-Length.
+	if r.ContentLength <= 0 {
+		return nil, ErrMissingContentLength
+	}
+
+	if aclPolicy, err = ACL.ParseConfig(io.LimitReader(r.Body, r.ContentLength), bucket); err != nil {
+		return nil, toAPIErrorCode(err)
+	}
+
+	return aclPolicy, ErrNone
+}
+
+// PutBucketACLHandler - PUT Bucket ACL
+// -----------------
+// This operation uses the ACL
+// subresource to set the ACL of a specified bucket.
 func (api objectAPIHandlers) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, "SetBucketACL")
 
@@ -38,27 +69,30 @@ func (api objectAPIHandlers) PutBucketACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Allow getBucketACL if policy action is set, since this is a dummy call
-	// we are simply re-purposing the bucketPolicyAction.
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketPolicyAction, bucket, ""); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketACLAction, bucket, ""); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
-	aclPolicy, err := ACL.ParseConfig(io.LimitReader(r.Body, r.ContentLength), bucket)
-	if err != nil {
-		writeErrorResponse(w, ErrMalformedPolicy, r.URL)
+	// Before proceeding validate if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
-	err = objAPI.SetBucketAccessControlPolicy(ctx, bucket, aclPolicy)
-	if err != nil {
+	aclPolicy, s3Error := parseACLRequest(r, bucket)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := setBucketACL(ctx, objAPI, bucket, aclPolicy); err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
 	// Success.
-	writeSuccessNoContent(w)
+	writeSuccessResponseHeadersOnly(w)
 }
 
 // GetBucketACLHandler - GET Bucket ACL
@@ -77,9 +111,7 @@ func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Allow getBucketACL if policy action is set, since this is a dummy call
-	// we are simply re-purposing the bucketPolicyAction.
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketPolicyAction, bucket, ""); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketACLAction, bucket, ""); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -116,8 +148,47 @@ func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.
 	w.(http.Flusher).Flush()
 }
 
+// PutObjectACLHandler - PUT Object ACL
+// -----------------
+// This operation uses the ACL
+// subresource to set the ACL of a specified object.
 func (api objectAPIHandlers) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
-	//FIXME
+	ctx := newContext(r, "SetObjectACL")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutObjectACLAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Before proceeding validate if object exists.
+	if _, err := objAPI.GetObjectInfo(ctx, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	aclPolicy, s3Error := parseACLRequest(r, bucket)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := setObjectACL(ctx, objAPI, bucket, object, aclPolicy); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
 }
 
 // GetObjectACLHandler - GET Object ACL
@@ -137,9 +208,7 @@ func (api objectAPIHandlers) GetObjectACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Allow getObjectACL if policy action is set, since this is a dummy call
-	// we are simply re-purposing the bucketPolicyAction.
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketPolicyAction, bucket, ""); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectACLAction, bucket, object); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -0,0 +1,402 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// Prefix of object ACL configuration files of a bucket.
+const objectACLPrefix = "objects-acl"
+
+// ACLSys - Object ACL subsystem, bucket ACLs are cached by
+// BucketMetadataSys.
+type ACLSys struct {
+	sync.RWMutex
+	// Object ACLs of buckets, objects without an entry have the default ACL.
+	objectACLMap map[string]map[string]acl.AccessControlPolicy
+}
+
+// removeDeletedBuckets - removes cached object ACLs of buckets which are deleted
+// without a delete-bucket notification.
+func (sys *ACLSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.objectACLMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.objectACLMap, bucket)
+		}
+	}
+}
+
+// Remove - removes ACLs of all objects of given bucket name.
+func (sys *ACLSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.objectACLMap, bucketName)
+}
+
+// SetObjectACL - sets ACL to given object, nil ACL removes the ACL of
+// the object.
+func (sys *ACLSys) SetObjectACL(bucketName, objectName string, aclPolicy *acl.AccessControlPolicy) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if aclPolicy == nil {
+		delete(sys.objectACLMap[bucketName], objectName)
+		if len(sys.objectACLMap[bucketName]) == 0 {
+			delete(sys.objectACLMap, bucketName)
+		}
+		return
+	}
+
+	if sys.objectACLMap[bucketName] == nil {
+		sys.objectACLMap[bucketName] = make(map[string]acl.AccessControlPolicy)
+	}
+	sys.objectACLMap[bucketName][objectName] = *aclPolicy
+}
+
+// GetObjectACL - gets ACL associated to given object.
+func (sys *ACLSys) GetObjectACL(bucketName, objectName string) (aclPolicy acl.AccessControlPolicy, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	aclPolicy, ok = sys.objectACLMap[bucketName][objectName]
+	return aclPolicy, ok
+}
+
+// setObjectACLs - replaces cached object ACLs of given bucket.
+func (sys *ACLSys) setObjectACLs(bucketName string, aclMap map[string]acl.AccessControlPolicy) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if len(aclMap) == 0 {
+		delete(sys.objectACLMap, bucketName)
+		return
+	}
+	sys.objectACLMap[bucketName] = aclMap
+}
+
+// Refresh ACLSys.
+func (sys *ACLSys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+	for _, bucket := range buckets {
+		aclMap, err := listObjectACLConfigs(objAPI, bucket.Name)
+		if err != nil {
+			logger.LogIf(context.Background(), err)
+			continue
+		}
+		sys.setObjectACLs(bucket.Name, aclMap)
+	}
+	return nil
+}
+
+// Init - initializes ACL system from object ACLs of all buckets.
+func (sys *ACLSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	// Load ACLSys once during boot.
+	if err := sys.refresh(objAPI); err != nil {
+		return err
+	}
+
+	// Refresh ACLSys in background.
+	go func() {
+		ticker := time.NewTicker(globalRefreshBucketPolicyInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-ticker.C:
+				sys.refresh(objAPI)
+			}
+		}
+	}()
+	return nil
+}
+
+// NewACLSys - creates new ACL system.
+func NewACLSys() *ACLSys {
+	return &ACLSys{
+		objectACLMap: make(map[string]map[string]acl.AccessControlPolicy),
+	}
+}
+
+// bucketACLPermissions - maps actions to bucket ACL permission granting them.
+var bucketACLPermissions = map[policy.Action]string{
+	policy.HeadBucketAction:                 acl.PermissionRead,
+	policy.ListBucketAction:                 acl.PermissionRead,
+	policy.ListBucketMultipartUploadsAction: acl.PermissionRead,
+	policy.ListBucketVersionsAction:         acl.PermissionRead,
+	policy.AbortMultipartUploadAction:       acl.PermissionWrite,
+	policy.DeleteObjectAction:               acl.PermissionWrite,
+	policy.DeleteObjectVersionAction:        acl.PermissionWrite,
+	policy.ListMultipartUploadPartsAction:   acl.PermissionWrite,
+	policy.PutObjectAction:                  acl.PermissionWrite,
+	policy.GetBucketACLAction:               acl.PermissionReadACP,
+	policy.PutBucketACLAction:               acl.PermissionWriteACP,
+}
+
+// objectACLPermissions - maps actions to object ACL permission granting them.
+var objectACLPermissions = map[policy.Action]string{
+	policy.GetObjectAction:        acl.PermissionRead,
+	policy.GetObjectVersionAction: acl.PermissionRead,
+	policy.GetObjectACLAction:     acl.PermissionReadACP,
+	policy.PutObjectACLAction:     acl.PermissionWriteACP,
+}
+
+// isAllowedByACL - checks whether ACL of the bucket or the object grants
+// given action to the account. Empty accountName denotes an anonymous request.
+func isAllowedByACL(ctx context.Context, action policy.Action, bucketName, objectName, accountName string) bool {
+	if permission, found := bucketACLPermissions[action]; found {
+		aclPolicy := globalBucketMetadataSys.GetACL(bucketName)
+		return aclPolicy != nil && aclPolicy.IsAllowed(accountName, permission)
+	}
+
+	permission, found := objectACLPermissions[action]
+	if !found || objectName == "" {
+		return false
+	}
+
+	// Gateways keep object ACLs in their backend.
+	if globalGatewayName != "" {
+		objAPI := newObjectLayerFn()
+		if objAPI == nil {
+			return false
+		}
+
+		aclPolicy, err := objAPI.GetObjectAccessControlPolicy(ctx, bucketName, objectName)
+		if err != nil {
+			return false
+		}
+
+		return aclPolicy.IsAllowed(accountName, permission)
+	}
+
+	aclPolicy, ok := globalACLSys.GetObjectACL(bucketName, objectName)
+	if !ok {
+		return defaultAccessControlPolicy().IsAllowed(accountName, permission)
+	}
+
+	return aclPolicy.IsAllowed(accountName, permission)
+}
+
+// getACLOwner - returns owner of all buckets and objects.
+func getACLOwner() acl.Owner {
+	return acl.Owner{ID: globalMinioDefaultOwnerID}
+}
+
+// defaultAccessControlPolicy - returns ACL granting FULL_CONTROL to the
+// owner, which applies to buckets and objects without an ACL.
+func defaultAccessControlPolicy() *acl.AccessControlPolicy {
+	// Private canned ACL is always valid.
+	aclPolicy, _ := acl.NewCannedACL(acl.Private, getACLOwner(), getACLOwner())
+	return aclPolicy
+}
+
+// parseACLHeaders - returns ACL from x-amz-acl or x-amz-grant-* headers of
+// the request, or nil if none is present.
+func parseACLHeaders(r *http.Request) (*acl.AccessControlPolicy, error) {
+	return acl.ParseHeaders(r.Header, getACLOwner(), getACLOwner())
+}
+
+// setBucketACL - persists ACL of the bucket and sends it to all peers.
+func setBucketACL(ctx context.Context, objAPI ObjectLayer, bucketName string, aclPolicy *acl.AccessControlPolicy) error {
+	if err := objAPI.SetBucketAccessControlPolicy(ctx, bucketName, aclPolicy); err != nil {
+		return err
+	}
+
+	updateBucketConfig(ctx, bucketName, bucketACLConfig, aclPolicy)
+	return nil
+}
+
+// setObjectACL - persists ACL of the object and sends it to all peers.
+func setObjectACL(ctx context.Context, objAPI ObjectLayer, bucketName, objectName string, aclPolicy *acl.AccessControlPolicy) error {
+	if err := objAPI.SetObjectAccessControlPolicy(ctx, bucketName, objectName, aclPolicy); err != nil {
+		return err
+	}
+
+	globalACLSys.SetObjectACL(bucketName, objectName, aclPolicy)
+	for nerr := range globalNotificationSys.SetObjectACL(bucketName, objectName, aclPolicy) {
+		logger.GetReqInfo(ctx).AppendTags("remotePeer", nerr.Host.Name)
+		logger.LogIf(ctx, nerr.Err)
+	}
+
+	return nil
+}
+
+// resetObjectACL - removes ACL of an object which is replaced by a new
+// write, so that the new object gets the default ACL instead of the ACL
+// of the object it replaces.
+func resetObjectACL(ctx context.Context, objAPI ObjectLayer, bucketName, objectName string) error {
+	// Gateways keep object ACLs in their backend.
+	if globalGatewayName != "" {
+		return nil
+	}
+
+	if _, ok := globalACLSys.GetObjectACL(bucketName, objectName); !ok {
+		return nil
+	}
+
+	if err := removeObjectACLConfig(ctx, objAPI, bucketName, objectName); err != nil {
+		if _, ok := err.(ObjectACLNotFound); !ok {
+			return err
+		}
+	}
+
+	for nerr := range globalNotificationSys.SetObjectACL(bucketName, objectName, nil) {
+		logger.GetReqInfo(ctx).AppendTags("remotePeer", nerr.Host.Name)
+		logger.LogIf(ctx, nerr.Err)
+	}
+
+	return nil
+}
+
+func readACLConfig(objAPI ObjectLayer, configFile string) (*acl.AccessControlPolicy, error) {
+	reader, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return acl.ParseConfig(reader, "")
+}
+
+func saveACLConfig(objAPI ObjectLayer, configFile string, aclPolicy *acl.AccessControlPolicy) error {
+	data, err := xml.Marshal(aclPolicy)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(objAPI, configFile, data)
+}
+
+// getObjectACLConfigFile - returns path to acl.xml of given object.
+func getObjectACLConfigFile(bucketName, objectName string) string {
+	return path.Join(bucketConfigPrefix, bucketName, objectACLPrefix, objectName, bucketACLConfig)
+}
+
+// listObjectACLConfigs - returns ACLs of all objects of given bucket which
+// have one.
+func listObjectACLConfigs(objAPI ObjectLayer, bucketName string) (map[string]acl.AccessControlPolicy, error) {
+	prefix := path.Join(bucketConfigPrefix, bucketName, objectACLPrefix) + slashSeparator
+	aclMap := make(map[string]acl.AccessControlPolicy)
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(context.Background(), minioMetaBucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, objInfo := range result.Objects {
+			if path.Base(objInfo.Name) != bucketACLConfig {
+				continue
+			}
+			aclPolicy, err := readACLConfig(objAPI, objInfo.Name)
+			if err != nil {
+				// Object ACL might be removed while listing.
+				continue
+			}
+			objectName := strings.TrimSuffix(strings.TrimPrefix(objInfo.Name, prefix), slashSeparator+bucketACLConfig)
+			aclMap[objectName] = *aclPolicy
+		}
+
+		if !result.IsTruncated {
+			return aclMap, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// getObjectACLConfig - get ACL config for given object.
+func getObjectACLConfig(objAPI ObjectLayer, bucketName, objectName string) (*acl.AccessControlPolicy, error) {
+	aclPolicy, err := readACLConfig(objAPI, getObjectACLConfigFile(bucketName, objectName))
+	if err == errConfigNotFound {
+		err = ObjectACLNotFound{Bucket: bucketName, Object: objectName}
+	}
+
+	return aclPolicy, err
+}
+
+func saveObjectACLConfig(objAPI ObjectLayer, bucketName, objectName string, aclPolicy *acl.AccessControlPolicy) error {
+	return saveACLConfig(objAPI, getObjectACLConfigFile(bucketName, objectName), aclPolicy)
+}
+
+func removeObjectACLConfig(ctx context.Context, objAPI ObjectLayer, bucketName, objectName string) error {
+	// Peers drop the cached ACL on their next refresh.
+	globalACLSys.SetObjectACL(bucketName, objectName, nil)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, getObjectACLConfigFile(bucketName, objectName)); err != nil {
+		if _, ok := err.(ObjectNotFound); ok {
+			return ObjectACLNotFound{Bucket: bucketName, Object: objectName}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// getBucketAccessControlPolicy - returns ACL of given bucket, or the
+// default ACL if the bucket has none.
+func getBucketAccessControlPolicy(objAPI ObjectLayer, bucketName string) (*acl.AccessControlPolicy, error) {
+	config, err := getBucketConfig(objAPI, bucketName, bucketACLConfig)
+	if err != nil {
+		if _, ok := err.(BucketACLNotFound); ok {
+			return defaultAccessControlPolicy(), nil
+		}
+
+		return nil, err
+	}
+
+	return config.(*acl.AccessControlPolicy), nil
+}
+
+// getObjectAccessControlPolicy - returns ACL of given object, or the
+// default ACL if the object has none.
+func getObjectAccessControlPolicy(objAPI ObjectLayer, bucketName, objectName string) (*acl.AccessControlPolicy, error) {
+	aclPolicy, err := getObjectACLConfig(objAPI, bucketName, objectName)
+	if _, ok := err.(ObjectACLNotFound); ok {
+		return defaultAccessControlPolicy(), nil
+	}
+
+	return aclPolicy, err
+}
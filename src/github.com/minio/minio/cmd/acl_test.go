@@ -0,0 +1,291 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/acl"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+	"github.com/minio/minio/pkg/versioning"
+)
+
+// Wrapper for calling ACL config tests for both XL and FS.
+func TestACLConfig(t *testing.T) {
+	ExecObjectLayerTest(t, testACLConfig)
+}
+
+// Tests validate saving and reading of bucket and object ACLs.
+func testACLConfig(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	objectName := "dir/object"
+	if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	data := []byte("hello")
+	if _, err := obj.PutObject(context.Background(), bucketName, objectName, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	// Buckets and objects without ACL have the default ACL.
+	result, err := obj.GetBucketAccessControlPolicy(context.Background(), bucketName)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if !reflect.DeepEqual(result, defaultAccessControlPolicy()) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, defaultAccessControlPolicy(), result)
+	}
+
+	aclPolicy := defaultAccessControlPolicy()
+	aclPolicy.AccessControlList.Grants = append(aclPolicy.AccessControlList.Grants, acl.Grant{
+		Grantee: acl.Grantee{
+			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
+			XMLXSI: "CanonicalUser",
+			Type:   "CanonicalUser",
+			ID:     "reader",
+		},
+		Permission: "READ",
+	})
+
+	if err = obj.SetBucketAccessControlPolicy(context.Background(), bucketName, aclPolicy); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if result, err = obj.GetBucketAccessControlPolicy(context.Background(), bucketName); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if !reflect.DeepEqual(result.AccessControlList, aclPolicy.AccessControlList) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, aclPolicy.AccessControlList, result.AccessControlList)
+	}
+
+	if err = obj.SetObjectAccessControlPolicy(context.Background(), bucketName, objectName, aclPolicy); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if result, err = obj.GetObjectAccessControlPolicy(context.Background(), bucketName, objectName); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if !reflect.DeepEqual(result.AccessControlList, aclPolicy.AccessControlList) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, aclPolicy.AccessControlList, result.AccessControlList)
+	}
+
+	// Object ACLs are listed for caching by ACLSys.
+	aclMap, err := listObjectACLConfigs(obj, bucketName)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if result, ok := aclMap[objectName]; len(aclMap) != 1 || !ok || !reflect.DeepEqual(result.AccessControlList, aclPolicy.AccessControlList) {
+		t.Fatalf("%s: expected ACL of %s, got: %v", instanceType, objectName, aclMap)
+	}
+
+	// Deleting the object removes its ACL.
+	if err = obj.DeleteObject(context.Background(), bucketName, objectName); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	// Object ACLs are removed by xlSets, not by its individual sets.
+	if _, ok := obj.(*xlObjects); ok {
+		return
+	}
+
+	if _, err = getObjectACLConfig(obj, bucketName, objectName); err != (ObjectACLNotFound{Bucket: bucketName, Object: objectName}) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, ObjectACLNotFound{Bucket: bucketName, Object: objectName}, err)
+	}
+}
+
+// Wrapper for calling isAllowedByACL tests for both XL and FS.
+func TestIsAllowedByACL(t *testing.T) {
+	ExecObjectLayerTest(t, testIsAllowedByACL)
+}
+
+// Tests validate ACL grants of buckets and objects against actions.
+func testIsAllowedByACL(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	objectName := "object"
+	if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	data := []byte("hello")
+	if _, err := obj.PutObject(context.Background(), bucketName, objectName, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	publicRead, _ := acl.NewCannedACL(acl.PublicRead, getACLOwner(), getACLOwner())
+	globalBucketMetadataSys.Set(bucketName, bucketACLConfig, publicRead)
+	defer globalBucketMetadataSys.Remove(bucketName)
+	defer globalACLSys.Remove(bucketName)
+	globalACLSys.SetObjectACL(bucketName, objectName, publicRead)
+
+	testCases := []struct {
+		action         policy.Action
+		objectName     string
+		accountName    string
+		expectedResult bool
+	}{
+		{policy.ListBucketAction, "", "", true},
+		{policy.PutObjectAction, objectName, "", false},
+		{policy.GetBucketACLAction, "", "", false},
+		{policy.GetBucketACLAction, "", globalMinioDefaultOwnerID, true},
+		{policy.GetObjectAction, objectName, "", true},
+		{policy.GetObjectAction, "nonexistent", "", false},
+		{policy.PutObjectACLAction, objectName, "", false},
+		{policy.DeleteBucketAction, "", "", false},
+	}
+
+	for i, testCase := range testCases {
+		result := isAllowedByACL(context.Background(), testCase.action, bucketName, testCase.objectName, testCase.accountName)
+		if result != testCase.expectedResult {
+			t.Fatalf("%s: case %v: expected: %v, got: %v", instanceType, i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+// Wrapper for calling isAccountAllowed tests for both XL and FS.
+func TestIsAccountAllowedDeniedByPolicy(t *testing.T) {
+	ExecObjectLayerTest(t, testIsAccountAllowedDeniedByPolicy)
+}
+
+// Tests an explicit deny of the bucket policy is not overridden by ACLs.
+func testIsAccountAllowedDeniedByPolicy(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	objectName := "object"
+	if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	publicRead, _ := acl.NewCannedACL(acl.PublicRead, getACLOwner(), getACLOwner())
+	globalBucketMetadataSys.Set(bucketName, bucketACLConfig, publicRead)
+	defer globalBucketMetadataSys.Remove(bucketName)
+	defer globalACLSys.Remove(bucketName)
+	globalACLSys.SetObjectACL(bucketName, objectName, publicRead)
+
+	globalPolicySys.Set(bucketName, policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{
+			policy.NewStatement(
+				policy.Deny,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.GetObjectAction),
+				policy.NewResourceSet(policy.NewResource(bucketName, "*")),
+				condition.NewFunctions(),
+			),
+		},
+	})
+	defer globalPolicySys.Remove(bucketName)
+
+	testCases := []struct {
+		action         policy.Action
+		objectName     string
+		expectedResult bool
+	}{
+		{policy.ListBucketAction, "", true},
+		{policy.GetObjectAction, objectName, false},
+	}
+
+	for i, testCase := range testCases {
+		result := isAccountAllowed(context.Background(), policy.Args{
+			Action:     testCase.action,
+			BucketName: bucketName,
+			ObjectName: testCase.objectName,
+		})
+		if result != testCase.expectedResult {
+			t.Fatalf("%s: case %v: expected: %v, got: %v", instanceType, i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+// Tests object ACL is kept as long as any version of the object is left.
+func TestXLVersionedObjectACL(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+	defer resetGlobalBucketSystems()
+
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	ctx := context.Background()
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	globalBucketMetadataSys.Set(bucket, bucketVersioningConfig, &versioning.Versioning{Status: versioning.Enabled})
+
+	objInfo, err := obj.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader([]byte("abc")), 3, "", ""), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicRead, _ := acl.NewCannedACL(acl.PublicRead, getACLOwner(), getACLOwner())
+	if err = obj.SetObjectAccessControlPolicy(ctx, bucket, object, publicRead); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting adds a delete marker, the object data and its ACL are kept.
+	if err = obj.DeleteObject(ctx, bucket, object); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getObjectACLConfig(obj, bucket, object); err != nil {
+		t.Fatalf("Expected object ACL to be kept, got %v", err)
+	}
+
+	versions, err := obj.ListObjectVersions(ctx, bucket, object, "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range versions.Objects {
+		if version.VersionID == objInfo.VersionID {
+			continue
+		}
+		if _, err = obj.DeleteObjectVersion(ctx, bucket, object, version.VersionID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = getObjectACLConfig(obj, bucket, object); err != nil {
+		t.Fatalf("Expected object ACL to be kept, got %v", err)
+	}
+
+	// Deleting the last version removes the ACL.
+	if _, err = obj.DeleteObjectVersion(ctx, bucket, object, objInfo.VersionID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = getObjectACLConfig(obj, bucket, object); err == nil {
+		t.Fatal("Expected object ACL to be removed")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// validateAdminBucketReq - validates an admin request on the bucket
+// given by the `bucket` query parameter, and returns the object layer
+// and the bucket when the bucket exists.
+func validateAdminBucketReq(ctx context.Context, w http.ResponseWriter, r *http.Request) (ObjectLayer, string) {
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return nil, ""
+	}
+
+	bucket := r.URL.Query().Get("bucket")
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return nil, ""
+	}
+
+	return objectAPI, bucket
+}
+
+// SetBucketQuotaHandler - PUT /minio/admin/v1/set-bucket-quota?bucket=<bucket>
+// ----------
+// Sets the quota of a bucket, replacing an existing one.
+func (a adminAPIHandlers) SetBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "SetBucketQuota")
+
+	objectAPI, bucket := validateAdminBucketReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		writeErrorResponseJSON(w, ErrMissingContentLength, r.URL)
+		return
+	}
+
+	var quota madmin.BucketQuota
+	decoder := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&quota); err != nil || !quota.IsValid() {
+		writeErrorResponseJSON(w, ErrAdminInvalidBucketQuota, r.URL)
+		return
+	}
+
+	// Deny FIFO quota if WORM is enabled, as it removes objects.
+	if globalWORMEnabled && quota.Type == madmin.FIFOQuota {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	if err := saveBucketConfig(objectAPI, bucket, bucketQuotaConfig, &quota); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	updateBucketConfig(ctx, bucket, bucketQuotaConfig, &quota)
+
+	// Bring the bucket under its new quota right away.
+	if quota.Type == madmin.FIFOQuota && globalBucketQuotaSys.Usage(bucket).Size > quota.Quota {
+		go globalBucketQuotaSys.enforceFIFOQuota(context.Background(), objectAPI, bucket)
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketQuotaHandler - GET /minio/admin/v1/get-bucket-quota?bucket=<bucket>
+// ----------
+// Returns the quota of a bucket.
+func (a adminAPIHandlers) GetBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "GetBucketQuota")
+
+	objectAPI, bucket := validateAdminBucketReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	quota, err := getBucketConfig(objectAPI, bucket, bucketQuotaConfig)
+	if err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeIAMResponseJSON(w, r, quota)
+}
+
+// RemoveBucketQuotaHandler - DELETE /minio/admin/v1/remove-bucket-quota?bucket=<bucket>
+// ----------
+// Removes the quota of a bucket.
+func (a adminAPIHandlers) RemoveBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "RemoveBucketQuota")
+
+	objectAPI, bucket := validateAdminBucketReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	if err := removeBucketConfig(ctx, objectAPI, bucket, bucketQuotaConfig); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	updateBucketConfig(ctx, bucket, bucketQuotaConfig, nil)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketUsageHandler - GET /minio/admin/v1/bucket-usage?bucket=<bucket>
+// ----------
+// Returns the size and number of objects of a bucket.
+func (a adminAPIHandlers) GetBucketUsageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "GetBucketUsage")
+
+	objectAPI, bucket := validateAdminBucketReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	writeIAMResponseJSON(w, r, globalBucketQuotaSys.Usage(bucket))
+}
@@ -0,0 +1,371 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// validateAdminUsersReq - returns the object layer if the server is
+// initialized and the request is signed by the server owner, otherwise
+// writes an error response and returns nil.
+func validateAdminUsersReq(w http.ResponseWriter, r *http.Request) ObjectLayer {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponseJSON(w, ErrServerNotInitialized, r.URL)
+		return nil
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, globalServerConfig.GetRegion())
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return nil
+	}
+
+	return objectAPI
+}
+
+// loadIAMItemOnPeers - notifies all peers to reload a single user,
+// group or canned policy.
+func loadIAMItemOnPeers(ctx context.Context, prefix, name string) {
+	for nerr := range globalNotificationSys.LoadIAMItem(prefix, name) {
+		logger.GetReqInfo(ctx).AppendTags("remotePeer", nerr.Host.Name)
+		logger.LogIf(ctx, nerr.Err)
+	}
+}
+
+// writeIAMResponseJSON - writes given value as JSON response.
+func writeIAMResponseJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	writeSuccessResponseJSON(w, data)
+}
+
+// isValidAccountStatus - returns whether status is a known account status.
+func isValidAccountStatus(status madmin.AccountStatus) bool {
+	return status == madmin.AccountEnabled || status == madmin.AccountDisabled
+}
+
+// AddUserHandler - PUT /minio/admin/v1/add-user?accessKey=<access_key>
+// ----------
+// Adds a user, or updates the secret key and status of an existing
+// user.
+func (a adminAPIHandlers) AddUserHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "AddUser")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	var uinfo madmin.UserInfo
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxConfigJSONSize)).Decode(&uinfo); err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, ErrRequestBodyParse, r.URL)
+		return
+	}
+	if uinfo.Status != "" && !isValidAccountStatus(uinfo.Status) {
+		writeErrorResponseJSON(w, ErrInvalidRequest, r.URL)
+		return
+	}
+
+	accessKey := r.URL.Query().Get("accessKey")
+	if err := globalIAMSys.SetUser(objectAPI, accessKey, uinfo); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigUsersPrefix, accessKey)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// RemoveUserHandler - DELETE /minio/admin/v1/remove-user?accessKey=<access_key>
+// ----------
+// Removes a user together with its policy mapping and group
+// memberships.
+func (a adminAPIHandlers) RemoveUserHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "RemoveUser")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	accessKey := r.URL.Query().Get("accessKey")
+	if err := globalIAMSys.DeleteUser(objectAPI, accessKey); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigUsersPrefix, accessKey)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ListUsersHandler - GET /minio/admin/v1/list-users
+// ----------
+// Returns all users with their policy, groups and status. Secret keys
+// are not returned.
+func (a adminAPIHandlers) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	if objectAPI := validateAdminUsersReq(w, r); objectAPI == nil {
+		return
+	}
+
+	writeIAMResponseJSON(w, r, globalIAMSys.ListUsers())
+}
+
+// SetUserStatusHandler - PUT /minio/admin/v1/set-user-status?accessKey=<access_key>&status=<status>
+// ----------
+// Enables or disables a user.
+func (a adminAPIHandlers) SetUserStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "SetUserStatus")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	accessKey := r.URL.Query().Get("accessKey")
+	status := madmin.AccountStatus(r.URL.Query().Get("status"))
+	if !isValidAccountStatus(status) {
+		writeErrorResponseJSON(w, ErrInvalidRequest, r.URL)
+		return
+	}
+
+	if err := globalIAMSys.SetUserStatus(objectAPI, accessKey, status); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigUsersPrefix, accessKey)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// SetUserPolicyHandler - PUT /minio/admin/v1/set-user-policy?accessKey=<access_key>&name=<policy_name>
+// ----------
+// Attaches a canned policy to a user, an empty name detaches the
+// current policy.
+func (a adminAPIHandlers) SetUserPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "SetUserPolicy")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	accessKey := r.URL.Query().Get("accessKey")
+	policyName := r.URL.Query().Get("name")
+	if err := globalIAMSys.SetUserPolicy(objectAPI, accessKey, policyName); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigUsersPrefix, accessKey)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// UpdateGroupMembersHandler - PUT /minio/admin/v1/update-group-members
+// ----------
+// Adds users to or removes users from a group. A group is created by
+// adding its first member and removed with its last member.
+func (a adminAPIHandlers) UpdateGroupMembersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "UpdateGroupMembers")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	var updReq madmin.GroupAddRemove
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxConfigJSONSize)).Decode(&updReq); err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, ErrRequestBodyParse, r.URL)
+		return
+	}
+
+	if err := globalIAMSys.UpdateGroupMembers(objectAPI, updReq); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigGroupsPrefix, updReq.Group)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ListGroupsHandler - GET /minio/admin/v1/list-groups
+// ----------
+// Returns all groups with their policy and members.
+func (a adminAPIHandlers) ListGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	if objectAPI := validateAdminUsersReq(w, r); objectAPI == nil {
+		return
+	}
+
+	writeIAMResponseJSON(w, r, globalIAMSys.ListGroups())
+}
+
+// SetGroupPolicyHandler - PUT /minio/admin/v1/set-group-policy?group=<group>&name=<policy_name>
+// ----------
+// Attaches a canned policy to a group, an empty name detaches the
+// current policy.
+func (a adminAPIHandlers) SetGroupPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "SetGroupPolicy")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	group := r.URL.Query().Get("group")
+	policyName := r.URL.Query().Get("name")
+	if err := globalIAMSys.SetGroupPolicy(objectAPI, group, policyName); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigGroupsPrefix, group)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// AddCannedPolicyHandler - PUT /minio/admin/v1/add-canned-policy?name=<policy_name>
+// ----------
+// Adds or replaces a custom canned policy.
+func (a adminAPIHandlers) AddCannedPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "AddCannedPolicy")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		writeErrorResponseJSON(w, ErrMissingContentLength, r.URL)
+		return
+	}
+
+	// Error out if Content-Length is beyond allowed size.
+	if r.ContentLength > maxBucketPolicySize {
+		writeErrorResponseJSON(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	iamPolicy, err := policy.ParseIdentityConfig(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, ErrMalformedPolicy, r.URL)
+		return
+	}
+
+	policyName := r.URL.Query().Get("name")
+	if err = globalIAMSys.SetPolicy(objectAPI, policyName, *iamPolicy); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigPoliciesPrefix, policyName)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// RemoveCannedPolicyHandler - DELETE /minio/admin/v1/remove-canned-policy?name=<policy_name>
+// ----------
+// Removes a custom canned policy.
+func (a adminAPIHandlers) RemoveCannedPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "RemoveCannedPolicy")
+
+	objectAPI := validateAdminUsersReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Deny if WORM is enabled
+	if globalWORMEnabled {
+		writeErrorResponseJSON(w, ErrMethodNotAllowed, r.URL)
+		return
+	}
+
+	policyName := r.URL.Query().Get("name")
+	if err := globalIAMSys.DeletePolicy(objectAPI, policyName); err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigPoliciesPrefix, policyName)
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// ListCannedPoliciesHandler - GET /minio/admin/v1/list-canned-policies
+// ----------
+// Returns all canned policies, built-in and custom.
+func (a adminAPIHandlers) ListCannedPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	if objectAPI := validateAdminUsersReq(w, r); objectAPI == nil {
+		return
+	}
+
+	writeIAMResponseJSON(w, r, globalIAMSys.ListPolicies())
+}
@@ -714,6 +714,18 @@ func (a adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Validate audit targets, which are created on restart.
+	if err = config.Audit.Validate(); err != nil {
+		writeCustomErrorResponseJSON(w, ErrAdminConfigBadJSON, err.Error(), r.URL)
+		return
+	}
+
+	// Validate replication targets.
+	if err = config.Replication.Validate(); err != nil {
+		writeCustomErrorResponseJSON(w, ErrAdminConfigBadJSON, err.Error(), r.URL)
+		return
+	}
+
 	// If credentials for the server are provided via environment,
 	// then credentials in the provided configuration must match.
 	if globalIsEnvCreds {
@@ -831,3 +843,79 @@ func (a adminAPIHandlers) UpdateCredentialsHandler(w http.ResponseWriter,
 	// At this stage, the operation is successful, return 200 OK
 	w.WriteHeader(http.StatusOK)
 }
+
+// KeyRotationHandler - POST /minio/admin/v1/rotate-keys
+// ----------
+// Starts re-sealing the object keys of all SSE-S3 and SSE-KMS
+// encrypted objects under a new master key. The rotation runs in the
+// background, its progress is returned by KeyRotationStatusHandler.
+func (a adminAPIHandlers) KeyRotationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "KeyRotation")
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponseJSON(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, globalServerConfig.GetRegion())
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return
+	}
+
+	// Decode request body
+	var opts madmin.KeyRotationOpts
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, ErrRequestBodyParse, r.URL)
+		return
+	}
+
+	status, err := startKeyRotation(ctx, objectAPI, opts)
+	if err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	writeSuccessResponseJSON(w, statusJSON)
+}
+
+// KeyRotationStatusHandler - GET /minio/admin/v1/rotate-keys
+// ----------
+// Returns the status of the current or last master key rotation.
+func (a adminAPIHandlers) KeyRotationStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "KeyRotationStatus")
+
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponseJSON(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, globalServerConfig.GetRegion())
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return
+	}
+
+	status, err := readKeyRotationStatus(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	writeSuccessResponseJSON(w, statusJSON)
+}
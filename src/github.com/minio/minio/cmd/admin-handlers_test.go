@@ -181,8 +181,7 @@ func prepareAdminXLTestBed() (*adminXLTestBed, error) {
 		return nil, err
 	}
 
-	// Create new policy system.
-	globalPolicySys = NewPolicySys()
+	resetGlobalBucketSystems()
 
 	// Setup admin mgmt REST API handlers.
 	adminRouter := mux.NewRouter()
@@ -272,7 +271,7 @@ func initTestXLObjLayer() (ObjectLayer, []string, error) {
 		return nil, nil, err
 	}
 
-	globalPolicySys = NewPolicySys()
+	resetGlobalBucketSystems()
 	objLayer, err := newXLSets(endpoints, format, 1, 16)
 	if err != nil {
 		return nil, nil, err
@@ -887,6 +886,153 @@ func TestSetConfigHandler(t *testing.T) {
 	}
 }
 
+// TestAdminUsersHandlers - test for the IAM user admin handlers.
+func TestAdminUsersHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	accessKey, secretKey := "alice", "alice-secret"
+	userInfo, err := json.Marshal(madmin.UserInfo{SecretKey: secretKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		method     string
+		path       string
+		queryVal   url.Values
+		body       []byte
+		statusCode int
+	}{
+		{http.MethodPut, "/add-user", url.Values{"accessKey": {accessKey}}, userInfo, http.StatusOK},
+		{http.MethodPut, "/set-user-policy", url.Values{"accessKey": {accessKey}, "name": {"unknown"}}, nil, http.StatusNotFound},
+		{http.MethodPut, "/set-user-policy", url.Values{"accessKey": {accessKey}, "name": {cannedPolicyReadOnly}}, nil, http.StatusOK},
+		{http.MethodPut, "/set-user-status", url.Values{"accessKey": {accessKey}, "status": {"unknown"}}, nil, http.StatusBadRequest},
+		{http.MethodPut, "/set-user-status", url.Values{"accessKey": {"bob"}, "status": {string(madmin.AccountDisabled)}}, nil, http.StatusNotFound},
+		{http.MethodPut, "/add-canned-policy", url.Values{"name": {cannedPolicyReadWrite}}, []byte(`{"Version":"2012-10-17","Statement":[]}`), http.StatusBadRequest},
+		{http.MethodPut, "/add-canned-policy", url.Values{"name": {"read-photos"}}, []byte(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::photos/*"]}]}`), http.StatusBadRequest},
+		{http.MethodPut, "/add-canned-policy", url.Values{"name": {"read-photos"}}, []byte(`{"Version":"2012-10-17","Statement":[{"Sid":"ReadPhotos","Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::photos/*"]}]}`), http.StatusOK},
+		{http.MethodPut, "/set-user-policy", url.Values{"accessKey": {accessKey}, "name": {"read-photos"}}, nil, http.StatusOK},
+		{http.MethodPut, "/set-user-policy", url.Values{"accessKey": {accessKey}, "name": {cannedPolicyReadOnly}}, nil, http.StatusOK},
+		{http.MethodGet, "/list-users", url.Values{}, nil, http.StatusOK},
+	}
+
+	for i, testCase := range testCases {
+		req, err := buildAdminRequest(testCase.queryVal, testCase.method, testCase.path,
+			int64(len(testCase.body)), bytes.NewReader(testCase.body))
+		if err != nil {
+			t.Fatalf("Test %d: failed to construct request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.router.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("Test %d: expected status %d, got %d - %s", i+1, testCase.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	users, ok := globalIAMSys.ListUsers()[accessKey]
+	if !ok || users.PolicyName != cannedPolicyReadOnly || users.Status != madmin.AccountEnabled {
+		t.Fatalf("Unexpected user info %v", users)
+	}
+
+	// Users are not allowed to call admin APIs.
+	req, err := newTestRequest(http.MethodGet, "/minio/admin/v1/list-users", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = signRequestV4(req, accessKey, secretKey); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d for user request, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	req, err = buildAdminRequest(url.Values{"accessKey": {accessKey}}, http.MethodDelete, "/remove-user", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	adminTestBed.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected to succeed but failed with %d", rec.Code)
+	}
+	if _, ok = globalIAMSys.GetUser(accessKey); ok {
+		t.Fatal("Expected user to be removed")
+	}
+}
+
+// TestAdminBucketQuotaHandlers - test for the bucket quota admin handlers.
+func TestAdminBucketQuotaHandlers(t *testing.T) {
+	adminTestBed, err := prepareAdminXLTestBed()
+	if err != nil {
+		t.Fatal("Failed to initialize a single node XL backend for admin handler tests.")
+	}
+	defer adminTestBed.TearDown()
+
+	bucket := "quota-bucket"
+	if err = adminTestBed.objLayer.MakeBucketWithLocation(context.Background(), bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	quota, err := json.Marshal(madmin.BucketQuota{Quota: 1 << 20, Type: madmin.HardQuota})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		method     string
+		path       string
+		queryVal   url.Values
+		body       []byte
+		statusCode int
+	}{
+		{http.MethodGet, "/get-bucket-quota", url.Values{"bucket": {bucket}}, nil, http.StatusNotFound},
+		{http.MethodPut, "/set-bucket-quota", url.Values{"bucket": {"unknown-bucket"}}, quota, http.StatusNotFound},
+		{http.MethodPut, "/set-bucket-quota", url.Values{"bucket": {bucket}}, []byte(`{"quota":0,"quotatype":"hard"}`), http.StatusBadRequest},
+		{http.MethodPut, "/set-bucket-quota", url.Values{"bucket": {bucket}}, []byte(`{"quota":1024,"quotatype":"soft"}`), http.StatusBadRequest},
+		{http.MethodPut, "/set-bucket-quota", url.Values{"bucket": {bucket}}, quota, http.StatusOK},
+		{http.MethodGet, "/get-bucket-quota", url.Values{"bucket": {bucket}}, nil, http.StatusOK},
+		{http.MethodGet, "/bucket-usage", url.Values{"bucket": {bucket}}, nil, http.StatusOK},
+	}
+
+	for i, testCase := range testCases {
+		req, err := buildAdminRequest(testCase.queryVal, testCase.method, testCase.path,
+			int64(len(testCase.body)), bytes.NewReader(testCase.body))
+		if err != nil {
+			t.Fatalf("Test %d: failed to construct request - %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		adminTestBed.router.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Errorf("Test %d: expected status %d, got %d - %s", i+1, testCase.statusCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	if q := globalBucketMetadataSys.GetQuota(bucket); q == nil || q.Quota != 1<<20 || q.Type != madmin.HardQuota {
+		t.Fatalf("Unexpected bucket quota %v", q)
+	}
+
+	req, err := buildAdminRequest(url.Values{"bucket": {bucket}}, http.MethodDelete, "/remove-bucket-quota", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	adminTestBed.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected to succeed but failed with %d", rec.Code)
+	}
+	if globalBucketMetadataSys.GetQuota(bucket) != nil {
+		t.Fatal("Expected bucket quota to be removed")
+	}
+}
+
 func TestAdminServerInfo(t *testing.T) {
 	adminTestBed, err := prepareAdminXLTestBed()
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/madmin"
+	"go.uber.org/atomic"
 )
 
 // healStatusSummary - overall short summary of a healing sequence
@@ -88,6 +89,51 @@ type healSequenceStatus struct {
 	Items []madmin.HealResultItem `json:"Items"`
 }
 
+// HealStats - statistics of items scanned by heal sequences during
+// the server's life.
+type HealStats struct {
+	// 64-bit counters are kept first to be 64-bit aligned on 32-bit
+	// platforms, other fields must be added after them.
+	metadata atomic.Uint64
+	buckets  atomic.Uint64
+	objects  atomic.Uint64
+	failures atomic.Uint64
+}
+
+// Increase scanned items according to the type of the heal result.
+func (s *HealStats) incHealResultItem(r madmin.HealResultItem) {
+	switch r.Type {
+	case madmin.HealItemMetadata:
+		s.metadata.Inc()
+	case madmin.HealItemBucket, madmin.HealItemBucketMetadata:
+		s.buckets.Inc()
+	case madmin.HealItemObject:
+		s.objects.Inc()
+	}
+	if r.Detail != "" {
+		s.failures.Inc()
+	}
+}
+
+// Return total scanned items by type.
+func (s *HealStats) getScannedItems() map[madmin.HealItemType]uint64 {
+	return map[madmin.HealItemType]uint64{
+		madmin.HealItemMetadata: s.metadata.Load(),
+		madmin.HealItemBucket:   s.buckets.Load(),
+		madmin.HealItemObject:   s.objects.Load(),
+	}
+}
+
+// Return total items which failed to heal.
+func (s *HealStats) getFailures() uint64 {
+	return s.failures.Load()
+}
+
+// Prepare new HealStats structure.
+func newHealStats() *HealStats {
+	return &HealStats{}
+}
+
 // structure to hold state of all heal sequences in server memory
 type allHealState struct {
 	sync.Mutex
@@ -121,6 +167,19 @@ func (ahs *allHealState) getHealSequence(path string) (h *healSequence, exists b
 	return h, exists
 }
 
+// runningHealSequences - returns the number of heal sequences which
+// have not ended yet.
+func (ahs *allHealState) runningHealSequences() (count int) {
+	ahs.Lock()
+	defer ahs.Unlock()
+	for _, h := range ahs.healSeqMap {
+		if !h.hasEnded() {
+			count++
+		}
+	}
+	return count
+}
+
 // LaunchNewHealSequence - launches a background routine that performs
 // healing according to the healSequence argument. For each heal
 // sequence, state is stored in the `globalAllHealState`, which is a
@@ -436,6 +495,7 @@ func (h *healSequence) pushHealResultItem(r madmin.HealResultItem) error {
 
 	// append to results
 	h.currentStatus.Items = append(h.currentStatus.Items, r)
+	globalHealStats.incHealResultItem(r)
 
 	// release lock
 	h.currentStatus.updateLock.Unlock()
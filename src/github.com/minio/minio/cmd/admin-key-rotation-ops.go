@@ -0,0 +1,355 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/kms"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Maximum number of objects listed at once during a key rotation.
+	keyRotationMaxKeys = 1000
+
+	// Key rotation status file under minioMetaBucket, shared by all
+	// nodes so that an interrupted rotation is resumed by any of them.
+	keyRotationStatusFile = "key-rotation.json"
+)
+
+var (
+	// Timeout to take the key rotation lock, failing to take it means
+	// another node is running the rotation.
+	keyRotationTimeout = newDynamicTimeout(60*time.Second, time.Second)
+
+	// Timeout to take the key rotation lock when starting a new
+	// rotation from an admin request.
+	keyRotationStartTimeout = newDynamicTimeout(5*time.Second, time.Second)
+
+	errKeyRotationAlreadyRunning = errors.New("A master key rotation is already in progress")
+)
+
+// initKeyRotation - resumes a master key rotation interrupted by a
+// server restart.
+func initKeyRotation() {
+	go resumeKeyRotation(globalServiceDoneCh)
+}
+
+func resumeKeyRotation(doneCh chan struct{}) {
+	ctx := context.Background()
+
+	var objAPI ObjectLayer
+	// Wait until the object layer is initialized.
+	for {
+		if objAPI = newObjectLayerFn(); objAPI != nil {
+			break
+		}
+		select {
+		case <-doneCh:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	status, err := readKeyRotationStatus(ctx, objAPI)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	if status.Summary == madmin.KeyRotationRunning {
+		runKeyRotation(ctx, objAPI)
+	}
+}
+
+// readKeyRotationStatus - reads status of the current or last key
+// rotation.
+func readKeyRotationStatus(ctx context.Context, objAPI ObjectLayer) (status madmin.KeyRotationStatus, err error) {
+	reader, err := readConfig(ctx, objAPI, keyRotationStatusFile)
+	if err != nil {
+		if IsErrIgnored(err, errConfigNotFound, errNoSuchNotifications) {
+			err = nil
+		}
+		return status, err
+	}
+
+	err = json.NewDecoder(reader).Decode(&status)
+	return status, err
+}
+
+// saveKeyRotationStatus - saves status of the current key rotation.
+func saveKeyRotationStatus(objAPI ObjectLayer, status madmin.KeyRotationStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(objAPI, keyRotationStatusFile, data)
+}
+
+// startKeyRotation - validates the options and starts re-sealing the
+// object keys of all SSE-S3 and SSE-KMS encrypted objects in the
+// background.
+func startKeyRotation(ctx context.Context, objAPI ObjectLayer, opts madmin.KeyRotationOpts) (status madmin.KeyRotationStatus, err error) {
+	if globalKMS == nil {
+		return status, errKMSNotConfigured
+	}
+	if opts.NewKeyID == "" {
+		opts.NewKeyID = globalKMSKeyID
+	}
+	// Verify that the new master key exists.
+	if _, _, err = globalKMS.GenerateKey(opts.NewKeyID, kms.Context{}); err != nil {
+		return status, err
+	}
+
+	// A running rotation holds the lock, so fail early if its status
+	// says so.
+	if status, err = readKeyRotationStatus(ctx, objAPI); err != nil {
+		return status, err
+	}
+	if status.Summary == madmin.KeyRotationRunning {
+		return status, errKeyRotationAlreadyRunning
+	}
+
+	// Lock to avoid concurrent starts from other nodes.
+	rotationLock := globalNSMutex.NewNSLock(minioMetaBucket, keyRotationStatusFile+".lock")
+	if err = rotationLock.GetLock(keyRotationStartTimeout); err != nil {
+		if _, ok := err.(OperationTimedOut); ok {
+			err = errKeyRotationAlreadyRunning
+		}
+		return status, err
+	}
+	defer rotationLock.Unlock()
+
+	if status, err = readKeyRotationStatus(ctx, objAPI); err != nil {
+		return status, err
+	}
+	if status.Summary == madmin.KeyRotationRunning {
+		return status, errKeyRotationAlreadyRunning
+	}
+
+	now := UTCNow()
+	status = madmin.KeyRotationStatus{
+		Summary:    madmin.KeyRotationRunning,
+		StartTime:  now,
+		LastUpdate: now,
+		Settings:   opts,
+	}
+	if err = saveKeyRotationStatus(objAPI, status); err != nil {
+		return status, err
+	}
+
+	go runKeyRotation(context.Background(), objAPI)
+	return status, nil
+}
+
+// runKeyRotation - runs the key rotation recorded in the status file,
+// continuing from the last processed object.
+func runKeyRotation(ctx context.Context, objAPI ObjectLayer) {
+	if err := keyRotationRound(ctx, objAPI); err != nil {
+		// Unable to hold the lock means another node is running
+		// the key rotation.
+		if _, ok := err.(OperationTimedOut); !ok {
+			logger.LogIf(ctx, err)
+		}
+	}
+}
+
+func keyRotationRound(ctx context.Context, objAPI ObjectLayer) error {
+	// Lock to avoid concurrent key rotations from other nodes.
+	rotationLock := globalNSMutex.NewNSLock(minioMetaBucket, keyRotationStatusFile+".lock")
+	if err := rotationLock.GetLock(keyRotationTimeout); err != nil {
+		return err
+	}
+	defer rotationLock.Unlock()
+
+	status, err := readKeyRotationStatus(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	if status.Summary != madmin.KeyRotationRunning {
+		return nil
+	}
+
+	if err = rotateAllKeys(ctx, objAPI, &status); err != nil {
+		status.Summary = madmin.KeyRotationFailed
+		status.FailureDetail = err.Error()
+	} else {
+		status.Summary = madmin.KeyRotationFinished
+	}
+	status.LastUpdate = UTCNow()
+	return saveKeyRotationStatus(objAPI, status)
+}
+
+// rotateAllKeys - rotates object keys of all buckets in lexical
+// order, skipping buckets before status.Bucket.
+func rotateAllKeys(ctx context.Context, objAPI ObjectLayer, status *madmin.KeyRotationStatus) error {
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Name < buckets[j].Name })
+
+	for _, bucket := range buckets {
+		if bucket.Name < status.Bucket {
+			continue
+		}
+		if bucket.Name != status.Bucket {
+			status.Bucket, status.Object, status.VersionID = bucket.Name, "", ""
+		}
+
+		if getBucketVersioningStatus(bucket.Name) != "" {
+			err = rotateBucketVersionKeys(ctx, objAPI, status)
+		} else {
+			err = rotateBucketKeys(ctx, objAPI, status)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rotateBucketKeys - rotates object keys of status.Bucket after
+// status.Object, saving the status after every listed page.
+func rotateBucketKeys(ctx context.Context, objAPI ObjectLayer, status *madmin.KeyRotationStatus) error {
+	bucket := status.Bucket
+	for {
+		result, err := objAPI.ListObjects(ctx, bucket, "", status.Object, "", keyRotationMaxKeys)
+		if err != nil {
+			return err
+		}
+
+		for _, objInfo := range result.Objects {
+			rotateObjectVersionKey(ctx, objAPI, bucket, objInfo.Name, "", status)
+			status.Object = objInfo.Name
+		}
+
+		status.LastUpdate = UTCNow()
+		if err = saveKeyRotationStatus(objAPI, *status); err != nil {
+			return err
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+	}
+}
+
+// rotateBucketVersionKeys - rotates object keys of all versions in the
+// versioned status.Bucket after status.Object and status.VersionID,
+// saving the status after every listed page.
+func rotateBucketVersionKeys(ctx context.Context, objAPI ObjectLayer, status *madmin.KeyRotationStatus) error {
+	bucket := status.Bucket
+	for {
+		result, err := objAPI.ListObjectVersions(ctx, bucket, "", status.Object, status.VersionID, "", keyRotationMaxKeys)
+		if err != nil {
+			return err
+		}
+
+		for _, objInfo := range result.Objects {
+			// Delete markers have no object key.
+			if !objInfo.DeleteMarker {
+				rotateObjectVersionKey(ctx, objAPI, bucket, objInfo.Name, objInfo.VersionID, status)
+			}
+			status.Object, status.VersionID = objInfo.Name, objInfo.VersionID
+		}
+
+		status.LastUpdate = UTCNow()
+		if err = saveKeyRotationStatus(objAPI, *status); err != nil {
+			return err
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+	}
+}
+
+// rotateObjectVersionKey - rotates the object key of given version of
+// the object, the latest version if versionID is empty, and records
+// the outcome in status.
+func rotateObjectVersionKey(ctx context.Context, objAPI ObjectLayer, bucket, object, versionID string, status *madmin.KeyRotationStatus) {
+	status.ObjectsScanned++
+	rotated, err := rotateObjectKey(ctx, objAPI, bucket, object, versionID, status.Settings)
+	if err != nil {
+		reqInfo := &logger.ReqInfo{BucketName: bucket, ObjectName: object}
+		reqInfo.AppendTags("versionId", versionID)
+		logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+		status.ObjectsFailed++
+	} else if rotated {
+		status.ObjectsRotated++
+	}
+}
+
+// rotateObjectKey - re-seals the object key of an SSE-S3 or SSE-KMS
+// encrypted object version by a data key of the new master key,
+// updating only the metadata of the version. Versions overwritten or
+// removed meanwhile are not rotated.
+func rotateObjectKey(ctx context.Context, objAPI ObjectLayer, bucket, object, versionID string, opts madmin.KeyRotationOpts) (bool, error) {
+	var objInfo ObjectInfo
+	var err error
+	if versionID == "" {
+		objInfo, err = objAPI.GetObjectInfo(ctx, bucket, object)
+	} else {
+		objInfo, err = objAPI.GetObjectVersionInfo(ctx, bucket, object, versionID)
+	}
+	if err != nil {
+		if isKeyRotationSkipErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !isKMSEncrypted(objInfo.UserDefined) {
+		return false, nil
+	}
+	keyID := kmsKeyID(objInfo.UserDefined)
+	if keyID == opts.NewKeyID || (opts.OldKeyID != "" && keyID != opts.OldKeyID) {
+		return false, nil
+	}
+
+	if err = rotateKMSKey(opts.NewKeyID, bucket, object, objInfo.UserDefined); err != nil {
+		return false, err
+	}
+
+	// Update the metadata only if the version was not overwritten
+	// since it was read.
+	if err = objAPI.PutObjectMetadata(ctx, bucket, object, versionID, objInfo.ETag, objInfo.UserDefined); err != nil {
+		if isKeyRotationSkipErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// isKeyRotationSkipErr - returns true if the error means the object
+// version was overwritten or removed during the key rotation.
+func isKeyRotationSkipErr(err error) bool {
+	switch err.(type) {
+	case InvalidETag, ObjectNotFound, VersionNotFound, ObjectVersionDeleteMarker:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/pkg/kms"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/versioning"
+)
+
+// Tests master key rotation of SSE-S3 and SSE-KMS objects on FS.
+func TestKeyRotation(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	initNSLock(false)
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+	defer resetGlobalBucketSystems()
+
+	defer func(KMS kms.KMS, keyID string) { globalKMS, globalKMSKeyID = KMS, keyID }(globalKMS, globalKMSKeyID)
+	oldKey, newKey := kms.MasterKey{ID: "old-key"}, kms.MasterKey{ID: "new-key"}
+	copy(oldKey.Key[:], bytes.Repeat([]byte{1}, 32))
+	copy(newKey.Key[:], bytes.Repeat([]byte{2}, 32))
+	globalKMS, globalKMSKeyID = kms.NewLocal(oldKey, newKey), "old-key"
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := bytes.Repeat([]byte("a"), 100)
+	putObject := func(object, algorithm string) {
+		req := &http.Request{Header: http.Header{}}
+		if algorithm != "" {
+			req.Header.Set(SSEHeader, algorithm)
+		}
+		metadata := map[string]string{}
+		reader := io.Reader(bytes.NewReader(plaintext))
+		if algorithm != "" {
+			if reader, err = EncryptRequest(reader, req, bucket, object, metadata); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var data bytes.Buffer
+		if _, err = io.Copy(&data, reader); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetHashReader(t, &data, int64(data.Len()), "", ""), metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+	putObject("a-sse-s3", SSECustomerAlgorithmAES256)
+	putObject("b-plain", "")
+	putObject("c-sse-kms", SSEAlgorithmKMS)
+	putObject("d-sse-s3", SSECustomerAlgorithmAES256)
+
+	if _, err = startKeyRotation(ctx, obj, madmin.KeyRotationOpts{NewKeyID: "unknown-key"}); err != kms.ErrKeyNotFound {
+		t.Fatalf("Expected %v, got %v", kms.ErrKeyNotFound, err)
+	}
+
+	// Simulate a rotation interrupted after the first object.
+	status := madmin.KeyRotationStatus{
+		Summary:  madmin.KeyRotationRunning,
+		Settings: madmin.KeyRotationOpts{NewKeyID: "new-key"},
+		Bucket:   bucket,
+		Object:   "a-sse-s3",
+	}
+	if err = saveKeyRotationStatus(obj, status); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = startKeyRotation(ctx, obj, madmin.KeyRotationOpts{}); err != errKeyRotationAlreadyRunning {
+		t.Fatalf("Expected %v, got %v", errKeyRotationAlreadyRunning, err)
+	}
+
+	if err = keyRotationRound(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	if status, err = readKeyRotationStatus(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	if status.Summary != madmin.KeyRotationFinished {
+		t.Fatalf("Expected rotation to be finished, got %q: %s", status.Summary, status.FailureDetail)
+	}
+	if status.ObjectsScanned != 3 || status.ObjectsRotated != 2 || status.ObjectsFailed != 0 {
+		t.Fatalf("Unexpected rotation progress %+v", status)
+	}
+
+	for object, keyID := range map[string]string{"a-sse-s3": "old-key", "c-sse-kms": "new-key", "d-sse-s3": "new-key"} {
+		objInfo, err := obj.GetObjectInfo(ctx, bucket, object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := kmsKeyID(objInfo.UserDefined); id != keyID {
+			t.Fatalf("%s: Expected master key id %q, got %q", object, keyID, id)
+		}
+
+		var data, client bytes.Buffer
+		if err = obj.GetObject(ctx, bucket, object, 0, objInfo.Size, &data, objInfo.ETag); err != nil {
+			t.Fatal(err)
+		}
+		writer, err := DecryptRequest(&client, &http.Request{Header: http.Header{}}, objInfo.UserDefined)
+		if err != nil {
+			t.Fatalf("%s: Failed to decrypt object: %v", object, err)
+		}
+		if _, err = writer.Write(data.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(client.Bytes(), plaintext) {
+			t.Fatalf("%s: Decrypted content does not match the original content", object)
+		}
+	}
+}
+
+// Tests master key rotation of all versions in a versioned bucket on XL.
+func TestKeyRotationVersioned(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+	defer resetGlobalBucketSystems()
+
+	defer func(KMS kms.KMS, keyID string) { globalKMS, globalKMSKeyID = KMS, keyID }(globalKMS, globalKMSKeyID)
+	oldKey, newKey := kms.MasterKey{ID: "old-key"}, kms.MasterKey{ID: "new-key"}
+	copy(oldKey.Key[:], bytes.Repeat([]byte{1}, 32))
+	copy(newKey.Key[:], bytes.Repeat([]byte{2}, 32))
+	globalKMS, globalKMSKeyID = kms.NewLocal(oldKey, newKey), "old-key"
+
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	ctx := context.Background()
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	config := versioning.Versioning{Status: versioning.Enabled}
+	if err = obj.SetBucketVersioning(ctx, bucket, &config); err != nil {
+		t.Fatal(err)
+	}
+	globalBucketMetadataSys.Set(bucket, bucketVersioningConfig, &config)
+
+	var versionIDs []string
+	for i := 0; i < 2; i++ {
+		req := &http.Request{Header: http.Header{}}
+		req.Header.Set(SSEHeader, SSECustomerAlgorithmAES256)
+		metadata := map[string]string{}
+		reader, err := EncryptRequest(bytes.NewReader([]byte("abc")), req, bucket, object, metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data bytes.Buffer
+		if _, err = io.Copy(&data, reader); err != nil {
+			t.Fatal(err)
+		}
+		objInfo, err := obj.PutObject(ctx, bucket, object, mustGetHashReader(t, &data, int64(data.Len()), "", ""), metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versionIDs = append(versionIDs, objInfo.VersionID)
+	}
+	// The delete marker becomes the latest version.
+	if err = obj.DeleteObject(ctx, bucket, object); err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata of a version is not updated once it was overwritten.
+	if err = obj.PutObjectMetadata(ctx, bucket, object, versionIDs[0], "etag", map[string]string{"x": "y"}); err != (InvalidETag{}) {
+		t.Fatalf("Expected %v, got %v", InvalidETag{}, err)
+	}
+
+	status := madmin.KeyRotationStatus{
+		Summary:  madmin.KeyRotationRunning,
+		Settings: madmin.KeyRotationOpts{NewKeyID: "new-key"},
+	}
+	if err = saveKeyRotationStatus(obj, status); err != nil {
+		t.Fatal(err)
+	}
+	if err = keyRotationRound(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	if status, err = readKeyRotationStatus(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	if status.Summary != madmin.KeyRotationFinished {
+		t.Fatalf("Expected rotation to be finished, got %q: %s", status.Summary, status.FailureDetail)
+	}
+	if status.ObjectsScanned != 2 || status.ObjectsRotated != 2 || status.ObjectsFailed != 0 {
+		t.Fatalf("Unexpected rotation progress %+v", status)
+	}
+
+	for _, versionID := range versionIDs {
+		objInfo, err := obj.GetObjectVersionInfo(ctx, bucket, object, versionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := kmsKeyID(objInfo.UserDefined); id != "new-key" {
+			t.Fatalf("%s: Expected master key id %q, got %q", versionID, "new-key", id)
+		}
+		if _, err = unsealKMSKey(objInfo.UserDefined); err != nil {
+			t.Fatalf("%s: Failed to unseal object key: %v", versionID, err)
+		}
+	}
+}
@@ -67,6 +67,49 @@ func registerAdminRouter(router *mux.Router) {
 	adminV1Router.Methods(http.MethodPost).Path("/heal/{bucket}").HandlerFunc(adminAPI.HealHandler)
 	adminV1Router.Methods(http.MethodPost).Path("/heal/{bucket}/{prefix:.*}").HandlerFunc(adminAPI.HealHandler)
 
+	/// Key rotation operations
+
+	// Start master key rotation
+	adminV1Router.Methods(http.MethodPost).Path("/rotate-keys").HandlerFunc(adminAPI.KeyRotationHandler)
+	// Get master key rotation status
+	adminV1Router.Methods(http.MethodGet).Path("/rotate-keys").HandlerFunc(adminAPI.KeyRotationStatusHandler)
+
+	/// IAM operations
+
+	// Add or update user
+	adminV1Router.Methods(http.MethodPut).Path("/add-user").HandlerFunc(adminAPI.AddUserHandler).Queries("accessKey", "{accessKey:.*}")
+	// Remove user
+	adminV1Router.Methods(http.MethodDelete).Path("/remove-user").HandlerFunc(adminAPI.RemoveUserHandler).Queries("accessKey", "{accessKey:.*}")
+	// List users
+	adminV1Router.Methods(http.MethodGet).Path("/list-users").HandlerFunc(adminAPI.ListUsersHandler)
+	// Enable or disable user
+	adminV1Router.Methods(http.MethodPut).Path("/set-user-status").HandlerFunc(adminAPI.SetUserStatusHandler).Queries("accessKey", "{accessKey:.*}", "status", "{status:.*}")
+	// Attach canned policy to user
+	adminV1Router.Methods(http.MethodPut).Path("/set-user-policy").HandlerFunc(adminAPI.SetUserPolicyHandler).Queries("accessKey", "{accessKey:.*}", "name", "{name:.*}")
+	// Add or remove group members
+	adminV1Router.Methods(http.MethodPut).Path("/update-group-members").HandlerFunc(adminAPI.UpdateGroupMembersHandler)
+	// List groups
+	adminV1Router.Methods(http.MethodGet).Path("/list-groups").HandlerFunc(adminAPI.ListGroupsHandler)
+	// Attach canned policy to group
+	adminV1Router.Methods(http.MethodPut).Path("/set-group-policy").HandlerFunc(adminAPI.SetGroupPolicyHandler).Queries("group", "{group:.*}", "name", "{name:.*}")
+	// Add canned policy
+	adminV1Router.Methods(http.MethodPut).Path("/add-canned-policy").HandlerFunc(adminAPI.AddCannedPolicyHandler).Queries("name", "{name:.*}")
+	// Remove canned policy
+	adminV1Router.Methods(http.MethodDelete).Path("/remove-canned-policy").HandlerFunc(adminAPI.RemoveCannedPolicyHandler).Queries("name", "{name:.*}")
+	// List canned policies
+	adminV1Router.Methods(http.MethodGet).Path("/list-canned-policies").HandlerFunc(adminAPI.ListCannedPoliciesHandler)
+
+	/// Bucket quota operations
+
+	// Set bucket quota
+	adminV1Router.Methods(http.MethodPut).Path("/set-bucket-quota").HandlerFunc(adminAPI.SetBucketQuotaHandler).Queries("bucket", "{bucket:.*}")
+	// Get bucket quota
+	adminV1Router.Methods(http.MethodGet).Path("/get-bucket-quota").HandlerFunc(adminAPI.GetBucketQuotaHandler).Queries("bucket", "{bucket:.*}")
+	// Remove bucket quota
+	adminV1Router.Methods(http.MethodDelete).Path("/remove-bucket-quota").HandlerFunc(adminAPI.RemoveBucketQuotaHandler).Queries("bucket", "{bucket:.*}")
+	// Get bucket usage
+	adminV1Router.Methods(http.MethodGet).Path("/bucket-usage").HandlerFunc(adminAPI.GetBucketUsageHandler).Queries("bucket", "{bucket:.*}")
+
 	/// Config operations
 
 	// Update credentials
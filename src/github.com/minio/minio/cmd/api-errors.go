@@ -21,9 +21,15 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/kms"
+	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/s3select"
+	"github.com/minio/minio/pkg/s3select/sql"
+	"github.com/minio/minio/pkg/tagging"
 )
 
 // APIError structure
@@ -79,8 +85,31 @@ const (
 	ErrMissingRequestBodyError
 	ErrNoSuchBucket
 	ErrNoSuchBucketPolicy
+	ErrNoSuchLifecycleConfiguration
+	ErrNoSuchBucketEncryptionConfiguration
+	ErrNoSuchCORSConfiguration
+	ErrCORSForbidden
+	ErrReplicationConfigurationNotFoundError
+	ErrInvalidReplicationDestination
+	ErrNoSuchWebsiteConfiguration
+	ErrInvalidRedirectLocation
+	ErrInvalidTargetBucketForLogging
 	ErrNoSuchKey
 	ErrNoSuchUpload
+	ErrNoSuchVersion
+	ErrInvalidVersionID
+	ErrNoSuchTagSet
+	ErrInvalidTag
+	ErrInvalidTaggingDirective
+	ErrInvalidExpressionType
+	ErrInvalidCompressionFormat
+	ErrInvalidFileHeaderInfo
+	ErrInvalidJSONType
+	ErrInvalidQuoteFields
+	ErrInvalidRequestParameter
+	ErrMissingRequiredParameter
+	ErrObjectSerializationConflict
+	ErrParseUnsupportedSyntax
 	ErrNotImplemented
 	ErrPreconditionFailed
 	ErrRequestTimeTooSkewed
@@ -139,6 +168,13 @@ const (
 	ErrSSECustomerKeyMD5Mismatch
 	ErrInvalidSSECustomerParameters
 
+	// Server-Side-Encryption (with server managed key) related API errors.
+	ErrInvalidEncryptionMethod
+	ErrIncompatibleEncryptionMethod
+	ErrKMSNotConfigured
+	ErrKMSKeyNotFound
+	ErrInvalidKMSKeyIDRequest
+
 	// Bucket notification related errors.
 	ErrEventNotification
 	ErrARNNotification
@@ -153,6 +189,18 @@ const (
 
 	// Lifecycle errors.
 	ErrMalformedLifecycle
+	ErrLifecycleInvalidArgument
+
+	// ACL errors.
+	ErrMalformedACLError
+	ErrACLInvalidArgument
+	ErrACLInvalidRequest
+	ErrUnexpectedContent
+
+	// Restore errors.
+	ErrRestoreAlreadyInProgress
+	ErrObjectNotArchived
+	ErrColdTierNotConfigured
 
 	// S3 extended errors.
 	ErrContentSHA256Mismatch
@@ -186,6 +234,14 @@ const (
 	ErrAdminConfigTooLarge
 	ErrAdminConfigBadJSON
 	ErrAdminCredentialsMismatch
+	ErrAdminNoSuchUser
+	ErrAdminNoSuchGroup
+	ErrAdminNoSuchPolicy
+	ErrAdminCannedPolicyReserved
+	ErrAdminNoSuchQuotaConfiguration
+	ErrAdminInvalidBucketQuota
+	ErrAdminActionNotAllowed
+	ErrQuotaExceeded
 	ErrInsecureClientRequest
 	ErrObjectTampered
 	ErrHealNotImplemented
@@ -194,6 +250,17 @@ const (
 	ErrHealMissingBucket
 	ErrHealAlreadyRunning
 	ErrHealOverlappingPaths
+	ErrKeyRotationAlreadyRunning
+
+	// Temporary credentials and STS errors.
+	ErrInvalidToken
+	ErrSTSInvalidAction
+	ErrSTSMissingParameter
+	ErrSTSInvalidParameterValue
+	ErrSTSMalformedPolicyDocument
+	ErrSTSExpiredToken
+	ErrSTSInvalidIdentityToken
+	ErrSTSNotConfigured
 )
 
 // error code to APIError structure, these fields carry respective
@@ -329,6 +396,51 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The bucket policy does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchLifecycleConfiguration: {
+		Code:           "NoSuchLifecycleConfiguration",
+		Description:    "The lifecycle configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrNoSuchBucketEncryptionConfiguration: {
+		Code:           "ServerSideEncryptionConfigurationNotFoundError",
+		Description:    "The server side encryption configuration was not found",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrNoSuchCORSConfiguration: {
+		Code:           "NoSuchCORSConfiguration",
+		Description:    "The CORS configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrNoSuchWebsiteConfiguration: {
+		Code:           "NoSuchWebsiteConfiguration",
+		Description:    "The specified bucket does not have a website configuration",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidRedirectLocation: {
+		Code:           "InvalidRedirectLocation",
+		Description:    "The website redirect location must have a prefix of 'http://' or 'https://' or '/'.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTargetBucketForLogging: {
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrReplicationConfigurationNotFoundError: {
+		Code:           "ReplicationConfigurationNotFoundError",
+		Description:    "The replication configuration was not found",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidReplicationDestination: {
+		Code:           "InvalidArgument",
+		Description:    "The replication destination is not a configured replication target",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrCORSForbidden: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: This CORS request is not allowed. This is usually because the evaluation of Origin, request method / Access-Control-Request-Method or Access-Control-Request-Headers are not whitelisted by the resource's CORS spec.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrNoSuchKey: {
 		Code:           "NoSuchKey",
 		Description:    "The specified key does not exist.",
@@ -339,6 +451,76 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The specified multipart upload does not exist. The upload ID may be invalid, or the upload may have been aborted or completed.",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrNoSuchVersion: {
+		Code:           "NoSuchVersion",
+		Description:    "The specified version does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidVersionID: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid version id specified",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchTagSet: {
+		Code:           "NoSuchTagSet",
+		Description:    "The TagSet does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidTag: {
+		Code:           "InvalidTag",
+		Description:    "The tag provided was not a valid tag.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTaggingDirective: {
+		Code:           "InvalidArgument",
+		Description:    "Unknown tagging directive.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidExpressionType: {
+		Code:           "InvalidExpressionType",
+		Description:    "The ExpressionType is invalid. Only SQL expressions are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCompressionFormat: {
+		Code:           "InvalidCompressionFormat",
+		Description:    "The file is not in a supported compression format. Only GZIP and BZIP2 are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidFileHeaderInfo: {
+		Code:           "InvalidFileHeaderInfo",
+		Description:    "The FileHeaderInfo is invalid. Only NONE, USE, and IGNORE are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidJSONType: {
+		Code:           "InvalidJsonType",
+		Description:    "The JsonType is invalid. Only DOCUMENT and LINES are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidQuoteFields: {
+		Code:           "InvalidQuoteFields",
+		Description:    "The QuoteFields is invalid. Only ALWAYS and ASNEEDED are supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidRequestParameter: {
+		Code:           "InvalidRequestParameter",
+		Description:    "The value of a parameter in SelectRequest element is invalid. Check the service API documentation and try again.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMissingRequiredParameter: {
+		Code:           "MissingRequiredParameter",
+		Description:    "The SelectRequest entity is missing a required parameter. Check the service documentation and try again.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrObjectSerializationConflict: {
+		Code:           "ObjectSerializationConflict",
+		Description:    "The SelectRequest entity can only contain one of CSV or JSON. Check the service documentation and try again.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrParseUnsupportedSyntax: {
+		Code:           "ParseUnsupportedSyntax",
+		Description:    "The SQL expression contains unsupported syntax.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrNotImplemented: {
 		Code:           "NotImplemented",
 		Description:    "A header you provided implies functionality that is not implemented",
@@ -680,6 +862,31 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "The provided encryption parameters did not match the ones used originally.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidEncryptionMethod: {
+		Code:           "InvalidArgument",
+		Description:    "The encryption method specified is not supported.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrIncompatibleEncryptionMethod: {
+		Code:           "InvalidArgument",
+		Description:    "Server Side Encryption with Customer provided key is incompatible with the encryption method specified.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrKMSNotConfigured: {
+		Code:           "InvalidArgument",
+		Description:    "Server side encryption specified but KMS is not configured.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrKMSKeyNotFound: {
+		Code:           "KMS.NotFoundException",
+		Description:    "The master key used to seal the object encryption key is not available.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidKMSKeyIDRequest: {
+		Code:           "InvalidArgument",
+		Description:    "Server Side Encryption with AWS KMS managed key requires HTTP header x-amz-server-side-encryption : aws:kms.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	/// Lifecycle errors.
 	ErrMalformedLifecycle: {
@@ -687,6 +894,48 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Lifecycle has invalid rules.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrLifecycleInvalidArgument: {
+		Code:           "InvalidArgument",
+		Description:    "Lifecycle configuration has an invalid argument.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+
+	/// ACL errors.
+	ErrMalformedACLError: {
+		Code:           "MalformedACLError",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrACLInvalidArgument: {
+		Code:           "InvalidArgument",
+		Description:    "Access control policy has an invalid argument.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrACLInvalidRequest: {
+		Code:           "InvalidRequest",
+		Description:    "Specifying both Canned ACLs and Header Grants is not allowed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUnexpectedContent: {
+		Code:           "UnexpectedContent",
+		Description:    "This request does not support content.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrRestoreAlreadyInProgress: {
+		Code:           "RestoreAlreadyInProgress",
+		Description:    "Object restore is already in progress.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrObjectNotArchived: {
+		Code:           "InvalidObjectState",
+		Description:    "Restore is not allowed for the object's current storage class.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrColdTierNotConfigured: {
+		Code:           "InvalidArgument",
+		Description:    "No cold tier is configured for GLACIER storage class, set MINIO_S3_COLD_TIER_BUCKET to enable it.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 
 	/// S3 extensions.
 	ErrContentSHA256Mismatch: {
@@ -777,6 +1026,46 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Credentials in config mismatch with server environment variables",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrAdminNoSuchUser: {
+		Code:           "XMinioAdminNoSuchUser",
+		Description:    "The specified user does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoSuchGroup: {
+		Code:           "XMinioAdminNoSuchGroup",
+		Description:    "The specified group does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoSuchPolicy: {
+		Code:           "XMinioAdminNoSuchPolicy",
+		Description:    "The canned policy does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminCannedPolicyReserved: {
+		Code:           "XMinioAdminCannedPolicyReserved",
+		Description:    "The built-in canned policies cannot be modified.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminNoSuchQuotaConfiguration: {
+		Code:           "XMinioAdminNoSuchQuotaConfiguration",
+		Description:    "The quota configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminInvalidBucketQuota: {
+		Code:           "XMinioAdminInvalidBucketQuota",
+		Description:    "The bucket quota must have a positive size and a type of hard or fifo.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminActionNotAllowed: {
+		Code:           "XMinioAdminActionNotAllowed",
+		Description:    "The requested action is not allowed for the server owner.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrQuotaExceeded: {
+		Code:           "XMinioQuotaExceeded",
+		Description:    "The write exceeds the quota of the bucket.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInsecureClientRequest: {
 		Code:           "XMinioInsecureClientRequest",
 		Description:    "Cannot respond to plain-text request from TLS-encrypted server",
@@ -845,6 +1134,51 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrKeyRotationAlreadyRunning: {
+		Code:           "XMinioKeyRotationAlreadyRunning",
+		Description:    "A master key rotation is already in progress.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidToken: {
+		Code:           "InvalidToken",
+		Description:    "The provided token is malformed or otherwise invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSInvalidAction: {
+		Code:           "InvalidAction",
+		Description:    "The action or operation requested is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSMissingParameter: {
+		Code:           "MissingParameter",
+		Description:    "A required parameter for the specified action is not supplied.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSInvalidParameterValue: {
+		Code:           "InvalidParameterValue",
+		Description:    "An invalid or out-of-range value was supplied for the input parameter.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSMalformedPolicyDocument: {
+		Code:           "MalformedPolicyDocument",
+		Description:    "The request was rejected because the policy document was malformed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSExpiredToken: {
+		Code:           "ExpiredTokenException",
+		Description:    "The web identity token that was passed is expired.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSInvalidIdentityToken: {
+		Code:           "InvalidIdentityToken",
+		Description:    "The web identity token that was passed could not be validated.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrSTSNotConfigured: {
+		Code:           "XMinioSTSNotConfigured",
+		Description:    "No identity provider is configured for web identities.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
 	ErrBackendDown: {
 		Code:           "XMinioBackendDown",
 		Description:    "Object storage backend is unreachable",
@@ -870,6 +1204,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrEntityTooLarge
 	case errDataTooSmall:
 		apiErr = ErrEntityTooSmall
+	case errServerNotInitialized:
+		apiErr = ErrServerNotInitialized
 	case auth.ErrInvalidAccessKeyLength:
 		apiErr = ErrAdminInvalidAccessKey
 	case auth.ErrInvalidSecretKeyLength:
@@ -881,6 +1217,25 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		return apiErr
 	}
 
+	switch err { // S3 Select errors
+	case s3select.ErrInvalidExpressionType:
+		return ErrInvalidExpressionType
+	case s3select.ErrInvalidCompressionFormat:
+		return ErrInvalidCompressionFormat
+	case s3select.ErrInvalidFileHeaderInfo:
+		return ErrInvalidFileHeaderInfo
+	case s3select.ErrInvalidJSONType:
+		return ErrInvalidJSONType
+	case s3select.ErrInvalidQuoteFields:
+		return ErrInvalidQuoteFields
+	case s3select.ErrInvalidRequestParameter:
+		return ErrInvalidRequestParameter
+	case s3select.ErrMissingRequiredParameter:
+		return ErrMissingRequiredParameter
+	case s3select.ErrObjectSerializationConflict:
+		return ErrObjectSerializationConflict
+	}
+
 	switch err { // SSE errors
 	case errInsecureSSERequest:
 		return ErrInsecureSSECustomerRequest
@@ -902,6 +1257,32 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		return ErrInvalidSSECustomerParameters
 	case errSSEKeyMismatch:
 		return ErrAccessDenied // no access without correct key
+	case errInvalidEncryptionMethod:
+		return ErrInvalidEncryptionMethod
+	case errIncompatibleEncryptionMethod:
+		return ErrIncompatibleEncryptionMethod
+	case errKMSNotConfigured:
+		return ErrKMSNotConfigured
+	case errInvalidKMSKeyIDRequest:
+		return ErrInvalidKMSKeyIDRequest
+	case kms.ErrKeyNotFound:
+		return ErrKMSKeyNotFound
+	case kms.ErrInvalidSealedKey:
+		return ErrObjectTampered
+	case errKeyRotationAlreadyRunning:
+		return ErrKeyRotationAlreadyRunning
+	case errNoSuchUser:
+		return ErrAdminNoSuchUser
+	case errNoSuchGroup:
+		return ErrAdminNoSuchGroup
+	case errNoSuchPolicy:
+		return ErrAdminNoSuchPolicy
+	case errCannedPolicyIsReserved:
+		return ErrAdminCannedPolicyReserved
+	case errIAMActionNotAllowed:
+		return ErrAdminActionNotAllowed
+	case errInvalidIAMArgument:
+		return ErrInvalidRequest
 	}
 
 	switch err.(type) {
@@ -931,6 +1312,14 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrBucketAlreadyOwnedByYou
 	case ObjectNotFound:
 		apiErr = ErrNoSuchKey
+	case ObjectArchived:
+		apiErr = ErrInvalidObjectState
+	case ColdTierNotConfigured:
+		apiErr = ErrColdTierNotConfigured
+	case VersionNotFound:
+		apiErr = ErrNoSuchVersion
+	case ObjectVersionDeleteMarker:
+		apiErr = ErrMethodNotAllowed
 	case ObjectAlreadyExists:
 		apiErr = ErrMethodNotAllowed
 	case ObjectNameInvalid:
@@ -971,6 +1360,26 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrPartsSizeUnequal
 	case BucketPolicyNotFound:
 		apiErr = ErrNoSuchBucketPolicy
+	case BucketLifecycleNotFound:
+		apiErr = ErrNoSuchLifecycleConfiguration
+	case BucketEncryptionNotFound:
+		apiErr = ErrNoSuchBucketEncryptionConfiguration
+	case BucketCORSNotFound:
+		apiErr = ErrNoSuchCORSConfiguration
+	case BucketQuotaNotFound:
+		apiErr = ErrAdminNoSuchQuotaConfiguration
+	case BucketQuotaExceeded:
+		apiErr = ErrQuotaExceeded
+	case BucketReplicationNotFound:
+		apiErr = ErrReplicationConfigurationNotFoundError
+	case BucketWebsiteNotFound:
+		apiErr = ErrNoSuchWebsiteConfiguration
+	case BucketTaggingNotFound:
+		apiErr = ErrNoSuchTagSet
+	case tagging.ErrInvalidTag:
+		apiErr = ErrInvalidTag
+	case *sql.Error:
+		apiErr = ErrParseUnsupportedSyntax
 	case *event.ErrInvalidEventName:
 		apiErr = ErrEventNotification
 	case *event.ErrInvalidARN:
@@ -993,6 +1402,16 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrOverlappingFilterNotification
 	case *event.ErrUnsupportedConfiguration:
 		apiErr = ErrUnsupportedNotification
+	case *lifecycle.ErrMalformedXML:
+		apiErr = ErrMalformedXML
+	case *lifecycle.ErrInvalidArgument:
+		apiErr = ErrLifecycleInvalidArgument
+	case *acl.ErrMalformedACL:
+		apiErr = ErrMalformedACLError
+	case *acl.ErrInvalidArgument:
+		apiErr = ErrACLInvalidArgument
+	case *acl.ErrInvalidRequest:
+		apiErr = ErrACLInvalidRequest
 	case BackendDown:
 		apiErr = ErrBackendDown
 	default:
@@ -87,6 +87,11 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 		w.Header().Set("Content-Encoding", objInfo.ContentEncoding)
 	}
 
+	// Set version id if the object is versioned.
+	if objInfo.VersionID != "" {
+		w.Header().Set(amzVersionID, objInfo.VersionID)
+	}
+
 	// Set all other user defined metadata.
 	for k, v := range objInfo.UserDefined {
 		if hasPrefix(k, ReservedMetadataPrefix) {
@@ -94,6 +99,11 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, contentRange *h
 			// values to client.
 			continue
 		}
+		if k == amzObjectTagging {
+			// Only the number of tags is sent to client.
+			w.Header().Set(amzTaggingCount, strconv.Itoa(len(getObjectTags(objInfo).TagSet.Tags)))
+			continue
+		}
 		w.Header().Set(k, v)
 	}
 
@@ -69,6 +69,32 @@ type ListObjectsResponse struct {
 	EncodingType string `xml:"EncodingType,omitempty"`
 }
 
+// ListVersionsResponse - format for list object versions response.
+type ListVersionsResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult" json:"-"`
+
+	Name            string
+	Prefix          string
+	KeyMarker       string
+	VersionIDMarker string `xml:"VersionIdMarker"`
+
+	// When response is truncated (the IsTruncated element value in the response
+	// is true), you can use the key name and version ID in these fields as
+	// markers in the subsequent request to get next set of versions.
+	NextKeyMarker       string `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string `xml:"NextVersionIdMarker,omitempty"`
+
+	MaxKeys   int
+	Delimiter string
+	// A flag that indicates whether or not ListObjectVersions returned all of the
+	// results that satisfied the search criteria.
+	IsTruncated bool
+
+	Versions       []ObjectVersion `xml:"Version"`
+	DeleteMarkers  []DeleteMarker  `xml:"DeleteMarker"`
+	CommonPrefixes []CommonPrefix
+}
+
 // ListObjectsV2Response - format for list objects response.
 type ListObjectsV2Response struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult" json:"-"`
@@ -200,6 +226,33 @@ type Object struct {
 	StorageClass string
 }
 
+// ObjectVersion container for object version metadata
+type ObjectVersion struct {
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string
+	Size         int64
+
+	// Owner of the object.
+	Owner Owner
+
+	// The class of storage used to store the object.
+	StorageClass string
+}
+
+// DeleteMarker container for delete marker metadata
+type DeleteMarker struct {
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+
+	// Owner of the delete marker.
+	Owner Owner
+}
+
 // CopyObjectResponse container returns ETag and LastModified of the successfully copied object
 type CopyObjectResponse struct {
 	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResult" json:"-"`
@@ -363,6 +416,65 @@ func generateListObjectsV1Response(bucket, prefix, marker, delimiter string, max
 	return data
 }
 
+// generates an ListObjectVersions response for the said bucket with other enumerated options.
+func generateListVersionsResponse(bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int, resp ListObjectVersionsInfo) ListVersionsResponse {
+	var versions []ObjectVersion
+	var deleteMarkers []DeleteMarker
+	var prefixes []CommonPrefix
+	var owner = Owner{}
+	var data = ListVersionsResponse{}
+
+	owner.ID = globalMinioDefaultOwnerID
+	for _, object := range resp.Objects {
+		if object.Name == "" {
+			continue
+		}
+		lastModified := object.ModTime.UTC().Format(timeFormatAMZLong)
+		if object.DeleteMarker {
+			deleteMarkers = append(deleteMarkers, DeleteMarker{
+				Key:          object.Name,
+				VersionID:    object.VersionID,
+				IsLatest:     object.IsLatest,
+				LastModified: lastModified,
+				Owner:        owner,
+			})
+			continue
+		}
+		var version = ObjectVersion{}
+		version.Key = object.Name
+		version.VersionID = object.VersionID
+		version.IsLatest = object.IsLatest
+		version.LastModified = lastModified
+		if object.ETag != "" {
+			version.ETag = "\"" + object.ETag + "\""
+		}
+		version.Size = object.Size
+		version.StorageClass = object.StorageClass
+		version.Owner = owner
+		versions = append(versions, version)
+	}
+	data.Name = bucket
+	data.Versions = versions
+	data.DeleteMarkers = deleteMarkers
+
+	data.Prefix = prefix
+	data.KeyMarker = keyMarker
+	data.VersionIDMarker = versionIDMarker
+	data.Delimiter = delimiter
+	data.MaxKeys = maxKeys
+
+	data.NextKeyMarker = resp.NextKeyMarker
+	data.NextVersionIDMarker = resp.NextVersionIDMarker
+	data.IsTruncated = resp.IsTruncated
+	for _, prefix := range resp.Prefixes {
+		var prefixItem = CommonPrefix{}
+		prefixItem.Prefix = prefix
+		prefixes = append(prefixes, prefixItem)
+	}
+	data.CommonPrefixes = prefixes
+	return data
+}
+
 // generates an ListObjectsV2 response for the said bucket with other enumerated options.
 func generateListObjectsV2Response(bucket, prefix, token, nextToken, startAfter, delimiter string, fetchOwner, isTruncated bool, maxKeys int, objects []ObjectInfo, prefixes []string) ListObjectsV2Response {
 	var contents []Object
@@ -67,18 +67,28 @@ func registerAPIRouter(router *mux.Router) {
 		bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.CompleteMultipartUploadHandler)).Queries("uploadId", "{uploadId:.*}")
 		// NewMultipartUpload
 		bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.NewMultipartUploadHandler)).Queries("uploads", "")
+		// SelectObjectContent
+		bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.SelectObjectContentHandler)).Queries("select", "").Queries("select-type", "2")
+		// RestoreObject
+		bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.RestoreObjectHandler)).Queries("restore", "")
 		// AbortMultipartUpload
 		bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.AbortMultipartUploadHandler)).Queries("uploadId", "{uploadId:.*}")
 		// GetObjectACL
 		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.GetObjectACLHandler)).Queries("acl", "")
+		// GetObjectTagging
+		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.GetObjectTaggingHandler)).Queries("tagging", "")
 		// GetObject
 		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.GetObjectHandler))
 		// CopyObject
 		bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(httpTraceAll(api.CopyObjectHandler))
 		// PutObjectACL
 		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.PutObjectACLHandler)).Queries("acl", "")
+		// PutObjectTagging
+		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.PutObjectTaggingHandler)).Queries("tagging", "")
 		// PutObject
 		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.PutObjectHandler))
+		// DeleteObjectTagging
+		bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.DeleteObjectTaggingHandler)).Queries("tagging", "")
 		// DeleteObject
 		bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.DeleteObjectHandler))
 
@@ -91,6 +101,22 @@ func registerAPIRouter(router *mux.Router) {
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketACLHandler)).Queries("acl", "")
 		// GetBucketLifecycle
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketLifecycleHandler)).Queries("lifecycle", "")
+		// GetBucketVersioning
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketVersioningHandler)).Queries("versioning", "")
+		// GetBucketTagging
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketTaggingHandler)).Queries("tagging", "")
+		// GetBucketEncryption
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketEncryptionHandler)).Queries("encryption", "")
+		// GetBucketCORS
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketCORSHandler)).Queries("cors", "")
+		// GetBucketReplication
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketReplicationHandler)).Queries("replication", "")
+		// GetBucketLogging
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketLoggingHandler)).Queries("logging", "")
+		// GetBucketWebsite
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketWebsiteHandler)).Queries("website", "")
+		// ListObjectVersions
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.ListObjectVersionsHandler)).Queries("versions", "")
 
 		// GetBucketNotification
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketNotificationHandler)).Queries("notification", "")
@@ -108,6 +134,20 @@ func registerAPIRouter(router *mux.Router) {
 		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketACLHandler)).Queries("acl", "")
 		// PutBucketLifecycle
 		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketLifecycleHandler)).Queries("lifecycle", "")
+		// PutBucketVersioning
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketVersioningHandler)).Queries("versioning", "")
+		// PutBucketTagging
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketTaggingHandler)).Queries("tagging", "")
+		// PutBucketEncryption
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketEncryptionHandler)).Queries("encryption", "")
+		// PutBucketCORS
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketCORSHandler)).Queries("cors", "")
+		// PutBucketReplication
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketReplicationHandler)).Queries("replication", "")
+		// PutBucketLogging
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketLoggingHandler)).Queries("logging", "")
+		// PutBucketWebsite
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketWebsiteHandler)).Queries("website", "")
 		// PutBucketNotification
 		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketNotificationHandler)).Queries("notification", "")
 		// PutBucket
@@ -122,6 +162,16 @@ func registerAPIRouter(router *mux.Router) {
 		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketPolicyHandler)).Queries("policy", "")
 		// DeleteBucketLifecycle
 		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketLifecycleHandler)).Queries("lifecycle", "")
+		// DeleteBucketTagging
+		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketTaggingHandler)).Queries("tagging", "")
+		// DeleteBucketEncryption
+		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketEncryptionHandler)).Queries("encryption", "")
+		// DeleteBucketCORS
+		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketCORSHandler)).Queries("cors", "")
+		// DeleteBucketReplication
+		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketReplicationHandler)).Queries("replication", "")
+		// DeleteBucketWebsite
+		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketWebsiteHandler)).Queries("website", "")
 		// DeleteBucket
 		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketHandler))
 	}
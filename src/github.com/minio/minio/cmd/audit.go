@@ -0,0 +1,65 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/audit"
+	"github.com/minio/minio/pkg/handlers"
+)
+
+// logAuditEntry - sends the audit log entry of an API call to the audit
+// targets. Requests without API name, such as internode RPC calls, are
+// not audited.
+func logAuditEntry(r *http.Request, w *httpResponseRecorder, reqInfo *logger.ReqInfo, tBefore, tAfter time.Time) {
+	if reqInfo == nil || reqInfo.API == "" || !globalAuditLogger.HasTargets() {
+		return
+	}
+
+	statusCode := w.statusCode()
+	entry := audit.Entry{
+		Version: audit.Version,
+		Time:    tBefore.UTC().Format(time.RFC3339Nano),
+		API: audit.API{
+			Name:       reqInfo.API,
+			Bucket:     reqInfo.BucketName,
+			Object:     reqInfo.ObjectName,
+			Status:     http.StatusText(statusCode),
+			StatusCode: statusCode,
+			ErrorCode:  w.errorCode(),
+			Duration:   tAfter.Sub(tBefore).String(),
+		},
+		RemoteHost: handlers.GetSourceIP(r),
+		RequestID:  w.Header().Get(responseRequestIDKey),
+		UserAgent:  r.UserAgent(),
+		AccessKey:  getRequestAccessKey(r),
+		ReqHeader:  audit.ToHeaderMap(r.Header),
+		RespHeader: audit.ToHeaderMap(w.Header()),
+	}
+	if !w.firstByteTime.IsZero() {
+		entry.API.TimeToFirstByte = w.firstByteTime.Sub(tBefore).String()
+	}
+
+	for id, err := range globalAuditLogger.Send(entry) {
+		// Log once per target, as full targets fail on each request.
+		logger.LogOnceIf(context.Background(), err, id)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/audit"
+)
+
+func TestLogAuditEntry(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	path := filepath.Join(rootPath, "audit.log")
+	config := *globalServerConfig
+	config.Audit.File = map[string]audit.FileArgs{"1": {Enable: true, Path: path}}
+	auditLogger, err := getAuditLogger(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(auditLogger *audit.Logger) { globalAuditLogger = auditLogger }(globalAuditLogger)
+	globalAuditLogger = auditLogger
+
+	router := mux.NewRouter()
+	router.Methods(http.MethodPut).Path("/{bucket}/{object:.+}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		newContext(r, "PutObject")
+		setCommonHeaders(w)
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+	})
+	// Requests without API name are not audited.
+	router.Methods(http.MethodPost).Path("/minio/rpc").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := setHTTPStatsHandler(router)
+
+	credentials := globalServerConfig.GetCredential()
+	req, err := newTestSignedRequestV4(http.MethodPut, "/bucket/object", 0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Amz-Meta-Color", "blue")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if req, err = http.NewRequest(http.MethodPost, "/minio/rpc", nil); err != nil {
+		t.Fatal(err)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	globalAuditLogger.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 audit log entry, got %d: %s", len(lines), data)
+	}
+
+	var entry audit.Entry
+	if err = json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.API.Name != "PutObject" || entry.API.Bucket != "bucket" || entry.API.Object != "object" {
+		t.Errorf("unexpected API: %v", entry.API)
+	}
+	if entry.API.StatusCode != http.StatusForbidden || entry.API.ErrorCode != "AccessDenied" {
+		t.Errorf("unexpected status: %v", entry.API)
+	}
+	if entry.AccessKey != credentials.AccessKey {
+		t.Errorf("expected access key %s, got %s", credentials.AccessKey, entry.AccessKey)
+	}
+	if entry.RequestID == "" || entry.RespHeader[http.CanonicalHeaderKey(responseRequestIDKey)] == "" {
+		t.Errorf("missing request ID: %v", entry)
+	}
+	if entry.ReqHeader["X-Amz-Meta-Color"] != "blue" {
+		t.Errorf("missing request header: %v", entry.ReqHeader)
+	}
+	if _, ok := entry.ReqHeader["Authorization"]; ok {
+		t.Errorf("unexpected authorization header: %v", entry.ReqHeader)
+	}
+}
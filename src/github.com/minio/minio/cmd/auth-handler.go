@@ -28,6 +28,7 @@ import (
 	"strings"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/hash"
 	"github.com/minio/minio/pkg/policy"
 )
@@ -110,13 +111,83 @@ func getRequestAuthType(r *http.Request) authType {
 	return authTypeUnknown
 }
 
+// getReqAccessKeyV4 - returns the credentials of the access key of a
+// signature V4 request, and whether it is the owner.
+func getReqAccessKeyV4(r *http.Request, region string, stype serviceType) (auth.Credentials, bool, APIErrorCode) {
+	ch, s3Err := parseCredentialHeader("Credential="+r.URL.Query().Get("X-Amz-Credential"), region, stype)
+	if s3Err != ErrNone {
+		// Strip off the Algorithm prefix.
+		v4Auth := strings.TrimPrefix(r.Header.Get("Authorization"), signV4Algorithm)
+		authFields := strings.Split(strings.TrimSpace(v4Auth), ",")
+		if len(authFields) != 3 {
+			return auth.Credentials{}, false, ErrMissingFields
+		}
+		ch, s3Err = parseCredentialHeader(authFields[0], region, stype)
+		if s3Err != ErrNone {
+			return auth.Credentials{}, false, s3Err
+		}
+	}
+	return checkKeyValid(ch.accessKey)
+}
+
+// getReqAccessKeyV2 - returns the credentials of the access key of a
+// signature V2 request, and whether it is the owner.
+func getReqAccessKeyV2(r *http.Request) (auth.Credentials, bool, APIErrorCode) {
+	if accessKey := r.URL.Query().Get("AWSAccessKeyId"); accessKey != "" {
+		return checkKeyValid(accessKey)
+	}
+
+	// below is V2 Signed Auth header format, splitting on `space` (after the `AWS` string).
+	// Authorization = "AWS" + " " + AWSAccessKeyId + ":" + Signature
+	authFields := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(authFields) != 2 {
+		return auth.Credentials{}, false, ErrMissingFields
+	}
+
+	// Then will be splitting on ":", this will seprate `AWSAccessKeyId` and `Signature` string.
+	keySignFields := strings.Split(strings.TrimSpace(authFields[1]), ":")
+	if len(keySignFields) != 2 {
+		return auth.Credentials{}, false, ErrMissingFields
+	}
+
+	return checkKeyValid(keySignFields[0])
+}
+
+// getRequestAccessKey - returns the access key which signed the
+// request, or an empty string for anonymous requests.
+func getRequestAccessKey(r *http.Request) string {
+	var cred auth.Credentials
+	var s3Err APIErrorCode
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypePresigned, authTypeStreamingSigned:
+		cred, _, s3Err = getReqAccessKeyV4(r, globalServerConfig.GetRegion(), serviceS3)
+	case authTypeSignedV2, authTypePresignedV2:
+		cred, _, s3Err = getReqAccessKeyV2(r)
+	default:
+		return ""
+	}
+
+	if s3Err != ErrNone {
+		return ""
+	}
+	return cred.AccessKey
+}
+
 // checkAdminRequestAuthType checks whether the request is a valid signature V2 or V4 request.
-// It does not accept presigned or JWT or anonymous requests.
+// It does not accept presigned or JWT or anonymous requests, nor
+// requests of IAM users.
 func checkAdminRequestAuthType(r *http.Request, region string) APIErrorCode {
 	s3Err := ErrAccessDenied
 	if _, ok := r.Header["X-Amz-Content-Sha256"]; ok && getRequestAuthType(r) == authTypeSigned && !skipContentSha256Cksum(r) { // we only support V4 (no presign) with auth. body
 		s3Err = isReqAuthenticated(r, region)
 	}
+	if s3Err == ErrNone {
+		if _, owner, errCode := getReqAccessKeyV4(r, region, serviceS3); errCode != ErrNone {
+			s3Err = errCode
+		} else if !owner {
+			s3Err = ErrAccessDenied
+		}
+	}
 	if s3Err != ErrNone {
 		reqInfo := (&logger.ReqInfo{}).AppendTags("requestHeaders", dumpRequest(r))
 		ctx := logger.SetReqInfo(context.Background(), reqInfo)
@@ -126,8 +197,8 @@ func checkAdminRequestAuthType(r *http.Request, region string) APIErrorCode {
 }
 
 func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Action, bucketName, objectName string) APIErrorCode {
-	isOwner := true
-	accountName := globalServerConfig.GetCredential().AccessKey
+	var cred auth.Credentials
+	var isOwner bool
 
 	switch getRequestAuthType(r) {
 	case authTypeUnknown:
@@ -136,6 +207,10 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 		if errorCode := isReqAuthenticatedV2(r); errorCode != ErrNone {
 			return errorCode
 		}
+		var errorCode APIErrorCode
+		if cred, isOwner, errorCode = getReqAccessKeyV2(r); errorCode != ErrNone {
+			return errorCode
+		}
 	case authTypeSigned, authTypePresigned:
 		region := globalServerConfig.GetRegion()
 		switch action {
@@ -146,10 +221,12 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 		if errorCode := isReqAuthenticated(r, region); errorCode != ErrNone {
 			return errorCode
 		}
-	default:
-		isOwner = false
-		accountName = ""
+		var errorCode APIErrorCode
+		if cred, isOwner, errorCode = getReqAccessKeyV4(r, region, serviceS3); errorCode != ErrNone {
+			return errorCode
+		}
 	}
+	accountName := cred.AccessKey
 
 	// LocationConstraint is valid only for CreateBucketAction.
 	var locationConstraint string
@@ -174,11 +251,16 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 		r.Body = ioutil.NopCloser(bytes.NewReader(payload))
 	}
 
-	if globalPolicySys.IsAllowed(policy.Args{
+	conditionValues := getConditionValues(r, locationConstraint)
+	if !isOwner {
+		addExistingObjectTags(ctx, r, action, accountName, bucketName, objectName, conditionValues)
+	}
+
+	if isAccountAllowed(ctx, policy.Args{
 		AccountName:     accountName,
 		Action:          action,
 		BucketName:      bucketName,
-		ConditionValues: getConditionValues(r, locationConstraint),
+		ConditionValues: conditionValues,
 		IsOwner:         isOwner,
 		ObjectName:      objectName,
 	}) {
@@ -188,6 +270,58 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 	return ErrAccessDenied
 }
 
+// isAccountAllowed - checks whether the policies of an IAM user, the
+// bucket policy or bucket and object ACLs allow given policy args. An
+// explicit deny of the IAM policies, the session policy or the bucket
+// policy is final, an allow of any other source cannot override it.
+func isAccountAllowed(ctx context.Context, args policy.Args) bool {
+	isIAMAccount := args.AccountName != "" && !args.IsOwner
+	if globalPolicySys.IsDenied(args) || (isIAMAccount && globalIAMSys.IsDenied(args)) {
+		return false
+	}
+
+	if isIAMAccount && globalIAMSys.IsAllowed(args) {
+		return true
+	}
+
+	if globalPolicySys.IsAllowed(args) {
+		return true
+	}
+
+	// Policy does not allow the request, check whether bucket or object ACL grants it.
+	return isAllowedByACL(ctx, args.Action, args.BucketName, args.ObjectName, args.AccountName)
+}
+
+// isPutAllowed - checks whether a signed PutObject or PutObjectPart
+// request, whose signature is verified by its handler, is allowed.
+func isPutAllowed(ctx context.Context, atype authType, bucketName, objectName string, r *http.Request) APIErrorCode {
+	var cred auth.Credentials
+	var owner bool
+	var s3Err APIErrorCode
+	switch atype {
+	case authTypeSignedV2, authTypePresignedV2:
+		cred, owner, s3Err = getReqAccessKeyV2(r)
+	case authTypeStreamingSigned, authTypePresigned, authTypeSigned:
+		cred, owner, s3Err = getReqAccessKeyV4(r, globalServerConfig.GetRegion(), serviceS3)
+	default:
+		return ErrAccessDenied
+	}
+	if s3Err != ErrNone {
+		return s3Err
+	}
+
+	if owner || isAccountAllowed(ctx, policy.Args{
+		AccountName:     cred.AccessKey,
+		Action:          policy.PutObjectAction,
+		BucketName:      bucketName,
+		ConditionValues: getConditionValues(r, ""),
+		ObjectName:      objectName,
+	}) {
+		return ErrNone
+	}
+	return ErrAccessDenied
+}
+
 // Verify if request has valid AWS Signature Version '2'.
 func isReqAuthenticatedV2(r *http.Request) (s3Error APIErrorCode) {
 	if isRequestSignatureV2(r) {
@@ -200,7 +334,7 @@ func reqSignatureV4Verify(r *http.Request, region string) (s3Error APIErrorCode)
 	sha256sum := getContentSha256Cksum(r)
 	switch {
 	case isRequestSignatureV4(r):
-		return doesSignatureMatch(sha256sum, r, region)
+		return doesSignatureMatch(sha256sum, r, region, serviceS3)
 	case isRequestPresignedSignatureV4(r):
 		return doesPresignedSignatureMatch(sha256sum, r, region)
 	default:
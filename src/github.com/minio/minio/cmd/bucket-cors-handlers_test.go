@@ -0,0 +1,157 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/cors"
+)
+
+func TestBucketCORSHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketCORSHandlers, []string{"PutBucketCORS", "GetBucketCORS", "DeleteBucketCORS"})
+}
+
+func testBucketCORSHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	var err error
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, url, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	corsURL := getBucketCORSURL("", bucketName)
+
+	if rec := serve("GET", corsURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	invalidConfig := []byte(`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>PATCH</AllowedMethod></CORSRule></CORSConfiguration>`)
+	if rec := serve("PUT", corsURL, invalidConfig); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	config := []byte(`<CORSConfiguration><CORSRule><AllowedOrigin>https://*.example.com</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`)
+	if rec := serve("PUT", corsURL, config); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutBucketCORS failed with status `%d`", instanceType, rec.Code)
+	}
+	if globalBucketMetadataSys.GetCORS(bucketName) == nil {
+		t.Fatalf("%s: Expected CORS configuration to be cached", instanceType)
+	}
+	rec := serve("GET", corsURL, nil)
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte("<AllowedOrigin>https://*.example.com</AllowedOrigin>")) {
+		t.Fatalf("%s: GetBucketCORS failed with status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	if rec = serve("DELETE", corsURL, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: DeleteBucketCORS failed with status `%d`", instanceType, rec.Code)
+	}
+	if rec = serve("GET", corsURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+	if globalBucketMetadataSys.GetCORS(bucketName) != nil {
+		t.Fatalf("%s: Expected CORS configuration to be removed", instanceType)
+	}
+}
+
+func TestBucketCORSHandler(t *testing.T) {
+	defer func(sys *BucketMetadataSys) { globalBucketMetadataSys = sys }(globalBucketMetadataSys)
+	globalBucketMetadataSys = NewBucketMetadataSys()
+	globalBucketMetadataSys.Set("bucket", bucketCORSConfig, &cors.Config{Rules: []cors.Rule{
+		{
+			AllowedOrigins: []string{"https://*.example.com"},
+			AllowedMethods: []string{"GET", "PUT"},
+			AllowedHeaders: []string{"content-*"},
+			ExposeHeaders:  []string{"ETag"},
+			MaxAgeSeconds:  600,
+		},
+		{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET"},
+		},
+	}})
+
+	handler := setCorsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		method         string
+		path           string
+		origin         string
+		requestMethod  string
+		requestHeaders string
+		expectedStatus int
+		expectedOrigin string
+		expectedHeader map[string]string
+	}{
+		// Preflight allowed by the first rule.
+		{http.MethodOptions, "/bucket/object", "https://www.example.com", "PUT", "Content-Type", http.StatusOK, "https://www.example.com",
+			map[string]string{"Access-Control-Allow-Methods": "GET, PUT", "Access-Control-Allow-Headers": "Content-Type", "Access-Control-Max-Age": "600", "Access-Control-Allow-Credentials": "true"}},
+		// Preflight with a header not allowed by any rule.
+		{http.MethodOptions, "/bucket/object", "https://www.example.com", "PUT", "X-Amz-Date", http.StatusForbidden, "", nil},
+		// Preflight allowed by the wildcard rule.
+		{http.MethodOptions, "/bucket/object", "http://other.org", "GET", "", http.StatusOK, "*", map[string]string{"Access-Control-Allow-Credentials": ""}},
+		{http.MethodOptions, "/bucket/object", "http://other.org", "PUT", "", http.StatusForbidden, "", nil},
+		// Actual requests.
+		{http.MethodPut, "/bucket/object", "https://www.example.com", "", "", http.StatusOK, "https://www.example.com", map[string]string{"Access-Control-Expose-Headers": "ETag"}},
+		{http.MethodGet, "/bucket/object", "http://other.org", "", "", http.StatusOK, "*", nil},
+		{http.MethodPut, "/bucket/object", "http://other.org", "", "", http.StatusOK, "", nil},
+		// Buckets without CORS configuration allow all origins.
+		{http.MethodPut, "/other-bucket/object", "http://other.org", "", "", http.StatusOK, "http://other.org", nil},
+		{http.MethodOptions, "/other-bucket/object", "http://other.org", "DELETE", "", http.StatusOK, "http://other.org", nil},
+	}
+
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://127.0.0.1:9000"+testCase.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Origin", testCase.origin)
+		if testCase.requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", testCase.requestMethod)
+		}
+		if testCase.requestHeaders != "" {
+			req.Header.Set("Access-Control-Request-Headers", testCase.requestHeaders)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expectedStatus, rec.Code)
+		}
+		if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != testCase.expectedOrigin {
+			t.Errorf("Test %d: expected allowed origin %q, got %q", i+1, testCase.expectedOrigin, origin)
+		}
+		for k, v := range testCase.expectedHeader {
+			if rec.Header().Get(k) != v {
+				t.Errorf("Test %d: expected %s %q, got %q", i+1, k, v, rec.Header().Get(k))
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/kms"
+	"github.com/minio/minio/pkg/sse"
+)
+
+func TestBucketEncryptionHandlers(t *testing.T) {
+	defer func(KMS kms.KMS, keyID string) { globalKMS, globalKMSKeyID = KMS, keyID }(globalKMS, globalKMSKeyID)
+	globalKMS, globalKMSKeyID = kms.NewLocal(kms.MasterKey{ID: "my-key"}), "my-key"
+
+	ExecObjectLayerAPITest(t, testBucketEncryptionHandlers, []string{"PutBucketEncryption", "GetBucketEncryption", "DeleteBucketEncryption", "PutObject", "GetObject"})
+}
+
+func testBucketEncryptionHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	var err error
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, url, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	encryptionURL := getBucketEncryptionURL("", bucketName)
+
+	if rec := serve("GET", encryptionURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	invalidConfig := []byte(`<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>DES</SSEAlgorithm></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`)
+	if rec := serve("PUT", encryptionURL, invalidConfig); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	config := []byte(`<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>AES256</SSEAlgorithm></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`)
+	if rec := serve("PUT", encryptionURL, config); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutBucketEncryption failed with status `%d`", instanceType, rec.Code)
+	}
+	rec := serve("GET", encryptionURL, nil)
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte("<SSEAlgorithm>AES256</SSEAlgorithm>")) {
+		t.Fatalf("%s: GetBucketEncryption failed with status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	// Objects uploaded without encryption headers are encrypted by default.
+	data := bytes.Repeat([]byte("a"), 1024)
+	rec = serve("PUT", getPutObjectURL("", bucketName, "object"), data)
+	if rec.Code != http.StatusOK || rec.Header().Get(SSEHeader) != SSECustomerAlgorithmAES256 {
+		t.Fatalf("%s: PutObject failed with status `%d` and SSE header %q", instanceType, rec.Code, rec.Header().Get(SSEHeader))
+	}
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isSSES3Encrypted(objInfo.UserDefined) {
+		t.Fatalf("%s: Expected object to be stored SSE-S3 encrypted", instanceType)
+	}
+	rec = serve("GET", getGetObjectURL("", bucketName, "object"), nil)
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("%s: GetObject failed with status `%d` or returned wrong content", instanceType, rec.Code)
+	}
+
+	if rec = serve("DELETE", encryptionURL, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: DeleteBucketEncryption failed with status `%d`", instanceType, rec.Code)
+	}
+	if rec = serve("GET", encryptionURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+	rec = serve("PUT", getPutObjectURL("", bucketName, "object-plain"), data)
+	if rec.Code != http.StatusOK || rec.Header().Get(SSEHeader) != "" {
+		t.Fatalf("%s: Expected object to be stored unencrypted after removing the default encryption", instanceType)
+	}
+}
+
+func TestSetBucketEncryptionHeaders(t *testing.T) {
+	defer func(sys *BucketMetadataSys) { globalBucketMetadataSys = sys }(globalBucketMetadataSys)
+	globalBucketMetadataSys = NewBucketMetadataSys()
+	globalIAMSys = NewIAMSys()
+	globalBucketMetadataSys.Set("kms-bucket", bucketEncryptionConfig, &sse.Config{Rules: []sse.Rule{{DefaultEncryption: sse.ApplySSEByDefault{SSEAlgorithm: sse.AWSKMS, KMSMasterKeyID: "my-key"}}}})
+
+	testCases := []struct {
+		bucket            string
+		header            map[string]string
+		expectedAlgorithm string
+		expectedKeyID     string
+	}{
+		{"kms-bucket", nil, sse.AWSKMS, "my-key"},
+		{"kms-bucket", map[string]string{SSEHeader: sse.AES256}, sse.AES256, ""},
+		{"kms-bucket", map[string]string{SSECustomerAlgorithm: sse.AES256}, "", ""},
+		{"bucket", nil, "", ""},
+	}
+
+	for i, testCase := range testCases {
+		header := http.Header{}
+		for k, v := range testCase.header {
+			header.Set(k, v)
+		}
+		setBucketEncryptionHeaders(testCase.bucket, header)
+		if header.Get(SSEHeader) != testCase.expectedAlgorithm || header.Get(SSEKMSKeyID) != testCase.expectedKeyID {
+			t.Errorf("test %d: expected (%s, %s), got (%s, %s)", i+1, testCase.expectedAlgorithm, testCase.expectedKeyID, header.Get(SSEHeader), header.Get(SSEKMSKeyID))
+		}
+	}
+}
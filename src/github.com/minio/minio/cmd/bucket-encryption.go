@@ -0,0 +1,38 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+)
+
+// setBucketEncryptionHeaders - sets the server-side encryption headers of
+// the default encryption of the bucket, if any, on requests without
+// encryption headers.
+func setBucketEncryptionHeaders(bucketName string, header http.Header) {
+	if hasSSECustomerHeader(header) || hasSSEHeader(header) {
+		return
+	}
+	config := globalBucketMetadataSys.GetEncryption(bucketName)
+	if config == nil {
+		return
+	}
+	header.Set(SSEHeader, config.Algorithm())
+	if keyID := config.KeyID(); keyID != "" {
+		header.Set(SSEKMSKeyID, keyID)
+	}
+}
@@ -181,3 +181,63 @@ func (api objectAPIHandlers) ListObjectsV1Handler(w http.ResponseWriter, r *http
 	// Write success response.
 	writeSuccessResponseXML(w, encodeResponse(response))
 }
+
+// ListObjectVersionsHandler - GET Bucket Object versions
+// --------------------------
+// This implementation of the GET operation returns metadata about all
+// versions of the objects in a bucket. You can use the request
+// parameters as selection criteria to return metadata about a subset
+// of all the object versions.
+func (api objectAPIHandlers) ListObjectVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "ListObjectVersions")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.ListBucketVersionsAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Extract all the listObjectVersions query params to their native values.
+	prefix, keyMarker, versionIDMarker, delimiter, maxKeys, _ := getListObjectVersionsArgs(r.URL.Query())
+
+	// Validate all the query params before beginning to serve the request.
+	if s3Error := validateListObjectsArgs(prefix, keyMarker, delimiter, maxKeys); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Version ID marker is only valid along with key marker.
+	if versionIDMarker != "" && (keyMarker == "" || !isValidVersionID(versionIDMarker)) {
+		writeErrorResponse(w, ErrInvalidVersionID, r.URL)
+		return
+	}
+
+	listObjectVersionsInfo, err := objectAPI.ListObjectVersions(ctx, bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	for i := range listObjectVersionsInfo.Objects {
+		if listObjectVersionsInfo.Objects[i].IsEncrypted() {
+			listObjectVersionsInfo.Objects[i].Size, err = listObjectVersionsInfo.Objects[i].DecryptedSize()
+			if err != nil {
+				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+				return
+			}
+		}
+	}
+
+	response := generateListVersionsResponse(bucket, prefix, keyMarker, versionIDMarker, delimiter, maxKeys, listObjectVersionsInfo)
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
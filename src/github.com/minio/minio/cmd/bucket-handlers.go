@@ -238,6 +238,10 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 
 	var wg = &sync.WaitGroup{} // Allocate a new wait group.
 	var dErrs = make([]error, len(deleteObjects.Objects))
+	var dObjInfos = make([]ObjectInfo, len(deleteObjects.Objects))
+
+	// Objects in versioned buckets are replaced by delete markers.
+	versioned := getBucketVersioningStatus(bucket) != ""
 
 	// Delete all requested objects in parallel.
 	for index, object := range deleteObjects.Objects {
@@ -257,28 +261,37 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			if api.CacheAPI() != nil {
 				deleteObject = api.CacheAPI().DeleteObject
 			}
-			dErr := deleteObject(ctx, bucket, obj.ObjectName)
-			if dErr != nil {
-				dErrs[i] = dErr
+			// Delete markers added to versioned buckets free no space.
+			if versioned {
+				dObjInfos[i], dErrs[i] = objectAPI.DeleteObjectVersion(ctx, bucket, obj.ObjectName, "")
+				return
+			}
+			size := globalBucketQuotaSys.objectSize(ctx, objectAPI, bucket, obj.ObjectName)
+			if dErrs[i] = deleteObject(ctx, bucket, obj.ObjectName); dErrs[i] != nil {
+				return
 			}
+			globalBucketQuotaSys.ObjectRemoved(bucket, size)
 		}(index, object)
 	}
 	wg.Wait()
 
 	// Collect deleted objects and errors if any.
 	var deletedObjects []ObjectIdentifier
+	var deletedObjInfos []ObjectInfo
 	var deleteErrors []DeleteError
 	for index, err := range dErrs {
 		object := deleteObjects.Objects[index]
 		// Success deleted objects are collected separately.
 		if err == nil {
 			deletedObjects = append(deletedObjects, object)
+			deletedObjInfos = append(deletedObjInfos, dObjInfos[index])
 			continue
 		}
 		if _, ok := err.(ObjectNotFound); ok {
 			// If the object is not found it should be
 			// accounted as deleted as per S3 spec.
 			deletedObjects = append(deletedObjects, object)
+			deletedObjInfos = append(deletedObjInfos, dObjInfos[index])
 			continue
 		}
 		// Error during delete should be collected separately.
@@ -303,13 +316,20 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 		host, port = "", ""
 	}
 
-	// Notify deleted event for objects.
-	for _, dobj := range deletedObjects {
+	// Notify deleted event for objects, or delete marker created
+	// event for objects replaced by a delete marker.
+	for index, dobj := range deletedObjects {
+		globalBucketReplicationSys.ReplicateDelete(bucket, dobj.ObjectName)
+		eventName := event.ObjectRemovedDelete
+		if deletedObjInfos[index].DeleteMarker {
+			eventName = event.ObjectRemovedDeleteMarkerCreated
+		}
 		sendEvent(eventArgs{
-			EventName:  event.ObjectRemovedDelete,
+			EventName:  eventName,
 			BucketName: bucket,
 			Object: ObjectInfo{
-				Name: dobj.ObjectName,
+				Name:      dobj.ObjectName,
+				VersionID: deletedObjInfos[index].VersionID,
 			},
 			ReqParams: extractReqParams(r),
 			UserAgent: r.UserAgent(),
@@ -353,6 +373,13 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Parse ACL of the bucket from x-amz-acl or x-amz-grant-* headers, if any.
+	aclPolicy, err := parseACLHeaders(r)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	bucketLock := globalNSMutex.NewNSLock(bucket, "")
 	if err := bucketLock.GetLock(globalObjectTimeout); err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -361,16 +388,36 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	defer bucketLock.Unlock()
 
 	// Proceed to creating a bucket.
-	err := objectAPI.MakeBucketWithLocation(ctx, bucket, location)
+	err = objectAPI.MakeBucketWithLocation(ctx, bucket, location)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	if aclPolicy != nil {
+		if err = setBucketACL(ctx, objectAPI, bucket, aclPolicy); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
 	// Make sure to add Location information here only for bucket
 	w.Header().Set("Location", path.Clean(r.URL.Path)) // Clean any trailing slashes.
 
 	writeSuccessResponseHeadersOnly(w)
+
+	// Get host and port from Request.RemoteAddr.
+	host, port, _ := net.SplitHostPort(r.RemoteAddr)
+
+	// Notify bucket created event.
+	sendEvent(eventArgs{
+		EventName:  event.BucketCreated,
+		BucketName: bucket,
+		ReqParams:  extractReqParams(r),
+		UserAgent:  r.UserAgent(),
+		Host:       host,
+		Port:       port,
+	})
 }
 
 // PostPolicyBucketHandler - POST policy
@@ -463,12 +510,24 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	}
 
 	// Verify policy signature.
-	apiErr := doesPolicySignatureMatch(formValues)
+	cred, apiErr := doesPolicySignatureMatch(formValues)
 	if apiErr != ErrNone {
 		writeErrorResponse(w, apiErr, r.URL)
 		return
 	}
 
+	// Uploads of IAM users must be allowed by their policies.
+	if cred.AccessKey != globalServerConfig.GetCredential().AccessKey && !isAccountAllowed(ctx, policy.Args{
+		AccountName:     cred.AccessKey,
+		Action:          policy.PutObjectAction,
+		BucketName:      bucket,
+		ConditionValues: getConditionValues(r, ""),
+		ObjectName:      object,
+	}) {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+
 	policyBytes, err := base64.StdEncoding.DecodeString(formValues.Get("Policy"))
 	if err != nil {
 		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
@@ -517,10 +576,10 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 	}
 
 	if objectAPI.IsEncryptionSupported() {
-		if hasSSECustomerHeader(formValues) && !hasSuffix(object, slashSeparator) { // handle SSE-C requests
+		if (hasSSECustomerHeader(formValues) || hasSSEHeader(formValues)) && !hasSuffix(object, slashSeparator) { // handle SSE-C, SSE-S3 and SSE-KMS requests
 			var reader io.Reader
 			var key []byte
-			key, err = ParseSSECustomerHeader(formValues)
+			key, err = newSSEKey(formValues, bucket, object, metadata)
 			if err != nil {
 				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 				return
@@ -539,12 +598,31 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		}
 	}
 
+	// Deny if the object exceeds the hard quota of the bucket.
+	replacedSize, releaseQuota, err := globalBucketQuotaSys.CheckQuota(ctx, objectAPI, bucket, object, fileSize)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer releaseQuota()
+
+	setReplicationStatus(ctx, r, bucket, object, metadata)
+
 	objInfo, err := objectAPI.PutObject(ctx, bucket, object, hashReader, metadata)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// New object does not inherit the ACL of the object it replaces.
+	if err = resetObjectACL(ctx, objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	globalBucketQuotaSys.ObjectCreated(bucket, objInfo.Size, replacedSize)
+	globalBucketReplicationSys.Replicate(bucket, objInfo)
+
 	location := getObjectLocation(r, globalDomainName, bucket, object)
 	w.Header().Set("ETag", `"`+objInfo.ETag+`"`)
 	w.Header().Set("Location", location)
@@ -653,8 +731,25 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Get host and port from Request.RemoteAddr.
+	host, port, _ := net.SplitHostPort(r.RemoteAddr)
+
+	// Notify bucket removed event, before the notification
+	// configuration of the bucket is removed.
+	sendEvent(eventArgs{
+		EventName:  event.BucketRemoved,
+		BucketName: bucket,
+		ReqParams:  extractReqParams(r),
+		UserAgent:  r.UserAgent(),
+		Host:       host,
+		Port:       port,
+	})
+
 	globalNotificationSys.RemoveNotification(bucket)
 	globalPolicySys.Remove(bucket)
+	globalBucketMetadataSys.Remove(bucket)
+	globalBucketQuotaSys.Remove(bucket)
+	globalACLSys.Remove(bucket)
 	for nerr := range globalNotificationSys.DeleteBucket(bucket) {
 		logger.GetReqInfo(ctx).AppendTags("remotePeer", nerr.Host.Name)
 		logger.LogIf(ctx, nerr.Err)
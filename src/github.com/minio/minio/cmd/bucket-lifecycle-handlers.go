@@ -17,13 +17,10 @@
 package cmd
 
 import (
-	"encoding/xml"
 	"io"
 	"net/http"
 
-	//humanize "github.com/dustin/go-humanize"
 	"github.com/gorilla/mux"
-	//"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/policy"
 )
@@ -61,7 +58,7 @@ func (api objectAPIHandlers) PutBucketLifecycleHandler(w http.ResponseWriter, r
 
 	lifecycle, err := lifecycle.ParseConfig(io.LimitReader(r.Body, r.ContentLength), bucket)
 	if err != nil {
-		writeErrorResponse(w, ErrMalformedPolicy, r.URL)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
@@ -71,6 +68,8 @@ func (api objectAPIHandlers) PutBucketLifecycleHandler(w http.ResponseWriter, r
 		return
 	}
 
+	updateBucketConfig(ctx, bucket, bucketLifecycleConfig, lifecycle)
+
 	// Success.
 	writeSuccessNoContent(w)
 }
@@ -105,6 +104,8 @@ func (api objectAPIHandlers) DeleteBucketLifecycleHandler(w http.ResponseWriter,
 		return
 	}
 
+	updateBucketConfig(ctx, bucket, bucketLifecycleConfig, nil)
+
 	// Success.
 	writeSuccessNoContent(w)
 }
@@ -140,12 +141,5 @@ func (api objectAPIHandlers) GetBucketLifecycleHandler(w http.ResponseWriter, r
 	}
 
 	// Write to client.
-
-	if err := xml.NewEncoder(w).Encode(lifecycle); err != nil {
-		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
-		return
-	}
-
-	w.(http.Flusher).Flush()
-
+	writeSuccessResponseXML(w, encodeResponse(lifecycle))
 }
@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/logging"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// Namespace of the logging status of buckets.
+const loggingXMLNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// PutBucketLoggingHandler - This HTTP handler sets the server access
+// logging status of a bucket. An empty logging status disables logging.
+func (api objectAPIHandlers) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "PutBucketLogging")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketLoggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	// PutBucketLogging always needs Content-Length.
+	if r.ContentLength <= 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+
+	config, err := logging.ParseConfig(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+
+	if !config.IsEnabled() {
+		if err = objAPI.DeleteBucketConfig(ctx, bucket, bucketLoggingConfig); err != nil {
+			if _, ok := err.(BucketLoggingNotFound); !ok {
+				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+				return
+			}
+		}
+
+		updateBucketConfig(ctx, bucket, bucketLoggingConfig, nil)
+
+		// Success.
+		writeSuccessResponseHeadersOnly(w)
+		return
+	}
+
+	// Access logs are written to an existing bucket only.
+	if _, err = objAPI.GetBucketInfo(ctx, config.LoggingEnabled.TargetBucket); err != nil {
+		if _, ok := err.(BucketNotFound); ok {
+			writeErrorResponse(w, ErrInvalidTargetBucketForLogging, r.URL)
+			return
+		}
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Access logs are written on behalf of the requester, who must be
+	// allowed to write into the target bucket.
+	accessKey := getRequestAccessKey(r)
+	if accessKey != globalServerConfig.GetCredential().AccessKey && !isAccountAllowed(ctx, policy.Args{
+		AccountName:     accessKey,
+		Action:          policy.PutObjectAction,
+		BucketName:      config.LoggingEnabled.TargetBucket,
+		ConditionValues: getConditionValues(r, ""),
+		ObjectName:      config.LoggingEnabled.TargetPrefix,
+	}) {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+
+	if err = objAPI.SetBucketConfig(ctx, bucket, bucketLoggingConfig, config); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	updateBucketConfig(ctx, bucket, bucketLoggingConfig, config)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketLoggingHandler - This HTTP handler returns the server access
+// logging status of a bucket.
+func (api objectAPIHandlers) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "GetBucketLogging")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketLoggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	bucketConfig, err := objAPI.GetBucketConfig(ctx, bucket, bucketLoggingConfig)
+	if err != nil {
+		if _, ok := err.(BucketLoggingNotFound); !ok {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+		// Logging is disabled.
+		bucketConfig = &logging.Config{}
+	}
+	config := bucketConfig.(*logging.Config)
+	config.XMLNS = loggingXMLNS
+
+	// Write to client.
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
@@ -0,0 +1,197 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/logging"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+)
+
+func TestBucketLoggingHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketLoggingHandlers, []string{"PutBucketLogging", "GetBucketLogging"})
+}
+
+func testBucketLoggingHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	var err error
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, url, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	loggingURL := getBucketLoggingURL("", bucketName)
+
+	// Logging is disabled by default.
+	rec := serve("GET", loggingURL, nil)
+	if rec.Code != http.StatusOK || bytes.Contains(rec.Body.Bytes(), []byte("<LoggingEnabled>")) {
+		t.Fatalf("%s: GetBucketLogging failed with status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	invalidConfig := []byte(`<BucketLoggingStatus><LoggingEnabled><TargetPrefix>logs/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`)
+	if rec = serve("PUT", loggingURL, invalidConfig); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	missingTarget := []byte(`<BucketLoggingStatus><LoggingEnabled><TargetBucket>missing-bucket</TargetBucket></LoggingEnabled></BucketLoggingStatus>`)
+	rec = serve("PUT", loggingURL, missingTarget)
+	if rec.Code != http.StatusBadRequest || !bytes.Contains(rec.Body.Bytes(), []byte("InvalidTargetBucketForLogging")) {
+		t.Fatalf("%s: Expected InvalidTargetBucketForLogging, but instead found `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	config := []byte(`<BucketLoggingStatus><LoggingEnabled><TargetBucket>` + bucketName + `</TargetBucket><TargetPrefix>logs/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`)
+
+	// Users not allowed to write into the target bucket cannot enable
+	// logging.
+	userCred, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetUser(obj, userCred.AccessKey, madmin.UserInfo{SecretKey: userCred.SecretKey}); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetPolicy(obj, "logging", newCannedPolicy(policy.PutBucketLoggingAction)); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetUserPolicy(obj, userCred.AccessKey, "logging"); err != nil {
+		t.Fatal(err)
+	}
+	userReq, err := newTestSignedRequestV4("PUT", loggingURL, int64(len(config)), bytes.NewReader(config), userCred.AccessKey, userCred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, userReq)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusForbidden, rec.Code)
+	}
+
+	if rec = serve("PUT", loggingURL, config); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutBucketLogging failed with status `%d`", instanceType, rec.Code)
+	}
+	if config := globalBucketMetadataSys.GetLogging(bucketName); config == nil || !config.IsEnabled() {
+		t.Fatalf("%s: Expected logging configuration to be cached", instanceType)
+	}
+	rec = serve("GET", loggingURL, nil)
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte("<TargetPrefix>logs/</TargetPrefix>")) {
+		t.Fatalf("%s: GetBucketLogging failed with status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	// An empty logging status disables logging.
+	if rec = serve("PUT", loggingURL, []byte(`<BucketLoggingStatus />`)); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutBucketLogging failed with status `%d`", instanceType, rec.Code)
+	}
+	if globalBucketMetadataSys.GetLogging(bucketName) != nil {
+		t.Fatalf("%s: Expected logging configuration to be removed", instanceType)
+	}
+	rec = serve("GET", loggingURL, nil)
+	if rec.Code != http.StatusOK || bytes.Contains(rec.Body.Bytes(), []byte("<LoggingEnabled>")) {
+		t.Fatalf("%s: GetBucketLogging failed with status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+}
+
+func TestBucketAccessLogging(t *testing.T) {
+	ExecObjectLayerTest(t, testBucketAccessLogging)
+}
+
+func testBucketAccessLogging(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	ctx := context.Background()
+	bucket, targetBucket := "source", "logs"
+	for _, name := range []string{bucket, targetBucket} {
+		if err := obj.MakeBucketWithLocation(ctx, name, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	globalBucketMetadataSys.Set(bucket, bucketLoggingConfig, &logging.Config{
+		LoggingEnabled: &logging.LoggingEnabled{TargetBucket: targetBucket, TargetPrefix: "source/"},
+	})
+	defer globalBucketMetadataSys.Remove(bucket)
+
+	router := mux.NewRouter()
+	router.Methods(http.MethodGet).Path("/{bucket}/{object:.+}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		newContext(r, "GetObject")
+		setCommonHeaders(w)
+		writeErrorResponse(w, ErrNoSuchKey, r.URL)
+	})
+	router.Methods(http.MethodGet).Path("/{bucket}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		newContext(r, "ListObjectsV1")
+		writeSuccessResponseXML(w, []byte("<ListBucketResult></ListBucketResult>"))
+	})
+	handler := setHTTPStatsHandler(router)
+
+	for _, path := range []string{"/source/missing.txt", "/source", "/logs"} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("User-Agent", "test-agent")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	globalBucketLoggingSys.flush(obj)
+
+	result, err := obj.ListObjects(ctx, targetBucket, "source/", "", "", 10)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Objects) != 1 {
+		t.Fatalf("%s: expected 1 access log object, got %d", instanceType, len(result.Objects))
+	}
+
+	var buffer bytes.Buffer
+	if err = obj.GetObject(ctx, targetBucket, result.Objects[0].Name, 0, -1, &buffer, ""); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	records := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(records) != 2 {
+		t.Fatalf("%s: expected 2 access log records, got %d: %s", instanceType, len(records), buffer.String())
+	}
+
+	expectedRecords := []string{
+		`REST.GET.OBJECT missing.txt "GET /source/missing.txt HTTP/1.1" 404 NoSuchKey 254 - `,
+		`REST.GET.BUCKET - "GET /source HTTP/1.1" 200 - 37 - `,
+	}
+	owner := globalServerConfig.GetCredential().AccessKey
+	for i, record := range records {
+		if !strings.HasPrefix(record, owner+" source [") || !strings.Contains(record, expectedRecords[i]) ||
+			!strings.HasSuffix(record, `"test-agent" -`) {
+			t.Errorf("%s: unexpected access log record %d: %s", instanceType, i+1, record)
+		}
+	}
+}
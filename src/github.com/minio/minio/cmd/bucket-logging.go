@@ -0,0 +1,264 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/handlers"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/logging"
+)
+
+const (
+	// Interval of writing buffered access logs into target buckets.
+	accessLogFlushInterval = 5 * time.Minute
+
+	// Maximum number of buffered access log records of a target,
+	// written once reached.
+	maxAccessLogEntries = 1000
+
+	// Value replacing credentials in the logged request URI.
+	accessLogRedacted = "*REDACTED*"
+)
+
+// Query parameters of presigned requests which carry credentials and
+// are redacted in access logs.
+var accessLogRedactedParams = []string{
+	"X-Amz-Signature",
+	"X-Amz-Security-Token",
+	"Signature",
+}
+
+// Subresources of bucket and object requests and their resource names
+// in access log operations, like Amazon S3 names them.
+var accessLogSubresources = []struct {
+	param          string
+	bucketResource string
+	objectResource string
+}{
+	{"acl", "ACL", "ACL"},
+	{"cors", "CORS", "CORS"},
+	{"delete", "MULTI_OBJECT_DELETE", "MULTI_OBJECT_DELETE"},
+	{"encryption", "ENCRYPTION", "ENCRYPTION"},
+	{"lifecycle", "LIFECYCLE", "LIFECYCLE"},
+	{"location", "LOCATION", "LOCATION"},
+	{"logging", "LOGGING_STATUS", "LOGGING_STATUS"},
+	{"notification", "NOTIFICATION", "NOTIFICATION"},
+	{"policy", "BUCKETPOLICY", "BUCKETPOLICY"},
+	{"replication", "REPLICATION", "REPLICATION"},
+	{"restore", "RESTORE", "RESTORE"},
+	{"tagging", "TAGGING", "OBJECT_TAGGING"},
+	{"uploads", "UPLOADS", "UPLOADS"},
+	{"versioning", "VERSIONING", "VERSIONING"},
+	{"versions", "BUCKETVERSIONS", "BUCKETVERSIONS"},
+	{"website", "WEBSITE", "WEBSITE"},
+}
+
+// BucketLoggingSys - Bucket logging subsystem, which buffers access log
+// records of buckets having logging configuration in BucketMetadataSys.
+type BucketLoggingSys struct {
+	// Buffered access log records of each target.
+	logMutex   sync.Mutex
+	accessLogs map[logging.LoggingEnabled][]string
+}
+
+// Init - initializes bucket logging system to write access logs in
+// background.
+func (sys *BucketLoggingSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	go func() {
+		flushTicker := time.NewTicker(accessLogFlushInterval)
+		defer flushTicker.Stop()
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				sys.flush(objAPI)
+				return
+			case <-flushTicker.C:
+				sys.flush(objAPI)
+			}
+		}
+	}()
+	return nil
+}
+
+// LogRequest - records a request in the access log of its bucket, if
+// logging is enabled for the bucket.
+func (sys *BucketLoggingSys) LogRequest(r *http.Request, w *httpResponseRecorder, reqInfo *logger.ReqInfo, tBefore, tAfter time.Time) {
+	if reqInfo == nil || reqInfo.BucketName == "" {
+		return
+	}
+
+	config := globalBucketMetadataSys.GetLogging(reqInfo.BucketName)
+	if config == nil || !config.IsEnabled() {
+		return
+	}
+
+	entry := logging.Entry{
+		BucketOwner: globalServerConfig.GetCredential().AccessKey,
+		Bucket:      reqInfo.BucketName,
+		Time:        tBefore,
+		RemoteIP:    handlers.GetSourceIP(r),
+		Requester:   getRequestAccessKey(r),
+		RequestID:   w.Header().Get(responseRequestIDKey),
+		Operation:   getAccessLogOperation(r, reqInfo),
+		Key:         reqInfo.ObjectName,
+		RequestURI:  r.Method + " " + getAccessLogRequestURI(r) + " " + r.Proto,
+		HTTPStatus:  w.statusCode(),
+		ErrorCode:   w.errorCode(),
+		BytesSent:   w.bytesWritten,
+		TotalTime:   tAfter.Sub(tBefore),
+		Referrer:    r.Referer(),
+		UserAgent:   r.UserAgent(),
+		VersionID:   r.URL.Query().Get("versionId"),
+	}
+	if !w.firstByteTime.IsZero() {
+		entry.TurnAroundTime = w.firstByteTime.Sub(tBefore)
+	}
+	if reqInfo.ObjectName != "" && entry.HTTPStatus < http.StatusMultipleChoices {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			entry.ObjectSize = w.bytesWritten
+		case http.MethodPut:
+			entry.ObjectSize = r.ContentLength
+		}
+	}
+
+	sys.log(*config.LoggingEnabled, entry.String())
+}
+
+// log - buffers an access log record of target, and writes the records
+// of target into its bucket once the buffer is full.
+func (sys *BucketLoggingSys) log(target logging.LoggingEnabled, record string) {
+	sys.logMutex.Lock()
+	defer sys.logMutex.Unlock()
+
+	sys.accessLogs[target] = append(sys.accessLogs[target], record)
+	if len(sys.accessLogs[target]) < maxAccessLogEntries {
+		return
+	}
+
+	records := sys.accessLogs[target]
+	delete(sys.accessLogs, target)
+	if objAPI := newObjectLayerFn(); objAPI != nil {
+		go writeAccessLog(objAPI, target, records)
+	}
+}
+
+// flush - writes all buffered access log records into target buckets.
+func (sys *BucketLoggingSys) flush(objAPI ObjectLayer) {
+	sys.logMutex.Lock()
+	accessLogs := sys.accessLogs
+	sys.accessLogs = make(map[logging.LoggingEnabled][]string)
+	sys.logMutex.Unlock()
+
+	for target, records := range accessLogs {
+		writeAccessLog(objAPI, target, records)
+	}
+}
+
+// NewBucketLoggingSys - creates new bucket logging system.
+func NewBucketLoggingSys() *BucketLoggingSys {
+	return &BucketLoggingSys{
+		accessLogs: make(map[logging.LoggingEnabled][]string),
+	}
+}
+
+// writeAccessLog - writes access log records as a new object into the
+// target bucket.
+func writeAccessLog(objAPI ObjectLayer, target logging.LoggingEnabled, records []string) {
+	// Access log objects are named like Amazon S3 names them.
+	object := target.TargetPrefix + UTCNow().Format("2006-01-02-15-04-05-") +
+		strings.ToUpper(strings.Replace(mustGetUUID(), "-", "", -1)[:16])
+	data := []byte(strings.Join(records, "\n") + "\n")
+
+	ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{BucketName: target.TargetBucket, ObjectName: object})
+	reader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", "")
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	if _, err = objAPI.PutObject(ctx, target.TargetBucket, object, reader, map[string]string{"content-type": "text/plain"}); err != nil {
+		logger.LogIf(ctx, err)
+	}
+}
+
+// getAccessLogRequestURI - returns the request URI with the
+// credentials of presigned requests redacted.
+func getAccessLogRequestURI(r *http.Request) string {
+	query := r.URL.Query()
+	redacted := false
+	for _, param := range accessLogRedactedParams {
+		if _, ok := query[param]; ok {
+			query.Set(param, accessLogRedacted)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return r.URL.RequestURI()
+	}
+
+	u := *r.URL
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// getAccessLogOperation - returns the operation of a request in access
+// log format, such as REST.GET.OBJECT, REST.PUT.ACL or REST.COPY.OBJECT.
+func getAccessLogOperation(r *http.Request, reqInfo *logger.ReqInfo) string {
+	query := r.URL.Query()
+
+	resource := "BUCKET"
+	if reqInfo.ObjectName != "" {
+		resource = "OBJECT"
+	}
+	if _, ok := query["uploadId"]; ok {
+		// Parts are uploaded by PUT, all other requests of a
+		// multipart upload act on the upload.
+		resource = "UPLOAD"
+		if r.Method == http.MethodPut {
+			resource = "PART"
+		}
+	} else {
+		for _, subresource := range accessLogSubresources {
+			if _, ok := query[subresource.param]; !ok {
+				continue
+			}
+			resource = subresource.bucketResource
+			if reqInfo.ObjectName != "" {
+				resource = subresource.objectResource
+			}
+			break
+		}
+	}
+
+	method := r.Method
+	if method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "" {
+		method = "COPY"
+	}
+	return "REST." + method + "." + resource
+}
@@ -0,0 +1,91 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+func TestGetAccessLogOperation(t *testing.T) {
+	testCases := []struct {
+		method            string
+		url               string
+		copySource        string
+		objectName        string
+		expectedOperation string
+	}{
+		{http.MethodGet, "/bucket", "", "", "REST.GET.BUCKET"},
+		{http.MethodGet, "/bucket/object", "", "object", "REST.GET.OBJECT"},
+		{http.MethodHead, "/bucket/object", "", "object", "REST.HEAD.OBJECT"},
+		{http.MethodPut, "/bucket/object", "", "object", "REST.PUT.OBJECT"},
+		{http.MethodPut, "/bucket/object", "/other/object", "object", "REST.COPY.OBJECT"},
+		{http.MethodPut, "/bucket?acl", "", "", "REST.PUT.ACL"},
+		{http.MethodGet, "/bucket/object?acl", "", "object", "REST.GET.ACL"},
+		{http.MethodGet, "/bucket?policy", "", "", "REST.GET.BUCKETPOLICY"},
+		{http.MethodPut, "/bucket?logging", "", "", "REST.PUT.LOGGING_STATUS"},
+		{http.MethodGet, "/bucket?tagging", "", "", "REST.GET.TAGGING"},
+		{http.MethodDelete, "/bucket/object?tagging", "", "object", "REST.DELETE.OBJECT_TAGGING"},
+		{http.MethodGet, "/bucket?versions", "", "", "REST.GET.BUCKETVERSIONS"},
+		{http.MethodPost, "/bucket?delete", "", "", "REST.POST.MULTI_OBJECT_DELETE"},
+		{http.MethodPost, "/bucket/object?uploads", "", "object", "REST.POST.UPLOADS"},
+		{http.MethodPut, "/bucket/object?partNumber=1&uploadId=abc", "", "object", "REST.PUT.PART"},
+		{http.MethodPut, "/bucket/object?partNumber=1&uploadId=abc", "/other/object", "object", "REST.COPY.PART"},
+		{http.MethodPost, "/bucket/object?uploadId=abc", "", "object", "REST.POST.UPLOAD"},
+		{http.MethodDelete, "/bucket/object?uploadId=abc", "", "object", "REST.DELETE.UPLOAD"},
+	}
+
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.copySource != "" {
+			req.Header.Set("X-Amz-Copy-Source", testCase.copySource)
+		}
+		reqInfo := &logger.ReqInfo{BucketName: "bucket", ObjectName: testCase.objectName}
+		if operation := getAccessLogOperation(req, reqInfo); operation != testCase.expectedOperation {
+			t.Errorf("Test %d: expected operation %s, got %s", i+1, testCase.expectedOperation, operation)
+		}
+	}
+}
+
+func TestGetAccessLogRequestURI(t *testing.T) {
+	testCases := []struct {
+		url         string
+		expectedURI string
+	}{
+		{"/bucket/object", "/bucket/object"},
+		{"/bucket/object?versionId=1", "/bucket/object?versionId=1"},
+		{"/bucket/object?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Security-Token=token&X-Amz-Signature=abc",
+			"/bucket/object?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Security-Token=%2AREDACTED%2A&X-Amz-Signature=%2AREDACTED%2A"},
+		{"/bucket/object?AWSAccessKeyId=minio&Expires=1&Signature=abc",
+			"/bucket/object?AWSAccessKeyId=minio&Expires=1&Signature=%2AREDACTED%2A"},
+	}
+
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(http.MethodGet, testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uri := getAccessLogRequestURI(req); uri != testCase.expectedURI {
+			t.Errorf("Test %d: expected request URI %s, got %s", i+1, testCase.expectedURI, uri)
+		}
+	}
+}
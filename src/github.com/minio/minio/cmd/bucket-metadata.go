@@ -0,0 +1,526 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
+	"github.com/minio/minio/pkg/cors"
+	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/logging"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/replication"
+	"github.com/minio/minio/pkg/sse"
+	"github.com/minio/minio/pkg/versioning"
+	"github.com/minio/minio/pkg/website"
+)
+
+const (
+	// Bucket ACL configuration file.
+	bucketACLConfig = "acl.xml"
+
+	// Bucket CORS configuration file.
+	bucketCORSConfig = "cors.xml"
+
+	// Bucket encryption configuration file.
+	bucketEncryptionConfig = "encryption.xml"
+
+	// Bucket lifecycle configuration file.
+	bucketLifecycleConfig = "lifecycle.xml"
+
+	// Bucket logging configuration file.
+	bucketLoggingConfig = "logging.xml"
+
+	// Bucket quota configuration file.
+	bucketQuotaConfig = "quota.json"
+
+	// Bucket replication configuration file.
+	bucketReplicationConfig = "replication.xml"
+
+	// Bucket versioning configuration file.
+	bucketVersioningConfig = "versioning.xml"
+
+	// Bucket website configuration file.
+	bucketWebsiteConfig = "website.xml"
+)
+
+// bucketConfigFormat - parses and encodes a bucket configuration file.
+type bucketConfigFormat struct {
+	parse   func(bucketName string, reader io.Reader) (interface{}, error)
+	marshal func(config interface{}) ([]byte, error)
+	// Error returned when the bucket has no configuration, nil if
+	// missing configuration is not an error.
+	notFound func(bucketName string) error
+}
+
+// bucketConfigFormats - bucket configuration files cached by
+// BucketMetadataSys. Parsed configurations are pointers to the
+// configuration type of the file.
+var bucketConfigFormats = map[string]bucketConfigFormat{
+	bucketACLConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return acl.ParseConfig(reader, "")
+		},
+		marshal:  xml.Marshal,
+		notFound: func(bucketName string) error { return BucketACLNotFound{Bucket: bucketName} },
+	},
+	bucketCORSConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return cors.ParseConfig(reader)
+		},
+		marshal:  xml.Marshal,
+		notFound: func(bucketName string) error { return BucketCORSNotFound{Bucket: bucketName} },
+	},
+	bucketEncryptionConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return sse.ParseConfig(reader)
+		},
+		marshal:  xml.Marshal,
+		notFound: func(bucketName string) error { return BucketEncryptionNotFound{Bucket: bucketName} },
+	},
+	bucketLifecycleConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return lifecycle.ParseConfig(reader, bucketName)
+		},
+		marshal:  xml.Marshal,
+		notFound: func(bucketName string) error { return BucketLifecycleNotFound{Bucket: bucketName} },
+	},
+	bucketLoggingConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return logging.ParseConfig(reader)
+		},
+		marshal:  xml.Marshal,
+		notFound: func(bucketName string) error { return BucketLoggingNotFound{Bucket: bucketName} },
+	},
+	bucketQuotaConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			var quota madmin.BucketQuota
+			if err := json.NewDecoder(reader).Decode(&quota); err != nil {
+				return nil, err
+			}
+			return &quota, nil
+		},
+		marshal:  json.Marshal,
+		notFound: func(bucketName string) error { return BucketQuotaNotFound{Bucket: bucketName} },
+	},
+	bucketReplicationConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return replication.ParseConfig(reader)
+		},
+		marshal:  xml.Marshal,
+		notFound: func(bucketName string) error { return BucketReplicationNotFound{Bucket: bucketName} },
+	},
+	bucketVersioningConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return versioning.ParseConfig(reader)
+		},
+		marshal: xml.Marshal,
+	},
+	bucketWebsiteConfig: {
+		parse: func(bucketName string, reader io.Reader) (interface{}, error) {
+			return website.ParseConfig(reader)
+		},
+		marshal:  xml.Marshal,
+		notFound: func(bucketName string) error { return BucketWebsiteNotFound{Bucket: bucketName} },
+	},
+}
+
+// bucketConfigInfo - version of a configuration file, which tells
+// refresh whether the file changed since it was last read.
+type bucketConfigInfo struct {
+	etag    string
+	modTime int64
+}
+
+// BucketMetadataSys - Bucket metadata subsystem, which caches the
+// configuration files of bucketConfigFormats of all buckets.
+type BucketMetadataSys struct {
+	sync.RWMutex
+	// Parsed configurations by bucket name and configuration file.
+	bucketMetadataMap map[string]map[string]interface{}
+	// Versions of the configuration files read by refresh.
+	configInfoMap map[string]map[string]bucketConfigInfo
+}
+
+// removeDeletedBuckets - removes cached configurations of buckets which
+// are deleted without a delete-bucket notification.
+func (sys *BucketMetadataSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.bucketMetadataMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.bucketMetadataMap, bucket)
+		}
+	}
+	for bucket := range sys.configInfoMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.configInfoMap, bucket)
+		}
+	}
+}
+
+// Set - sets parsed configuration file of given bucket name.
+func (sys *BucketMetadataSys) Set(bucketName, configFile string, config interface{}) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if sys.bucketMetadataMap[bucketName] == nil {
+		sys.bucketMetadataMap[bucketName] = make(map[string]interface{})
+	}
+	sys.bucketMetadataMap[bucketName][configFile] = config
+}
+
+// Get - gets parsed configuration file of given bucket name, nil if
+// the bucket has none.
+func (sys *BucketMetadataSys) Get(bucketName, configFile string) interface{} {
+	if sys == nil {
+		return nil
+	}
+
+	sys.RLock()
+	defer sys.RUnlock()
+
+	return sys.bucketMetadataMap[bucketName][configFile]
+}
+
+// Delete - removes configuration file of given bucket name.
+func (sys *BucketMetadataSys) Delete(bucketName, configFile string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketMetadataMap[bucketName], configFile)
+	if len(sys.bucketMetadataMap[bucketName]) == 0 {
+		delete(sys.bucketMetadataMap, bucketName)
+	}
+	delete(sys.configInfoMap[bucketName], configFile)
+	if len(sys.configInfoMap[bucketName]) == 0 {
+		delete(sys.configInfoMap, bucketName)
+	}
+}
+
+// Remove - removes all configurations of given bucket name.
+func (sys *BucketMetadataSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketMetadataMap, bucketName)
+	delete(sys.configInfoMap, bucketName)
+}
+
+// getConfigInfo - returns version of configuration file of given bucket
+// name last read by refresh.
+func (sys *BucketMetadataSys) getConfigInfo(bucketName, configFile string) (info bucketConfigInfo, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	info, ok = sys.configInfoMap[bucketName][configFile]
+	return info, ok
+}
+
+// setConfigInfo - records version of configuration file of given bucket
+// name read by refresh.
+func (sys *BucketMetadataSys) setConfigInfo(bucketName, configFile string, info bucketConfigInfo) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if sys.configInfoMap[bucketName] == nil {
+		sys.configInfoMap[bucketName] = make(map[string]bucketConfigInfo)
+	}
+	sys.configInfoMap[bucketName][configFile] = info
+}
+
+// GetACL - returns ACL of given bucket, nil if it has none.
+func (sys *BucketMetadataSys) GetACL(bucketName string) *acl.AccessControlPolicy {
+	aclPolicy, _ := sys.Get(bucketName, bucketACLConfig).(*acl.AccessControlPolicy)
+	return aclPolicy
+}
+
+// GetCORS - returns CORS configuration of given bucket, nil if it has none.
+func (sys *BucketMetadataSys) GetCORS(bucketName string) *cors.Config {
+	config, _ := sys.Get(bucketName, bucketCORSConfig).(*cors.Config)
+	return config
+}
+
+// GetEncryption - returns default encryption of given bucket, nil if it
+// has none.
+func (sys *BucketMetadataSys) GetEncryption(bucketName string) *sse.Config {
+	config, _ := sys.Get(bucketName, bucketEncryptionConfig).(*sse.Config)
+	return config
+}
+
+// GetLifecycle - returns lifecycle of given bucket, nil if it has none.
+func (sys *BucketMetadataSys) GetLifecycle(bucketName string) *lifecycle.Lifecycle {
+	lc, _ := sys.Get(bucketName, bucketLifecycleConfig).(*lifecycle.Lifecycle)
+	return lc
+}
+
+// GetLogging - returns logging configuration of given bucket, nil if it
+// has none.
+func (sys *BucketMetadataSys) GetLogging(bucketName string) *logging.Config {
+	config, _ := sys.Get(bucketName, bucketLoggingConfig).(*logging.Config)
+	return config
+}
+
+// GetQuota - returns quota of given bucket, nil if it has none.
+func (sys *BucketMetadataSys) GetQuota(bucketName string) *madmin.BucketQuota {
+	quota, _ := sys.Get(bucketName, bucketQuotaConfig).(*madmin.BucketQuota)
+	return quota
+}
+
+// GetReplication - returns replication configuration of given bucket,
+// nil if it has none.
+func (sys *BucketMetadataSys) GetReplication(bucketName string) *replication.Config {
+	config, _ := sys.Get(bucketName, bucketReplicationConfig).(*replication.Config)
+	return config
+}
+
+// GetVersioning - returns versioning configuration of given bucket, nil
+// if versioning was never configured.
+func (sys *BucketMetadataSys) GetVersioning(bucketName string) *versioning.Versioning {
+	v, _ := sys.Get(bucketName, bucketVersioningConfig).(*versioning.Versioning)
+	return v
+}
+
+// GetWebsite - returns website configuration of given bucket, nil if it
+// has none.
+func (sys *BucketMetadataSys) GetWebsite(bucketName string) *website.Config {
+	config, _ := sys.Get(bucketName, bucketWebsiteConfig).(*website.Config)
+	return config
+}
+
+// load - reads configuration file of given bucket name into the cache.
+func (sys *BucketMetadataSys) load(objAPI ObjectLayer, bucketName, configFile string) error {
+	config, err := readBucketConfig(objAPI, bucketName, configFile)
+	switch err {
+	case nil:
+		sys.Set(bucketName, configFile, config)
+	case errConfigNotFound:
+		sys.Delete(bucketName, configFile)
+	default:
+		return err
+	}
+	return nil
+}
+
+// Refresh BucketMetadataSys.
+func (sys *BucketMetadataSys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+	for _, bucket := range buckets {
+		if err = sys.refreshBucket(objAPI, bucket.Name); err != nil {
+			logger.LogIf(context.Background(), err)
+		}
+	}
+	return nil
+}
+
+// refreshBucket - reloads configuration files of given bucket name which
+// changed since refreshBucket last read them, and removes deleted ones.
+// A listing of the bucket configuration directory is used to find the
+// changed files, so unchanged configurations are not read again.
+func (sys *BucketMetadataSys) refreshBucket(objAPI ObjectLayer, bucketName string) error {
+	configInfos, err := listBucketConfigInfos(objAPI, bucketName)
+	if err != nil {
+		return err
+	}
+
+	for configFile := range bucketConfigFormats {
+		info, ok := configInfos[configFile]
+		if !ok {
+			sys.Delete(bucketName, configFile)
+			continue
+		}
+
+		if loaded, ok := sys.getConfigInfo(bucketName, configFile); ok && loaded == info {
+			continue
+		}
+
+		// Unreadable configurations keep their cached value and
+		// are read again by the next refresh.
+		if err = sys.load(objAPI, bucketName, configFile); err != nil {
+			logger.LogIf(context.Background(), err)
+			continue
+		}
+		if sys.Get(bucketName, configFile) != nil {
+			sys.setConfigInfo(bucketName, configFile, info)
+		}
+	}
+
+	return nil
+}
+
+// Init - initializes bucket metadata system from configuration files of
+// all buckets.
+func (sys *BucketMetadataSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	// Load BucketMetadataSys once during boot.
+	if err := sys.refresh(objAPI); err != nil {
+		return err
+	}
+
+	// Refresh BucketMetadataSys in background.
+	go func() {
+		ticker := time.NewTicker(globalRefreshBucketPolicyInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-ticker.C:
+				sys.refresh(objAPI)
+			}
+		}
+	}()
+	return nil
+}
+
+// NewBucketMetadataSys - creates new bucket metadata system.
+func NewBucketMetadataSys() *BucketMetadataSys {
+	return &BucketMetadataSys{
+		bucketMetadataMap: make(map[string]map[string]interface{}),
+		configInfoMap:     make(map[string]map[string]bucketConfigInfo),
+	}
+}
+
+// updateBucketConfig - updates cached configuration file of a bucket,
+// removed if config is nil, and makes all peers reload it.
+func updateBucketConfig(ctx context.Context, bucketName, configFile string, config interface{}) {
+	if config == nil {
+		globalBucketMetadataSys.Delete(bucketName, configFile)
+	} else {
+		globalBucketMetadataSys.Set(bucketName, configFile, config)
+	}
+
+	for nerr := range globalNotificationSys.LoadBucketMetadata(bucketName, configFile) {
+		logger.GetReqInfo(ctx).AppendTags("remotePeer", nerr.Host.Name)
+		logger.LogIf(ctx, nerr.Err)
+	}
+}
+
+// readBucketConfig - reads and parses configuration file of given bucket
+// name, errConfigNotFound is returned if the bucket has none.
+func readBucketConfig(objAPI ObjectLayer, bucketName, configFile string) (interface{}, error) {
+	format, ok := bucketConfigFormats[configFile]
+	if !ok {
+		return nil, errInvalidArgument
+	}
+
+	// Construct path to the configuration file of the given bucket.
+	reader, err := readConfig(context.Background(), objAPI, path.Join(bucketConfigPrefix, bucketName, configFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return format.parse(bucketName, reader)
+}
+
+// listBucketConfigInfos - returns versions of the configuration files
+// of bucketConfigFormats saved for given bucket name.
+func listBucketConfigInfos(objAPI ObjectLayer, bucketName string) (map[string]bucketConfigInfo, error) {
+	prefix := path.Join(bucketConfigPrefix, bucketName) + slashSeparator
+	configInfos := make(map[string]bucketConfigInfo)
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(context.Background(), minioMetaBucket, prefix, marker, slashSeparator, maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, objInfo := range result.Objects {
+			configFile := strings.TrimPrefix(objInfo.Name, prefix)
+			if _, ok := bucketConfigFormats[configFile]; ok {
+				configInfos[configFile] = bucketConfigInfo{
+					etag:    objInfo.ETag,
+					modTime: objInfo.ModTime.UnixNano(),
+				}
+			}
+		}
+
+		if !result.IsTruncated {
+			return configInfos, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// getBucketConfig - get configuration file for given bucket name.
+func getBucketConfig(objAPI ObjectLayer, bucketName, configFile string) (interface{}, error) {
+	config, err := readBucketConfig(objAPI, bucketName, configFile)
+	if err == errConfigNotFound {
+		if notFound := bucketConfigFormats[configFile].notFound; notFound != nil {
+			err = notFound(bucketName)
+		}
+	}
+
+	return config, err
+}
+
+func saveBucketConfig(objAPI ObjectLayer, bucketName, configFile string, config interface{}) error {
+	format, ok := bucketConfigFormats[configFile]
+	if !ok {
+		return errInvalidArgument
+	}
+
+	data, err := format.marshal(config)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(objAPI, path.Join(bucketConfigPrefix, bucketName, configFile), data)
+}
+
+func removeBucketConfig(ctx context.Context, objAPI ObjectLayer, bucketName, configFile string) error {
+	format, ok := bucketConfigFormats[configFile]
+	if !ok {
+		return errInvalidArgument
+	}
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, path.Join(bucketConfigPrefix, bucketName, configFile)); err != nil {
+		if _, ok := err.(ObjectNotFound); ok {
+			if format.notFound != nil {
+				return format.notFound(bucketName)
+			}
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
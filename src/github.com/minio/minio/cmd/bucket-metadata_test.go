@@ -0,0 +1,184 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/versioning"
+)
+
+func TestBucketMetadataSys(t *testing.T) {
+	bucketLifecycle := &lifecycle.Lifecycle{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "rule1",
+				Status:     lifecycle.Enabled,
+				Prefix:     "logs/",
+				Expiration: &lifecycle.Expiration{Days: 30},
+			},
+		},
+	}
+	quota := &madmin.BucketQuota{Quota: 10, Type: madmin.HardQuota}
+
+	sys := NewBucketMetadataSys()
+	sys.Set("mybucket", bucketLifecycleConfig, bucketLifecycle)
+	sys.Set("mybucket", bucketQuotaConfig, quota)
+	sys.Set("otherbucket", bucketQuotaConfig, quota)
+
+	if lc := sys.GetLifecycle("mybucket"); !reflect.DeepEqual(lc, bucketLifecycle) {
+		t.Fatalf("expected: %v, got: %v", bucketLifecycle, lc)
+	}
+	if q := sys.GetQuota("mybucket"); !reflect.DeepEqual(q, quota) {
+		t.Fatalf("expected: %v, got: %v", quota, q)
+	}
+	if v := sys.GetVersioning("mybucket"); v != nil {
+		t.Fatalf("expected no versioning, got: %v", v)
+	}
+
+	sys.Delete("mybucket", bucketLifecycleConfig)
+	if lc := sys.GetLifecycle("mybucket"); lc != nil {
+		t.Fatalf("expected lifecycle to be removed, got: %v", lc)
+	}
+	if q := sys.GetQuota("mybucket"); q == nil {
+		t.Fatal("expected quota to be kept")
+	}
+
+	sys.removeDeletedBuckets([]BucketInfo{{Name: "mybucket"}})
+	if q := sys.GetQuota("otherbucket"); q != nil {
+		t.Fatalf("expected configuration of deleted bucket to be removed, got: %v", q)
+	}
+
+	sys.Remove("mybucket")
+	if len(sys.bucketMetadataMap) != 0 {
+		t.Fatalf("expected no cached configuration, got: %v", sys.bucketMetadataMap)
+	}
+
+	// Nil system has no configuration.
+	var nilSys *BucketMetadataSys
+	if q := nilSys.GetQuota("mybucket"); q != nil {
+		t.Fatalf("expected no quota, got: %v", q)
+	}
+}
+
+// Wrapper for calling bucket metadata load tests for both XL and FS.
+func TestBucketMetadataSysLoad(t *testing.T) {
+	ExecObjectLayerTest(t, testBucketMetadataSysLoad)
+}
+
+// Tests validate loading of saved and removed bucket configuration files.
+func testBucketMetadataSysLoad(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	sys := NewBucketMetadataSys()
+	if err := sys.load(obj, bucketName, "unknown.xml"); err != errInvalidArgument {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, errInvalidArgument, err)
+	}
+
+	config := &versioning.Versioning{Status: versioning.Enabled}
+	if err := saveBucketConfig(obj, bucketName, bucketVersioningConfig, config); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if err := sys.load(obj, bucketName, bucketVersioningConfig); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if v := sys.GetVersioning(bucketName); v == nil || v.Status != versioning.Enabled {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, config, v)
+	}
+
+	if err := removeBucketConfig(context.Background(), obj, bucketName, bucketVersioningConfig); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if err := sys.load(obj, bucketName, bucketVersioningConfig); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if v := sys.GetVersioning(bucketName); v != nil {
+		t.Fatalf("%s: expected versioning to be removed, got: %v", instanceType, v)
+	}
+
+	if err := removeBucketConfig(context.Background(), obj, bucketName, bucketQuotaConfig); err != (BucketQuotaNotFound{Bucket: bucketName}) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, BucketQuotaNotFound{Bucket: bucketName}, err)
+	}
+}
+
+// Wrapper for calling bucket metadata refresh tests for both XL and FS.
+func TestBucketMetadataSysRefresh(t *testing.T) {
+	ExecObjectLayerTest(t, testBucketMetadataSysRefresh)
+}
+
+// Tests validate that refresh reads only changed configuration files.
+func testBucketMetadataSysRefresh(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	sys := NewBucketMetadataSys()
+	if err := saveBucketConfig(obj, bucketName, bucketVersioningConfig, &versioning.Versioning{Status: versioning.Enabled}); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if err := sys.refresh(obj); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if v := sys.GetVersioning(bucketName); v == nil || v.Status != versioning.Enabled {
+		t.Fatalf("%s: expected versioning to be enabled, got: %v", instanceType, v)
+	}
+
+	// An unchanged configuration file is not read again, so the cached
+	// value is kept.
+	sys.Set(bucketName, bucketVersioningConfig, &versioning.Versioning{Status: versioning.Suspended})
+	if err := sys.refresh(obj); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if v := sys.GetVersioning(bucketName); v == nil || v.Status != versioning.Suspended {
+		t.Fatalf("%s: expected cached versioning to be kept, got: %v", instanceType, v)
+	}
+
+	quota := &madmin.BucketQuota{Quota: 10, Type: madmin.HardQuota}
+	if err := saveBucketConfig(obj, bucketName, bucketQuotaConfig, quota); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if err := saveBucketConfig(obj, bucketName, bucketVersioningConfig, &versioning.Versioning{Status: versioning.Enabled}); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if err := sys.refresh(obj); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if q := sys.GetQuota(bucketName); !reflect.DeepEqual(q, quota) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, quota, q)
+	}
+	if v := sys.GetVersioning(bucketName); v == nil || v.Status != versioning.Enabled {
+		t.Fatalf("%s: expected changed versioning to be read, got: %v", instanceType, v)
+	}
+
+	if err := removeBucketConfig(context.Background(), obj, bucketName, bucketQuotaConfig); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if err := sys.refresh(obj); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if q := sys.GetQuota(bucketName); q != nil {
+		t.Fatalf("%s: expected quota to be removed, got: %v", instanceType, q)
+	}
+}
@@ -0,0 +1,494 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Interval between two scans recomputing the usage of all buckets.
+	bucketUsageScanInterval = time.Hour
+
+	// Interval between two attempts of the first scan.
+	bucketUsageRetryInterval = 10 * time.Second
+)
+
+// BucketQuotaSys - Bucket quota subsystem, which accounts the size and
+// number of objects of all buckets and enforces quotas cached by
+// BucketMetadataSys.
+//
+// Usage is kept by each server on its own: it is updated by writes and
+// deletes handled by this server only, and recomputed from all objects
+// by periodic scans. Between two scans it is therefore approximate, and
+// quotas may be exceeded by writes spread over several servers. Writes
+// replacing objects are accounted as new objects until the next scan,
+// to avoid looking up the replaced object. Usage of versioned buckets
+// counts all versions except delete markers.
+//
+// Writes to buckets with a hard quota are denied until the first scan
+// completes, as their usage is unknown before.
+type BucketQuotaSys struct {
+	sync.RWMutex
+	bucketUsageMap map[string]madmin.BucketUsage
+	// Bytes of hard quotas held by writes in progress.
+	reservedMap map[string]uint64
+	// Set once the first scan completed.
+	usageScanned bool
+
+	objAPI ObjectLayer
+	// Buckets whose oldest objects are being removed.
+	fifoBuckets set.StringSet
+}
+
+// removeDeletedBuckets - removes usage of buckets which are deleted
+// without a delete-bucket notification.
+func (sys *BucketQuotaSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.bucketUsageMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.bucketUsageMap, bucket)
+		}
+	}
+}
+
+// Remove - removes usage of given bucket name.
+func (sys *BucketQuotaSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketUsageMap, bucketName)
+}
+
+// getBucketQuota - returns quota config of given bucket name.
+func getBucketQuota(bucketName string) (quota madmin.BucketQuota, ok bool) {
+	q := globalBucketMetadataSys.GetQuota(bucketName)
+	if q == nil {
+		return quota, false
+	}
+	return *q, true
+}
+
+// Usage - returns the size and number of objects of given bucket name.
+func (sys *BucketQuotaSys) Usage(bucketName string) madmin.BucketUsage {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	return sys.bucketUsageMap[bucketName]
+}
+
+// AllUsage - returns the size and number of objects of all buckets.
+func (sys *BucketQuotaSys) AllUsage() map[string]madmin.BucketUsage {
+	if sys == nil {
+		return nil
+	}
+
+	sys.RLock()
+	defer sys.RUnlock()
+
+	usage := make(map[string]madmin.BucketUsage, len(sys.bucketUsageMap))
+	for bucket, u := range sys.bucketUsageMap {
+		usage[bucket] = u
+	}
+	return usage
+}
+
+// updateUsage - adds size bytes and objects to the usage of a bucket,
+// negative values are subtracted.
+func (sys *BucketQuotaSys) updateUsage(bucketName string, size, objects int64) madmin.BucketUsage {
+	sys.Lock()
+	defer sys.Unlock()
+
+	usage := sys.bucketUsageMap[bucketName]
+	usage.Size = addUsage(usage.Size, size)
+	usage.Objects = addUsage(usage.Objects, objects)
+	sys.bucketUsageMap[bucketName] = usage
+	return usage
+}
+
+// addUsage - adds delta to value without going below zero.
+func addUsage(value uint64, delta int64) uint64 {
+	if delta < 0 && uint64(-delta) > value {
+		return 0
+	}
+	return uint64(int64(value) + delta)
+}
+
+// objectSize - returns the size of an existing object of an unversioned
+// bucket with quota, -1 if the bucket has no quota, is versioned or the
+// object does not exist.
+func (sys *BucketQuotaSys) objectSize(ctx context.Context, objAPI ObjectLayer, bucket, object string) int64 {
+	if _, ok := getBucketQuota(bucket); !ok || getBucketVersioningStatus(bucket) != "" {
+		return -1
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return -1
+	}
+	return objInfo.Size
+}
+
+// reserve - holds size bytes of the hard quota limit of bucket for a
+// write replacing an object of replacedSize bytes, -1 when unknown, and
+// returns the number of bytes held.
+func (sys *BucketQuotaSys) reserve(bucket string, limit uint64, size, replacedSize int64) (uint64, error) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if !sys.usageScanned {
+		return 0, errServerNotInitialized
+	}
+
+	reserved := uint64(size)
+	if replacedSize > 0 {
+		reserved = addUsage(reserved, -replacedSize)
+	}
+	if sys.bucketUsageMap[bucket].Size+sys.reservedMap[bucket]+reserved > limit {
+		return 0, BucketQuotaExceeded{Bucket: bucket}
+	}
+	sys.reservedMap[bucket] += reserved
+	return reserved, nil
+}
+
+// unreserve - releases bytes of the hard quota of bucket held by reserve.
+func (sys *BucketQuotaSys) unreserve(bucket string, reserved uint64) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if sys.reservedMap[bucket] <= reserved {
+		delete(sys.reservedMap, bucket)
+	} else {
+		sys.reservedMap[bucket] -= reserved
+	}
+}
+
+// CheckQuota - returns BucketQuotaExceeded when writing size bytes to
+// object exceeds the hard quota of bucket, and errServerNotInitialized
+// while its usage is not known yet. Otherwise size bytes are held in the
+// quota, so that concurrent writes cannot exceed it together, until
+// release is called once the write is done. It also returns the size of
+// the object the write replaces, -1 when unknown, to be passed to
+// ObjectCreated.
+func (sys *BucketQuotaSys) CheckQuota(ctx context.Context, objAPI ObjectLayer, bucket, object string, size int64) (replacedSize int64, release func(), err error) {
+	replacedSize, release = -1, func() {}
+
+	quota, ok := getBucketQuota(bucket)
+	if !ok || quota.Type != madmin.HardQuota || size < 0 {
+		return replacedSize, release, nil
+	}
+
+	reserved, err := sys.reserve(bucket, quota.Quota, size, -1)
+	if _, ok = err.(BucketQuotaExceeded); ok {
+		// Only look up the replaced object when the write would
+		// exceed the quota without it.
+		if replacedSize = sys.objectSize(ctx, objAPI, bucket, object); replacedSize > 0 {
+			reserved, err = sys.reserve(bucket, quota.Quota, size, replacedSize)
+		}
+	}
+	if err != nil {
+		return replacedSize, release, err
+	}
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() { sys.unreserve(bucket, reserved) })
+	}
+	return replacedSize, release, nil
+}
+
+// ObjectCreated - accounts an object of size bytes written to bucket,
+// replacing an object of replacedSize bytes, -1 when unknown or no
+// object was replaced, and removes the oldest objects of buckets over their FIFO
+// quota.
+func (sys *BucketQuotaSys) ObjectCreated(bucket string, size, replacedSize int64) {
+	if sys == nil {
+		return
+	}
+
+	var usage madmin.BucketUsage
+	if replacedSize < 0 {
+		usage = sys.updateUsage(bucket, size, 1)
+	} else {
+		usage = sys.updateUsage(bucket, size-replacedSize, 0)
+	}
+
+	if quota, ok := getBucketQuota(bucket); ok && quota.Type == madmin.FIFOQuota && usage.Size > quota.Quota {
+		sys.RLock()
+		objAPI := sys.objAPI
+		sys.RUnlock()
+		if objAPI != nil {
+			go sys.enforceFIFOQuota(context.Background(), objAPI, bucket)
+		}
+	}
+}
+
+// ObjectRemoved - accounts an object of size bytes removed from bucket,
+// -1 when the size is unknown.
+func (sys *BucketQuotaSys) ObjectRemoved(bucket string, size int64) {
+	if sys == nil {
+		return
+	}
+
+	if size < 0 {
+		size = 0
+	}
+	sys.updateUsage(bucket, -size, -1)
+}
+
+// walkBucketUsage - calls fn with all objects of bucket accounted in its
+// usage, which are all versions of the objects of versioned buckets.
+func walkBucketUsage(ctx context.Context, objAPI ObjectLayer, bucket string, fn func(ObjectInfo)) error {
+	if getBucketVersioningStatus(bucket) != "" {
+		return walkBucketVersions(ctx, objAPI, bucket, fn)
+	}
+	return walkBucket(ctx, objAPI, bucket, fn)
+}
+
+// walkBucketVersions - calls fn with all versions of all objects of a
+// versioned bucket, except delete markers which hold no data.
+func walkBucketVersions(ctx context.Context, objAPI ObjectLayer, bucket string, fn func(ObjectInfo)) error {
+	keyMarker, versionIDMarker := "", ""
+	for {
+		result, err := objAPI.ListObjectVersions(ctx, bucket, "", keyMarker, versionIDMarker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, objInfo := range result.Objects {
+			if !objInfo.DeleteMarker {
+				fn(objInfo)
+			}
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+		keyMarker, versionIDMarker = result.NextKeyMarker, result.NextVersionIDMarker
+	}
+}
+
+// walkBucket - calls fn with all objects of bucket.
+func walkBucket(ctx context.Context, objAPI ObjectLayer, bucket string, fn func(ObjectInfo)) error {
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, bucket, "", marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, objInfo := range result.Objects {
+			fn(objInfo)
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+		marker = result.NextMarker
+		if marker == "" && len(result.Objects) > 0 {
+			marker = result.Objects[len(result.Objects)-1].Name
+		}
+	}
+}
+
+// enforceFIFOQuota - removes the oldest objects of bucket until its size
+// is below its FIFO quota, or the oldest versions of versioned buckets.
+func (sys *BucketQuotaSys) enforceFIFOQuota(ctx context.Context, objAPI ObjectLayer, bucket string) {
+	// Objects are never removed from WORM enabled servers.
+	if globalWORMEnabled {
+		return
+	}
+
+	sys.Lock()
+	if sys.fifoBuckets.Contains(bucket) {
+		sys.Unlock()
+		return
+	}
+	sys.fifoBuckets.Add(bucket)
+	sys.Unlock()
+
+	defer func() {
+		sys.Lock()
+		sys.fifoBuckets.Remove(bucket)
+		sys.Unlock()
+	}()
+
+	quota, ok := getBucketQuota(bucket)
+	if !ok || quota.Type != madmin.FIFOQuota {
+		return
+	}
+
+	ctx = logger.SetReqInfo(ctx, &logger.ReqInfo{BucketName: bucket})
+	var objects []ObjectInfo
+	var usage madmin.BucketUsage
+	err := walkBucketUsage(ctx, objAPI, bucket, func(objInfo ObjectInfo) {
+		objects = append(objects, objInfo)
+		usage.Size += uint64(objInfo.Size)
+		usage.Objects++
+	})
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].ModTime.Before(objects[j].ModTime)
+	})
+
+	for _, objInfo := range objects {
+		if usage.Size <= quota.Quota {
+			break
+		}
+
+		if objInfo.VersionID != "" {
+			_, err = objAPI.DeleteObjectVersion(ctx, bucket, objInfo.Name, objInfo.VersionID)
+		} else {
+			err = objAPI.DeleteObject(ctx, bucket, objInfo.Name)
+		}
+		if err != nil {
+			if !isErrObjectNotFound(err) && !isErrVersionNotFound(err) {
+				reqInfo := &logger.ReqInfo{BucketName: bucket, ObjectName: objInfo.Name}
+				logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+			}
+			continue
+		}
+
+		usage.Size -= uint64(objInfo.Size)
+		usage.Objects--
+
+		// Notify object deleted event.
+		sendEvent(eventArgs{
+			EventName:  event.ObjectRemovedDelete,
+			BucketName: bucket,
+			Object: ObjectInfo{
+				Name:      objInfo.Name,
+				VersionID: objInfo.VersionID,
+			},
+			Host: globalMinioHost,
+			Port: globalMinioPort,
+		})
+	}
+
+	sys.Lock()
+	sys.bucketUsageMap[bucket] = usage
+	sys.Unlock()
+}
+
+// scanUsage - recomputes the usage of all buckets, starting with buckets
+// with quota, and enforces FIFO quotas exceeded meanwhile.
+func (sys *BucketQuotaSys) scanUsage(objAPI ObjectLayer) error {
+	ctx := context.Background()
+
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+
+	sort.SliceStable(buckets, func(i, j int) bool {
+		_, iok := getBucketQuota(buckets[i].Name)
+		_, jok := getBucketQuota(buckets[j].Name)
+		return iok && !jok
+	})
+
+	for _, bucket := range buckets {
+		bucketCtx := logger.SetReqInfo(ctx, &logger.ReqInfo{BucketName: bucket.Name})
+		var usage madmin.BucketUsage
+		err = walkBucketUsage(bucketCtx, objAPI, bucket.Name, func(objInfo ObjectInfo) {
+			usage.Size += uint64(objInfo.Size)
+			usage.Objects++
+		})
+		if err != nil {
+			logger.LogIf(bucketCtx, err)
+			continue
+		}
+
+		sys.Lock()
+		sys.bucketUsageMap[bucket.Name] = usage
+		sys.Unlock()
+
+		if quota, ok := getBucketQuota(bucket.Name); ok && quota.Type == madmin.FIFOQuota && usage.Size > quota.Quota {
+			sys.enforceFIFOQuota(bucketCtx, objAPI, bucket.Name)
+		}
+	}
+
+	sys.Lock()
+	sys.usageScanned = true
+	sys.Unlock()
+	return nil
+}
+
+// Init - initializes bucket quota system to account the usage of all
+// buckets.
+func (sys *BucketQuotaSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	sys.Lock()
+	sys.objAPI = objAPI
+	sys.Unlock()
+
+	// Recompute usage of all buckets in background.
+	go func() {
+		// Retry the first scan until it completes.
+		for sys.scanUsage(objAPI) != nil {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-time.After(bucketUsageRetryInterval):
+			}
+		}
+
+		scanTicker := time.NewTicker(bucketUsageScanInterval)
+		defer scanTicker.Stop()
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-scanTicker.C:
+				sys.scanUsage(objAPI)
+			}
+		}
+	}()
+	return nil
+}
+
+// NewBucketQuotaSys - creates new bucket quota system.
+func NewBucketQuotaSys() *BucketQuotaSys {
+	return &BucketQuotaSys{
+		bucketUsageMap: make(map[string]madmin.BucketUsage),
+		reservedMap:    make(map[string]uint64),
+		fifoBuckets:    set.NewStringSet(),
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/versioning"
+)
+
+func TestBucketQuota(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketQuota, []string{"PutObject", "DeleteObject"})
+}
+
+func testBucketQuota(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	var err error
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Quotas need the object layer of the bucket.
+	globalBucketQuotaSys.Lock()
+	globalBucketQuotaSys.objAPI = obj
+	globalBucketQuotaSys.usageScanned = false
+	globalBucketQuotaSys.Unlock()
+	defer globalBucketQuotaSys.Remove(bucketName)
+	defer globalBucketMetadataSys.Remove(bucketName)
+
+	serve := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, url, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	checkUsage := func(size, objects uint64) {
+		t.Helper()
+		if usage := globalBucketQuotaSys.Usage(bucketName); usage.Size != size || usage.Objects != objects {
+			t.Fatalf("%s: Expected usage of %d bytes in %d objects, but instead found %d bytes in %d objects", instanceType, size, objects, usage.Size, usage.Objects)
+		}
+	}
+
+	// Writes to buckets with a hard quota are denied until usage is known.
+	globalBucketMetadataSys.Set(bucketName, bucketQuotaConfig, &madmin.BucketQuota{Quota: 10, Type: madmin.HardQuota})
+	rec := serve("PUT", getPutObjectURL("", bucketName, "object1"), []byte("123456"))
+	if rec.Code != http.StatusServiceUnavailable || !bytes.Contains(rec.Body.Bytes(), []byte("<Code>XMinioServerNotInitialized</Code>")) {
+		t.Fatalf("%s: Expected server not initialized error, but instead found status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	if err = globalBucketQuotaSys.scanUsage(obj); err != nil {
+		t.Fatal(err)
+	}
+	checkUsage(0, 0)
+
+	// Writes beyond a hard quota are denied.
+	if rec = serve("PUT", getPutObjectURL("", bucketName, "object1"), []byte("123456")); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutObject failed with status `%d`", instanceType, rec.Code)
+	}
+	checkUsage(6, 1)
+
+	rec = serve("PUT", getPutObjectURL("", bucketName, "object2"), []byte("123456"))
+	if rec.Code != http.StatusBadRequest || !bytes.Contains(rec.Body.Bytes(), []byte("<Code>XMinioQuotaExceeded</Code>")) {
+		t.Fatalf("%s: Expected quota exceeded error, but instead found status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+	checkUsage(6, 1)
+
+	// Overwrites are accounted by the size of the replaced object.
+	if rec = serve("PUT", getPutObjectURL("", bucketName, "object1"), []byte("12345678")); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutObject failed with status `%d`", instanceType, rec.Code)
+	}
+	checkUsage(8, 1)
+
+	// Concurrent writes cannot exceed a hard quota together.
+	_, release, err := globalBucketQuotaSys.CheckQuota(context.Background(), obj, bucketName, "object2", 2)
+	if err != nil {
+		t.Fatalf("%s: Expected the write to be allowed, but instead found %v", instanceType, err)
+	}
+	if _, _, err = globalBucketQuotaSys.CheckQuota(context.Background(), obj, bucketName, "object3", 1); err == nil {
+		t.Fatalf("%s: Expected concurrent write to exceed the quota", instanceType)
+	}
+	release()
+	release()
+	if _, release, err = globalBucketQuotaSys.CheckQuota(context.Background(), obj, bucketName, "object3", 2); err != nil {
+		t.Fatalf("%s: Expected the write to be allowed once released, but instead found %v", instanceType, err)
+	}
+	release()
+
+	if rec = serve("DELETE", getDeleteObjectURL("", bucketName, "object1"), nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: DeleteObject failed with status `%d`", instanceType, rec.Code)
+	}
+	checkUsage(0, 0)
+
+	// The oldest objects are removed to stay below a FIFO quota.
+	globalBucketMetadataSys.Set(bucketName, bucketQuotaConfig, &madmin.BucketQuota{Quota: 10, Type: madmin.FIFOQuota})
+	for _, object := range []string{"object1", "object2"} {
+		if rec = serve("PUT", getPutObjectURL("", bucketName, object), []byte("123456")); rec.Code != http.StatusOK {
+			t.Fatalf("%s: PutObject failed with status `%d`", instanceType, rec.Code)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	for i := 0; i < 100; i++ {
+		if _, err = obj.GetObjectInfo(context.Background(), bucketName, "object1"); isErrObjectNotFound(err) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !isErrObjectNotFound(err) {
+		t.Fatalf("%s: Expected the oldest object to be removed, but instead found %v", instanceType, err)
+	}
+	if _, err = obj.GetObjectInfo(context.Background(), bucketName, "object2"); err != nil {
+		t.Fatalf("%s: Expected the newest object to be kept, but instead found %v", instanceType, err)
+	}
+
+	if err = globalBucketQuotaSys.scanUsage(obj); err != nil {
+		t.Fatal(err)
+	}
+	checkUsage(6, 1)
+}
+
+func TestBucketQuotaFIFOVersions(t *testing.T) {
+	resetGlobalBucketSystems()
+	defer resetGlobalBucketSystems()
+
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	config := versioning.Versioning{Status: versioning.Enabled}
+	if err = obj.SetBucketVersioning(ctx, bucket, &config); err != nil {
+		t.Fatal(err)
+	}
+	globalBucketMetadataSys.Set(bucket, bucketVersioningConfig, &config)
+	globalBucketMetadataSys.Set(bucket, bucketQuotaConfig, &madmin.BucketQuota{Quota: 10, Type: madmin.FIFOQuota})
+
+	var versionIDs []string
+	for i := 0; i < 3; i++ {
+		objInfo, err := obj.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader([]byte("abcd")), 4, "", ""), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versionIDs = append(versionIDs, objInfo.VersionID)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Versions count in the usage, and the oldest one is removed.
+	if err = globalBucketQuotaSys.scanUsage(obj); err != nil {
+		t.Fatal(err)
+	}
+	if usage := globalBucketQuotaSys.Usage(bucket); usage.Size != 8 || usage.Objects != 2 {
+		t.Fatalf("Expected usage of 8 bytes in 2 versions, but instead found %d bytes in %d versions", usage.Size, usage.Objects)
+	}
+
+	result, err := obj.ListObjectVersions(ctx, bucket, "", "", "", "", maxObjectList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 2 || result.Objects[0].VersionID != versionIDs[2] || result.Objects[1].VersionID != versionIDs[1] {
+		t.Fatalf("Expected the newest versions %v to be kept, but instead found %v", versionIDs[1:], result.Objects)
+	}
+}
@@ -0,0 +1,253 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/replication"
+)
+
+// newTestReplicationTarget - returns a server accepting requests of
+// replication clients, which are sent to the returned channel. The first
+// failDeletes deletes are denied.
+func newTestReplicationTarget(failDeletes int) (*httptest.Server, chan *http.Request) {
+	var mutex sync.Mutex
+	reqCh := make(chan *http.Request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			writeSuccessResponseXML(w, encodeResponse(LocationResponse{Location: globalMinioDefaultRegion}))
+			return
+		}
+		reqCh <- r
+		switch r.Method {
+		case http.MethodPut:
+			w.Header().Set("ETag", `"`+emptyETag+`"`)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			mutex.Lock()
+			defer mutex.Unlock()
+			if failDeletes > 0 {
+				failDeletes--
+				writeErrorResponse(w, ErrAccessDenied, r.URL)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	return server, reqCh
+}
+
+func TestBucketReplicationHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketReplicationHandlers, []string{"PutBucketReplication", "GetBucketReplication", "DeleteBucketReplication", "PutObject", "DeleteObject"})
+}
+
+func testBucketReplicationHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	var err error
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replication needs the object layer of the bucket.
+	if err = globalBucketReplicationSys.Init(obj); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failed replication tasks are retried.
+	defer func(min time.Duration) {
+		replicationMinRetryInterval = min
+	}(replicationMinRetryInterval)
+	replicationMinRetryInterval = 10 * time.Millisecond
+
+	server, reqCh := newTestReplicationTarget(1)
+	defer server.Close()
+
+	globalServerConfig.Replication = replication.Targets{
+		"1": {
+			Enable:    true,
+			Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+			AccessKey: "minio",
+			SecretKey: "minio123",
+		},
+	}
+
+	serve := func(method, url string, body []byte, header http.Header) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, url, err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	replicationURL := getBucketReplicationURL("", bucketName)
+
+	if rec := serve("GET", replicationURL, nil, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	unknownTargetConfig := []byte(`<ReplicationConfiguration><Rule><ID>photos</ID><Status>Enabled</Status><Prefix>photos/</Prefix><Destination><Bucket>arn:minio:replication::2:destination</Bucket></Destination></Rule></ReplicationConfiguration>`)
+	if rec := serve("PUT", replicationURL, unknownTargetConfig, nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	config := []byte(`<ReplicationConfiguration><Rule><ID>photos</ID><Status>Enabled</Status><Prefix>photos/</Prefix><Destination><Bucket>arn:minio:replication::1:destination</Bucket></Destination></Rule></ReplicationConfiguration>`)
+	if rec := serve("PUT", replicationURL, config, nil); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutBucketReplication failed with status `%d`", instanceType, rec.Code)
+	}
+	rec := serve("GET", replicationURL, nil, nil)
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte("<Bucket>arn:minio:replication::1:destination</Bucket>")) {
+		t.Fatalf("%s: GetBucketReplication failed with status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	// Objects matching the rule are replicated as replicas.
+	if rec = serve("PUT", getPutObjectURL("", bucketName, "photos/image.png"), []byte("image"), nil); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutObject failed with status `%d`", instanceType, rec.Code)
+	}
+	select {
+	case req := <-reqCh:
+		if req.Method != http.MethodPut || req.URL.Path != "/destination/photos/image.png" {
+			t.Fatalf("%s: Unexpected replication request %s %s", instanceType, req.Method, req.URL.Path)
+		}
+		if status := req.Header.Get(amzReplicationStatus); status != replication.StatusReplica {
+			t.Fatalf("%s: Expected replication status `%s`, but instead found `%s`", instanceType, replication.StatusReplica, status)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("%s: Object was not replicated", instanceType)
+	}
+
+	var status string
+	for i := 0; i < 100; i++ {
+		objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, "photos/image.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status = objInfo.UserDefined[amzReplicationStatus]; status == replication.StatusCompleted {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if status != replication.StatusCompleted {
+		t.Fatalf("%s: Expected replication status `%s`, but instead found `%s`", instanceType, replication.StatusCompleted, status)
+	}
+
+	// Objects not matching the rule and replicas are not replicated.
+	if rec = serve("PUT", getPutObjectURL("", bucketName, "docs/file.txt"), []byte("file"), nil); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutObject failed with status `%d`", instanceType, rec.Code)
+	}
+	header := http.Header{amzReplicationStatus: []string{replication.StatusReplica}}
+	if rec = serve("PUT", getPutObjectURL("", bucketName, "photos/replica.png"), []byte("replica"), header); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutObject failed with status `%d`", instanceType, rec.Code)
+	}
+	testCases := []struct {
+		object         string
+		expectedStatus string
+	}{
+		{"docs/file.txt", ""},
+		{"photos/replica.png", replication.StatusReplica},
+	}
+	for i, testCase := range testCases {
+		objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, testCase.object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := objInfo.UserDefined[amzReplicationStatus]; status != testCase.expectedStatus {
+			t.Errorf("%s: Test %d: Expected replication status `%s`, but instead found `%s`", instanceType, i+1, testCase.expectedStatus, status)
+		}
+	}
+
+	// The replica status is ignored from accounts not allowed to
+	// replicate objects.
+	userCred, err := auth.GetNewCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetUser(obj, userCred.AccessKey, madmin.UserInfo{SecretKey: userCred.SecretKey}); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetPolicy(obj, "put", newCannedPolicy(policy.PutObjectAction)); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetUserPolicy(obj, userCred.AccessKey, "put"); err != nil {
+		t.Fatal(err)
+	}
+	req, err := newTestSignedRequestV4("PUT", getPutObjectURL("", bucketName, "photos/user.png"), int64(len("user")), bytes.NewReader([]byte("user")), userCred.AccessKey, userCred.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(amzReplicationStatus, replication.StatusReplica)
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutObject failed with status `%d`", instanceType, rec.Code)
+	}
+	select {
+	case req := <-reqCh:
+		if req.Method != http.MethodPut || req.URL.Path != "/destination/photos/user.png" {
+			t.Fatalf("%s: Unexpected replication request %s %s", instanceType, req.Method, req.URL.Path)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("%s: Object of an account not allowed to replicate objects was not replicated", instanceType)
+	}
+
+	// Encrypted objects cannot be replicated.
+	metadata := map[string]string{ServerSideEncryptionSealAlgorithm: SSESealAlgorithmDareSha256}
+	setReplicationStatus(context.Background(), req, bucketName, "photos/encrypted.png", metadata)
+	if status := metadata[amzReplicationStatus]; status != replication.StatusFailed {
+		t.Fatalf("%s: Expected replication status `%s`, but instead found `%s`", instanceType, replication.StatusFailed, status)
+	}
+
+	// Deletes of objects matching the rule are replicated, and retried
+	// when they fail.
+	if rec = serve("DELETE", getDeleteObjectURL("", bucketName, "photos/image.png"), nil, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: DeleteObject failed with status `%d`", instanceType, rec.Code)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case req := <-reqCh:
+			if req.Method != http.MethodDelete || req.URL.Path != "/destination/photos/image.png" {
+				t.Fatalf("%s: Unexpected replication request %s %s", instanceType, req.Method, req.URL.Path)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s: Object deletion was not replicated", instanceType)
+		}
+	}
+
+	if rec = serve("DELETE", replicationURL, nil, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: DeleteBucketReplication failed with status `%d`", instanceType, rec.Code)
+	}
+	if rec = serve("GET", replicationURL, nil, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+	if globalBucketMetadataSys.GetReplication(bucketName) != nil {
+		t.Fatalf("%s: Expected replication configuration to be removed", instanceType)
+	}
+}
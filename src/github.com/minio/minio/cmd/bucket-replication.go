@@ -0,0 +1,530 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	miniogo "github.com/minio/minio-go"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/replication"
+)
+
+const (
+	// Replication status of an object, stored in its metadata.
+	amzReplicationStatus = "X-Amz-Replication-Status"
+
+	// Number of objects replicated concurrently.
+	replicationWorkers = 4
+
+	// Lock file under minioMetaBucket held while a node resumes
+	// replication, so that objects are queued by one node at a time.
+	replicationResumeLockFile = "replication-resume.lock"
+)
+
+// Timeout to take the replication resume lock, failing to take it means
+// another node is resuming replication.
+var replicationResumeTimeout = newDynamicTimeout(60*time.Second, time.Second)
+
+// Intervals of retrying a failed replication task. The interval doubles
+// on each failure up to the maximum.
+var (
+	replicationMinRetryInterval = time.Second
+	replicationMaxRetryInterval = 5 * time.Minute
+)
+
+// replicationTask - object to replicate, or whose deletion to replicate.
+type replicationTask struct {
+	bucket string
+	object string
+	delete bool
+}
+
+// BucketReplicationSys - Bucket replication subsystem, which replicates
+// objects of buckets having replication configuration in
+// BucketMetadataSys.
+type BucketReplicationSys struct {
+	sync.RWMutex
+
+	// Replication clients of remote targets by target ID.
+	clientMutex sync.Mutex
+	clients     map[string]replicationClient
+
+	objAPI ObjectLayer
+
+	// Tasks waiting for a worker in queue order, the set of them so
+	// that a task is queued once, and the retry intervals of failed
+	// tasks.
+	queueMutex     sync.Mutex
+	tasks          []replicationTask
+	queued         map[replicationTask]struct{}
+	retryIntervals map[replicationTask]time.Duration
+	taskCh         chan struct{}
+	workerOnce     sync.Once
+}
+
+// Init - initializes bucket replication system and resumes replication
+// of objects left pending or failed.
+func (sys *BucketReplicationSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	configs := make(map[string]replication.Config)
+	for _, bucket := range buckets {
+		if config := globalBucketMetadataSys.GetReplication(bucket.Name); config != nil {
+			configs[bucket.Name] = *config
+		}
+	}
+
+	sys.Lock()
+	sys.objAPI = objAPI
+	sys.Unlock()
+
+	// Retry replication of objects left pending or failed.
+	if len(configs) > 0 {
+		go sys.resume(objAPI, configs)
+	}
+	return nil
+}
+
+// NewBucketReplicationSys - creates new bucket replication system.
+func NewBucketReplicationSys() *BucketReplicationSys {
+	return &BucketReplicationSys{
+		clients:        make(map[string]replicationClient),
+		queued:         make(map[replicationTask]struct{}),
+		retryIntervals: make(map[replicationTask]time.Duration),
+		taskCh:         make(chan struct{}, 1),
+	}
+}
+
+// match - returns the replication rule of object in bucket, if any.
+func (sys *BucketReplicationSys) match(bucket, object string) (replication.Rule, bool) {
+	if sys == nil {
+		return replication.Rule{}, false
+	}
+
+	config := globalBucketMetadataSys.GetReplication(bucket)
+	if config == nil {
+		return replication.Rule{}, false
+	}
+	return config.Match(object)
+}
+
+// Replicate - queues an object pending replication to be copied to its
+// destination.
+func (sys *BucketReplicationSys) Replicate(bucket string, objInfo ObjectInfo) {
+	if objInfo.UserDefined[amzReplicationStatus] != replication.StatusPending {
+		return
+	}
+	sys.queue(replicationTask{bucket: bucket, object: objInfo.Name})
+}
+
+// ReplicateDelete - queues the deletion of an object to be replicated to
+// its destination.
+func (sys *BucketReplicationSys) ReplicateDelete(bucket, object string) {
+	if _, ok := sys.match(bucket, object); !ok {
+		return
+	}
+	sys.queue(replicationTask{bucket: bucket, object: object, delete: true})
+}
+
+// queue - queues a replication task unless it is queued already,
+// starting the replication workers on first use.
+func (sys *BucketReplicationSys) queue(task replicationTask) {
+	if sys == nil {
+		return
+	}
+
+	sys.RLock()
+	objAPI := sys.objAPI
+	sys.RUnlock()
+	if objAPI == nil {
+		return
+	}
+
+	sys.workerOnce.Do(func() {
+		for i := 0; i < replicationWorkers; i++ {
+			go sys.worker()
+		}
+	})
+
+	sys.queueMutex.Lock()
+	defer sys.queueMutex.Unlock()
+
+	if _, ok := sys.queued[task]; ok {
+		return
+	}
+	sys.queued[task] = struct{}{}
+	sys.tasks = append(sys.tasks, task)
+
+	select {
+	case sys.taskCh <- struct{}{}:
+	default:
+	}
+}
+
+// next - removes the first queued task, if any.
+func (sys *BucketReplicationSys) next() (task replicationTask, ok bool) {
+	sys.queueMutex.Lock()
+	defer sys.queueMutex.Unlock()
+
+	if len(sys.tasks) == 0 {
+		return task, false
+	}
+
+	task = sys.tasks[0]
+	sys.tasks[0] = replicationTask{}
+	sys.tasks = sys.tasks[1:]
+	delete(sys.queued, task)
+
+	// Wake up another worker for the remaining tasks.
+	if len(sys.tasks) > 0 {
+		select {
+		case sys.taskCh <- struct{}{}:
+		default:
+		}
+	}
+	return task, true
+}
+
+// retry - queues a failed task again after its retry interval.
+func (sys *BucketReplicationSys) retry(task replicationTask) {
+	sys.queueMutex.Lock()
+	interval := sys.retryIntervals[task] * 2
+	if interval < replicationMinRetryInterval {
+		interval = replicationMinRetryInterval
+	}
+	if interval > replicationMaxRetryInterval {
+		interval = replicationMaxRetryInterval
+	}
+	sys.retryIntervals[task] = interval
+	sys.queueMutex.Unlock()
+
+	time.AfterFunc(interval, func() {
+		sys.queue(task)
+	})
+}
+
+// worker - replicates queued objects until the server stops. Failed
+// tasks are retried with backoff.
+func (sys *BucketReplicationSys) worker() {
+	for {
+		task, ok := sys.next()
+		if !ok {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-sys.taskCh:
+			}
+			continue
+		}
+
+		sys.RLock()
+		objAPI := sys.objAPI
+		sys.RUnlock()
+
+		reqInfo := &logger.ReqInfo{BucketName: task.bucket, ObjectName: task.object}
+		ctx := logger.SetReqInfo(context.Background(), reqInfo)
+
+		var err error
+		if task.delete {
+			err = sys.replicateDelete(task.bucket, task.object)
+		} else {
+			err = sys.replicateObject(ctx, objAPI, task.bucket, task.object)
+		}
+		if err != nil {
+			logger.LogIf(ctx, err)
+			sys.retry(task)
+			continue
+		}
+
+		sys.queueMutex.Lock()
+		delete(sys.retryIntervals, task)
+		sys.queueMutex.Unlock()
+	}
+}
+
+// resume - queues objects of buckets with replication configuration
+// whose replication was left pending or failed by the last run of the
+// server. The objects are queued by one node at a time in the cluster.
+func (sys *BucketReplicationSys) resume(objAPI ObjectLayer, configs map[string]replication.Config) {
+	ctx := context.Background()
+
+	// Unable to hold the lock means another node is resuming.
+	resumeLock := globalNSMutex.NewNSLock(minioMetaBucket, replicationResumeLockFile)
+	if err := resumeLock.GetLock(replicationResumeTimeout); err != nil {
+		if _, ok := err.(OperationTimedOut); !ok {
+			logger.LogIf(ctx, err)
+		}
+		return
+	}
+	defer resumeLock.Unlock()
+
+	for bucket, config := range configs {
+		for _, rule := range config.Rules {
+			if rule.Status != replication.Enabled {
+				continue
+			}
+
+			marker := ""
+			for {
+				result, err := objAPI.ListObjects(ctx, bucket, rule.Prefix, marker, "", maxObjectList)
+				if err != nil {
+					logger.LogIf(ctx, err)
+					break
+				}
+
+				for _, objInfo := range result.Objects {
+					if objInfo, err = objAPI.GetObjectInfo(ctx, bucket, objInfo.Name); err != nil {
+						continue
+					}
+
+					switch objInfo.UserDefined[amzReplicationStatus] {
+					case replication.StatusPending, replication.StatusFailed:
+						sys.queue(replicationTask{bucket: bucket, object: objInfo.Name})
+					}
+				}
+
+				if !result.IsTruncated {
+					break
+				}
+				marker = result.NextMarker
+				if marker == "" && len(result.Objects) > 0 {
+					marker = result.Objects[len(result.Objects)-1].Name
+				}
+			}
+		}
+	}
+}
+
+// replicaTransport - marks objects written by replication clients as
+// replicas, so that the destination does not replicate them again.
+type replicaTransport struct {
+	http.RoundTripper
+}
+
+// RoundTrip - sets the replica status header on requests creating objects.
+func (t replicaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut || req.Method == http.MethodPost {
+		// Requests must not be modified by round trippers.
+		r := new(http.Request)
+		*r = *req
+		r.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			r.Header[k] = v
+		}
+		r.Header.Set(amzReplicationStatus, replication.StatusReplica)
+		req = r
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// replicationClient - client of a remote target.
+type replicationClient struct {
+	target replication.Target
+	client *miniogo.Client
+}
+
+// getClient - returns the replication client of the remote target.
+func (sys *BucketReplicationSys) getClient(targetID string) (*miniogo.Client, error) {
+	globalServerConfigMu.RLock()
+	target, ok := globalServerConfig.Replication[targetID]
+	globalServerConfigMu.RUnlock()
+	if !ok || !target.Enable {
+		return nil, fmt.Errorf("replication target %v is not configured", targetID)
+	}
+
+	sys.clientMutex.Lock()
+	defer sys.clientMutex.Unlock()
+
+	// Clients are created again when their target changes.
+	if rc, ok := sys.clients[targetID]; ok && rc.target == target {
+		return rc.client, nil
+	}
+
+	client, err := miniogo.New(target.Endpoint, target.AccessKey, target.SecretKey, target.Secure)
+	if err != nil {
+		return nil, err
+	}
+	client.SetCustomTransport(replicaTransport{NewCustomHTTPTransport()})
+
+	sys.clients[targetID] = replicationClient{target, client}
+	return client, nil
+}
+
+// replicateObject - copies the object to the destination of its
+// replication rule, and records the replication status in its metadata.
+func (sys *BucketReplicationSys) replicateObject(ctx context.Context, objAPI ObjectLayer, bucket, object string) error {
+	rule, ok := sys.match(bucket, object)
+	if !ok {
+		return nil
+	}
+
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			// The object was removed before it was replicated.
+			return nil
+		}
+		return err
+	}
+
+	switch objInfo.UserDefined[amzReplicationStatus] {
+	case replication.StatusPending, replication.StatusFailed:
+	default:
+		return nil
+	}
+
+	// Encrypted objects stay failed, as they cannot be replicated.
+	if objInfo.IsEncrypted() {
+		return nil
+	}
+
+	status := replication.StatusCompleted
+	err = sys.putObject(ctx, objAPI, rule, objInfo)
+	if err != nil {
+		status = replication.StatusFailed
+	}
+
+	// The status is not recorded if the object was overwritten meanwhile.
+	if serr := objAPI.PutObjectReplicationStatus(ctx, bucket, object, objInfo.ETag, status); serr != nil {
+		if _, ok := serr.(InvalidETag); !ok && !isErrObjectNotFound(serr) && err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// putObject - writes the object to the destination bucket of rule.
+func (sys *BucketReplicationSys) putObject(ctx context.Context, objAPI ObjectLayer, rule replication.Rule, objInfo ObjectInfo) error {
+	arn, err := rule.Destination.ARN()
+	if err != nil {
+		return err
+	}
+
+	client, err := sys.getClient(arn.TargetID)
+	if err != nil {
+		return err
+	}
+
+	opts := miniogo.PutObjectOptions{
+		UserMetadata:    make(map[string]string),
+		ContentType:     objInfo.ContentType,
+		ContentEncoding: objInfo.ContentEncoding,
+		StorageClass:    rule.Destination.StorageClass,
+	}
+	for k, v := range objInfo.UserDefined {
+		switch {
+		case strings.HasPrefix(strings.ToLower(k), "x-amz-meta-"):
+			opts.UserMetadata[k] = v
+		case strings.EqualFold(k, "Cache-Control"):
+			opts.CacheControl = v
+		case strings.EqualFold(k, "Content-Disposition"):
+			opts.ContentDisposition = v
+		case strings.EqualFold(k, "Content-Language"):
+			opts.ContentLanguage = v
+		}
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(objAPI.GetObject(ctx, objInfo.Bucket, objInfo.Name, 0, objInfo.Size, pipeWriter, objInfo.ETag))
+	}()
+	defer pipeReader.Close()
+
+	_, err = client.PutObjectWithContext(ctx, arn.Bucket, objInfo.Name, pipeReader, objInfo.Size, opts)
+	return err
+}
+
+// replicateDelete - removes the object from the destination of its
+// replication rule.
+func (sys *BucketReplicationSys) replicateDelete(bucket, object string) error {
+	rule, ok := sys.match(bucket, object)
+	if !ok {
+		return nil
+	}
+
+	arn, err := rule.Destination.ARN()
+	if err != nil {
+		return err
+	}
+
+	client, err := sys.getClient(arn.TargetID)
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveObject(arn.Bucket, object)
+}
+
+// isReplicaRequest - returns whether the request writes a replica by
+// replication from another server. The replica status is trusted only
+// from the owner and accounts allowed to replicate objects, so that
+// clients cannot keep their objects from being replicated.
+func isReplicaRequest(ctx context.Context, r *http.Request, bucket, object string) bool {
+	if r.Header.Get(amzReplicationStatus) != replication.StatusReplica {
+		return false
+	}
+
+	accessKey := getRequestAccessKey(r)
+	if accessKey == "" {
+		return false
+	}
+
+	return accessKey == globalServerConfig.GetCredential().AccessKey || isAccountAllowed(ctx, policy.Args{
+		AccountName:     accessKey,
+		Action:          policy.ReplicateObjectAction,
+		BucketName:      bucket,
+		ConditionValues: getConditionValues(r, ""),
+		ObjectName:      object,
+	})
+}
+
+// setReplicationStatus - marks a new object as pending replication when
+// a replication rule of its bucket matches, or as a replica when it is
+// written by replication from another server. Encrypted objects cannot
+// be replicated and are marked as failed.
+func setReplicationStatus(ctx context.Context, r *http.Request, bucket, object string, metadata map[string]string) {
+	delete(metadata, amzReplicationStatus)
+
+	if isReplicaRequest(ctx, r, bucket, object) {
+		metadata[amzReplicationStatus] = replication.StatusReplica
+		return
+	}
+
+	if _, ok := globalBucketReplicationSys.match(bucket, object); !ok {
+		return
+	}
+
+	metadata[amzReplicationStatus] = replication.StatusPending
+	if objInfo := (ObjectInfo{UserDefined: metadata}); objInfo.IsEncrypted() {
+		metadata[amzReplicationStatus] = replication.StatusFailed
+	}
+}
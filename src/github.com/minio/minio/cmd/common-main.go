@@ -124,6 +124,7 @@ func handleCommonEnvVars() {
 	}
 
 	globalDomainName, globalIsEnvDomainName = os.LookupEnv("MINIO_DOMAIN")
+	globalWebsiteDomainName = os.Getenv("MINIO_WEBSITE_DOMAIN")
 
 	if drives := os.Getenv("MINIO_CACHE_DRIVES"); drives != "" {
 		driveList, err := parseCacheDrives(strings.Split(drives, cacheEnvDelimiter))
@@ -197,4 +198,18 @@ func handleCommonEnvVars() {
 		globalIsEnvWORM = true
 		globalWORMEnabled = bool(wormFlag)
 	}
+
+	// Initialize the KMS for SSE-S3 and SSE-KMS, if any.
+	KMS, keyID, err := loadKMS()
+	if err != nil {
+		logger.Fatal(uiErrInvalidKMSConfig(err), "Unable to initialize the KMS")
+	}
+	globalKMS, globalKMSKeyID = KMS, keyID
+
+	// Initialize the validator of web identity tokens, if any.
+	stsValidator, err := loadSTSValidator()
+	if err != nil {
+		logger.Fatal(uiErrInvalidSTSConfig(err), "Unable to initialize the web identity validator")
+	}
+	globalSTSValidator = stsValidator
 }
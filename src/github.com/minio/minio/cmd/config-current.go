@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -24,10 +25,12 @@ import (
 
 	"github.com/minio/minio/cmd/logger"
 
+	"github.com/minio/minio/pkg/audit"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/event/target"
 	"github.com/minio/minio/pkg/quick"
+	"github.com/minio/minio/pkg/replication"
 )
 
 // Steps to move from version N to version N+1
@@ -39,9 +42,9 @@ import (
 // 6. Make changes in config-current_test.go for any test change
 
 // Config version
-const serverConfigVersion = "25"
+const serverConfigVersion = "27"
 
-type serverConfig = serverConfigV25
+type serverConfig = serverConfigV27
 
 var (
 	// globalServerConfig server config.
@@ -170,6 +173,12 @@ func (s *serverConfig) ConfigDiff(t *serverConfig) string {
 		return "MySQL Notification configuration differs"
 	case !reflect.DeepEqual(s.Notify.MQTT, t.Notify.MQTT):
 		return "MQTT Notification configuration differs"
+	case !reflect.DeepEqual(s.Audit.Webhook, t.Audit.Webhook):
+		return "Webhook Audit configuration differs"
+	case !reflect.DeepEqual(s.Audit.File, t.Audit.File):
+		return "File Audit configuration differs"
+	case !reflect.DeepEqual(s.Replication, t.Replication):
+		return "Replication configuration differs"
 	case reflect.DeepEqual(s, t):
 		return ""
 	default:
@@ -220,6 +229,16 @@ func newServerConfig() *serverConfig {
 	srvCfg.Notify.Webhook = make(map[string]target.WebhookArgs)
 	srvCfg.Notify.Webhook["1"] = target.WebhookArgs{}
 
+	// Make sure to initialize audit configs.
+	srvCfg.Audit.Webhook = make(map[string]audit.WebhookArgs)
+	srvCfg.Audit.Webhook["1"] = audit.WebhookArgs{}
+	srvCfg.Audit.File = make(map[string]audit.FileArgs)
+	srvCfg.Audit.File["1"] = audit.FileArgs{}
+
+	// Make sure to initialize replication targets.
+	srvCfg.Replication = make(replication.Targets)
+	srvCfg.Replication["1"] = replication.Target{}
+
 	srvCfg.Cache.Drives = make([]string, 0)
 	srvCfg.Cache.Exclude = make([]string, 0)
 	srvCfg.Cache.Expiry = globalCacheExpiry
@@ -294,6 +313,10 @@ func getValidConfig() (*serverConfig, error) {
 		return nil, errors.New("invalid credential in config file " + getConfigFile())
 	}
 
+	if err := srvCfg.Replication.Validate(); err != nil {
+		return nil, err
+	}
+
 	return srvCfg, nil
 }
 
@@ -468,8 +491,11 @@ func getNotificationTargets(config *serverConfig) (*event.TargetList, error) {
 
 	for id, args := range config.Notify.Webhook {
 		if args.Enable {
-			newTarget := target.NewWebhookTarget(id, args)
-			if err := targetList.Add(newTarget); err != nil {
+			newTarget, err := target.NewWebhookTarget(id, args)
+			if err != nil {
+				return nil, err
+			}
+			if err = targetList.Add(newTarget); err != nil {
 				return nil, err
 			}
 		}
@@ -477,3 +503,36 @@ func getNotificationTargets(config *serverConfig) (*event.TargetList, error) {
 
 	return targetList, nil
 }
+
+// getAuditLogger - returns audit logger which sends entries to the enabled
+// audit targets in serverConfig.
+func getAuditLogger(config *serverConfig) (*audit.Logger, error) {
+	if err := config.Audit.Validate(); err != nil {
+		return nil, err
+	}
+
+	auditLogger := audit.NewLogger()
+	logError := func(err error) {
+		logger.LogIf(context.Background(), err)
+	}
+
+	for id, args := range config.Audit.Webhook {
+		if args.Enable {
+			args.RootCAs = globalRootCAs
+			auditLogger.AddTarget(audit.NewWebhookTarget("webhook:"+id, args, logError))
+		}
+	}
+
+	for id, args := range config.Audit.File {
+		if args.Enable {
+			newTarget, err := audit.NewFileTarget("file:"+id, args)
+			if err != nil {
+				auditLogger.Close()
+				return nil, err
+			}
+			auditLogger.AddTarget(newTarget)
+		}
+	}
+
+	return auditLogger, nil
+}
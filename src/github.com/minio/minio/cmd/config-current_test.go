@@ -249,8 +249,8 @@ func TestConfigDiff(t *testing.T) {
 		{&serverConfig{}, nil, "Given configuration is empty"},
 		// 2
 		{
-			&serverConfig{Credential: auth.Credentials{"u1", "p1"}},
-			&serverConfig{Credential: auth.Credentials{"u1", "p2"}},
+			&serverConfig{Credential: auth.Credentials{AccessKey: "u1", SecretKey: "p1"}},
+			&serverConfig{Credential: auth.Credentials{AccessKey: "u1", SecretKey: "p2"}},
 			"Credential configuration differs",
 		},
 		// 3
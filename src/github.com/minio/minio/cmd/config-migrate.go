@@ -22,11 +22,13 @@ import (
 	"path/filepath"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/audit"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/event/target"
 	xnet "github.com/minio/minio/pkg/net"
 	"github.com/minio/minio/pkg/quick"
+	"github.com/minio/minio/pkg/replication"
 )
 
 // DO NOT EDIT following message template, please open a github issue to discuss instead.
@@ -182,6 +184,16 @@ func migrateConfig() error {
 			return err
 		}
 		fallthrough
+	case "25":
+		if err = migrateV25ToV26(); err != nil {
+			return err
+		}
+		fallthrough
+	case "26":
+		if err = migrateV26ToV27(); err != nil {
+			return err
+		}
+		fallthrough
 	case serverConfigVersion:
 		// No migration needed. this always points to current version.
 		err = nil
@@ -2192,3 +2204,92 @@ func migrateV24ToV25() error {
 	logger.Info(configMigrateMSGTemplate, configFile, cv24.Version, srvConfig.Version)
 	return nil
 }
+
+func migrateV25ToV26() error {
+	configFile := getConfigFile()
+
+	cv25 := &serverConfigV25{}
+	_, err := quick.Load(configFile, cv25)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘25’. %v", err)
+	}
+	if cv25.Version != "25" {
+		return nil
+	}
+
+	// Copy over fields from V25 into V26 config struct
+	srvConfig := &serverConfigV26{
+		Version:      "26",
+		Credential:   cv25.Credential,
+		Region:       cv25.Region,
+		Browser:      cv25.Browser,
+		Worm:         cv25.Worm,
+		Domain:       cv25.Domain,
+		StorageClass: cv25.StorageClass,
+		Cache:        cv25.Cache,
+		Notify:       cv25.Notify,
+	}
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// New audit targets are turned-off by default.
+	srvConfig.Audit.Webhook = make(map[string]audit.WebhookArgs)
+	srvConfig.Audit.Webhook["1"] = audit.WebhookArgs{}
+	srvConfig.Audit.File = make(map[string]audit.FileArgs)
+	srvConfig.Audit.File["1"] = audit.FileArgs{}
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv25.Version, srvConfig.Version, err)
+	}
+
+	logger.Info(configMigrateMSGTemplate, configFile, cv25.Version, srvConfig.Version)
+	return nil
+}
+
+func migrateV26ToV27() error {
+	configFile := getConfigFile()
+
+	cv26 := &serverConfigV26{}
+	_, err := quick.Load(configFile, cv26)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Unable to load config version ‘26’. %v", err)
+	}
+	if cv26.Version != "26" {
+		return nil
+	}
+
+	// Copy over fields from V26 into V27 config struct
+	srvConfig := &serverConfigV27{
+		Version:      "27",
+		Credential:   cv26.Credential,
+		Region:       cv26.Region,
+		Browser:      cv26.Browser,
+		Worm:         cv26.Worm,
+		Domain:       cv26.Domain,
+		StorageClass: cv26.StorageClass,
+		Cache:        cv26.Cache,
+		Notify:       cv26.Notify,
+		Audit:        cv26.Audit,
+	}
+	if srvConfig.Region == "" {
+		// Region needs to be set for AWS Signature Version 4.
+		srvConfig.Region = globalMinioDefaultRegion
+	}
+
+	// New replication targets are turned-off by default.
+	srvConfig.Replication = make(replication.Targets)
+	srvConfig.Replication["1"] = replication.Target{}
+
+	if err = quick.Save(configFile, srvConfig); err != nil {
+		return fmt.Errorf("Failed to migrate config from ‘%s’ to ‘%s’. %v", cv26.Version, srvConfig.Version, err)
+	}
+
+	logger.Info(configMigrateMSGTemplate, configFile, cv26.Version, srvConfig.Version)
+	return nil
+}
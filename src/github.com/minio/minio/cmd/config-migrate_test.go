@@ -21,6 +21,8 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+
+	"github.com/minio/minio/pkg/quick"
 )
 
 // Test if config v1 is purged
@@ -134,6 +136,12 @@ func TestServerConfigMigrateInexistentConfig(t *testing.T) {
 	if err := migrateV21ToV22(); err != nil {
 		t.Fatal("migrate v21 to v22 should succeed when no config file is found")
 	}
+	if err := migrateV25ToV26(); err != nil {
+		t.Fatal("migrate v25 to v26 should succeed when no config file is found")
+	}
+	if err := migrateV26ToV27(); err != nil {
+		t.Fatal("migrate v26 to v27 should succeed when no config file is found")
+	}
 }
 
 // Test if a config migration from v2 to v23 is successfully done
@@ -191,6 +199,116 @@ func TestServerConfigMigrateV2toV23(t *testing.T) {
 	}
 }
 
+// Test if a config migration from v25 to v26 keeps the credentials
+// and adds the audit targets turned-off by default.
+func TestServerConfigMigrateV25toV26(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer os.RemoveAll(rootPath)
+
+	setConfigDir(rootPath)
+	configPath := rootPath + "/" + minioConfigFile
+
+	accessKey := "accessfoo"
+	secretKey := "secretfoo"
+
+	// Create a V25 config json file and store it
+	configJSON := "{ \"version\":\"25\", \"credential\": {\"accessKey\":\"" + accessKey + "\", \"secretKey\":\"" + secretKey + "\"}, \"region\":\"\"}"
+	if err := ioutil.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	if err := migrateV25ToV26(); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	cv26 := &serverConfigV26{}
+	if _, err := quick.Load(configPath, cv26); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if cv26.Version != "26" {
+		t.Fatalf("Expect version 26, found: %v", cv26.Version)
+	}
+	if cv26.Credential.AccessKey != accessKey {
+		t.Fatalf("Access key lost during migration, expected: %v, found:%v", accessKey, cv26.Credential.AccessKey)
+	}
+	if cv26.Credential.SecretKey != secretKey {
+		t.Fatalf("Secret key lost during migration, expected: %v, found: %v", secretKey, cv26.Credential.SecretKey)
+	}
+	if cv26.Region != globalMinioDefaultRegion {
+		t.Fatalf("Expect region %v, found: %v", globalMinioDefaultRegion, cv26.Region)
+	}
+	if _, ok := cv26.Audit.Webhook["1"]; !ok {
+		t.Fatal("Audit webhook target missing after migration")
+	}
+	if _, ok := cv26.Audit.File["1"]; !ok {
+		t.Fatal("Audit file target missing after migration")
+	}
+
+	// A config of any other version must be left untouched.
+	if err := migrateV25ToV26(); err != nil {
+		t.Fatal("migrate v25 to v26 should succeed on a v26 config")
+	}
+}
+
+// Test if a config migration from v26 to v27 keeps the credentials
+// and adds the replication targets turned-off by default.
+func TestServerConfigMigrateV26toV27(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Init Test config failed")
+	}
+	// remove the root directory after the test ends.
+	defer os.RemoveAll(rootPath)
+
+	setConfigDir(rootPath)
+	configPath := rootPath + "/" + minioConfigFile
+
+	accessKey := "accessfoo"
+	secretKey := "secretfoo"
+
+	// Create a V26 config json file and store it
+	configJSON := "{ \"version\":\"26\", \"credential\": {\"accessKey\":\"" + accessKey + "\", \"secretKey\":\"" + secretKey + "\"}, \"region\":\"us-west-1\", \"audit\": {\"webhook\": {\"1\": {\"enable\": false, \"endpoint\": \"\"}}}}"
+	if err := ioutil.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	if err := migrateV26ToV27(); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	cv27 := &serverConfigV27{}
+	if _, err := quick.Load(configPath, cv27); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if cv27.Version != "27" {
+		t.Fatalf("Expect version 27, found: %v", cv27.Version)
+	}
+	if cv27.Credential.AccessKey != accessKey {
+		t.Fatalf("Access key lost during migration, expected: %v, found:%v", accessKey, cv27.Credential.AccessKey)
+	}
+	if cv27.Credential.SecretKey != secretKey {
+		t.Fatalf("Secret key lost during migration, expected: %v, found: %v", secretKey, cv27.Credential.SecretKey)
+	}
+	if cv27.Region != "us-west-1" {
+		t.Fatalf("Expect region us-west-1, found: %v", cv27.Region)
+	}
+	if _, ok := cv27.Audit.Webhook["1"]; !ok {
+		t.Fatal("Audit webhook target lost during migration")
+	}
+	if _, ok := cv27.Replication["1"]; !ok {
+		t.Fatal("Replication target missing after migration")
+	}
+
+	// A config of any other version must be left untouched.
+	if err := migrateV26ToV27(); err != nil {
+		t.Fatal("migrate v26 to v27 should succeed on a v27 config")
+	}
+}
+
 // Test if all migrate code returns error with corrupted config files
 func TestServerConfigMigrateFaultyConfig(t *testing.T) {
 	rootPath, err := newTestConfig(globalMinioDefaultRegion)
@@ -272,6 +390,12 @@ func TestServerConfigMigrateFaultyConfig(t *testing.T) {
 	if err := migrateV22ToV23(); err == nil {
 		t.Fatal("migrateConfigV22ToV23() should fail with a corrupted json")
 	}
+	if err := migrateV25ToV26(); err == nil {
+		t.Fatal("migrateConfigV25ToV26() should fail with a corrupted json")
+	}
+	if err := migrateV26ToV27(); err == nil {
+		t.Fatal("migrateConfigV26ToV27() should fail with a corrupted json")
+	}
 }
 
 // Test if all migrate code returns error with corrupted config files
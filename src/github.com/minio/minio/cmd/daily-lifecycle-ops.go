@@ -0,0 +1,342 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/lifecycle"
+	"go.uber.org/atomic"
+)
+
+const (
+	// Interval between two lifecycle rounds.
+	bgLifecycleInterval = 24 * time.Hour
+	// Interval to check whether a lifecycle round is due.
+	bgLifecycleTick = time.Hour
+
+	// Maximum number of objects listed at once during a lifecycle round.
+	bgLifecycleMaxKeys = 1000
+
+	// Lifecycle round status file of each bucket under bucketConfigPrefix,
+	// shared by all nodes so that rules of a bucket are only applied once
+	// per interval in the cluster.
+	lifecycleOpsStatusFile = "lifecycle-ops.json"
+)
+
+// Timeout to take the lifecycle round lock of a bucket, failing to take
+// it means another node is applying the rules of the bucket.
+var lifecycleTimeout = newDynamicTimeout(60*time.Second, time.Second)
+
+// bucketMultipartLister - implemented by object layers which can list
+// multipart uploads of all objects in a bucket.
+type bucketMultipartLister interface {
+	listBucketMultipartUploads(ctx context.Context, bucket string) ([]MultipartInfo, error)
+}
+
+// LifecycleStats - statistics of multipart uploads aborted by lifecycle
+// rules during the server's life.
+type LifecycleStats struct {
+	abortedUploads atomic.Uint64
+	reclaimedBytes atomic.Uint64
+}
+
+// Increase aborted uploads and their reclaimed bytes.
+func (s *LifecycleStats) incAbortedUpload(size int64) {
+	s.abortedUploads.Inc()
+	s.reclaimedBytes.Add(uint64(size))
+}
+
+// Return total aborted uploads.
+func (s *LifecycleStats) getAbortedUploads() uint64 {
+	return s.abortedUploads.Load()
+}
+
+// Return total bytes reclaimed by aborting uploads.
+func (s *LifecycleStats) getReclaimedBytes() uint64 {
+	return s.reclaimedBytes.Load()
+}
+
+// Prepare new LifecycleStats structure.
+func newLifecycleStats() *LifecycleStats {
+	return &LifecycleStats{}
+}
+
+// lifecycleOpsStatus - status of last lifecycle round.
+type lifecycleOpsStatus struct {
+	LastActivity time.Time `json:"lastActivity"`
+}
+
+// initDailyLifecycle - starts the routine which periodically applies
+// expiration rules of bucket lifecycle configurations.
+func initDailyLifecycle() {
+	go startDailyLifecycle(globalServiceDoneCh)
+}
+
+func startDailyLifecycle(doneCh chan struct{}) {
+	ctx := context.Background()
+
+	var objAPI ObjectLayer
+	// Wait until the object layer is initialized.
+	for {
+		if objAPI = newObjectLayerFn(); objAPI != nil {
+			break
+		}
+		select {
+		case <-doneCh:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	for {
+		wait := bgLifecycleTick
+		if err := lifecycleRound(ctx, objAPI); err != nil {
+			logger.LogIf(ctx, err)
+			wait = time.Minute
+		}
+
+		select {
+		case <-doneCh:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// readLifecycleOpsStatus - reads status of last lifecycle round of a bucket.
+func readLifecycleOpsStatus(ctx context.Context, objAPI ObjectLayer, statusFile string) (status lifecycleOpsStatus, err error) {
+	reader, err := readConfig(ctx, objAPI, statusFile)
+	if err != nil {
+		if IsErrIgnored(err, errConfigNotFound, errNoSuchNotifications) {
+			err = nil
+		}
+		return status, err
+	}
+
+	err = json.NewDecoder(reader).Decode(&status)
+	return status, err
+}
+
+// saveLifecycleOpsStatus - saves status of last lifecycle round of a bucket.
+func saveLifecycleOpsStatus(objAPI ObjectLayer, statusFile string, status lifecycleOpsStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(objAPI, statusFile, data)
+}
+
+// lifecycleRound - applies lifecycle rules of each bucket once per
+// bgLifecycleInterval in the cluster.
+func lifecycleRound(ctx context.Context, objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range buckets {
+		lc := globalBucketMetadataSys.GetLifecycle(bucket.Name)
+		if lc == nil {
+			continue
+		}
+
+		bucketCtx := logger.SetReqInfo(ctx, &logger.ReqInfo{BucketName: bucket.Name})
+		if err = bucketLifecycleRound(bucketCtx, objAPI, bucket.Name, *lc); err != nil {
+			// Unable to hold the lock means another node is applying
+			// the rules of the bucket.
+			if _, ok := err.(OperationTimedOut); !ok {
+				logger.LogIf(bucketCtx, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// bucketLifecycleRound - applies lifecycle rules of a bucket unless they
+// were applied less than bgLifecycleInterval ago. Each bucket has its own
+// lock, so that the lock is only held while a single bucket is processed
+// and the buckets of a round are shared by all nodes.
+func bucketLifecycleRound(ctx context.Context, objAPI ObjectLayer, bucket string, lc lifecycle.Lifecycle) error {
+	statusFile := path.Join(bucketConfigPrefix, bucket, lifecycleOpsStatusFile)
+
+	// Lock to avoid concurrent rounds of the bucket from other nodes.
+	roundLock := globalNSMutex.NewNSLock(minioMetaBucket, statusFile+".lock")
+	if err := roundLock.GetLock(lifecycleTimeout); err != nil {
+		return err
+	}
+	defer roundLock.Unlock()
+
+	status, err := readLifecycleOpsStatus(ctx, objAPI, statusFile)
+	if err != nil {
+		return err
+	}
+
+	if !status.LastActivity.IsZero() && time.Since(status.LastActivity) < bgLifecycleInterval {
+		return nil
+	}
+
+	if err = expireBucketObjects(ctx, objAPI, bucket, lc); err != nil {
+		logger.LogIf(ctx, err)
+	}
+
+	if err = abortBucketMultipartUploads(ctx, objAPI, bucket, lc); err != nil {
+		logger.LogIf(ctx, err)
+	}
+
+	return saveLifecycleOpsStatus(objAPI, statusFile, lifecycleOpsStatus{LastActivity: UTCNow()})
+}
+
+// expireBucketObjects - removes all objects of the bucket expired as per lifecycle.
+func expireBucketObjects(ctx context.Context, objAPI ObjectLayer, bucket string, lc lifecycle.Lifecycle) error {
+	if !lc.HasExpiry() {
+		return nil
+	}
+
+	deleteObject := objAPI.DeleteObject
+	if api := newCacheObjectsFn(); api != nil {
+		deleteObject = api.DeleteObject
+	}
+
+	prefix := lc.CommonPrefix()
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, bucket, prefix, marker, "", bgLifecycleMaxKeys)
+		if err != nil {
+			return err
+		}
+
+		for _, objInfo := range result.Objects {
+			if lc.ComputeAction(objInfo.Name, getObjectTags(objInfo).ToMap(), objInfo.ModTime) != lifecycle.DeleteAction {
+				continue
+			}
+
+			if err = deleteObject(ctx, bucket, objInfo.Name); err != nil {
+				if !isErrObjectNotFound(err) {
+					reqInfo := &logger.ReqInfo{BucketName: bucket, ObjectName: objInfo.Name}
+					logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+				}
+				continue
+			}
+
+			globalBucketQuotaSys.ObjectRemoved(bucket, objInfo.Size)
+
+			// Notify object expired event.
+			sendEvent(eventArgs{
+				EventName:  event.ObjectRemovedExpired,
+				BucketName: bucket,
+				Object: ObjectInfo{
+					Name: objInfo.Name,
+				},
+				Host: globalMinioHost,
+				Port: globalMinioPort,
+			})
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+
+		marker = result.NextMarker
+		if marker == "" && len(result.Objects) > 0 {
+			marker = result.Objects[len(result.Objects)-1].Name
+		}
+	}
+}
+
+// abortBucketMultipartUploads - aborts all multipart uploads of the bucket
+// incomplete for longer than allowed by lifecycle.
+func abortBucketMultipartUploads(ctx context.Context, objAPI ObjectLayer, bucket string, lc lifecycle.Lifecycle) error {
+	if !lc.HasAbortMultipartUpload() {
+		return nil
+	}
+
+	lister, ok := objAPI.(bucketMultipartLister)
+	if !ok {
+		return nil
+	}
+
+	uploads, err := lister.listBucketMultipartUploads(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	for _, upload := range uploads {
+		if !lc.ComputeAbortMultipartUpload(upload.Object, upload.Initiated) {
+			continue
+		}
+
+		reqInfo := &logger.ReqInfo{BucketName: bucket, ObjectName: upload.Object}
+		uploadCtx := logger.SetReqInfo(ctx, reqInfo)
+
+		size, err := getMultipartUploadSize(uploadCtx, objAPI, bucket, upload)
+		if err != nil {
+			if _, ok := err.(InvalidUploadID); !ok {
+				logger.LogIf(uploadCtx, err)
+			}
+			continue
+		}
+
+		if err = objAPI.AbortMultipartUpload(uploadCtx, bucket, upload.Object, upload.UploadID); err != nil {
+			if _, ok := err.(InvalidUploadID); !ok {
+				logger.LogIf(uploadCtx, err)
+			}
+			continue
+		}
+
+		globalLifecycleStats.incAbortedUpload(size)
+	}
+
+	return nil
+}
+
+// getMultipartUploadSize - returns total size of uploaded parts.
+func getMultipartUploadSize(ctx context.Context, objAPI ObjectLayer, bucket string, upload MultipartInfo) (size int64, err error) {
+	partNumberMarker := 0
+	for {
+		result, err := objAPI.ListObjectParts(ctx, bucket, upload.Object, upload.UploadID, partNumberMarker, maxPartsList)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, part := range result.Parts {
+			size += part.Size
+		}
+
+		if !result.IsTruncated {
+			return size, nil
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+}
+
+// getLifecycleConfig - get lifecycle config for given bucket name.
+func getLifecycleConfig(objAPI ObjectLayer, bucketName string) (*lifecycle.Lifecycle, error) {
+	config, err := getBucketConfig(objAPI, bucketName, bucketLifecycleConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.(*lifecycle.Lifecycle), nil
+}
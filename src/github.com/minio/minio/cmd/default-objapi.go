@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"context"
+	"io"
+
 	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/tagging"
+	"github.com/minio/minio/pkg/versioning"
 )
 
 type DefaultObjectAPI struct {
@@ -38,3 +42,71 @@ func (fs *DefaultObjectAPI) GetBucketLifecycle(ctx context.Context, bucket strin
 func (fs *DefaultObjectAPI) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
 	return NotImplemented{}
 }
+
+// Bucket configuration
+func (fs *DefaultObjectAPI) SetBucketConfig(ctx context.Context, bucket, configFile string, config interface{}) error {
+	return NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) GetBucketConfig(ctx context.Context, bucket, configFile string) (interface{}, error) {
+	return nil, NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) DeleteBucketConfig(ctx context.Context, bucket, configFile string) error {
+	return NotImplemented{}
+}
+
+// Restore
+func (fs *DefaultObjectAPI) RestoreObject(ctx context.Context, bucket, object string, days int) (<-chan error, error) {
+	return nil, NotImplemented{}
+}
+
+// Versioning
+func (fs *DefaultObjectAPI) SetBucketVersioning(ctx context.Context, bucket string, v *versioning.Versioning) error {
+	return NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) GetBucketVersioning(ctx context.Context, bucket string) (*versioning.Versioning, error) {
+	return nil, NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) ListObjectVersions(ctx context.Context, bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (result ListObjectVersionsInfo, err error) {
+	return result, NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) GetObjectVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	return NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) GetObjectVersionInfo(ctx context.Context, bucket, object, versionID string) (objInfo ObjectInfo, err error) {
+	return objInfo, NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) DeleteObjectVersion(ctx context.Context, bucket, object, versionID string) (objInfo ObjectInfo, err error) {
+	return objInfo, NotImplemented{}
+}
+
+// Tagging
+func (fs *DefaultObjectAPI) SetBucketTagging(ctx context.Context, bucket string, t *tagging.Tagging) error {
+	return NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) GetBucketTagging(ctx context.Context, bucket string) (*tagging.Tagging, error) {
+	return nil, NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) DeleteBucketTagging(ctx context.Context, bucket string) error {
+	return NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) PutObjectTags(ctx context.Context, bucket, object, tags string) error {
+	return NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) PutObjectReplicationStatus(ctx context.Context, bucket, object, etag, status string) error {
+	return NotImplemented{}
+}
+
+func (fs *DefaultObjectAPI) PutObjectMetadata(ctx context.Context, bucket, object, versionID, etag string, metadata map[string]string) error {
+	return NotImplemented{}
+}
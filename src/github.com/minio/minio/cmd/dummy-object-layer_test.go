@@ -26,7 +26,9 @@ import (
 	"github.com/minio/minio/pkg/policy"
 )
 
-type DummyObjectLayer struct{}
+type DummyObjectLayer struct {
+	DefaultObjectAPI
+}
 
 func (api *DummyObjectLayer) Shutdown(context.Context) (err error) {
 	return
@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/minio/minio/pkg/kms"
+)
+
+const (
+	// sseMasterKeyEnv is the environment variable holding a single
+	// master key of the local KMS.
+	sseMasterKeyEnv = "MINIO_SSE_MASTER_KEY"
+
+	// sseMasterKeyFileEnv is the environment variable holding the path of
+	// a file containing the master keys of the local KMS.
+	sseMasterKeyFileEnv = "MINIO_SSE_MASTER_KEY_FILE"
+
+	// Environment variables configuring Hashicorp Vault as KMS.
+	sseVaultEndpointEnv      = "MINIO_SSE_VAULT_ENDPOINT"
+	sseVaultAppRoleIDEnv     = "MINIO_SSE_VAULT_APPROLE_ID"
+	sseVaultAppRoleSecretEnv = "MINIO_SSE_VAULT_APPROLE_SECRET"
+	sseVaultKeyNameEnv       = "MINIO_SSE_VAULT_KEY_NAME"
+)
+
+var errMultipleKMS = errors.New("only one of " + sseMasterKeyEnv + ", " + sseMasterKeyFileEnv + " and " + sseVaultEndpointEnv + " may be set")
+
+// loadKMS initializes the KMS configured by the environment and returns
+// it along with the ID of the default master key. It returns a nil KMS
+// if no KMS is configured.
+func loadKMS() (kms.KMS, string, error) {
+	key, keyFile, endpoint := os.Getenv(sseMasterKeyEnv), os.Getenv(sseMasterKeyFileEnv), os.Getenv(sseVaultEndpointEnv)
+	configured := 0
+	for _, v := range []string{key, keyFile, endpoint} {
+		if v != "" {
+			configured++
+		}
+	}
+	if configured > 1 {
+		return nil, "", errMultipleKMS
+	}
+
+	switch {
+	case key != "":
+		masterKey, err := kms.ParseMasterKey(key)
+		if err != nil {
+			return nil, "", err
+		}
+		return kms.NewLocal(masterKey), masterKey.ID, nil
+	case keyFile != "":
+		masterKeys, err := kms.LoadMasterKeys(keyFile)
+		if err != nil {
+			return nil, "", err
+		}
+		return kms.NewLocal(masterKeys...), masterKeys[0].ID, nil
+	case endpoint != "":
+		keyName := os.Getenv(sseVaultKeyNameEnv)
+		if keyName == "" {
+			return nil, "", errors.New(sseVaultKeyNameEnv + " must be set")
+		}
+		vault, err := kms.NewVault(kms.VaultConfig{
+			Endpoint: endpoint,
+			AppRole: kms.VaultAppRole{
+				ID:     os.Getenv(sseVaultAppRoleIDEnv),
+				Secret: os.Getenv(sseVaultAppRoleSecretEnv),
+			},
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return vault, keyName, nil
+	}
+	return nil, "", nil
+}
@@ -0,0 +1,78 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKMS(t *testing.T) {
+	defer os.Unsetenv(sseMasterKeyEnv)
+	defer os.Unsetenv(sseMasterKeyFileEnv)
+	defer os.Unsetenv(sseVaultEndpointEnv)
+	defer os.Unsetenv(sseVaultKeyNameEnv)
+
+	dir, err := ioutil.TempDir("", "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const masterKey = "my-key:6368616e676520746869732070617373776f726420746f206120736563726574"
+	keyFile := filepath.Join(dir, "master.key")
+	keys := "# master keys\n" + masterKey + "\nold-key:6368616e676520746869732070617373776f726420746f206120736563726574\n"
+	if err = ioutil.WriteFile(keyFile, []byte(keys), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Unsetenv(sseMasterKeyEnv)
+	os.Unsetenv(sseMasterKeyFileEnv)
+	os.Unsetenv(sseVaultEndpointEnv)
+	if KMS, _, err := loadKMS(); err != nil || KMS != nil {
+		t.Fatalf("Expected no KMS, got: %v %v", KMS, err)
+	}
+
+	os.Setenv(sseMasterKeyFileEnv, keyFile)
+	if KMS, keyID, err := loadKMS(); err != nil || KMS == nil || keyID != "my-key" {
+		t.Fatalf("Failed to load master keys from file: %v", err)
+	}
+
+	os.Setenv(sseMasterKeyEnv, masterKey)
+	if _, _, err := loadKMS(); err != errMultipleKMS {
+		t.Fatalf("Expected %v when both master key and key file are set, got: %v", errMultipleKMS, err)
+	}
+
+	os.Unsetenv(sseMasterKeyFileEnv)
+	if KMS, keyID, err := loadKMS(); err != nil || KMS == nil || keyID != "my-key" {
+		t.Fatalf("Failed to load master key from environment: %v", err)
+	}
+
+	os.Setenv(sseMasterKeyEnv, "my-key:invalid")
+	if _, _, err := loadKMS(); err == nil {
+		t.Fatal("Expected an error for an invalid master key")
+	}
+
+	os.Unsetenv(sseMasterKeyEnv)
+	os.Setenv(sseVaultEndpointEnv, "http://127.0.0.1:8200")
+	os.Unsetenv(sseVaultKeyNameEnv)
+	if _, _, err := loadKMS(); err == nil {
+		t.Fatal("Expected an error when the Vault key name is not set")
+	}
+}
@@ -25,13 +25,16 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/ioutil"
+	"github.com/minio/minio/pkg/kms"
 	sha256 "github.com/minio/sha256-simd"
 	"github.com/minio/sio"
 )
@@ -50,9 +53,20 @@ var (
 
 	// Additional Minio errors for SSE-C requests.
 	errObjectTampered = errors.New("The requested object was modified and may be compromised")
+
+	// AWS errors for invalid SSE-S3 requests.
+	errInvalidEncryptionMethod      = errors.New("The encryption method specified is not supported")
+	errIncompatibleEncryptionMethod = errors.New("SSE-C is incompatible with the encryption method specified")
+	errKMSNotConfigured             = errors.New("Server side encryption specified but KMS is not configured")
+	errInvalidKMSKeyIDRequest       = errors.New("The KMS key ID requires the aws:kms server side encryption")
 )
 
 const (
+	// SSEHeader is the AWS SSE-S3 and SSE-KMS HTTP header key.
+	SSEHeader = "X-Amz-Server-Side-Encryption"
+	// SSEKMSKeyID is the AWS SSE-KMS master key ID HTTP header key.
+	SSEKMSKeyID = "X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"
+
 	// SSECustomerAlgorithm is the AWS SSE-C algorithm HTTP header key.
 	SSECustomerAlgorithm = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
 	// SSECustomerKey is the AWS SSE-C encryption key HTTP header key.
@@ -79,6 +93,9 @@ const (
 	// SSECustomerAlgorithmAES256 the only valid S3 SSE-C encryption algorithm identifier.
 	SSECustomerAlgorithmAES256 = "AES256"
 
+	// SSEAlgorithmKMS is the SSE-KMS encryption algorithm identifier.
+	SSEAlgorithmKMS = "aws:kms"
+
 	// SSE dare package block size.
 	sseDAREPackageBlockSize = 64 * 1024 // 64KiB bytes
 
@@ -142,6 +159,19 @@ const (
 	// ServerSideEncryptionSealedKey is the sealed object encryption key. The sealed key can be decrypted
 	// by the key encryption key derived from the client provided key and the server-side-encryption IV.
 	ServerSideEncryptionSealedKey = ReservedMetadataPrefix + "Server-Side-Encryption-Sealed-Key"
+
+	// ServerSideEncryptionS3KeyID is the ID of the KMS master key of an SSE-S3 encrypted object.
+	ServerSideEncryptionS3KeyID = ReservedMetadataPrefix + "Server-Side-Encryption-S3-Key-Id"
+
+	// ServerSideEncryptionKMSKeyID is the ID of the KMS master key of an SSE-KMS encrypted object.
+	ServerSideEncryptionKMSKeyID = ReservedMetadataPrefix + "Server-Side-Encryption-Kms-Key-Id"
+
+	// ServerSideEncryptionKMSSealedKey is the data key sealed by the KMS master key. For SSE-S3
+	// and SSE-KMS the data key replaces the client provided key in the key derivation above.
+	ServerSideEncryptionKMSSealedKey = ReservedMetadataPrefix + "Server-Side-Encryption-Kms-Sealed-Key"
+
+	// ServerSideEncryptionKMSContext is the KMS context the data key is bound to.
+	ServerSideEncryptionKMSContext = ReservedMetadataPrefix + "Server-Side-Encryption-Kms-Context"
 )
 
 // SSESealAlgorithmDareSha256 specifies DARE as authenticated en/decryption scheme and SHA256 as cryptographic
@@ -160,6 +190,181 @@ func hasSSECopyCustomerHeader(header http.Header) bool {
 	return header.Get(SSECopyCustomerAlgorithm) != "" || header.Get(SSECopyCustomerKey) != "" || header.Get(SSECopyCustomerKeyMD5) != ""
 }
 
+// hasSSEHeader returns true if the given HTTP header contains
+// server-side-encryption with server managed key fields.
+func hasSSEHeader(header http.Header) bool {
+	return header.Get(SSEHeader) != "" || header.Get(SSEKMSKeyID) != ""
+}
+
+// ParseSSEHeader validates the SSE-S3 and SSE-KMS header fields and
+// returns the ID of the KMS master key which seals the object key.
+// SSE-S3 requests and SSE-KMS requests without key ID use the default
+// master key.
+func ParseSSEHeader(header http.Header) (keyID string, err error) {
+	algorithm := header.Get(SSEHeader)
+	if algorithm != SSEAlgorithmKMS && header.Get(SSEKMSKeyID) != "" {
+		return "", errInvalidKMSKeyIDRequest
+	}
+	if algorithm != SSECustomerAlgorithmAES256 && algorithm != SSEAlgorithmKMS {
+		return "", errInvalidEncryptionMethod
+	}
+	if hasSSECustomerHeader(header) {
+		return "", errIncompatibleEncryptionMethod
+	}
+	if globalKMS == nil {
+		return "", errKMSNotConfigured
+	}
+	if keyID = header.Get(SSEKMSKeyID); keyID == "" {
+		keyID = globalKMSKeyID
+	}
+	return keyID, nil
+}
+
+// isSSES3Encrypted returns true if the object metadata marks
+// the object as SSE-S3 encrypted.
+func isSSES3Encrypted(metadata map[string]string) bool {
+	_, ok := metadata[ServerSideEncryptionS3KeyID]
+	return ok
+}
+
+// isKMSEncrypted returns true if the object encryption key of the
+// object is sealed by a KMS data key, i.e. for SSE-S3 and SSE-KMS.
+func isKMSEncrypted(metadata map[string]string) bool {
+	if isSSES3Encrypted(metadata) {
+		return true
+	}
+	_, ok := metadata[ServerSideEncryptionKMSKeyID]
+	return ok
+}
+
+// setSSEHeaders sets the server-side-encryption response headers
+// of SSE-S3 and SSE-KMS encrypted objects.
+func setSSEHeaders(w http.ResponseWriter, metadata map[string]string) {
+	if keyID, ok := metadata[ServerSideEncryptionKMSKeyID]; ok {
+		w.Header().Set(SSEHeader, SSEAlgorithmKMS)
+		w.Header().Set(SSEKMSKeyID, keyID)
+	} else if isSSES3Encrypted(metadata) {
+		w.Header().Set(SSEHeader, SSECustomerAlgorithmAES256)
+	}
+}
+
+// newSSEKey returns the key used to seal a new object encryption key,
+// which is a new KMS data key for SSE-S3 and SSE-KMS and the client
+// provided key for SSE-C requests. The data key is bound to the object
+// and is added to metadata sealed by the KMS.
+func newSSEKey(header http.Header, bucket, object string, metadata map[string]string) ([]byte, error) {
+	if !hasSSEHeader(header) {
+		return ParseSSECustomerHeader(header)
+	}
+	keyID, err := ParseSSEHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	return generateKMSKey(keyID, header.Get(SSEHeader) == SSEAlgorithmKMS, bucket, object, metadata)
+}
+
+// generateKMSKey generates a new KMS data key for the object, sealed
+// by the master key keyID, and stores the sealed key in the metadata.
+func generateKMSKey(keyID string, sseKMS bool, bucket, object string, metadata map[string]string) ([]byte, error) {
+	context := kms.Context{bucket: path.Join(bucket, object)}
+	key, sealedKey, err := globalKMS.GenerateKey(keyID, context)
+	if err != nil {
+		return nil, err
+	}
+	contextJSON, err := json.Marshal(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if sseKMS {
+		metadata[ServerSideEncryptionKMSKeyID] = keyID
+	} else {
+		metadata[ServerSideEncryptionS3KeyID] = keyID
+	}
+	metadata[ServerSideEncryptionKMSSealedKey] = base64.StdEncoding.EncodeToString(sealedKey)
+	metadata[ServerSideEncryptionKMSContext] = base64.StdEncoding.EncodeToString(contextJSON)
+	return key[:], nil
+}
+
+// kmsKeyID returns the ID of the master key which sealed the KMS data
+// key of an SSE-S3 or SSE-KMS encrypted object.
+func kmsKeyID(metadata map[string]string) string {
+	if keyID, ok := metadata[ServerSideEncryptionKMSKeyID]; ok {
+		return keyID
+	}
+	return metadata[ServerSideEncryptionS3KeyID]
+}
+
+// unsealKMSKey unseals the KMS data key of an SSE-S3 or SSE-KMS
+// encrypted object.
+func unsealKMSKey(metadata map[string]string) ([]byte, error) {
+	if globalKMS == nil {
+		return nil, errKMSNotConfigured
+	}
+	sealedKey, err := base64.StdEncoding.DecodeString(metadata[ServerSideEncryptionKMSSealedKey])
+	if err != nil || len(sealedKey) == 0 {
+		return nil, errObjectTampered
+	}
+	contextJSON, err := base64.StdEncoding.DecodeString(metadata[ServerSideEncryptionKMSContext])
+	if err != nil {
+		return nil, errObjectTampered
+	}
+	var context kms.Context
+	if err = json.Unmarshal(contextJSON, &context); err != nil {
+		return nil, errObjectTampered
+	}
+	key, err := globalKMS.UnsealKey(kmsKeyID(metadata), sealedKey, context)
+	if err != nil {
+		return nil, err
+	}
+	return key[:], nil
+}
+
+// rotateKMSKey re-seals the object encryption key of an SSE-S3 or
+// SSE-KMS encrypted object with a new KMS data key sealed by the
+// master key keyID. The object data remains unchanged.
+func rotateKMSKey(keyID, bucket, object string, metadata map[string]string) error {
+	oldKey, err := unsealKMSKey(metadata)
+	if err != nil {
+		return err
+	}
+	_, sseKMS := metadata[ServerSideEncryptionKMSKeyID]
+	newMetadata := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		newMetadata[k] = v
+	}
+	delete(newMetadata, ServerSideEncryptionKMSKeyID)
+	delete(newMetadata, ServerSideEncryptionS3KeyID)
+	newKey, err := generateKMSKey(keyID, sseKMS, bucket, object, newMetadata)
+	if err != nil {
+		return err
+	}
+	if err = rotateKey(oldKey, newKey, newMetadata); err != nil {
+		return err
+	}
+	for k := range metadata {
+		delete(metadata, k)
+	}
+	for k, v := range newMetadata {
+		metadata[k] = v
+	}
+	return nil
+}
+
+// sealingKey returns the key which sealed the object encryption key of
+// an encrypted object. SSE-S3 and SSE-KMS objects are sealed with a KMS
+// data key, all others with the SSE-C key - of the copy source if
+// copySource is set.
+func sealingKey(r *http.Request, metadata map[string]string, copySource bool) ([]byte, error) {
+	if isKMSEncrypted(metadata) {
+		return unsealKMSKey(metadata)
+	}
+	if copySource {
+		return ParseSSECopyCustomerRequest(r)
+	}
+	return ParseSSECustomerRequest(r)
+}
+
 // ParseSSECopyCustomerRequest parses the SSE-C header fields of the provided request.
 // It returns the client provided key on success.
 func ParseSSECopyCustomerRequest(r *http.Request) (key []byte, err error) {
@@ -365,10 +570,10 @@ func newEncryptReader(content io.Reader, key []byte, metadata map[string]string)
 }
 
 // EncryptRequest takes the client provided content and encrypts the data
-// with the client provided key. It also marks the object as client-side-encrypted
-// and sets the correct headers.
-func EncryptRequest(content io.Reader, r *http.Request, metadata map[string]string) (io.Reader, error) {
-	key, err := ParseSSECustomerRequest(r)
+// with the client provided key or, for SSE-S3 and SSE-KMS requests, a KMS
+// data key. It also marks the object as encrypted and sets the correct headers.
+func EncryptRequest(content io.Reader, r *http.Request, bucket, object string, metadata map[string]string) (io.Reader, error) {
+	key, err := newSSEKey(r.Header, bucket, object, metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -378,7 +583,7 @@ func EncryptRequest(content io.Reader, r *http.Request, metadata map[string]stri
 // DecryptCopyRequest decrypts the object with the client provided key. It also removes
 // the client-side-encryption metadata from the object and sets the correct headers.
 func DecryptCopyRequest(client io.Writer, r *http.Request, metadata map[string]string) (io.WriteCloser, error) {
-	key, err := ParseSSECopyCustomerRequest(r)
+	key, err := sealingKey(r, metadata, true)
 	if err != nil {
 		return nil, err
 	}
@@ -437,6 +642,10 @@ func newDecryptWriterWithObjectKey(client io.Writer, objectEncryptionKey []byte,
 	delete(metadata, ServerSideEncryptionIV)
 	delete(metadata, ServerSideEncryptionSealAlgorithm)
 	delete(metadata, ServerSideEncryptionSealedKey)
+	delete(metadata, ServerSideEncryptionS3KeyID)
+	delete(metadata, ServerSideEncryptionKMSKeyID)
+	delete(metadata, ServerSideEncryptionKMSSealedKey)
+	delete(metadata, ServerSideEncryptionKMSContext)
 	delete(metadata, ReservedMetadataPrefix+"Encrypted-Multipart")
 	return writer, nil
 }
@@ -444,7 +653,7 @@ func newDecryptWriterWithObjectKey(client io.Writer, objectEncryptionKey []byte,
 // DecryptRequestWithSequenceNumber decrypts the object with the client provided key. It also removes
 // the client-side-encryption metadata from the object and sets the correct headers.
 func DecryptRequestWithSequenceNumber(client io.Writer, r *http.Request, seqNumber uint32, metadata map[string]string) (io.WriteCloser, error) {
-	key, err := ParseSSECustomerRequest(r)
+	key, err := sealingKey(r, metadata, false)
 	if err != nil {
 		return nil, err
 	}
@@ -486,15 +695,14 @@ func (w *DecryptBlocksWriter) buildDecrypter(partID int) error {
 		m[k] = v
 	}
 	// Initialize the first decrypter, new decrypters will be initialized in Write() operation as needed.
-	var key []byte
-	var err error
-	if w.copySource {
-		w.req.Header.Set(SSECopyCustomerKey, w.customerKeyHeader)
-		key, err = ParseSSECopyCustomerRequest(w.req)
-	} else {
-		w.req.Header.Set(SSECustomerKey, w.customerKeyHeader)
-		key, err = ParseSSECustomerRequest(w.req)
+	if !isKMSEncrypted(m) {
+		if w.copySource {
+			w.req.Header.Set(SSECopyCustomerKey, w.customerKeyHeader)
+		} else {
+			w.req.Header.Set(SSECustomerKey, w.customerKeyHeader)
+		}
 	}
+	key, err := sealingKey(w.req, m, w.copySource)
 	if err != nil {
 		return err
 	}
@@ -670,6 +878,10 @@ func DecryptBlocksRequest(client io.Writer, r *http.Request, startOffset, length
 	delete(objInfo.UserDefined, ServerSideEncryptionIV)
 	delete(objInfo.UserDefined, ServerSideEncryptionSealAlgorithm)
 	delete(objInfo.UserDefined, ServerSideEncryptionSealedKey)
+	delete(objInfo.UserDefined, ServerSideEncryptionS3KeyID)
+	delete(objInfo.UserDefined, ServerSideEncryptionKMSKeyID)
+	delete(objInfo.UserDefined, ServerSideEncryptionKMSSealedKey)
+	delete(objInfo.UserDefined, ServerSideEncryptionKMSContext)
 	delete(objInfo.UserDefined, ReservedMetadataPrefix+"Encrypted-Multipart")
 
 	if w.copySource {
@@ -769,8 +981,9 @@ func (o *ObjectInfo) EncryptedSize() int64 {
 }
 
 // DecryptCopyObjectInfo tries to decrypt the provided object if it is encrypted.
-// It fails if the object is encrypted and the HTTP headers don't contain
-// SSE-C headers or the object is not encrypted but SSE-C headers are provided. (AWS behavior)
+// It fails if the object is SSE-C encrypted and the HTTP headers don't contain
+// SSE-C headers or the object is not SSE-C encrypted but SSE-C headers are provided. (AWS behavior)
+// SSE-S3 and SSE-KMS encrypted objects are decrypted with a KMS data key.
 // DecryptObjectInfo returns 'ErrNone' if the object is not encrypted or the
 // decryption succeeded.
 //
@@ -780,10 +993,10 @@ func DecryptCopyObjectInfo(info *ObjectInfo, headers http.Header) (apiErr APIErr
 	if info.IsDir {
 		return ErrNone, false
 	}
-	if apiErr, encrypted = ErrNone, info.IsEncrypted(); !encrypted && hasSSECopyCustomerHeader(headers) {
+	if apiErr, encrypted = ErrNone, info.IsEncrypted(); (!encrypted || isKMSEncrypted(info.UserDefined)) && hasSSECopyCustomerHeader(headers) {
 		apiErr = ErrInvalidEncryptionParameters
 	} else if encrypted {
-		if !hasSSECopyCustomerHeader(headers) {
+		if !isKMSEncrypted(info.UserDefined) && !hasSSECopyCustomerHeader(headers) {
 			apiErr = ErrSSEEncryptedObject
 			return
 		}
@@ -796,8 +1009,9 @@ func DecryptCopyObjectInfo(info *ObjectInfo, headers http.Header) (apiErr APIErr
 }
 
 // DecryptObjectInfo tries to decrypt the provided object if it is encrypted.
-// It fails if the object is encrypted and the HTTP headers don't contain
-// SSE-C headers or the object is not encrypted but SSE-C headers are provided. (AWS behavior)
+// It fails if the object is SSE-C encrypted and the HTTP headers don't contain
+// SSE-C headers or the object is not SSE-C encrypted but SSE-C headers are provided. (AWS behavior)
+// SSE-S3 and SSE-KMS encrypted objects are decrypted with a KMS data key.
 // DecryptObjectInfo returns 'ErrNone' if the object is not encrypted or the
 // decryption succeeded.
 //
@@ -807,10 +1021,10 @@ func DecryptObjectInfo(info *ObjectInfo, headers http.Header) (apiErr APIErrorCo
 	if info.IsDir {
 		return ErrNone, false
 	}
-	if apiErr, encrypted = ErrNone, info.IsEncrypted(); !encrypted && hasSSECustomerHeader(headers) {
+	if apiErr, encrypted = ErrNone, info.IsEncrypted(); (!encrypted || isKMSEncrypted(info.UserDefined)) && hasSSECustomerHeader(headers) {
 		apiErr = ErrInvalidEncryptionParameters
 	} else if encrypted {
-		if !hasSSECustomerHeader(headers) {
+		if !isKMSEncrypted(info.UserDefined) && !hasSSECustomerHeader(headers) {
 			apiErr = ErrSSEEncryptedObject
 			return
 		}
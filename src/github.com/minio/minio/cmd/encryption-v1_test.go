@@ -18,8 +18,12 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/base64"
+	"io"
 	"net/http"
 	"testing"
+
+	"github.com/minio/minio/pkg/kms"
 )
 
 var hasSSECopyCustomerHeaderTests = []struct {
@@ -308,7 +312,7 @@ func TestEncryptRequest(t *testing.T) {
 		for k, v := range test.header {
 			req.Header.Set(k, v)
 		}
-		_, err := EncryptRequest(content, req, test.metadata)
+		_, err := EncryptRequest(content, req, "bucket", "object", test.metadata)
 		if err != nil {
 			t.Fatalf("Test %d: Failed to encrypt request: %v", i, err)
 		}
@@ -449,6 +453,16 @@ var decryptObjectInfoTests = []struct {
 		headers: http.Header{SSECustomerAlgorithm: []string{SSECustomerAlgorithmAES256}},
 		expErr:  ErrObjectTampered,
 	},
+	{
+		info:    ObjectInfo{Size: 100, UserDefined: map[string]string{ServerSideEncryptionSealAlgorithm: SSESealAlgorithmDareSha256, ServerSideEncryptionS3KeyID: "my-key"}},
+		headers: http.Header{},
+		expErr:  ErrNone,
+	},
+	{
+		info:    ObjectInfo{Size: 100, UserDefined: map[string]string{ServerSideEncryptionSealAlgorithm: SSESealAlgorithmDareSha256, ServerSideEncryptionS3KeyID: "my-key"}},
+		headers: http.Header{SSECustomerAlgorithm: []string{SSECustomerAlgorithmAES256}},
+		expErr:  ErrInvalidEncryptionParameters,
+	},
 }
 
 func TestDecryptObjectInfo(t *testing.T) {
@@ -462,3 +476,118 @@ func TestDecryptObjectInfo(t *testing.T) {
 		}
 	}
 }
+
+var parseSSEHeaderTests = []struct {
+	headers map[string]string
+	kms     bool
+	keyID   string
+	err     error
+}{
+	{headers: map[string]string{SSEHeader: "AES256"}, kms: true, keyID: "my-key", err: nil},                                            // 0
+	{headers: map[string]string{SSEHeader: "AES256"}, kms: false, err: errKMSNotConfigured},                                            // 1
+	{headers: map[string]string{SSEHeader: "aws:kms"}, kms: true, keyID: "my-key", err: nil},                                           // 2
+	{headers: map[string]string{SSEHeader: "aws:kms", SSEKMSKeyID: "other-key"}, kms: true, keyID: "other-key", err: nil},              // 3
+	{headers: map[string]string{SSEHeader: "AES256", SSEKMSKeyID: "other-key"}, kms: true, err: errInvalidKMSKeyIDRequest},             // 4
+	{headers: map[string]string{SSEKMSKeyID: "other-key"}, kms: true, err: errInvalidKMSKeyIDRequest},                                  // 5
+	{headers: map[string]string{SSEHeader: "DES"}, kms: true, err: errInvalidEncryptionMethod},                                         // 6
+	{headers: map[string]string{SSEHeader: "AES256", SSECustomerAlgorithm: "AES256"}, kms: true, err: errIncompatibleEncryptionMethod}, // 7
+}
+
+func TestParseSSEHeader(t *testing.T) {
+	defer func(KMS kms.KMS, keyID string) { globalKMS, globalKMSKeyID = KMS, keyID }(globalKMS, globalKMSKeyID)
+	for i, test := range parseSSEHeaderTests {
+		globalKMS, globalKMSKeyID = nil, ""
+		if test.kms {
+			globalKMS, globalKMSKeyID = kms.NewLocal(kms.MasterKey{ID: "my-key"}), "my-key"
+		}
+		headers := http.Header{}
+		for k, v := range test.headers {
+			headers.Set(k, v)
+		}
+		keyID, err := ParseSSEHeader(headers)
+		if err != test.err {
+			t.Errorf("Test %d: Parse returned: %v, want: %v", i, err, test.err)
+		}
+		if err == nil && keyID != test.keyID {
+			t.Errorf("Test %d: Parse returned key ID: %s, want: %s", i, keyID, test.keyID)
+		}
+	}
+}
+
+var kmsEncryptDecryptRequestTests = []struct {
+	headers map[string]string
+	keyID   string
+}{
+	{headers: map[string]string{SSEHeader: SSECustomerAlgorithmAES256}, keyID: ServerSideEncryptionS3KeyID},              // 0
+	{headers: map[string]string{SSEHeader: SSEAlgorithmKMS}, keyID: ServerSideEncryptionKMSKeyID},                        // 1
+	{headers: map[string]string{SSEHeader: SSEAlgorithmKMS, SSEKMSKeyID: "my-key"}, keyID: ServerSideEncryptionKMSKeyID}, // 2
+}
+
+func TestKMSEncryptDecryptRequest(t *testing.T) {
+	defer func(KMS kms.KMS, keyID string) { globalKMS, globalKMSKeyID = KMS, keyID }(globalKMS, globalKMSKeyID)
+	masterKey := kms.MasterKey{ID: "my-key"}
+	copy(masterKey.Key[:], bytes.Repeat([]byte{1}, 32))
+	globalKMS, globalKMSKeyID = kms.NewLocal(masterKey), "my-key"
+
+	copyMetadata := func(metadata map[string]string) map[string]string {
+		m := make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			m[k] = v
+		}
+		return m
+	}
+	plaintext := bytes.Repeat([]byte("a"), 100)
+	for i, test := range kmsEncryptDecryptRequestTests {
+		req := &http.Request{Header: http.Header{}}
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		metadata := map[string]string{}
+		reader, err := EncryptRequest(bytes.NewReader(plaintext), req, "bucket", "object", metadata)
+		if err != nil {
+			t.Fatalf("Test %d: Failed to encrypt request: %v", i, err)
+		}
+		if id := metadata[test.keyID]; id != "my-key" {
+			t.Fatalf("Test %d: Expected master key id my-key in metadata, got %q", i, id)
+		}
+		if _, ok := metadata[ServerSideEncryptionKMSSealedKey]; !ok {
+			t.Fatalf("Test %d: Sealed KMS data key must be part of metadata", i)
+		}
+
+		ciphertext := bytes.NewBuffer(nil)
+		if _, err = io.Copy(ciphertext, reader); err != nil {
+			t.Fatal(err)
+		}
+
+		// KMS encrypted objects are decrypted without any client provided key.
+		client := bytes.NewBuffer(nil)
+		writer, err := DecryptRequest(client, &http.Request{Header: http.Header{}}, copyMetadata(metadata))
+		if err != nil {
+			t.Fatalf("Test %d: Failed to decrypt request: %v", i, err)
+		}
+		if _, err = writer.Write(ciphertext.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(client.Bytes(), plaintext) {
+			t.Fatalf("Test %d: Decrypted content does not match the original content", i)
+		}
+
+		// The data key is bound to the object.
+		tampered := copyMetadata(metadata)
+		tampered[ServerSideEncryptionKMSContext] = base64.StdEncoding.EncodeToString([]byte(`{"bucket":"bucket/other-object"}`))
+		if _, err = DecryptRequest(client, &http.Request{Header: http.Header{}}, tampered); err != kms.ErrInvalidSealedKey {
+			t.Fatalf("Test %d: Expected %v, got %v", i, kms.ErrInvalidSealedKey, err)
+		}
+	}
+
+	globalKMS = kms.NewLocal(kms.MasterKey{ID: "other-key"})
+	req := &http.Request{Header: http.Header{}}
+	req.Header.Set(SSEHeader, SSECustomerAlgorithmAES256)
+	metadata := map[string]string{}
+	if _, err := EncryptRequest(bytes.NewReader(plaintext), req, "bucket", "object", metadata); err != kms.ErrKeyNotFound {
+		t.Fatalf("Expected %v, got %v", kms.ErrKeyNotFound, err)
+	}
+}
@@ -229,7 +229,7 @@ func (fs *FSObjects) NewMultipartUpload(ctx context.Context, bucket, object stri
 
 	// Initialize fs.json values.
 	fsMeta := newFSMetaV1()
-	fsMeta.Meta = meta
+	fsMeta.Meta = newMultipartMetadata(bucket, object, meta)
 
 	fsMetaBytes, err := json.Marshal(fsMeta)
 	if err != nil {
@@ -641,6 +641,7 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	if len(fsMeta.Meta) == 0 {
 		fsMeta.Meta = make(map[string]string)
 	}
+	removeMultipartMetadata(fsMeta.Meta)
 	fsMeta.Meta["etag"] = s3MD5
 	if _, err = fsMeta.WriteTo(metaFile); err != nil {
 		logger.LogIf(ctx, err)
@@ -709,6 +710,45 @@ func (fs *FSObjects) AbortMultipartUpload(ctx context.Context, bucket, object, u
 	return nil
 }
 
+// listBucketMultipartUploads - lists multipart uploads of all objects
+// in the bucket, unlike ListMultipartUploads which requires the object
+// name. Uploads without recorded object are skipped.
+func (fs *FSObjects) listBucketMultipartUploads(ctx context.Context, bucket string) ([]MultipartInfo, error) {
+	multipartDir := pathJoin(fs.fsPath, minioMetaMultipartBucket)
+	shaDirs, err := readDir(multipartDir)
+	if err != nil {
+		if err == errFileNotFound {
+			return nil, nil
+		}
+		logger.LogIf(ctx, err)
+		return nil, err
+	}
+
+	var uploads []MultipartInfo
+	for _, shaDir := range shaDirs {
+		uploadIDs, err := readDir(pathJoin(multipartDir, shaDir))
+		if err != nil {
+			continue
+		}
+		for _, uploadID := range uploadIDs {
+			fsMetaBuf, err := ioutil.ReadFile(pathJoin(multipartDir, shaDir, uploadID, fs.metaJSONFile))
+			if err != nil {
+				continue
+			}
+			var fsMeta fsMetaV1
+			if err = json.Unmarshal(fsMetaBuf, &fsMeta); err != nil {
+				continue
+			}
+			uploadBucket, upload, ok := getMultipartUploadInfo(fsMeta.Meta, strings.TrimSuffix(uploadID, slashSeparator))
+			if ok && uploadBucket == bucket {
+				uploads = append(uploads, upload)
+			}
+		}
+	}
+
+	return uploads, nil
+}
+
 // Removes multipart uploads if any older than `expiry` duration
 // on all buckets for every `cleanupInterval`, this function is
 // blocking and should be run in a go-routine.
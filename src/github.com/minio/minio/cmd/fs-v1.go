@@ -30,11 +30,14 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/lock"
 	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/mimedb"
 	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/tagging"
 )
 
 // Default etag is used for pre-existing objects.
@@ -158,6 +161,36 @@ func NewFSObjectLayer(fsPath string) (ObjectLayer, error) {
 		return nil, uiErrUnableToReadFromBackend(err).Msg("Unable to initialize policy system")
 	}
 
+	// Initialize bucket metadata system.
+	if err = globalBucketMetadataSys.Init(fs); err != nil {
+		return nil, uiErrUnableToReadFromBackend(err).Msg("Unable to initialize bucket metadata system")
+	}
+
+	// Initialize ACL system.
+	if err = globalACLSys.Init(fs); err != nil {
+		return nil, uiErrUnableToReadFromBackend(err).Msg("Unable to initialize ACL system")
+	}
+
+	// Initialize bucket replication system.
+	if err = globalBucketReplicationSys.Init(fs); err != nil {
+		return nil, uiErrUnableToReadFromBackend(err).Msg("Unable to initialize bucket replication system")
+	}
+
+	// Initialize bucket quota system.
+	if err = globalBucketQuotaSys.Init(fs); err != nil {
+		return nil, uiErrUnableToReadFromBackend(err).Msg("Unable to initialize bucket quota system")
+	}
+
+	// Initialize bucket logging system.
+	if err = globalBucketLoggingSys.Init(fs); err != nil {
+		return nil, uiErrUnableToReadFromBackend(err).Msg("Unable to initialize bucket logging system")
+	}
+
+	// Initialize IAM system.
+	if err = globalIAMSys.Init(fs); err != nil {
+		return nil, uiErrUnableToReadFromBackend(err).Msg("Unable to initialize IAM system")
+	}
+
 	go fs.diskUsage(globalServiceDoneCh)
 	go fs.cleanupStaleMultipartUploads(ctx, globalMultipartCleanupInterval, globalMultipartExpiry, globalServiceDoneCh)
 
@@ -728,6 +761,178 @@ func (fs *FSObjects) GetObjectInfo(ctx context.Context, bucket, object string) (
 	return oi, toObjectErr(err, bucket, object)
 }
 
+// PutObjectTags - replaces tags of the object in `fs.json`, empty tags
+// remove all tags of the object.
+func (fs *FSObjects) PutObjectTags(ctx context.Context, bucket, object, tags string) error {
+	// Acquire a write lock before updating the object.
+	objectLock := fs.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	if _, err := fs.statBucketDir(ctx, bucket); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
+	if _, err := fs.getObjectInfo(ctx, bucket, object); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fs.metaJSONFile)
+	wlk, err := fs.rwPool.Write(fsMetaPath)
+	if err == errFileNotFound {
+		// Objects without `fs.json` get the default one first.
+		if err = fs.createFsJSON(object, fsMetaPath); err == nil {
+			wlk, err = fs.rwPool.Write(fsMetaPath)
+		}
+	}
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return toObjectErr(err, bucket, object)
+	}
+	// This close will allow for locks to be synchronized on `fs.json`.
+	defer wlk.Close()
+
+	fsMeta := newFSMetaV1()
+	if _, err = fsMeta.ReadFrom(ctx, wlk); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	if tags == "" {
+		delete(fsMeta.Meta, amzObjectTagging)
+	} else {
+		fsMeta.Meta[amzObjectTagging] = tags
+	}
+
+	if _, err = fsMeta.WriteTo(wlk); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	return nil
+}
+
+// PutObjectReplicationStatus - sets the replication status of the object
+// in `fs.json`, if the object still has the given etag.
+func (fs *FSObjects) PutObjectReplicationStatus(ctx context.Context, bucket, object, etag, status string) error {
+	// Acquire a write lock before updating the object.
+	objectLock := fs.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	if _, err := fs.statBucketDir(ctx, bucket); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
+	if _, err := fs.getObjectInfo(ctx, bucket, object); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fs.metaJSONFile)
+	wlk, err := fs.rwPool.Write(fsMetaPath)
+	if err == errFileNotFound {
+		// Objects without `fs.json` get the default one first.
+		if err = fs.createFsJSON(object, fsMetaPath); err == nil {
+			wlk, err = fs.rwPool.Write(fsMetaPath)
+		}
+	}
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return toObjectErr(err, bucket, object)
+	}
+	// This close will allow for locks to be synchronized on `fs.json`.
+	defer wlk.Close()
+
+	fsMeta := newFSMetaV1()
+	if _, err = fsMeta.ReadFrom(ctx, wlk); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	// The object was overwritten since it was replicated.
+	if fsMeta.Meta["etag"] != etag {
+		return InvalidETag{}
+	}
+	fsMeta.Meta[amzReplicationStatus] = status
+
+	if _, err = fsMeta.WriteTo(wlk); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	return nil
+}
+
+// PutObjectMetadata - sets the given metadata entries of the object in
+// `fs.json`, if the object still has the given etag. FS objects have
+// no versions but the null version.
+func (fs *FSObjects) PutObjectMetadata(ctx context.Context, bucket, object, versionID, etag string, metadata map[string]string) error {
+	if versionID != "" && versionID != nullVersionID {
+		return VersionNotFound{bucket, object, versionID}
+	}
+
+	// Acquire a write lock before updating the object.
+	objectLock := fs.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	if _, err := fs.statBucketDir(ctx, bucket); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
+	if _, err := fs.getObjectInfo(ctx, bucket, object); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fs.metaJSONFile)
+	wlk, err := fs.rwPool.Write(fsMetaPath)
+	if err == errFileNotFound {
+		// Objects without `fs.json` get the default one first.
+		if err = fs.createFsJSON(object, fsMetaPath); err == nil {
+			wlk, err = fs.rwPool.Write(fsMetaPath)
+		}
+	}
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return toObjectErr(err, bucket, object)
+	}
+	// This close will allow for locks to be synchronized on `fs.json`.
+	defer wlk.Close()
+
+	fsMeta := newFSMetaV1()
+	if _, err = fsMeta.ReadFrom(ctx, wlk); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	// The object was overwritten since its metadata was read.
+	if extractETag(fsMeta.Meta) != etag {
+		return InvalidETag{}
+	}
+	for k, v := range metadata {
+		fsMeta.Meta[k] = v
+	}
+
+	if _, err = fsMeta.WriteTo(wlk); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	return nil
+}
+
 // This function does the following check, suppose
 // object is "a/b/c/d", stat makes sure that objects ""a/b/c""
 // "a/b" and "a" do not exist.
@@ -945,6 +1150,9 @@ func (fs *FSObjects) DeleteObject(ctx context.Context, bucket, object string) er
 		if err != nil && err != errFileNotFound {
 			return toObjectErr(err, bucket, object)
 		}
+
+		// Delete object ACL, if present - ignore any errors.
+		removeObjectACLConfig(ctx, fs, bucket, object)
 	}
 	return nil
 }
@@ -1224,6 +1432,71 @@ func (fs *FSObjects) DeleteBucketPolicy(ctx context.Context, bucket string) erro
 	return removePolicyConfig(ctx, fs, bucket)
 }
 
+// SetBucketConfig persists the new configuration file on the bucket.
+func (fs *FSObjects) SetBucketConfig(ctx context.Context, bucket, configFile string, config interface{}) error {
+	return saveBucketConfig(fs, bucket, configFile, config)
+}
+
+// GetBucketConfig will return the configuration file of a bucket.
+func (fs *FSObjects) GetBucketConfig(ctx context.Context, bucket, configFile string) (interface{}, error) {
+	return getBucketConfig(fs, bucket, configFile)
+}
+
+// DeleteBucketConfig deletes the configuration file of a bucket.
+func (fs *FSObjects) DeleteBucketConfig(ctx context.Context, bucket, configFile string) error {
+	return removeBucketConfig(ctx, fs, bucket, configFile)
+}
+
+// SetBucketLifecycle persists the new lifecycle configuration on the bucket.
+func (fs *FSObjects) SetBucketLifecycle(ctx context.Context, bucket string, lifecycle *lifecycle.Lifecycle) error {
+	return saveBucketConfig(fs, bucket, bucketLifecycleConfig, lifecycle)
+}
+
+// GetBucketLifecycle will return the lifecycle configuration of a bucket.
+func (fs *FSObjects) GetBucketLifecycle(ctx context.Context, bucket string) (*lifecycle.Lifecycle, error) {
+	return getLifecycleConfig(fs, bucket)
+}
+
+// DeleteBucketLifecycle deletes the lifecycle configuration of a bucket.
+func (fs *FSObjects) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	return removeBucketConfig(ctx, fs, bucket, bucketLifecycleConfig)
+}
+
+// SetBucketTagging persists the new tagging configuration on the bucket.
+func (fs *FSObjects) SetBucketTagging(ctx context.Context, bucket string, t *tagging.Tagging) error {
+	return saveBucketTaggingConfig(fs, bucket, t)
+}
+
+// GetBucketTagging will return the tagging configuration of a bucket.
+func (fs *FSObjects) GetBucketTagging(ctx context.Context, bucket string) (*tagging.Tagging, error) {
+	return getBucketTaggingConfig(fs, bucket)
+}
+
+// DeleteBucketTagging deletes the tagging configuration of a bucket.
+func (fs *FSObjects) DeleteBucketTagging(ctx context.Context, bucket string) error {
+	return removeBucketTaggingConfig(ctx, fs, bucket)
+}
+
+// SetBucketAccessControlPolicy persists the new ACL on the bucket.
+func (fs *FSObjects) SetBucketAccessControlPolicy(ctx context.Context, bucket string, aclPolicy *acl.AccessControlPolicy) error {
+	return saveBucketConfig(fs, bucket, bucketACLConfig, aclPolicy)
+}
+
+// GetBucketAccessControlPolicy will return the ACL of a bucket.
+func (fs *FSObjects) GetBucketAccessControlPolicy(ctx context.Context, bucket string) (*acl.AccessControlPolicy, error) {
+	return getBucketAccessControlPolicy(fs, bucket)
+}
+
+// SetObjectAccessControlPolicy persists the new ACL on the object.
+func (fs *FSObjects) SetObjectAccessControlPolicy(ctx context.Context, bucket, object string, aclPolicy *acl.AccessControlPolicy) error {
+	return saveObjectACLConfig(fs, bucket, object, aclPolicy)
+}
+
+// GetObjectAccessControlPolicy will return the ACL of an object.
+func (fs *FSObjects) GetObjectAccessControlPolicy(ctx context.Context, bucket, object string) (*acl.AccessControlPolicy, error) {
+	return getObjectAccessControlPolicy(fs, bucket, object)
+}
+
 // ListObjectsV2 lists all blobs in bucket filtered by prefix
 func (fs *FSObjects) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
 	loi, err := fs.ListObjects(ctx, bucket, prefix, continuationToken, delimiter, maxKeys)
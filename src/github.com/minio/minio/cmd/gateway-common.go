@@ -30,6 +30,9 @@ var (
 
 	// MustGetUUID function alias.
 	MustGetUUID = mustGetUUID
+
+	// InitNSLock function alias, initializes the lock used by LockObject.
+	InitNSLock = initNSLock
 )
 
 // AnonErrToObjectErr - converts standard http codes into meaningful object layer errors.
@@ -292,6 +295,8 @@ func ErrorRespToObjectError(err error, params ...string) error {
 		} else {
 			err = BucketNotFound{Bucket: bucket}
 		}
+	case "InvalidObjectState":
+		err = ObjectArchived{Bucket: bucket, Object: object}
 	case "XMinioInvalidObjectName":
 		err = ObjectNameInvalid{}
 	case "AccessDenied":
@@ -309,3 +314,14 @@ func ErrorRespToObjectError(err error, params ...string) error {
 
 	return err
 }
+
+// LockObject locks given object for writing, so that gateways can serialize
+// their own updates of an object. Returns function releasing the lock.
+func LockObject(bucket, object string) (unlock func(), err error) {
+	objectLock := globalNSMutex.NewNSLock(bucket, object)
+	if err = objectLock.GetLock(globalObjectTimeout); err != nil {
+		return nil, err
+	}
+
+	return objectLock.Unlock, nil
+}
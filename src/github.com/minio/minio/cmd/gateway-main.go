@@ -119,6 +119,7 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 
 	// Validate if we have access, secret set through environment.
 	gatewayName := gw.Name()
+	globalGatewayName = gatewayName
 	if ctx.Args().First() == "help" {
 		cli.ShowCommandHelpAndExit(ctx, gatewayName, 1)
 	}
@@ -173,9 +174,27 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{})
 	logger.FatalIf(err, "Unable to create new notification system")
 
+	// Create new audit logger.
+	globalAuditLogger, err = getAuditLogger(globalServerConfig)
+	logger.FatalIf(err, "Unable to create new audit logger")
+
 	// Create new policy system.
 	globalPolicySys = NewPolicySys()
 
+	// Create new bucket metadata system.
+	globalBucketMetadataSys = NewBucketMetadataSys()
+
+	// Create new bucket replication, quota and logging systems.
+	globalBucketReplicationSys = NewBucketReplicationSys()
+	globalBucketQuotaSys = NewBucketQuotaSys()
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
+	// Create new IAM system.
+	globalIAMSys = NewIAMSys()
+
+	// Create new ACL system.
+	globalACLSys = NewACLSys()
+
 	router := mux.NewRouter().SkipClean(true)
 
 	// Add healthcheck router
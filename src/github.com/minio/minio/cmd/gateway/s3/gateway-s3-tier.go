@@ -0,0 +1,867 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	miniogo "github.com/minio/minio-go"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/lifecycle"
+
+	minio "github.com/minio/minio/cmd"
+)
+
+const (
+	// Cold tier environment variables.
+	coldTierEndpointEnv  = "MINIO_S3_COLD_TIER_ENDPOINT"
+	coldTierBucketEnv    = "MINIO_S3_COLD_TIER_BUCKET"
+	coldTierAccessKeyEnv = "MINIO_S3_COLD_TIER_ACCESS_KEY"
+	coldTierSecretKeyEnv = "MINIO_S3_COLD_TIER_SECRET_KEY"
+
+	// Prefix of bucket lifecycle configurations in the cold tier bucket.
+	// Bucket names never start with '.', so it does not clash with
+	// archived objects.
+	coldTierConfigPrefix = ".minio.sys/buckets"
+
+	// Prefix of markers of restored objects in the cold tier bucket.
+	coldTierRestorePrefix = ".minio.sys/restored"
+
+	// Lease of lifecycle rounds in the cold tier bucket, shared by all
+	// gateway instances using the same cold tier.
+	coldTierLeaseKey = ".minio.sys/lifecycle-lease.json"
+
+	// Lifecycle configuration file of a bucket.
+	lifecycleConfigFile = "lifecycle.xml"
+
+	// Interval between two lifecycle rounds.
+	lifecycleInterval = 24 * time.Hour
+
+	// Interval between two checks whether a lifecycle round is due.
+	lifecycleTick = time.Hour
+
+	// Duration of the lifecycle lease, it is renewed while a round runs.
+	lifecycleLeaseDuration = 10 * time.Minute
+
+	// Time to wait for concurrent writes of the lifecycle lease before
+	// checking which gateway instance holds it.
+	lifecycleLeaseSettle = 5 * time.Second
+
+	// Maximum number of objects listed at once during a lifecycle round.
+	lifecycleMaxKeys = 1000
+
+	// Size of the parts of restored data uploaded to the backend.
+	restorePartSize = 64 * 1024 * 1024
+)
+
+// User metadata of the stub left in place of an archived object.
+const (
+	archiveStorageClassKey = "X-Amz-Meta-X-Minio-Gateway-Storage-Class"
+	archiveSizeKey         = "X-Amz-Meta-X-Minio-Gateway-Size"
+	archiveETagKey         = "X-Amz-Meta-X-Minio-Gateway-Etag"
+	archiveRestoreKey      = "X-Amz-Meta-X-Minio-Gateway-Restore"
+)
+
+// Standard headers preserved when an object is archived.
+var archiveHeaders = []string{
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+}
+
+// Error returned when the lifecycle lease was taken by another gateway
+// instance during a round.
+var errLifecycleLeaseLost = errors.New("lifecycle lease lost")
+
+// Error returned when an object was overwritten while it was restored.
+var errObjectChanged = errors.New("object changed during restore")
+
+// coldTier - bucket archived objects are moved to, which also holds
+// bucket lifecycle configurations.
+type coldTier struct {
+	client *miniogo.Core
+	bucket string
+
+	// Identifies this gateway instance in the lifecycle lease.
+	leaseOwner string
+}
+
+// lifecycleLease - lease of lifecycle rounds kept in the cold tier, so
+// that a single gateway instance runs a round at a time, and a round is
+// run once per lifecycleInterval by all instances together.
+type lifecycleLease struct {
+	Owner     string    `json:"owner"`
+	Expiry    time.Time `json:"expiry"`
+	LastRound time.Time `json:"lastRound"`
+}
+
+// isAvailable - returns whether the lease can be taken by owner at now,
+// that is it is not held by another instance and a round is due.
+func (lease lifecycleLease) isAvailable(owner string, now time.Time) bool {
+	if lease.Owner != "" && lease.Owner != owner && now.Before(lease.Expiry) {
+		return false
+	}
+
+	return lease.LastRound.IsZero() || now.Sub(lease.LastRound) >= lifecycleInterval
+}
+
+// newColdTier - returns cold tier configured by environment variables, or
+// nil if none is configured. Endpoint and credentials of the backend are
+// used unless set explicitly.
+func newColdTier(endpoint string, secure bool, creds auth.Credentials) (*coldTier, error) {
+	bucket := os.Getenv(coldTierBucketEnv)
+	if bucket == "" {
+		return nil, nil
+	}
+
+	if host := os.Getenv(coldTierEndpointEnv); host != "" {
+		var err error
+		if endpoint, secure, err = minio.ParseGatewayEndpoint(host); err != nil {
+			return nil, err
+		}
+	}
+
+	accessKey, secretKey := creds.AccessKey, creds.SecretKey
+	if value := os.Getenv(coldTierAccessKeyEnv); value != "" {
+		accessKey = value
+	}
+	if value := os.Getenv(coldTierSecretKeyEnv); value != "" {
+		secretKey = value
+	}
+
+	client, err := miniogo.NewCore(endpoint, accessKey, secretKey, secure)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coldTier{client: client, bucket: bucket, leaseOwner: minio.MustGetUUID()}, nil
+}
+
+// readLease - reads the lifecycle lease, a missing lease is never held.
+func (tier *coldTier) readLease() (lease lifecycleLease, err error) {
+	reader, _, err := tier.client.GetObject(tier.bucket, coldTierLeaseKey, miniogo.GetObjectOptions{})
+	if err != nil {
+		if miniogo.ToErrorResponse(err).Code == "NoSuchKey" {
+			return lease, nil
+		}
+		return lease, err
+	}
+	defer reader.Close()
+
+	err = json.NewDecoder(reader).Decode(&lease)
+	return lease, err
+}
+
+// writeLease - writes the lifecycle lease.
+func (tier *coldTier) writeLease(lease lifecycleLease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	metadata := map[string]string{"Content-Type": "application/json"}
+	_, err = tier.client.PutObject(tier.bucket, coldTierLeaseKey, bytes.NewReader(data), int64(len(data)), "", "", metadata)
+	return err
+}
+
+// acquireLease - takes the lifecycle lease if a round is due and no other
+// instance holds it. Instances taking it at once all write the lease, the
+// last write wins once the writes settle.
+func (tier *coldTier) acquireLease() (bool, error) {
+	lease, err := tier.readLease()
+	if err != nil || !lease.isAvailable(tier.leaseOwner, time.Now().UTC()) {
+		return false, err
+	}
+
+	lease.Owner = tier.leaseOwner
+	lease.Expiry = time.Now().UTC().Add(lifecycleLeaseDuration)
+	if err = tier.writeLease(lease); err != nil {
+		return false, err
+	}
+
+	time.Sleep(lifecycleLeaseSettle)
+	if lease, err = tier.readLease(); err != nil {
+		return false, err
+	}
+
+	return lease.Owner == tier.leaseOwner, nil
+}
+
+// renewLease - extends the lifecycle lease, errLifecycleLeaseLost is
+// returned if another instance took it.
+func (tier *coldTier) renewLease() error {
+	lease, err := tier.readLease()
+	if err != nil {
+		return err
+	}
+
+	if lease.Owner != tier.leaseOwner {
+		return errLifecycleLeaseLost
+	}
+
+	lease.Expiry = time.Now().UTC().Add(lifecycleLeaseDuration)
+	return tier.writeLease(lease)
+}
+
+// releaseLease - releases the lifecycle lease once a round is completed.
+func (tier *coldTier) releaseLease() error {
+	return tier.writeLease(lifecycleLease{LastRound: time.Now().UTC()})
+}
+
+// objectKey - returns name of archived copy of given object.
+func (tier *coldTier) objectKey(bucket, object string) string {
+	return bucket + "/" + object
+}
+
+// restoreKey - returns name of the marker of given restored object.
+func (tier *coldTier) restoreKey(bucket, object string) string {
+	return coldTierRestorePrefix + "/" + bucket + "/" + object
+}
+
+// lifecycleKey - returns name of lifecycle configuration of given bucket.
+func (tier *coldTier) lifecycleKey(bucket string) string {
+	return path.Join(coldTierConfigPrefix, bucket, lifecycleConfigFile)
+}
+
+// removeArchiveMetadata - removes stub metadata sent by clients.
+func removeArchiveMetadata(metadata map[string]string) {
+	for k := range metadata {
+		switch http.CanonicalHeaderKey(k) {
+		case archiveStorageClassKey, archiveSizeKey, archiveETagKey, archiveRestoreKey:
+			delete(metadata, k)
+		}
+	}
+}
+
+// archivedObjectMetadata - returns metadata of given object to be kept
+// by its archived copy and its stub.
+func archivedObjectMetadata(info miniogo.ObjectInfo) map[string]string {
+	metadata := map[string]string{"Content-Type": info.ContentType}
+	for k := range info.Metadata {
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") {
+			metadata[k] = info.Metadata.Get(k)
+		}
+	}
+	for _, k := range archiveHeaders {
+		if v := info.Metadata.Get(k); v != "" {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
+// isArchiveStub - returns whether object with given metadata is a stub of
+// an archived object.
+func isArchiveStub(metadata http.Header) bool {
+	return metadata.Get(archiveStorageClassKey) != ""
+}
+
+// isArchiveRestored - returns whether archived object with given metadata
+// is restored and readable.
+func isArchiveRestored(metadata http.Header) bool {
+	status, err := minio.ParseRestoreStatus(metadata.Get(archiveRestoreKey))
+	return err == nil && status.IsRestored(time.Now().UTC())
+}
+
+// fromArchiveStub - returns object info of the archived object if objInfo
+// is of its stub.
+func fromArchiveStub(objInfo minio.ObjectInfo) minio.ObjectInfo {
+	storageClass, ok := objInfo.UserDefined[archiveStorageClassKey]
+	if !ok {
+		return objInfo
+	}
+
+	if size, err := strconv.ParseInt(objInfo.UserDefined[archiveSizeKey], 10, 64); err == nil {
+		objInfo.Size = size
+	}
+	if etag := objInfo.UserDefined[archiveETagKey]; etag != "" {
+		objInfo.ETag = etag
+	}
+
+	if restore := objInfo.UserDefined[archiveRestoreKey]; restore != "" {
+		objInfo.UserDefined["x-amz-restore"] = restore
+	}
+
+	delete(objInfo.UserDefined, archiveStorageClassKey)
+	delete(objInfo.UserDefined, archiveSizeKey)
+	delete(objInfo.UserDefined, archiveETagKey)
+	delete(objInfo.UserDefined, archiveRestoreKey)
+	objInfo.UserDefined["x-amz-storage-class"] = storageClass
+	objInfo.StorageClass = storageClass
+	return objInfo
+}
+
+// lockObject - locks given object while this gateway instance updates it.
+// Other instances are not affected by the lock, stubs are only written on
+// condition the object is unchanged to be safe against them. Objects need
+// no locking without a cold tier.
+func (l *s3Objects) lockObject(bucket, object string) (unlock func(), err error) {
+	if l.tier == nil {
+		return func() {}, nil
+	}
+
+	return minio.LockObject(bucket, object)
+}
+
+// isArchived - returns whether given object is a stub of an archived object.
+func (l *s3Objects) isArchived(bucket, object string) bool {
+	if l.tier == nil {
+		return false
+	}
+
+	oi, err := l.Client.StatObject(bucket, object, miniogo.StatObjectOptions{})
+	return err == nil && isArchiveStub(oi.Metadata)
+}
+
+// removeArchive - removes archived copy of given object and its restore
+// marker from the cold tier.
+func (l *s3Objects) removeArchive(bucket, object string) error {
+	if err := l.tier.client.RemoveObject(l.tier.bucket, l.tier.objectKey(bucket, object)); err != nil {
+		return err
+	}
+
+	return l.tier.client.RemoveObject(l.tier.bucket, l.tier.restoreKey(bucket, object))
+}
+
+// isPreconditionFailed - returns whether a conditional request failed as
+// the object has changed.
+func isPreconditionFailed(err error) bool {
+	return miniogo.ToErrorResponse(err).Code == "PreconditionFailed"
+}
+
+// replaceMetadata - replaces metadata of given object by a copy of itself,
+// on condition the object still has given etag. Returns the new etag of
+// the object.
+func (l *s3Objects) replaceMetadata(bucket, object, etag string, metadata map[string]string) (string, error) {
+	headers := map[string]string{
+		"x-amz-metadata-directive":   "REPLACE",
+		"x-amz-copy-source-if-match": etag,
+	}
+	for k, v := range metadata {
+		headers[k] = v
+	}
+
+	oi, err := l.Client.CopyObject(bucket, object, bucket, object, headers)
+	return oi.ETag, err
+}
+
+// writeStub - replaces given object of given size by a stub with given
+// metadata, on condition the object still has given etag. The object is
+// marked as archived by a conditional copy of itself, then its data is
+// dropped by a multipart upload of its first byte, copied on condition
+// the object is still the marked one.
+func (l *s3Objects) writeStub(bucket, object, etag string, size int64, metadata map[string]string) error {
+	etag, err := l.replaceMetadata(bucket, object, etag, metadata)
+	if err != nil || size == 0 {
+		return err
+	}
+
+	uploadID, err := l.Client.NewMultipartUpload(bucket, object, miniogo.PutObjectOptions{UserMetadata: metadata})
+	if err != nil {
+		return err
+	}
+
+	part, err := l.Client.CopyObjectPart(bucket, object, bucket, object, uploadID, 1, 0, 1, map[string]string{
+		"x-amz-copy-source-if-match": etag,
+	})
+	if err == nil {
+		err = l.Client.CompleteMultipartUpload(bucket, object, uploadID, []miniogo.CompletePart{part})
+	}
+	if err != nil {
+		// Upload is aborted on a best effort basis.
+		l.Client.AbortMultipartUpload(bucket, object, uploadID)
+	}
+
+	return err
+}
+
+// transitionObject - moves data of given object to the cold tier and
+// replaces the object by a stub.
+func (l *s3Objects) transitionObject(ctx context.Context, bucket, object string) error {
+	unlock, err := l.lockObject(bucket, object)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return l.archiveObject(ctx, bucket, object)
+}
+
+// archiveObject - moves data of given object to the cold tier and replaces
+// the object by a stub. The object must be locked by the caller.
+func (l *s3Objects) archiveObject(ctx context.Context, bucket, object string) error {
+	reader, info, err := l.Client.GetObject(bucket, object, miniogo.GetObjectOptions{})
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return minio.ErrorRespToObjectError(err, bucket, object)
+	}
+	defer reader.Close()
+
+	// Object is already archived, or is a restored copy.
+	if isArchiveStub(info.Metadata) {
+		return nil
+	}
+
+	metadata := archivedObjectMetadata(info)
+	if _, err = l.tier.client.PutObject(l.tier.bucket, l.tier.objectKey(bucket, object), reader, info.Size, "", "", metadata); err != nil {
+		logger.LogIf(ctx, err)
+		return minio.ErrorRespToObjectError(err, bucket, object)
+	}
+
+	// Keep the object if it is overwritten while being archived, bypassing
+	// the gateway or through another gateway instance.
+	metadata[archiveStorageClassKey] = lifecycle.Glacier
+	metadata[archiveSizeKey] = strconv.FormatInt(info.Size, 10)
+	metadata[archiveETagKey] = info.ETag
+	if err = l.writeStub(bucket, object, info.ETag, info.Size, metadata); err != nil && !isPreconditionFailed(err) {
+		logger.LogIf(ctx, err)
+		return minio.ErrorRespToObjectError(err, bucket, object)
+	}
+
+	return nil
+}
+
+// RestoreObject starts restoring archived data of the object from the cold
+// tier for given days, the data is copied in background. Restoring a
+// restored object only updates its expiry.
+func (l *s3Objects) RestoreObject(ctx context.Context, bucket, object string, days int) (<-chan error, error) {
+	if l.tier == nil {
+		return nil, minio.ColdTierNotConfigured{StorageClass: lifecycle.Glacier}
+	}
+
+	unlock, err := l.lockObject(bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	oi, err := l.Client.StatObject(bucket, object, miniogo.StatObjectOptions{})
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return nil, minio.ErrorRespToObjectError(err, bucket, object)
+	}
+
+	if !isArchiveStub(oi.Metadata) {
+		return nil, minio.NotImplemented{}
+	}
+
+	doneCh := make(chan error, 1)
+	metadata := archivedObjectMetadata(oi)
+	restored := minio.RestoreStatus{Expiry: lifecycle.ExpectedExpiryTime(time.Now(), days)}
+	if isArchiveRestored(oi.Metadata) {
+		metadata[archiveRestoreKey] = restored.String()
+		if _, err = l.replaceMetadata(bucket, object, oi.ETag, metadata); err != nil {
+			logger.LogIf(ctx, err)
+			return nil, minio.ErrorRespToObjectError(err, bucket, object)
+		}
+		doneCh <- nil
+		close(doneCh)
+		return doneCh, nil
+	}
+
+	// Mark the stub as being restored.
+	metadata[archiveRestoreKey] = minio.RestoreStatus{Ongoing: true}.String()
+	etag, err := l.replaceMetadata(bucket, object, oi.ETag, metadata)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return nil, minio.ErrorRespToObjectError(err, bucket, object)
+	}
+
+	// Object is not locked while its data is copied, the stub is only
+	// replaced if it was not overwritten meanwhile. Request context is
+	// done by then.
+	ctx = logger.SetReqInfo(context.Background(), logger.GetReqInfo(ctx))
+	go func() {
+		defer close(doneCh)
+
+		restoredMetadata := make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			restoredMetadata[k] = v
+		}
+		restoredMetadata[archiveRestoreKey] = restored.String()
+
+		err := l.restoreObject(bucket, object, etag, restoredMetadata)
+		switch err {
+		case nil:
+		case errObjectChanged:
+			// Object was overwritten during the restore, it is kept.
+		default:
+			logger.LogIf(ctx, err)
+
+			// Revert the stub, so that restore can be requested again.
+			delete(metadata, archiveRestoreKey)
+			if _, rerr := l.replaceMetadata(bucket, object, etag, metadata); rerr != nil && !isPreconditionFailed(rerr) {
+				logger.LogIf(ctx, rerr)
+			}
+			err = minio.ErrorRespToObjectError(err, bucket, object)
+		}
+		doneCh <- err
+	}()
+
+	return doneCh, nil
+}
+
+// restoreObject - uploads archived data of the object from the cold tier
+// to the backend, then replaces the stub by it on condition the stub still
+// has given etag. errObjectChanged is returned if the stub was overwritten
+// while the data was uploaded.
+func (l *s3Objects) restoreObject(bucket, object, etag string, metadata map[string]string) error {
+	reader, info, err := l.tier.client.GetObject(l.tier.bucket, l.tier.objectKey(bucket, object), miniogo.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// Data is uploaded as a multipart upload, which is not visible until
+	// it is completed.
+	uploadID, err := l.Client.NewMultipartUpload(bucket, object, miniogo.PutObjectOptions{UserMetadata: metadata})
+	if err != nil {
+		return err
+	}
+
+	parts, err := l.uploadParts(bucket, object, uploadID, reader, info.Size)
+	if err == nil {
+		err = l.completeRestore(bucket, object, uploadID, etag, parts)
+	}
+	if err != nil {
+		// Upload is aborted on a best effort basis.
+		l.Client.AbortMultipartUpload(bucket, object, uploadID)
+		return err
+	}
+
+	_, err = l.tier.client.PutObject(l.tier.bucket, l.tier.restoreKey(bucket, object), bytes.NewReader(nil), 0, "", "", nil)
+	return err
+}
+
+// uploadParts - uploads data of given size as parts of the multipart upload.
+func (l *s3Objects) uploadParts(bucket, object, uploadID string, reader io.Reader, size int64) ([]miniogo.CompletePart, error) {
+	var parts []miniogo.CompletePart
+	for partID := 1; ; partID++ {
+		partSize := size
+		if partSize > restorePartSize {
+			partSize = restorePartSize
+		}
+
+		part, err := l.Client.PutObjectPart(bucket, object, uploadID, partID, io.LimitReader(reader, partSize), partSize, "", "")
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, miniogo.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+
+		if size -= partSize; size == 0 {
+			return parts, nil
+		}
+	}
+}
+
+// completeRestore - completes the multipart upload of restored data if the
+// stub still has given etag.
+func (l *s3Objects) completeRestore(bucket, object, uploadID, etag string, parts []miniogo.CompletePart) error {
+	unlock, err := l.lockObject(bucket, object)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	oi, err := l.Client.StatObject(bucket, object, miniogo.StatObjectOptions{})
+	if err != nil {
+		return err
+	}
+
+	if oi.ETag != etag {
+		return errObjectChanged
+	}
+
+	return l.Client.CompleteMultipartUpload(bucket, object, uploadID, parts)
+}
+
+// expireRestores - replaces restored objects whose restore has expired
+// by their stubs.
+func (l *s3Objects) expireRestores(ctx context.Context) error {
+	prefix := coldTierRestorePrefix + "/"
+	marker := ""
+	for {
+		result, err := l.tier.client.ListObjects(l.tier.bucket, prefix, marker, "", lifecycleMaxKeys)
+		if err != nil {
+			return err
+		}
+
+		for _, oi := range result.Contents {
+			tokens := strings.SplitN(strings.TrimPrefix(oi.Key, prefix), "/", 2)
+			if len(tokens) == 2 {
+				l.expireRestore(ctx, tokens[0], tokens[1])
+			}
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+
+		if err = l.tier.renewLease(); err != nil {
+			return err
+		}
+
+		marker = result.NextMarker
+		if marker == "" && len(result.Contents) > 0 {
+			marker = result.Contents[len(result.Contents)-1].Key
+		}
+	}
+}
+
+// expireRestore - replaces restored object by its stub if its restore has
+// expired, and removes its restore marker once it is no longer restored.
+func (l *s3Objects) expireRestore(ctx context.Context, bucket, object string) {
+	ctx = logger.SetReqInfo(ctx, &logger.ReqInfo{BucketName: bucket, ObjectName: object})
+	unlock, err := l.lockObject(bucket, object)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	defer unlock()
+
+	oi, err := l.Client.StatObject(bucket, object, miniogo.StatObjectOptions{})
+	if err != nil && miniogo.ToErrorResponse(err).Code != "NoSuchKey" {
+		logger.LogIf(ctx, err)
+		return
+	}
+
+	// Object is removed or overwritten.
+	if err == nil && isArchiveStub(oi.Metadata) {
+		status, perr := minio.ParseRestoreStatus(oi.Metadata.Get(archiveRestoreKey))
+		if perr == nil && (status.Ongoing || status.IsRestored(time.Now().UTC())) {
+			return
+		}
+
+		metadata := archivedObjectMetadata(oi)
+		delete(metadata, archiveRestoreKey)
+		if err = l.writeStub(bucket, object, oi.ETag, oi.Size, metadata); err != nil {
+			// Object was overwritten meanwhile, the next round will
+			// check it again.
+			if !isPreconditionFailed(err) {
+				logger.LogIf(ctx, err)
+			}
+			return
+		}
+	}
+
+	if err = l.tier.client.RemoveObject(l.tier.bucket, l.tier.restoreKey(bucket, object)); err != nil {
+		logger.LogIf(ctx, err)
+	}
+}
+
+// startLifecycle - periodically applies lifecycle configurations of all
+// buckets until the gateway is shut down. Rounds are run by the gateway
+// instance holding the lifecycle lease.
+func (l *s3Objects) startLifecycle() {
+	ctx := context.Background()
+	ticker := time.NewTicker(lifecycleTick)
+	defer ticker.Stop()
+
+	for {
+		l.runLifecycleRound(ctx)
+
+		select {
+		case <-l.doneCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runLifecycleRound - runs a lifecycle round if this gateway instance
+// takes the lifecycle lease, and releases the lease once it is completed.
+func (l *s3Objects) runLifecycleRound(ctx context.Context) {
+	ok, err := l.tier.acquireLease()
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	if err = l.expireRestores(ctx); err == nil {
+		err = l.lifecycleRound(ctx)
+	}
+	if err != nil {
+		if err != errLifecycleLeaseLost {
+			logger.LogIf(ctx, err)
+		}
+		return
+	}
+
+	if err = l.tier.releaseLease(); err != nil {
+		logger.LogIf(ctx, err)
+	}
+}
+
+// lifecycleRound - applies lifecycle configurations of all buckets once.
+func (l *s3Objects) lifecycleRound(ctx context.Context) error {
+	buckets, err := l.Client.ListBuckets()
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range buckets {
+		bucketCtx := logger.SetReqInfo(ctx, &logger.ReqInfo{BucketName: bucket.Name})
+		lc, err := l.GetBucketLifecycle(bucketCtx, bucket.Name)
+		if err != nil {
+			if _, ok := err.(minio.BucketLifecycleNotFound); !ok {
+				logger.LogIf(bucketCtx, err)
+			}
+			continue
+		}
+
+		if err = l.tier.renewLease(); err != nil {
+			return err
+		}
+
+		if err = l.applyLifecycle(bucketCtx, bucket.Name, *lc); err != nil {
+			if err == errLifecycleLeaseLost {
+				return err
+			}
+			logger.LogIf(bucketCtx, err)
+		}
+	}
+
+	return nil
+}
+
+// applyLifecycle - expires and archives objects of the bucket as per lifecycle.
+func (l *s3Objects) applyLifecycle(ctx context.Context, bucket string, lc lifecycle.Lifecycle) error {
+	if !lc.HasExpiry() && !lc.HasTransition() {
+		return nil
+	}
+
+	prefix := lc.CommonPrefix()
+	marker := ""
+	for {
+		result, err := l.Client.ListObjects(bucket, prefix, marker, "", lifecycleMaxKeys)
+		if err != nil {
+			return minio.ErrorRespToObjectError(err, bucket)
+		}
+
+		for _, oi := range result.Contents {
+			if lc.ComputeAction(oi.Key, nil, oi.LastModified) == lifecycle.DeleteAction {
+				// Errors are logged by DeleteObject.
+				l.DeleteObject(ctx, bucket, oi.Key)
+				continue
+			}
+
+			if lc.ComputeTransition(oi.Key, nil, oi.LastModified) == lifecycle.Glacier {
+				// Errors are logged by transitionObject.
+				l.transitionObject(ctx, bucket, oi.Key)
+			}
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+
+		if err = l.tier.renewLease(); err != nil {
+			return err
+		}
+
+		marker = result.NextMarker
+		if marker == "" && len(result.Contents) > 0 {
+			marker = result.Contents[len(result.Contents)-1].Key
+		}
+	}
+}
+
+// SetBucketLifecycle saves lifecycle configuration of the bucket in the
+// cold tier. Only transitions to GLACIER storage class are supported.
+func (l *s3Objects) SetBucketLifecycle(ctx context.Context, bucket string, lc *lifecycle.Lifecycle) error {
+	// Lifecycle configurations are kept in the cold tier.
+	if l.tier == nil {
+		return minio.ColdTierNotConfigured{StorageClass: lifecycle.Glacier}
+	}
+
+	for _, rule := range lc.Rules {
+		if len(rule.NoncurrentVersionTransitions) > 0 {
+			return minio.NotImplemented{}
+		}
+		for _, transition := range rule.Transitions {
+			if transition.StorageClass != lifecycle.Glacier {
+				return minio.NotImplemented{}
+			}
+		}
+	}
+
+	data, err := xml.Marshal(lc)
+	if err != nil {
+		// This should not happen.
+		logger.LogIf(ctx, err)
+		return minio.ErrorRespToObjectError(err, bucket)
+	}
+
+	metadata := map[string]string{"Content-Type": "application/xml"}
+	if _, err = l.tier.client.PutObject(l.tier.bucket, l.tier.lifecycleKey(bucket), bytes.NewReader(data), int64(len(data)), "", "", metadata); err != nil {
+		logger.LogIf(ctx, err)
+		return minio.ErrorRespToObjectError(err, bucket)
+	}
+
+	return nil
+}
+
+// GetBucketLifecycle reads lifecycle configuration of the bucket from the cold tier.
+func (l *s3Objects) GetBucketLifecycle(ctx context.Context, bucket string) (*lifecycle.Lifecycle, error) {
+	// No lifecycle configuration can be set without a cold tier.
+	if l.tier == nil {
+		return nil, minio.BucketLifecycleNotFound{Bucket: bucket}
+	}
+
+	reader, _, err := l.tier.client.GetObject(l.tier.bucket, l.tier.lifecycleKey(bucket), miniogo.GetObjectOptions{})
+	if err != nil {
+		if miniogo.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, minio.BucketLifecycleNotFound{Bucket: bucket}
+		}
+		logger.LogIf(ctx, err)
+		return nil, minio.ErrorRespToObjectError(err, bucket)
+	}
+	defer reader.Close()
+
+	return lifecycle.ParseConfig(reader, bucket)
+}
+
+// DeleteBucketLifecycle removes lifecycle configuration of the bucket from the cold tier.
+func (l *s3Objects) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	// No lifecycle configuration can be set without a cold tier.
+	if l.tier == nil {
+		return minio.BucketLifecycleNotFound{Bucket: bucket}
+	}
+
+	if err := l.tier.client.RemoveObject(l.tier.bucket, l.tier.lifecycleKey(bucket)); err != nil {
+		logger.LogIf(ctx, err)
+		return minio.ErrorRespToObjectError(err, bucket)
+	}
+
+	return nil
+}
@@ -70,6 +70,12 @@ ENVIRONMENT VARIABLES:
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
      MINIO_CACHE_EXPIRY: Cache expiry duration in days.
 
+  COLD TIER:
+     MINIO_S3_COLD_TIER_BUCKET: Bucket objects of GLACIER storage class are archived to.
+     MINIO_S3_COLD_TIER_ENDPOINT: S3 server endpoint of the bucket. Default is ENDPOINT.
+     MINIO_S3_COLD_TIER_ACCESS_KEY: Access key of the bucket. Default is MINIO_ACCESS_KEY.
+     MINIO_S3_COLD_TIER_SECRET_KEY: Secret key of the bucket. Default is MINIO_SECRET_KEY.
+
 EXAMPLES:
   1. Start minio gateway server for AWS S3 backend.
      $ export MINIO_ACCESS_KEY=accesskey
@@ -88,6 +94,13 @@ EXAMPLES:
      $ export MINIO_CACHE_EXCLUDE="bucket1/*;*.png"
      $ export MINIO_CACHE_EXPIRY=40
      $ {{.HelpName}}
+
+  4. Start minio gateway server for AWS S3 backend archiving objects to a cold tier bucket.
+     $ export MINIO_ACCESS_KEY=accesskey
+     $ export MINIO_SECRET_KEY=secretkey
+     $ export MINIO_S3_COLD_TIER_BUCKET=archive
+     $ export MINIO_S3_COLD_TIER_ENDPOINT=https://cold.example.com:9000
+     $ {{.HelpName}}
 `
 
 	minio.RegisterGatewayCommand(cli.Command{
@@ -145,9 +158,23 @@ func (g *S3) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, error)
 		return nil, err
 	}
 
-	return &s3Objects{
+	tier, err := newColdTier(endpoint, secure, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &s3Objects{
 		Client: client,
-	}, nil
+		tier:   tier,
+		doneCh: make(chan struct{}),
+	}
+
+	// Lifecycle configurations are only supported with a cold tier.
+	if tier != nil {
+		go s.startLifecycle()
+	}
+
+	return s, nil
 }
 
 // Production - s3 gateway is production ready.
@@ -158,12 +185,18 @@ func (g *S3) Production() bool {
 // s3Objects implements gateway for Minio and S3 compatible object storage servers.
 type s3Objects struct {
 	minio.GatewayUnsupported
+	minio.DefaultObjectAPI
 	Client *miniogo.Core
+
+	// Cold tier archived objects are moved to, nil if not configured.
+	tier   *coldTier
+	doneCh chan struct{}
 }
 
 // Shutdown saves any gateway metadata to disk
 // if necessary and reload upon next restart.
 func (l *s3Objects) Shutdown(ctx context.Context) error {
+	close(l.doneCh)
 	return nil
 }
 
@@ -287,13 +320,18 @@ func (l *s3Objects) GetObject(ctx context.Context, bucket string, key string, st
 			return minio.ErrorRespToObjectError(err, bucket, key)
 		}
 	}
-	object, _, err := l.Client.GetObject(bucket, key, opts)
+	object, info, err := l.Client.GetObject(bucket, key, opts)
 	if err != nil {
 		logger.LogIf(ctx, err)
 		return minio.ErrorRespToObjectError(err, bucket, key)
 	}
 	defer object.Close()
 
+	// Archived objects are not readable until restored.
+	if isArchiveStub(info.Metadata) && !isArchiveRestored(info.Metadata) {
+		return minio.ObjectArchived{Bucket: bucket, Object: key}
+	}
+
 	if _, err := io.Copy(writer, object); err != nil {
 		logger.LogIf(ctx, err)
 		return minio.ErrorRespToObjectError(err, bucket, key)
@@ -309,24 +347,48 @@ func (l *s3Objects) GetObjectInfo(ctx context.Context, bucket string, object str
 		return minio.ObjectInfo{}, minio.ErrorRespToObjectError(err, bucket, object)
 	}
 
-	return minio.FromMinioClientObjectInfo(bucket, oi), nil
+	return fromArchiveStub(minio.FromMinioClientObjectInfo(bucket, oi)), nil
 }
 
 // PutObject creates a new object with the incoming data,
 func (l *s3Objects) PutObject(ctx context.Context, bucket string, object string, data *hash.Reader, metadata map[string]string) (objInfo minio.ObjectInfo, err error) {
+	removeArchiveMetadata(metadata)
+
+	// Objects of GLACIER storage class are archived right away if a
+	// cold tier is configured, else the storage class is passed to
+	// the backend.
+	archive := l.tier != nil && metadata["x-amz-storage-class"] == lifecycle.Glacier
+	if archive {
+		delete(metadata, "x-amz-storage-class")
+	}
+
+	unlock, err := l.lockObject(bucket, object)
+	if err != nil {
+		return objInfo, err
+	}
+	defer unlock()
+
+	archived := l.isArchived(bucket, object)
 	oi, err := l.Client.PutObject(bucket, object, data, data.Size(), data.MD5Base64String(), data.SHA256HexString(), minio.ToMinioClientMetadata(metadata))
 	if err != nil {
 		logger.LogIf(ctx, err)
 		return objInfo, minio.ErrorRespToObjectError(err, bucket, object)
 	}
 
-	if storageClass, ok := metadata["x-amz-storage-class"]; ok {
-		if storageClass == "GLACIER" {
-			// Archive the object right away
-			//TODO
+	// Archived copy of the replaced object is not needed anymore.
+	if archived {
+		if err = l.removeArchive(bucket, object); err != nil {
+			logger.LogIf(ctx, err)
 		}
 	}
 
+	if archive {
+		if err = l.archiveObject(ctx, bucket, object); err != nil {
+			return objInfo, err
+		}
+		return l.GetObjectInfo(ctx, bucket, object)
+	}
+
 	return minio.FromMinioClientObjectInfo(bucket, oi), nil
 }
 
@@ -338,21 +400,50 @@ func (l *s3Objects) CopyObject(ctx context.Context, srcBucket string, srcObject
 	// So preserve it by adding "REPLACE" directive to save all the metadata set by CopyObject API.
 	srcInfo.UserDefined["x-amz-metadata-directive"] = "REPLACE"
 	srcInfo.UserDefined["x-amz-copy-source-if-match"] = srcInfo.ETag
+	removeArchiveMetadata(srcInfo.UserDefined)
+
+	unlock, err := l.lockObject(dstBucket, dstObject)
+	if err != nil {
+		return objInfo, err
+	}
+	defer unlock()
+
+	archived := l.isArchived(dstBucket, dstObject)
 	if _, err = l.Client.CopyObject(srcBucket, srcObject, dstBucket, dstObject, srcInfo.UserDefined); err != nil {
 		logger.LogIf(ctx, err)
 		return objInfo, minio.ErrorRespToObjectError(err, srcBucket, srcObject)
 	}
+
+	// Archived copy of the replaced object is not needed anymore.
+	if archived {
+		if err = l.removeArchive(dstBucket, dstObject); err != nil {
+			logger.LogIf(ctx, err)
+		}
+	}
+
 	return l.GetObjectInfo(ctx, dstBucket, dstObject)
 }
 
 // DeleteObject deletes a blob in bucket
 func (l *s3Objects) DeleteObject(ctx context.Context, bucket string, object string) error {
-	err := l.Client.RemoveObject(bucket, object)
+	unlock, err := l.lockObject(bucket, object)
 	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	archived := l.isArchived(bucket, object)
+	if err = l.Client.RemoveObject(bucket, object); err != nil {
 		logger.LogIf(ctx, err)
 		return minio.ErrorRespToObjectError(err, bucket, object)
 	}
 
+	if archived {
+		if err = l.removeArchive(bucket, object); err != nil {
+			logger.LogIf(ctx, err)
+		}
+	}
+
 	return nil
 }
 
@@ -368,6 +459,8 @@ func (l *s3Objects) ListMultipartUploads(ctx context.Context, bucket string, pre
 
 // NewMultipartUpload upload object in multiple parts
 func (l *s3Objects) NewMultipartUpload(ctx context.Context, bucket string, object string, metadata map[string]string) (uploadID string, err error) {
+	removeArchiveMetadata(metadata)
+
 	// Create PutObject options
 	opts := miniogo.PutObjectOptions{UserMetadata: metadata}
 	uploadID, err = l.Client.NewMultipartUpload(bucket, object, opts)
@@ -427,12 +520,25 @@ func (l *s3Objects) AbortMultipartUpload(ctx context.Context, bucket string, obj
 
 // CompleteMultipartUpload completes ongoing multipart upload and finalizes object
 func (l *s3Objects) CompleteMultipartUpload(ctx context.Context, bucket string, object string, uploadID string, uploadedParts []minio.CompletePart) (oi minio.ObjectInfo, e error) {
-	err := l.Client.CompleteMultipartUpload(bucket, object, uploadID, minio.ToMinioClientCompleteParts(uploadedParts))
+	unlock, err := l.lockObject(bucket, object)
 	if err != nil {
+		return oi, err
+	}
+	defer unlock()
+
+	archived := l.isArchived(bucket, object)
+	if err = l.Client.CompleteMultipartUpload(bucket, object, uploadID, minio.ToMinioClientCompleteParts(uploadedParts)); err != nil {
 		logger.LogIf(ctx, err)
 		return oi, minio.ErrorRespToObjectError(err, bucket, object)
 	}
 
+	// Archived copy of the replaced object is not needed anymore.
+	if archived {
+		if err = l.removeArchive(bucket, object); err != nil {
+			logger.LogIf(ctx, err)
+		}
+	}
+
 	return l.GetObjectInfo(ctx, bucket, object)
 }
 
@@ -536,16 +642,3 @@ func (l *s3Objects) GetObjectAccessControlPolicy(ctx context.Context, bucket, ob
 	aclPolicy, err := acl.ParseConfig(strings.NewReader(data), bucket)
 	return aclPolicy, minio.ErrorRespToObjectError(err, bucket)
 }
-
-// Lifecycle
-func (l *s3Objects) SetBucketLifecycle(ctx context.Context, bucket string, lifecycle *lifecycle.Lifecycle) error {
-	return minio.NotImplemented{}
-}
-
-func (l *s3Objects) GetBucketLifecycle(ctx context.Context, bucket string) (*lifecycle.Lifecycle, error) {
-	return nil, minio.NotImplemented{}
-}
-
-func (l *s3Objects) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
-	return minio.NotImplemented{}
-}
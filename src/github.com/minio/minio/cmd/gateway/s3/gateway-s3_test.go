@@ -17,11 +17,25 @@
 package s3
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	miniogo "github.com/minio/minio-go"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/lifecycle"
 
 	minio "github.com/minio/minio/cmd"
 )
@@ -84,6 +98,12 @@ func TestS3ToObjectError(t *testing.T) {
 			inputErr:    errResponse("EntityTooSmall"),
 			expectedErr: minio.PartTooSmall{},
 		},
+		{
+			inputErr:    errResponse("InvalidObjectState"),
+			expectedErr: minio.ObjectArchived{Bucket: "bucket", Object: "object"},
+			bucket:      "bucket",
+			object:      "object",
+		},
 		{
 			inputErr:    nil,
 			expectedErr: nil,
@@ -118,3 +138,344 @@ func TestS3ToObjectError(t *testing.T) {
 		}
 	}
 }
+
+func TestFromArchiveStub(t *testing.T) {
+	testCases := []struct {
+		objInfo        minio.ObjectInfo
+		expectedResult minio.ObjectInfo
+	}{
+		// Not a stub.
+		{
+			minio.ObjectInfo{Size: 5, ETag: "etag", UserDefined: map[string]string{"Content-Type": "text/plain"}},
+			minio.ObjectInfo{Size: 5, ETag: "etag", UserDefined: map[string]string{"Content-Type": "text/plain"}},
+		},
+		{
+			minio.ObjectInfo{Size: 0, ETag: "stub-etag", UserDefined: map[string]string{
+				"Content-Type":         "text/plain",
+				"X-Amz-Meta-Color":     "red",
+				archiveStorageClassKey: "GLACIER",
+				archiveSizeKey:         "5",
+				archiveETagKey:         "etag",
+			}},
+			minio.ObjectInfo{Size: 5, ETag: "etag", StorageClass: "GLACIER", UserDefined: map[string]string{
+				"Content-Type":        "text/plain",
+				"X-Amz-Meta-Color":    "red",
+				"x-amz-storage-class": "GLACIER",
+			}},
+		},
+		// Restored stub.
+		{
+			minio.ObjectInfo{Size: 5, ETag: "stub-etag", UserDefined: map[string]string{
+				archiveStorageClassKey: "GLACIER",
+				archiveSizeKey:         "5",
+				archiveETagKey:         "etag",
+				archiveRestoreKey:      `ongoing-request="true"`,
+			}},
+			minio.ObjectInfo{Size: 5, ETag: "etag", StorageClass: "GLACIER", UserDefined: map[string]string{
+				"x-amz-storage-class": "GLACIER",
+				"x-amz-restore":       `ongoing-request="true"`,
+			}},
+		},
+	}
+
+	for i, testCase := range testCases {
+		result := fromArchiveStub(testCase.objInfo)
+
+		if !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestRemoveArchiveMetadata(t *testing.T) {
+	metadata := map[string]string{
+		"x-amz-meta-x-minio-gateway-storage-class": "GLACIER",
+		archiveSizeKey:     "5",
+		archiveRestoreKey:  `ongoing-request="true"`,
+		"X-Amz-Meta-Color": "red",
+	}
+	removeArchiveMetadata(metadata)
+
+	expectedResult := map[string]string{"X-Amz-Meta-Color": "red"}
+	if !reflect.DeepEqual(metadata, expectedResult) {
+		t.Fatalf("expected: %v, got: %v", expectedResult, metadata)
+	}
+}
+
+func TestNoColdTier(t *testing.T) {
+	l := &s3Objects{}
+	expectedErr := minio.ColdTierNotConfigured{StorageClass: lifecycle.Glacier}
+
+	if err := l.SetBucketLifecycle(context.Background(), "bucket", &lifecycle.Lifecycle{}); err != expectedErr {
+		t.Fatalf("expected: %v, got: %v", expectedErr, err)
+	}
+	if _, err := l.RestoreObject(context.Background(), "bucket", "object", 1); err != expectedErr {
+		t.Fatalf("expected: %v, got: %v", expectedErr, err)
+	}
+	if _, err := l.GetBucketLifecycle(context.Background(), "bucket"); err != (minio.BucketLifecycleNotFound{Bucket: "bucket"}) {
+		t.Fatalf("expected: %v, got: %v", minio.BucketLifecycleNotFound{Bucket: "bucket"}, err)
+	}
+}
+
+func TestLifecycleLeaseIsAvailable(t *testing.T) {
+	now := time.Now().UTC()
+	testCases := []struct {
+		lease          lifecycleLease
+		expectedResult bool
+	}{
+		// No lease yet.
+		{lifecycleLease{}, true},
+		// Held by another instance.
+		{lifecycleLease{Owner: "other", Expiry: now.Add(time.Minute)}, false},
+		// Held by another instance which did not renew it.
+		{lifecycleLease{Owner: "other", Expiry: now.Add(-time.Minute)}, true},
+		// Held by this instance.
+		{lifecycleLease{Owner: "self", Expiry: now.Add(time.Minute)}, true},
+		// Round is not due.
+		{lifecycleLease{LastRound: now.Add(-time.Hour)}, false},
+		// Round is due.
+		{lifecycleLease{LastRound: now.Add(-lifecycleInterval)}, true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.lease.isAvailable("self", now)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+// testObject - object kept by testBackend.
+type testObject struct {
+	data   []byte
+	header http.Header
+	etag   string
+}
+
+// testBackend - in-memory S3 backend serving the requests the cold tier
+// needs: objects, copies on condition, multipart uploads and copied parts.
+type testBackend struct {
+	sync.Mutex
+	objects map[string]testObject
+	uploads map[string]map[int][]byte
+	headers map[string]http.Header
+
+	// Called after a part of a multipart upload is uploaded.
+	onUploadPart func()
+}
+
+func newTestBackend() *testBackend {
+	return &testBackend{
+		objects: make(map[string]testObject),
+		uploads: make(map[string]map[int][]byte),
+		headers: make(map[string]http.Header),
+	}
+}
+
+// newTestObject - returns object with given data and metadata of header.
+func newTestObject(data []byte, header http.Header) testObject {
+	metadata := make(http.Header)
+	for k, v := range header {
+		if strings.HasPrefix(k, "X-Amz-Meta-") || k == "Content-Type" {
+			metadata[k] = v
+		}
+	}
+
+	sum := md5.Sum(data)
+	return testObject{data: data, header: metadata, etag: hex.EncodeToString(sum[:])}
+}
+
+func (b *testBackend) putObject(key string, obj testObject) {
+	b.Lock()
+	defer b.Unlock()
+	b.objects[key] = obj
+}
+
+func (b *testBackend) getObject(key string) (testObject, bool) {
+	b.Lock()
+	defer b.Unlock()
+	obj, ok := b.objects[key]
+	return obj, ok
+}
+
+func writeTestError(w http.ResponseWriter, statusCode int, code string) {
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, "<Error><Code>%s</Code></Error>", code)
+}
+
+// copySource - returns data of the copy source of r, checking its etag.
+func (b *testBackend) copySource(w http.ResponseWriter, r *http.Request) (testObject, bool) {
+	src, ok := b.objects[strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/")]
+	if !ok {
+		writeTestError(w, http.StatusNotFound, "NoSuchKey")
+		return src, false
+	}
+
+	if etag := r.Header.Get("X-Amz-Copy-Source-If-Match"); etag != "" && etag != src.etag {
+		writeTestError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return src, false
+	}
+
+	return src, true
+}
+
+func (b *testBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if _, ok := query["location"]; ok {
+		fmt.Fprint(w, "<LocationConstraint></LocationConstraint>")
+		return
+	}
+
+	data, _ := ioutil.ReadAll(r.Body)
+	uploadID := query.Get("uploadId")
+
+	b.Lock()
+	onUploadPart := b.onUploadPart
+	switch {
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		obj, ok := b.objects[key]
+		if !ok {
+			writeTestError(w, http.StatusNotFound, "NoSuchKey")
+			break
+		}
+		for k, v := range obj.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("ETag", `"`+obj.etag+`"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			w.Write(obj.data)
+		}
+	case r.Method == http.MethodPost && query.Get("uploads") != "" || r.Method == http.MethodPost && uploadID == "":
+		uploadID = minio.MustGetUUID()
+		b.uploads[uploadID] = make(map[int][]byte)
+		b.headers[uploadID] = r.Header
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+	case r.Method == http.MethodPost:
+		var partIDs []int
+		for partID := range b.uploads[uploadID] {
+			partIDs = append(partIDs, partID)
+		}
+		sort.Ints(partIDs)
+
+		var buf bytes.Buffer
+		for _, partID := range partIDs {
+			buf.Write(b.uploads[uploadID][partID])
+		}
+		obj := newTestObject(buf.Bytes(), b.headers[uploadID])
+		obj.etag += "-" + strconv.Itoa(len(partIDs))
+		b.objects[key] = obj
+		delete(b.uploads, uploadID)
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><ETag>%s</ETag></CompleteMultipartUploadResult>", strings.SplitN(key, "/", 2)[0], obj.etag)
+	case r.Method == http.MethodPut && uploadID != "":
+		partID, _ := strconv.Atoi(query.Get("partNumber"))
+		if r.Header.Get("X-Amz-Copy-Source") == "" {
+			b.uploads[uploadID][partID] = data
+			w.Header().Set("ETag", newTestObject(data, nil).etag)
+			break
+		}
+
+		onUploadPart = nil
+		src, ok := b.copySource(w, r)
+		if !ok {
+			break
+		}
+		var start, end int
+		fmt.Sscanf(r.Header.Get("X-Amz-Copy-Source-Range"), "bytes=%d-%d", &start, &end)
+		b.uploads[uploadID][partID] = src.data[start : end+1]
+		fmt.Fprintf(w, "<CopyPartResult><ETag>%s</ETag></CopyPartResult>", newTestObject(src.data[start:end+1], nil).etag)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		src, ok := b.copySource(w, r)
+		if !ok {
+			break
+		}
+		obj := newTestObject(src.data, r.Header)
+		b.objects[key] = obj
+		fmt.Fprintf(w, "<CopyObjectResult><ETag>%s</ETag></CopyObjectResult>", obj.etag)
+	case r.Method == http.MethodPut:
+		obj := newTestObject(data, r.Header)
+		b.objects[key] = obj
+		w.Header().Set("ETag", obj.etag)
+	case r.Method == http.MethodDelete && uploadID != "":
+		delete(b.uploads, uploadID)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	b.Unlock()
+
+	if r.Method == http.MethodPut && uploadID != "" && onUploadPart != nil {
+		onUploadPart()
+	}
+}
+
+// Tests an object written while it is restored is not replaced by the
+// restored data.
+func TestRestoreObject(t *testing.T) {
+	minio.InitNSLock(false)
+
+	backend := newTestBackend()
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client, err := miniogo.NewCore(server.Listener.Addr().String(), "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &s3Objects{Client: client, tier: &coldTier{client: client, bucket: "tier", leaseOwner: "test"}}
+
+	archive := func(data string) {
+		backend.putObject("bucket/object", newTestObject([]byte(data), http.Header{"X-Amz-Meta-Color": {"red"}}))
+		if err := l.archiveObject(context.Background(), "bucket", "object"); err != nil {
+			t.Fatal(err)
+		}
+
+		obj, _ := backend.getObject("bucket/object")
+		if len(obj.data) != 1 || obj.header.Get(archiveSizeKey) != strconv.Itoa(len(data)) {
+			t.Fatalf("expected stub of %q, got: %q, %v", data, obj.data, obj.header)
+		}
+	}
+
+	// Object written during the restore is kept.
+	archive("hello")
+	backend.onUploadPart = func() {
+		backend.putObject("bucket/object", newTestObject([]byte("world"), nil))
+	}
+	doneCh, err := l.RestoreObject(context.Background(), "bucket", "object", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-doneCh; err != errObjectChanged {
+		t.Fatalf("expected: %v, got: %v", errObjectChanged, err)
+	}
+	if obj, _ := backend.getObject("bucket/object"); string(obj.data) != "world" || isArchiveStub(obj.header) {
+		t.Fatalf("expected object written during restore, got: %q, %v", obj.data, obj.header)
+	}
+	backend.Lock()
+	uploads := len(backend.uploads)
+	backend.Unlock()
+	if uploads != 0 {
+		t.Fatalf("expected restore upload to be aborted, got %v uploads", uploads)
+	}
+
+	// Object is restored if it is not written meanwhile.
+	archive("hello")
+	backend.onUploadPart = nil
+	if doneCh, err = l.RestoreObject(context.Background(), "bucket", "object", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = <-doneCh; err != nil {
+		t.Fatal(err)
+	}
+	obj, _ := backend.getObject("bucket/object")
+	if string(obj.data) != "hello" || !isArchiveRestored(obj.header) || obj.header.Get("X-Amz-Meta-Color") != "red" {
+		t.Fatalf("expected restored object, got: %q, %v", obj.data, obj.header)
+	}
+	if _, ok := backend.getObject("tier/" + l.tier.restoreKey("bucket", "object")); !ok {
+		t.Fatal("expected restore marker in the cold tier")
+	}
+}
@@ -18,9 +18,12 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/xml"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -227,8 +230,9 @@ func guessIsRPCReq(req *http.Request) bool {
 
 func (h redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	aType := getRequestAuthType(r)
-	// Re-direct only for JWT and anonymous requests from browser.
-	if aType == authTypeJWT || aType == authTypeAnonymous {
+	// Re-direct only for JWT and anonymous requests from browser, which
+	// are not for static websites.
+	if (aType == authTypeJWT || aType == authTypeAnonymous) && !isWebsiteReq(r) {
 		// Re-direction is handled specifically for browser requests.
 		if guessIsBrowserReq(r) && globalIsBrowserEnabled {
 			// Fetch the redirect location if any.
@@ -287,8 +291,9 @@ func setReservedBucketHandler(h http.Handler) http.Handler {
 
 func (h minioReservedBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch {
-	case guessIsRPCReq(r), guessIsBrowserReq(r), guessIsHealthCheckReq(r), guessIsMetricsReq(r), isAdminReq(r):
-		// Allow access to reserved buckets
+	case guessIsRPCReq(r), guessIsBrowserReq(r), guessIsHealthCheckReq(r), guessIsMetricsReq(r), isAdminReq(r), isWebsiteReq(r):
+		// Allow access to reserved buckets, the paths of website
+		// requests do not name buckets.
 	default:
 		// For all other requests reject access to reserved
 		// buckets
@@ -406,7 +411,84 @@ func setCorsHandler(h http.Handler) http.Handler {
 		ExposedHeaders:   commonS3Headers,
 		AllowCredentials: true,
 	})
-	return c.Handler(h)
+	return bucketCORSHandler{handler: h, defaultHandler: c.Handler(h)}
+}
+
+// bucketCORSHandler evaluates the CORS configuration of the bucket of a
+// request, falling back to the global CORS settings for requests without
+// a bucket or to buckets without CORS configuration.
+type bucketCORSHandler struct {
+	handler        http.Handler
+	defaultHandler http.Handler
+}
+
+func (h bucketCORSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	bucket, _ := urlPath2BucketObjectName(r.URL.Path)
+	if isWebsiteReq(r) {
+		bucket = getWebsiteBucketName(r)
+	}
+	if origin == "" || bucket == "" {
+		h.defaultHandler.ServeHTTP(w, r)
+		return
+	}
+	config := globalBucketMetadataSys.GetCORS(bucket)
+	if config == nil {
+		h.defaultHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Preflight request.
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.Header().Add("Vary", "Origin")
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		var headers []string
+		for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, header)
+			}
+		}
+		rule, ok := config.Match(origin, r.Header.Get("Access-Control-Request-Method"), headers)
+		if !ok {
+			writeErrorResponse(w, ErrCORSForbidden, r.URL)
+			return
+		}
+
+		setCORSAllowOrigin(w, origin, rule.IsAnyOrigin())
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+		if len(headers) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+		}
+		if rule.MaxAgeSeconds > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(rule.MaxAgeSeconds))
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Actual request, which is served without CORS headers if no rule
+	// allows it.
+	w.Header().Add("Vary", "Origin")
+	if rule, ok := config.Match(origin, r.Method, nil); ok {
+		setCORSAllowOrigin(w, origin, rule.IsAnyOrigin())
+		if len(rule.ExposeHeaders) > 0 {
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeaders, ", "))
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// setCORSAllowOrigin - allows origin, with credentials unless any origin is
+// allowed.
+func setCORSAllowOrigin(w http.ResponseWriter, origin string, anyOrigin bool) {
+	if anyOrigin {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
 }
 
 // setIgnoreResourcesHandler -
@@ -449,28 +531,24 @@ func ignoreNotImplementedObjectResources(req *http.Request) bool {
 // List of not implemented bucket queries
 var notimplementedBucketResourceNames = map[string]bool{
 	//"acl":            true,
-	"cors": true,
 	//"lifecycle":      true,
-	"logging":        true,
-	"replication":    true,
-	"tagging":        true,
-	"versions":       true,
+	//"replication":    true,
+	//"tagging":     true,
+	//"versions":       true,
 	"requestPayment": true,
-	"versioning":     true,
-	"website":        true,
-	"inventory":      true,
-	"metrics":        true,
-	"accelerate":     true,
+	//"versioning":     true,
+	"inventory":  true,
+	"metrics":    true,
+	"accelerate": true,
 }
 
 // List of not implemented object queries
 var notimplementedObjectResourceNames = map[string]bool{
 	"torrent": true,
 	//"acl":     true,
-	"policy":  true,
-	"tagging": true,
-	//"restore": true,
-	"select": true,
+	"policy": true,
+	//"tagging": true,
+	//"select": true,
 }
 
 // Resource handler ServeHTTP() wrapper
@@ -506,11 +584,30 @@ func (h resourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 type httpResponseRecorder struct {
 	http.ResponseWriter
 	respStatusCode int
+	bytesWritten   int64
+	firstByteTime  time.Time
+	// Beginning of the body of error responses.
+	errorBody bytes.Buffer
 }
 
+// Maximum length of the body of error responses recorded.
+const maxRecordedErrorBodySize = 1024
+
 // Wraps ResponseWriter's Write()
 func (rww *httpResponseRecorder) Write(b []byte) (int, error) {
-	return rww.ResponseWriter.Write(b)
+	if rww.firstByteTime.IsZero() {
+		rww.firstByteTime = UTCNow()
+	}
+	if rww.respStatusCode >= http.StatusBadRequest && rww.errorBody.Len() < maxRecordedErrorBodySize {
+		n := maxRecordedErrorBodySize - rww.errorBody.Len()
+		if n > len(b) {
+			n = len(b)
+		}
+		rww.errorBody.Write(b[:n])
+	}
+	n, err := rww.ResponseWriter.Write(b)
+	rww.bytesWritten += int64(n)
+	return n, err
 }
 
 // Wraps ResponseWriter's Flush()
@@ -521,10 +618,35 @@ func (rww *httpResponseRecorder) Flush() {
 // Wraps ResponseWriter's WriteHeader() and record
 // the response status code
 func (rww *httpResponseRecorder) WriteHeader(httpCode int) {
+	if rww.firstByteTime.IsZero() {
+		rww.firstByteTime = UTCNow()
+	}
 	rww.respStatusCode = httpCode
 	rww.ResponseWriter.WriteHeader(httpCode)
 }
 
+// statusCode - returns the status code of the response, which is
+// implicitly http.StatusOK if no header is written.
+func (rww *httpResponseRecorder) statusCode() int {
+	if rww.respStatusCode == 0 {
+		return http.StatusOK
+	}
+	return rww.respStatusCode
+}
+
+// errorCode - returns the code of an XML error response, or an empty
+// string if the response is not an error.
+func (rww *httpResponseRecorder) errorCode() string {
+	if rww.errorBody.Len() == 0 {
+		return ""
+	}
+	var errorResponse APIErrorResponse
+	if err := xml.Unmarshal(rww.errorBody.Bytes(), &errorResponse); err != nil {
+		return ""
+	}
+	return errorResponse.Code
+}
+
 func (rww *httpResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return rww.ResponseWriter.(http.Hijacker).Hijack()
 }
@@ -543,6 +665,11 @@ func (h httpStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Wraps w to record http response information
 	ww := &httpResponseRecorder{ResponseWriter: w}
 
+	// Request information filled by the API handler, for access and
+	// audit logging.
+	reqInfo := &logger.ReqInfo{}
+	r = r.WithContext(logger.SetReqInfo(r.Context(), reqInfo))
+
 	// Time start before the call is about to start.
 	tBefore := UTCNow()
 
@@ -560,6 +687,15 @@ func (h httpStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Update http statistics
 	globalHTTPStats.updateStats(r, ww, durationSecs)
+
+	// Update metrics of the API and bucket of the request.
+	updateAPIMetrics(reqInfo, ww, durationSecs)
+
+	// Record the request in the access log of its bucket.
+	globalBucketLoggingSys.LogRequest(r, ww, reqInfo, tBefore, tAfter)
+
+	// Send the audit log entry of the request.
+	logAuditEntry(r, ww, reqInfo, tBefore, tAfter)
 }
 
 // pathValidityHandler validates all the incoming paths for
@@ -25,8 +25,11 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/audit"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/certs"
+	"github.com/minio/minio/pkg/iam/validator"
+	"github.com/minio/minio/pkg/kms"
 )
 
 // minio configuration related constants.
@@ -92,6 +95,9 @@ var (
 	// Indicates if the running minio server is an erasure-code backend.
 	globalIsXL = false
 
+	// Name of the gateway backend, empty if not running as a gateway.
+	globalGatewayName = ""
+
 	// This flag is set to 'true' by default
 	globalIsBrowserEnabled = true
 
@@ -120,8 +126,14 @@ var (
 	// Holds the host that was passed using --address
 	globalMinioHost = ""
 
-	globalNotificationSys *NotificationSys
-	globalPolicySys       *PolicySys
+	globalNotificationSys      *NotificationSys
+	globalPolicySys            *PolicySys
+	globalBucketMetadataSys    *BucketMetadataSys
+	globalACLSys               *ACLSys
+	globalBucketReplicationSys *BucketReplicationSys
+	globalBucketQuotaSys       *BucketQuotaSys
+	globalBucketLoggingSys     *BucketLoggingSys
+	globalIAMSys               *IAMSys
 
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
@@ -152,6 +164,15 @@ var (
 	// Global HTTP request statisitics
 	globalHTTPStats = newHTTPStats()
 
+	// Global audit logger, which has targets once configured.
+	globalAuditLogger = audit.NewLogger()
+
+	// Global lifecycle statistics
+	globalLifecycleStats = newLifecycleStats()
+
+	// Global heal statistics
+	globalHealStats = newHealStats()
+
 	// Time when object layer was initialized on start up.
 	globalBootTime time.Time
 
@@ -161,6 +182,9 @@ var (
 	globalIsEnvDomainName bool
 	globalDomainName      string // Root domain for virtual host style requests
 
+	// Root domain for static website requests, which are disabled if empty.
+	globalWebsiteDomainName string
+
 	globalListingTimeout   = newDynamicTimeout( /*30*/ 600*time.Second /*5*/, 600*time.Second) // timeout for listing related ops
 	globalObjectTimeout    = newDynamicTimeout( /*1*/ 10*time.Minute /*10*/, 600*time.Second)  // timeout for Object API related ops
 	globalOperationTimeout = newDynamicTimeout(10*time.Minute /*30*/, 600*time.Second)         // default timeout for general ops
@@ -177,6 +201,15 @@ var (
 	globalIsEnvWORM   bool
 	globalWORMEnabled bool
 
+	// KMS for SSE-S3 and SSE-KMS, nil if not configured, and the
+	// ID of the default master key.
+	globalKMS      kms.KMS
+	globalKMSKeyID string
+
+	// Validator of web identity tokens presented to STS, nil if not
+	// configured.
+	globalSTSValidator *validator.JWT
+
 	// Is Disk Caching set up
 	globalIsDiskCacheEnabled bool
 	// Disk cache drives
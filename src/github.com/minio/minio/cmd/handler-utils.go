@@ -63,6 +63,7 @@ var supportedHeaders = []string{
 	"content-encoding",
 	"content-disposition",
 	amzStorageClass,
+	amzWebsiteRedirectLocation,
 	"expires",
 	// Add more supported headers here.
 }
@@ -0,0 +1,1093 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+const (
+	// IAM configuration directory.
+	iamConfigPrefix = "config/iam"
+
+	// IAM users directory.
+	iamConfigUsersPrefix = iamConfigPrefix + "/users/"
+
+	// IAM groups directory.
+	iamConfigGroupsPrefix = iamConfigPrefix + "/groups/"
+
+	// IAM policies directory.
+	iamConfigPoliciesPrefix = iamConfigPrefix + "/policies/"
+
+	// IAM temporary users directory, holding credentials issued by STS.
+	iamConfigSTSPrefix = iamConfigPrefix + "/sts/"
+
+	// IAM identity file which captures identity credentials.
+	iamIdentityFile = "identity.json"
+
+	// IAM group members file.
+	iamGroupMembersFile = "members.json"
+
+	// IAM policy file of a policy document, or of the policy mapped
+	// to a user or group.
+	iamPolicyFile = "policy.json"
+
+	// Current version of IAM identity, group and policy mapping files.
+	iamFormatVersion1 = 1
+)
+
+// Built-in canned policies.
+const (
+	cannedPolicyReadOnly  = "readonly"
+	cannedPolicyReadWrite = "readwrite"
+	cannedPolicyWriteOnly = "writeonly"
+)
+
+var (
+	errNoSuchUser             = errors.New("Specified user does not exist")
+	errNoSuchGroup            = errors.New("Specified group does not exist")
+	errNoSuchPolicy           = errors.New("Specified canned policy does not exist")
+	errIAMActionNotAllowed    = errors.New("Specified IAM action is not allowed")
+	errCannedPolicyIsReserved = errors.New("Specified canned policy is built-in")
+	errInvalidIAMArgument     = errors.New("Invalid user, group, policy name or account status")
+)
+
+// newCannedPolicy - returns a policy allowing given actions on all
+// buckets and objects to every user it is attached to.
+func newCannedPolicy(actions ...policy.Action) policy.Policy {
+	return policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{
+			policy.NewStatement(
+				policy.Allow,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(actions...),
+				policy.NewResourceSet(policy.NewResource("*", "")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+}
+
+// cannedPolicies - built-in canned policies which cannot be replaced
+// or removed.
+var cannedPolicies = map[string]policy.Policy{
+	cannedPolicyReadOnly: newCannedPolicy(
+		policy.GetBucketLocationAction,
+		policy.GetObjectAction,
+		policy.HeadBucketAction,
+		policy.ListAllMyBucketsAction,
+		policy.ListBucketAction,
+	),
+	cannedPolicyWriteOnly: newCannedPolicy(
+		policy.PutObjectAction,
+	),
+	cannedPolicyReadWrite: newCannedPolicy(
+		policy.AbortMultipartUploadAction,
+		policy.CreateBucketAction,
+		policy.DeleteBucketAction,
+		policy.DeleteBucketPolicyAction,
+		policy.DeleteObjectAction,
+		policy.DeleteObjectTaggingAction,
+		policy.DeleteObjectVersionAction,
+		policy.GetBucketACLAction,
+		policy.GetBucketEncryptionAction,
+		policy.GetBucketLocationAction,
+		policy.GetBucketNotificationAction,
+		policy.GetBucketPolicyAction,
+		policy.GetBucketTaggingAction,
+		policy.GetBucketVersioningAction,
+		policy.GetObjectAction,
+		policy.GetObjectACLAction,
+		policy.GetObjectTaggingAction,
+		policy.GetObjectVersionAction,
+		policy.HeadBucketAction,
+		policy.ListAllMyBucketsAction,
+		policy.ListBucketAction,
+		policy.ListBucketMultipartUploadsAction,
+		policy.ListBucketVersionsAction,
+		policy.ListenBucketNotificationAction,
+		policy.ListMultipartUploadPartsAction,
+		policy.PutBucketACLAction,
+		policy.PutBucketEncryptionAction,
+		policy.PutBucketNotificationAction,
+		policy.PutBucketPolicyAction,
+		policy.PutBucketTaggingAction,
+		policy.PutBucketVersioningAction,
+		policy.PutObjectAction,
+		policy.PutObjectACLAction,
+		policy.PutObjectTaggingAction,
+		policy.RestoreObjectAction,
+	),
+}
+
+// iamUserIdentity - content of a user's identity.json.
+type iamUserIdentity struct {
+	Version     int                  `json:"version"`
+	Credentials auth.Credentials     `json:"credentials"`
+	Status      madmin.AccountStatus `json:"status"`
+}
+
+// iamSTSIdentity - content of a temporary user's identity.json. The
+// permissions of a temporary user are those of its parent user, or of
+// its canned policy for web identities, restricted by its optional
+// session policy.
+type iamSTSIdentity struct {
+	Version       int              `json:"version"`
+	Credentials   auth.Credentials `json:"credentials"`
+	ParentUser    string           `json:"parentUser,omitempty"`
+	PolicyName    string           `json:"policyName,omitempty"`
+	SessionPolicy *policy.Policy   `json:"sessionPolicy,omitempty"`
+}
+
+// iamGroupMembers - content of a group's members.json.
+type iamGroupMembers struct {
+	Version int                  `json:"version"`
+	Status  madmin.AccountStatus `json:"status"`
+	Members []string             `json:"members"`
+}
+
+// iamPolicyMapping - content of a user's or group's policy.json.
+type iamPolicyMapping struct {
+	Version int    `json:"version"`
+	Policy  string `json:"policy"`
+}
+
+// isValidIAMName - checks whether the name of a user, group or policy
+// can be used as a single path element.
+func isValidIAMName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\\")
+}
+
+// IAMSys - IAM subsystem holding users, groups and canned policies.
+type IAMSys struct {
+	sync.RWMutex
+	iamUsersMap        map[string]iamUserIdentity
+	iamUserPolicyMap   map[string]string
+	iamGroupsMap       map[string]iamGroupMembers
+	iamGroupPolicyMap  map[string]string
+	iamPolicyDocsMap   map[string]policy.Policy
+	iamUserGroupMemMap map[string]set.StringSet
+	iamSTSUsersMap     map[string]iamSTSIdentity
+}
+
+// listIAMConfigItems - lists names of the directories under prefix.
+func listIAMConfigItems(objAPI ObjectLayer, prefix string) ([]string, error) {
+	var items []string
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(context.Background(), minioMetaBucket, prefix, marker, "/", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, prefix := range result.Prefixes {
+			items = append(items, path.Base(prefix))
+		}
+
+		if !result.IsTruncated {
+			return items, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// saveIAMConfigItem - encodes and saves an IAM configuration file.
+func saveIAMConfigItem(objAPI ObjectLayer, configFile string, item interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(objAPI, configFile, data)
+}
+
+// deleteIAMConfigItem - removes an IAM configuration file, ignoring
+// files which do not exist.
+func deleteIAMConfigItem(objAPI ObjectLayer, configFile string) error {
+	err := objAPI.DeleteObject(context.Background(), minioMetaBucket, configFile)
+	if err != nil && !isErrObjectNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// loadIAMConfigItem - reads and decodes an IAM configuration file,
+// returns false if it does not exist. Corrupted files are logged and
+// skipped so that they do not prevent loading other items.
+func loadIAMConfigItem(objAPI ObjectLayer, configFile string, item interface{}) (bool, error) {
+	ctx := context.Background()
+	reader, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err = json.NewDecoder(reader).Decode(item); err != nil {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("configFile", configFile)
+		logger.LogIf(logger.SetReqInfo(ctx, reqInfo), err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// loadIAMPolicyMapping - loads the policy mapped to a user or group
+// under prefix into policyMap.
+func loadIAMPolicyMapping(objAPI ObjectLayer, prefix, name string, policyMap map[string]string) error {
+	var mapping iamPolicyMapping
+	ok, err := loadIAMConfigItem(objAPI, prefix+name+"/"+iamPolicyFile, &mapping)
+	if ok {
+		policyMap[name] = mapping.Policy
+	}
+	return err
+}
+
+// loadIAMUser - loads a user and its policy mapping into given maps.
+func loadIAMUser(objAPI ObjectLayer, user string, usersMap map[string]iamUserIdentity, userPolicyMap map[string]string) error {
+	var identity iamUserIdentity
+	ok, err := loadIAMConfigItem(objAPI, iamConfigUsersPrefix+user+"/"+iamIdentityFile, &identity)
+	if !ok {
+		return err
+	}
+	usersMap[user] = identity
+	return loadIAMPolicyMapping(objAPI, iamConfigUsersPrefix, user, userPolicyMap)
+}
+
+// loadIAMGroup - loads a group and its policy mapping into given maps.
+func loadIAMGroup(objAPI ObjectLayer, group string, groupsMap map[string]iamGroupMembers, groupPolicyMap map[string]string) error {
+	var members iamGroupMembers
+	ok, err := loadIAMConfigItem(objAPI, iamConfigGroupsPrefix+group+"/"+iamGroupMembersFile, &members)
+	if !ok {
+		return err
+	}
+	groupsMap[group] = members
+	return loadIAMPolicyMapping(objAPI, iamConfigGroupsPrefix, group, groupPolicyMap)
+}
+
+// loadIAMPolicy - loads a custom canned policy into policyDocsMap.
+func loadIAMPolicy(objAPI ObjectLayer, policyName string, policyDocsMap map[string]policy.Policy) error {
+	var p policy.Policy
+	ok, err := loadIAMConfigItem(objAPI, iamConfigPoliciesPrefix+policyName+"/"+iamPolicyFile, &p)
+	if ok {
+		policyDocsMap[policyName] = p
+	}
+	return err
+}
+
+// loadIAMSTSUser - loads a temporary user into stsUsersMap, expired
+// temporary users are purged.
+func loadIAMSTSUser(objAPI ObjectLayer, user string, stsUsersMap map[string]iamSTSIdentity) error {
+	configFile := iamConfigSTSPrefix + user + "/" + iamIdentityFile
+	var identity iamSTSIdentity
+	ok, err := loadIAMConfigItem(objAPI, configFile, &identity)
+	if !ok {
+		return err
+	}
+	if identity.Credentials.IsExpired() {
+		return deleteIAMConfigItem(objAPI, configFile)
+	}
+	stsUsersMap[user] = identity
+	return nil
+}
+
+// Load - loads all users, groups and policies from the backend,
+// replacing the in-memory state.
+func (sys *IAMSys) Load(objAPI ObjectLayer) error {
+	usersMap := make(map[string]iamUserIdentity)
+	userPolicyMap := make(map[string]string)
+	groupsMap := make(map[string]iamGroupMembers)
+	groupPolicyMap := make(map[string]string)
+	policyDocsMap := make(map[string]policy.Policy)
+	stsUsersMap := make(map[string]iamSTSIdentity)
+
+	users, err := listIAMConfigItems(objAPI, iamConfigUsersPrefix)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err = loadIAMUser(objAPI, user, usersMap, userPolicyMap); err != nil {
+			return err
+		}
+	}
+
+	groups, err := listIAMConfigItems(objAPI, iamConfigGroupsPrefix)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err = loadIAMGroup(objAPI, group, groupsMap, groupPolicyMap); err != nil {
+			return err
+		}
+	}
+
+	policies, err := listIAMConfigItems(objAPI, iamConfigPoliciesPrefix)
+	if err != nil {
+		return err
+	}
+	for _, policyName := range policies {
+		if err = loadIAMPolicy(objAPI, policyName, policyDocsMap); err != nil {
+			return err
+		}
+	}
+
+	stsUsers, err := listIAMConfigItems(objAPI, iamConfigSTSPrefix)
+	if err != nil {
+		return err
+	}
+	for _, user := range stsUsers {
+		if err = loadIAMSTSUser(objAPI, user, stsUsersMap); err != nil {
+			return err
+		}
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.iamUsersMap = usersMap
+	sys.iamUserPolicyMap = userPolicyMap
+	sys.iamGroupsMap = groupsMap
+	sys.iamGroupPolicyMap = groupPolicyMap
+	sys.iamPolicyDocsMap = policyDocsMap
+	sys.iamSTSUsersMap = stsUsersMap
+	sys.updateGroupMemberships()
+	return nil
+}
+
+// LoadUser - reloads a single user and its policy mapping from the
+// backend. Groups of a removed user are reloaded as well since
+// removing a user updates its groups.
+func (sys *IAMSys) LoadUser(objAPI ObjectLayer, accessKey string) error {
+	usersMap := make(map[string]iamUserIdentity)
+	userPolicyMap := make(map[string]string)
+	if err := loadIAMUser(objAPI, accessKey, usersMap, userPolicyMap); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	identity, ok := usersMap[accessKey]
+	if ok {
+		sys.iamUsersMap[accessKey] = identity
+	} else {
+		delete(sys.iamUsersMap, accessKey)
+	}
+	if policyName, ok := userPolicyMap[accessKey]; ok {
+		sys.iamUserPolicyMap[accessKey] = policyName
+	} else {
+		delete(sys.iamUserPolicyMap, accessKey)
+	}
+	groups := sys.iamUserGroupMemMap[accessKey].ToSlice()
+	sys.Unlock()
+
+	if ok {
+		return nil
+	}
+	for _, group := range groups {
+		if err := sys.LoadGroup(objAPI, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadGroup - reloads a single group and its policy mapping from the
+// backend.
+func (sys *IAMSys) LoadGroup(objAPI ObjectLayer, group string) error {
+	groupsMap := make(map[string]iamGroupMembers)
+	groupPolicyMap := make(map[string]string)
+	if err := loadIAMGroup(objAPI, group, groupsMap, groupPolicyMap); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if members, ok := groupsMap[group]; ok {
+		sys.iamGroupsMap[group] = members
+	} else {
+		delete(sys.iamGroupsMap, group)
+	}
+	if policyName, ok := groupPolicyMap[group]; ok {
+		sys.iamGroupPolicyMap[group] = policyName
+	} else {
+		delete(sys.iamGroupPolicyMap, group)
+	}
+	sys.updateGroupMemberships()
+	return nil
+}
+
+// LoadPolicy - reloads a single custom canned policy from the backend.
+func (sys *IAMSys) LoadPolicy(objAPI ObjectLayer, policyName string) error {
+	policyDocsMap := make(map[string]policy.Policy)
+	if err := loadIAMPolicy(objAPI, policyName, policyDocsMap); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if p, ok := policyDocsMap[policyName]; ok {
+		sys.iamPolicyDocsMap[policyName] = p
+	} else {
+		delete(sys.iamPolicyDocsMap, policyName)
+	}
+	return nil
+}
+
+// LoadSTSUser - reloads a single temporary user from the backend.
+func (sys *IAMSys) LoadSTSUser(objAPI ObjectLayer, accessKey string) error {
+	stsUsersMap := make(map[string]iamSTSIdentity)
+	if err := loadIAMSTSUser(objAPI, accessKey, stsUsersMap); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if identity, ok := stsUsersMap[accessKey]; ok {
+		sys.iamSTSUsersMap[accessKey] = identity
+	} else {
+		delete(sys.iamSTSUsersMap, accessKey)
+	}
+	return nil
+}
+
+// LoadItem - reloads a single user, group, policy or temporary user,
+// identified by its configuration directory prefix and name, from the
+// backend.
+func (sys *IAMSys) LoadItem(objAPI ObjectLayer, prefix, name string) error {
+	if !isValidIAMName(name) {
+		return errInvalidIAMArgument
+	}
+
+	switch prefix {
+	case iamConfigUsersPrefix:
+		return sys.LoadUser(objAPI, name)
+	case iamConfigGroupsPrefix:
+		return sys.LoadGroup(objAPI, name)
+	case iamConfigPoliciesPrefix:
+		return sys.LoadPolicy(objAPI, name)
+	case iamConfigSTSPrefix:
+		return sys.LoadSTSUser(objAPI, name)
+	}
+	return errInvalidIAMArgument
+}
+
+// updateGroupMemberships - rebuilds the user to groups map, must be
+// called with the write lock held.
+func (sys *IAMSys) updateGroupMemberships() {
+	sys.iamUserGroupMemMap = make(map[string]set.StringSet)
+	for group, info := range sys.iamGroupsMap {
+		for _, member := range info.Members {
+			if _, ok := sys.iamUserGroupMemMap[member]; !ok {
+				sys.iamUserGroupMemMap[member] = set.NewStringSet()
+			}
+			sys.iamUserGroupMemMap[member].Add(group)
+		}
+	}
+}
+
+// Init - initializes IAM subsystem from the backend.
+func (sys *IAMSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	// Load IAMSys once during boot.
+	if err := sys.Load(objAPI); err != nil {
+		return err
+	}
+
+	// Refresh IAMSys in background.
+	go func() {
+		ticker := time.NewTicker(globalRefreshBucketPolicyInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-ticker.C:
+				logger.LogIf(context.Background(), sys.Load(objAPI))
+			}
+		}
+	}()
+	return nil
+}
+
+// SetUser - adds a user or updates its secret key and status.
+func (sys *IAMSys) SetUser(objAPI ObjectLayer, accessKey string, uinfo madmin.UserInfo) error {
+	if !isValidIAMName(accessKey) {
+		return auth.ErrInvalidAccessKeyLength
+	}
+	// The server credentials cannot be shadowed by a user.
+	if accessKey == globalServerConfig.GetCredential().AccessKey {
+		return errIAMActionNotAllowed
+	}
+
+	cred, err := auth.CreateCredentials(accessKey, uinfo.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	status := uinfo.Status
+	if status == "" {
+		status = madmin.AccountEnabled
+	}
+	if status != madmin.AccountEnabled && status != madmin.AccountDisabled {
+		return errInvalidIAMArgument
+	}
+
+	identity := iamUserIdentity{
+		Version:     iamFormatVersion1,
+		Credentials: cred,
+		Status:      status,
+	}
+	if err = saveIAMConfigItem(objAPI, iamConfigUsersPrefix+accessKey+"/"+iamIdentityFile, identity); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.iamUsersMap[accessKey] = identity
+	return nil
+}
+
+// DeleteUser - removes a user, its policy mapping and its group
+// memberships.
+func (sys *IAMSys) DeleteUser(objAPI ObjectLayer, accessKey string) error {
+	sys.RLock()
+	_, ok := sys.iamUsersMap[accessKey]
+	groups := sys.iamUserGroupMemMap[accessKey].ToSlice()
+	sys.RUnlock()
+	if !ok {
+		return errNoSuchUser
+	}
+
+	for _, group := range groups {
+		if err := sys.UpdateGroupMembers(objAPI, madmin.GroupAddRemove{
+			Group:    group,
+			Members:  []string{accessKey},
+			IsRemove: true,
+		}); err != nil && err != errNoSuchGroup {
+			return err
+		}
+	}
+
+	if err := deleteIAMConfigItem(objAPI, iamConfigUsersPrefix+accessKey+"/"+iamPolicyFile); err != nil {
+		return err
+	}
+	if err := deleteIAMConfigItem(objAPI, iamConfigUsersPrefix+accessKey+"/"+iamIdentityFile); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.iamUsersMap, accessKey)
+	delete(sys.iamUserPolicyMap, accessKey)
+	return nil
+}
+
+// SetUserStatus - enables or disables a user.
+func (sys *IAMSys) SetUserStatus(objAPI ObjectLayer, accessKey string, status madmin.AccountStatus) error {
+	if status != madmin.AccountEnabled && status != madmin.AccountDisabled {
+		return errInvalidIAMArgument
+	}
+
+	sys.RLock()
+	identity, ok := sys.iamUsersMap[accessKey]
+	sys.RUnlock()
+	if !ok {
+		return errNoSuchUser
+	}
+
+	identity.Status = status
+	if err := saveIAMConfigItem(objAPI, iamConfigUsersPrefix+accessKey+"/"+iamIdentityFile, identity); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.iamUsersMap[accessKey] = identity
+	return nil
+}
+
+// setPolicyMapping - saves or removes the policy mapped to a user or
+// group under prefix.
+func (sys *IAMSys) setPolicyMapping(objAPI ObjectLayer, prefix, name, policyName string) error {
+	configFile := prefix + name + "/" + iamPolicyFile
+	if policyName == "" {
+		return deleteIAMConfigItem(objAPI, configFile)
+	}
+
+	sys.RLock()
+	_, ok := sys.getPolicy(policyName)
+	sys.RUnlock()
+	if !ok {
+		return errNoSuchPolicy
+	}
+
+	return saveIAMConfigItem(objAPI, configFile, iamPolicyMapping{
+		Version: iamFormatVersion1,
+		Policy:  policyName,
+	})
+}
+
+// SetUserPolicy - attaches a canned policy to a user. Empty policy
+// name detaches the current policy.
+func (sys *IAMSys) SetUserPolicy(objAPI ObjectLayer, accessKey, policyName string) error {
+	sys.RLock()
+	_, ok := sys.iamUsersMap[accessKey]
+	sys.RUnlock()
+	if !ok {
+		return errNoSuchUser
+	}
+
+	if err := sys.setPolicyMapping(objAPI, iamConfigUsersPrefix, accessKey, policyName); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if policyName == "" {
+		delete(sys.iamUserPolicyMap, accessKey)
+	} else {
+		sys.iamUserPolicyMap[accessKey] = policyName
+	}
+	return nil
+}
+
+// ListUsers - lists all users without their secret keys.
+func (sys *IAMSys) ListUsers() map[string]madmin.UserInfo {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	users := make(map[string]madmin.UserInfo, len(sys.iamUsersMap))
+	for accessKey, identity := range sys.iamUsersMap {
+		groups := sys.iamUserGroupMemMap[accessKey].ToSlice()
+		users[accessKey] = madmin.UserInfo{
+			PolicyName: sys.iamUserPolicyMap[accessKey],
+			MemberOf:   groups,
+			Status:     identity.Status,
+		}
+	}
+	return users
+}
+
+// GetUser - returns credentials of an enabled user, or of a temporary
+// user which has not expired and whose parent user is enabled.
+func (sys *IAMSys) GetUser(accessKey string) (cred auth.Credentials, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if stsIdentity, ok := sys.iamSTSUsersMap[accessKey]; ok {
+		if stsIdentity.Credentials.IsExpired() {
+			return cred, false
+		}
+		if parentUser := stsIdentity.ParentUser; parentUser != "" && parentUser != globalServerConfig.GetCredential().AccessKey {
+			if parent, ok := sys.iamUsersMap[parentUser]; !ok || parent.Status != madmin.AccountEnabled {
+				return cred, false
+			}
+		}
+		return stsIdentity.Credentials, true
+	}
+
+	identity, ok := sys.iamUsersMap[accessKey]
+	if !ok || identity.Status != madmin.AccountEnabled {
+		return cred, false
+	}
+	return identity.Credentials, true
+}
+
+// SetTempUser - adds a temporary user issued by STS.
+func (sys *IAMSys) SetTempUser(objAPI ObjectLayer, identity iamSTSIdentity) error {
+	accessKey := identity.Credentials.AccessKey
+	if !identity.Credentials.IsTemp() || !isValidIAMName(accessKey) {
+		return errInvalidIAMArgument
+	}
+
+	identity.Version = iamFormatVersion1
+	if err := saveIAMConfigItem(objAPI, iamConfigSTSPrefix+accessKey+"/"+iamIdentityFile, identity); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.iamSTSUsersMap[accessKey] = identity
+	return nil
+}
+
+// UpdateGroupMembers - adds users to a group, creating the group if
+// needed, or removes users from it. A group without members is removed.
+func (sys *IAMSys) UpdateGroupMembers(objAPI ObjectLayer, g madmin.GroupAddRemove) error {
+	if !isValidIAMName(g.Group) {
+		return errInvalidIAMArgument
+	}
+
+	sys.RLock()
+	info, ok := sys.iamGroupsMap[g.Group]
+	if !g.IsRemove {
+		for _, member := range g.Members {
+			if _, found := sys.iamUsersMap[member]; !found {
+				sys.RUnlock()
+				return errNoSuchUser
+			}
+		}
+	}
+	sys.RUnlock()
+
+	if !ok {
+		if g.IsRemove {
+			return errNoSuchGroup
+		}
+		info = iamGroupMembers{
+			Version: iamFormatVersion1,
+			Status:  madmin.AccountEnabled,
+		}
+	}
+
+	members := set.CreateStringSet(info.Members...)
+	for _, member := range g.Members {
+		if g.IsRemove {
+			members.Remove(member)
+		} else {
+			members.Add(member)
+		}
+	}
+	info.Members = members.ToSlice()
+
+	if len(info.Members) == 0 {
+		if err := deleteIAMConfigItem(objAPI, iamConfigGroupsPrefix+g.Group+"/"+iamPolicyFile); err != nil {
+			return err
+		}
+		if err := deleteIAMConfigItem(objAPI, iamConfigGroupsPrefix+g.Group+"/"+iamGroupMembersFile); err != nil {
+			return err
+		}
+	} else if err := saveIAMConfigItem(objAPI, iamConfigGroupsPrefix+g.Group+"/"+iamGroupMembersFile, info); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if len(info.Members) == 0 {
+		delete(sys.iamGroupsMap, g.Group)
+		delete(sys.iamGroupPolicyMap, g.Group)
+	} else {
+		sys.iamGroupsMap[g.Group] = info
+	}
+	sys.updateGroupMemberships()
+	return nil
+}
+
+// SetGroupPolicy - attaches a canned policy to a group. Empty policy
+// name detaches the current policy.
+func (sys *IAMSys) SetGroupPolicy(objAPI ObjectLayer, group, policyName string) error {
+	sys.RLock()
+	_, ok := sys.iamGroupsMap[group]
+	sys.RUnlock()
+	if !ok {
+		return errNoSuchGroup
+	}
+
+	if err := sys.setPolicyMapping(objAPI, iamConfigGroupsPrefix, group, policyName); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if policyName == "" {
+		delete(sys.iamGroupPolicyMap, group)
+	} else {
+		sys.iamGroupPolicyMap[group] = policyName
+	}
+	return nil
+}
+
+// ListGroups - lists all groups.
+func (sys *IAMSys) ListGroups() map[string]madmin.GroupInfo {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	groups := make(map[string]madmin.GroupInfo, len(sys.iamGroupsMap))
+	for group, info := range sys.iamGroupsMap {
+		members := append([]string{}, info.Members...)
+		sort.Strings(members)
+		groups[group] = madmin.GroupInfo{
+			PolicyName: sys.iamGroupPolicyMap[group],
+			Members:    members,
+			Status:     info.Status,
+		}
+	}
+	return groups
+}
+
+// SetPolicy - adds or replaces a custom canned policy.
+func (sys *IAMSys) SetPolicy(objAPI ObjectLayer, policyName string, p policy.Policy) error {
+	if !isValidIAMName(policyName) {
+		return errInvalidIAMArgument
+	}
+	if _, ok := cannedPolicies[policyName]; ok {
+		return errCannedPolicyIsReserved
+	}
+
+	if err := saveIAMConfigItem(objAPI, iamConfigPoliciesPrefix+policyName+"/"+iamPolicyFile, p); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.iamPolicyDocsMap[policyName] = p
+	return nil
+}
+
+// DeletePolicy - removes a custom canned policy. Users and groups
+// mapped to it lose the permissions it granted.
+func (sys *IAMSys) DeletePolicy(objAPI ObjectLayer, policyName string) error {
+	if _, ok := cannedPolicies[policyName]; ok {
+		return errCannedPolicyIsReserved
+	}
+
+	sys.RLock()
+	_, ok := sys.iamPolicyDocsMap[policyName]
+	sys.RUnlock()
+	if !ok {
+		return errNoSuchPolicy
+	}
+
+	if err := deleteIAMConfigItem(objAPI, iamConfigPoliciesPrefix+policyName+"/"+iamPolicyFile); err != nil {
+		return err
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.iamPolicyDocsMap, policyName)
+	return nil
+}
+
+// ListPolicies - lists built-in and custom canned policies.
+func (sys *IAMSys) ListPolicies() map[string]policy.Policy {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	policies := make(map[string]policy.Policy, len(cannedPolicies)+len(sys.iamPolicyDocsMap))
+	for name, p := range cannedPolicies {
+		policies[name] = p
+	}
+	for name, p := range sys.iamPolicyDocsMap {
+		policies[name] = p
+	}
+	return policies
+}
+
+// hasPolicy - returns whether a built-in or custom canned policy
+// exists.
+func (sys *IAMSys) hasPolicy(policyName string) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	_, ok := sys.getPolicy(policyName)
+	return ok
+}
+
+// getPolicy - returns a built-in or custom canned policy, must be
+// called with the read lock held.
+func (sys *IAMSys) getPolicy(policyName string) (p policy.Policy, ok bool) {
+	if p, ok = cannedPolicies[policyName]; ok {
+		return p, ok
+	}
+	p, ok = sys.iamPolicyDocsMap[policyName]
+	return p, ok
+}
+
+// IsAllowed - checks whether the policies attached to the user or to
+// its enabled groups allow given policy args. A deny statement in any
+// of them takes precedence.
+func (sys *IAMSys) IsAllowed(args policy.Args) bool {
+	if args.IsOwner {
+		return true
+	}
+
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if identity, ok := sys.iamSTSUsersMap[args.AccountName]; ok {
+		return sys.isTempUserAllowed(identity, args)
+	}
+	return sys.isUserAllowed(args)
+}
+
+// isTempUserAllowed - checks whether the session policy of a temporary
+// user, and the policies of its parent user or its canned policy allow
+// given policy args, must be called with the read lock held.
+func (sys *IAMSys) isTempUserAllowed(identity iamSTSIdentity, args policy.Args) bool {
+	if identity.SessionPolicy != nil && !identity.SessionPolicy.IsAllowed(args) {
+		return false
+	}
+
+	switch {
+	case identity.ParentUser == globalServerConfig.GetCredential().AccessKey:
+		return true
+	case identity.ParentUser != "":
+		args.AccountName = identity.ParentUser
+		return sys.isUserAllowed(args)
+	default:
+		p, ok := sys.getPolicy(identity.PolicyName)
+		return ok && p.IsAllowed(args)
+	}
+}
+
+// isUserAllowed - checks whether the policies of a user and its enabled
+// groups allow given policy args, must be called with the read lock
+// held.
+func (sys *IAMSys) isUserAllowed(args policy.Args) bool {
+	p, ok := sys.getUserPolicy(args.AccountName)
+	return ok && p.IsAllowed(args)
+}
+
+// IsDenied - checks whether the policies of an IAM user, or the session
+// policy and the policies of a temporary user explicitly deny given
+// policy args.
+func (sys *IAMSys) IsDenied(args policy.Args) bool {
+	if args.IsOwner {
+		return false
+	}
+
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if identity, ok := sys.iamSTSUsersMap[args.AccountName]; ok {
+		return sys.isTempUserDenied(identity, args)
+	}
+	return sys.isUserDenied(args)
+}
+
+// isTempUserDenied - checks whether the session policy of a temporary
+// user, or the policies of its parent user or its canned policy deny
+// given policy args, must be called with the read lock held.
+func (sys *IAMSys) isTempUserDenied(identity iamSTSIdentity, args policy.Args) bool {
+	if identity.SessionPolicy != nil && identity.SessionPolicy.IsDenied(args) {
+		return true
+	}
+
+	switch {
+	case identity.ParentUser == globalServerConfig.GetCredential().AccessKey:
+		return false
+	case identity.ParentUser != "":
+		args.AccountName = identity.ParentUser
+		return sys.isUserDenied(args)
+	default:
+		p, ok := sys.getPolicy(identity.PolicyName)
+		return ok && p.IsDenied(args)
+	}
+}
+
+// isUserDenied - checks whether the policies of a user and its enabled
+// groups deny given policy args, must be called with the read lock held.
+func (sys *IAMSys) isUserDenied(args policy.Args) bool {
+	p, ok := sys.getUserPolicy(args.AccountName)
+	return ok && p.IsDenied(args)
+}
+
+// getUserPolicy - returns combined policies of a user and its enabled
+// groups, must be called with the read lock held.
+func (sys *IAMSys) getUserPolicy(accessKey string) (policy.Policy, bool) {
+	if _, ok := sys.iamUsersMap[accessKey]; !ok {
+		return policy.Policy{}, false
+	}
+
+	policyNames := set.NewStringSet()
+	if policyName, ok := sys.iamUserPolicyMap[accessKey]; ok {
+		policyNames.Add(policyName)
+	}
+	for group := range sys.iamUserGroupMemMap[accessKey] {
+		if sys.iamGroupsMap[group].Status != madmin.AccountEnabled {
+			continue
+		}
+		if policyName, ok := sys.iamGroupPolicyMap[group]; ok {
+			policyNames.Add(policyName)
+		}
+	}
+
+	combinedPolicy := policy.Policy{Version: policy.DefaultVersion}
+	for policyName := range policyNames {
+		if p, ok := sys.getPolicy(policyName); ok {
+			combinedPolicy.Statements = append(combinedPolicy.Statements, p.Statements...)
+		}
+	}
+	return combinedPolicy, true
+}
+
+// HasConditionKey - returns whether any policy applicable to given
+// account uses given condition key.
+func (sys *IAMSys) HasConditionKey(accountName string, key condition.Key) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if identity, ok := sys.iamSTSUsersMap[accountName]; ok {
+		if identity.SessionPolicy != nil && identity.SessionPolicy.HasConditionKey(key) {
+			return true
+		}
+		switch {
+		case identity.ParentUser == globalServerConfig.GetCredential().AccessKey:
+			return false
+		case identity.ParentUser != "":
+			accountName = identity.ParentUser
+		default:
+			p, ok := sys.getPolicy(identity.PolicyName)
+			return ok && p.HasConditionKey(key)
+		}
+	}
+
+	p, ok := sys.getUserPolicy(accountName)
+	return ok && p.HasConditionKey(key)
+}
+
+// NewIAMSys - creates new IAM subsystem.
+func NewIAMSys() *IAMSys {
+	return &IAMSys{
+		iamUsersMap:        make(map[string]iamUserIdentity),
+		iamUserPolicyMap:   make(map[string]string),
+		iamGroupsMap:       make(map[string]iamGroupMembers),
+		iamGroupPolicyMap:  make(map[string]string),
+		iamPolicyDocsMap:   make(map[string]policy.Policy),
+		iamUserGroupMemMap: make(map[string]set.StringSet),
+		iamSTSUsersMap:     make(map[string]iamSTSIdentity),
+	}
+}
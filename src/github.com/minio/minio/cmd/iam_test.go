@@ -0,0 +1,378 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+// Tests users, groups and canned policies of IAMSys on FS.
+func TestIAMSys(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+	defer func() { globalIAMSys = NewIAMSys() }()
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	sys := NewIAMSys()
+	accessKey, secretKey := "alice", "alice-secret"
+
+	if err = sys.SetUser(obj, globalServerConfig.GetCredential().AccessKey, madmin.UserInfo{SecretKey: secretKey}); err != errIAMActionNotAllowed {
+		t.Fatalf("Expected %v, got %v", errIAMActionNotAllowed, err)
+	}
+	if err = sys.SetUser(obj, accessKey, madmin.UserInfo{SecretKey: secretKey}); err != nil {
+		t.Fatal(err)
+	}
+	if cred, ok := sys.GetUser(accessKey); !ok || cred.SecretKey != secretKey {
+		t.Fatalf("Expected user %s with secret key %s, got %v", accessKey, secretKey, cred)
+	}
+
+	getArgs := policy.Args{
+		AccountName:     accessKey,
+		Action:          policy.GetObjectAction,
+		BucketName:      "bucket",
+		ConditionValues: map[string][]string{},
+		ObjectName:      "object",
+	}
+	putArgs := getArgs
+	putArgs.Action = policy.PutObjectAction
+
+	// Users without policies are not allowed anything.
+	if sys.IsAllowed(getArgs) {
+		t.Fatal("Expected user without policy to be denied")
+	}
+
+	if err = sys.SetUserPolicy(obj, accessKey, "unknown"); err != errNoSuchPolicy {
+		t.Fatalf("Expected %v, got %v", errNoSuchPolicy, err)
+	}
+	if err = sys.SetUserPolicy(obj, accessKey, cannedPolicyReadOnly); err != nil {
+		t.Fatal(err)
+	}
+	if !sys.IsAllowed(getArgs) || sys.IsAllowed(putArgs) {
+		t.Fatal("Expected readonly user to be allowed GetObject only")
+	}
+
+	// Group policies add to user policies.
+	if err = sys.SetPolicy(obj, cannedPolicyReadWrite, newCannedPolicy()); err != errCannedPolicyIsReserved {
+		t.Fatalf("Expected %v, got %v", errCannedPolicyIsReserved, err)
+	}
+	uploadPolicy := newCannedPolicy(policy.PutObjectAction)
+	uploadPolicy.Statements[0].Resources = policy.NewResourceSet(policy.NewResource("bucket", "*"))
+	if err = sys.SetPolicy(obj, "upload", uploadPolicy); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.UpdateGroupMembers(obj, madmin.GroupAddRemove{Group: "uploaders", Members: []string{accessKey}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.SetGroupPolicy(obj, "uploaders", "upload"); err != nil {
+		t.Fatal(err)
+	}
+	if !sys.IsAllowed(putArgs) {
+		t.Fatal("Expected group policy to allow PutObject")
+	}
+	otherBucketArgs := putArgs
+	otherBucketArgs.BucketName = "other"
+	if sys.IsAllowed(otherBucketArgs) {
+		t.Fatal("Expected group policy to deny PutObject on other bucket")
+	}
+
+	// Deny statements take precedence.
+	denyPolicy := newCannedPolicy(policy.GetObjectAction)
+	denyPolicy.Statements[0] = policy.NewStatement(
+		policy.Deny,
+		policy.NewPrincipal("*"),
+		policy.NewActionSet(policy.GetObjectAction),
+		policy.NewResourceSet(policy.NewResource("bucket", "*")),
+		condition.NewFunctions(),
+	)
+	if err = sys.SetPolicy(obj, "deny-get", denyPolicy); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.UpdateGroupMembers(obj, madmin.GroupAddRemove{Group: "restricted", Members: []string{accessKey}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.SetGroupPolicy(obj, "restricted", "deny-get"); err != nil {
+		t.Fatal(err)
+	}
+	if sys.IsAllowed(getArgs) {
+		t.Fatal("Expected deny statement to take precedence")
+	}
+
+	// Loading from the backend restores the same state.
+	loaded := NewIAMSys()
+	if err = loaded.Load(obj); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.IsAllowed(putArgs) || loaded.IsAllowed(getArgs) {
+		t.Fatal("Expected loaded policies to match saved policies")
+	}
+	if groups := loaded.ListUsers()[accessKey].MemberOf; len(groups) != 2 {
+		t.Fatalf("Expected user to be member of 2 groups, got %v", groups)
+	}
+
+	// Disabled users cannot authenticate.
+	if err = sys.SetUserStatus(obj, accessKey, madmin.AccountDisabled); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sys.GetUser(accessKey); ok {
+		t.Fatal("Expected disabled user to be rejected")
+	}
+
+	// Removing the user removes its group memberships, empty groups
+	// are removed.
+	if err = sys.DeleteUser(obj, accessKey); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.DeleteUser(obj, accessKey); err != errNoSuchUser {
+		t.Fatalf("Expected %v, got %v", errNoSuchUser, err)
+	}
+	if groups := sys.ListGroups(); len(groups) != 0 {
+		t.Fatalf("Expected no groups, got %v", groups)
+	}
+
+	// Reloading the removed user alone reloads its groups as well.
+	if err = loaded.LoadItem(obj, iamConfigUsersPrefix, accessKey); err != nil {
+		t.Fatal(err)
+	}
+	if users := loaded.ListUsers(); len(users) != 0 {
+		t.Fatalf("Expected no users, got %v", users)
+	}
+	if groups := loaded.ListGroups(); len(groups) != 0 {
+		t.Fatalf("Expected no groups, got %v", groups)
+	}
+	if err = loaded.LoadItem(obj, iamConfigUsersPrefix, "../"+accessKey); err != errInvalidIAMArgument {
+		t.Fatalf("Expected %v, got %v", errInvalidIAMArgument, err)
+	}
+
+	// Corrupted entries are skipped.
+	if err = sys.SetUser(obj, "bob", madmin.UserInfo{SecretKey: "bob-secret"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = saveConfig(obj, iamConfigUsersPrefix+"mallory/"+iamIdentityFile, []byte("{")); err != nil {
+		t.Fatal(err)
+	}
+	if err = loaded.Load(obj); err != nil {
+		t.Fatal(err)
+	}
+	if users := loaded.ListUsers(); len(users) != 1 {
+		t.Fatalf("Expected only user bob, got %v", users)
+	}
+}
+
+// Tests that requests signed by IAM users are authorized by their
+// policies.
+func TestCheckRequestAuthTypeIAMUser(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+	defer func() { globalIAMSys = NewIAMSys() }()
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	accessKey, secretKey := "alice", "alice-secret"
+	if err = globalIAMSys.SetUser(obj, accessKey, madmin.UserInfo{SecretKey: secretKey}); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetUserPolicy(obj, accessKey, cannedPolicyReadOnly); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		action    policy.Action
+		accessKey string
+		secretKey string
+		expected  APIErrorCode
+	}{
+		{policy.GetObjectAction, accessKey, secretKey, ErrNone},
+		{policy.PutObjectAction, accessKey, secretKey, ErrAccessDenied},
+		{policy.GetObjectAction, accessKey, "wrong-secret", ErrSignatureDoesNotMatch},
+		{policy.GetObjectAction, "unknown", secretKey, ErrInvalidAccessKeyID},
+	}
+
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4(http.MethodGet, "http://127.0.0.1:9000/bucket/object", 0, nil, testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if errCode := checkRequestAuthType(context.Background(), req, testCase.action, "bucket", "object"); errCode != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, errCode)
+		}
+
+		req, err = newTestSignedRequestV2(http.MethodGet, "http://127.0.0.1:9000/bucket/object", 0, nil, testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RequestURI = req.URL.RequestURI()
+		if errCode := checkRequestAuthType(context.Background(), req, testCase.action, "bucket", "object"); errCode != testCase.expected {
+			t.Errorf("Test %d (V2): expected %v, got %v", i+1, testCase.expected, errCode)
+		}
+	}
+}
+
+// Tests that an explicit deny of IAM or session policies is not
+// overridden by an allow of the bucket policy.
+func TestIsAccountAllowedIAMDeny(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+	defer func() { globalIAMSys = NewIAMSys() }()
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	userPolicy, err := policy.ParseIdentityConfig(strings.NewReader(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": ["s3:GetObject"],
+            "Resource": ["arn:aws:s3:::bucket/*"]
+        },
+        {
+            "Effect": "Deny",
+            "Action": ["s3:GetObject"],
+            "Resource": ["arn:aws:s3:::bucket/secret/*"]
+        }
+    ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionPolicy, err := policy.ParseIdentityConfig(strings.NewReader(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Deny",
+            "Action": ["s3:GetObject", "s3:PutObject"],
+            "Resource": ["arn:aws:s3:::bucket/private/*"]
+        }
+    ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessKey := "alice"
+	if err = globalIAMSys.SetUser(obj, accessKey, madmin.UserInfo{SecretKey: "alice-secret"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetPolicy(obj, "get-not-secret", *userPolicy); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetUserPolicy(obj, accessKey, "get-not-secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	cred, err := auth.GetNewTempCredentials(UTCNow().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = globalIAMSys.SetTempUser(obj, iamSTSIdentity{
+		Credentials:   cred,
+		ParentUser:    accessKey,
+		SessionPolicy: sessionPolicy,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bucket policy allows everyone to read and write the bucket.
+	globalPolicySys.Set("bucket", policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{
+			policy.NewStatement(
+				policy.Allow,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.GetObjectAction, policy.PutObjectAction),
+				policy.NewResourceSet(policy.NewResource("bucket", "*")),
+				condition.NewFunctions(),
+			),
+		},
+	})
+	defer globalPolicySys.Remove("bucket")
+
+	testCases := []struct {
+		accountName    string
+		action         policy.Action
+		objectName     string
+		expectedResult bool
+	}{
+		{accessKey, policy.GetObjectAction, "object", true},
+		{accessKey, policy.PutObjectAction, "object", true},
+		{accessKey, policy.GetObjectAction, "secret/object", false},
+		{"", policy.GetObjectAction, "secret/object", true},
+		{cred.AccessKey, policy.GetObjectAction, "object", true},
+		{cred.AccessKey, policy.GetObjectAction, "secret/object", false},
+		{cred.AccessKey, policy.PutObjectAction, "private/object", false},
+		{accessKey, policy.PutObjectAction, "private/object", true},
+	}
+
+	for i, testCase := range testCases {
+		result := isAccountAllowed(context.Background(), policy.Args{
+			AccountName:     testCase.accountName,
+			Action:          testCase.action,
+			BucketName:      "bucket",
+			ConditionValues: map[string][]string{},
+			ObjectName:      testCase.objectName,
+		})
+		if result != testCase.expectedResult {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/minio/minio/pkg/lifecycle"
+)
+
+// Wrapper for calling lifecycle config tests for both XL and FS.
+func TestLifecycleConfig(t *testing.T) {
+	ExecObjectLayerTest(t, testLifecycleConfig)
+}
+
+// Tests validate saving, reading and removing of bucket lifecycle configuration.
+func testLifecycleConfig(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	if _, err := obj.GetBucketLifecycle(context.Background(), bucketName); err != (BucketLifecycleNotFound{Bucket: bucketName}) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, BucketLifecycleNotFound{Bucket: bucketName}, err)
+	}
+
+	bucketLifecycle := &lifecycle.Lifecycle{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "rule1",
+				Status:     lifecycle.Enabled,
+				Filter:     &lifecycle.Filter{Prefix: "logs/"},
+				Expiration: &lifecycle.Expiration{Days: 30},
+			},
+		},
+	}
+	if err := obj.SetBucketLifecycle(context.Background(), bucketName, bucketLifecycle); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	result, err := obj.GetBucketLifecycle(context.Background(), bucketName)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if result.CommonPrefix() != "logs/" || len(result.Rules) != 1 || result.Rules[0].Expiration.Days != 30 {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, bucketLifecycle, result)
+	}
+
+	if err = obj.DeleteBucketLifecycle(context.Background(), bucketName); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	if err = obj.DeleteBucketLifecycle(context.Background(), bucketName); err != (BucketLifecycleNotFound{Bucket: bucketName}) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, BucketLifecycleNotFound{Bucket: bucketName}, err)
+	}
+}
@@ -102,6 +102,13 @@ func (l LockInfoStateNotBlocked) Error() string {
 	return fmt.Sprintf("Lock state should be \"Blocked\" for <volume> %s, <path> %s, <opsID> %s", l.volume, l.path, l.opsID)
 }
 
+// lockStats - returns the lock counts of the whole namespace.
+func (n *nsLockMap) lockStats() lockStat {
+	n.lockMapMutex.Lock()
+	defer n.lockMapMutex.Unlock()
+	return *n.counters
+}
+
 // Initialize lock info for given (volume, path).
 func (n *nsLockMap) initLockInfoForVolumePath(param nsParam) {
 	n.debugLockMap[param] = &debugLockInfoPerVolumePath{
@@ -34,13 +34,82 @@ var (
 		},
 		[]string{"request_type"},
 	)
+	s3RequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "minio_s3_requests_total",
+			Help: "Total number of S3 requests served by current Minio server instance",
+		},
+		[]string{"api", "bucket"},
+	)
+	s3RequestsDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "minio_s3_requests_duration_seconds",
+			Help:    "Time taken by S3 requests served by current Minio server instance",
+			Buckets: []float64{.001, .003, .005, .1, .5, 1, 5, 10},
+		},
+		[]string{"api", "bucket"},
+	)
+	s3ErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "minio_s3_errors_total",
+			Help: "Total number of S3 requests failed with an S3 error code by current Minio server instance",
+		},
+		[]string{"api", "code"},
+	)
+	diskOperationsDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "minio_disk_operations_duration_seconds",
+			Help:    "Time taken by read and write operations on the local disks of current Minio server instance",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		},
+		[]string{"disk", "operation"},
+	)
+	diskOperationErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "minio_disk_operation_errors_total",
+			Help: "Total number of read and write operations failed on the local disks of current Minio server instance",
+		},
+		[]string{"disk", "operation"},
+	)
+)
+
+// Disk operations recorded in disk metrics.
+const (
+	diskReadOperation  = "read"
+	diskWriteOperation = "write"
 )
 
+// metricsCollectors - collectors of metrics updated by the server.
+var metricsCollectors = []prometheus.Collector{
+	httpRequestsDuration,
+	s3RequestsTotal,
+	s3RequestsDuration,
+	s3ErrorsTotal,
+	diskOperationsDuration,
+	diskOperationErrors,
+}
+
 func init() {
-	prometheus.MustRegister(httpRequestsDuration)
+	prometheus.MustRegister(metricsCollectors...)
 	prometheus.MustRegister(newMinioCollector())
 }
 
+// updateAPIMetrics - records a request served by an API handler in the
+// metrics of its API and bucket.
+func updateAPIMetrics(reqInfo *logger.ReqInfo, w *httpResponseRecorder, durationSecs float64) {
+	// Requests not served by an API handler are not recorded.
+	if reqInfo == nil || reqInfo.API == "" {
+		return
+	}
+
+	labels := prometheus.Labels{"api": reqInfo.API, "bucket": reqInfo.BucketName}
+	s3RequestsTotal.With(labels).Inc()
+	s3RequestsDuration.With(labels).Observe(durationSecs)
+	if code := w.errorCode(); code != "" {
+		s3ErrorsTotal.With(prometheus.Labels{"api": reqInfo.API, "code": code}).Inc()
+	}
+}
+
 // newMinioCollector describes the collector
 // and returns reference of minioCollector
 // It creates the Prometheus Description which is used
@@ -84,6 +153,96 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 		float64(globalConnStats.getTotalInputBytes()),
 	)
 
+	// Multipart uploads aborted by bucket lifecycle
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName("minio", "lifecycle", "aborted_uploads_total"),
+			"Total number of incomplete multipart uploads aborted by bucket lifecycle",
+			nil, nil),
+		prometheus.CounterValue,
+		float64(globalLifecycleStats.getAbortedUploads()),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName("minio", "lifecycle", "reclaimed_bytes_total"),
+			"Total number of bytes reclaimed by aborting incomplete multipart uploads",
+			nil, nil),
+		prometheus.CounterValue,
+		float64(globalLifecycleStats.getReclaimedBytes()),
+	)
+
+	// Heal statistics
+	for itemType, count := range globalHealStats.getScannedItems() {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "heal", "scanned_items_total"),
+				"Total number of items scanned by heal sequences, by type",
+				[]string{"type"}, nil),
+			prometheus.CounterValue,
+			float64(count),
+			string(itemType),
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName("minio", "heal", "failed_items_total"),
+			"Total number of items which failed to heal",
+			nil, nil),
+		prometheus.CounterValue,
+		float64(globalHealStats.getFailures()),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName("minio", "heal", "running_sequences"),
+			"Number of heal sequences running on current Minio server instance",
+			nil, nil),
+		prometheus.GaugeValue,
+		float64(globalAllHealState.runningHealSequences()),
+	)
+
+	// Namespace lock statistics
+	if globalNSMutex != nil {
+		ls := globalNSMutex.lockStats()
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "locks", "granted"),
+				"Number of namespace locks held on current Minio server instance",
+				nil, nil),
+			prometheus.GaugeValue,
+			float64(ls.granted),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "locks", "blocked"),
+				"Number of namespace locks waiting to be granted on current Minio server instance",
+				nil, nil),
+			prometheus.GaugeValue,
+			float64(ls.blocked),
+		)
+	}
+
+	// Bucket usage, as accounted for bucket quotas
+	for bucket, usage := range globalBucketQuotaSys.AllUsage() {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "bucket", "usage_bytes"),
+				"Total size of the objects of a bucket",
+				[]string{"bucket"}, nil),
+			prometheus.GaugeValue,
+			float64(usage.Size),
+			bucket,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "bucket", "objects"),
+				"Total number of objects of a bucket",
+				[]string{"bucket"}, nil),
+			prometheus.GaugeValue,
+			float64(usage.Objects),
+			bucket,
+		)
+	}
+
 	// Expose cache stats only if available
 	cacheObjLayer := newCacheObjectsFn()
 	if cacheObjLayer != nil {
@@ -164,10 +323,12 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 func metricsHandler() http.Handler {
 	registry := prometheus.NewRegistry()
 
-	err := registry.Register(httpRequestsDuration)
-	logger.LogIf(context.Background(), err)
+	for _, collector := range metricsCollectors {
+		err := registry.Register(collector)
+		logger.LogIf(context.Background(), err)
+	}
 
-	err = registry.Register(newMinioCollector())
+	err := registry.Register(newMinioCollector())
 	logger.LogIf(context.Background(), err)
 
 	gatherers := prometheus.Gatherers{
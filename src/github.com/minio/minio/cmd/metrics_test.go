@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	// An S3 error response served by an API handler.
+	rec := &httpResponseRecorder{ResponseWriter: httptest.NewRecorder()}
+	writeErrorResponse(rec, ErrNoSuchKey, &url.URL{Path: "/metrics-bucket/object"})
+	updateAPIMetrics(&logger.ReqInfo{API: "MetricsTest", BucketName: "metrics-bucket"}, rec, 0.01)
+
+	// Requests not served by an API handler are not recorded.
+	updateAPIMetrics(&logger.ReqInfo{BucketName: "metrics-bucket"}, rec, 0.01)
+
+	// Bucket usage accounted for bucket quotas.
+	globalBucketQuotaSys = NewBucketQuotaSys()
+	globalBucketQuotaSys.updateUsage("metrics-bucket", 1024, 2)
+
+	// Disk operations on a local disk.
+	disk, diskPath, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(diskPath)
+	if err = disk.MakeVol("metrics-volume"); err != nil {
+		t.Fatal(err)
+	}
+	if err = disk.AppendFile("metrics-volume", "file", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = disk.ReadAll("metrics-volume", "file"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = disk.ReadAll("metrics-volume", "missing-file"); err != errFileNotFound {
+		t.Fatalf("Expected error `%v`, but instead found `%v`", errFileNotFound, err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/minio/prometheus/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	metricsHandler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the response status to be `%d`, but instead found `%d`", http.StatusOK, w.Code)
+	}
+	body, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []string{
+		`minio_s3_requests_total{api="MetricsTest",bucket="metrics-bucket"} 1`,
+		`minio_s3_requests_duration_seconds_count{api="MetricsTest",bucket="metrics-bucket"} 1`,
+		`minio_s3_errors_total{api="MetricsTest",code="NoSuchKey"} 1`,
+		`minio_bucket_usage_bytes{bucket="metrics-bucket"} 1024`,
+		`minio_bucket_objects{bucket="metrics-bucket"} 2`,
+		fmt.Sprintf(`minio_disk_operations_duration_seconds_count{disk="%s",operation="read"} 2`, diskPath),
+		fmt.Sprintf(`minio_disk_operations_duration_seconds_count{disk="%s",operation="write"} 1`, diskPath),
+		`minio_heal_failed_items_total`,
+		`minio_heal_scanned_items_total{type="object"}`,
+	}
+	for i, testCase := range testCases {
+		if !strings.Contains(string(body), testCase) {
+			t.Errorf("Test %d: Expected metric `%s` to be exposed", i+1, testCase)
+		}
+	}
+
+	// Files not found are not disk errors.
+	if strings.Contains(string(body), fmt.Sprintf(`minio_disk_operation_errors_total{disk="%s"`, diskPath)) {
+		t.Errorf("Expected no disk errors to be exposed")
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/hash"
@@ -169,6 +170,81 @@ func (sys *NotificationSys) RemoveBucketPolicy(bucketName string) <-chan Notific
 	return errCh
 }
 
+// LoadBucketMetadata - calls LoadBucketMetadata RPC call on all peers.
+func (sys *NotificationSys) LoadBucketMetadata(bucketName, configFile string) <-chan NotificationPeerErr {
+	errCh := make(chan NotificationPeerErr)
+	go func() {
+		defer close(errCh)
+
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.LoadBucketMetadata(bucketName, configFile); err != nil {
+					errCh <- NotificationPeerErr{
+						Host: addr,
+						Err:  err,
+					}
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+
+	return errCh
+}
+
+// LoadIAMItem - calls LoadIAMItem RPC call on all peers.
+func (sys *NotificationSys) LoadIAMItem(prefix, name string) <-chan NotificationPeerErr {
+	errCh := make(chan NotificationPeerErr)
+	go func() {
+		defer close(errCh)
+
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.LoadIAMItem(prefix, name); err != nil {
+					errCh <- NotificationPeerErr{
+						Host: addr,
+						Err:  err,
+					}
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+
+	return errCh
+}
+
+// SetObjectACL - calls SetObjectACL RPC call on all peers.
+func (sys *NotificationSys) SetObjectACL(bucketName, objectName string, aclPolicy *acl.AccessControlPolicy) <-chan NotificationPeerErr {
+	errCh := make(chan NotificationPeerErr)
+	go func() {
+		defer close(errCh)
+
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.SetObjectACL(bucketName, objectName, aclPolicy); err != nil {
+					errCh <- NotificationPeerErr{
+						Host: addr,
+						Err:  err,
+					}
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+
+	return errCh
+}
+
 // PutBucketNotification - calls PutBucketNotification RPC call on all peers.
 func (sys *NotificationSys) PutBucketNotification(bucketName string, rulesMap event.RulesMap) <-chan NotificationPeerErr {
 	errCh := make(chan NotificationPeerErr)
@@ -452,6 +528,10 @@ func (sys *NotificationSys) send(bucketName string, eventData event.Event, targe
 
 // Send - sends event data to all matching targets.
 func (sys *NotificationSys) Send(args eventArgs) []event.TargetIDErr {
+	if args.EventName.IsBucketEvent() {
+		return sys.sendBucketEvent(args)
+	}
+
 	sys.RLock()
 	targetIDSet := sys.bucketRulesMap[args.BucketName].Match(args.EventName, args.Object.Name)
 	sys.RUnlock()
@@ -463,6 +543,32 @@ func (sys *NotificationSys) Send(args eventArgs) []event.TargetIDErr {
 	return sys.send(args.BucketName, args.ToEvent(), targetIDs...)
 }
 
+// sendBucketEvent - sends bucket event data to the matching targets of
+// all buckets, as a created or removed bucket has no notification
+// configuration of its own. Rules match against the bucket name. Only
+// targets of the server configuration receive bucket events, never
+// HTTP/PeerRPC client targets listening on another bucket.
+func (sys *NotificationSys) sendBucketEvent(args eventArgs) []event.TargetIDErr {
+	targetIDSet := event.NewTargetIDSet()
+
+	sys.RLock()
+	for bucketName, rulesMap := range sys.bucketRulesMap {
+		matched := rulesMap.Match(args.EventName, args.BucketName)
+		for targetID := range sys.bucketRemoteTargetRulesMap[bucketName] {
+			delete(matched, targetID)
+		}
+		targetIDSet = targetIDSet.Union(matched)
+	}
+	sys.RUnlock()
+
+	if len(targetIDSet) == 0 {
+		return nil
+	}
+
+	targetIDs := targetIDSet.ToSlice()
+	return sys.send(args.BucketName, args.ToEvent(), targetIDs...)
+}
+
 // NewNotificationSys - creates new notification system object.
 func NewNotificationSys(config *serverConfig, endpoints EndpointList) (*NotificationSys, error) {
 	targetList, err := getNotificationTargets(config)
@@ -540,13 +646,22 @@ func (args eventArgs) ToEvent() event.Event {
 		},
 	}
 
-	if args.EventName != event.ObjectRemovedDelete {
+	switch args.EventName {
+	case event.ObjectRemovedDelete, event.ObjectRemovedDeleteMarkerCreated, event.ObjectRemovedExpired:
+	case event.BucketCreated, event.BucketRemoved:
+		newEvent.S3.Object = event.Object{Sequencer: uniqueID}
+	default:
 		newEvent.S3.Object.ETag = args.Object.ETag
 		newEvent.S3.Object.Size = args.Object.Size
 		newEvent.S3.Object.ContentType = args.Object.ContentType
 		newEvent.S3.Object.UserMetadata = args.Object.UserDefined
 	}
 
+	// Versioned buckets report the version of the object.
+	if args.Object.VersionID != "" {
+		newEvent.S3.Object.VersionID = args.Object.VersionID
+	}
+
 	return newEvent
 }
 
@@ -0,0 +1,128 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+// testNotificationTarget - records the events sent to it.
+type testNotificationTarget struct {
+	sync.Mutex
+	id     event.TargetID
+	events []event.Event
+}
+
+func (target *testNotificationTarget) ID() event.TargetID {
+	return target.id
+}
+
+func (target *testNotificationTarget) Send(eventData event.Event) error {
+	target.Lock()
+	defer target.Unlock()
+	target.events = append(target.events, eventData)
+	return nil
+}
+
+func (target *testNotificationTarget) Close() error {
+	return nil
+}
+
+func TestNotificationSysSend(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	target1 := &testNotificationTarget{id: event.TargetID{ID: "1", Name: "webhook"}}
+	target2 := &testNotificationTarget{id: event.TargetID{ID: "2", Name: "webhook"}}
+	target3 := &testNotificationTarget{id: event.TargetID{ID: "3", Name: "listen"}}
+
+	sys := &NotificationSys{
+		targetList:                 event.NewTargetList(),
+		bucketRulesMap:             make(map[string]event.RulesMap),
+		bucketRemoteTargetRulesMap: make(map[string]map[event.TargetID]event.RulesMap),
+	}
+	for _, target := range []event.Target{target1, target2} {
+		if err = sys.targetList.Add(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// target1 receives object and bucket events of bucket1, and is
+	// subscribed to bucket events on bucket2 too.
+	sys.AddRulesMap("bucket1", event.NewRulesMap([]event.Name{event.ObjectRemovedAll, event.BucketCreated, event.BucketRemoved}, "", target1.id))
+	sys.AddRulesMap("bucket2", event.NewRulesMap([]event.Name{event.BucketCreated}, "", target1.id))
+	// target2 receives bucket created events of buckets prefixed with "logs".
+	sys.AddRulesMap("bucket2", event.NewRulesMap([]event.Name{event.BucketCreated}, event.NewPattern("logs", ""), target2.id))
+	// target3 listens for bucket events on bucket1, which are not sent
+	// to HTTP/PeerRPC client targets.
+	if err = sys.AddRemoteTarget("bucket1", target3, event.NewRulesMap([]event.Name{event.BucketCreated, event.BucketRemoved}, "", target3.id)); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []eventArgs{
+		{EventName: event.ObjectRemovedDeleteMarkerCreated, BucketName: "bucket1", Object: ObjectInfo{Name: "object", VersionID: "v1"}},
+		{EventName: event.ObjectRemovedExpired, BucketName: "bucket1", Object: ObjectInfo{Name: "object"}},
+		{EventName: event.ObjectRemovedDelete, BucketName: "bucket2", Object: ObjectInfo{Name: "object"}},
+		{EventName: event.BucketCreated, BucketName: "logs-2018"},
+		{EventName: event.BucketRemoved, BucketName: "photos"},
+	}
+	for i, args := range testCases {
+		if errs := sys.Send(args); len(errs) != 0 {
+			t.Fatalf("test %v: unexpected errors: %v", i+1, errs)
+		}
+	}
+
+	var names1, names2 []string
+	for _, eventData := range target1.events {
+		names1 = append(names1, eventData.EventName.String()+" "+eventData.S3.Bucket.Name+"/"+eventData.S3.Object.Key)
+	}
+	for _, eventData := range target2.events {
+		names2 = append(names2, eventData.EventName.String()+" "+eventData.S3.Bucket.Name+"/"+eventData.S3.Object.Key)
+	}
+	sort.Strings(names1)
+
+	expectedNames1 := []string{
+		"s3:BucketCreated logs-2018/",
+		"s3:BucketRemoved photos/",
+		"s3:ObjectRemoved:DeleteMarkerCreated bucket1/object",
+		"s3:ObjectRemoved:Expired bucket1/object",
+	}
+	if !reflect.DeepEqual(names1, expectedNames1) {
+		t.Fatalf("target1: expected: %v, got: %v", expectedNames1, names1)
+	}
+	if expectedNames2 := []string{"s3:BucketCreated logs-2018/"}; !reflect.DeepEqual(names2, expectedNames2) {
+		t.Fatalf("target2: expected: %v, got: %v", expectedNames2, names2)
+	}
+	if len(target3.events) != 0 {
+		t.Fatalf("target3: expected no events, got: %v", target3.events)
+	}
+
+	for _, eventData := range target1.events {
+		if eventData.EventName == event.ObjectRemovedDeleteMarkerCreated && eventData.S3.Object.VersionID != "v1" {
+			t.Fatalf("expected: version v1, got: %v", eventData.S3.Object.VersionID)
+		}
+	}
+}
@@ -19,7 +19,9 @@ package cmd
 import (
 	"context"
 	"path"
+	"strings"
 	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio/cmd/logger"
@@ -87,6 +89,14 @@ func deleteBucketMetadata(ctx context.Context, bucket string, objAPI ObjectLayer
 	// Delete bucket access policy, if present - ignore any errors.
 	removePolicyConfig(ctx, objAPI, bucket)
 
+	// Delete bucket configuration files, if present - ignore any errors.
+	for configFile := range bucketConfigFormats {
+		removeBucketConfig(ctx, objAPI, bucket, configFile)
+	}
+
+	// Delete bucket tagging config, if present - ignore any errors.
+	removeBucketTaggingConfig(ctx, objAPI, bucket)
+
 	// Delete notification config, if present - ignore any errors.
 	removeNotificationConfig(ctx, objAPI, bucket)
 
@@ -161,3 +171,47 @@ func removeListenerConfig(ctx context.Context, objAPI ObjectLayer, bucket string
 	lcPath := path.Join(bucketConfigPrefix, bucket, bucketListenerConfig)
 	return objAPI.DeleteObject(ctx, minioMetaBucket, lcPath)
 }
+
+// Internal metadata of a multipart upload recording its object and
+// initiation time, as the backend path of an upload is derived from
+// a hash of the object name.
+const (
+	multipartObjectMetaKey    = ReservedMetadataPrefix + "Multipart-Object"
+	multipartInitiatedMetaKey = ReservedMetadataPrefix + "Multipart-Initiated"
+)
+
+// newMultipartMetadata - returns a copy of metadata of a new multipart
+// upload with its bucket, object and initiation time recorded.
+func newMultipartMetadata(bucket, object string, meta map[string]string) map[string]string {
+	uploadMeta := make(map[string]string, len(meta)+2)
+	for k, v := range meta {
+		uploadMeta[k] = v
+	}
+	uploadMeta[multipartObjectMetaKey] = pathJoin(bucket, object)
+	uploadMeta[multipartInitiatedMetaKey] = UTCNow().Format(time.RFC3339Nano)
+	return uploadMeta
+}
+
+// removeMultipartMetadata - removes internal metadata of a multipart
+// upload before it is saved as metadata of the completed object.
+func removeMultipartMetadata(meta map[string]string) {
+	delete(meta, multipartObjectMetaKey)
+	delete(meta, multipartInitiatedMetaKey)
+}
+
+// getMultipartUploadInfo - returns bucket and upload info recorded in
+// metadata of a multipart upload, ok is false for uploads initiated
+// without it.
+func getMultipartUploadInfo(meta map[string]string, uploadID string) (bucket string, upload MultipartInfo, ok bool) {
+	tokens := strings.SplitN(meta[multipartObjectMetaKey], slashSeparator, 2)
+	if len(tokens) != 2 {
+		return "", upload, false
+	}
+
+	initiated, err := time.Parse(time.RFC3339Nano, meta[multipartInitiatedMetaKey])
+	if err != nil {
+		return "", upload, false
+	}
+
+	return tokens[0], MultipartInfo{Object: tokens[1], UploadID: uploadID, Initiated: initiated}, true
+}
@@ -102,6 +102,15 @@ type ObjectInfo struct {
 	// User-Defined metadata
 	UserDefined map[string]string
 
+	// Version ID of the object, empty if the bucket is not versioned.
+	VersionID string
+
+	// IsLatest indicates if this is the current version of the object.
+	IsLatest bool
+
+	// DeleteMarker indicates if this version is a delete marker.
+	DeleteMarker bool
+
 	// List of individual parts, maximum size of upto 10,000
 	Parts []objectPartInfo `json:"-"`
 
@@ -220,6 +229,29 @@ type ListObjectsInfo struct {
 	Prefixes []string
 }
 
+// ListObjectVersionsInfo - container for list object versions.
+type ListObjectVersionsInfo struct {
+	// Indicates whether the returned list is truncated. A value of
+	// true indicates that the list was truncated. The list can be
+	// truncated if the number of versions exceeds the limit allowed
+	// or specified by max keys.
+	IsTruncated bool
+
+	// When response is truncated (the IsTruncated element value in the
+	// response is true), you can use the key name and version ID in
+	// these fields as markers in the subsequent request to get next set
+	// of versions.
+	NextKeyMarker       string
+	NextVersionIDMarker string
+
+	// List of object versions for this request, newest first for
+	// every object.
+	Objects []ObjectInfo
+
+	// List of prefixes for this request.
+	Prefixes []string
+}
+
 // ListObjectsV2Info - container for list objects version 2.
 type ListObjectsV2Info struct {
 	// Indicates whether the returned list objects response is truncated. A
@@ -161,6 +161,44 @@ func (e ObjectNotFound) Error() string {
 	return "Object not found: " + e.Bucket + "#" + e.Object
 }
 
+// ObjectArchived object is archived and must be restored before access.
+type ObjectArchived GenericError
+
+func (e ObjectArchived) Error() string {
+	return "Object is archived: " + e.Bucket + "#" + e.Object
+}
+
+// VersionNotFound version of the object does not exist.
+type VersionNotFound struct {
+	Bucket    string
+	Object    string
+	VersionID string
+}
+
+func (e VersionNotFound) Error() string {
+	return "Version not found: " + e.Bucket + "#" + e.Object + " (" + e.VersionID + ")"
+}
+
+// ObjectVersionDeleteMarker version of the object is a delete marker.
+type ObjectVersionDeleteMarker struct {
+	Bucket    string
+	Object    string
+	VersionID string
+}
+
+func (e ObjectVersionDeleteMarker) Error() string {
+	return "Version is a delete marker: " + e.Bucket + "#" + e.Object + " (" + e.VersionID + ")"
+}
+
+// ColdTierNotConfigured no cold tier is configured for archiving objects.
+type ColdTierNotConfigured struct {
+	StorageClass string
+}
+
+func (e ColdTierNotConfigured) Error() string {
+	return "No cold tier is configured for storage class " + e.StorageClass
+}
+
 // ObjectAlreadyExists object already exists.
 type ObjectAlreadyExists GenericError
 
@@ -223,6 +261,83 @@ func (e BucketPolicyNotFound) Error() string {
 	return "No bucket policy found for bucket: " + e.Bucket
 }
 
+// BucketLifecycleNotFound - no bucket lifecycle found.
+type BucketLifecycleNotFound GenericError
+
+func (e BucketLifecycleNotFound) Error() string {
+	return "No bucket lifecycle found for bucket: " + e.Bucket
+}
+
+// BucketEncryptionNotFound - no bucket encryption found.
+type BucketEncryptionNotFound GenericError
+
+func (e BucketEncryptionNotFound) Error() string {
+	return "No bucket encryption found for bucket: " + e.Bucket
+}
+
+// BucketCORSNotFound - no bucket CORS configuration found.
+type BucketCORSNotFound GenericError
+
+func (e BucketCORSNotFound) Error() string {
+	return "No bucket CORS configuration found for bucket: " + e.Bucket
+}
+
+// BucketQuotaNotFound - no bucket quota configuration found.
+type BucketQuotaNotFound GenericError
+
+func (e BucketQuotaNotFound) Error() string {
+	return "No bucket quota configuration found for bucket: " + e.Bucket
+}
+
+// BucketQuotaExceeded - write exceeds the hard quota of the bucket.
+type BucketQuotaExceeded GenericError
+
+func (e BucketQuotaExceeded) Error() string {
+	return "Bucket quota exceeded for bucket: " + e.Bucket
+}
+
+// BucketWebsiteNotFound - no bucket website configuration found.
+type BucketWebsiteNotFound GenericError
+
+func (e BucketWebsiteNotFound) Error() string {
+	return "No bucket website configuration found for bucket: " + e.Bucket
+}
+
+// BucketLoggingNotFound - no bucket logging configuration found.
+type BucketLoggingNotFound GenericError
+
+func (e BucketLoggingNotFound) Error() string {
+	return "No bucket logging configuration found for bucket: " + e.Bucket
+}
+
+// BucketReplicationNotFound - no bucket replication configuration found.
+type BucketReplicationNotFound GenericError
+
+func (e BucketReplicationNotFound) Error() string {
+	return "No bucket replication configuration found for bucket: " + e.Bucket
+}
+
+// BucketTaggingNotFound - no bucket tagging found.
+type BucketTaggingNotFound GenericError
+
+func (e BucketTaggingNotFound) Error() string {
+	return "No bucket tagging found for bucket: " + e.Bucket
+}
+
+// BucketACLNotFound - no bucket ACL found.
+type BucketACLNotFound GenericError
+
+func (e BucketACLNotFound) Error() string {
+	return "No bucket ACL found for bucket: " + e.Bucket
+}
+
+// ObjectACLNotFound - no object ACL found.
+type ObjectACLNotFound GenericError
+
+func (e ObjectACLNotFound) Error() string {
+	return "No object ACL found for object: " + e.Bucket + "/" + e.Object
+}
+
 /// Bucket related errors.
 
 // BucketNameInvalid - bucketname provided is invalid.
@@ -397,3 +512,12 @@ func isErrObjectNotFound(err error) bool {
 	}
 	return false
 }
+
+// isErrVersionNotFound - Check if error type is VersionNotFound.
+func isErrVersionNotFound(err error) bool {
+	switch err.(type) {
+	case VersionNotFound:
+		return true
+	}
+	return false
+}
@@ -26,6 +26,8 @@ import (
 	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/tagging"
+	"github.com/minio/minio/pkg/versioning"
 )
 
 // ObjectLayer implements primitives for object API layer.
@@ -91,4 +93,33 @@ type ObjectLayer interface {
 	SetBucketLifecycle(context.Context, string, *lifecycle.Lifecycle) error
 	GetBucketLifecycle(context.Context, string) (*lifecycle.Lifecycle, error)
 	DeleteBucketLifecycle(context.Context, string) error
+
+	// Bucket configuration operations, of files in bucketConfigFormats
+	SetBucketConfig(ctx context.Context, bucket, configFile string, config interface{}) error
+	GetBucketConfig(ctx context.Context, bucket, configFile string) (interface{}, error)
+	DeleteBucketConfig(ctx context.Context, bucket, configFile string) error
+
+	// Restore operations, the returned channel receives the result of the
+	// restore once archived data of the object is readable.
+	RestoreObject(ctx context.Context, bucket, object string, days int) (<-chan error, error)
+
+	// Versioning operations
+	SetBucketVersioning(context.Context, string, *versioning.Versioning) error
+	GetBucketVersioning(context.Context, string) (*versioning.Versioning, error)
+	ListObjectVersions(ctx context.Context, bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (result ListObjectVersionsInfo, err error)
+	GetObjectVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer, etag string) (err error)
+	GetObjectVersionInfo(ctx context.Context, bucket, object, versionID string) (objInfo ObjectInfo, err error)
+	DeleteObjectVersion(ctx context.Context, bucket, object, versionID string) (objInfo ObjectInfo, err error)
+
+	// Tagging operations
+	SetBucketTagging(context.Context, string, *tagging.Tagging) error
+	GetBucketTagging(context.Context, string) (*tagging.Tagging, error)
+	DeleteBucketTagging(context.Context, string) error
+	PutObjectTags(ctx context.Context, bucket, object, tags string) error
+
+	// Replication operations
+	PutObjectReplicationStatus(ctx context.Context, bucket, object, etag, status string) error
+
+	// Metadata operations
+	PutObjectMetadata(ctx context.Context, bucket, object, versionID, etag string, metadata map[string]string) error
 }
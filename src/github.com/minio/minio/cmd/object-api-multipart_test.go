@@ -1250,6 +1250,56 @@ func testListMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHan
 	}
 }
 
+// Wrapper for calling listBucketMultipartUploads tests for both XL multiple disks and single node setup.
+func TestListBucketMultipartUploads(t *testing.T) {
+	ExecObjectLayerTest(t, testListBucketMultipartUploads)
+}
+
+// Tests validate listing of multipart uploads of all objects in a bucket.
+func testListBucketMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	buckets := []string{"minio-bucket", "minio-bucket-2"}
+	for _, bucket := range buckets {
+		if err := obj.MakeBucketWithLocation(context.Background(), bucket, ""); err != nil {
+			t.Fatalf("%s : %s", instanceType, err.Error())
+		}
+	}
+
+	objects := []string{"a/b/minio-object", "minio-object"}
+	uploadIDs := make(map[string]string)
+	for _, object := range objects {
+		uploadID, err := obj.NewMultipartUpload(context.Background(), buckets[0], object, nil)
+		if err != nil {
+			t.Fatalf("%s : %s", instanceType, err.Error())
+		}
+		uploadIDs[uploadID] = object
+	}
+	if _, err := obj.NewMultipartUpload(context.Background(), buckets[1], objects[0], nil); err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	lister, ok := obj.(bucketMultipartLister)
+	if !ok {
+		t.Fatalf("%s: expected object layer to list multipart uploads of a bucket", instanceType)
+	}
+
+	uploads, err := lister.listBucketMultipartUploads(context.Background(), buckets[0])
+	if err != nil {
+		t.Fatalf("%s : %s", instanceType, err.Error())
+	}
+
+	if len(uploads) != len(objects) {
+		t.Fatalf("%s: expected: %v uploads, got: %v", instanceType, len(objects), len(uploads))
+	}
+	for _, upload := range uploads {
+		if uploadIDs[upload.UploadID] != upload.Object {
+			t.Fatalf("%s: unexpected upload %v of object %v", instanceType, upload.UploadID, upload.Object)
+		}
+		if upload.Initiated.IsZero() {
+			t.Fatalf("%s: expected initiation time of upload %v", instanceType, upload.UploadID)
+		}
+	}
+}
+
 // Wrapper for calling TestListObjectPartsDiskNotFound tests for both XL multiple disks and single node setup.
 func TestListObjectPartsDiskNotFound(t *testing.T) {
 	ExecObjectLayerDiskAlteredTest(t, testListObjectPartsDiskNotFound)
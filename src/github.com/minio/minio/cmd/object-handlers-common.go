@@ -237,11 +237,16 @@ func deleteObject(ctx context.Context, obj ObjectLayer, cache CacheObjectLayer,
 	if cache != nil {
 		deleteObject = cache.DeleteObject
 	}
+	size := globalBucketQuotaSys.objectSize(ctx, obj, bucket, object)
+
 	// Proceed to delete the object.
 	if err = deleteObject(ctx, bucket, object); err != nil {
 		return err
 	}
 
+	globalBucketQuotaSys.ObjectRemoved(bucket, size)
+	globalBucketReplicationSys.ReplicateDelete(bucket, object)
+
 	// Get host and port from Request.RemoteAddr.
 	host, port, _ := net.SplitHostPort(r.RemoteAddr)
 
@@ -260,3 +265,53 @@ func deleteObject(ctx context.Context, obj ObjectLayer, cache CacheObjectLayer,
 
 	return nil
 }
+
+// deleteObjectVersion is a convenient wrapper to delete an object version
+// in a versioned bucket, sets the version response headers and sends
+// the matching notification event. An empty versionID removes the
+// latest version, which adds a delete marker on versioned buckets.
+func deleteObjectVersion(ctx context.Context, obj ObjectLayer, bucket, object, versionID string, w http.ResponseWriter, r *http.Request) (err error) {
+	objInfo, err := obj.DeleteObjectVersion(ctx, bucket, object, versionID)
+	if err != nil {
+		return err
+	}
+
+	if objInfo.VersionID != "" {
+		w.Header().Set(amzVersionID, objInfo.VersionID)
+	}
+	if objInfo.DeleteMarker {
+		w.Header().Set(amzDeleteMarker, "true")
+	}
+
+	// Only removal of the latest version is replicated, while only
+	// removal of a version frees space, as delete markers hold no data.
+	if versionID == "" {
+		globalBucketReplicationSys.ReplicateDelete(bucket, object)
+	} else if !objInfo.DeleteMarker {
+		globalBucketQuotaSys.ObjectRemoved(bucket, objInfo.Size)
+	}
+
+	// Get host and port from Request.RemoteAddr.
+	host, port, _ := net.SplitHostPort(r.RemoteAddr)
+
+	// Notify object deleted event, or delete marker created event
+	// when the latest version was replaced by a delete marker.
+	eventName := event.ObjectRemovedDelete
+	if objInfo.DeleteMarker {
+		eventName = event.ObjectRemovedDeleteMarkerCreated
+	}
+	sendEvent(eventArgs{
+		EventName:  eventName,
+		BucketName: bucket,
+		Object: ObjectInfo{
+			Name:      object,
+			VersionID: objInfo.VersionID,
+		},
+		ReqParams: extractReqParams(r),
+		UserAgent: r.UserAgent(),
+		Host:      host,
+		Port:      port,
+	})
+
+	return nil
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/minio/minio/pkg/hash"
 	"github.com/minio/minio/pkg/ioutil"
 	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/s3select"
 	sha256 "github.com/minio/sha256-simd"
 	"github.com/minio/sio"
 )
@@ -84,13 +85,26 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		getObjectInfo = api.CacheAPI().GetObjectInfo
 	}
 
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectAction, bucket, object); s3Error != ErrNone {
+	var getAction policy.Action = policy.GetObjectAction
+	versionID := r.URL.Query().Get("versionId")
+	if versionID != "" {
+		if !isValidVersionID(versionID) {
+			writeErrorResponse(w, ErrInvalidVersionID, r.URL)
+			return
+		}
+		getAction = policy.GetObjectVersionAction
+		getObjectInfo = func(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+			return objectAPI.GetObjectVersionInfo(ctx, bucket, object, versionID)
+		}
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, getAction, bucket, object); s3Error != ErrNone {
 		if getRequestAuthType(r) == authTypeAnonymous {
 			// As per "Permission" section in https://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectGET.html
 			// If the object you request does not exist, the error Amazon S3 returns depends on whether you also have the s3:ListBucket permission.
 			// * If you have the s3:ListBucket permission on the bucket, Amazon S3 will return an HTTP status code 404 ("no such key") error.
 			// * if you don’t have the s3:ListBucket permission, Amazon S3 will return an HTTP status code 403 ("access denied") error.`
-			if globalPolicySys.IsAllowed(policy.Args{
+			if isAccountAllowed(ctx, policy.Args{
 				Action:          policy.ListBucketAction,
 				BucketName:      bucket,
 				ConditionValues: getConditionValues(r, ""),
@@ -108,12 +122,23 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 
 	objInfo, err := getObjectInfo(ctx, bucket, object)
 	if err != nil {
+		if _, ok := err.(ObjectVersionDeleteMarker); ok {
+			w.Header().Set(amzDeleteMarker, "true")
+		}
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Archived objects are not readable until restored.
+	if isObjectArchived(objInfo) {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
+	var encrypted bool
 	if objectAPI.IsEncryptionSupported() {
-		if apiErr, _ := DecryptObjectInfo(&objInfo, r.Header); apiErr != ErrNone {
+		var apiErr APIErrorCode
+		if apiErr, encrypted = DecryptObjectInfo(&objInfo, r.Header); apiErr != ErrNone {
 			writeErrorResponse(w, apiErr, r.URL)
 			return
 		}
@@ -151,21 +176,23 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 
 	var writer io.Writer
 	writer = w
-	if objectAPI.IsEncryptionSupported() {
-		if hasSSECustomerHeader(r.Header) {
-			// Response writer should be limited early on for decryption upto required length,
-			// additionally also skipping mod(offset)64KiB boundaries.
-			writer = ioutil.LimitedWriter(writer, startOffset%(64*1024), length)
-
-			writer, startOffset, length, err = DecryptBlocksRequest(writer, r, startOffset, length, objInfo, false)
-			if err != nil {
-				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
-				return
-			}
-
+	if encrypted {
+		if isKMSEncrypted(objInfo.UserDefined) {
+			setSSEHeaders(w, objInfo.UserDefined)
+		} else {
 			w.Header().Set(SSECustomerAlgorithm, r.Header.Get(SSECustomerAlgorithm))
 			w.Header().Set(SSECustomerKeyMD5, r.Header.Get(SSECustomerKeyMD5))
 		}
+
+		// Response writer should be limited early on for decryption upto required length,
+		// additionally also skipping mod(offset)64KiB boundaries.
+		writer = ioutil.LimitedWriter(writer, startOffset%(64*1024), length)
+
+		writer, startOffset, length, err = DecryptBlocksRequest(writer, r, startOffset, length, objInfo, false)
+		if err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
 	}
 
 	setObjectHeaders(w, objInfo, hrange)
@@ -173,9 +200,14 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	httpWriter := ioutil.WriteOnClose(writer)
 
 	getObject := objectAPI.GetObject
-	if api.CacheAPI() != nil && !hasSSECustomerHeader(r.Header) {
+	if api.CacheAPI() != nil && !encrypted {
 		getObject = api.CacheAPI().GetObject
 	}
+	if versionID != "" {
+		getObject = func(ctx context.Context, bucket, object string, startOffset, length int64, writer io.Writer, etag string) error {
+			return objectAPI.GetObjectVersion(ctx, bucket, object, versionID, startOffset, length, writer, etag)
+		}
+	}
 
 	// Reads the object at startOffset and writes to mw.
 	if err = getObject(ctx, bucket, object, startOffset, length, httpWriter, objInfo.ETag); err != nil {
@@ -211,6 +243,106 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 	})
 }
 
+// SelectObjectContentHandler - POST Object?select&select-type=2
+// ----------
+// This implementation of the POST operation filters the contents of a
+// CSV or JSON object by a SQL expression and returns the matching
+// records as event stream.
+func (api objectAPIHandlers) SelectObjectContentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "SelectObject")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objInfo, err := objectAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Archived objects are not readable until restored.
+	if isObjectArchived(objInfo) {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
+	var encrypted bool
+	if objectAPI.IsEncryptionSupported() {
+		var apiErr APIErrorCode
+		if apiErr, encrypted = DecryptObjectInfo(&objInfo, r.Header); apiErr != ErrNone {
+			writeErrorResponse(w, apiErr, r.URL)
+			return
+		}
+	}
+
+	s3Select, err := s3select.NewS3Select(r.Body)
+	if err != nil {
+		apiErr := toAPIErrorCode(err)
+		if apiErr == ErrInternalError {
+			apiErr = ErrMalformedXML
+		}
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	// Object data is read through a pipe while the query is evaluated.
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close()
+
+	var writer io.WriteCloser = pipeWriter
+	var startOffset int64
+	length := objInfo.Size
+	if encrypted {
+		writer, startOffset, length, err = DecryptBlocksRequest(pipeWriter, r, startOffset, length, objInfo, false)
+		if err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
+	go func() {
+		if err := objectAPI.GetObject(ctx, bucket, object, startOffset, length, writer, objInfo.ETag); err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+		pipeWriter.CloseWithError(writer.Close())
+	}()
+
+	setCommonHeaders(w)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	s3Select.Evaluate(pipeReader, w)
+
+	// Get host and port from Request.RemoteAddr.
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host, port = "", ""
+	}
+
+	// Notify object accessed via a Select request.
+	sendEvent(eventArgs{
+		EventName:  event.ObjectAccessedSelect,
+		BucketName: bucket,
+		Object:     objInfo,
+		ReqParams:  extractReqParams(r),
+		UserAgent:  r.UserAgent(),
+		Host:       host,
+		Port:       port,
+	})
+}
+
 // HeadObjectHandler - HEAD Object
 // -----------
 // The HEAD operation retrieves metadata from an object without returning the object itself.
@@ -233,13 +365,26 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 		getObjectInfo = api.CacheAPI().GetObjectInfo
 	}
 
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectAction, bucket, object); s3Error != ErrNone {
+	var getAction policy.Action = policy.GetObjectAction
+	versionID := r.URL.Query().Get("versionId")
+	if versionID != "" {
+		if !isValidVersionID(versionID) {
+			writeErrorResponseHeadersOnly(w, ErrInvalidVersionID)
+			return
+		}
+		getAction = policy.GetObjectVersionAction
+		getObjectInfo = func(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+			return objectAPI.GetObjectVersionInfo(ctx, bucket, object, versionID)
+		}
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, getAction, bucket, object); s3Error != ErrNone {
 		if getRequestAuthType(r) == authTypeAnonymous {
 			// As per "Permission" section in https://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectHEAD.html
 			// If the object you request does not exist, the error Amazon S3 returns depends on whether you also have the s3:ListBucket permission.
 			// * If you have the s3:ListBucket permission on the bucket, Amazon S3 will return an HTTP status code 404 ("no such key") error.
 			// * if you don’t have the s3:ListBucket permission, Amazon S3 will return an HTTP status code 403 ("access denied") error.`
-			if globalPolicySys.IsAllowed(policy.Args{
+			if isAccountAllowed(ctx, policy.Args{
 				Action:          policy.ListBucketAction,
 				BucketName:      bucket,
 				ConditionValues: getConditionValues(r, ""),
@@ -257,6 +402,9 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 
 	objInfo, err := getObjectInfo(ctx, bucket, object)
 	if err != nil {
+		if _, ok := err.(ObjectVersionDeleteMarker); ok {
+			w.Header().Set(amzDeleteMarker, "true")
+		}
 		writeErrorResponseHeadersOnly(w, toAPIErrorCode(err))
 		return
 	}
@@ -266,12 +414,18 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 			writeErrorResponse(w, apiErr, r.URL)
 			return
 		} else if encrypted {
+			kmsEncrypted := isKMSEncrypted(objInfo.UserDefined)
+			if kmsEncrypted {
+				setSSEHeaders(w, objInfo.UserDefined)
+			}
 			if _, err = DecryptRequest(w, r, objInfo.UserDefined); err != nil {
 				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 				return
 			}
-			w.Header().Set(SSECustomerAlgorithm, r.Header.Get(SSECustomerAlgorithm))
-			w.Header().Set(SSECustomerKeyMD5, r.Header.Get(SSECustomerKeyMD5))
+			if !kmsEncrypted {
+				w.Header().Set(SSECustomerAlgorithm, r.Header.Get(SSECustomerAlgorithm))
+				w.Header().Set(SSECustomerKeyMD5, r.Header.Get(SSECustomerKeyMD5))
+			}
 		}
 	}
 
@@ -307,6 +461,117 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 	})
 }
 
+// RestoreObjectHandler - POST Object?restore
+// ----------
+// This implementation of the POST operation restores a temporary copy of
+// an archived object for the number of days given in the request.
+func (api objectAPIHandlers) RestoreObjectHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "RestoreObject")
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.RestoreObjectAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	// RestoreObject always needs Content-Length.
+	if r.ContentLength <= 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+
+	restoreRequest, s3Error := parseRestoreRequest(io.LimitReader(r.Body, maxRestoreRequestSize))
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objInfo, err := objectAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if objInfo.StorageClass != glacierStorageClass {
+		writeErrorResponse(w, ErrObjectNotArchived, r.URL)
+		return
+	}
+
+	status, ok := getRestoreStatus(objInfo)
+	if ok && status.Ongoing {
+		writeErrorResponse(w, ErrRestoreAlreadyInProgress, r.URL)
+		return
+	}
+
+	// Restoring a restored object only updates its expiry.
+	restored := ok && status.IsRestored(UTCNow())
+
+	// Get host and port from Request.RemoteAddr.
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host, port = "", ""
+	}
+
+	doneCh, err := objectAPI.RestoreObject(ctx, bucket, object, restoreRequest.Days)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	if restored {
+		writeSuccessResponseHeadersOnly(w)
+		return
+	}
+
+	reqParams := extractReqParams(r)
+	userAgent := r.UserAgent()
+
+	// Notify object restore is initiated.
+	sendEvent(eventArgs{
+		EventName:  event.ObjectRestorePost,
+		BucketName: bucket,
+		Object:     objInfo,
+		ReqParams:  reqParams,
+		UserAgent:  userAgent,
+		Host:       host,
+		Port:       port,
+	})
+
+	writeResponse(w, http.StatusAccepted, nil, mimeNone)
+
+	// Archived data is restored in background.
+	go func() {
+		if err := <-doneCh; err != nil {
+			return
+		}
+
+		if info, err := objectAPI.GetObjectInfo(context.Background(), bucket, object); err == nil {
+			objInfo = info
+		}
+
+		// Notify object restore is completed.
+		sendEvent(eventArgs{
+			EventName:  event.ObjectRestoreCompleted,
+			BucketName: bucket,
+			Object:     objInfo,
+			ReqParams:  reqParams,
+			UserAgent:  userAgent,
+			Host:       host,
+			Port:       port,
+		})
+	}()
+}
+
 // Extract metadata relevant for an CopyObject operation based on conditional
 // header values specified in X-Amz-Metadata-Directive.
 func getCpObjMetadataFromHeader(ctx context.Context, header http.Header, userMeta map[string]string) (map[string]string, error) {
@@ -363,6 +628,13 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		cpSrcPath = r.Header.Get("X-Amz-Copy-Source")
 	}
 
+	// Copy source may refer to a specific version of the object.
+	cpSrcPath, srcVersionID := splitCopySourceVersionID(cpSrcPath)
+	if srcVersionID != "" && !isValidVersionID(srcVersionID) {
+		writeErrorResponse(w, ErrInvalidVersionID, r.URL)
+		return
+	}
+
 	srcBucket, srcObject := path2BucketAndObject(cpSrcPath)
 	// If source object is empty or bucket is empty, reply back invalid copy source.
 	if srcObject == "" || srcBucket == "" {
@@ -376,13 +648,30 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	cpSrcDstSame := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
-	srcInfo, err := objectAPI.GetObjectInfo(ctx, srcBucket, srcObject)
+	// Check if tagging directive is valid.
+	if !isTaggingDirectiveValid(r.Header) {
+		writeErrorResponse(w, ErrInvalidTaggingDirective, r.URL)
+		return
+	}
+
+	cpSrcDstSame := srcVersionID == "" && isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
+	var srcInfo ObjectInfo
+	if srcVersionID != "" {
+		srcInfo, err = objectAPI.GetObjectVersionInfo(ctx, srcBucket, srcObject, srcVersionID)
+	} else {
+		srcInfo, err = objectAPI.GetObjectInfo(ctx, srcBucket, srcObject)
+	}
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
+	// Archived objects cannot be copied until restored.
+	if isObjectArchived(srcInfo) {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
 	// Deny if WORM is enabled
 	if globalWORMEnabled {
 		if _, err = objectAPI.GetObjectInfo(ctx, dstBucket, dstObject); err == nil {
@@ -391,8 +680,18 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	// Deny if the object exceeds the hard quota of the bucket.
+	replacedSize, releaseQuota, err := globalBucketQuotaSys.CheckQuota(ctx, objectAPI, dstBucket, dstObject, srcInfo.Size)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer releaseQuota()
+
+	var srcEncrypted bool
 	if objectAPI.IsEncryptionSupported() {
-		if apiErr, _ := DecryptCopyObjectInfo(&srcInfo, r.Header); apiErr != ErrNone {
+		var apiErr APIErrorCode
+		if apiErr, srcEncrypted = DecryptCopyObjectInfo(&srcInfo, r.Header); apiErr != ErrNone {
 			writeErrorResponse(w, apiErr, r.URL)
 			return
 		}
@@ -434,11 +733,13 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	var encMetadata = make(map[string]string)
 	if objectAPI.IsEncryptionSupported() {
+		setBucketEncryptionHeaders(dstBucket, r.Header)
 		var oldKey, newKey []byte
 		sseCopyC := hasSSECopyCustomerHeader(r.Header)
 		sseC := hasSSECustomerHeader(r.Header)
-		if sseC {
-			newKey, err = ParseSSECustomerRequest(r)
+		encrypt := sseC || hasSSEHeader(r.Header)
+		if encrypt {
+			newKey, err = newSSEKey(r.Header, dstBucket, dstObject, encMetadata)
 			if err != nil {
 				pipeWriter.CloseWithError(err)
 				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -468,7 +769,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 			// Since we are rotating the keys, make sure to update the metadata.
 			srcInfo.metadataOnly = true
 		} else {
-			if sseCopyC {
+			if srcEncrypted {
 				// Source is encrypted make sure to save the encrypted size.
 				writer = ioutil.LimitedWriter(writer, 0, srcInfo.Size)
 				writer, srcInfo.Size, err = DecryptAllBlocksCopyRequest(writer, r, srcInfo)
@@ -481,11 +782,11 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 				// we are creating a new object at this point, even
 				// if source and destination are same objects.
 				srcInfo.metadataOnly = false
-				if sseC {
+				if encrypt {
 					size = srcInfo.Size
 				}
 			}
-			if sseC {
+			if encrypt {
 				reader, err = newEncryptReader(pipeReader, newKey, encMetadata)
 				if err != nil {
 					pipeWriter.CloseWithError(err)
@@ -496,7 +797,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 				// we are creating a new object at this point, even
 				// if source and destination are same objects.
 				srcInfo.metadataOnly = false
-				if !sseCopyC {
+				if !srcEncrypted {
 					size = srcInfo.EncryptedSize()
 				}
 			}
@@ -510,6 +811,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 	}
 	srcInfo.Writer = writer
 
+	srcTags, hasSrcTags := srcInfo.UserDefined[amzObjectTagging]
 	srcInfo.UserDefined, err = getCpObjMetadataFromHeader(ctx, r.Header, srcInfo.UserDefined)
 	if err != nil {
 		pipeWriter.CloseWithError(err)
@@ -517,6 +819,19 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Tags of the source object are copied unless x-amz-tagging-directive
+	// says REPLACE, independent of x-amz-metadata-directive.
+	delete(srcInfo.UserDefined, amzObjectTagging)
+	if isTaggingReplace(r.Header) {
+		if s3Error := extractTagsFromHeader(r.Header, srcInfo.UserDefined); s3Error != ErrNone {
+			pipeWriter.CloseWithError(fmt.Errorf("invalid tags"))
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	} else if hasSrcTags {
+		srcInfo.UserDefined[amzObjectTagging] = srcTags
+	}
+
 	// We need to preserve the encryption headers set in EncryptRequest,
 	// so we do not want to override them, copy them instead.
 	for k, v := range encMetadata {
@@ -536,6 +851,8 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	setReplicationStatus(ctx, r, dstBucket, dstObject, srcInfo.UserDefined)
+
 	// Copy source object to destination, if source and destination
 	// object is same then only metadata is updated.
 	objInfo, err := objectAPI.CopyObject(ctx, srcBucket, srcObject, dstBucket, dstObject, srcInfo)
@@ -547,9 +864,25 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	pipeReader.Close()
 
+	// Copied object does not inherit the ACL of the object it replaces.
+	if err = resetObjectACL(ctx, objectAPI, dstBucket, dstObject); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	response := generateCopyObjectResponse(objInfo.ETag, objInfo.ModTime)
 	encodedSuccessResponse := encodeResponse(response)
 
+	if srcVersionID != "" {
+		w.Header().Set("x-amz-copy-source-version-id", srcVersionID)
+	}
+	if objInfo.VersionID != "" {
+		w.Header().Set(amzVersionID, objInfo.VersionID)
+	}
+	if objectAPI.IsEncryptionSupported() {
+		setSSEHeaders(w, encMetadata)
+	}
+
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 
@@ -559,6 +892,9 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		host, port = "", ""
 	}
 
+	globalBucketQuotaSys.ObjectCreated(dstBucket, objInfo.Size, replacedSize)
+	globalBucketReplicationSys.Replicate(dstBucket, objInfo)
+
 	// Notify object created event.
 	sendEvent(eventArgs{
 		EventName:  event.ObjectCreatedCopy,
@@ -595,12 +931,18 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 
 	// Validate storage class metadata if present
 	if _, ok := r.Header[amzStorageClassCanonical]; ok {
-		if !isValidStorageClassMeta(r.Header.Get(amzStorageClassCanonical)) {
+		if !isSupportedStorageClass(r.Header.Get(amzStorageClassCanonical)) {
 			writeErrorResponse(w, ErrInvalidStorageClass, r.URL)
 			return
 		}
 	}
 
+	// Validate website redirect location if present
+	if !isValidWebsiteRedirectLocation(r.Header.Get(amzWebsiteRedirectLocation)) {
+		writeErrorResponse(w, ErrInvalidRedirectLocation, r.URL)
+		return
+	}
+
 	// Get Content-Md5 sent by client and verify if valid
 	md5Bytes, err := checkValidMD5(r.Header)
 	if err != nil {
@@ -641,6 +983,20 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
+
+	// Save tags of the object from x-amz-tagging header, if any.
+	if s3Error := extractTagsFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Parse ACL of the object from x-amz-acl or x-amz-grant-* headers, if any.
+	aclPolicy, err := parseACLHeaders(r)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	if rAuthType == authTypeStreamingSigned {
 		if contentEncoding, ok := metadata["content-encoding"]; ok {
 			contentEncoding = trimAwsChunkedContentEncoding(contentEncoding)
@@ -675,7 +1031,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		writeErrorResponse(w, ErrAccessDenied, r.URL)
 		return
 	case authTypeAnonymous:
-		if !globalPolicySys.IsAllowed(policy.Args{
+		if !isAccountAllowed(ctx, policy.Args{
 			Action:          policy.PutObjectAction,
 			BucketName:      bucket,
 			ConditionValues: getConditionValues(r, ""),
@@ -709,6 +1065,14 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Signature is verified, check whether the account is allowed to write.
+	if rAuthType != authTypeAnonymous {
+		if s3Err = isPutAllowed(ctx, rAuthType, bucket, object, r); s3Err != ErrNone {
+			writeErrorResponse(w, s3Err, r.URL)
+			return
+		}
+	}
+
 	hashReader, err := hash.NewReader(reader, size, md5hex, sha256hex)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -723,9 +1087,18 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Deny if the object exceeds the hard quota of the bucket.
+	replacedSize, releaseQuota, err := globalBucketQuotaSys.CheckQuota(ctx, objectAPI, bucket, object, size)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer releaseQuota()
+
 	if objectAPI.IsEncryptionSupported() {
-		if hasSSECustomerHeader(r.Header) && !hasSuffix(object, slashSeparator) { // handle SSE-C requests
-			reader, err = EncryptRequest(hashReader, r, metadata)
+		setBucketEncryptionHeaders(bucket, r.Header)
+		if (hasSSECustomerHeader(r.Header) || hasSSEHeader(r.Header)) && !hasSuffix(object, slashSeparator) { // handle SSE-C, SSE-S3 and SSE-KMS requests
+			reader, err = EncryptRequest(hashReader, r, bucket, object, metadata)
 			if err != nil {
 				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 				return
@@ -739,9 +1112,11 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	if api.CacheAPI() != nil && !hasSSECustomerHeader(r.Header) {
+	if api.CacheAPI() != nil && !hasSSECustomerHeader(r.Header) && !hasSSEHeader(r.Header) {
 		putObject = api.CacheAPI().PutObject
 	}
+	setReplicationStatus(ctx, r, bucket, object, metadata)
+
 	// Create the object..
 	objInfo, err := putObject(ctx, bucket, object, hashReader, metadata)
 	if err != nil {
@@ -749,12 +1124,27 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if aclPolicy != nil {
+		err = setObjectACL(ctx, objectAPI, bucket, object, aclPolicy)
+	} else {
+		// New object does not inherit the ACL of the object it replaces.
+		err = resetObjectACL(ctx, objectAPI, bucket, object)
+	}
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	w.Header().Set("ETag", "\""+objInfo.ETag+"\"")
+	if objInfo.VersionID != "" {
+		w.Header().Set(amzVersionID, objInfo.VersionID)
+	}
 	if objectAPI.IsEncryptionSupported() {
 		if hasSSECustomerHeader(r.Header) {
 			w.Header().Set(SSECustomerAlgorithm, r.Header.Get(SSECustomerAlgorithm))
 			w.Header().Set(SSECustomerKeyMD5, r.Header.Get(SSECustomerKeyMD5))
 		}
+		setSSEHeaders(w, metadata)
 	}
 
 	writeSuccessResponseHeadersOnly(w)
@@ -765,6 +1155,9 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		host, port = "", ""
 	}
 
+	globalBucketQuotaSys.ObjectCreated(bucket, objInfo.Size, replacedSize)
+	globalBucketReplicationSys.Replicate(bucket, objInfo)
+
 	// Notify object created event.
 	sendEvent(eventArgs{
 		EventName:  event.ObjectCreatedPut,
@@ -815,11 +1208,18 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		}
 	}
 
+	// Validate website redirect location if present
+	if !isValidWebsiteRedirectLocation(r.Header.Get(amzWebsiteRedirectLocation)) {
+		writeErrorResponse(w, ErrInvalidRedirectLocation, r.URL)
+		return
+	}
+
 	var encMetadata = map[string]string{}
 
 	if objectAPI.IsEncryptionSupported() {
-		if hasSSECustomerHeader(r.Header) {
-			key, err := ParseSSECustomerRequest(r)
+		setBucketEncryptionHeaders(bucket, r.Header)
+		if hasSSECustomerHeader(r.Header) || hasSSEHeader(r.Header) {
+			key, err := newSSEKey(r.Header, bucket, object, encMetadata)
 			if err != nil {
 				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 				return
@@ -843,12 +1243,20 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
+	// Save tags of the object from x-amz-tagging header, if any.
+	if s3Error := extractTagsFromHeader(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// We need to preserve the encryption headers set in EncryptRequest,
 	// so we do not want to override them, copy them instead.
 	for k, v := range encMetadata {
 		metadata[k] = v
 	}
 
+	setReplicationStatus(ctx, r, bucket, object, metadata)
+
 	newMultipartUpload := objectAPI.NewMultipartUpload
 	if api.CacheAPI() != nil {
 		newMultipartUpload = api.CacheAPI().NewMultipartUpload
@@ -862,6 +1270,8 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 	response := generateInitiateMultipartUploadResponse(bucket, object, uploadID)
 	encodedSuccessResponse := encodeResponse(response)
 
+	setSSEHeaders(w, encMetadata)
+
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 }
@@ -920,6 +1330,12 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// Archived objects cannot be copied until restored.
+	if isObjectArchived(srcInfo) {
+		writeErrorResponse(w, ErrInvalidObjectState, r.URL)
+		return
+	}
+
 	// Deny if WORM is enabled
 	if globalWORMEnabled {
 		if _, err = objectAPI.GetObjectInfo(ctx, dstBucket, dstObject); err == nil {
@@ -928,8 +1344,10 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		}
 	}
 
+	var srcEncrypted bool
 	if objectAPI.IsEncryptionSupported() {
-		if apiErr, _ := DecryptCopyObjectInfo(&srcInfo, r.Header); apiErr != ErrNone {
+		var apiErr APIErrorCode
+		if apiErr, srcEncrypted = DecryptCopyObjectInfo(&srcInfo, r.Header); apiErr != ErrNone {
 			writeErrorResponse(w, apiErr, r.URL)
 			return
 		}
@@ -968,6 +1386,14 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// Deny if the part exceeds the hard quota of the bucket.
+	_, releaseQuota, err := globalBucketQuotaSys.CheckQuota(ctx, objectAPI, dstBucket, dstObject, length)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer releaseQuota()
+
 	// Initialize pipe.
 	pipeReader, pipeWriter := io.Pipe()
 
@@ -987,8 +1413,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 			return
 		}
-		sseCopyC := hasSSECopyCustomerHeader(r.Header)
-		if sseCopyC {
+		if srcEncrypted {
 			// Response writer should be limited early on for decryption upto required length,
 			// additionally also skipping mod(offset)64KiB boundaries.
 			writer = ioutil.LimitedWriter(writer, startOffset%(64*1024), length)
@@ -1000,12 +1425,12 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 			}
 		}
 		if li.IsEncrypted() {
-			if !hasSSECustomerHeader(r.Header) {
+			if !isKMSEncrypted(li.UserDefined) && !hasSSECustomerHeader(r.Header) {
 				writeErrorResponse(w, ErrSSEMultipartEncrypted, r.URL)
 				return
 			}
 			var key []byte
-			key, err = ParseSSECustomerRequest(r)
+			key, err = sealingKey(r, li.UserDefined, false)
 			if err != nil {
 				pipeWriter.CloseWithError(err)
 				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
@@ -1029,7 +1454,7 @@ func (api objectAPIHandlers) CopyObjectPartHandler(w http.ResponseWriter, r *htt
 			}
 
 			size := length
-			if !sseCopyC {
+			if !srcEncrypted {
 				info := ObjectInfo{Size: length}
 				size = info.EncryptedSize()
 			}
@@ -1146,7 +1571,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		writeErrorResponse(w, ErrAccessDenied, r.URL)
 		return
 	case authTypeAnonymous:
-		if !globalPolicySys.IsAllowed(policy.Args{
+		if !isAccountAllowed(ctx, policy.Args{
 			Action:          policy.PutObjectAction,
 			BucketName:      bucket,
 			ConditionValues: getConditionValues(r, ""),
@@ -1181,6 +1606,14 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		}
 	}
 
+	// Signature is verified, check whether the account is allowed to write.
+	if rAuthType != authTypeAnonymous {
+		if s3Error := isPutAllowed(ctx, rAuthType, bucket, object, r); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
+	}
+
 	hashReader, err := hash.NewReader(reader, size, md5hex, sha256hex)
 	if err != nil {
 		// Verify if the underlying error is signature mismatch.
@@ -1196,6 +1629,14 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		}
 	}
 
+	// Deny if the part exceeds the hard quota of the bucket.
+	_, releaseQuota, err := globalBucketQuotaSys.CheckQuota(ctx, objectAPI, bucket, object, size)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	defer releaseQuota()
+
 	if objectAPI.IsEncryptionSupported() {
 		var li ListPartsInfo
 		li, err = objectAPI.ListObjectParts(ctx, bucket, object, uploadID, 0, 1)
@@ -1204,12 +1645,12 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 			return
 		}
 		if li.IsEncrypted() {
-			if !hasSSECustomerHeader(r.Header) {
+			if !isKMSEncrypted(li.UserDefined) && !hasSSECustomerHeader(r.Header) {
 				writeErrorResponse(w, ErrSSEMultipartEncrypted, r.URL)
 				return
 			}
 			var key []byte
-			key, err = ParseSSECustomerRequest(r)
+			key, err = sealingKey(r, li.UserDefined, false)
 			if err != nil {
 				writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 				return
@@ -1414,6 +1855,12 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		return
 	}
 
+	// New object does not inherit the ACL of the object it replaces.
+	if err = resetObjectACL(ctx, objectAPI, bucket, object); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	// Get object location.
 	location := getObjectLocation(r, globalDomainName, bucket, object)
 	// Generate complete multipart response.
@@ -1426,6 +1873,9 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 
 	// Set etag.
 	w.Header().Set("ETag", "\""+objInfo.ETag+"\"")
+	if objInfo.VersionID != "" {
+		w.Header().Set(amzVersionID, objInfo.VersionID)
+	}
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
@@ -1436,6 +1886,9 @@ func (api objectAPIHandlers) CompleteMultipartUploadHandler(w http.ResponseWrite
 		host, port = "", ""
 	}
 
+	globalBucketQuotaSys.ObjectCreated(bucket, objInfo.Size, -1)
+	globalBucketReplicationSys.Replicate(bucket, objInfo)
+
 	// Notify object created event.
 	sendEvent(eventArgs{
 		EventName:  event.ObjectCreatedCompleteMultipartUpload,
@@ -1464,7 +1917,17 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if s3Error := checkRequestAuthType(ctx, r, policy.DeleteObjectAction, bucket, object); s3Error != ErrNone {
+	var deleteAction policy.Action = policy.DeleteObjectAction
+	versionID := r.URL.Query().Get("versionId")
+	if versionID != "" {
+		if !isValidVersionID(versionID) {
+			writeErrorResponse(w, ErrInvalidVersionID, r.URL)
+			return
+		}
+		deleteAction = policy.DeleteObjectVersionAction
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, deleteAction, bucket, object); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -1477,6 +1940,14 @@ func (api objectAPIHandlers) DeleteObjectHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Versioned deletes either remove a specific version or add a
+	// delete marker, both of which are reported back to the client.
+	if versionID != "" || getBucketVersioningStatus(bucket) != "" {
+		deleteObjectVersion(ctx, objectAPI, bucket, object, versionID, w, r)
+		writeSuccessNoContent(w)
+		return
+	}
+
 	// http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html
 	// Ignore delete object errors while replying to client, since we are
 	// suppposed to reply only 204. Additionally log the error for
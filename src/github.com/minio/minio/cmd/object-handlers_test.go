@@ -19,19 +19,23 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/xml"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/kms"
 )
 
 // Type to capture different modifications to API request to simulate failure cases.
@@ -465,6 +469,281 @@ func testAPIGetObjectHandler(obj ObjectLayer, instanceType, bucketName string, a
 	ExecObjectLayerAPINilTest(t, nilBucket, nilObject, instanceType, apiRouter, nilReq)
 }
 
+// Wrapper for calling SelectObjectContent API handler tests for both XL multiple disks and FS single drive setup.
+func TestAPISelectObjectContentHandler(t *testing.T) {
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPISelectObjectContentHandler, []string{"SelectObjectContent"})
+}
+
+func testAPISelectObjectContentHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	objectName := "test-object.csv"
+	data := []byte("name,age\nalice,30\nbob,25\n")
+	if _, err := obj.PutObject(context.Background(), bucketName, objectName, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	selectRequest := func(expression string) string {
+		return `<SelectObjectContentRequest>
+  <Expression>` + expression + `</Expression>
+  <ExpressionType>SQL</ExpressionType>
+  <InputSerialization><CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV></InputSerialization>
+  <OutputSerialization><CSV/></OutputSerialization>
+</SelectObjectContentRequest>`
+	}
+
+	testCases := []struct {
+		objectName         string
+		body               string
+		accessKey          string
+		expectedRespStatus int
+		expectedRecords    string
+	}{
+		{objectName, selectRequest("SELECT name FROM S3Object WHERE age = 25"), credentials.AccessKey, http.StatusOK, "bob\n"},
+		{objectName, selectRequest("SELECT COUNT(*) FROM S3Object"), credentials.AccessKey, http.StatusOK, "2\n"},
+		{objectName, selectRequest("SELECT name FROM"), credentials.AccessKey, http.StatusBadRequest, ""},
+		{objectName, "<SelectObjectContentRequest>", credentials.AccessKey, http.StatusBadRequest, ""},
+		{"nonexistent", selectRequest("SELECT * FROM S3Object"), credentials.AccessKey, http.StatusNotFound, ""},
+		{objectName, selectRequest("SELECT * FROM S3Object"), "Invalid-AccessID", http.StatusForbidden, ""},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getSelectObjectContentURL("", bucketName, testCase.objectName),
+			int64(len(testCase.body)), bytes.NewReader([]byte(testCase.body)), testCase.accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for SelectObjectContent: <ERROR> %v", i+1, instanceType, err)
+		}
+
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		events, err := decodeSelectEvents(rec.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Test %d: %s: Unable to decode event stream: <ERROR> %v", i+1, instanceType, err)
+		}
+
+		// Records are followed by Stats and End messages.
+		var records string
+		var eventTypes []string
+		var stats struct {
+			BytesScanned   int64
+			BytesProcessed int64
+			BytesReturned  int64
+		}
+		for _, event := range events {
+			if event.headers[":message-type"] != "event" {
+				t.Fatalf("Test %d: %s: Unexpected message %v", i+1, instanceType, event.headers)
+			}
+			switch eventType := event.headers[":event-type"]; eventType {
+			case "Cont", "Progress":
+				continue
+			case "Records":
+				records += string(event.payload)
+			case "Stats":
+				if err = xml.Unmarshal(event.payload, &stats); err != nil {
+					t.Fatalf("Test %d: %s: Unable to parse Stats message: <ERROR> %v", i+1, instanceType, err)
+				}
+			}
+			if n := len(eventTypes); n == 0 || eventTypes[n-1] != event.headers[":event-type"] {
+				eventTypes = append(eventTypes, event.headers[":event-type"])
+			}
+		}
+
+		if strings.Join(eventTypes, ",") != "Records,Stats,End" {
+			t.Fatalf("Test %d: %s: Expected Records, Stats and End messages, got %v", i+1, instanceType, eventTypes)
+		}
+		if records != testCase.expectedRecords {
+			t.Fatalf("Test %d: %s: Expected records %q, got %q", i+1, instanceType, testCase.expectedRecords, records)
+		}
+		if stats.BytesScanned != int64(len(data)) || stats.BytesProcessed != int64(len(data)) ||
+			stats.BytesReturned != int64(len(testCase.expectedRecords)) {
+			t.Fatalf("Test %d: %s: Unexpected stats %+v", i+1, instanceType, stats)
+		}
+	}
+}
+
+// selectEvent - decoded event stream message of SelectObjectContent response.
+type selectEvent struct {
+	headers map[string]string
+	payload []byte
+}
+
+// decodeSelectEvents - decodes event stream messages of SelectObjectContent
+// response and verifies their lengths and checksums.
+func decodeSelectEvents(data []byte) ([]selectEvent, error) {
+	var events []selectEvent
+	for len(data) > 0 {
+		if len(data) < 16 {
+			return nil, fmt.Errorf("short message of %d bytes", len(data))
+		}
+		totalLength := binary.BigEndian.Uint32(data[0:4])
+		headersLength := binary.BigEndian.Uint32(data[4:8])
+		if int(totalLength) > len(data) || headersLength+16 > totalLength {
+			return nil, fmt.Errorf("invalid message length %d", totalLength)
+		}
+		if binary.BigEndian.Uint32(data[8:12]) != crc32.ChecksumIEEE(data[0:8]) {
+			return nil, fmt.Errorf("prelude CRC mismatch")
+		}
+		if binary.BigEndian.Uint32(data[totalLength-4:totalLength]) != crc32.ChecksumIEEE(data[0:totalLength-4]) {
+			return nil, fmt.Errorf("message CRC mismatch")
+		}
+
+		event := selectEvent{headers: map[string]string{}}
+		headers := bytes.NewReader(data[12 : 12+headersLength])
+		for headers.Len() > 0 {
+			nameLength, _ := headers.ReadByte()
+			name := make([]byte, nameLength)
+			if _, err := io.ReadFull(headers, name); err != nil {
+				return nil, err
+			}
+			// Only string header values (type 7) are used.
+			if valueType, _ := headers.ReadByte(); valueType != 7 {
+				return nil, fmt.Errorf("unexpected header value type %d", valueType)
+			}
+			var valueLength uint16
+			if err := binary.Read(headers, binary.BigEndian, &valueLength); err != nil {
+				return nil, err
+			}
+			value := make([]byte, valueLength)
+			if _, err := io.ReadFull(headers, value); err != nil {
+				return nil, err
+			}
+			event.headers[string(name)] = string(value)
+		}
+		event.payload = data[12+headersLength : totalLength-4]
+
+		events = append(events, event)
+		data = data[totalLength:]
+	}
+
+	return events, nil
+}
+
+// Wrapper for calling SSE-S3 API handler tests for both XL multiple disks and FS single drive setup.
+func TestAPISSES3Handlers(t *testing.T) {
+	defer DetectTestLeak(t)()
+	defer func(KMS kms.KMS, keyID string) { globalKMS, globalKMSKeyID = KMS, keyID }(globalKMS, globalKMSKeyID)
+	globalKMS, globalKMSKeyID = kms.NewLocal(kms.MasterKey{ID: "my-key"}), "my-key"
+	ExecObjectLayerAPITest(t, testAPISSES3Handlers, []string{"CopyObject", "PutObjectPart", "NewMultipart",
+		"CompleteMultipart", "PutObject", "GetObject", "HeadObject"})
+}
+
+func testAPISSES3Handlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 100*1024)
+
+	serve := func(method, url string, body []byte, sse string) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, url, err)
+		}
+		if sse != "" {
+			req.Header.Set(SSEHeader, sse)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Only AES256 is a valid SSE-S3 algorithm.
+	if rec := serve("PUT", getPutObjectURL("", bucketName, "object"), data, "AES"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	rec := serve("PUT", getPutObjectURL("", bucketName, "object"), data, SSECustomerAlgorithmAES256)
+	if rec.Code != http.StatusOK || rec.Header().Get(SSEHeader) != SSECustomerAlgorithmAES256 {
+		t.Fatalf("%s: PutObject failed with status `%d` and SSE header %q", instanceType, rec.Code, rec.Header().Get(SSEHeader))
+	}
+
+	// Object data is encrypted on the backend.
+	objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isSSES3Encrypted(objInfo.UserDefined) || objInfo.Size == int64(len(data)) {
+		t.Fatalf("%s: Expected object to be stored SSE-S3 encrypted", instanceType)
+	}
+
+	rec = serve("HEAD", getHeadObjectURL("", bucketName, "object"), nil, "")
+	if rec.Code != http.StatusOK || rec.Header().Get(SSEHeader) != SSECustomerAlgorithmAES256 {
+		t.Fatalf("%s: HeadObject failed with status `%d` and SSE header %q", instanceType, rec.Code, rec.Header().Get(SSEHeader))
+	}
+	if rec.Header().Get("Content-Length") != strconv.Itoa(len(data)) {
+		t.Fatalf("%s: Expected content length %d, got %s", instanceType, len(data), rec.Header().Get("Content-Length"))
+	}
+
+	rec = serve("GET", getGetObjectURL("", bucketName, "object"), nil, "")
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("%s: GetObject failed with status `%d` or returned wrong content", instanceType, rec.Code)
+	}
+	if rec.Header().Get(SSEHeader) != SSECustomerAlgorithmAES256 {
+		t.Fatalf("%s: Expected SSE header in GetObject response", instanceType)
+	}
+
+	// SSE-KMS objects echo the master key ID.
+	rec = serve("PUT", getPutObjectURL("", bucketName, "object-kms"), data, SSEAlgorithmKMS)
+	if rec.Code != http.StatusOK || rec.Header().Get(SSEHeader) != SSEAlgorithmKMS || rec.Header().Get(SSEKMSKeyID) != "my-key" {
+		t.Fatalf("%s: PutObject failed with status `%d` and SSE header %q", instanceType, rec.Code, rec.Header().Get(SSEHeader))
+	}
+	rec = serve("GET", getGetObjectURL("", bucketName, "object-kms"), nil, "")
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("%s: GetObject failed with status `%d` or returned wrong content", instanceType, rec.Code)
+	}
+	if rec.Header().Get(SSEHeader) != SSEAlgorithmKMS || rec.Header().Get(SSEKMSKeyID) != "my-key" {
+		t.Fatalf("%s: Expected SSE-KMS headers in GetObject response", instanceType)
+	}
+
+	// Copying without SSE-S3 header stores a plain object.
+	req, err := newTestSignedRequestV4("PUT", getCopyObjectURL("", bucketName, "object-copy"), 0, nil, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Amz-Copy-Source", url.QueryEscape(pathJoin(bucketName, "object")))
+	rec = httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: CopyObject failed with status `%d`", instanceType, rec.Code)
+	}
+	rec = serve("GET", getGetObjectURL("", bucketName, "object-copy"), nil, "")
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) || rec.Header().Get(SSEHeader) != "" {
+		t.Fatalf("%s: Expected copied object to be stored unencrypted", instanceType)
+	}
+
+	// Multipart uploads are encrypted with the SSE-S3 key of the upload.
+	rec = serve("POST", getNewMultipartURL("", bucketName, "object-multipart"), nil, SSECustomerAlgorithmAES256)
+	if rec.Code != http.StatusOK || rec.Header().Get(SSEHeader) != SSECustomerAlgorithmAES256 {
+		t.Fatalf("%s: NewMultipart failed with status `%d`", instanceType, rec.Code)
+	}
+	var initResponse InitiateMultipartUploadResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &initResponse); err != nil {
+		t.Fatal(err)
+	}
+	rec = serve("PUT", getPutObjectPartURL("", bucketName, "object-multipart", initResponse.UploadID, "1"), data, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutObjectPart failed with status `%d`", instanceType, rec.Code)
+	}
+	completeBytes, err := xml.Marshal(&CompleteMultipartUpload{
+		Parts: []CompletePart{{PartNumber: 1, ETag: rec.Header().Get("ETag")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = serve("POST", getCompleteMultipartUploadURL("", bucketName, "object-multipart", initResponse.UploadID), completeBytes, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: CompleteMultipart failed with status `%d`", instanceType, rec.Code)
+	}
+	rec = serve("GET", getGetObjectURL("", bucketName, "object-multipart"), nil, "")
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("%s: GetObject of multipart object failed with status `%d` or returned wrong content", instanceType, rec.Code)
+	}
+}
+
 // Wrapper for calling PutObject API handler tests using streaming signature v4 for both XL multiple disks and FS single drive setup.
 func TestAPIPutObjectStreamSigV4Handler(t *testing.T) {
 	defer DetectTestLeak(t)()
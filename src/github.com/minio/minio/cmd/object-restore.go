@@ -0,0 +1,144 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+const (
+	// Restore status of an archived object.
+	amzRestore = "x-amz-restore"
+
+	// Maximum size of RestoreRequest XML.
+	maxRestoreRequestSize = 1024 * 1024
+)
+
+// Retrieval tiers of a restore request.
+const (
+	restoreTierStandard  = "Standard"
+	restoreTierBulk      = "Bulk"
+	restoreTierExpedited = "Expedited"
+)
+
+var errInvalidRestoreStatus = errors.New("invalid restore status")
+
+// Matches key="value" pairs of x-amz-restore header.
+var restoreStatusRegexp = regexp.MustCompile(`([a-z-]+)="([^"]*)"`)
+
+// RestoreRequest - RestoreObject request body.
+type RestoreRequest struct {
+	XMLName              xml.Name `xml:"RestoreRequest"`
+	Days                 int      `xml:"Days"`
+	GlacierJobParameters *struct {
+		Tier string `xml:"Tier"`
+	} `xml:"GlacierJobParameters,omitempty"`
+}
+
+// parseRestoreRequest - parses and validates RestoreRequest XML.
+func parseRestoreRequest(reader io.Reader) (*RestoreRequest, APIErrorCode) {
+	var req RestoreRequest
+	if err := xml.NewDecoder(reader).Decode(&req); err != nil {
+		return nil, ErrMalformedXML
+	}
+
+	if req.Days <= 0 {
+		return nil, ErrMalformedXML
+	}
+
+	if req.GlacierJobParameters != nil {
+		switch req.GlacierJobParameters.Tier {
+		case restoreTierStandard, restoreTierBulk, restoreTierExpedited:
+		default:
+			return nil, ErrMalformedXML
+		}
+	}
+
+	return &req, ErrNone
+}
+
+// RestoreStatus - restore status of an archived object, which is sent
+// as x-amz-restore header.
+type RestoreStatus struct {
+	Ongoing bool
+	Expiry  time.Time
+}
+
+// String - returns x-amz-restore header value of the status.
+func (status RestoreStatus) String() string {
+	if status.Ongoing {
+		return `ongoing-request="true"`
+	}
+
+	return fmt.Sprintf(`ongoing-request="false", expiry-date="%s"`, status.Expiry.UTC().Format(http.TimeFormat))
+}
+
+// IsRestored - returns whether the archived object is readable at given time.
+func (status RestoreStatus) IsRestored(now time.Time) bool {
+	return !status.Ongoing && now.Before(status.Expiry)
+}
+
+// ParseRestoreStatus - parses x-amz-restore header value.
+func ParseRestoreStatus(value string) (status RestoreStatus, err error) {
+	var ongoing, expiry string
+	for _, match := range restoreStatusRegexp.FindAllStringSubmatch(value, -1) {
+		switch match[1] {
+		case "ongoing-request":
+			ongoing = match[2]
+		case "expiry-date":
+			expiry = match[2]
+		}
+	}
+
+	switch ongoing {
+	case "true":
+		status.Ongoing = true
+		return status, nil
+	case "false":
+		status.Expiry, err = time.Parse(http.TimeFormat, expiry)
+		if err != nil {
+			return status, errInvalidRestoreStatus
+		}
+		return status, nil
+	}
+
+	return status, errInvalidRestoreStatus
+}
+
+// getRestoreStatus - returns restore status of the object, ok is false if
+// restore of the object was never requested.
+func getRestoreStatus(objInfo ObjectInfo) (status RestoreStatus, ok bool) {
+	value, found := objInfo.UserDefined[amzRestore]
+	if !found {
+		return status, false
+	}
+
+	status, err := ParseRestoreStatus(value)
+	return status, err == nil
+}
+
+// isObjectRestored - returns whether the archived object is restored.
+func isObjectRestored(objInfo ObjectInfo) bool {
+	status, ok := getRestoreStatus(objInfo)
+	return ok && status.IsRestored(UTCNow())
+}
@@ -0,0 +1,254 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/event"
+)
+
+func TestParseRestoreRequest(t *testing.T) {
+	testCases := []struct {
+		data         string
+		expectedDays int
+		expectedErr  APIErrorCode
+	}{
+		{`<RestoreRequest><Days>2</Days></RestoreRequest>`, 2, ErrNone},
+		{`<RestoreRequest><Days>2</Days><GlacierJobParameters><Tier>Bulk</Tier></GlacierJobParameters></RestoreRequest>`, 2, ErrNone},
+		{`<RestoreRequest><Days>2</Days><GlacierJobParameters><Tier>Fast</Tier></GlacierJobParameters></RestoreRequest>`, 0, ErrMalformedXML},
+		{`<RestoreRequest><Days>0</Days></RestoreRequest>`, 0, ErrMalformedXML},
+		{`<RestoreRequest></RestoreRequest>`, 0, ErrMalformedXML},
+		{`<RestoreRequest>`, 0, ErrMalformedXML},
+	}
+
+	for i, testCase := range testCases {
+		req, err := parseRestoreRequest(strings.NewReader(testCase.data))
+		if err != testCase.expectedErr {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedErr, err)
+		}
+
+		if err == ErrNone && req.Days != testCase.expectedDays {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedDays, req.Days)
+		}
+	}
+}
+
+func TestParseRestoreStatus(t *testing.T) {
+	expiry := time.Date(2012, time.December, 23, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value          string
+		expectedResult RestoreStatus
+		expectErr      bool
+	}{
+		{`ongoing-request="true"`, RestoreStatus{Ongoing: true}, false},
+		{`ongoing-request="false", expiry-date="Sun, 23 Dec 2012 00:00:00 GMT"`, RestoreStatus{Expiry: expiry}, false},
+		{`ongoing-request="false"`, RestoreStatus{}, true},
+		{`ongoing-request="false", expiry-date="2012-12-23"`, RestoreStatus{}, true},
+		{``, RestoreStatus{}, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := ParseRestoreStatus(testCase.value)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			if result != testCase.expectedResult {
+				t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+			}
+
+			if result.String() != testCase.value {
+				t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.value, result.String())
+			}
+		}
+	}
+}
+
+func TestIsObjectArchived(t *testing.T) {
+	restored := RestoreStatus{Expiry: UTCNow().Add(time.Hour)}.String()
+	expired := RestoreStatus{Expiry: UTCNow().Add(-time.Hour)}.String()
+	ongoing := RestoreStatus{Ongoing: true}.String()
+
+	testCases := []struct {
+		objInfo        ObjectInfo
+		expectedResult bool
+	}{
+		{ObjectInfo{}, false},
+		{ObjectInfo{StorageClass: standardStorageClass}, false},
+		{ObjectInfo{StorageClass: glacierStorageClass}, true},
+		{ObjectInfo{StorageClass: glacierStorageClass, UserDefined: map[string]string{amzRestore: ongoing}}, true},
+		{ObjectInfo{StorageClass: glacierStorageClass, UserDefined: map[string]string{amzRestore: expired}}, true},
+		{ObjectInfo{StorageClass: glacierStorageClass, UserDefined: map[string]string{amzRestore: restored}}, false},
+	}
+
+	for i, testCase := range testCases {
+		result := isObjectArchived(testCase.objInfo)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+// archivedObjectLayer - object layer whose objects are all archived, and
+// whose restores complete when the test sends their result.
+type archivedObjectLayer struct {
+	ObjectLayer
+	doneCh chan error
+}
+
+func (l *archivedObjectLayer) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	return ObjectInfo{Bucket: bucket, Name: object, StorageClass: glacierStorageClass}, nil
+}
+
+func (l *archivedObjectLayer) RestoreObject(ctx context.Context, bucket, object string, days int) (<-chan error, error) {
+	return l.doneCh, nil
+}
+
+// Wrapper for calling RestoreObject API handler tests for both XL and FS.
+func TestAPIRestoreObjectHandler(t *testing.T) {
+	ExecObjectLayerAPITest(t, testAPIRestoreObjectHandler, []string{"RestoreObject"})
+}
+
+func testAPIRestoreObjectHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	objectName := "test-object"
+	data := []byte("hello")
+	if _, err := obj.PutObject(context.Background(), bucketName, objectName, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		objectName         string
+		body               string
+		accessKey          string
+		expectedRespStatus int
+	}{
+		// Objects of backends without cold tier are never archived.
+		{objectName, `<RestoreRequest><Days>1</Days></RestoreRequest>`, credentials.AccessKey, http.StatusForbidden},
+		{"nonexistent", `<RestoreRequest><Days>1</Days></RestoreRequest>`, credentials.AccessKey, http.StatusNotFound},
+		{objectName, `<RestoreRequest><Days>-1</Days></RestoreRequest>`, credentials.AccessKey, http.StatusBadRequest},
+		{objectName, `<RestoreRequest><Days>1</Days></RestoreRequest>`, "Invalid-AccessID", http.StatusForbidden},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getRestoreObjectURL("", bucketName, testCase.objectName),
+			int64(len(testCase.body)), strings.NewReader(testCase.body), testCase.accessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for RestoreObject: <ERROR> %v", i+1, instanceType, err)
+		}
+
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`", i+1, instanceType, testCase.expectedRespStatus, rec.Code)
+		}
+	}
+
+	// Restores are completed in background, their events are sent only
+	// when the restore is initiated and completed.
+	globalObjLayerMutex.RLock()
+	objLayer := globalObjectAPI
+	globalObjLayerMutex.RUnlock()
+	defer func() {
+		globalObjLayerMutex.Lock()
+		globalObjectAPI = objLayer
+		globalObjLayerMutex.Unlock()
+	}()
+
+	archived := &archivedObjectLayer{ObjectLayer: obj}
+	archivedRouter := initTestAPIEndPoints(archived, []string{"RestoreObject"})
+
+	target := &testNotificationTarget{id: event.TargetID{ID: "1", Name: "webhook"}}
+	notificationSys := globalNotificationSys
+	defer func() { globalNotificationSys = notificationSys }()
+	globalNotificationSys = &NotificationSys{
+		targetList:                 event.NewTargetList(),
+		bucketRulesMap:             make(map[string]event.RulesMap),
+		bucketRemoteTargetRulesMap: make(map[string]map[event.TargetID]event.RulesMap),
+	}
+	if err := globalNotificationSys.targetList.Add(target); err != nil {
+		t.Fatal(err)
+	}
+	globalNotificationSys.AddRulesMap(bucketName, event.NewRulesMap([]event.Name{event.ObjectRestoreAll}, "", target.id))
+
+	// eventNames - returns names of events received by the target.
+	eventNames := func() (names []event.Name) {
+		target.Lock()
+		defer target.Unlock()
+		for _, eventData := range target.events {
+			names = append(names, eventData.EventName)
+		}
+		return names
+	}
+
+	// waitEvents - waits until the target received given number of events.
+	waitEvents := func(count int) []event.Name {
+		for i := 0; i < 100 && len(eventNames()) < count; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return eventNames()
+	}
+
+	restore := func() {
+		body := `<RestoreRequest><Days>1</Days></RestoreRequest>`
+		rec := httptest.NewRecorder()
+		req, err := newTestSignedRequestV4("POST", getRestoreObjectURL("", bucketName, objectName),
+			int64(len(body)), strings.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for RestoreObject: <ERROR> %v", instanceType, err)
+		}
+
+		archivedRouter.ServeHTTP(rec, req)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusAccepted, rec.Code)
+		}
+	}
+
+	// Failed restore is not completed.
+	archived.doneCh = make(chan error, 1)
+	restore()
+	archived.doneCh <- ObjectNotFound{Bucket: bucketName, Object: objectName}
+	time.Sleep(100 * time.Millisecond)
+	if names := eventNames(); len(names) != 1 || names[0] != event.ObjectRestorePost {
+		t.Fatalf("%s: expected events: %v, got: %v", instanceType, []event.Name{event.ObjectRestorePost}, names)
+	}
+
+	archived.doneCh = make(chan error, 1)
+	restore()
+	if names := eventNames(); len(names) != 2 {
+		t.Fatalf("%s: expected restore to complete in background, got events: %v", instanceType, names)
+	}
+	archived.doneCh <- nil
+	expectedNames := []event.Name{event.ObjectRestorePost, event.ObjectRestorePost, event.ObjectRestoreCompleted}
+	if names := waitEvents(3); !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("%s: expected events: %v, got: %v", instanceType, expectedNames, names)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"crypto/tls"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/event"
 	xnet "github.com/minio/minio/pkg/net"
@@ -58,6 +59,27 @@ func (rpcClient *PeerRPCClient) RemoveBucketPolicy(bucketName string) error {
 	return rpcClient.Call(peerServiceName+".RemoveBucketPolicy", &args, &reply)
 }
 
+// LoadBucketMetadata - calls load bucket metadata RPC.
+func (rpcClient *PeerRPCClient) LoadBucketMetadata(bucketName, configFile string) error {
+	args := LoadBucketMetadataArgs{
+		BucketName: bucketName,
+		ConfigFile: configFile,
+	}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".LoadBucketMetadata", &args, &reply)
+}
+
+// SetObjectACL - calls set object ACL RPC.
+func (rpcClient *PeerRPCClient) SetObjectACL(bucketName, objectName string, aclPolicy *acl.AccessControlPolicy) error {
+	args := SetObjectACLArgs{
+		BucketName: bucketName,
+		ObjectName: objectName,
+		ACL:        aclPolicy,
+	}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".SetObjectACL", &args, &reply)
+}
+
 // PutBucketNotification - calls put bukcet notification RPC.
 func (rpcClient *PeerRPCClient) PutBucketNotification(bucketName string, rulesMap event.RulesMap) error {
 	args := PutBucketNotificationArgs{
@@ -124,6 +146,14 @@ func (rpcClient *PeerRPCClient) SetCredentials(credentials auth.Credentials) err
 	return rpcClient.Call(peerServiceName+".SetCredentials", &args, &reply)
 }
 
+// LoadIAMItem - calls load IAM item RPC.
+func (rpcClient *PeerRPCClient) LoadIAMItem(prefix, name string) error {
+	args := LoadIAMItemArgs{Prefix: prefix, Name: name}
+	reply := VoidReply{}
+
+	return rpcClient.Call(peerServiceName+".LoadIAMItem", &args, &reply)
+}
+
 // NewPeerRPCClient - returns new peer RPC client.
 func NewPeerRPCClient(host *xnet.Host) (*PeerRPCClient, error) {
 	scheme := "http"
@@ -24,6 +24,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/minio/minio/cmd/logger"
 	xrpc "github.com/minio/minio/cmd/rpc"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/event"
 	xnet "github.com/minio/minio/pkg/net"
@@ -48,6 +49,9 @@ type DeleteBucketArgs struct {
 func (receiver *peerRPCReceiver) DeleteBucket(args *DeleteBucketArgs, reply *VoidReply) error {
 	globalNotificationSys.RemoveNotification(args.BucketName)
 	globalPolicySys.Remove(args.BucketName)
+	globalBucketMetadataSys.Remove(args.BucketName)
+	globalACLSys.Remove(args.BucketName)
+	globalBucketQuotaSys.Remove(args.BucketName)
 	return nil
 }
 
@@ -76,6 +80,38 @@ func (receiver *peerRPCReceiver) RemoveBucketPolicy(args *RemoveBucketPolicyArgs
 	return nil
 }
 
+// LoadBucketMetadataArgs - load bucket metadata RPC arguments.
+type LoadBucketMetadataArgs struct {
+	AuthArgs
+	BucketName string
+	ConfigFile string
+}
+
+// LoadBucketMetadata - handles load bucket metadata RPC call which reloads a configuration file of given bucket in globalBucketMetadataSys.
+func (receiver *peerRPCReceiver) LoadBucketMetadata(args *LoadBucketMetadataArgs, reply *VoidReply) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	return globalBucketMetadataSys.load(objAPI, args.BucketName, args.ConfigFile)
+}
+
+// SetObjectACLArgs - set object ACL RPC arguments.
+type SetObjectACLArgs struct {
+	AuthArgs
+	BucketName string
+	ObjectName string
+	// Nil ACL removes the ACL of the object.
+	ACL *acl.AccessControlPolicy
+}
+
+// SetObjectACL - handles set object ACL RPC call which adds or removes object ACL in globalACLSys.
+func (receiver *peerRPCReceiver) SetObjectACL(args *SetObjectACLArgs, reply *VoidReply) error {
+	globalACLSys.SetObjectACL(args.BucketName, args.ObjectName, args.ACL)
+	return nil
+}
+
 // PutBucketNotificationArgs - put bucket notification RPC arguments.
 type PutBucketNotificationArgs struct {
 	AuthArgs
@@ -189,6 +225,23 @@ func (receiver *peerRPCReceiver) SetCredentials(args *SetCredentialsArgs, reply
 	return nil
 }
 
+// LoadIAMItemArgs - load IAM item RPC arguments.
+type LoadIAMItemArgs struct {
+	AuthArgs
+	Prefix string
+	Name   string
+}
+
+// LoadIAMItem - handles load IAM item RPC call which reloads a single user, group, policy or temporary user into globalIAMSys.
+func (receiver *peerRPCReceiver) LoadIAMItem(args *LoadIAMItemArgs, reply *VoidReply) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	return globalIAMSys.LoadItem(objAPI, args.Prefix, args.Name)
+}
+
 // NewPeerRPCServer - returns new peer RPC server.
 func NewPeerRPCServer() (*xrpc.Server, error) {
 	rpcServer := xrpc.NewServer()
@@ -29,6 +29,8 @@ import (
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/handlers"
 	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+	"github.com/minio/minio/pkg/tagging"
 )
 
 // PolicySys - policy subsystem.
@@ -90,6 +92,29 @@ func (sys *PolicySys) IsAllowed(args policy.Args) bool {
 	return args.IsOwner
 }
 
+// HasConditionKey - returns whether the policy of given bucket uses
+// given condition key.
+func (sys *PolicySys) HasConditionKey(bucketName string, key condition.Key) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	p, found := sys.bucketPolicyMap[bucketName]
+	return found && p.HasConditionKey(key)
+}
+
+// IsDenied - checks whether the policy of the bucket explicitly denies
+// given policy args.
+func (sys *PolicySys) IsDenied(args policy.Args) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if p, found := sys.bucketPolicyMap[args.BucketName]; found {
+		return p.IsDenied(args)
+	}
+
+	return false
+}
+
 // Refresh PolicySys.
 func (sys *PolicySys) refresh(objAPI ObjectLayer) error {
 	buckets, err := objAPI.ListBuckets(context.Background())
@@ -180,6 +205,12 @@ func getConditionValues(request *http.Request, locationConstraint string) map[st
 
 	args["SourceIp"] = []string{handlers.GetSourceIP(request)}
 
+	if tags, err := tagging.ParseObjectTags(request.Header.Get(amzObjectTagging)); err == nil {
+		for key, value := range tags.ToMap() {
+			args["RequestObjectTag/"+key] = []string{value}
+		}
+	}
+
 	if locationConstraint != "" {
 		args["LocationConstraint"] = []string{locationConstraint}
 	}
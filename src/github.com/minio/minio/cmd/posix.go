@@ -34,6 +34,7 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/disk"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -330,6 +331,16 @@ func (s *posix) checkDiskFound() (err error) {
 	return err
 }
 
+// updateDiskMetrics - records the latency of a disk operation started
+// at startTime, and its error if the disk failed to serve it.
+func (s *posix) updateDiskMetrics(operation string, startTime time.Time, err *error) {
+	labels := prometheus.Labels{"disk": s.diskPath, "operation": operation}
+	diskOperationsDuration.With(labels).Observe(UTCNow().Sub(startTime).Seconds())
+	if *err != nil && *err != errFileNotFound && *err != errVolumeNotFound {
+		diskOperationErrors.With(labels).Inc()
+	}
+}
+
 // diskUsage returns du information for the posix path, in a continuous routine.
 func (s *posix) diskUsage(doneCh chan struct{}) {
 	ticker := time.NewTicker(globalUsageCheckInterval)
@@ -625,6 +636,8 @@ func (s *posix) ListDir(volume, dirPath string, count int) (entries []string, er
 // This API is meant to be used on files which have small memory footprint, do
 // not use this on large files as it would cause server to crash.
 func (s *posix) ReadAll(volume, path string) (buf []byte, err error) {
+	defer s.updateDiskMetrics(diskReadOperation, UTCNow(), &err)
+
 	defer func() {
 		if err == syscall.EIO {
 			atomic.AddInt32(&s.ioErrCount, 1)
@@ -696,6 +709,8 @@ func (s *posix) ReadAll(volume, path string) (buf []byte, err error) {
 // Additionally ReadFile also starts reading from an offset. ReadFile
 // semantics are same as io.ReadFull.
 func (s *posix) ReadFile(volume, path string, offset int64, buffer []byte, verifier *BitrotVerifier) (n int64, err error) {
+	defer s.updateDiskMetrics(diskReadOperation, UTCNow(), &err)
+
 	defer func() {
 		if err == syscall.EIO {
 			atomic.AddInt32(&s.ioErrCount, 1)
@@ -856,6 +871,8 @@ func (s *posix) PrepareFile(volume, path string, fileSize int64) (err error) {
 		return errInvalidArgument
 	}
 
+	defer s.updateDiskMetrics(diskWriteOperation, UTCNow(), &err)
+
 	defer func() {
 		if err == syscall.EIO {
 			atomic.AddInt32(&s.ioErrCount, 1)
@@ -903,6 +920,8 @@ func (s *posix) PrepareFile(volume, path string, fileSize int64) (err error) {
 // AppendFile - append a byte array at path, if file doesn't exist at
 // path this call explicitly creates it.
 func (s *posix) AppendFile(volume, path string, buf []byte) (err error) {
+	defer s.updateDiskMetrics(diskWriteOperation, UTCNow(), &err)
+
 	defer func() {
 		if err == syscall.EIO {
 			atomic.AddInt32(&s.ioErrCount, 1)
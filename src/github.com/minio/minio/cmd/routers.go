@@ -107,6 +107,10 @@ func configureServerHandler(endpoints EndpointList) (http.Handler, error) {
 	// Add server metrics router
 	registerMetricsRouter(router)
 
+	// Add static website router, before the web and API routers
+	// which match the paths of website requests.
+	registerWebsiteRouter(router)
+
 	// Register web router when its enabled.
 	if globalIsBrowserEnabled {
 		if err := registerWebRouter(router); err != nil {
@@ -114,6 +118,9 @@ func configureServerHandler(endpoints EndpointList) (http.Handler, error) {
 		}
 	}
 
+	// Add STS router, before the API router which matches all paths.
+	registerSTSRouter(router)
+
 	// Add API router.
 	registerAPIRouter(router)
 
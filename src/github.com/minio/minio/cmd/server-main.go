@@ -270,9 +270,29 @@ func serverMain(ctx *cli.Context) {
 		logger.Fatal(err, "Unable to initialize the notification system")
 	}
 
+	// Create new audit logger.
+	globalAuditLogger, err = getAuditLogger(globalServerConfig)
+	if err != nil {
+		logger.Fatal(err, "Unable to initialize the audit logger")
+	}
+
 	// Create new policy system.
 	globalPolicySys = NewPolicySys()
 
+	// Create new bucket metadata system.
+	globalBucketMetadataSys = NewBucketMetadataSys()
+
+	// Create new bucket replication, quota and logging systems.
+	globalBucketReplicationSys = NewBucketReplicationSys()
+	globalBucketQuotaSys = NewBucketQuotaSys()
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
+	// Create new IAM system.
+	globalIAMSys = NewIAMSys()
+
+	// Create new ACL system.
+	globalACLSys = NewACLSys()
+
 	// Initialize Admin Peers inter-node communication only in distributed setup.
 	initGlobalAdminPeers(globalEndpoints)
 
@@ -303,6 +323,12 @@ func serverMain(ctx *cli.Context) {
 	globalObjectAPI = newObject
 	globalObjLayerMutex.Unlock()
 
+	// Start applying bucket lifecycle rules in background.
+	initDailyLifecycle()
+
+	// Resume an interrupted master key rotation.
+	initKeyRotation()
+
 	// Prints the formatted startup message once object layer is initialized.
 	apiEndpoints := getAPIEndpoints(globalMinioAddr)
 	printStartupMessage(apiEndpoints)
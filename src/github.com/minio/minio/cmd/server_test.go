@@ -87,6 +87,7 @@ func runAllTests(suite *TestSuiteCommon, c *check) {
 	suite.TestEmptyObject(c)
 	suite.TestBucket(c)
 	suite.TestObjectGetAnonymous(c)
+	suite.TestObjectACLOverwrite(c)
 	suite.TestObjectGet(c)
 	suite.TestMultipleObjects(c)
 	suite.TestNotImplemented(c)
@@ -821,6 +822,78 @@ func (s *TestSuiteCommon) TestObjectGetAnonymous(c *check) {
 	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
 }
 
+// TestObjectACLOverwrite - Tests that writes replacing an object do not
+// inherit the ACL of the replaced object.
+func (s *TestSuiteCommon) TestObjectACLOverwrite(c *check) {
+	// generate a random bucket name.
+	bucketName := getRandomBucketName()
+	// HTTP request to create the bucket.
+	request, err := newTestSignedRequest("PUT", getMakeBucketURL(s.endPoint, bucketName),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	client := http.Client{Transport: s.transport}
+	// execute the make bucket http request.
+	response, err := client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// putObject - uploads an object, with the given canned ACL if any.
+	putObject := func(objectName, cannedACL string) {
+		buffer := bytes.NewReader([]byte("hello world"))
+		request, err := newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, objectName),
+			int64(buffer.Len()), buffer)
+		c.Assert(err, nil)
+		if cannedACL != "" {
+			request.Header.Set("x-amz-acl", cannedACL)
+		}
+		if s.signer == signerV4 {
+			err = signRequestV4(request, s.accessKey, s.secretKey)
+		} else {
+			err = signRequestV2(request, s.accessKey, s.secretKey)
+		}
+		c.Assert(err, nil)
+
+		response, err := client.Do(request)
+		c.Assert(err, nil)
+		c.Assert(response.StatusCode, http.StatusOK)
+	}
+
+	// getAnonymous - returns status code of an anonymous GET of the object.
+	getAnonymous := func(objectName string) int {
+		response, err := client.Get(getGetObjectURL(s.endPoint, bucketName, objectName))
+		c.Assert(err, nil)
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	objectName := "testObject"
+	putObject(objectName, "public-read")
+	c.Assert(getAnonymous(objectName), http.StatusOK)
+
+	// Overwriting the object without x-amz-acl resets its ACL.
+	putObject(objectName, "")
+	c.Assert(getAnonymous(objectName), http.StatusForbidden)
+
+	// Copying over the object resets its ACL.
+	putObject(objectName, "public-read")
+	c.Assert(getAnonymous(objectName), http.StatusOK)
+	putObject(objectName+"-src", "")
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, objectName), 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set("X-Amz-Copy-Source", url.QueryEscape("/"+bucketName+"/"+objectName+"-src"))
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+	c.Assert(getAnonymous(objectName), http.StatusForbidden)
+}
+
 // TestGetObject - Tests fetching of a small object after its insertion into the bucket.
 func (s *TestSuiteCommon) TestObjectGet(c *check) {
 	// generate a random bucket name.
@@ -1799,15 +1872,30 @@ func (s *TestSuiteCommon) TestPutBucketErrors(c *check) {
 	verifyError(c, response, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.",
 		http.StatusConflict)
 
-	// request for ACL.
-	// Since Minio server doesn't support ACL's the request is expected to fail with  "NotImplemented" error message.
+	// request for ACL without canned ACL header and ACL body.
+	// expected to fail with error message "MissingContentLength".
 	request, err = newTestSignedRequest("PUT", s.endPoint+"/"+bucketName+"?acl",
 		0, nil, s.accessKey, s.secretKey, s.signer)
 	c.Assert(err, nil)
 
 	response, err = client.Do(request)
 	c.Assert(err, nil)
-	verifyError(c, response, "NotImplemented", "A header you provided implies functionality that is not implemented", http.StatusNotImplemented)
+	verifyError(c, response, "MissingContentLength", "You must provide the Content-Length HTTP header.", http.StatusLengthRequired)
+
+	// request for canned ACL, expected to succeed.
+	request, err = newTestRequest("PUT", s.endPoint+"/"+bucketName+"?acl", 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set("x-amz-acl", "private")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
 }
 
 func (s *TestSuiteCommon) TestGetObjectLarge10MiB(c *check) {
@@ -18,10 +18,12 @@ package cmd
 
 import (
 	"crypto/hmac"
+	"crypto/subtle"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/sha256-simd"
 )
 
@@ -29,6 +31,10 @@ import (
 // client did not calculate sha256 of the payload.
 const unsignedPayload = "UNSIGNED-PAYLOAD"
 
+// amzSecurityToken is the header, query parameter and form field
+// carrying the session token of temporary credentials.
+const amzSecurityToken = "X-Amz-Security-Token"
+
 // skipContentSha256Cksum returns true if caller needs to skip
 // payload checksum, false if not.
 func skipContentSha256Cksum(r *http.Request) bool {
@@ -78,6 +84,42 @@ func getContentSha256Cksum(r *http.Request) string {
 	return defaultSha256Cksum
 }
 
+// check if the access key is valid and recognized, additionally
+// also returns if the access key is owner/admin.
+func checkKeyValid(accessKey string) (auth.Credentials, bool, APIErrorCode) {
+	cred := globalServerConfig.GetCredential()
+	if cred.AccessKey != accessKey {
+		if globalIAMSys == nil {
+			return cred, false, ErrInvalidAccessKeyID
+		}
+		ucred, ok := globalIAMSys.GetUser(accessKey)
+		if !ok {
+			return cred, false, ErrInvalidAccessKeyID
+		}
+		return ucred, false, ErrNone
+	}
+	return cred, true, ErrNone
+}
+
+// getSessionToken - returns the session token of the request, sent
+// either as header or as query parameter of presigned requests.
+func getSessionToken(r *http.Request) string {
+	if token := r.Header.Get(amzSecurityToken); token != "" {
+		return token
+	}
+	return r.URL.Query().Get(amzSecurityToken)
+}
+
+// checkSessionToken - verifies the session token sent along with
+// cred. Temporary credentials require their session token and static
+// credentials must not be sent with any.
+func checkSessionToken(token string, cred auth.Credentials) APIErrorCode {
+	if subtle.ConstantTimeCompare([]byte(token), []byte(cred.SessionToken)) != 1 {
+		return ErrInvalidToken
+	}
+	return ErrNone
+}
+
 // isValidRegion - verify if incoming region value is valid with configured Region.
 func isValidRegion(reqRegion string, confRegion string) bool {
 	if confRegion == "" {
@@ -32,6 +32,8 @@ const (
 	reducedRedundancyStorageClass = "REDUCED_REDUNDANCY"
 	// Standard storage class
 	standardStorageClass = "STANDARD"
+	// Archive storage class, only supported by S3 gateway
+	glacierStorageClass = "GLACIER"
 	// Reduced redundancy storage class environment variable
 	reducedRedundancyStorageClassEnv = "MINIO_STORAGE_CLASS_RRS"
 	// Standard storage class environment variable
@@ -74,6 +76,19 @@ func isValidStorageClassMeta(sc string) bool {
 	return sc == reducedRedundancyStorageClass || sc == standardStorageClass
 }
 
+// Validate if storage class in PutObject request is supported. S3 gateway
+// additionally accepts GLACIER storage class, which archives the object
+// to its cold tier.
+func isSupportedStorageClass(sc string) bool {
+	return isValidStorageClassMeta(sc) || sc == glacierStorageClass && globalGatewayName == "s3"
+}
+
+// isObjectArchived - returns whether data of the object is archived, such
+// objects are not readable until restored.
+func isObjectArchived(objInfo ObjectInfo) bool {
+	return objInfo.StorageClass == glacierStorageClass && !isObjectRestored(objInfo)
+}
+
 func (sc *storageClass) UnmarshalText(b []byte) error {
 	scStr := string(b)
 	if scStr == "" {
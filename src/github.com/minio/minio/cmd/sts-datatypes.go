@@ -0,0 +1,92 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/minio/minio/pkg/auth"
+)
+
+// stsAPIVersion is the only supported STS API version.
+const stsAPIVersion = "2011-06-15"
+
+// AssumedRoleUser - identifies the temporary credentials returned by
+// AssumeRole and AssumeRoleWithWebIdentity.
+type AssumedRoleUser struct {
+	Arn           string `xml:"Arn"`
+	AssumedRoleID string `xml:"AssumeRoleId"`
+}
+
+// AssumeRoleResponse - AssumeRole response.
+type AssumeRoleResponse struct {
+	XMLName xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleResponse" json:"-"`
+
+	Result           AssumeRoleResult `xml:"AssumeRoleResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId,omitempty"`
+	} `xml:"ResponseMetadata,omitempty"`
+}
+
+// AssumeRoleResult - result of AssumeRole.
+type AssumeRoleResult struct {
+	AssumedRoleUser AssumedRoleUser  `xml:"AssumedRoleUser"`
+	Credentials     auth.Credentials `xml:"Credentials"`
+}
+
+// AssumeRoleWithWebIdentityResponse - AssumeRoleWithWebIdentity response.
+type AssumeRoleWithWebIdentityResponse struct {
+	XMLName xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithWebIdentityResponse" json:"-"`
+
+	Result           WebIdentityResult `xml:"AssumeRoleWithWebIdentityResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId,omitempty"`
+	} `xml:"ResponseMetadata,omitempty"`
+}
+
+// WebIdentityResult - result of AssumeRoleWithWebIdentity.
+type WebIdentityResult struct {
+	AssumedRoleUser             AssumedRoleUser  `xml:"AssumedRoleUser"`
+	Credentials                 auth.Credentials `xml:"Credentials"`
+	SubjectFromWebIdentityToken string           `xml:"SubjectFromWebIdentityToken,omitempty"`
+}
+
+// STSErrorResponse - error response of the STS API.
+type STSErrorResponse struct {
+	XMLName xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ ErrorResponse" json:"-"`
+	Error   struct {
+		Type    string `xml:"Type"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+	RequestID string `xml:"RequestId"`
+}
+
+// writeSTSErrorResponse - writes error response in the STS format.
+func writeSTSErrorResponse(w http.ResponseWriter, errorCode APIErrorCode) {
+	apiError := getAPIError(errorCode)
+	stsErrorResponse := STSErrorResponse{}
+	stsErrorResponse.Error.Type = "Sender"
+	if apiError.HTTPStatusCode >= http.StatusInternalServerError {
+		stsErrorResponse.Error.Type = "Receiver"
+	}
+	stsErrorResponse.Error.Code = apiError.Code
+	stsErrorResponse.Error.Message = apiError.Description
+	stsErrorResponse.RequestID = mustGetRequestID(UTCNow())
+	writeResponse(w, apiError.HTTPStatusCode, encodeResponse(stsErrorResponse), mimeXML)
+}
@@ -0,0 +1,297 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/iam/validator"
+	"github.com/minio/minio/pkg/policy"
+	sha256 "github.com/minio/sha256-simd"
+)
+
+const (
+	// stsJWKSURLEnv is the environment variable holding the file path
+	// or URL of the JSON web key set validating web identity tokens.
+	stsJWKSURLEnv = "MINIO_IAM_JWKS_URL"
+
+	// stsJWTAudienceEnv and stsJWTIssuerEnv are the environment
+	// variables holding the audience and the issuer which web identity
+	// tokens must have.
+	stsJWTAudienceEnv = "MINIO_IAM_JWT_AUDIENCE"
+	stsJWTIssuerEnv   = "MINIO_IAM_JWT_ISSUER"
+
+	// Maximum size of an STS request body.
+	maxSTSRequestSize = 64 * 1024
+
+	// Bounds and default of the lifetime of temporary credentials.
+	minSTSDuration     = 15 * time.Minute
+	maxSTSDuration     = 12 * time.Hour
+	defaultSTSDuration = time.Hour
+
+	// stsPolicyClaim is the claim of a web identity token naming the
+	// canned policy of the temporary credentials.
+	stsPolicyClaim = "policy"
+)
+
+// STS actions.
+const (
+	stsActionAssumeRole                = "AssumeRole"
+	stsActionAssumeRoleWithWebIdentity = "AssumeRoleWithWebIdentity"
+)
+
+// loadSTSValidator returns the validator of web identity tokens
+// configured by the environment, or nil if none is configured.
+func loadSTSValidator() (*validator.JWT, error) {
+	location := os.Getenv(stsJWKSURLEnv)
+	if location == "" {
+		return nil, nil
+	}
+	return validator.NewJWT(location, os.Getenv(stsJWTAudienceEnv), os.Getenv(stsJWTIssuerEnv))
+}
+
+// stsAPIHandlers implements and provides http handlers for the AWS
+// STS API.
+type stsAPIHandlers struct{}
+
+// registerSTSRouter - registers the STS API, which is served on POST
+// requests to the root path with a form encoded body.
+func registerSTSRouter(router *mux.Router) {
+	stsAPI := stsAPIHandlers{}
+
+	stsRouter := router.NewRoute().PathPrefix("/").Subrouter()
+	stsRouter.Methods(http.MethodPost).Path("/").HeadersRegexp("Content-Type", "application/x-www-form-urlencoded*").HandlerFunc(stsAPI.STSHandler)
+}
+
+// STSHandler - POST /
+// ----------
+// Dispatches an STS request to the handler of its action.
+func (sts stsAPIHandlers) STSHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "STS")
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSTSRequestSize))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrMalformedPOSTRequest)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeSTSErrorResponse(w, ErrMalformedPOSTRequest)
+		return
+	}
+
+	if version := form.Get("Version"); version == "" {
+		writeSTSErrorResponse(w, ErrSTSMissingParameter)
+		return
+	} else if version != stsAPIVersion {
+		writeSTSErrorResponse(w, ErrSTSInvalidParameterValue)
+		return
+	}
+
+	switch form.Get("Action") {
+	case stsActionAssumeRole:
+		sts.assumeRole(ctx, w, r, body, form)
+	case stsActionAssumeRoleWithWebIdentity:
+		sts.assumeRoleWithWebIdentity(ctx, w, form)
+	default:
+		writeSTSErrorResponse(w, ErrSTSInvalidAction)
+	}
+}
+
+// parseSTSDuration - parses the optional DurationSeconds parameter.
+func parseSTSDuration(form url.Values) (time.Duration, APIErrorCode) {
+	durationStr := form.Get("DurationSeconds")
+	if durationStr == "" {
+		return defaultSTSDuration, ErrNone
+	}
+	seconds, err := strconv.Atoi(durationStr)
+	if err != nil {
+		return 0, ErrSTSInvalidParameterValue
+	}
+	duration := time.Duration(seconds) * time.Second
+	if duration < minSTSDuration || duration > maxSTSDuration {
+		return 0, ErrSTSInvalidParameterValue
+	}
+	return duration, ErrNone
+}
+
+// parseSTSSessionPolicy - parses the optional inline session policy,
+// which further restricts the temporary credentials. Session policies
+// are identity policies, they have no Principal.
+func parseSTSSessionPolicy(form url.Values) (*policy.Policy, APIErrorCode) {
+	policyStr := form.Get("Policy")
+	if policyStr == "" {
+		return nil, ErrNone
+	}
+	if len(policyStr) > maxBucketPolicySize {
+		return nil, ErrSTSMalformedPolicyDocument
+	}
+	sessionPolicy, err := policy.ParseIdentityConfig(strings.NewReader(policyStr))
+	if err != nil {
+		return nil, ErrSTSMalformedPolicyDocument
+	}
+	return sessionPolicy, ErrNone
+}
+
+// issueTempCredentials - creates temporary credentials of identity,
+// valid for the requested duration but not after maxExpiry if set, and
+// syncs them to all peers.
+func issueTempCredentials(ctx context.Context, form url.Values, identity iamSTSIdentity, maxExpiry time.Time) (auth.Credentials, APIErrorCode) {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return auth.Credentials{}, ErrServerNotInitialized
+	}
+
+	duration, s3Err := parseSTSDuration(form)
+	if s3Err != ErrNone {
+		return auth.Credentials{}, s3Err
+	}
+	if identity.SessionPolicy, s3Err = parseSTSSessionPolicy(form); s3Err != ErrNone {
+		return auth.Credentials{}, s3Err
+	}
+
+	expiry := UTCNow().Add(duration)
+	if !maxExpiry.IsZero() && expiry.After(maxExpiry) {
+		expiry = maxExpiry
+	}
+	cred, err := auth.GetNewTempCredentials(expiry)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return auth.Credentials{}, ErrInternalError
+	}
+	identity.Credentials = cred
+	if err = globalIAMSys.SetTempUser(objectAPI, identity); err != nil {
+		logger.LogIf(ctx, err)
+		return auth.Credentials{}, toAPIErrorCode(err)
+	}
+
+	loadIAMItemOnPeers(ctx, iamConfigSTSPrefix, cred.AccessKey)
+	return cred, ErrNone
+}
+
+// assumeRole - issues temporary credentials of the user who signed
+// the request.
+func (sts stsAPIHandlers) assumeRole(ctx context.Context, w http.ResponseWriter, r *http.Request, body []byte, form url.Values) {
+	// The body is read already, verify the signature with its checksum.
+	sum := sha256.Sum256(body)
+	hashedPayload := hex.EncodeToString(sum[:])
+	if v := r.Header.Get("X-Amz-Content-Sha256"); v != "" && v != hashedPayload {
+		writeSTSErrorResponse(w, ErrContentSHA256Mismatch)
+		return
+	}
+	if !isRequestSignatureV4(r) {
+		writeSTSErrorResponse(w, ErrAccessDenied)
+		return
+	}
+	region := globalServerConfig.GetRegion()
+	if s3Err := doesSignatureMatch(hashedPayload, r, region, serviceSTS); s3Err != ErrNone {
+		writeSTSErrorResponse(w, s3Err)
+		return
+	}
+	cred, _, s3Err := getReqAccessKeyV4(r, region, serviceSTS)
+	if s3Err != ErrNone {
+		writeSTSErrorResponse(w, s3Err)
+		return
+	}
+	// Temporary credentials cannot assume roles.
+	if cred.IsTemp() {
+		writeSTSErrorResponse(w, ErrAccessDenied)
+		return
+	}
+
+	tempCred, s3Err := issueTempCredentials(ctx, form, iamSTSIdentity{ParentUser: cred.AccessKey}, time.Time{})
+	if s3Err != ErrNone {
+		writeSTSErrorResponse(w, s3Err)
+		return
+	}
+
+	response := AssumeRoleResponse{
+		Result: AssumeRoleResult{
+			AssumedRoleUser: AssumedRoleUser{
+				Arn:           "arn:aws:sts:::assumed-role/" + cred.AccessKey + "/" + form.Get("RoleSessionName"),
+				AssumedRoleID: tempCred.AccessKey,
+			},
+			Credentials: tempCred,
+		},
+	}
+	response.ResponseMetadata.RequestID = mustGetRequestID(UTCNow())
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// assumeRoleWithWebIdentity - issues temporary credentials for a web
+// identity token. Their permissions are those of the canned policy
+// named by the token.
+func (sts stsAPIHandlers) assumeRoleWithWebIdentity(ctx context.Context, w http.ResponseWriter, form url.Values) {
+	if globalSTSValidator == nil {
+		writeSTSErrorResponse(w, ErrSTSNotConfigured)
+		return
+	}
+
+	token := form.Get("WebIdentityToken")
+	if token == "" {
+		writeSTSErrorResponse(w, ErrSTSMissingParameter)
+		return
+	}
+	claims, expiry, err := globalSTSValidator.Validate(token)
+	if err != nil {
+		if err == validator.ErrTokenExpired {
+			writeSTSErrorResponse(w, ErrSTSExpiredToken)
+			return
+		}
+		writeSTSErrorResponse(w, ErrSTSInvalidIdentityToken)
+		return
+	}
+
+	policyName, _ := claims[stsPolicyClaim].(string)
+	if !globalIAMSys.hasPolicy(policyName) {
+		writeSTSErrorResponse(w, ErrAccessDenied)
+		return
+	}
+
+	// The temporary credentials do not outlive the token.
+	tempCred, s3Err := issueTempCredentials(ctx, form, iamSTSIdentity{PolicyName: policyName}, expiry)
+	if s3Err != ErrNone {
+		writeSTSErrorResponse(w, s3Err)
+		return
+	}
+
+	subject, _ := claims["sub"].(string)
+	response := AssumeRoleWithWebIdentityResponse{
+		Result: WebIdentityResult{
+			AssumedRoleUser: AssumedRoleUser{
+				Arn:           "arn:aws:sts:::assumed-role/" + policyName + "/" + form.Get("RoleSessionName"),
+				AssumedRoleID: tempCred.AccessKey,
+			},
+			Credentials:                 tempCred,
+			SubjectFromWebIdentityToken: subject,
+		},
+	}
+	response.ResponseMetadata.RequestID = mustGetRequestID(UTCNow())
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
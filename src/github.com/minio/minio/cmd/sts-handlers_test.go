@@ -0,0 +1,375 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/iam/validator"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// prepareSTSTestBed - sets up an FS object layer with IAM and returns
+// a router serving the STS API.
+func prepareSTSTestBed(t *testing.T) (ObjectLayer, *mux.Router, func()) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	router := mux.NewRouter()
+	registerSTSRouter(router)
+
+	return obj, router, func() {
+		globalObjLayerMutex.Lock()
+		globalObjectAPI = nil
+		globalObjLayerMutex.Unlock()
+		globalIAMSys = NewIAMSys()
+		os.RemoveAll(fsDir)
+		os.RemoveAll(rootPath)
+	}
+}
+
+// newSTSRequest - returns an STS request with the given form values,
+// signed by accessKey in the STS scope unless accessKey is empty.
+func newSTSRequest(t *testing.T, form url.Values, accessKey, secretKey string) *http.Request {
+	body := []byte(form.Encode())
+	req, err := newTestRequest(http.MethodPost, "http://127.0.0.1:9000/", int64(len(body)), bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if accessKey != "" {
+		if err = signRequestV4Service(req, accessKey, secretKey, serviceSTS); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return req
+}
+
+// checkSTSError - checks that the response is an STS error of code.
+func checkSTSError(t *testing.T, name string, rec *httptest.ResponseRecorder, code APIErrorCode) {
+	apiErr := getAPIError(code)
+	if rec.Code != apiErr.HTTPStatusCode {
+		t.Fatalf("%s: expected status %d, got %d: %s", name, apiErr.HTTPStatusCode, rec.Code, rec.Body.String())
+	}
+	var errResp STSErrorResponse
+	if err := xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if errResp.Error.Code != apiErr.Code {
+		t.Fatalf("%s: expected error code %s, got %s", name, apiErr.Code, errResp.Error.Code)
+	}
+}
+
+func TestSTSAssumeRole(t *testing.T) {
+	obj, router, cleanup := prepareSTSTestBed(t)
+	defer cleanup()
+
+	accessKey, secretKey := "alice", "alice-secret"
+	if err := globalIAMSys.SetUser(obj, accessKey, madmin.UserInfo{SecretKey: secretKey}); err != nil {
+		t.Fatal(err)
+	}
+	if err := globalIAMSys.SetUserPolicy(obj, accessKey, cannedPolicyReadWrite); err != nil {
+		t.Fatal(err)
+	}
+
+	// Session policy restricting the credentials to reading "bucket".
+	sessionPolicy := `{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Sid": "ReadBucket",
+            "Effect": "Allow",
+            "Action": "s3:GetObject",
+            "Resource": "arn:aws:s3:::bucket/*"
+        }
+    ]
+}`
+
+	form := url.Values{}
+	form.Set("Action", stsActionAssumeRole)
+	form.Set("Version", stsAPIVersion)
+	form.Set("DurationSeconds", "900")
+	form.Set("Policy", sessionPolicy)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newSTSRequest(t, form, accessKey, secretKey))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response AssumeRoleResponse
+	if err := xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	tempCred := response.Result.Credentials
+	if !tempCred.IsTemp() || tempCred.IsExpired() || tempCred.SecretKey == "" {
+		t.Fatalf("Expected valid temporary credentials, got %v", tempCred)
+	}
+	if d := tempCred.Expiration.Sub(UTCNow()); d > 15*time.Minute || d < 14*time.Minute {
+		t.Fatalf("Expected credentials to expire in 15 minutes, got %v", d)
+	}
+
+	testCases := []struct {
+		action       policy.Action
+		object       string
+		sessionToken string
+		expected     APIErrorCode
+	}{
+		{policy.GetObjectAction, "/bucket/object", tempCred.SessionToken, ErrNone},
+		// Allowed by the parent, but not by the session policy.
+		{policy.PutObjectAction, "/bucket/object", tempCred.SessionToken, ErrAccessDenied},
+		{policy.GetObjectAction, "/other/object", tempCred.SessionToken, ErrAccessDenied},
+		{policy.GetObjectAction, "/bucket/object", "", ErrInvalidToken},
+		{policy.GetObjectAction, "/bucket/object", "invalid-token", ErrInvalidToken},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestRequest(http.MethodGet, "http://127.0.0.1:9000"+testCase.object, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.sessionToken != "" {
+			req.Header.Set(amzSecurityToken, testCase.sessionToken)
+		}
+		if err = signRequestV4(req, tempCred.AccessKey, tempCred.SecretKey); err != nil {
+			t.Fatal(err)
+		}
+		bucket, object := "bucket", "object"
+		if strings.HasPrefix(testCase.object, "/other/") {
+			bucket = "other"
+		}
+		if errCode := checkRequestAuthType(context.Background(), req, testCase.action, bucket, object); errCode != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, errCode)
+		}
+	}
+
+	// Temporary credentials cannot assume a role again.
+	rec = httptest.NewRecorder()
+	req := newSTSRequest(t, url.Values{"Action": {stsActionAssumeRole}, "Version": {stsAPIVersion}}, "", "")
+	req.Header.Set(amzSecurityToken, tempCred.SessionToken)
+	if err := signRequestV4Service(req, tempCred.AccessKey, tempCred.SecretKey, serviceSTS); err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(rec, req)
+	checkSTSError(t, "temporary credentials", rec, ErrAccessDenied)
+
+	// Credentials disappear with their parent user.
+	if err := globalIAMSys.SetUserStatus(obj, accessKey, madmin.AccountDisabled); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := globalIAMSys.GetUser(tempCred.AccessKey); ok {
+		t.Fatal("Expected temporary credentials of a disabled user to be rejected")
+	}
+}
+
+func TestSTSAssumeRoleErrors(t *testing.T) {
+	_, router, cleanup := prepareSTSTestBed(t)
+	defer cleanup()
+
+	cred := globalServerConfig.GetCredential()
+	testCases := []struct {
+		name      string
+		form      url.Values
+		secretKey string
+		expected  APIErrorCode
+	}{
+		{"missing version", url.Values{"Action": {stsActionAssumeRole}}, cred.SecretKey, ErrSTSMissingParameter},
+		{"invalid version", url.Values{"Action": {stsActionAssumeRole}, "Version": {"2006-03-01"}}, cred.SecretKey, ErrSTSInvalidParameterValue},
+		{"invalid action", url.Values{"Action": {"GetCallerIdentity"}, "Version": {stsAPIVersion}}, cred.SecretKey, ErrSTSInvalidAction},
+		{"short duration", url.Values{"Action": {stsActionAssumeRole}, "Version": {stsAPIVersion}, "DurationSeconds": {"60"}}, cred.SecretKey, ErrSTSInvalidParameterValue},
+		{"long duration", url.Values{"Action": {stsActionAssumeRole}, "Version": {stsAPIVersion}, "DurationSeconds": {"86400"}}, cred.SecretKey, ErrSTSInvalidParameterValue},
+		{"malformed policy", url.Values{"Action": {stsActionAssumeRole}, "Version": {stsAPIVersion}, "Policy": {"{"}}, cred.SecretKey, ErrSTSMalformedPolicyDocument},
+		{"policy with principal", url.Values{"Action": {stsActionAssumeRole}, "Version": {stsAPIVersion}, "Policy": {`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`}}, cred.SecretKey, ErrSTSMalformedPolicyDocument},
+		{"wrong secret", url.Values{"Action": {stsActionAssumeRole}, "Version": {stsAPIVersion}}, "wrong-secret", ErrSignatureDoesNotMatch},
+	}
+	for _, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, newSTSRequest(t, testCase.form, cred.AccessKey, testCase.secretKey))
+		checkSTSError(t, testCase.name, rec, testCase.expected)
+	}
+
+	// Requests signed in the S3 scope are rejected.
+	body := []byte(url.Values{"Action": {stsActionAssumeRole}, "Version": {stsAPIVersion}}.Encode())
+	req, err := newTestRequest(http.MethodPost, "http://127.0.0.1:9000/", int64(len(body)), bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	checkSTSError(t, "s3 scope", rec, ErrInvalidService)
+}
+
+func TestSTSAssumeRoleWithWebIdentity(t *testing.T) {
+	_, router, cleanup := prepareSTSTestBed(t)
+	defer cleanup()
+
+	form := url.Values{}
+	form.Set("Action", stsActionAssumeRoleWithWebIdentity)
+	form.Set("Version", stsAPIVersion)
+
+	// Not configured yet.
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, newSTSRequest(t, form, "", ""))
+	checkSTSError(t, "not configured", rec, ErrSTSNotConfigured)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err := json.Marshal(validator.JWKS{Keys: []validator.JWK{{
+		Kty: "RSA",
+		Kid: "key-1",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := ioutil.TempFile("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(jwks); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if globalSTSValidator, err = validator.NewJWT(file.Name(), "minio", "https://idp"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { globalSTSValidator = nil }()
+
+	newToken := func(claims jwtgo.MapClaims) string {
+		if _, ok := claims["aud"]; !ok {
+			claims["aud"] = "minio"
+		}
+		claims["iss"] = "https://idp"
+		token := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	testCases := []struct {
+		name     string
+		token    string
+		expected APIErrorCode
+	}{
+		{"missing token", "", ErrSTSMissingParameter},
+		{"malformed token", "not-a-token", ErrSTSInvalidIdentityToken},
+		{"expired token", newToken(jwtgo.MapClaims{"exp": UTCNow().Add(-time.Minute).Unix(), "policy": cannedPolicyReadOnly}), ErrSTSExpiredToken},
+		{"unknown policy", newToken(jwtgo.MapClaims{"exp": UTCNow().Add(time.Hour).Unix(), "policy": "unknown"}), ErrAccessDenied},
+		{"no policy", newToken(jwtgo.MapClaims{"exp": UTCNow().Add(time.Hour).Unix()}), ErrAccessDenied},
+		{"other audience", newToken(jwtgo.MapClaims{"exp": UTCNow().Add(time.Hour).Unix(), "policy": cannedPolicyReadOnly, "aud": "other"}), ErrSTSInvalidIdentityToken},
+	}
+	for _, testCase := range testCases {
+		form.Set("WebIdentityToken", testCase.token)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, newSTSRequest(t, form, "", ""))
+		checkSTSError(t, testCase.name, rec, testCase.expected)
+	}
+
+	// The token expires before the requested duration.
+	exp := UTCNow().Add(30 * time.Minute).Unix()
+	form.Set("WebIdentityToken", newToken(jwtgo.MapClaims{
+		"exp":    exp,
+		"sub":    "bob",
+		"policy": cannedPolicyReadOnly,
+	}))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, newSTSRequest(t, form, "", ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response AssumeRoleWithWebIdentityResponse
+	if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Result.SubjectFromWebIdentityToken != "bob" {
+		t.Fatalf("Expected subject bob, got %q", response.Result.SubjectFromWebIdentityToken)
+	}
+
+	tempCred := response.Result.Credentials
+	if tempCred.Expiration == nil || tempCred.Expiration.Unix() != exp {
+		t.Fatalf("Expected credentials to expire with the token at %d, got %v", exp, tempCred.Expiration)
+	}
+	for _, testCase := range []struct {
+		action   policy.Action
+		expected APIErrorCode
+	}{
+		{policy.GetObjectAction, ErrNone},
+		{policy.PutObjectAction, ErrAccessDenied},
+	} {
+		if errCode := checkTempCredRequest(t, tempCred, testCase.action); errCode != testCase.expected {
+			t.Errorf("%s: expected %v, got %v", testCase.action, testCase.expected, errCode)
+		}
+	}
+}
+
+// checkTempCredRequest - checks a request for action on bucket/object
+// signed with temporary credentials.
+func checkTempCredRequest(t *testing.T, cred auth.Credentials, action policy.Action) APIErrorCode {
+	req, err := newTestRequest(http.MethodGet, "http://127.0.0.1:9000/bucket/object", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(amzSecurityToken, cred.SessionToken)
+	if err = signRequestV4(req, cred.AccessKey, cred.SecretKey); err != nil {
+		t.Fatal(err)
+	}
+	return checkRequestAuthType(context.Background(), req, action, "bucket", "object")
+}
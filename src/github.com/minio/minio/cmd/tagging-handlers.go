@@ -0,0 +1,269 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/tagging"
+)
+
+// Namespace of Tagging XML in responses.
+const taggingXMLNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// parseTaggingRequest - parses Tagging XML in request body having at
+// most maxTags tags.
+func parseTaggingRequest(r *http.Request, maxTags int) (*tagging.Tagging, APIErrorCode) {
+	t, err := tagging.ParseConfig(io.LimitReader(r.Body, maxTaggingConfigSize), maxTags)
+	if err != nil {
+		if _, ok := err.(tagging.ErrInvalidTag); ok {
+			return nil, ErrInvalidTag
+		}
+		return nil, ErrMalformedXML
+	}
+
+	return t, ErrNone
+}
+
+// PutBucketTaggingHandler - PUT Bucket tagging
+// -----------------
+// This operation uses the tagging
+// subresource to set the tags of a specified bucket.
+func (api objectAPIHandlers) PutBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "PutBucketTagging")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketTaggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	bucketTagging, s3Error := parseTaggingRequest(r, tagging.MaxBucketTags)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := objAPI.SetBucketTagging(ctx, bucket, bucketTagging); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
+
+// GetBucketTaggingHandler - GET Bucket tagging
+// -----------------
+// This operation uses the tagging
+// subresource to return the tags of a specified bucket.
+func (api objectAPIHandlers) GetBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "GetBucketTagging")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketTaggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	bucketTagging, err := objAPI.GetBucketTagging(ctx, bucket)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+	bucketTagging.XMLNS = taggingXMLNS
+
+	// Write to client.
+	writeSuccessResponseXML(w, encodeResponse(bucketTagging))
+}
+
+// DeleteBucketTaggingHandler - DELETE Bucket tagging
+// -----------------
+// This operation uses the tagging
+// subresource to remove the tags of a specified bucket.
+func (api objectAPIHandlers) DeleteBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "DeleteBucketTagging")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketTaggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Removing tags of a bucket without tags is not an error.
+	if err := objAPI.DeleteBucketTagging(ctx, bucket); err != nil {
+		if _, ok := err.(BucketTaggingNotFound); !ok {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
+
+// PutObjectTaggingHandler - PUT Object tagging
+// -----------------
+// This operation uses the tagging
+// subresource to replace the tags of a specified object.
+func (api objectAPIHandlers) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "PutObjectTagging")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutObjectTaggingAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objectTagging, s3Error := parseTaggingRequest(r, tagging.MaxObjectTags)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := objAPI.PutObjectTags(ctx, bucket, object, objectTagging.String()); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetObjectTaggingHandler - GET Object tagging
+// -----------------
+// This operation uses the tagging
+// subresource to return the tags of a specified object.
+func (api objectAPIHandlers) GetObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "GetObjectTagging")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectTaggingAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	objectTagging := getObjectTags(objInfo)
+	objectTagging.XMLNS = taggingXMLNS
+	if objectTagging.TagSet.Tags == nil {
+		// Always send an empty TagSet element.
+		objectTagging.TagSet.Tags = []tagging.Tag{}
+	}
+
+	// Write to client.
+	writeSuccessResponseXML(w, encodeResponse(objectTagging))
+}
+
+// DeleteObjectTaggingHandler - DELETE Object tagging
+// -----------------
+// This operation uses the tagging
+// subresource to remove the tags of a specified object.
+func (api objectAPIHandlers) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "DeleteObjectTagging")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.DeleteObjectTaggingAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := objAPI.PutObjectTags(ctx, bucket, object, ""); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// Success.
+	writeSuccessNoContent(w)
+}
@@ -0,0 +1,175 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"path"
+
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+	"github.com/minio/minio/pkg/tagging"
+)
+
+const (
+	// Tagging configuration file.
+	bucketTaggingConfig = "tagging.xml"
+
+	// Tags of the object in x-amz-tagging header and in object metadata.
+	amzObjectTagging = "X-Amz-Tagging"
+
+	// Whether CopyObject copies tags of the source object or replaces
+	// them with x-amz-tagging header.
+	amzTaggingDirective = "X-Amz-Tagging-Directive"
+
+	// Number of tags of the object in response headers.
+	amzTaggingCount = "x-amz-tagging-count"
+
+	// Maximum size of Tagging XML.
+	maxTaggingConfigSize = 1024 * 1024
+)
+
+// getObjectTags - returns tags of the object saved in its metadata.
+func getObjectTags(objInfo ObjectInfo) *tagging.Tagging {
+	if tags, ok := objInfo.UserDefined[amzObjectTagging]; ok {
+		if t, err := tagging.ParseObjectTags(tags); err == nil {
+			return t
+		}
+	}
+
+	return &tagging.Tagging{}
+}
+
+// extractTagsFromHeader - validates x-amz-tagging header and saves the
+// tags in metadata.
+func extractTagsFromHeader(header http.Header, metadata map[string]string) APIErrorCode {
+	if _, ok := header[amzObjectTagging]; !ok {
+		return ErrNone
+	}
+
+	t, err := tagging.ParseObjectTags(header.Get(amzObjectTagging))
+	if err != nil {
+		return ErrInvalidTag
+	}
+
+	if t.IsEmpty() {
+		delete(metadata, amzObjectTagging)
+	} else {
+		metadata[amzObjectTagging] = t.String()
+	}
+
+	return ErrNone
+}
+
+// isTaggingDirectiveValid - returns whether x-amz-tagging-directive
+// header is valid.
+func isTaggingDirectiveValid(header http.Header) bool {
+	switch header.Get(amzTaggingDirective) {
+	case "", "COPY", "REPLACE":
+		return true
+	}
+
+	return false
+}
+
+// isTaggingReplace - returns whether x-amz-tagging-directive is REPLACE.
+func isTaggingReplace(header http.Header) bool {
+	return header.Get(amzTaggingDirective) == "REPLACE"
+}
+
+// addExistingObjectTags - adds tags of the existing object to condition
+// values of object actions which support s3:ExistingObjectTag keys, if
+// the bucket policy or policies of the account use them.
+func addExistingObjectTags(ctx context.Context, r *http.Request, action policy.Action, accountName, bucket, object string, values map[string][]string) {
+	switch action {
+	case policy.GetObjectAction, policy.GetObjectVersionAction, policy.DeleteObjectVersionAction:
+	case policy.GetObjectTaggingAction, policy.PutObjectTaggingAction, policy.DeleteObjectTaggingAction:
+	default:
+		return
+	}
+
+	if !globalPolicySys.HasConditionKey(bucket, condition.S3ExistingObjectTag) &&
+		!globalIAMSys.HasConditionKey(accountName, condition.S3ExistingObjectTag) {
+		return
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil || object == "" {
+		return
+	}
+
+	var objInfo ObjectInfo
+	var err error
+	if versionID := r.URL.Query().Get("versionId"); versionID != "" {
+		objInfo, err = objAPI.GetObjectVersionInfo(ctx, bucket, object, versionID)
+	} else {
+		objInfo, err = objAPI.GetObjectInfo(ctx, bucket, object)
+	}
+	if err != nil {
+		return
+	}
+
+	for key, value := range getObjectTags(objInfo).ToMap() {
+		values["ExistingObjectTag/"+key] = []string{value}
+	}
+}
+
+// getBucketTaggingConfig - get tagging config for given bucket name.
+func getBucketTaggingConfig(objAPI ObjectLayer, bucketName string) (*tagging.Tagging, error) {
+	// Construct path to tagging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketTaggingConfig)
+
+	reader, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			err = BucketTaggingNotFound{Bucket: bucketName}
+		}
+
+		return nil, err
+	}
+
+	return tagging.ParseConfig(reader, tagging.MaxBucketTags)
+}
+
+func saveBucketTaggingConfig(objAPI ObjectLayer, bucketName string, bucketTagging *tagging.Tagging) error {
+	data, err := xml.Marshal(bucketTagging)
+	if err != nil {
+		return err
+	}
+
+	// Construct path to tagging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketTaggingConfig)
+
+	return saveConfig(objAPI, configFile, data)
+}
+
+func removeBucketTaggingConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to tagging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketTaggingConfig)
+
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+		if _, ok := err.(ObjectNotFound); ok {
+			return BucketTaggingNotFound{Bucket: bucketName}
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/tagging"
+)
+
+func TestExtractTagsFromHeader(t *testing.T) {
+	testCases := []struct {
+		header         http.Header
+		expectedTags   string
+		expectedResult APIErrorCode
+	}{
+		{http.Header{}, "", ErrNone},
+		{http.Header{amzObjectTagging: []string{""}}, "", ErrNone},
+		{http.Header{amzObjectTagging: []string{"project=minio&env=prod"}}, "env=prod&project=minio", ErrNone},
+		{http.Header{amzObjectTagging: []string{"env=prod&env=dev"}}, "", ErrInvalidTag},
+		{http.Header{amzObjectTagging: []string{"=prod"}}, "", ErrInvalidTag},
+		{http.Header{amzObjectTagging: []string{"a=1&b=2&c=3&d=4&e=5&f=6&g=7&h=8&i=9&j=10&k=11"}}, "", ErrInvalidTag},
+	}
+
+	for i, testCase := range testCases {
+		metadata := map[string]string{}
+		result := extractTagsFromHeader(testCase.header, metadata)
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+		if metadata[amzObjectTagging] != testCase.expectedTags {
+			t.Fatalf("case %v: tags: expected: %v, got: %v", i+1, testCase.expectedTags, metadata[amzObjectTagging])
+		}
+	}
+}
+
+func TestIsTaggingDirectiveValid(t *testing.T) {
+	testCases := []struct {
+		directive      string
+		expectedResult bool
+	}{
+		{"", true},
+		{"COPY", true},
+		{"REPLACE", true},
+		{"copy", false},
+		{"MOVE", false},
+	}
+
+	for i, testCase := range testCases {
+		header := http.Header{}
+		if testCase.directive != "" {
+			header.Set(amzTaggingDirective, testCase.directive)
+		}
+		if result := isTaggingDirectiveValid(header); result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+// Wrapper for calling tagging tests for both XL and FS.
+func TestObjectTagging(t *testing.T) {
+	ExecObjectLayerTest(t, testObjectTagging)
+}
+
+// Tests validate saving and reading of bucket and object tags.
+func testObjectTagging(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	bucketName := getRandomBucketName()
+	objectName := "dir/object"
+	if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	// Bucket without tags.
+	if _, err := obj.GetBucketTagging(context.Background(), bucketName); err != (BucketTaggingNotFound{Bucket: bucketName}) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, BucketTaggingNotFound{Bucket: bucketName}, err)
+	}
+
+	bucketTagging := &tagging.Tagging{TagSet: tagging.TagSet{Tags: []tagging.Tag{{Key: "project", Value: "minio"}}}}
+	if err := obj.SetBucketTagging(context.Background(), bucketName, bucketTagging); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	result, err := obj.GetBucketTagging(context.Background(), bucketName)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if !reflect.DeepEqual(result.TagSet, bucketTagging.TagSet) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, bucketTagging.TagSet, result.TagSet)
+	}
+	if err = obj.DeleteBucketTagging(context.Background(), bucketName); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+	if err = obj.DeleteBucketTagging(context.Background(), bucketName); err != (BucketTaggingNotFound{Bucket: bucketName}) {
+		t.Fatalf("%s: expected: %v, got: %v", instanceType, BucketTaggingNotFound{Bucket: bucketName}, err)
+	}
+
+	// Tags of non-existent object.
+	if err = obj.PutObjectTags(context.Background(), bucketName, objectName, "env=dev"); err == nil {
+		t.Fatalf("%s: expected an error for non-existent object", instanceType)
+	}
+
+	data := []byte("hello")
+	metadata := map[string]string{amzObjectTagging: "env=dev"}
+	if _, err = obj.PutObject(context.Background(), bucketName, objectName, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), metadata); err != nil {
+		t.Fatalf("%s: unexpected error: %v", instanceType, err)
+	}
+
+	testCases := []struct {
+		tags         string
+		expectedTags map[string]string
+	}{
+		{"env=prod&project=minio", map[string]string{"env": "prod", "project": "minio"}},
+		{"", map[string]string{}},
+	}
+
+	for i, testCase := range testCases {
+		if err = obj.PutObjectTags(context.Background(), bucketName, objectName, testCase.tags); err != nil {
+			t.Fatalf("%s: case %v: unexpected error: %v", instanceType, i+1, err)
+		}
+
+		objInfo, err := obj.GetObjectInfo(context.Background(), bucketName, objectName)
+		if err != nil {
+			t.Fatalf("%s: case %v: unexpected error: %v", instanceType, i+1, err)
+		}
+		if tags := getObjectTags(objInfo).ToMap(); !reflect.DeepEqual(tags, testCase.expectedTags) {
+			t.Fatalf("%s: case %v: expected: %v, got: %v", instanceType, i+1, testCase.expectedTags, tags)
+		}
+		if objInfo.Size != int64(len(data)) {
+			t.Fatalf("%s: case %v: object size changed to %v", instanceType, i+1, objInfo.Size)
+		}
+	}
+}
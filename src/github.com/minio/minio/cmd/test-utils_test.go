@@ -358,8 +358,7 @@ func UnstartedTestServer(t TestErrHandler, instanceType string) TestServer {
 		t.Fatalf("Unable to create new notification system. %v", err)
 	}
 
-	// Create new policy system.
-	globalPolicySys = NewPolicySys()
+	resetGlobalBucketSystems()
 
 	return testServer
 }
@@ -558,6 +557,18 @@ func resetGlobalCacheObjectAPI() {
 	globalCacheObjectAPI = nil
 }
 
+// creates new policy, bucket metadata, ACL, IAM and bucket replication,
+// quota and logging systems.
+func resetGlobalBucketSystems() {
+	globalPolicySys = NewPolicySys()
+	globalBucketMetadataSys = NewBucketMetadataSys()
+	globalACLSys = NewACLSys()
+	globalBucketReplicationSys = NewBucketReplicationSys()
+	globalBucketQuotaSys = NewBucketQuotaSys()
+	globalBucketLoggingSys = NewBucketLoggingSys()
+	globalIAMSys = NewIAMSys()
+}
+
 // Resets all the globals used modified in tests.
 // Resetting ensures that the changes made to globals by one test doesn't affect others.
 func resetTestGlobals() {
@@ -968,7 +979,7 @@ func preSignV4(req *http.Request, accessKeyID, secretAccessKey string, expires i
 	queryStr := strings.Replace(query.Encode(), "+", "%20", -1)
 	canonicalRequest := getCanonicalRequest(extractedSignedHeaders, unsignedPayload, queryStr, req.URL.Path, req.Method)
 	stringToSign := getStringToSign(canonicalRequest, date, scope)
-	signingKey := getSigningKey(secretAccessKey, date, region)
+	signingKey := getSigningKey(secretAccessKey, date, region, serviceS3)
 	signature := getSignature(signingKey, stringToSign)
 
 	req.URL.RawQuery = query.Encode()
@@ -1048,6 +1059,11 @@ func signRequestV2(req *http.Request, accessKey, secretKey string) error {
 
 // Sign given request using Signature V4.
 func signRequestV4(req *http.Request, accessKey, secretKey string) error {
+	return signRequestV4Service(req, accessKey, secretKey, serviceS3)
+}
+
+// Sign given request using Signature V4 in the scope of the given service.
+func signRequestV4Service(req *http.Request, accessKey, secretKey string, stype serviceType) error {
 	// Get hashed payload.
 	hashedPayload := req.Header.Get("x-amz-content-sha256")
 	if hashedPayload == "" {
@@ -1129,7 +1145,7 @@ func signRequestV4(req *http.Request, accessKey, secretKey string) error {
 	scope := strings.Join([]string{
 		currTime.Format(yyyymmdd),
 		region,
-		"s3",
+		string(stype),
 		"aws4_request",
 	}, "/")
 
@@ -1139,7 +1155,7 @@ func signRequestV4(req *http.Request, accessKey, secretKey string) error {
 
 	date := sumHMAC([]byte("AWS4"+secretKey), []byte(currTime.Format(yyyymmdd)))
 	regionHMAC := sumHMAC(date, []byte(region))
-	service := sumHMAC(regionHMAC, []byte("s3"))
+	service := sumHMAC(regionHMAC, []byte(stype))
 	signingKey := sumHMAC(service, []byte("aws4_request"))
 
 	signature := hex.EncodeToString(sumHMAC(signingKey, []byte(stringToSign)))
@@ -1539,6 +1555,41 @@ func getDeletePolicyURL(endPoint, bucketName string) string {
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+// return URL for bucket encryption operations.
+func getBucketEncryptionURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("encryption", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for bucket CORS operations.
+func getBucketCORSURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("cors", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for bucket website operations.
+func getBucketWebsiteURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("website", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for bucket logging operations.
+func getBucketLoggingURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("logging", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// return URL for bucket replication operations.
+func getBucketReplicationURL(endPoint, bucketName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("replication", "")
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // return URL for creating the bucket.
 func getMakeBucketURL(endPoint, bucketName string) string {
 	return makeTestTargetURL(endPoint, bucketName, "", url.Values{})
@@ -1602,6 +1653,21 @@ func getNewMultipartURL(endPoint, bucketName, objectName string) string {
 	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
 }
 
+// return URL for selecting object content.
+func getSelectObjectContentURL(endPoint, bucketName, objectName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("select", "")
+	queryValue.Set("select-type", "2")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
+// return URL for restoring an archived object.
+func getRestoreObjectURL(endPoint, bucketName, objectName string) string {
+	queryValue := url.Values{}
+	queryValue.Set("restore", "")
+	return makeTestTargetURL(endPoint, bucketName, objectName, queryValue)
+}
+
 // return URL for a new multipart upload.
 func getPartUploadURL(endPoint, bucketName, objectName, uploadID, partNumber string) string {
 	queryValues := url.Values{}
@@ -1724,8 +1790,7 @@ func newTestObjectLayer(endpoints EndpointList) (newObject ObjectLayer, err erro
 		return nil, err
 	}
 
-	// Create new policy system.
-	globalPolicySys = NewPolicySys()
+	resetGlobalBucketSystems()
 
 	return xl, nil
 }
@@ -1995,6 +2060,9 @@ func ExecObjectLayerAPITest(t *testing.T, objAPITest objAPITestType, endpoints [
 	if err != nil {
 		t.Fatalf("Unable to initialize server config. %s", err)
 	}
+
+	resetGlobalBucketSystems()
+
 	objLayer, fsDir, err := prepareFS()
 	if err != nil {
 		t.Fatalf("Initialization of object layer failed for single node setup: %s", err)
@@ -2045,6 +2113,8 @@ func ExecObjectLayerTest(t TestErrHandler, objTest objTestType) {
 	}
 	defer os.RemoveAll(rootPath)
 
+	resetGlobalBucketSystems()
+
 	objLayer, fsDir, err := prepareFS()
 	if err != nil {
 		t.Fatalf("Initialization of object layer failed for single node setup: %s", err)
@@ -2174,6 +2244,12 @@ func registerBucketLevelFunc(bucket *mux.Router, api objectAPIHandlers, apiFunct
 		case "NewMultipart":
 			// Register New Multipart upload handler.
 			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.NewMultipartUploadHandler).Queries("uploads", "")
+		case "SelectObjectContent":
+			// Register SelectObjectContent handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.SelectObjectContentHandler).Queries("select", "").Queries("select-type", "2")
+		case "RestoreObject":
+			// Register RestoreObject handler.
+			bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(api.RestoreObjectHandler).Queries("restore", "")
 		case "CopyObjectPart":
 			// Register CopyObjectPart handler.
 			bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(api.CopyObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}")
@@ -2192,6 +2268,48 @@ func registerBucketLevelFunc(bucket *mux.Router, api objectAPIHandlers, apiFunct
 		case "AbortMultipart":
 			// Register AbortMultipart Handler.
 			bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(api.AbortMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}")
+		case "PutBucketEncryption":
+			// Register PutBucketEncryption Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketEncryptionHandler).Queries("encryption", "")
+		case "GetBucketEncryption":
+			// Register GetBucketEncryption Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketEncryptionHandler).Queries("encryption", "")
+		case "DeleteBucketEncryption":
+			// Register DeleteBucketEncryption Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketEncryptionHandler).Queries("encryption", "")
+		case "PutBucketCORS":
+			// Register PutBucketCORS Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketCORSHandler).Queries("cors", "")
+		case "PutBucketReplication":
+			// Register PutBucketReplication Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketReplicationHandler).Queries("replication", "")
+		case "PutBucketLogging":
+			// Register PutBucketLogging Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketLoggingHandler).Queries("logging", "")
+		case "PutBucketWebsite":
+			// Register PutBucketWebsite Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketWebsiteHandler).Queries("website", "")
+		case "GetBucketCORS":
+			// Register GetBucketCORS Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketCORSHandler).Queries("cors", "")
+		case "GetBucketReplication":
+			// Register GetBucketReplication Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketReplicationHandler).Queries("replication", "")
+		case "GetBucketLogging":
+			// Register GetBucketLogging Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketLoggingHandler).Queries("logging", "")
+		case "GetBucketWebsite":
+			// Register GetBucketWebsite Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketWebsiteHandler).Queries("website", "")
+		case "DeleteBucketCORS":
+			// Register DeleteBucketCORS Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketCORSHandler).Queries("cors", "")
+		case "DeleteBucketReplication":
+			// Register DeleteBucketReplication Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketReplicationHandler).Queries("replication", "")
+		case "DeleteBucketWebsite":
+			// Register DeleteBucketWebsite Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketWebsiteHandler).Queries("website", "")
 		case "GetBucketNotification":
 			// Register GetBucketNotification Handler.
 			bucket.Methods("GET").HandlerFunc(api.GetBucketNotificationHandler).Queries("notification", "")
@@ -47,6 +47,21 @@ var (
 		"MINIO_CACHE_EXCLUDE: Cache exclusion patterns are delimited by `;`",
 	)
 
+	uiErrInvalidKMSConfig = newUIErrFn(
+		"Invalid KMS configuration",
+		"Please check the passed values",
+		`Configure exactly one KMS:
+MINIO_SSE_MASTER_KEY: a master key of the form <key-id>:<64 hex characters>
+MINIO_SSE_MASTER_KEY_FILE: a file containing one master key per line
+MINIO_SSE_VAULT_ENDPOINT: a Vault endpoint, along with MINIO_SSE_VAULT_APPROLE_ID, MINIO_SSE_VAULT_APPROLE_SECRET and MINIO_SSE_VAULT_KEY_NAME`,
+	)
+
+	uiErrInvalidSTSConfig = newUIErrFn(
+		"Invalid web identity configuration",
+		"Please check the passed value",
+		"MINIO_IAM_JWKS_URL: a file path or http(s) URL of a JSON web key set, MINIO_IAM_JWT_AUDIENCE and MINIO_IAM_JWT_ISSUER: the expected audience and issuer of web identity tokens",
+	)
+
 	uiErrInvalidCacheExpiryValue = newUIErrFn(
 		"Invalid cache expiry value",
 		"Please check the passed value",
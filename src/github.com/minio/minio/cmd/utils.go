@@ -335,7 +335,17 @@ func newContext(r *http.Request, api string) context.Context {
 	if prefix != "" {
 		object = prefix
 	}
-	reqInfo := &logger.ReqInfo{RemoteHost: r.RemoteAddr, UserAgent: r.Header.Get("user-agent"), API: api, BucketName: bucket, ObjectName: object}
+	// Fill the request information of the HTTP stats handler, if any,
+	// which is used for access logging.
+	reqInfo := logger.GetReqInfo(r.Context())
+	if reqInfo == nil {
+		reqInfo = &logger.ReqInfo{}
+	}
+	reqInfo.RemoteHost = r.RemoteAddr
+	reqInfo.UserAgent = r.Header.Get("user-agent")
+	reqInfo.API = api
+	reqInfo.BucketName = bucket
+	reqInfo.ObjectName = object
 	return logger.SetReqInfo(context.Background(), reqInfo)
 }
 
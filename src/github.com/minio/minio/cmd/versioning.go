@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/minio/minio/pkg/versioning"
+	"github.com/skyrings/skyring-common/tools/uuid"
+)
+
+const (
+	// Version ID of objects written while versioning is suspended or
+	// before versioning was enabled.
+	nullVersionID = "null"
+
+	// Version ID of the object in response headers.
+	amzVersionID = "x-amz-version-id"
+
+	// Indicates whether the version of the object is a delete marker
+	// in response headers.
+	amzDeleteMarker = "x-amz-delete-marker"
+)
+
+// getBucketVersioningStatus - returns versioning status of given bucket,
+// which is empty if versioning was never configured.
+func getBucketVersioningStatus(bucketName string) string {
+	if isMinioMetaBucketName(bucketName) {
+		return ""
+	}
+
+	v := globalBucketMetadataSys.GetVersioning(bucketName)
+	if v == nil {
+		return ""
+	}
+	return v.Status
+}
+
+// newVersionID - returns version ID for a new version of an object in
+// given bucket, which is empty if versioning was never configured.
+func newVersionID(bucketName string) string {
+	switch getBucketVersioningStatus(bucketName) {
+	case versioning.Enabled:
+		return mustGetUUID()
+	case versioning.Suspended:
+		return nullVersionID
+	}
+
+	return ""
+}
+
+// isValidVersionID - checks whether given version ID is either null
+// version ID or an UUID generated by newVersionID.
+func isValidVersionID(versionID string) bool {
+	if versionID == nullVersionID {
+		return true
+	}
+
+	if len(versionID) != 36 {
+		return false
+	}
+
+	_, err := uuid.Parse(versionID)
+	return err == nil
+}
+
+// getVersioningConfig - get versioning config for given bucket name, an
+// empty configuration is returned if versioning was never configured.
+func getVersioningConfig(objAPI ObjectLayer, bucketName string) (*versioning.Versioning, error) {
+	config, err := getBucketConfig(objAPI, bucketName, bucketVersioningConfig)
+	if err != nil {
+		if err == errConfigNotFound {
+			return &versioning.Versioning{}, nil
+		}
+
+		return nil, err
+	}
+
+	return config.(*versioning.Versioning), nil
+}
+
+// splitCopySourceVersionID splits the optional versionId query
+// from an unescaped x-amz-copy-source value.
+func splitCopySourceVersionID(cpSrcPath string) (string, string) {
+	i := strings.LastIndex(cpSrcPath, "?versionId=")
+	if i < 0 {
+		return cpSrcPath, ""
+	}
+	return cpSrcPath[:i], cpSrcPath[i+len("?versionId="):]
+}
@@ -0,0 +1,57 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+func TestIsValidVersionID(t *testing.T) {
+	testCases := []struct {
+		versionID string
+		expected  bool
+	}{
+		{"null", true},
+		{"2c4fb3e4-4a41-4b1e-8b59-2c2bcd1f3b7b", true},
+		{"", false},
+		{"abc", false},
+		{"2c4fb3e4-4a41-4b1e-8b59-2c2bcd1f3b7", false},
+	}
+
+	for i, testCase := range testCases {
+		if result := isValidVersionID(testCase.versionID); result != testCase.expected {
+			t.Errorf("test %d: expected %v, got %v", i+1, testCase.expected, result)
+		}
+	}
+}
+
+func TestSplitCopySourceVersionID(t *testing.T) {
+	testCases := []struct {
+		cpSrcPath         string
+		expectedPath      string
+		expectedVersionID string
+	}{
+		{"/bucket/object", "/bucket/object", ""},
+		{"bucket/object?versionId=null", "bucket/object", "null"},
+		{"/bucket/dir/object?versionId=2c4fb3e4-4a41-4b1e-8b59-2c2bcd1f3b7b", "/bucket/dir/object", "2c4fb3e4-4a41-4b1e-8b59-2c2bcd1f3b7b"},
+	}
+
+	for i, testCase := range testCases {
+		path, versionID := splitCopySourceVersionID(testCase.cpSrcPath)
+		if path != testCase.expectedPath || versionID != testCase.expectedVersionID {
+			t.Errorf("test %d: expected (%s, %s), got (%s, %s)", i+1, testCase.expectedPath, testCase.expectedVersionID, path, versionID)
+		}
+	}
+}
@@ -139,6 +139,13 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 		return toJSONError(err, args.BucketName)
 	}
 
+	// Notify bucket created event.
+	sendEvent(eventArgs{
+		EventName:  event.BucketCreated,
+		BucketName: args.BucketName,
+		ReqParams:  extractReqParams(r),
+	})
+
 	reply.UIVersion = browser.UIVersion
 	return nil
 }
@@ -169,8 +176,18 @@ func (web *webAPIHandlers) DeleteBucket(r *http.Request, args *RemoveBucketArgs,
 		return toJSONError(err, args.BucketName)
 	}
 
+	// Notify bucket removed event.
+	sendEvent(eventArgs{
+		EventName:  event.BucketRemoved,
+		BucketName: args.BucketName,
+		ReqParams:  extractReqParams(r),
+	})
+
 	globalNotificationSys.RemoveNotification(args.BucketName)
 	globalPolicySys.Remove(args.BucketName)
+	globalBucketMetadataSys.Remove(args.BucketName)
+	globalBucketQuotaSys.Remove(args.BucketName)
+	globalACLSys.Remove(args.BucketName)
 	for nerr := range globalNotificationSys.DeleteBucket(args.BucketName) {
 		logger.GetReqInfo(ctx).AppendTags("remotePeer", nerr.Host.Name)
 		logger.LogIf(ctx, nerr.Err)
@@ -609,12 +626,31 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Deny if the object exceeds the hard quota of the bucket.
+	replacedSize, releaseQuota, err := globalBucketQuotaSys.CheckQuota(context.Background(), objectAPI, bucket, object, size)
+	if err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+	defer releaseQuota()
+
+	setReplicationStatus(context.Background(), r, bucket, object, metadata)
+
 	objInfo, err := putObject(context.Background(), bucket, object, hashReader, metadata)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
 	}
 
+	// New object does not inherit the ACL of the object it replaces.
+	if err = resetObjectACL(context.Background(), objectAPI, bucket, object); err != nil {
+		writeWebErrorResponse(w, err)
+		return
+	}
+
+	globalBucketQuotaSys.ObjectCreated(bucket, objInfo.Size, replacedSize)
+	globalBucketReplicationSys.Replicate(bucket, objInfo)
+
 	// Notify object created event.
 	sendEvent(eventArgs{
 		EventName:  event.ObjectCreatedPut,
@@ -1014,7 +1050,7 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	extractedSignedHeaders.Set("host", host)
 	canonicalRequest := getCanonicalRequest(extractedSignedHeaders, unsignedPayload, queryStr, path, "GET")
 	stringToSign := getStringToSign(canonicalRequest, date, getScope(date, region))
-	signingKey := getSigningKey(secretKey, date, region)
+	signingKey := getSigningKey(secretKey, date, region, serviceS3)
 	signature := getSignature(signingKey, stringToSign)
 
 	// Construct the final presigned URL.
@@ -0,0 +1,254 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/ioutil"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/website"
+)
+
+// Redirect location of website requests for an object, stored in its
+// metadata.
+const amzWebsiteRedirectLocation = "x-amz-website-redirect-location"
+
+// websiteAPIHandlers implements and provides http handlers serving the
+// static websites of buckets.
+type websiteAPIHandlers struct {
+	ObjectAPI func() ObjectLayer
+}
+
+// registerWebsiteRouter - registers the static website endpoint, which
+// serves the website of bucket on bucket.<website domain>.
+func registerWebsiteRouter(router *mux.Router) {
+	if globalWebsiteDomainName == "" {
+		return
+	}
+
+	websiteAPI := websiteAPIHandlers{
+		ObjectAPI: newObjectLayerFn,
+	}
+
+	websiteRouter := router.Host("{bucket:.+}." + globalWebsiteDomainName).Subrouter()
+	websiteRouter.NewRoute().HandlerFunc(httpTraceHdrs(websiteAPI.WebsiteHandler))
+}
+
+// getWebsiteBucketName - returns the bucket of a request to the static
+// website endpoint, or an empty string for other requests. Minio
+// metadata buckets are never served as websites.
+func getWebsiteBucketName(r *http.Request) string {
+	if globalWebsiteDomainName == "" {
+		return ""
+	}
+	host := r.Host
+	if i := strings.Index(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	if !strings.HasSuffix(host, "."+globalWebsiteDomainName) {
+		return ""
+	}
+	bucket := strings.TrimSuffix(host, "."+globalWebsiteDomainName)
+	if isMinioMetaBucketName(bucket) || !IsValidBucketName(bucket) {
+		return ""
+	}
+	return bucket
+}
+
+// isWebsiteReq - returns whether the request is for the static website
+// endpoint.
+func isWebsiteReq(r *http.Request) bool {
+	return getWebsiteBucketName(r) != ""
+}
+
+// isValidWebsiteRedirectLocation - returns whether location is a valid
+// value of the website redirect location of an object.
+func isValidWebsiteRedirectLocation(location string) bool {
+	return location == "" || strings.HasPrefix(location, "/") ||
+		strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// WebsiteHandler - GET/HEAD on the website endpoint
+// ----------
+// Serves the static website of a bucket: index documents of directory
+// paths, routing rule and object redirects, and the error document on
+// failed requests. Only publicly readable objects are served.
+func (web websiteAPIHandlers) WebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, "Website")
+
+	objAPI := web.ObjectAPI()
+	if objAPI == nil {
+		writeWebsiteErrorResponse(w, r, ErrServerNotInitialized)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeWebsiteErrorResponse(w, r, ErrMethodNotAllowed)
+		return
+	}
+
+	bucket := getWebsiteBucketName(r)
+	if bucket == "" {
+		writeWebsiteErrorResponse(w, r, ErrNoSuchBucket)
+		return
+	}
+	config := globalBucketMetadataSys.GetWebsite(bucket)
+	if config == nil {
+		if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+			writeWebsiteErrorResponse(w, r, toAPIErrorCode(err))
+			return
+		}
+		writeWebsiteErrorResponse(w, r, ErrNoSuchWebsiteConfiguration)
+		return
+	}
+
+	scheme := getURLScheme(globalIsSSL)
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if location, code, ok := config.Route(scheme, r.Host, key, 0); ok {
+		http.Redirect(w, r, location, code)
+		return
+	}
+
+	objectKey := config.IndexKey(key)
+	objInfo, s3Error := getWebsiteObjectInfo(ctx, objAPI, r, bucket, objectKey)
+	if s3Error != ErrNone && objectKey == key && key != "" {
+		// Directories requested without trailing slash are redirected,
+		// backends may report them either missing or inaccessible.
+		if _, errCode := getWebsiteObjectInfo(ctx, objAPI, r, bucket, config.IndexKey(key+"/")); errCode == ErrNone {
+			http.Redirect(w, r, "/"+key+"/", http.StatusFound)
+			return
+		}
+	}
+	if s3Error != ErrNone {
+		writeWebsiteError(ctx, w, r, objAPI, bucket, key, *config, s3Error)
+		return
+	}
+
+	if location := objInfo.UserDefined[amzWebsiteRedirectLocation]; location != "" {
+		http.Redirect(w, r, location, http.StatusMovedPermanently)
+		return
+	}
+
+	serveWebsiteObject(ctx, w, r, objAPI, objInfo, http.StatusOK)
+}
+
+// getWebsiteObjectInfo - returns the info of an object readable by the
+// website request.
+func getWebsiteObjectInfo(ctx context.Context, objAPI ObjectLayer, r *http.Request, bucket, object string) (ObjectInfo, APIErrorCode) {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectAction, bucket, object); s3Error != ErrNone {
+		return ObjectInfo{}, ErrAccessDenied
+	}
+
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return objInfo, toAPIErrorCode(err)
+	}
+
+	// Archived objects are not readable until restored.
+	if isObjectArchived(objInfo) {
+		return objInfo, ErrInvalidObjectState
+	}
+	return objInfo, ErrNone
+}
+
+// writeWebsiteError - responds to a failed website request with the
+// redirect of a routing rule for the error, the error document of the
+// website or an error page.
+func writeWebsiteError(ctx context.Context, w http.ResponseWriter, r *http.Request, objAPI ObjectLayer,
+	bucket, key string, config website.Config, s3Error APIErrorCode) {
+	statusCode := getAPIError(s3Error).HTTPStatusCode
+	if location, code, ok := config.Route(getURLScheme(globalIsSSL), r.Host, key, statusCode); ok {
+		http.Redirect(w, r, location, code)
+		return
+	}
+
+	if config.ErrorDocument != nil && statusCode >= 400 && statusCode < 500 {
+		if objInfo, errCode := getWebsiteObjectInfo(ctx, objAPI, r, bucket, config.ErrorDocument.Key); errCode == ErrNone {
+			serveWebsiteObject(ctx, w, r, objAPI, objInfo, statusCode)
+			return
+		}
+	}
+
+	writeWebsiteErrorResponse(w, r, s3Error)
+}
+
+// serveWebsiteObject - writes the object with statusCode.
+func serveWebsiteObject(ctx context.Context, w http.ResponseWriter, r *http.Request, objAPI ObjectLayer, objInfo ObjectInfo, statusCode int) {
+	var encrypted bool
+	if objAPI.IsEncryptionSupported() {
+		var apiErr APIErrorCode
+		if apiErr, encrypted = DecryptObjectInfo(&objInfo, r.Header); apiErr != ErrNone {
+			writeWebsiteErrorResponse(w, r, apiErr)
+			return
+		}
+	}
+
+	// Validate pre-conditions if any.
+	if statusCode == http.StatusOK && checkPreconditions(w, r, objInfo) {
+		return
+	}
+
+	var writer io.Writer = w
+	var startOffset int64
+	length := objInfo.Size
+	if encrypted {
+		setSSEHeaders(w, objInfo.UserDefined)
+
+		var err error
+		writer = ioutil.LimitedWriter(writer, 0, length)
+		if writer, startOffset, length, err = DecryptBlocksRequest(writer, r, startOffset, length, objInfo, false); err != nil {
+			writeWebsiteErrorResponse(w, r, toAPIErrorCode(err))
+			return
+		}
+	}
+
+	setObjectHeaders(w, objInfo, nil)
+	w.WriteHeader(statusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	httpWriter := ioutil.WriteOnClose(writer)
+	if err := objAPI.GetObject(ctx, objInfo.Bucket, objInfo.Name, startOffset, length, httpWriter, objInfo.ETag); err != nil {
+		logger.LogIf(ctx, err)
+	}
+	httpWriter.Close()
+}
+
+// writeWebsiteErrorResponse - writes an HTML error page, as website
+// clients are browsers rather than S3 clients.
+func writeWebsiteErrorResponse(w http.ResponseWriter, r *http.Request, errorCode APIErrorCode) {
+	apiError := getAPIError(errorCode)
+	setCommonHeaders(w)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(apiError.HTTPStatusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	title := fmt.Sprintf("%d %s", apiError.HTTPStatusCode, http.StatusText(apiError.HTTPStatusCode))
+	fmt.Fprintf(w, "<html>\n<head><title>%s</title></head>\n<body>\n<h1>%s</h1>\n<ul>\n<li>Code: %s</li>\n<li>Message: %s</li>\n<li>RequestId: %s</li>\n</ul>\n</body>\n</html>\n",
+		title, title, html.EscapeString(apiError.Code), html.EscapeString(apiError.Description), w.Header().Get(responseRequestIDKey))
+}
@@ -0,0 +1,245 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/website"
+)
+
+func TestBucketWebsiteHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketWebsiteHandlers, []string{"PutBucketWebsite", "GetBucketWebsite", "DeleteBucketWebsite"})
+}
+
+func testBucketWebsiteHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	var err error
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, url string, body []byte) *httptest.ResponseRecorder {
+		req, err := newTestSignedRequestV4(method, url, int64(len(body)), bytes.NewReader(body), credentials.AccessKey, credentials.SecretKey)
+		if err != nil {
+			t.Fatalf("%s: Failed to create HTTP request for %s %s: <ERROR> %v", instanceType, method, url, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	websiteURL := getBucketWebsiteURL("", bucketName)
+
+	if rec := serve("GET", websiteURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+
+	invalidConfig := []byte(`<WebsiteConfiguration><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`)
+	if rec := serve("PUT", websiteURL, invalidConfig); rec.Code != http.StatusBadRequest {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusBadRequest, rec.Code)
+	}
+
+	config := []byte(`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`)
+	if rec := serve("PUT", websiteURL, config); rec.Code != http.StatusOK {
+		t.Fatalf("%s: PutBucketWebsite failed with status `%d`", instanceType, rec.Code)
+	}
+	if globalBucketMetadataSys.GetWebsite(bucketName) == nil {
+		t.Fatalf("%s: Expected website configuration to be cached", instanceType)
+	}
+	rec := serve("GET", websiteURL, nil)
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte("<Suffix>index.html</Suffix>")) {
+		t.Fatalf("%s: GetBucketWebsite failed with status `%d`: %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	if rec = serve("DELETE", websiteURL, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("%s: DeleteBucketWebsite failed with status `%d`", instanceType, rec.Code)
+	}
+	if rec = serve("GET", websiteURL, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("%s: Expected the response status to be `%d`, but instead found `%d`", instanceType, http.StatusNotFound, rec.Code)
+	}
+}
+
+// Tests that only requests to a bucket subdomain of the website domain
+// are website requests.
+func TestGetWebsiteBucketName(t *testing.T) {
+	defer func(domain string) { globalWebsiteDomainName = domain }(globalWebsiteDomainName)
+	globalWebsiteDomainName = "website.local"
+
+	testCases := []struct {
+		host           string
+		expectedBucket string
+	}{
+		{"site.website.local", "site"},
+		{"site.website.local:9000", "site"},
+		{"my.site.website.local", "my.site"},
+		// Not website requests.
+		{"localhost:9000", ""},
+		{"example.com", ""},
+		{"website.local", ""},
+		{"sitewebsite.local", ""},
+		{"site.website.local.example.com", ""},
+		{minioMetaBucket, ""},
+		{minioMetaBucket + ".website.local", ""},
+		{minioMetaTmpBucket + ".website.local:9000", ""},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = testCase.host
+		if bucket := getWebsiteBucketName(req); bucket != testCase.expectedBucket {
+			t.Errorf("Test %d: expected bucket %q, got %q", i+1, testCase.expectedBucket, bucket)
+		}
+		if isWebsiteReq(req) != (testCase.expectedBucket != "") {
+			t.Errorf("Test %d: unexpected website request %v", i+1, isWebsiteReq(req))
+		}
+	}
+
+	// Without website domain no request is a website request.
+	globalWebsiteDomainName = ""
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Host = "site.website.local"
+	if isWebsiteReq(req) {
+		t.Error("Expected no website request without website domain")
+	}
+}
+
+func TestWebsiteHandler(t *testing.T) {
+	ExecObjectLayerTest(t, testWebsiteHandler)
+}
+
+func testWebsiteHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	defer func(domain string) { globalWebsiteDomainName = domain }(globalWebsiteDomainName)
+	globalWebsiteDomainName = "website.local"
+
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	ctx := context.Background()
+	bucket, privateBucket := "site", "private"
+	for _, name := range []string{bucket, privateBucket} {
+		if err := obj.MakeBucketWithLocation(ctx, name, ""); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	objects := map[string]map[string]string{
+		"index.html":      nil,
+		"docs/index.html": nil,
+		"error.html":      nil,
+		"moved.html":      {amzWebsiteRedirectLocation: "/docs/"},
+	}
+	for name, metadata := range objects {
+		data := []byte("content of " + name)
+		if _, err := obj.PutObject(ctx, bucket, name, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), metadata); err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+	}
+
+	publicRead, err := policy.ParseConfig(strings.NewReader(fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/*"]}]}`, bucket)), bucket)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	globalPolicySys.Set(bucket, *publicRead)
+	defer globalPolicySys.Remove(bucket)
+
+	globalBucketMetadataSys.Set(bucket, bucketWebsiteConfig, &website.Config{
+		IndexDocument: &website.IndexDocument{Suffix: "index.html"},
+		ErrorDocument: &website.ErrorDocument{Key: "error.html"},
+		RoutingRules: []website.RoutingRule{{
+			Condition: &website.Condition{KeyPrefixEquals: "old/"},
+			Redirect:  website.Redirect{ReplaceKeyPrefixWith: "docs/"},
+		}},
+	})
+	defer globalBucketMetadataSys.Remove(bucket)
+
+	router := mux.NewRouter()
+	registerWebsiteRouter(router)
+
+	testCases := []struct {
+		method           string
+		host             string
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+	}{
+		{http.MethodGet, "site.website.local", "/", http.StatusOK, "content of index.html", ""},
+		{http.MethodGet, "site.website.local:9000", "/docs/", http.StatusOK, "content of docs/index.html", ""},
+		{http.MethodHead, "site.website.local", "/index.html", http.StatusOK, "", ""},
+		// Directory without trailing slash.
+		{http.MethodGet, "site.website.local", "/docs", http.StatusFound, "", "/docs/"},
+		// Object redirect.
+		{http.MethodGet, "site.website.local", "/moved.html", http.StatusMovedPermanently, "", "/docs/"},
+		// Routing rule.
+		{http.MethodGet, "site.website.local", "/old/a.html", http.StatusMovedPermanently, "", "http://site.website.local/docs/a.html"},
+		// Error document.
+		{http.MethodGet, "site.website.local", "/missing.html", http.StatusNotFound, "content of error.html", ""},
+		// Buckets without website configuration.
+		{http.MethodGet, "private.website.local", "/", http.StatusNotFound, "NoSuchWebsiteConfiguration", ""},
+		{http.MethodGet, "unknown.website.local", "/", http.StatusNotFound, "NoSuchBucket", ""},
+		// Minio metadata buckets are not served.
+		{http.MethodGet, minioMetaBucket + ".website.local", "/config/config.json", http.StatusNotFound, "NoSuchBucket", ""},
+		{http.MethodPut, "site.website.local", "/index.html", http.StatusMethodNotAllowed, "", ""},
+	}
+
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, testCase.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = testCase.host
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedStatus {
+			t.Errorf("%s: test %d: expected status %d, got %d: %s", instanceType, i+1, testCase.expectedStatus, rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), testCase.expectedBody) {
+			t.Errorf("%s: test %d: expected body containing %q, got %q", instanceType, i+1, testCase.expectedBody, rec.Body.String())
+		}
+		if location := rec.Header().Get("Location"); location != testCase.expectedLocation {
+			t.Errorf("%s: test %d: expected location %q, got %q", instanceType, i+1, testCase.expectedLocation, location)
+		}
+	}
+
+	// Objects are only served if publicly readable.
+	globalPolicySys.Remove(bucket)
+	req, err := http.NewRequest(http.MethodGet, "/index.html", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Host = "site.website.local"
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("%s: expected status %d, got %d", instanceType, http.StatusForbidden, rec.Code)
+	}
+}
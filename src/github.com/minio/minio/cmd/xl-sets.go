@@ -27,11 +27,15 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/bpool"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/policy"
 	"github.com/minio/minio/pkg/sync/errgroup"
+	"github.com/minio/minio/pkg/tagging"
+	"github.com/minio/minio/pkg/versioning"
 )
 
 // setsStorageAPI is encapsulated type for Close()
@@ -275,6 +279,36 @@ func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesP
 		return nil, fmt.Errorf("Unable to initialize policy system. %v", err)
 	}
 
+	// Initialize bucket metadata system.
+	if err := globalBucketMetadataSys.Init(s); err != nil {
+		return nil, fmt.Errorf("Unable to initialize bucket metadata system. %v", err)
+	}
+
+	// Initialize ACL system.
+	if err := globalACLSys.Init(s); err != nil {
+		return nil, fmt.Errorf("Unable to initialize ACL system. %v", err)
+	}
+
+	// Initialize bucket replication system.
+	if err := globalBucketReplicationSys.Init(s); err != nil {
+		return nil, fmt.Errorf("Unable to initialize bucket replication system. %v", err)
+	}
+
+	// Initialize bucket quota system.
+	if err := globalBucketQuotaSys.Init(s); err != nil {
+		return nil, fmt.Errorf("Unable to initialize bucket quota system. %v", err)
+	}
+
+	// Initialize bucket logging system.
+	if err := globalBucketLoggingSys.Init(s); err != nil {
+		return nil, fmt.Errorf("Unable to initialize bucket logging system. %v", err)
+	}
+
+	// Initialize IAM system.
+	if err := globalIAMSys.Init(s); err != nil {
+		return nil, fmt.Errorf("Unable to initialize IAM system. %v", err)
+	}
+
 	// Start the disk monitoring and connect routine.
 	go s.monitorAndConnectEndpoints(defaultMonitorConnectEndpointInterval)
 
@@ -486,6 +520,81 @@ func (s *xlSets) DeleteBucketPolicy(ctx context.Context, bucket string) error {
 	return removePolicyConfig(ctx, s, bucket)
 }
 
+// SetBucketConfig persists the new configuration file on the bucket.
+func (s *xlSets) SetBucketConfig(ctx context.Context, bucket, configFile string, config interface{}) error {
+	return saveBucketConfig(s, bucket, configFile, config)
+}
+
+// GetBucketConfig will return the configuration file of a bucket.
+func (s *xlSets) GetBucketConfig(ctx context.Context, bucket, configFile string) (interface{}, error) {
+	return getBucketConfig(s, bucket, configFile)
+}
+
+// DeleteBucketConfig deletes the configuration file of a bucket.
+func (s *xlSets) DeleteBucketConfig(ctx context.Context, bucket, configFile string) error {
+	return removeBucketConfig(ctx, s, bucket, configFile)
+}
+
+// SetBucketLifecycle persists the new lifecycle configuration on the bucket.
+func (s *xlSets) SetBucketLifecycle(ctx context.Context, bucket string, lifecycle *lifecycle.Lifecycle) error {
+	return saveBucketConfig(s, bucket, bucketLifecycleConfig, lifecycle)
+}
+
+// GetBucketLifecycle will return the lifecycle configuration of a bucket.
+func (s *xlSets) GetBucketLifecycle(ctx context.Context, bucket string) (*lifecycle.Lifecycle, error) {
+	return getLifecycleConfig(s, bucket)
+}
+
+// DeleteBucketLifecycle deletes the lifecycle configuration of a bucket.
+func (s *xlSets) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	return removeBucketConfig(ctx, s, bucket, bucketLifecycleConfig)
+}
+
+// SetBucketTagging persists the new tagging configuration on the bucket.
+func (s *xlSets) SetBucketTagging(ctx context.Context, bucket string, t *tagging.Tagging) error {
+	return saveBucketTaggingConfig(s, bucket, t)
+}
+
+// GetBucketTagging will return the tagging configuration of a bucket.
+func (s *xlSets) GetBucketTagging(ctx context.Context, bucket string) (*tagging.Tagging, error) {
+	return getBucketTaggingConfig(s, bucket)
+}
+
+// DeleteBucketTagging deletes the tagging configuration of a bucket.
+func (s *xlSets) DeleteBucketTagging(ctx context.Context, bucket string) error {
+	return removeBucketTaggingConfig(ctx, s, bucket)
+}
+
+// SetBucketVersioning persists the new versioning configuration on the bucket.
+func (s *xlSets) SetBucketVersioning(ctx context.Context, bucket string, v *versioning.Versioning) error {
+	return saveBucketConfig(s, bucket, bucketVersioningConfig, v)
+}
+
+// GetBucketVersioning will return the versioning configuration of a bucket.
+func (s *xlSets) GetBucketVersioning(ctx context.Context, bucket string) (*versioning.Versioning, error) {
+	return getVersioningConfig(s, bucket)
+}
+
+// SetBucketAccessControlPolicy persists the new ACL on the bucket.
+func (s *xlSets) SetBucketAccessControlPolicy(ctx context.Context, bucket string, aclPolicy *acl.AccessControlPolicy) error {
+	return saveBucketConfig(s, bucket, bucketACLConfig, aclPolicy)
+}
+
+// GetBucketAccessControlPolicy will return the ACL of a bucket.
+func (s *xlSets) GetBucketAccessControlPolicy(ctx context.Context, bucket string) (*acl.AccessControlPolicy, error) {
+	return getBucketAccessControlPolicy(s, bucket)
+}
+
+// SetObjectAccessControlPolicy persists the new ACL on the object.
+func (s *xlSets) SetObjectAccessControlPolicy(ctx context.Context, bucket, object string, aclPolicy *acl.AccessControlPolicy) error {
+	return saveObjectACLConfig(s, bucket, object, aclPolicy)
+}
+
+// GetObjectAccessControlPolicy will return the ACL of an object.
+func (s *xlSets) GetObjectAccessControlPolicy(ctx context.Context, bucket, object string) (*acl.AccessControlPolicy, error) {
+	return getObjectAccessControlPolicy(s, bucket, object)
+}
+
 // IsNotificationSupported returns whether bucket notification is applicable for this layer.
 func (s *xlSets) IsNotificationSupported() bool {
 	return s.getHashedSet("").IsNotificationSupported()
@@ -571,9 +680,60 @@ func (s *xlSets) GetObjectInfo(ctx context.Context, bucket, object string) (objI
 	return s.getHashedSet(object).GetObjectInfo(ctx, bucket, object)
 }
 
+// GetObjectVersion - reads a version of an object from the hashedSet based on the object name.
+func (s *xlSets) GetObjectVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	return s.getHashedSet(object).GetObjectVersion(ctx, bucket, object, versionID, startOffset, length, writer, etag)
+}
+
+// GetObjectVersionInfo - reads metadata of a version of an object from the hashedSet based on the object name.
+func (s *xlSets) GetObjectVersionInfo(ctx context.Context, bucket, object, versionID string) (objInfo ObjectInfo, err error) {
+	return s.getHashedSet(object).GetObjectVersionInfo(ctx, bucket, object, versionID)
+}
+
+// DeleteObjectVersion - deletes a version of an object from the hashedSet based on the object name.
+func (s *xlSets) DeleteObjectVersion(ctx context.Context, bucket, object, versionID string) (objInfo ObjectInfo, err error) {
+	set := s.getHashedSet(object)
+	if objInfo, err = set.DeleteObjectVersion(ctx, bucket, object, versionID); err != nil {
+		return objInfo, err
+	}
+
+	// Delete object ACL once no version of the object is left - ignore any errors.
+	if versions, verr := set.getObjectVersions(ctx, bucket, object); verr == nil && len(versions) == 0 {
+		removeObjectACLConfig(ctx, s, bucket, object)
+	}
+
+	return objInfo, nil
+}
+
+// PutObjectTags - replaces tags of an object in the hashedSet based on the object name.
+func (s *xlSets) PutObjectTags(ctx context.Context, bucket, object, tags string) error {
+	return s.getHashedSet(object).PutObjectTags(ctx, bucket, object, tags)
+}
+
+// PutObjectReplicationStatus - sets the replication status of an object in the hashedSet based on the object name.
+func (s *xlSets) PutObjectReplicationStatus(ctx context.Context, bucket, object, etag, status string) error {
+	return s.getHashedSet(object).PutObjectReplicationStatus(ctx, bucket, object, etag, status)
+}
+
+// PutObjectMetadata - sets metadata of an object version in the hashedSet based on the object name.
+func (s *xlSets) PutObjectMetadata(ctx context.Context, bucket, object, versionID, etag string, metadata map[string]string) error {
+	return s.getHashedSet(object).PutObjectMetadata(ctx, bucket, object, versionID, etag, metadata)
+}
+
 // DeleteObject - deletes an object from the hashedSet based on the object name.
 func (s *xlSets) DeleteObject(ctx context.Context, bucket string, object string) (err error) {
-	return s.getHashedSet(object).DeleteObject(ctx, bucket, object)
+	if err = s.getHashedSet(object).DeleteObject(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	// In versioned buckets only a delete marker is added and the object
+	// data is kept along with its ACL.
+	if !isMinioMetaBucketName(bucket) && getBucketVersioningStatus(bucket) == "" {
+		// Delete object ACL, if present - ignore any errors.
+		removeObjectACLConfig(ctx, s, bucket, object)
+	}
+
+	return nil
 }
 
 // CopyObject - copies objects from one hashedSet to another hashedSet, on server side.
@@ -583,7 +743,8 @@ func (s *xlSets) CopyObject(ctx context.Context, srcBucket, srcObject, destBucke
 
 	// Check if this request is only metadata update.
 	cpSrcDstSame := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(destBucket, destObject))
-	if cpSrcDstSame && srcInfo.metadataOnly {
+	// Metadata update of an object in versioned bucket creates a new version.
+	if cpSrcDstSame && srcInfo.metadataOnly && getBucketVersioningStatus(destBucket) == "" {
 		return srcSet.CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, srcInfo)
 	}
 
@@ -610,7 +771,13 @@ func (s *xlSets) CopyObject(ctx context.Context, srcBucket, srcObject, destBucke
 	}
 
 	go func() {
-		if gerr := srcSet.getObject(ctx, srcBucket, srcObject, 0, srcInfo.Size, srcInfo.Writer, srcInfo.ETag); gerr != nil {
+		getObject := srcSet.getObject
+		if srcInfo.VersionID != "" {
+			getObject = func(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) error {
+				return srcSet.getObjectVersion(ctx, bucket, object, srcInfo.VersionID, startOffset, length, writer, etag)
+			}
+		}
+		if gerr := getObject(ctx, srcBucket, srcObject, 0, srcInfo.Size, srcInfo.Writer, srcInfo.ETag); gerr != nil {
 			if gerr = srcInfo.Writer.Close(); gerr != nil {
 				logger.LogIf(ctx, gerr)
 			}
@@ -700,6 +867,29 @@ func listDirSetsFactory(ctx context.Context, isLeaf isLeafFunc, isLeafDir isLeaf
 	return listDir
 }
 
+// startMergeWalk - starts a tree walk over all sets, entries of the sets
+// are merged lexically sorted inside listDirSetsFactory().
+func (s *xlSets) startMergeWalk(ctx context.Context, bucket, prefix, marker string, recursive bool, endWalkCh chan struct{}) chan treeWalkResult {
+	isLeaf := func(bucket, entry string) bool {
+		entry = strings.TrimSuffix(entry, slashSeparator)
+		// Verify if we are at the leaf, a leaf is where we
+		// see `xl.json` inside a directory.
+		return s.getHashedSet(entry).isObject(bucket, entry)
+	}
+
+	isLeafDir := func(bucket, entry string) bool {
+		return s.getHashedSet(entry).isObjectDir(bucket, entry)
+	}
+
+	var setDisks = make([][]StorageAPI, len(s.sets))
+	for _, set := range s.sets {
+		setDisks = append(setDisks, set.getLoadBalancedDisks())
+	}
+
+	listDir := listDirSetsFactory(ctx, isLeaf, isLeafDir, xlTreeWalkIgnoredErrs, setDisks...)
+	return startTreeWalk(ctx, bucket, prefix, marker, recursive, listDir, isLeaf, isLeafDir, endWalkCh)
+}
+
 // ListObjects - implements listing of objects across sets, each set is independently
 // listed and subsequently merge lexically sorted inside listDirSetsFactory(). Resulting
 // value through the walk channel receives the data properly lexically sorted.
@@ -722,24 +912,7 @@ func (s *xlSets) ListObjects(ctx context.Context, bucket, prefix, marker, delimi
 	walkResultCh, endWalkCh := s.listPool.Release(listParams{bucket, recursive, marker, prefix, false})
 	if walkResultCh == nil {
 		endWalkCh = make(chan struct{})
-		isLeaf := func(bucket, entry string) bool {
-			entry = strings.TrimSuffix(entry, slashSeparator)
-			// Verify if we are at the leaf, a leaf is where we
-			// see `xl.json` inside a directory.
-			return s.getHashedSet(entry).isObject(bucket, entry)
-		}
-
-		isLeafDir := func(bucket, entry string) bool {
-			return s.getHashedSet(entry).isObjectDir(bucket, entry)
-		}
-
-		var setDisks = make([][]StorageAPI, len(s.sets))
-		for _, set := range s.sets {
-			setDisks = append(setDisks, set.getLoadBalancedDisks())
-		}
-
-		listDir := listDirSetsFactory(ctx, isLeaf, isLeafDir, xlTreeWalkIgnoredErrs, setDisks...)
-		walkResultCh = startTreeWalk(ctx, bucket, prefix, marker, recursive, listDir, isLeaf, isLeafDir, endWalkCh)
+		walkResultCh = s.startMergeWalk(ctx, bucket, prefix, marker, recursive, endWalkCh)
 	}
 
 	for i := 0; i < maxKeys; {
@@ -800,12 +973,117 @@ func (s *xlSets) ListObjects(ctx context.Context, bucket, prefix, marker, delimi
 	return result, nil
 }
 
+// ListObjectVersions - lists all versions of objects in the bucket, in
+// lexical order of object names and newest first for each object. Objects
+// are walked across all sets starting after the key marker, remaining
+// versions of the key marker object are listed first.
+func (s *xlSets) ListObjectVersions(ctx context.Context, bucket, prefix, keyMarker, versionIDMarker, delimiter string, maxKeys int) (result ListObjectVersionsInfo, err error) {
+	if err = checkListObjsArgs(ctx, bucket, prefix, keyMarker, delimiter, s); err != nil {
+		return result, err
+	}
+
+	if maxKeys <= 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+
+	var count int
+	// addVersions - adds versions of the object to the result, returns
+	// false once the result is full.
+	addVersions := func(object string, objInfos []ObjectInfo) bool {
+		for _, objInfo := range objInfos {
+			if count == maxKeys {
+				result.IsTruncated = true
+				return false
+			}
+			result.Objects = append(result.Objects, objInfo)
+			result.NextKeyMarker = object
+			result.NextVersionIDMarker = objInfo.VersionID
+			count++
+		}
+		return true
+	}
+
+	// Skip versions of the key marker object up to the version ID marker.
+	if versionIDMarker != "" && !hasSuffix(keyMarker, slashSeparator) {
+		objInfos, err := s.getHashedSet(keyMarker).getObjectVersions(ctx, bucket, keyMarker)
+		if err != nil {
+			return result, toObjectErr(err, bucket, keyMarker)
+		}
+		for i, objInfo := range objInfos {
+			if objInfo.VersionID == versionIDMarker {
+				if !addVersions(keyMarker, objInfos[i+1:]) {
+					return result, nil
+				}
+				break
+			}
+		}
+	}
+
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+
+	recursive := delimiter != slashSeparator
+	walkResultCh := s.startMergeWalk(ctx, bucket, prefix, keyMarker, recursive, endWalkCh)
+	for walkResult := range walkResultCh {
+		// For any walk error return right away.
+		if walkResult.err != nil {
+			return result, toObjectErr(walkResult.err, bucket, prefix)
+		}
+
+		entry := walkResult.entry
+		if hasSuffix(entry, slashSeparator) {
+			if recursive {
+				continue
+			}
+			if count == maxKeys {
+				result.IsTruncated = true
+				return result, nil
+			}
+			result.Prefixes = append(result.Prefixes, entry)
+			result.NextKeyMarker = entry
+			result.NextVersionIDMarker = ""
+			count++
+			continue
+		}
+
+		objInfos, err := s.getHashedSet(entry).getObjectVersions(ctx, bucket, entry)
+		if err != nil {
+			// Ignore quorum error as it might be an entry from an outdated disk.
+			if IsErrIgnored(err, errFileNotFound, errXLReadQuorum) {
+				continue
+			}
+			return result, toObjectErr(err, bucket, prefix)
+		}
+		if !addVersions(entry, objInfos) {
+			return result, nil
+		}
+	}
+
+	result.NextKeyMarker = ""
+	result.NextVersionIDMarker = ""
+	return result, nil
+}
+
 func (s *xlSets) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartsInfo, err error) {
 	// In list multipart uploads we are going to treat input prefix as the object,
 	// this means that we are not supporting directory navigation.
 	return s.getHashedSet(prefix).ListMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
 }
 
+// listBucketMultipartUploads - lists multipart uploads of all objects
+// in the bucket across all sets.
+func (s *xlSets) listBucketMultipartUploads(ctx context.Context, bucket string) ([]MultipartInfo, error) {
+	var uploads []MultipartInfo
+	for _, set := range s.sets {
+		setUploads, err := set.listBucketMultipartUploads(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, setUploads...)
+	}
+	return uploads, nil
+}
+
 // Initiate a new multipart upload on a hashedSet based on object name.
 func (s *xlSets) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error) {
 	return s.getHashedSet(object).NewMultipartUpload(ctx, bucket, object, metadata)
@@ -22,7 +22,11 @@ import (
 	"sync"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
+	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/tagging"
+	"github.com/minio/minio/pkg/versioning"
 )
 
 // list all errors that can be ignore in a bucket operation.
@@ -292,6 +296,81 @@ func (xl xlObjects) DeleteBucketPolicy(ctx context.Context, bucket string) error
 	return removePolicyConfig(ctx, xl, bucket)
 }
 
+// SetBucketConfig persists the new configuration file on the bucket.
+func (xl xlObjects) SetBucketConfig(ctx context.Context, bucket, configFile string, config interface{}) error {
+	return saveBucketConfig(xl, bucket, configFile, config)
+}
+
+// GetBucketConfig will return the configuration file of a bucket.
+func (xl xlObjects) GetBucketConfig(ctx context.Context, bucket, configFile string) (interface{}, error) {
+	return getBucketConfig(xl, bucket, configFile)
+}
+
+// DeleteBucketConfig deletes the configuration file of a bucket.
+func (xl xlObjects) DeleteBucketConfig(ctx context.Context, bucket, configFile string) error {
+	return removeBucketConfig(ctx, xl, bucket, configFile)
+}
+
+// SetBucketLifecycle persists the new lifecycle configuration on the bucket.
+func (xl xlObjects) SetBucketLifecycle(ctx context.Context, bucket string, lifecycle *lifecycle.Lifecycle) error {
+	return saveBucketConfig(xl, bucket, bucketLifecycleConfig, lifecycle)
+}
+
+// GetBucketLifecycle will return the lifecycle configuration of a bucket.
+func (xl xlObjects) GetBucketLifecycle(ctx context.Context, bucket string) (*lifecycle.Lifecycle, error) {
+	return getLifecycleConfig(xl, bucket)
+}
+
+// DeleteBucketLifecycle deletes the lifecycle configuration of a bucket.
+func (xl xlObjects) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	return removeBucketConfig(ctx, xl, bucket, bucketLifecycleConfig)
+}
+
+// SetBucketVersioning persists the new versioning configuration on the bucket.
+func (xl xlObjects) SetBucketVersioning(ctx context.Context, bucket string, v *versioning.Versioning) error {
+	return saveBucketConfig(xl, bucket, bucketVersioningConfig, v)
+}
+
+// GetBucketVersioning will return the versioning configuration of a bucket.
+func (xl xlObjects) GetBucketVersioning(ctx context.Context, bucket string) (*versioning.Versioning, error) {
+	return getVersioningConfig(xl, bucket)
+}
+
+// SetBucketTagging persists the new tagging configuration on the bucket.
+func (xl xlObjects) SetBucketTagging(ctx context.Context, bucket string, t *tagging.Tagging) error {
+	return saveBucketTaggingConfig(xl, bucket, t)
+}
+
+// GetBucketTagging will return the tagging configuration of a bucket.
+func (xl xlObjects) GetBucketTagging(ctx context.Context, bucket string) (*tagging.Tagging, error) {
+	return getBucketTaggingConfig(xl, bucket)
+}
+
+// DeleteBucketTagging deletes the tagging configuration of a bucket.
+func (xl xlObjects) DeleteBucketTagging(ctx context.Context, bucket string) error {
+	return removeBucketTaggingConfig(ctx, xl, bucket)
+}
+
+// SetBucketAccessControlPolicy persists the new ACL on the bucket.
+func (xl xlObjects) SetBucketAccessControlPolicy(ctx context.Context, bucket string, aclPolicy *acl.AccessControlPolicy) error {
+	return saveBucketConfig(xl, bucket, bucketACLConfig, aclPolicy)
+}
+
+// GetBucketAccessControlPolicy will return the ACL of a bucket.
+func (xl xlObjects) GetBucketAccessControlPolicy(ctx context.Context, bucket string) (*acl.AccessControlPolicy, error) {
+	return getBucketAccessControlPolicy(xl, bucket)
+}
+
+// SetObjectAccessControlPolicy persists the new ACL on the object.
+func (xl xlObjects) SetObjectAccessControlPolicy(ctx context.Context, bucket, object string, aclPolicy *acl.AccessControlPolicy) error {
+	return saveObjectACLConfig(xl, bucket, object, aclPolicy)
+}
+
+// GetObjectAccessControlPolicy will return the ACL of an object.
+func (xl xlObjects) GetObjectAccessControlPolicy(ctx context.Context, bucket, object string) (*acl.AccessControlPolicy, error) {
+	return getObjectAccessControlPolicy(xl, bucket, object)
+}
+
 // IsNotificationSupported returns whether bucket notification is applicable for this layer.
 func (xl xlObjects) IsNotificationSupported() bool {
 	return true
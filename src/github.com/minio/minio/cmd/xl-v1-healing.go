@@ -278,7 +278,26 @@ func listAllBuckets(storageDisks []StorageAPI) (buckets map[string]VolInfo,
 	return buckets, bucketsOcc, nil
 }
 
-// Heals an object by re-writing corrupt/missing erasure blocks.
+// xlHealVersion - a version of an object to heal, with its metadata on
+// each disk as if it was the latest version.
+type xlHealVersion struct {
+	// Directory of the parts of the version, relative to the object.
+	dataDir string
+
+	// Metadata of the version on each disk along with read errors.
+	metaArr []xlMetaV1
+	errs    []error
+
+	// Disks having all parts of the version.
+	availableDisks []StorageAPI
+
+	// Erasure info and parts of the version.
+	erasure ErasureInfo
+	parts   []objectPartInfo
+}
+
+// Heals an object by re-writing corrupt/missing erasure blocks of all its
+// versions.
 func healObject(ctx context.Context, storageDisks []StorageAPI, bucket string, object string,
 	quorum int, dryRun bool) (result madmin.HealResultItem, err error) {
 
@@ -310,6 +329,52 @@ func healObject(ctx context.Context, storageDisks []StorageAPI, bucket string, o
 		return result, toObjectErr(aErr, bucket, object)
 	}
 
+	// Latest xlMetaV1 for reference. If a valid metadata is not
+	// present, it is as good as object not found.
+	latestMeta, pErr := pickValidXLMeta(ctx, partsMetadata, modTime)
+
+	// Versions of the object, the latest version first. Parts of
+	// noncurrent versions are healed along with the latest version.
+	versions := []xlHealVersion{{
+		metaArr:        partsMetadata,
+		errs:           errs,
+		availableDisks: append([]StorageAPI(nil), availableDisks...),
+		erasure:        latestMeta.Erasure,
+		parts:          latestMeta.Parts,
+	}}
+	if pErr == nil {
+		for _, version := range latestMeta.Versions {
+			metaArr, vErrs := noncurrentVersionXLMeta(partsMetadata, errs, version.VersionID)
+			vDisks, vDataErrs, vErr := disksWithAllParts(ctx, latestDisks, metaArr, vErrs, bucket, pathJoin(object, version.VersionID))
+			if vErr != nil {
+				return result, toObjectErr(vErr, bucket, object)
+			}
+
+			for i := range vDisks {
+				// A disk without the version has none of its parts.
+				if vErrs[i] != nil {
+					vDisks[i] = nil
+					vDataErrs[i] = vErrs[i]
+				}
+
+				// A disk missing any version needs healing.
+				if availableDisks[i] != nil && vDisks[i] == nil {
+					availableDisks[i] = nil
+					dataErrs[i] = vDataErrs[i]
+				}
+			}
+
+			versions = append(versions, xlHealVersion{
+				dataDir:        version.VersionID,
+				metaArr:        metaArr,
+				errs:           vErrs,
+				availableDisks: vDisks,
+				erasure:        version.Erasure,
+				parts:          version.Parts,
+			})
+		}
+	}
+
 	// Initialize heal result object
 	result = madmin.HealResultItem{
 		Type:      madmin.HealItemObject,
@@ -322,18 +387,15 @@ func healObject(ctx context.Context, storageDisks []StorageAPI, bucket string, o
 		ObjectSize: -1,
 	}
 
-	// Loop to find number of disks with valid data, per-drive
-	// data state and a list of outdated disks on which data needs
-	// to be healed.
+	// Loop to find per-drive data state and a list of outdated
+	// disks on which data needs to be healed.
 	outDatedDisks := make([]StorageAPI, len(storageDisks))
-	numAvailableDisks := 0
 	disksToHealCount := 0
 	for i, v := range availableDisks {
 		driveState := ""
 		switch {
 		case v != nil:
 			driveState = madmin.DriveStateOk
-			numAvailableDisks++
 			// If data is sane on any one disk, we can
 			// extract the correct object size.
 			result.ObjectSize = partsMetadata[i].Stat.Size
@@ -387,9 +449,15 @@ func healObject(ctx context.Context, storageDisks []StorageAPI, bucket string, o
 	}
 
 	// If less than read quorum number of disks have all the parts
-	// of the data, we can't reconstruct the erasure-coded data.
-	if numAvailableDisks < quorum {
-		return result, toObjectErr(errXLReadQuorum, bucket, object)
+	// of any version, we can't reconstruct the erasure-coded data.
+	for i, version := range versions {
+		versionQuorum := quorum
+		if i > 0 {
+			versionQuorum = version.erasure.DataBlocks
+		}
+		if diskCount(version.availableDisks) < versionQuorum {
+			return result, toObjectErr(errXLReadQuorum, bucket, object)
+		}
 	}
 
 	if disksToHealCount == 0 {
@@ -403,9 +471,6 @@ func healObject(ctx context.Context, storageDisks []StorageAPI, bucket string, o
 		return result, nil
 	}
 
-	// Latest xlMetaV1 for reference. If a valid metadata is not
-	// present, it is as good as object not found.
-	latestMeta, pErr := pickValidXLMeta(ctx, partsMetadata, modTime)
 	if pErr != nil {
 		return result, toObjectErr(pErr, bucket, object)
 	}
@@ -413,9 +478,9 @@ func healObject(ctx context.Context, storageDisks []StorageAPI, bucket string, o
 	// Clear data files of the object on outdated disks
 	for _, disk := range outDatedDisks {
 		// Before healing outdated disks, we need to remove
-		// xl.json and part files from "bucket/object/" so
-		// that rename(minioMetaBucket, "tmp/tmpuuid/",
-		// "bucket", "object/") succeeds.
+		// xl.json, part files and version directories from
+		// "bucket/object/" so that rename(minioMetaBucket,
+		// "tmp/tmpuuid/", "bucket", "object/") succeeds.
 		if disk == nil {
 			// Not an outdated disk.
 			continue
@@ -425,84 +490,103 @@ func healObject(ctx context.Context, storageDisks []StorageAPI, bucket string, o
 		files, derr := disk.ListDir(bucket, object, -1)
 		if derr == nil {
 			for _, entry := range files {
+				if hasSuffix(entry, slashSeparator) {
+					_ = cleanupDir(ctx, disk, bucket, pathJoin(object, entry))
+					continue
+				}
 				_ = disk.DeleteFile(bucket,
 					pathJoin(object, entry))
 			}
 		}
 	}
 
-	// Reorder so that we have data disks first and parity disks next.
-	latestDisks = shuffleDisks(latestDisks, latestMeta.Erasure.Distribution)
-	outDatedDisks = shuffleDisks(outDatedDisks, latestMeta.Erasure.Distribution)
-	partsMetadata = shufflePartsMetadata(partsMetadata, latestMeta.Erasure.Distribution)
+	// Reorder so that we have data disks first and parity disks
+	// next. All versions share the distribution, which only
+	// depends on the object name.
+	distribution := latestMeta.Erasure.Distribution
+	outDatedDisks = shuffleDisks(outDatedDisks, distribution)
+	partsMetadata = shufflePartsMetadata(partsMetadata, distribution)
 
 	// We write at temporary location and then rename to final location.
 	tmpID := mustGetUUID()
 
-	// Checksum of the part files. checkSumInfos[index] will
-	// contain checksums of all the part files in the
+	// Checksum of the part files of each version.
+	// checksumInfos[version][index] will contain checksums of
+	// all the part files of the version in the
 	// outDatedDisks[index]
-	checksumInfos := make([][]ChecksumInfo, len(outDatedDisks))
-
-	// Heal each part. erasureHealFile() will write the healed
-	// part to .minio/tmp/uuid/ which needs to be renamed later to
-	// the final location.
-	storage, err := NewErasureStorage(ctx, latestDisks, latestMeta.Erasure.DataBlocks,
-		latestMeta.Erasure.ParityBlocks, latestMeta.Erasure.BlockSize)
-	if err != nil {
-		return result, toObjectErr(err, bucket, object)
-	}
-	checksums := make([][]byte, len(latestDisks))
-	for partIndex := 0; partIndex < len(latestMeta.Parts); partIndex++ {
-		partName := latestMeta.Parts[partIndex].Name
-		partSize := latestMeta.Parts[partIndex].Size
-		erasure := latestMeta.Erasure
-		var algorithm BitrotAlgorithm
-		for i, disk := range storage.disks {
-			if disk != OfflineDisk {
-				info := partsMetadata[i].Erasure.GetChecksumInfo(partName)
-				algorithm = info.Algorithm
-				checksums[i] = info.Hash
-			}
-		}
-		// Heal the part file.
-		file, hErr := storage.HealFile(ctx, outDatedDisks, bucket, pathJoin(object, partName),
-			erasure.BlockSize, minioMetaTmpBucket, pathJoin(tmpID, partName), partSize,
-			algorithm, checksums)
-		if hErr != nil {
-			return result, toObjectErr(hErr, bucket, object)
+	checksumInfos := make([][][]ChecksumInfo, len(versions))
+
+	// Heal each part of each version. erasureHealFile() will
+	// write the healed part to .minio/tmp/uuid/ which needs to be
+	// renamed later to the final location.
+	for v, version := range versions {
+		checksumInfos[v] = make([][]ChecksumInfo, len(outDatedDisks))
+		metaArr := shufflePartsMetadata(version.metaArr, distribution)
+		erasure := version.erasure
+		storage, err := NewErasureStorage(ctx, shuffleDisks(version.availableDisks, distribution),
+			erasure.DataBlocks, erasure.ParityBlocks, erasure.BlockSize)
+		if err != nil {
+			return result, toObjectErr(err, bucket, object)
 		}
-		// outDatedDisks that had write errors should not be
-		// written to for remaining parts, so we nil it out.
-		for i, disk := range outDatedDisks {
-			if disk == nil {
-				continue
+		checksums := make([][]byte, len(storage.disks))
+		for partIndex := 0; partIndex < len(version.parts); partIndex++ {
+			partName := version.parts[partIndex].Name
+			partSize := version.parts[partIndex].Size
+			var algorithm BitrotAlgorithm
+			for i, disk := range storage.disks {
+				if disk != OfflineDisk {
+					info := metaArr[i].Erasure.GetChecksumInfo(partName)
+					algorithm = info.Algorithm
+					checksums[i] = info.Hash
+				}
 			}
-			// A non-nil stale disk which did not receive
-			// a healed part checksum had a write error.
-			if file.Checksums[i] == nil {
-				outDatedDisks[i] = nil
-				disksToHealCount--
-				continue
+			// Heal the part file.
+			partPath := pathJoin(version.dataDir, partName)
+			file, hErr := storage.HealFile(ctx, outDatedDisks, bucket, pathJoin(object, partPath),
+				erasure.BlockSize, minioMetaTmpBucket, pathJoin(tmpID, partPath), partSize,
+				algorithm, checksums)
+			if hErr != nil {
+				return result, toObjectErr(hErr, bucket, object)
+			}
+			// outDatedDisks that had write errors should not be
+			// written to for remaining parts, so we nil it out.
+			for i, disk := range outDatedDisks {
+				if disk == nil {
+					continue
+				}
+				// A non-nil stale disk which did not receive
+				// a healed part checksum had a write error.
+				if file.Checksums[i] == nil {
+					outDatedDisks[i] = nil
+					disksToHealCount--
+					continue
+				}
+				// append part checksums
+				checksumInfos[v][i] = append(checksumInfos[v][i],
+					ChecksumInfo{partName, file.Algorithm, file.Checksums[i]})
 			}
-			// append part checksums
-			checksumInfos[i] = append(checksumInfos[i],
-				ChecksumInfo{partName, file.Algorithm, file.Checksums[i]})
-		}
 
-		// If all disks are having errors, we give up.
-		if disksToHealCount == 0 {
-			return result, fmt.Errorf("all disks without up-to-date data had write errors")
+			// If all disks are having errors, we give up.
+			if disksToHealCount == 0 {
+				return result, fmt.Errorf("all disks without up-to-date data had write errors")
+			}
 		}
 	}
 
-	// xl.json should be written to all the healed disks.
+	// xl.json should be written to all the healed disks, with
+	// erasure info of each version for the disk.
 	for index, disk := range outDatedDisks {
 		if disk == nil {
 			continue
 		}
 		partsMetadata[index] = latestMeta
-		partsMetadata[index].Erasure.Checksums = checksumInfos[index]
+		partsMetadata[index].Erasure.Checksums = checksumInfos[0][index]
+		partsMetadata[index].Versions = make([]xlObjectVersion, len(latestMeta.Versions))
+		for i, version := range latestMeta.Versions {
+			version.Erasure.Index = index + 1
+			version.Erasure.Checksums = checksumInfos[i+1][index]
+			partsMetadata[index].Versions[i] = version
+		}
 	}
 
 	// Generate and write `xl.json` generated from other disks.
@@ -22,6 +22,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/versioning"
 )
 
 // Tests undoes and validates if the undoing completes successfully.
@@ -148,3 +151,105 @@ func TestHealObjectXL(t *testing.T) {
 		t.Errorf("Expected %v but received %v", InsufficientWriteQuorum{}, err)
 	}
 }
+
+// Tests healing of all versions of a versioned object.
+func TestHealVersionedObjectXL(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	nDisks := 16
+	fsDirs, err := getRandomDisks(nDisks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	obj, _, err := initObjectLayer(mustGetNewEndpointList(fsDirs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	xl := obj.(*xlObjects)
+
+	ctx := context.Background()
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatalf("Failed to make a bucket - %v", err)
+	}
+
+	// Write the "null" version and two versions, which leaves
+	// two noncurrent versions.
+	contents := map[string][]byte{}
+	put := func(data []byte) {
+		objInfo, perr := obj.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil)
+		if perr != nil {
+			t.Fatalf("Failed to put object - %v", perr)
+		}
+		versionID := objInfo.VersionID
+		if versionID == "" {
+			versionID = nullVersionID
+		}
+		contents[versionID] = data
+	}
+	put(bytes.Repeat([]byte("a"), 1024*1024))
+	globalBucketMetadataSys.Set(bucket, bucketVersioningConfig, &versioning.Versioning{Status: versioning.Enabled})
+	defer globalBucketMetadataSys.Remove(bucket)
+	put(bytes.Repeat([]byte("b"), 2*1024*1024))
+	put(bytes.Repeat([]byte("c"), 3*1024*1024))
+
+	checkDisk := func(disk StorageAPI) {
+		xlMeta, rerr := readXLMeta(ctx, disk, bucket, object)
+		if rerr != nil {
+			t.Fatalf("Failed to read xl.json - %v", rerr)
+		}
+		if len(xlMeta.Versions) != 2 {
+			t.Fatalf("Expected 2 noncurrent versions, got %d", len(xlMeta.Versions))
+		}
+		for _, version := range xlMeta.Versions {
+			if version.Erasure.Index != xlMeta.Erasure.Index {
+				t.Fatalf("Expected erasure index %d of version %s, got %d", xlMeta.Erasure.Index, version.VersionID, version.Erasure.Index)
+			}
+			if _, serr := disk.StatFile(bucket, pathJoin(object, version.VersionID, "part.1")); serr != nil {
+				t.Fatalf("Expected part of version %s to be present but stat failed - %v", version.VersionID, serr)
+			}
+		}
+	}
+
+	// Wipe the object from the first disk, and a noncurrent
+	// version from the second disk.
+	firstDisk, secondDisk := xl.storageDisks[0], xl.storageDisks[1]
+	if err = cleanupDir(ctx, firstDisk, bucket, object); err != nil {
+		t.Fatalf("Failed to delete the object - %v", err)
+	}
+	if err = cleanupDir(ctx, secondDisk, bucket, pathJoin(object, nullVersionID)); err != nil {
+		t.Fatalf("Failed to delete a version - %v", err)
+	}
+
+	result, err := obj.HealObject(ctx, bucket, object, false)
+	if err != nil {
+		t.Fatalf("Failed to heal object - %v", err)
+	}
+	for i, drive := range result.After.Drives {
+		if drive.State != madmin.DriveStateOk {
+			t.Fatalf("Expected drive %d to be healed, got state %s", i, drive.State)
+		}
+	}
+	checkDisk(firstDisk)
+	checkDisk(secondDisk)
+
+	// Every version must be readable using the healed disks.
+	for i := 2; i <= len(xl.storageDisks)/2; i++ {
+		xl.storageDisks[i] = nil
+	}
+	for versionID, data := range contents {
+		var buf bytes.Buffer
+		if err = obj.GetObjectVersion(ctx, bucket, object, versionID, 0, int64(len(data)), &buf, ""); err != nil {
+			t.Fatalf("Failed to read version %s - %v", versionID, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("Unexpected content of version %s", versionID)
+		}
+	}
+}
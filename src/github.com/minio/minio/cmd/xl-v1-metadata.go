@@ -221,6 +221,11 @@ type xlMetaV1 struct {
 	Version string   `json:"version"` // Version of the current `xl.json`.
 	Format  string   `json:"format"`  // Format of the current `xl.json`.
 	Stat    statInfo `json:"stat"`    // Stat of the current object `xl.json`.
+	// Version ID of the object, empty if the object was written
+	// without bucket versioning.
+	VersionID string `json:"versionId,omitempty"`
+	// Indicates whether this version of the object is a delete marker.
+	DeleteMarker bool `json:"deleteMarker,omitempty"`
 	// Erasure coded info for the current object `xl.json`.
 	Erasure ErasureInfo `json:"erasure"`
 	// Minio release tag for current object `xl.json`.
@@ -231,6 +236,20 @@ type xlMetaV1 struct {
 	Meta map[string]string `json:"meta,omitempty"`
 	// Captures all the individual object `xl.json`.
 	Parts []objectPartInfo `json:"parts,omitempty"`
+	// Noncurrent versions of the object, newest first.
+	Versions []xlObjectVersion `json:"versions,omitempty"`
+}
+
+// A xlObjectVersion represents a noncurrent version of the object
+// recorded in `xl.json`, its parts are stored under the version ID
+// inside the object directory.
+type xlObjectVersion struct {
+	VersionID    string            `json:"versionId"`
+	DeleteMarker bool              `json:"deleteMarker,omitempty"`
+	Stat         statInfo          `json:"stat"`
+	Erasure      ErasureInfo       `json:"erasure"`
+	Meta         map[string]string `json:"meta,omitempty"`
+	Parts        []objectPartInfo  `json:"parts,omitempty"`
 }
 
 // XL metadata constants.
@@ -293,6 +312,8 @@ func (m xlMetaV1) ToObjectInfo(bucket, object string) ObjectInfo {
 		ModTime:         m.Stat.ModTime,
 		ContentType:     m.Meta["content-type"],
 		ContentEncoding: m.Meta["content-encoding"],
+		VersionID:       m.VersionID,
+		DeleteMarker:    m.DeleteMarker,
 	}
 
 	// Extract etag from metadata.
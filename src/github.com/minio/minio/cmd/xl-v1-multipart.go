@@ -44,6 +44,73 @@ func (xl xlObjects) isUploadIDExists(ctx context.Context, bucket, object, upload
 	return xl.isObject(minioMetaMultipartBucket, xl.getUploadIDDir(bucket, object, uploadID))
 }
 
+// listMultipartDir - lists entries of given directory in the multipart
+// bucket which are present on at least read quorum of disks, so that a
+// single stale or healing disk neither hides nor resurrects uploads.
+func (xl xlObjects) listMultipartDir(ctx context.Context, dir string) ([]string, error) {
+	disks := xl.getDisks()
+	errs := make([]error, len(disks))
+	entriesCount := make(map[string]int)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for index, disk := range disks {
+		if disk == nil {
+			errs[index] = errDiskNotFound
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			entries, err := disk.ListDir(minioMetaMultipartBucket, dir, -1)
+			if err != nil {
+				// Directory is not present on this disk.
+				if err != errFileNotFound {
+					errs[index] = err
+				}
+				return
+			}
+			mutex.Lock()
+			for _, entry := range entries {
+				entriesCount[strings.TrimSuffix(entry, slashSeparator)]++
+			}
+			mutex.Unlock()
+		}(index, disk)
+	}
+	wg.Wait()
+
+	readQuorum := len(disks) / 2
+	if err := reduceReadQuorumErrs(ctx, errs, nil, readQuorum); err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for entry, count := range entriesCount {
+		if count >= readQuorum {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Strings(entries)
+	return entries, nil
+}
+
+// readUploadXLMeta - reads xl.json of given upload with read quorum.
+func (xl xlObjects) readUploadXLMeta(ctx context.Context, uploadIDPath string) (xlMetaV1, error) {
+	metaArr, errs := readAllXLMetadata(ctx, xl.getDisks(), minioMetaMultipartBucket, uploadIDPath)
+
+	readQuorum, _, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return xlMetaV1{}, err
+	}
+
+	if err = reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, readQuorum); err != nil {
+		return xlMetaV1{}, err
+	}
+
+	_, modTime := listOnlineDisks(xl.getDisks(), metaArr, errs)
+	return pickValidXLMeta(ctx, metaArr, modTime)
+}
+
 // Removes part given by partName belonging to a mulitpart upload from minioMetaBucket
 func (xl xlObjects) removeObjectPart(bucket, object, uploadID, partName string) {
 	curpartPath := path.Join(bucket, object, uploadID, partName)
@@ -152,29 +219,16 @@ func (xl xlObjects) ListMultipartUploads(ctx context.Context, bucket, object, ke
 	result.Prefix = object
 	result.Delimiter = delimiter
 
-	for _, disk := range xl.getLoadBalancedDisks() {
-		if disk == nil {
-			continue
-		}
-		uploadIDs, err := disk.ListDir(minioMetaMultipartBucket, xl.getMultipartSHADir(bucket, object), -1)
-		if err != nil {
-			if err == errFileNotFound {
-				return result, nil
-			}
-			logger.LogIf(ctx, err)
-			return result, err
-		}
-		for i := range uploadIDs {
-			uploadIDs[i] = strings.TrimSuffix(uploadIDs[i], slashSeparator)
-		}
-		sort.Strings(uploadIDs)
-		for _, uploadID := range uploadIDs {
-			if len(result.Uploads) == maxUploads {
-				break
-			}
-			result.Uploads = append(result.Uploads, MultipartInfo{Object: object, UploadID: uploadID})
+	uploadIDs, err := xl.listMultipartDir(ctx, xl.getMultipartSHADir(bucket, object))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return result, err
+	}
+	for _, uploadID := range uploadIDs {
+		if len(result.Uploads) == maxUploads {
+			break
 		}
-		break
+		result.Uploads = append(result.Uploads, MultipartInfo{Object: object, UploadID: uploadID})
 	}
 
 	return result, nil
@@ -190,6 +244,8 @@ func (xl xlObjects) ListMultipartUploads(ctx context.Context, bucket, object, ke
 // operation(s) on the object.
 func (xl xlObjects) newMultipartUpload(ctx context.Context, bucket string, object string, meta map[string]string) (string, error) {
 
+	meta = newMultipartMetadata(bucket, object, meta)
+
 	dataBlocks, parityBlocks := getRedundancyCount(meta[amzStorageClass], len(xl.getDisks()))
 
 	xlMeta := newXLMetaV1(object, dataBlocks, parityBlocks)
@@ -433,10 +489,17 @@ func (xl xlObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID
 	// Add the current part.
 	xlMeta.AddObjectPart(partID, partSuffix, md5hex, file.Size)
 
+	// Uploads initiated before their object was recorded are recorded
+	// now, so that lifecycle rules apply to them.
+	if _, ok := xlMeta.Meta[multipartObjectMetaKey]; !ok {
+		xlMeta.Meta = newMultipartMetadata(bucket, object, xlMeta.Meta)
+	}
+
 	for i, disk := range onlineDisks {
 		if disk == OfflineDisk {
 			continue
 		}
+		partsMetadata[i].Meta = xlMeta.Meta
 		partsMetadata[i].Parts = xlMeta.Parts
 		partsMetadata[i].Erasure.AddChecksumInfo(ChecksumInfo{partSuffix, file.Algorithm, file.Checksums[i]})
 	}
@@ -687,8 +750,10 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	// Save the final object size and modtime.
 	xlMeta.Stat.Size = objectSize
 	xlMeta.Stat.ModTime = UTCNow()
+	xlMeta.VersionID = newVersionID(bucket)
 
 	// Save successfully calculated md5sum.
+	removeMultipartMetadata(xlMeta.Meta)
 	xlMeta.Meta["etag"] = s3MD5
 
 	tempUploadIDPath := uploadID
@@ -699,6 +764,7 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 		partsMetadata[index].Stat = xlMeta.Stat
 		partsMetadata[index].Meta = xlMeta.Meta
 		partsMetadata[index].Parts = xlMeta.Parts
+		partsMetadata[index].VersionID = xlMeta.VersionID
 	}
 
 	// Write unique `xl.json` for each disk.
@@ -712,7 +778,8 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 		return oi, toObjectErr(rErr, minioMetaMultipartBucket, uploadIDPath)
 	}
 
-	if xl.isObject(bucket, object) {
+	// Objects in versioned bucket are added as new version.
+	if xlMeta.VersionID == "" && xl.isObject(bucket, object) {
 		// Rename if an object already exists to temporary location.
 		newUniqueID := mustGetUUID()
 
@@ -749,8 +816,15 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 		}
 	}
 
-	// Rename the multipart object to final location.
-	if _, err = renameObject(ctx, onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, writeQuorum); err != nil {
+	if xlMeta.VersionID != "" {
+		// Make the multipart object the latest version.
+		if err = xl.putVersion(ctx, minioMetaMultipartBucket, uploadIDPath, bucket, object, xlMeta.VersionID, writeQuorum); err != nil {
+			return oi, toObjectErr(err, bucket, object)
+		}
+		// Remove what is left of the upload.
+		xl.deleteObject(ctx, minioMetaMultipartBucket, uploadIDPath)
+	} else if _, err = renameObject(ctx, onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, writeQuorum); err != nil {
+		// Rename the multipart object to final location.
 		return oi, toObjectErr(err, bucket, object)
 	}
 
@@ -834,6 +908,39 @@ func (xl xlObjects) AbortMultipartUpload(ctx context.Context, bucket, object, up
 	return nil
 }
 
+// listBucketMultipartUploads - lists multipart uploads of all objects
+// in the bucket, unlike ListMultipartUploads which requires the object
+// name. Uploads initiated before their object was recorded are skipped
+// until their next part is uploaded, stale ones are removed by
+// cleanupStaleMultipartUploads.
+func (xl xlObjects) listBucketMultipartUploads(ctx context.Context, bucket string) ([]MultipartInfo, error) {
+	shaDirs, err := xl.listMultipartDir(ctx, "")
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return nil, err
+	}
+
+	var uploads []MultipartInfo
+	for _, shaDir := range shaDirs {
+		uploadIDs, err := xl.listMultipartDir(ctx, shaDir)
+		if err != nil {
+			continue
+		}
+		for _, uploadID := range uploadIDs {
+			xlMeta, err := xl.readUploadXLMeta(ctx, pathJoin(shaDir, uploadID))
+			if err != nil {
+				continue
+			}
+			uploadBucket, upload, ok := getMultipartUploadInfo(xlMeta.Meta, uploadID)
+			if ok && uploadBucket == bucket {
+				uploads = append(uploads, upload)
+			}
+		}
+	}
+
+	return uploads, nil
+}
+
 // Clean-up the old multipart uploads. Should be run in a Go routine.
 func (xl xlObjects) cleanupStaleMultipartUploads(ctx context.Context, cleanupInterval, expiry time.Duration, doneCh chan struct{}) {
 	ticker := time.NewTicker(cleanupInterval)
@@ -844,40 +951,70 @@ func (xl xlObjects) cleanupStaleMultipartUploads(ctx context.Context, cleanupInt
 		case <-doneCh:
 			return
 		case <-ticker.C:
-			var disk StorageAPI
-			for _, d := range xl.getLoadBalancedDisks() {
-				if d != nil {
-					disk = d
-					break
-				}
-			}
-			if disk == nil {
-				continue
+			xl.cleanupStaleMultipartUploadsWithQuorum(ctx, expiry)
+		}
+	}
+}
+
+// lastUploadActivity - returns the latest modification time of xl.json
+// of given upload, read from at least read quorum of disks.
+func (xl xlObjects) lastUploadActivity(ctx context.Context, uploadIDPath string) (time.Time, error) {
+	disks := xl.getDisks()
+	errs := make([]error, len(disks))
+	modTimes := make([]time.Time, len(disks))
+
+	var wg sync.WaitGroup
+	for index, disk := range disks {
+		if disk == nil {
+			errs[index] = errDiskNotFound
+			continue
+		}
+		wg.Add(1)
+		go func(index int, disk StorageAPI) {
+			defer wg.Done()
+			fi, err := disk.StatFile(minioMetaMultipartBucket, pathJoin(uploadIDPath, xlMetaJSONFile))
+			if err != nil {
+				errs[index] = err
+				return
 			}
-			xl.cleanupStaleMultipartUploadsOnDisk(ctx, disk, expiry)
+			modTimes[index] = fi.ModTime
+		}(index, disk)
+	}
+	wg.Wait()
+
+	if err := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, len(disks)/2); err != nil {
+		return timeSentinel, err
+	}
+
+	lastActivity := timeSentinel
+	for _, modTime := range modTimes {
+		if modTime.After(lastActivity) {
+			lastActivity = modTime
 		}
 	}
+	return lastActivity, nil
 }
 
-// Remove the old multipart uploads on the given disk.
-func (xl xlObjects) cleanupStaleMultipartUploadsOnDisk(ctx context.Context, disk StorageAPI, expiry time.Duration) {
+// Remove the old multipart uploads, including the ones initiated before
+// their object was recorded in xl.json.
+func (xl xlObjects) cleanupStaleMultipartUploadsWithQuorum(ctx context.Context, expiry time.Duration) {
 	now := time.Now()
-	shaDirs, err := disk.ListDir(minioMetaMultipartBucket, "", -1)
+	shaDirs, err := xl.listMultipartDir(ctx, "")
 	if err != nil {
 		return
 	}
 	for _, shaDir := range shaDirs {
-		uploadIDDirs, err := disk.ListDir(minioMetaMultipartBucket, shaDir, -1)
+		uploadIDs, err := xl.listMultipartDir(ctx, shaDir)
 		if err != nil {
 			continue
 		}
-		for _, uploadIDDir := range uploadIDDirs {
-			uploadIDPath := pathJoin(shaDir, uploadIDDir)
-			fi, err := disk.StatFile(minioMetaMultipartBucket, pathJoin(uploadIDPath, xlMetaJSONFile))
+		for _, uploadID := range uploadIDs {
+			uploadIDPath := pathJoin(shaDir, uploadID)
+			lastActivity, err := xl.lastUploadActivity(ctx, uploadIDPath)
 			if err != nil {
 				continue
 			}
-			if now.Sub(fi.ModTime) > expiry {
+			if now.Sub(lastActivity) > expiry {
 				// Quorum value will need to be figured out using readAllXLMetadata() and objectQuorumFromMeta()
 				// But we can avoid these calls as we do not care if xl.cleanupUploadedParts() meets quorum
 				// when it removes files. We igore the error message from xl.cleanupUploadedParts() as we can't
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"testing"
@@ -71,3 +72,85 @@ func TestXLCleanupStaleMultipartUploads(t *testing.T) {
 		}
 	}
 }
+
+// Tests listing of uploads initiated before their object was recorded
+// and of uploads present only on a single disk.
+func TestXLListBucketMultipartUploadsQuorum(t *testing.T) {
+	root, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer os.RemoveAll(root)
+
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	xl := obj.(*xlObjects)
+	ctx := context.Background()
+
+	bucketName := "bucket"
+	objectName := "object"
+
+	if err = obj.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := obj.NewMultipartUpload(ctx, bucketName, objectName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Remove the recorded object to simulate an upload
+	// initiated before the upgrade.
+	uploadIDPath := xl.getUploadIDDir(bucketName, objectName, uploadID)
+	var xlMeta xlMetaV1
+	for _, disk := range xl.getDisks() {
+		if xlMeta, err = readXLMeta(ctx, disk, minioMetaMultipartBucket, uploadIDPath); err != nil {
+			t.Fatal(err)
+		}
+		removeMultipartMetadata(xlMeta.Meta)
+		if err = disk.DeleteFile(minioMetaMultipartBucket, pathJoin(uploadIDPath, xlMetaJSONFile)); err != nil {
+			t.Fatal(err)
+		}
+		if err = writeXLMetadata(ctx, disk, minioMetaMultipartBucket, uploadIDPath, xlMeta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Upload present only on a single disk must not be listed.
+	strayPath := xl.getUploadIDDir(bucketName, objectName, mustGetUUID())
+	if err = writeXLMetadata(ctx, xl.getDisks()[0], minioMetaMultipartBucket, strayPath, xlMeta); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := obj.ListMultipartUploads(ctx, bucketName, objectName, "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Uploads) != 1 || result.Uploads[0].UploadID != uploadID {
+		t.Fatalf("expected only upload %s, got %v", uploadID, result.Uploads)
+	}
+
+	uploads, err := xl.listBucketMultipartUploads(ctx, bucketName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 0 {
+		t.Fatalf("expected no uploads before a part is uploaded, got %v", uploads)
+	}
+
+	data := []byte("part")
+	if _, err = obj.PutObjectPart(ctx, bucketName, objectName, uploadID, 1, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	uploads, err = xl.listBucketMultipartUploads(ctx, bucketName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].UploadID != uploadID || uploads[0].Object != objectName {
+		t.Fatalf("expected upload %s of %s, got %v", uploadID, objectName, uploads)
+	}
+}
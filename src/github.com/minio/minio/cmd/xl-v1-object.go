@@ -107,9 +107,6 @@ func (xl xlObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBuc
 		return oi, toObjectErr(err, srcBucket, srcObject)
 	}
 
-	// Length of the file to read.
-	length := xlMeta.Stat.Size
-
 	// Check if this request is only metadata update.
 	if cpSrcDstSame {
 		// Update `xl.json` content on each disks.
@@ -133,32 +130,27 @@ func (xl xlObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBuc
 		return xlMeta.ToObjectInfo(srcBucket, srcObject), nil
 	}
 
-	// Initialize pipe.
-	pipeReader, pipeWriter := io.Pipe()
-
+	// The source object is read through srcInfo.Writer, which may
+	// transform the content (e.g. decrypt), and the destination
+	// object is written from srcInfo.Reader.
 	go func() {
-		var startOffset int64 // Read the whole file.
-		if gerr := xl.getObject(ctx, srcBucket, srcObject, startOffset, length, pipeWriter, srcInfo.ETag); gerr != nil {
-			pipeWriter.CloseWithError(toObjectErr(gerr, srcBucket, srcObject))
+		if gerr := xl.getObject(ctx, srcBucket, srcObject, 0, srcInfo.Size, srcInfo.Writer, srcInfo.ETag); gerr != nil {
+			if gerr = srcInfo.Writer.Close(); gerr != nil {
+				logger.LogIf(ctx, gerr)
+			}
 			return
 		}
-		pipeWriter.Close() // Close writer explicitly signalling we wrote all data.
+		// Close writer explicitly signalling we wrote all data.
+		if gerr := srcInfo.Writer.Close(); gerr != nil {
+			logger.LogIf(ctx, gerr)
+		}
 	}()
 
-	hashReader, err := hash.NewReader(pipeReader, length, "", "")
-	if err != nil {
-		logger.LogIf(ctx, err)
-		return oi, toObjectErr(err, dstBucket, dstObject)
-	}
-
-	objInfo, err := xl.putObject(ctx, dstBucket, dstObject, hashReader, srcInfo.UserDefined)
+	objInfo, err := xl.putObject(ctx, dstBucket, dstObject, srcInfo.Reader, srcInfo.UserDefined)
 	if err != nil {
 		return oi, toObjectErr(err, dstBucket, dstObject)
 	}
 
-	// Explicitly close the reader.
-	pipeReader.Close()
-
 	return objInfo, nil
 }
 
@@ -207,6 +199,12 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(ctx, xl.getDisks(), bucket, object)
 
+	return xl.getObjectData(ctx, bucket, object, object, metaArr, errs, startOffset, length, writer)
+}
+
+// getObjectData - reads the object data stored under dataDir, as
+// described by metadata of all disks.
+func (xl xlObjects) getObjectData(ctx context.Context, bucket, object, dataDir string, metaArr []xlMetaV1, errs []error, startOffset int64, length int64, writer io.Writer) error {
 	// get Quorum for this object
 	readQuorum, _, err := objectQuorumFromMeta(xl, metaArr, errs)
 	if err != nil {
@@ -226,6 +224,11 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 		return err
 	}
 
+	// Object hidden by a delete marker is not found.
+	if xlMeta.DeleteMarker {
+		return toObjectErr(errFileNotFound, bucket, object)
+	}
+
 	// Reorder online disks based on erasure distribution order.
 	onlineDisks = shuffleDisks(onlineDisks, xlMeta.Erasure.Distribution)
 
@@ -292,7 +295,7 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 			checksums[index] = checksumInfo.Hash
 		}
 
-		file, err := storage.ReadFile(ctx, writer, bucket, pathJoin(object, partName), partOffset, readSize, partSize, checksums, algorithm, xlMeta.Erasure.BlockSize)
+		file, err := storage.ReadFile(ctx, writer, bucket, pathJoin(dataDir, partName), partOffset, readSize, partSize, checksums, algorithm, xlMeta.Erasure.BlockSize)
 		if err != nil {
 			return toObjectErr(err, bucket, object)
 		}
@@ -403,6 +406,11 @@ func (xl xlObjects) getObjectInfo(ctx context.Context, bucket, object string) (o
 		return objInfo, err
 	}
 
+	// Object hidden by a delete marker is not found.
+	if xlMeta.DeleteMarker {
+		return objInfo, errFileNotFound
+	}
+
 	return xlMeta.ToObjectInfo(bucket, object), nil
 }
 
@@ -684,7 +692,10 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 		}
 	}
 
-	if xl.isObject(bucket, object) {
+	// Objects in versioned bucket are added as new version.
+	versionID := newVersionID(bucket)
+
+	if versionID == "" && xl.isObject(bucket, object) {
 		// Rename if an object already exists to temporary location.
 		newUniqueID := mustGetUUID()
 
@@ -706,6 +717,7 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 		partsMetadata[index].Meta = metadata
 		partsMetadata[index].Stat.Size = sizeWritten
 		partsMetadata[index].Stat.ModTime = modTime
+		partsMetadata[index].VersionID = versionID
 	}
 
 	// Write unique `xl.json` for each disk.
@@ -721,8 +733,13 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 		}
 	}
 
-	// Rename the successfully written temporary object to final location.
-	if _, err = renameObject(ctx, onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, writeQuorum); err != nil {
+	if versionID != "" {
+		// Make the successfully written temporary object the latest version.
+		if err = xl.putVersion(ctx, minioMetaTmpBucket, tempObj, bucket, object, versionID, writeQuorum); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+	} else if _, err = renameObject(ctx, onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, writeQuorum); err != nil {
+		// Rename the successfully written temporary object to final location.
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
@@ -740,6 +757,7 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 		ContentType:     xlMeta.Meta["content-type"],
 		ContentEncoding: xlMeta.Meta["content-encoding"],
 		UserDefined:     xlMeta.Meta,
+		VersionID:       xlMeta.VersionID,
 	}
 
 	// Success, return object info.
@@ -823,6 +841,12 @@ func (xl xlObjects) DeleteObject(ctx context.Context, bucket, object string) (er
 		return err
 	}
 
+	// Objects in versioned bucket are replaced by delete marker.
+	if !hasSuffix(object, slashSeparator) && getBucketVersioningStatus(bucket) != "" {
+		_, err = xl.deleteLatestVersion(ctx, bucket, object)
+		return err
+	}
+
 	if hasSuffix(object, slashSeparator) {
 		// Delete the object on all disks.
 		if err = xl.deleteObject(ctx, bucket, object); err != nil {
@@ -844,6 +868,195 @@ func (xl xlObjects) DeleteObject(ctx context.Context, bucket, object string) (er
 	return nil
 }
 
+// PutObjectTags - replaces tags of the object in `xl.json`, empty tags
+// remove all tags of the object.
+func (xl xlObjects) PutObjectTags(ctx context.Context, bucket, object, tags string) error {
+	// Acquire a write lock before updating the object.
+	objectLock := xl.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	// Read metadata associated with the object from all disks.
+	storageDisks := xl.getDisks()
+	metaArr, errs := readAllXLMetadata(ctx, storageDisks, bucket, object)
+
+	// get Quorum for this object
+	readQuorum, writeQuorum, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	if reducedErr := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, readQuorum); reducedErr != nil {
+		return toObjectErr(reducedErr, bucket, object)
+	}
+
+	// Update `xl.json` content only on disks having the object.
+	onlineDisks := make([]StorageAPI, len(storageDisks))
+	for index := range metaArr {
+		if errs[index] != nil || !metaArr[index].IsValid() {
+			continue
+		}
+		onlineDisks[index] = storageDisks[index]
+		if tags == "" {
+			delete(metaArr[index].Meta, amzObjectTagging)
+		} else {
+			metaArr[index].Meta[amzObjectTagging] = tags
+		}
+	}
+
+	tempObj := mustGetUUID()
+
+	// Write unique `xl.json` for each disk.
+	if onlineDisks, err = writeUniqueXLMetadata(ctx, onlineDisks, minioMetaTmpBucket, tempObj, metaArr, writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	// Rename atomically `xl.json` from tmp location to destination for each disk.
+	if _, err = renameXLMetadata(ctx, onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	return nil
+}
+
+// PutObjectReplicationStatus - sets the replication status of the object
+// in `xl.json`, if the object still has the given etag.
+func (xl xlObjects) PutObjectReplicationStatus(ctx context.Context, bucket, object, etag, status string) error {
+	// Acquire a write lock before updating the object.
+	objectLock := xl.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	// Read metadata associated with the object from all disks.
+	storageDisks := xl.getDisks()
+	metaArr, errs := readAllXLMetadata(ctx, storageDisks, bucket, object)
+
+	// get Quorum for this object
+	readQuorum, writeQuorum, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	if reducedErr := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, readQuorum); reducedErr != nil {
+		return toObjectErr(reducedErr, bucket, object)
+	}
+
+	// List all online disks.
+	_, modTime := listOnlineDisks(storageDisks, metaArr, errs)
+
+	// Pick latest valid metadata.
+	xlMeta, err := pickValidXLMeta(ctx, metaArr, modTime)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	// The object was overwritten since it was replicated.
+	if xlMeta.Meta["etag"] != etag {
+		return InvalidETag{}
+	}
+
+	// Update `xl.json` content only on disks having the object.
+	onlineDisks := make([]StorageAPI, len(storageDisks))
+	for index := range metaArr {
+		if errs[index] != nil || !metaArr[index].IsValid() {
+			continue
+		}
+		onlineDisks[index] = storageDisks[index]
+		metaArr[index].Meta[amzReplicationStatus] = status
+	}
+
+	tempObj := mustGetUUID()
+
+	// Write unique `xl.json` for each disk.
+	if onlineDisks, err = writeUniqueXLMetadata(ctx, onlineDisks, minioMetaTmpBucket, tempObj, metaArr, writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	// Rename atomically `xl.json` from tmp location to destination for each disk.
+	if _, err = renameXLMetadata(ctx, onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	return nil
+}
+
+// PutObjectMetadata - sets the given metadata entries of a version of
+// the object in `xl.json`, if the version still has the given etag. An
+// empty version ID refers to the latest version.
+func (xl xlObjects) PutObjectMetadata(ctx context.Context, bucket, object, versionID, etag string, metadata map[string]string) error {
+	// Acquire a write lock before updating the object.
+	objectLock := xl.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	xlMeta, metaArr, errs, err := xl.readXLMetaVersions(ctx, bucket, object)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	_, writeQuorum, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	// Metadata of the version on every disk, nil if the disk does not
+	// have it.
+	versionMeta := func(m xlMetaV1) map[string]string {
+		if versionID == "" || versionID == m.latestVersion().VersionID {
+			return m.Meta
+		}
+		if i := m.findVersion(versionID); i >= 0 {
+			return m.Versions[i].Meta
+		}
+		return nil
+	}
+
+	meta := versionMeta(xlMeta)
+	if meta == nil {
+		return VersionNotFound{bucket, object, versionID}
+	}
+	// The version was overwritten since its metadata was read.
+	if extractETag(meta) != etag {
+		return InvalidETag{}
+	}
+
+	for index := range metaArr {
+		if errs[index] != nil || !metaArr[index].IsValid() {
+			continue
+		}
+		if meta = versionMeta(metaArr[index]); meta == nil {
+			continue
+		}
+		for k, v := range metadata {
+			meta[k] = v
+		}
+	}
+
+	if err = xl.writeXLMetaVersions(ctx, bucket, object, metaArr, writeQuorum); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+
+	return nil
+}
+
 // ListObjectsV2 lists all blobs in bucket filtered by prefix
 func (xl xlObjects) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
 	loi, err := xl.ListObjects(ctx, bucket, prefix, continuationToken, delimiter, maxKeys)
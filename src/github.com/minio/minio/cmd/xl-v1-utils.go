@@ -139,6 +139,14 @@ func parseXLVersion(xlMetaBuf []byte) string {
 	return gjson.GetBytes(xlMetaBuf, "version").String()
 }
 
+func parseXLVersionID(xlMetaBuf []byte) string {
+	return gjson.GetBytes(xlMetaBuf, "versionId").String()
+}
+
+func parseXLDeleteMarker(xlMetaBuf []byte) bool {
+	return gjson.GetBytes(xlMetaBuf, "deleteMarker").Bool()
+}
+
 func parseXLFormat(xlMetaBuf []byte) string {
 	return gjson.GetBytes(xlMetaBuf, "format").String()
 }
@@ -210,6 +218,33 @@ func parseXLMetaMap(xlMetaBuf []byte) map[string]string {
 	return metaMap
 }
 
+func parseXLVersions(ctx context.Context, xlMetaBuf []byte) ([]xlObjectVersion, error) {
+	// Parse the noncurrent versions, each of them carries the
+	// same fields as the latest version.
+	var versions []xlObjectVersion
+	for _, v := range gjson.GetBytes(xlMetaBuf, "versions").Array() {
+		versionBuf := []byte(v.Raw)
+		stat, err := parseXLStat(versionBuf)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			return nil, err
+		}
+		erasure, err := parseXLErasureInfo(ctx, versionBuf)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, xlObjectVersion{
+			VersionID:    parseXLVersionID(versionBuf),
+			DeleteMarker: parseXLDeleteMarker(versionBuf),
+			Stat:         stat,
+			Erasure:      erasure,
+			Meta:         parseXLMetaMap(versionBuf),
+			Parts:        parseXLParts(versionBuf),
+		})
+	}
+	return versions, nil
+}
+
 // Constructs XLMetaV1 using `gjson` lib to retrieve each field.
 func xlMetaV1UnmarshalJSON(ctx context.Context, xlMetaBuf []byte) (xlMeta xlMetaV1, e error) {
 	// obtain version.
@@ -224,6 +259,9 @@ func xlMetaV1UnmarshalJSON(ctx context.Context, xlMetaBuf []byte) (xlMeta xlMeta
 	}
 
 	xlMeta.Stat = stat
+	// obtain version ID and delete marker.
+	xlMeta.VersionID = parseXLVersionID(xlMetaBuf)
+	xlMeta.DeleteMarker = parseXLDeleteMarker(xlMetaBuf)
 	// parse the xlV1Meta.Erasure fields.
 	xlMeta.Erasure, err = parseXLErasureInfo(ctx, xlMetaBuf)
 	if err != nil {
@@ -236,6 +274,11 @@ func xlMetaV1UnmarshalJSON(ctx context.Context, xlMetaBuf []byte) (xlMeta xlMeta
 	xlMeta.Minio.Release = parseXLRelease(xlMetaBuf)
 	// parse xlMetaV1.
 	xlMeta.Meta = parseXLMetaMap(xlMetaBuf)
+	// Parse the noncurrent versions.
+	xlMeta.Versions, err = parseXLVersions(ctx, xlMetaBuf)
+	if err != nil {
+		return xlMeta, err
+	}
 
 	return xlMeta, nil
 }
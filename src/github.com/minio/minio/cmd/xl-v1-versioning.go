@@ -0,0 +1,539 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Every version of an object is recorded in its `xl.json`, the latest
+// version, which may be a delete marker, in the usual fields and the
+// noncurrent versions newest first in `versions`. Parts of the latest
+// version are stored at their usual location `bucket/object/part.N`,
+// parts of a noncurrent version under `bucket/object/versionID/`.
+
+// latestVersion - returns the latest version of the object as noncurrent
+// version, objects written without versioning have null version ID.
+func (m xlMetaV1) latestVersion() xlObjectVersion {
+	versionID := m.VersionID
+	if versionID == "" {
+		versionID = nullVersionID
+	}
+	return xlObjectVersion{
+		VersionID:    versionID,
+		DeleteMarker: m.DeleteMarker,
+		Stat:         m.Stat,
+		Erasure:      m.Erasure,
+		Meta:         m.Meta,
+		Parts:        m.Parts,
+	}
+}
+
+// findVersion - returns the index of given noncurrent version, -1 if
+// the object has no such noncurrent version.
+func (m xlMetaV1) findVersion(versionID string) int {
+	for i, version := range m.Versions {
+		if version.VersionID == versionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// toXLMeta - returns the version as the latest version of the object
+// described by xlMeta, without noncurrent versions.
+func (v xlObjectVersion) toXLMeta(xlMeta xlMetaV1) xlMetaV1 {
+	xlMeta.VersionID = v.VersionID
+	xlMeta.DeleteMarker = v.DeleteMarker
+	xlMeta.Stat = v.Stat
+	xlMeta.Erasure = v.Erasure
+	xlMeta.Meta = v.Meta
+	xlMeta.Parts = v.Parts
+	xlMeta.Versions = nil
+	return xlMeta
+}
+
+// toObjectVersions - returns object info of all versions of the object,
+// newest first.
+func (m xlMetaV1) toObjectVersions(bucket, object string) []ObjectInfo {
+	objInfo := m.ToObjectInfo(bucket, object)
+	objInfo.VersionID = m.latestVersion().VersionID
+	objInfo.IsLatest = true
+
+	objInfos := []ObjectInfo{objInfo}
+	for _, version := range m.Versions {
+		objInfos = append(objInfos, version.toXLMeta(m).ToObjectInfo(bucket, object))
+	}
+	return objInfos
+}
+
+// readXLMetaVersions - reads `xl.json` of the object from all disks,
+// returns the latest valid metadata along with metadata of all disks.
+func (xl xlObjects) readXLMetaVersions(ctx context.Context, bucket, object string) (xlMeta xlMetaV1, metaArr []xlMetaV1, errs []error, err error) {
+	metaArr, errs = readAllXLMetadata(ctx, xl.getDisks(), bucket, object)
+
+	readQuorum, _, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return xlMeta, nil, nil, err
+	}
+
+	if err = reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, readQuorum); err != nil {
+		return xlMeta, nil, nil, err
+	}
+
+	_, modTime := listOnlineDisks(xl.getDisks(), metaArr, errs)
+	xlMeta, err = pickValidXLMeta(ctx, metaArr, modTime)
+	return xlMeta, metaArr, errs, err
+}
+
+// writeXLMetaVersions - replaces `xl.json` of the object with given
+// metadata on all disks having valid metadata.
+func (xl xlObjects) writeXLMetaVersions(ctx context.Context, bucket, object string, metaArr []xlMetaV1, writeQuorum int) error {
+	disks := make([]StorageAPI, len(metaArr))
+	for index, disk := range xl.getDisks() {
+		if metaArr[index].IsValid() {
+			disks[index] = disk
+		}
+	}
+
+	tempObj := mustGetUUID()
+
+	// Write unique `xl.json` for each disk.
+	disks, err := writeUniqueXLMetadata(ctx, disks, minioMetaTmpBucket, tempObj, metaArr, writeQuorum)
+	if err != nil {
+		return err
+	}
+
+	// Rename atomically `xl.json` from tmp location to destination for each disk.
+	_, err = renameXLMetadata(ctx, disks, minioMetaTmpBucket, tempObj, bucket, object, writeQuorum)
+	return err
+}
+
+// moveParts - moves given parts between directories on all disks.
+func moveParts(ctx context.Context, disks []StorageAPI, srcBucket, srcDir, dstBucket, dstDir string, parts []objectPartInfo, writeQuorum int) error {
+	for _, part := range parts {
+		if _, err := renamePart(ctx, disks, srcBucket, pathJoin(srcDir, part.Name), dstBucket, pathJoin(dstDir, part.Name), writeQuorum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteParts - removes given parts stored in dir from all disks,
+// errors are ignored.
+func (xl xlObjects) deleteParts(bucket, dir string, parts []objectPartInfo) {
+	var wg sync.WaitGroup
+	for _, disk := range xl.getDisks() {
+		if disk == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(disk StorageAPI) {
+			defer wg.Done()
+			for _, part := range parts {
+				_ = disk.DeleteFile(bucket, pathJoin(dir, part.Name))
+			}
+		}(disk)
+	}
+	wg.Wait()
+}
+
+// deleteVersionDir - removes the directory holding parts of given
+// noncurrent version from all disks, errors are ignored.
+func (xl xlObjects) deleteVersionDir(ctx context.Context, bucket, object, versionID string) {
+	var wg sync.WaitGroup
+	for _, disk := range xl.getDisks() {
+		if disk == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(disk StorageAPI) {
+			defer wg.Done()
+			_ = cleanupDir(ctx, disk, bucket, pathJoin(object, versionID))
+		}(disk)
+	}
+	wg.Wait()
+}
+
+// getObjectVersions - lists all versions of the object, newest first.
+func (xl xlObjects) getObjectVersions(ctx context.Context, bucket, object string) ([]ObjectInfo, error) {
+	// Lock the object before reading.
+	objectLock := xl.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetRLock(globalObjectTimeout); err != nil {
+		return nil, err
+	}
+	defer objectLock.RUnlock()
+
+	if !xl.isObject(bucket, object) {
+		return nil, nil
+	}
+
+	xlMeta, _, _, err := xl.readXLMetaVersions(ctx, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+
+	return xlMeta.toObjectVersions(bucket, object), nil
+}
+
+// putVersion - makes the object written to srcBucket/srcPrefix the
+// latest version of the object in a versioned bucket. The current
+// latest version becomes noncurrent, unless both of them are null
+// versions. A new null version replaces the noncurrent null version.
+func (xl xlObjects) putVersion(ctx context.Context, srcBucket, srcPrefix, bucket, object, versionID string, writeQuorum int) error {
+	disks := xl.getDisks()
+	if !xl.isObject(bucket, object) {
+		_, err := renameObject(ctx, disks, srcBucket, srcPrefix, bucket, object, writeQuorum)
+		return err
+	}
+
+	latest, metaArr, errs, err := xl.readXLMetaVersions(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	_, latestWriteQuorum, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return err
+	}
+
+	newMetaArr, newErrs := readAllXLMetadata(ctx, disks, srcBucket, srcPrefix)
+	newMeta, _ := getLatestXLMeta(newMetaArr, newErrs)
+
+	// Move parts of the latest version out of the way.
+	latestVersionID := latest.latestVersion().VersionID
+	keepLatest := versionID != nullVersionID || latestVersionID != nullVersionID
+	if keepLatest {
+		if err = moveParts(ctx, disks, bucket, object, bucket, pathJoin(object, latestVersionID), latest.Parts, latestWriteQuorum); err != nil {
+			return err
+		}
+	} else {
+		xl.deleteParts(bucket, object, latest.Parts)
+	}
+
+	if versionID == nullVersionID && latest.findVersion(nullVersionID) >= 0 {
+		xl.deleteVersionDir(ctx, bucket, object, nullVersionID)
+	}
+
+	if err = moveParts(ctx, evalDisks(disks, newErrs), srcBucket, srcPrefix, bucket, object, newMeta.Parts, writeQuorum); err != nil {
+		return err
+	}
+
+	// Record versions of the object, each disk keeps its own
+	// erasure info of the versions it holds.
+	for index := range newMetaArr {
+		if newErrs[index] != nil || errs[index] != nil {
+			continue
+		}
+		var versions []xlObjectVersion
+		if keepLatest {
+			versions = append(versions, metaArr[index].latestVersion())
+		}
+		for _, version := range metaArr[index].Versions {
+			if versionID == nullVersionID && version.VersionID == nullVersionID {
+				continue
+			}
+			versions = append(versions, version)
+		}
+		newMetaArr[index].Versions = versions
+	}
+
+	return xl.writeXLMetaVersions(ctx, bucket, object, newMetaArr, writeQuorum)
+}
+
+// deleteLatestVersion - deletes the object in a versioned bucket by
+// adding a delete marker as its latest version.
+func (xl xlObjects) deleteLatestVersion(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	disks := xl.getDisks()
+	writeQuorum := len(disks)/2 + 1
+
+	xlMeta := newXLMetaV1(object, len(disks)/2, len(disks)/2)
+	xlMeta.Stat.ModTime = UTCNow()
+	xlMeta.VersionID = newVersionID(bucket)
+	xlMeta.DeleteMarker = true
+
+	tempObj := mustGetUUID()
+
+	// Delete temporary object in the event of failure.
+	defer xl.deleteObject(ctx, minioMetaTmpBucket, tempObj)
+
+	if _, err := writeSameXLMetadata(ctx, disks, minioMetaTmpBucket, tempObj, xlMeta, writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	if err := xl.putVersion(ctx, minioMetaTmpBucket, tempObj, bucket, object, xlMeta.VersionID, writeQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	objInfo := xlMeta.ToObjectInfo(bucket, object)
+	objInfo.IsLatest = true
+	return objInfo, nil
+}
+
+// readVersionXLMeta - reads metadata of given version of the object from
+// all disks as if it was the latest version, along with the directory
+// holding its parts.
+func (xl xlObjects) readVersionXLMeta(ctx context.Context, bucket, object, versionID string) (metaArr []xlMetaV1, errs []error, dataDir string, err error) {
+	if !isValidVersionID(versionID) || !xl.isObject(bucket, object) {
+		return nil, nil, "", VersionNotFound{bucket, object, versionID}
+	}
+
+	xlMeta, metaArr, errs, err := xl.readXLMetaVersions(ctx, bucket, object)
+	if err != nil {
+		return nil, nil, "", toObjectErr(err, bucket, object)
+	}
+
+	if xlMeta.latestVersion().VersionID == versionID {
+		return metaArr, errs, object, nil
+	}
+
+	if xlMeta.findVersion(versionID) < 0 {
+		return nil, nil, "", VersionNotFound{bucket, object, versionID}
+	}
+
+	versionArr, versionErrs := noncurrentVersionXLMeta(metaArr, errs, versionID)
+	return versionArr, versionErrs, pathJoin(object, versionID), nil
+}
+
+// noncurrentVersionXLMeta - returns metadata of given noncurrent version
+// on each disk as if it was the latest version, disks without the
+// version have errFileNotFound.
+func noncurrentVersionXLMeta(metaArr []xlMetaV1, errs []error, versionID string) ([]xlMetaV1, []error) {
+	versionArr := make([]xlMetaV1, len(metaArr))
+	versionErrs := make([]error, len(errs))
+	for index := range metaArr {
+		if errs[index] != nil {
+			versionErrs[index] = errs[index]
+			continue
+		}
+		i := metaArr[index].findVersion(versionID)
+		if i < 0 {
+			versionErrs[index] = errFileNotFound
+			continue
+		}
+		versionArr[index] = metaArr[index].Versions[i].toXLMeta(metaArr[index])
+	}
+
+	return versionArr, versionErrs
+}
+
+// getVersionInfo - returns object info of given version of the object.
+func (xl xlObjects) getVersionInfo(ctx context.Context, bucket, object, versionID string) (ObjectInfo, error) {
+	metaArr, errs, dataDir, err := xl.readVersionXLMeta(ctx, bucket, object, versionID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	readQuorum, _, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	if err = reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, readQuorum); err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	_, modTime := listOnlineDisks(xl.getDisks(), metaArr, errs)
+	xlMeta, err := pickValidXLMeta(ctx, metaArr, modTime)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	objInfo := xlMeta.ToObjectInfo(bucket, object)
+	objInfo.VersionID = versionID
+	objInfo.IsLatest = dataDir == object
+	return objInfo, nil
+}
+
+// GetObjectVersion - reads given version of the object.
+func (xl xlObjects) GetObjectVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	// Lock the object before reading.
+	objectLock := xl.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetRLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.RUnlock()
+	return xl.getObjectVersion(ctx, bucket, object, versionID, startOffset, length, writer, etag)
+}
+
+// getObjectVersion wrapper for xl GetObjectVersion.
+func (xl xlObjects) getObjectVersion(ctx context.Context, bucket, object, versionID string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return err
+	}
+
+	objInfo, err := xl.getVersionInfo(ctx, bucket, object, versionID)
+	if err != nil {
+		return err
+	}
+
+	if objInfo.DeleteMarker {
+		return ObjectVersionDeleteMarker{bucket, object, versionID}
+	}
+
+	metaArr, errs, dataDir, err := xl.readVersionXLMeta(ctx, bucket, object, versionID)
+	if err != nil {
+		return err
+	}
+
+	return xl.getObjectData(ctx, bucket, object, dataDir, metaArr, errs, startOffset, length, writer)
+}
+
+// GetObjectVersionInfo - reads metadata of given version of the object,
+// delete markers have no metadata to be read.
+func (xl xlObjects) GetObjectVersionInfo(ctx context.Context, bucket, object, versionID string) (ObjectInfo, error) {
+	// Lock the object before reading.
+	objectLock := xl.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetRLock(globalObjectTimeout); err != nil {
+		return ObjectInfo{}, err
+	}
+	defer objectLock.RUnlock()
+
+	if err := checkGetObjArgs(ctx, bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	objInfo, err := xl.getVersionInfo(ctx, bucket, object, versionID)
+	if err != nil {
+		return objInfo, err
+	}
+
+	if objInfo.DeleteMarker {
+		return ObjectInfo{}, ObjectVersionDeleteMarker{bucket, object, versionID}
+	}
+
+	return objInfo, nil
+}
+
+// removeLatestVersion - permanently removes the latest version of the
+// object, the newest noncurrent version, if any, becomes the latest.
+func (xl xlObjects) removeLatestVersion(ctx context.Context, bucket, object string, latest xlMetaV1, metaArr []xlMetaV1, errs []error, writeQuorum int) error {
+	if len(latest.Versions) == 0 {
+		return xl.deleteObject(ctx, bucket, object)
+	}
+
+	next := latest.Versions[0]
+	xl.deleteParts(bucket, object, latest.Parts)
+	if err := moveParts(ctx, xl.getDisks(), bucket, pathJoin(object, next.VersionID), bucket, object, next.Parts, writeQuorum); err != nil {
+		return err
+	}
+
+	for index := range metaArr {
+		if errs[index] != nil {
+			continue
+		}
+		i := metaArr[index].findVersion(next.VersionID)
+		if i < 0 {
+			metaArr[index] = xlMetaV1{}
+			continue
+		}
+		versions := metaArr[index].Versions[i+1:]
+		metaArr[index] = metaArr[index].Versions[i].toXLMeta(metaArr[index])
+		metaArr[index].Versions = versions
+	}
+
+	if err := xl.writeXLMetaVersions(ctx, bucket, object, metaArr, writeQuorum); err != nil {
+		return err
+	}
+
+	xl.deleteVersionDir(ctx, bucket, object, next.VersionID)
+	return nil
+}
+
+// removeNoncurrentVersion - permanently removes given noncurrent version
+// of the object.
+func (xl xlObjects) removeNoncurrentVersion(ctx context.Context, bucket, object, versionID string, metaArr []xlMetaV1, errs []error, writeQuorum int) error {
+	for index := range metaArr {
+		if errs[index] != nil {
+			continue
+		}
+		if i := metaArr[index].findVersion(versionID); i >= 0 {
+			versions := metaArr[index].Versions
+			metaArr[index].Versions = append(versions[:i:i], versions[i+1:]...)
+		}
+	}
+
+	if err := xl.writeXLMetaVersions(ctx, bucket, object, metaArr, writeQuorum); err != nil {
+		return err
+	}
+
+	xl.deleteVersionDir(ctx, bucket, object, versionID)
+	return nil
+}
+
+// DeleteObjectVersion - permanently deletes given version of the object,
+// deleting the latest version makes the newest noncurrent version the
+// latest one. Empty version ID deletes the object as DeleteObject does
+// and returns the delete marker created, if any.
+func (xl xlObjects) DeleteObjectVersion(ctx context.Context, bucket, object, versionID string) (ObjectInfo, error) {
+	// Acquire a write lock before deleting the object.
+	objectLock := xl.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalOperationTimeout); err != nil {
+		return ObjectInfo{}, err
+	}
+	defer objectLock.Unlock()
+
+	if err := checkDelObjArgs(ctx, bucket, object); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if versionID == "" {
+		if getBucketVersioningStatus(bucket) != "" {
+			return xl.deleteLatestVersion(ctx, bucket, object)
+		}
+
+		if !xl.isObject(bucket, object) {
+			return ObjectInfo{}, ObjectNotFound{bucket, object}
+		}
+		if err := xl.deleteObject(ctx, bucket, object); err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		return ObjectInfo{Bucket: bucket, Name: object}, nil
+	}
+
+	if !isValidVersionID(versionID) || !xl.isObject(bucket, object) {
+		return ObjectInfo{}, VersionNotFound{bucket, object, versionID}
+	}
+
+	latest, metaArr, errs, err := xl.readXLMetaVersions(ctx, bucket, object)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	_, writeQuorum, err := objectQuorumFromMeta(xl, metaArr, errs)
+	if err != nil {
+		return ObjectInfo{}, toObjectErr(err, bucket, object)
+	}
+
+	for i, objInfo := range latest.toObjectVersions(bucket, object) {
+		if objInfo.VersionID != versionID {
+			continue
+		}
+		if i == 0 {
+			err = xl.removeLatestVersion(ctx, bucket, object, latest, metaArr, errs, writeQuorum)
+		} else {
+			err = xl.removeNoncurrentVersion(ctx, bucket, object, versionID, metaArr, errs, writeQuorum)
+		}
+		if err != nil {
+			return ObjectInfo{}, toObjectErr(err, bucket, object)
+		}
+		return objInfo, nil
+	}
+
+	return ObjectInfo{}, VersionNotFound{bucket, object, versionID}
+}
@@ -0,0 +1,249 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/pkg/versioning"
+)
+
+// Tests versioned put, get, list and delete on XL sets.
+func TestXLObjectVersioning(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	if globalNotificationSys, err = NewNotificationSys(globalServerConfig, EndpointList{}); err != nil {
+		t.Fatal(err)
+	}
+	resetGlobalBucketSystems()
+	defer resetGlobalBucketSystems()
+
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	ctx := context.Background()
+	bucket, object := "bucket", "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unversioned object becomes the "null" version.
+	if _, err = obj.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader([]byte("abc")), 3, "", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	config := versioning.Versioning{Status: versioning.Enabled}
+	if err = obj.SetBucketVersioning(ctx, bucket, &config); err != nil {
+		t.Fatal(err)
+	}
+	globalBucketMetadataSys.Set(bucket, bucketVersioningConfig, &config)
+
+	objInfo, err := obj.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader([]byte("abcd")), 4, "", ""), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isValidVersionID(objInfo.VersionID) || objInfo.VersionID == nullVersionID {
+		t.Fatalf("Expected a new version id, got %q", objInfo.VersionID)
+	}
+	latestVersionID := objInfo.VersionID
+
+	result, err := obj.ListObjectVersions(ctx, bucket, "", "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(result.Objects))
+	}
+	if result.Objects[0].VersionID != latestVersionID || !result.Objects[0].IsLatest {
+		t.Fatalf("Expected latest version %q first, got %q", latestVersionID, result.Objects[0].VersionID)
+	}
+	if result.Objects[1].VersionID != nullVersionID || result.Objects[1].IsLatest {
+		t.Fatalf("Expected noncurrent null version, got %q", result.Objects[1].VersionID)
+	}
+
+	var buf bytes.Buffer
+	if err = obj.GetObjectVersion(ctx, bucket, object, nullVersionID, 0, 3, &buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "abc" {
+		t.Fatalf("Expected old version content, got %q", buf.String())
+	}
+
+	if _, err = obj.GetObjectVersionInfo(ctx, bucket, object, "00000000-0000-0000-0000-000000000000"); err == nil {
+		t.Fatal("Expected an error for unknown version id")
+	} else if _, ok := err.(VersionNotFound); !ok {
+		t.Fatalf("Expected VersionNotFound, got %T", err)
+	}
+
+	// Deleting without version id adds a delete marker.
+	marker, err := obj.DeleteObjectVersion(ctx, bucket, object, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !marker.DeleteMarker {
+		t.Fatal("Expected a delete marker")
+	}
+	if _, err = obj.GetObjectInfo(ctx, bucket, object); err == nil {
+		t.Fatal("Expected object to be hidden by delete marker")
+	}
+	if _, err = obj.GetObjectVersionInfo(ctx, bucket, object, marker.VersionID); err == nil {
+		t.Fatal("Expected an error when reading a delete marker")
+	} else if _, ok := err.(ObjectVersionDeleteMarker); !ok {
+		t.Fatalf("Expected ObjectVersionDeleteMarker, got %T", err)
+	}
+
+	// Removing the delete marker restores the previous version.
+	if _, err = obj.DeleteObjectVersion(ctx, bucket, object, marker.VersionID); err != nil {
+		t.Fatal(err)
+	}
+	objInfo, err = obj.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.VersionID != latestVersionID {
+		t.Fatalf("Expected version %q to be restored, got %q", latestVersionID, objInfo.VersionID)
+	}
+
+	// Bucket with versions cannot be removed.
+	if err = obj.DeleteBucket(ctx, bucket); err == nil {
+		t.Fatal("Expected bucket with versions to be non-empty")
+	}
+}
+
+// Tests that versions are recorded in `xl.json` of the object, delete
+// markers of missing objects and paginated listing of versions.
+func TestXLObjectVersionsListing(t *testing.T) {
+	rootPath, err := newTestConfig(globalMinioDefaultRegion)
+	if err != nil {
+		t.Fatalf("Unable to initialize server config. %s", err)
+	}
+	defer os.RemoveAll(rootPath)
+
+	resetGlobalBucketSystems()
+	defer resetGlobalBucketSystems()
+
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	globalBucketMetadataSys.Set(bucket, bucketVersioningConfig, &versioning.Versioning{Status: versioning.Enabled})
+
+	// Deleting a missing object adds a delete marker.
+	marker, err := obj.DeleteObjectVersion(ctx, bucket, "missing", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !marker.DeleteMarker || !isValidVersionID(marker.VersionID) {
+		t.Fatalf("Expected a delete marker, got %+v", marker)
+	}
+
+	var versionIDs []string
+	for i := 0; i < 3; i++ {
+		data := []byte(strings.Repeat("a", i+1))
+		objInfo, err := obj.PutObject(ctx, bucket, "object", mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versionIDs = append([]string{objInfo.VersionID}, versionIDs...)
+	}
+
+	// Versions are recorded in `xl.json` of the object.
+	xl := obj.(*xlSets).getHashedSet("object")
+	xlMeta, err := readXLMeta(ctx, xl.getDisks()[0], bucket, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xlMeta.VersionID != versionIDs[0] || len(xlMeta.Versions) != 2 {
+		t.Fatalf("Expected latest version %s with 2 noncurrent versions, got %s with %d", versionIDs[0], xlMeta.VersionID, len(xlMeta.Versions))
+	}
+
+	// Objects hidden by delete marker are not listed.
+	loi, err := obj.ListObjects(ctx, bucket, "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loi.Objects) != 1 || loi.Objects[0].Name != "object" {
+		t.Fatalf("Expected only object to be listed, got %v", loi.Objects)
+	}
+
+	// List versions two at a time, objects in lexical order.
+	expected := []string{"missing/" + marker.VersionID}
+	for _, versionID := range versionIDs {
+		expected = append(expected, "object/"+versionID)
+	}
+	var listed []string
+	keyMarker, versionIDMarker := "", ""
+	for {
+		result, err := obj.ListObjectVersions(ctx, bucket, "", keyMarker, versionIDMarker, "", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, objInfo := range result.Objects {
+			listed = append(listed, objInfo.Name+"/"+objInfo.VersionID)
+		}
+		if !result.IsTruncated {
+			break
+		}
+		keyMarker, versionIDMarker = result.NextKeyMarker, result.NextVersionIDMarker
+	}
+	if !reflect.DeepEqual(listed, expected) {
+		t.Fatalf("Expected versions %v, got %v", expected, listed)
+	}
+
+	// Removing noncurrent version keeps the latest one.
+	if _, err = obj.DeleteObjectVersion(ctx, bucket, "object", versionIDs[1]); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = obj.GetObjectVersion(ctx, bucket, "object", versionIDs[2], 0, -1, &buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a" {
+		t.Fatalf("Expected oldest version content, got %q", buf.String())
+	}
+
+	// Removing the latest version promotes the oldest one.
+	if _, err = obj.DeleteObjectVersion(ctx, bucket, "object", versionIDs[0]); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err = obj.GetObject(ctx, bucket, "object", 0, -1, &buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a" {
+		t.Fatalf("Expected promoted version content, got %q", buf.String())
+	}
+}
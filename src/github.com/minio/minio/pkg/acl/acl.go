@@ -21,9 +21,10 @@ import (
 	"io"
 )
 
-// Data types used for returning dummy access control
-// policy XML, these variables shouldn't be used elsewhere
-// they are only defined to be used in this file alone.
+// XML namespace of S3 access control policy.
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// Grantee - grantee of a grant, either a canonical user or a predefined group.
 type Grantee struct {
 	XMLNS       string `xml:"xmlns:xsi,attr"`
 	XMLXSI      string `xml:"xsi:type,attr"`
@@ -39,11 +40,13 @@ type Owner struct {
 	DisplayName string
 }
 
+// Grant - permission granted to a grantee.
 type Grant struct {
 	Grantee    Grantee `xml:"Grantee"`
 	Permission string  `xml:"Permission"`
 }
 
+// AccessControlPolicy - access control policy of a bucket or an object.
 type AccessControlPolicy struct {
 	XMLName           xml.Name `xml:"AccessControlPolicy"`
 	XMLNS             string   `xml:"xmlns,attr"`
@@ -53,15 +56,36 @@ type AccessControlPolicy struct {
 	} `xml:"AccessControlList"`
 }
 
+// IsAllowed - checks whether given account is granted given permission.
+// Empty accountName denotes an anonymous request.
+func (acl AccessControlPolicy) IsAllowed(accountName, permission string) bool {
+	for _, grant := range acl.AccessControlList.Grants {
+		if grant.Permission != permission && grant.Permission != PermissionFullControl {
+			continue
+		}
+
+		if grant.Grantee.matches(accountName) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // isValid - checks if Policy is valid or not.
 func (acl AccessControlPolicy) isValid() error {
+	for _, grant := range acl.AccessControlList.Grants {
+		if err := grant.isValid(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
 // Validate - validates all rules are for given bucket or not.
 func (acl AccessControlPolicy) Validate(bucketName string) error {
-	return nil
+	return acl.isValid()
 }
 
 // ParseConfig - parses data in given reader to AccessControlPolicy
@@ -69,19 +93,18 @@ func ParseConfig(reader io.Reader, bucketName string) (*AccessControlPolicy, err
 	var acl AccessControlPolicy
 
 	decoder := xml.NewDecoder(reader)
-	err := decoder.Decode(&acl)
-
-	if err != nil {
-		return nil, err
+	if err := decoder.Decode(&acl); err != nil {
+		return nil, errMalformedACL("%v", err)
 	}
 
-	err = acl.Validate(bucketName)
+	XMLNS := acl.XMLNS
+	if XMLNS == "" {
+		XMLNS = s3Namespace
+	}
 
-	// FIXME
-	result := &AccessControlPolicy{Owner: acl.Owner, XMLNS: acl.XMLNS}
+	result := &AccessControlPolicy{Owner: acl.Owner, XMLNS: XMLNS}
 	for _, grant := range acl.AccessControlList.Grants {
-		Type := "CanonicalUser"
-		XMLNS := "http://www.w3.org/2001/XMLSchema-instance"
+		Type := CanonicalUser
 
 		if grant.Grantee.Type != "" {
 			Type = grant.Grantee.Type
@@ -89,21 +112,22 @@ func ParseConfig(reader io.Reader, bucketName string) (*AccessControlPolicy, err
 			Type = grant.Grantee.XMLXSI
 		}
 
-		if grant.Grantee.XMLNS != "" {
-			XMLNS = grant.Grantee.XMLNS
-		}
-
 		result.AccessControlList.Grants = append(result.AccessControlList.Grants, Grant{
 			Grantee: Grantee{
-				XMLNS:       XMLNS,
+				XMLNS:       xsiNamespace,
 				XMLXSI:      Type,
 				Type:        Type,
 				ID:          grant.Grantee.ID,
 				DisplayName: grant.Grantee.DisplayName,
+				URI:         grant.Grantee.URI,
 			},
 			Permission: grant.Permission,
 		})
 	}
 
-	return result, err
+	if err := result.Validate(bucketName); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
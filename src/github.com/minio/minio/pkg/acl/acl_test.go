@@ -0,0 +1,166 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data           string
+		expectedGrants []Grant
+		expectErr      bool
+	}{
+		{`<AccessControlPolicy><Owner><ID>minio</ID></Owner><AccessControlList>
+<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>minio</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>
+<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ</Permission></Grant>
+</AccessControlList></AccessControlPolicy>`, []Grant{
+			{Grantee: NewCanonicalUserGrantee("minio"), Permission: PermissionFullControl},
+			{Grantee: NewGroupGrantee(AllUsersURI), Permission: PermissionRead},
+		}, false},
+		// Invalid XML.
+		{`<AccessControlPolicy>`, nil, true},
+		// Invalid permission.
+		{`<AccessControlPolicy><AccessControlList>
+<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>minio</ID></Grantee><Permission>DELETE</Permission></Grant>
+</AccessControlList></AccessControlPolicy>`, nil, true},
+		// Invalid group URI.
+		{`<AccessControlPolicy><AccessControlList>
+<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://example.com/everyone</URI></Grantee><Permission>READ</Permission></Grant>
+</AccessControlList></AccessControlPolicy>`, nil, true},
+		// Email grantees are not supported.
+		{`<AccessControlPolicy><AccessControlList>
+<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="AmazonCustomerByEmail"><EmailAddress>a@b.c</EmailAddress></Grantee><Permission>READ</Permission></Grant>
+</AccessControlList></AccessControlPolicy>`, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := ParseConfig(strings.NewReader(testCase.data), "mybucket")
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			grants := result.AccessControlList.Grants
+			if len(grants) != len(testCase.expectedGrants) {
+				t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedGrants, grants)
+			}
+			for j := range grants {
+				if grants[j] != testCase.expectedGrants[j] {
+					t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedGrants[j], grants[j])
+				}
+			}
+		}
+	}
+}
+
+func TestAccessControlPolicyIsAllowed(t *testing.T) {
+	owner := Owner{ID: "minio"}
+	privateACL, _ := NewCannedACL(Private, owner, owner)
+	publicReadACL, _ := NewCannedACL(PublicRead, owner, owner)
+	authenticatedReadACL, _ := NewCannedACL(AuthenticatedRead, owner, owner)
+
+	testCases := []struct {
+		acl            *AccessControlPolicy
+		accountName    string
+		permission     string
+		expectedResult bool
+	}{
+		{privateACL, "minio", PermissionWriteACP, true},
+		{privateACL, "", PermissionRead, false},
+		{privateACL, "reader", PermissionRead, false},
+		{publicReadACL, "", PermissionRead, true},
+		{publicReadACL, "", PermissionWrite, false},
+		{authenticatedReadACL, "", PermissionRead, false},
+		{authenticatedReadACL, "reader", PermissionRead, true},
+		{authenticatedReadACL, "reader", PermissionReadACP, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.acl.IsAllowed(testCase.accountName, testCase.permission)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	owner := Owner{ID: "minio"}
+
+	testCases := []struct {
+		header         http.Header
+		expectedGrants []Grant
+		expectErr      bool
+	}{
+		{http.Header{}, nil, false},
+		{http.Header{"X-Amz-Acl": []string{PublicRead}}, []Grant{
+			{Grantee: NewCanonicalUserGrantee("minio"), Permission: PermissionFullControl},
+			{Grantee: NewGroupGrantee(AllUsersURI), Permission: PermissionRead},
+		}, false},
+		{http.Header{"X-Amz-Acl": []string{"public"}}, nil, true},
+		{http.Header{
+			"X-Amz-Grant-Read":         []string{`uri="http://acs.amazonaws.com/groups/global/AllUsers", id="reader"`},
+			"X-Amz-Grant-Full-Control": []string{`id="minio"`},
+		}, []Grant{
+			{Grantee: NewCanonicalUserGrantee("minio"), Permission: PermissionFullControl},
+			{Grantee: NewGroupGrantee(AllUsersURI), Permission: PermissionRead},
+			{Grantee: NewCanonicalUserGrantee("reader"), Permission: PermissionRead},
+		}, false},
+		{http.Header{"X-Amz-Grant-Read": []string{`emailAddress="a@b.c"`}}, nil, true},
+		{http.Header{"X-Amz-Grant-Read": []string{`name="reader"`}}, nil, true},
+		{http.Header{
+			"X-Amz-Acl":        []string{Private},
+			"X-Amz-Grant-Read": []string{`id="reader"`},
+		}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := ParseHeaders(testCase.header, owner, owner)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if testCase.expectErr {
+			continue
+		}
+
+		if testCase.expectedGrants == nil {
+			if result != nil {
+				t.Fatalf("case %v: expected: <nil>, got: %v\n", i+1, result)
+			}
+			continue
+		}
+
+		grants := result.AccessControlList.Grants
+		if len(grants) != len(testCase.expectedGrants) {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedGrants, grants)
+		}
+		for j := range grants {
+			if grants[j] != testCase.expectedGrants[j] {
+				t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedGrants[j], grants[j])
+			}
+		}
+	}
+}
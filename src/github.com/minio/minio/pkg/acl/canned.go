@@ -0,0 +1,113 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Canned ACLs.
+// Refer https://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl
+// for more information.
+const (
+	Private                = "private"
+	PublicRead             = "public-read"
+	PublicReadWrite        = "public-read-write"
+	AuthenticatedRead      = "authenticated-read"
+	BucketOwnerRead        = "bucket-owner-read"
+	BucketOwnerFullControl = "bucket-owner-full-control"
+	LogDeliveryWrite       = "log-delivery-write"
+)
+
+// Canned ACL request header.
+const cannedACLHeader = "X-Amz-Acl"
+
+// NewCannedACL - returns access control policy of given canned ACL.
+// Owner is the owner of the resource and bucketOwner is the owner of the
+// bucket the resource belongs to.
+func NewCannedACL(cannedACL string, owner, bucketOwner Owner) (*AccessControlPolicy, error) {
+	ownerGrant := Grant{Grantee: NewCanonicalUserGrantee(owner.ID), Permission: PermissionFullControl}
+	grants := []Grant{ownerGrant}
+
+	switch cannedACL {
+	case Private:
+	case PublicRead:
+		grants = append(grants, Grant{Grantee: NewGroupGrantee(AllUsersURI), Permission: PermissionRead})
+	case PublicReadWrite:
+		grants = append(grants,
+			Grant{Grantee: NewGroupGrantee(AllUsersURI), Permission: PermissionRead},
+			Grant{Grantee: NewGroupGrantee(AllUsersURI), Permission: PermissionWrite},
+		)
+	case AuthenticatedRead:
+		grants = append(grants, Grant{Grantee: NewGroupGrantee(AuthenticatedUsersURI), Permission: PermissionRead})
+	case BucketOwnerRead:
+		if bucketOwner.ID != owner.ID {
+			grants = append(grants, Grant{Grantee: NewCanonicalUserGrantee(bucketOwner.ID), Permission: PermissionRead})
+		}
+	case BucketOwnerFullControl:
+		if bucketOwner.ID != owner.ID {
+			grants = append(grants, Grant{Grantee: NewCanonicalUserGrantee(bucketOwner.ID), Permission: PermissionFullControl})
+		}
+	case LogDeliveryWrite:
+		grants = append(grants,
+			Grant{Grantee: NewGroupGrantee(LogDeliveryURI), Permission: PermissionWrite},
+			Grant{Grantee: NewGroupGrantee(LogDeliveryURI), Permission: PermissionReadACP},
+		)
+	default:
+		return nil, errInvalidArgument("invalid canned ACL '%v'", cannedACL)
+	}
+
+	acp := &AccessControlPolicy{XMLNS: s3Namespace, Owner: owner}
+	acp.AccessControlList.Grants = grants
+	return acp, nil
+}
+
+// ParseHeaders - parses access control policy from x-amz-acl or
+// x-amz-grant-* request headers. It returns nil if none of the headers
+// is present.
+func ParseHeaders(header http.Header, owner, bucketOwner Owner) (*AccessControlPolicy, error) {
+	_, hasCannedACL := header[cannedACLHeader]
+	switch {
+	case hasCannedACL && hasGrantHeaders(header):
+		return nil, &ErrInvalidRequest{Reason: "specifying both canned ACL and grant headers is not allowed"}
+	case hasCannedACL:
+		return NewCannedACL(header.Get(cannedACLHeader), owner, bucketOwner)
+	case !hasGrantHeaders(header):
+		return nil, nil
+	}
+
+	// Parse grant headers in a well defined order.
+	var names []string
+	for name := range grantHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	acp := &AccessControlPolicy{XMLNS: s3Namespace, Owner: owner}
+	for _, name := range names {
+		for _, value := range header[name] {
+			grants, err := parseGrantHeader(value, grantHeaders[name])
+			if err != nil {
+				return nil, err
+			}
+			acp.AccessControlList.Grants = append(acp.AccessControlList.Grants, grants...)
+		}
+	}
+
+	return acp, nil
+}
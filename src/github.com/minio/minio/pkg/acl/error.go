@@ -0,0 +1,54 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import "fmt"
+
+// ErrMalformedACL - access control policy does not conform to the S3 ACL schema.
+type ErrMalformedACL struct {
+	Reason string
+}
+
+func (err ErrMalformedACL) Error() string {
+	return fmt.Sprintf("malformed access control policy: %v", err.Reason)
+}
+
+// ErrInvalidArgument - access control policy or ACL header has an invalid value.
+type ErrInvalidArgument struct {
+	Reason string
+}
+
+func (err ErrInvalidArgument) Error() string {
+	return fmt.Sprintf("invalid access control policy: %v", err.Reason)
+}
+
+// ErrInvalidRequest - both canned ACL and grant headers are specified.
+type ErrInvalidRequest struct {
+	Reason string
+}
+
+func (err ErrInvalidRequest) Error() string {
+	return fmt.Sprintf("invalid ACL request: %v", err.Reason)
+}
+
+func errMalformedACL(format string, a ...interface{}) error {
+	return &ErrMalformedACL{Reason: fmt.Sprintf(format, a...)}
+}
+
+func errInvalidArgument(format string, a ...interface{}) error {
+	return &ErrInvalidArgument{Reason: fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,210 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+)
+
+// Permissions of a grant.
+const (
+	PermissionRead        = "READ"
+	PermissionWrite       = "WRITE"
+	PermissionReadACP     = "READ_ACP"
+	PermissionWriteACP    = "WRITE_ACP"
+	PermissionFullControl = "FULL_CONTROL"
+)
+
+// Grantee types.
+const (
+	CanonicalUser         = "CanonicalUser"
+	Group                 = "Group"
+	AmazonCustomerByEmail = "AmazonCustomerByEmail"
+)
+
+// URIs of predefined groups.
+const (
+	AllUsersURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	AuthenticatedUsersURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+	LogDeliveryURI        = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+)
+
+// XML namespace of the xsi:type attribute of a grantee.
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// grantHeaders - maps x-amz-grant-* request headers to their permission.
+var grantHeaders = map[string]string{
+	"X-Amz-Grant-Read":         PermissionRead,
+	"X-Amz-Grant-Write":        PermissionWrite,
+	"X-Amz-Grant-Read-Acp":     PermissionReadACP,
+	"X-Amz-Grant-Write-Acp":    PermissionWriteACP,
+	"X-Amz-Grant-Full-Control": PermissionFullControl,
+}
+
+func isValidPermission(permission string) bool {
+	switch permission {
+	case PermissionRead, PermissionWrite, PermissionReadACP, PermissionWriteACP, PermissionFullControl:
+		return true
+	}
+
+	return false
+}
+
+func isValidGroupURI(uri string) bool {
+	switch uri {
+	case AllUsersURI, AuthenticatedUsersURI, LogDeliveryURI:
+		return true
+	}
+
+	return false
+}
+
+// NewCanonicalUserGrantee - creates grantee of given canonical user ID.
+func NewCanonicalUserGrantee(id string) Grantee {
+	return Grantee{
+		XMLNS:  xsiNamespace,
+		XMLXSI: CanonicalUser,
+		Type:   CanonicalUser,
+		ID:     id,
+	}
+}
+
+// NewGroupGrantee - creates grantee of given predefined group URI.
+func NewGroupGrantee(uri string) Grantee {
+	return Grantee{
+		XMLNS:  xsiNamespace,
+		XMLXSI: Group,
+		Type:   Group,
+		URI:    uri,
+	}
+}
+
+// UnmarshalXML - decodes grantee, its type is read from the xsi:type attribute.
+func (grantee *Grantee) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type subGrantee Grantee
+	var g subGrantee
+	if err := d.DecodeElement(&g, &start); err != nil {
+		return err
+	}
+
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "type" && (attr.Name.Space == xsiNamespace || attr.Name.Space == "xsi") {
+			g.XMLXSI = attr.Value
+		}
+	}
+
+	*grantee = Grantee(g)
+	return nil
+}
+
+// matches - returns whether the grantee applies to given account. Empty
+// accountName denotes an anonymous request.
+func (grantee Grantee) matches(accountName string) bool {
+	switch grantee.Type {
+	case CanonicalUser:
+		return accountName != "" && grantee.ID == accountName
+	case Group:
+		switch grantee.URI {
+		case AllUsersURI:
+			return true
+		case AuthenticatedUsersURI:
+			return accountName != ""
+		}
+	}
+
+	return false
+}
+
+func (grantee Grantee) isValid() error {
+	switch grantee.Type {
+	case CanonicalUser:
+		if grantee.ID == "" {
+			return errMalformedACL("grantee of type '%v' must have 'ID'", CanonicalUser)
+		}
+	case Group:
+		if !isValidGroupURI(grantee.URI) {
+			return errInvalidArgument("invalid group URI '%v'", grantee.URI)
+		}
+	case AmazonCustomerByEmail:
+		return errInvalidArgument("grantee of type '%v' is not supported", AmazonCustomerByEmail)
+	default:
+		return errMalformedACL("invalid grantee type '%v'", grantee.Type)
+	}
+
+	return nil
+}
+
+func (grant Grant) isValid() error {
+	if !isValidPermission(grant.Permission) {
+		return errMalformedACL("invalid permission '%v'", grant.Permission)
+	}
+
+	return grant.Grantee.isValid()
+}
+
+// parseGrantHeader - parses value of a x-amz-grant-* header, which is comma
+// separated list of key="value" grantees like
+// `id="ID", uri="http://acs.amazonaws.com/groups/global/AllUsers"`.
+func parseGrantHeader(value, permission string) ([]Grant, error) {
+	var grants []Grant
+	for _, token := range strings.Split(value, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		tokens := strings.SplitN(token, "=", 2)
+		if len(tokens) != 2 {
+			return nil, errInvalidArgument("invalid grantee '%v'", token)
+		}
+
+		granteeValue := strings.Trim(strings.TrimSpace(tokens[1]), `"`)
+		var grantee Grantee
+		switch strings.ToLower(strings.TrimSpace(tokens[0])) {
+		case "id":
+			grantee = NewCanonicalUserGrantee(granteeValue)
+		case "uri":
+			grantee = NewGroupGrantee(granteeValue)
+		case "emailaddress":
+			grantee = Grantee{XMLNS: xsiNamespace, XMLXSI: AmazonCustomerByEmail, Type: AmazonCustomerByEmail}
+		default:
+			return nil, errInvalidArgument("invalid grantee '%v'", token)
+		}
+
+		grant := Grant{Grantee: grantee, Permission: permission}
+		if err := grant.isValid(); err != nil {
+			return nil, err
+		}
+
+		grants = append(grants, grant)
+	}
+
+	return grants, nil
+}
+
+// hasGrantHeaders - returns whether any x-amz-grant-* header is present.
+func hasGrantHeaders(header http.Header) bool {
+	for name := range grantHeaders {
+		if _, found := header[name]; found {
+			return true
+		}
+	}
+
+	return false
+}
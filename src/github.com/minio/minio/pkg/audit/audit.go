@@ -0,0 +1,166 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package audit implements the audit log of API calls and its targets.
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Version of the format of audit log entries.
+const Version = "1"
+
+// ErrQueueFull - a target has no room to buffer an entry.
+var ErrQueueFull = errors.New("audit target queue is full")
+
+// API - API call of an audit log entry.
+type API struct {
+	Name            string `json:"name"`
+	Bucket          string `json:"bucket,omitempty"`
+	Object          string `json:"object,omitempty"`
+	Status          string `json:"status"`
+	StatusCode      int    `json:"statusCode"`
+	ErrorCode       string `json:"errorCode,omitempty"`
+	Duration        string `json:"duration"`
+	TimeToFirstByte string `json:"timeToFirstByte,omitempty"`
+}
+
+// Entry - audit log entry of an API call.
+type Entry struct {
+	Version    string            `json:"version"`
+	Time       string            `json:"time"`
+	API        API               `json:"api"`
+	RemoteHost string            `json:"remotehost,omitempty"`
+	RequestID  string            `json:"requestID,omitempty"`
+	UserAgent  string            `json:"userAgent,omitempty"`
+	AccessKey  string            `json:"accessKey,omitempty"`
+	ReqHeader  map[string]string `json:"requestHeader,omitempty"`
+	RespHeader map[string]string `json:"responseHeader,omitempty"`
+}
+
+// Headers which are never written to the audit log, as they carry
+// secrets.
+var secretHeaders = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"Cookie":               true,
+	"Set-Cookie":           true,
+	"X-Amz-Security-Token": true,
+	"X-Amz-Server-Side-Encryption-Customer-Key":             true,
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key": true,
+}
+
+// ToHeaderMap - returns the headers as a map, without headers carrying
+// secrets. Multiple values of a header are joined by commas.
+func ToHeaderMap(header http.Header) map[string]string {
+	m := make(map[string]string, len(header))
+	for key, values := range header {
+		key = http.CanonicalHeaderKey(key)
+		if secretHeaders[key] {
+			continue
+		}
+		m[key] = strings.Join(values, ",")
+	}
+	return m
+}
+
+// Target - target of audit log entries.
+type Target interface {
+	ID() string
+	Send(entry Entry) error
+	Close() error
+}
+
+// Logger - sends audit log entries to a list of targets.
+type Logger struct {
+	sync.RWMutex
+	targets []Target
+}
+
+// AddTarget - adds a target.
+func (logger *Logger) AddTarget(target Target) {
+	logger.Lock()
+	defer logger.Unlock()
+
+	logger.targets = append(logger.targets, target)
+}
+
+// HasTargets - returns whether any target is added.
+func (logger *Logger) HasTargets() bool {
+	logger.RLock()
+	defer logger.RUnlock()
+
+	return len(logger.targets) > 0
+}
+
+// Send - sends entry to all targets, and returns the errors of targets
+// by their ID.
+func (logger *Logger) Send(entry Entry) map[string]error {
+	logger.RLock()
+	defer logger.RUnlock()
+
+	var errs map[string]error
+	for _, target := range logger.targets {
+		if err := target.Send(entry); err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[target.ID()] = err
+		}
+	}
+	return errs
+}
+
+// Close - closes and removes all targets.
+func (logger *Logger) Close() {
+	logger.Lock()
+	defer logger.Unlock()
+
+	for _, target := range logger.targets {
+		target.Close()
+	}
+	logger.targets = nil
+}
+
+// NewLogger - creates new audit logger without targets.
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// Config - configuration of audit log targets by their ID.
+type Config struct {
+	Webhook map[string]WebhookArgs `json:"webhook"`
+	File    map[string]FileArgs    `json:"file"`
+}
+
+// Validate - validates the arguments of all enabled targets.
+func (config Config) Validate() error {
+	for _, args := range config.Webhook {
+		if err := args.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, args := range config.File {
+		if err := args.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	xnet "github.com/minio/minio/pkg/net"
+)
+
+func TestToHeaderMap(t *testing.T) {
+	header := http.Header{
+		"Authorization":                             {"AWS4-HMAC-SHA256 Credential=minio/20181002/us-east-1/s3/aws4_request"},
+		"X-Amz-Security-Token":                      {"token"},
+		"X-Amz-Server-Side-Encryption-Customer-Key": {"MzJieXRlc2xvbmdzZWNyZXRrZXltdXN0cHJvdmlkZWQ="},
+		"Content-Type":                              {"application/xml"},
+		"X-Amz-Meta-A":                              {"1", "2"},
+	}
+	expected := map[string]string{
+		"Content-Type": "application/xml",
+		"X-Amz-Meta-A": "1,2",
+	}
+
+	if result := ToHeaderMap(header); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, result)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	endpoint, err := xnet.ParseURL("http://localhost:8080/audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		config    Config
+		expectErr bool
+	}{
+		{Config{}, false},
+		{Config{Webhook: map[string]WebhookArgs{"1": {}}, File: map[string]FileArgs{"1": {}}}, false},
+		{Config{Webhook: map[string]WebhookArgs{"1": {Enable: true, Endpoint: *endpoint}}}, false},
+		{Config{File: map[string]FileArgs{"1": {Enable: true, Path: "/var/log/minio/audit.log"}}}, false},
+		// Missing endpoint.
+		{Config{Webhook: map[string]WebhookArgs{"1": {Enable: true}}}, true},
+		// Invalid batch size.
+		{Config{Webhook: map[string]WebhookArgs{"1": {Enable: true, Endpoint: *endpoint, BatchSize: -1}}}, true},
+		// Missing path.
+		{Config{File: map[string]FileArgs{"1": {Enable: true}}}, true},
+		// Invalid maximum size.
+		{Config{File: map[string]FileArgs{"1": {Enable: true, Path: "/var/log/minio/audit.log", MaxSize: -1}}}, true},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+	}
+}
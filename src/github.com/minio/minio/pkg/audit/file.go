@@ -0,0 +1,180 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Default maximum size of a log file in megabytes.
+	defaultFileMaxSize = 100
+
+	// Default number of rotated log files kept.
+	defaultFileMaxBackups = 10
+
+	// Suffix format of rotated log files.
+	fileBackupTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// FileArgs - local file target arguments.
+type FileArgs struct {
+	Enable     bool   `json:"enable"`
+	Path       string `json:"path"`
+	MaxSize    int64  `json:"maxSize"`
+	MaxBackups int    `json:"maxBackups"`
+}
+
+// Validate - validates the arguments of an enabled target.
+func (args FileArgs) Validate() error {
+	if !args.Enable {
+		return nil
+	}
+	if args.Path == "" {
+		return errors.New("empty audit file path")
+	}
+	if args.MaxSize < 0 {
+		return errors.New("invalid audit file maximum size")
+	}
+	if args.MaxBackups < 0 {
+		return errors.New("invalid audit file maximum backups")
+	}
+	return nil
+}
+
+// FileTarget - writes audit log entries as JSON lines to a local file,
+// which is rotated once it reaches its maximum size.
+type FileTarget struct {
+	sync.Mutex
+	id      string
+	args    FileArgs
+	maxSize int64
+	file    *os.File
+	size    int64
+}
+
+// ID - returns target ID.
+func (target *FileTarget) ID() string {
+	return target.id
+}
+
+// Send - writes entry to the log file.
+func (target *FileTarget) Send(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	target.Lock()
+	defer target.Unlock()
+
+	if target.size > 0 && target.size+int64(len(data)) > target.maxSize {
+		if err = target.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := target.file.Write(data)
+	target.size += int64(n)
+	return err
+}
+
+// Close - closes the log file.
+func (target *FileTarget) Close() error {
+	target.Lock()
+	defer target.Unlock()
+
+	return target.file.Close()
+}
+
+// open - opens the log file for appending.
+func (target *FileTarget) open() error {
+	if err := os.MkdirAll(filepath.Dir(target.args.Path), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target.args.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	target.file = file
+	target.size = fi.Size()
+	return nil
+}
+
+// rotate - renames the log file with a time suffix, removes the oldest
+// rotated files and opens a new log file.
+func (target *FileTarget) rotate() error {
+	if err := target.file.Close(); err != nil {
+		return err
+	}
+
+	backupPath := target.args.Path + "." + time.Now().UTC().Format(fileBackupTimeFormat)
+	if err := os.Rename(target.args.Path, backupPath); err != nil {
+		return err
+	}
+
+	// Time suffixes of rotated files sort in time order.
+	backups, err := filepath.Glob(target.args.Path + ".*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(backups)
+	for len(backups) > target.args.MaxBackups {
+		if err = os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+
+	return target.open()
+}
+
+// NewFileTarget - creates new local file target.
+func NewFileTarget(id string, args FileArgs) (*FileTarget, error) {
+	if args.MaxSize == 0 {
+		args.MaxSize = defaultFileMaxSize
+	}
+	if args.MaxBackups == 0 {
+		args.MaxBackups = defaultFileMaxBackups
+	}
+
+	target := &FileTarget{
+		id:      id,
+		args:    args,
+		maxSize: args.MaxSize * 1024 * 1024,
+	}
+	if err := target.open(); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
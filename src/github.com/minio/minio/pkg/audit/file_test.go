@@ -0,0 +1,81 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs", "audit.log")
+	target, err := NewFileTarget("1", FileArgs{Enable: true, Path: path, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rotate after every two entries.
+	data, err := json.Marshal(Entry{Version: Version, API: API{Name: "PutObject"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target.maxSize = 2 * int64(len(data)+1)
+
+	for i := 0; i < 9; i++ {
+		if err = target.Send(Entry{Version: Version, API: API{Name: "PutObject"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = target.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The log file has the last entry, older entries are rotated
+	// and only two rotated files are kept.
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); lines++ {
+		var entry Entry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lines != 1 {
+		t.Fatalf("expected 1 entry in log file, got %d", lines)
+	}
+
+	backups, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v", backups)
+	}
+}
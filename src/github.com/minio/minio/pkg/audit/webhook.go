@@ -0,0 +1,231 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	xnet "github.com/minio/minio/pkg/net"
+)
+
+const (
+	// Default number of entries sent in one request.
+	defaultWebhookBatchSize = 100
+
+	// Default number of retries of failed requests.
+	defaultWebhookMaxRetry = 3
+
+	// Number of entries buffered before entries are dropped.
+	webhookQueueSize = 10000
+)
+
+// Interval of sending incomplete batches, and first interval of
+// retrying failed requests which doubles on each retry.
+var (
+	webhookFlushInterval = 5 * time.Second
+	webhookRetryInterval = time.Second
+)
+
+// WebhookArgs - HTTP webhook target arguments.
+type WebhookArgs struct {
+	Enable    bool           `json:"enable"`
+	Endpoint  xnet.URL       `json:"endpoint"`
+	BatchSize int            `json:"batchSize"`
+	MaxRetry  int            `json:"maxRetry"`
+	RootCAs   *x509.CertPool `json:"-"`
+}
+
+// Validate - validates the arguments of an enabled target.
+func (args WebhookArgs) Validate() error {
+	if !args.Enable {
+		return nil
+	}
+	if args.Endpoint.IsEmpty() {
+		return errors.New("empty audit webhook endpoint")
+	}
+	if args.BatchSize < 0 {
+		return errors.New("invalid audit webhook batch size")
+	}
+	if args.MaxRetry < 0 {
+		return errors.New("invalid audit webhook retry count")
+	}
+	return nil
+}
+
+// WebhookTarget - sends batches of audit log entries as JSON arrays to
+// an HTTP endpoint.
+type WebhookTarget struct {
+	id         string
+	args       WebhookArgs
+	httpClient *http.Client
+	logError   func(error)
+
+	entryCh chan Entry
+	doneCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// ID - returns target ID.
+func (target *WebhookTarget) ID() string {
+	return target.id
+}
+
+// Send - queues entry to be sent in the next batch.
+func (target *WebhookTarget) Send(entry Entry) error {
+	select {
+	case target.entryCh <- entry:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close - sends queued entries and stops the target.
+func (target *WebhookTarget) Close() error {
+	close(target.doneCh)
+	target.wg.Wait()
+	return nil
+}
+
+// run - sends queued entries in batches until the target is closed.
+func (target *WebhookTarget) run() {
+	defer target.wg.Done()
+
+	ticker := time.NewTicker(webhookFlushInterval)
+	defer ticker.Stop()
+
+	var batch []Entry
+	for {
+		select {
+		case entry := <-target.entryCh:
+			batch = append(batch, entry)
+			if len(batch) < target.args.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-target.doneCh:
+			for len(target.entryCh) > 0 {
+				batch = append(batch, <-target.entryCh)
+			}
+			for len(batch) > target.args.BatchSize {
+				target.sendWithRetry(batch[:target.args.BatchSize])
+				batch = batch[target.args.BatchSize:]
+			}
+			target.sendWithRetry(batch)
+			return
+		}
+
+		target.sendWithRetry(batch)
+		batch = nil
+	}
+}
+
+// sendWithRetry - sends a batch, retrying failed requests.
+func (target *WebhookTarget) sendWithRetry(batch []Entry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	retryInterval := webhookRetryInterval
+	for retry := 0; ; retry++ {
+		err := target.send(batch)
+		if err == nil {
+			return
+		}
+		if retry == target.args.MaxRetry {
+			target.logError(fmt.Errorf("audit webhook %s: dropped %d entries: %v", target.id, len(batch), err))
+			return
+		}
+
+		select {
+		case <-time.After(retryInterval):
+		case <-target.doneCh:
+			// Retry once more without waiting on close.
+		}
+		retryInterval *= 2
+	}
+}
+
+// send - sends a batch in one request.
+func (target *WebhookTarget) send(batch []Entry) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", target.args.Endpoint.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := target.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sending audit log failed with %v", resp.Status)
+	}
+
+	return nil
+}
+
+// NewWebhookTarget - creates new HTTP webhook target, which reports
+// entries it fails to send to logError.
+func NewWebhookTarget(id string, args WebhookArgs, logError func(error)) *WebhookTarget {
+	if args.BatchSize == 0 {
+		args.BatchSize = defaultWebhookBatchSize
+	}
+	if args.MaxRetry == 0 {
+		args.MaxRetry = defaultWebhookMaxRetry
+	}
+
+	target := &WebhookTarget{
+		id:   id,
+		args: args,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: args.RootCAs},
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 5 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   3 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second,
+				ExpectContinueTimeout: 2 * time.Second,
+			},
+		},
+		logError: logError,
+		entryCh:  make(chan Entry, webhookQueueSize),
+		doneCh:   make(chan struct{}),
+	}
+
+	target.wg.Add(1)
+	go target.run()
+	return target
+}
@@ -0,0 +1,110 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	xnet "github.com/minio/minio/pkg/net"
+)
+
+func TestWebhookTarget(t *testing.T) {
+	defer func(interval time.Duration) { webhookRetryInterval = interval }(webhookRetryInterval)
+	webhookRetryInterval = time.Millisecond
+
+	var mu sync.Mutex
+	var batches [][]Entry
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Fail the first request to test retries.
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var batch []Entry
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Error(err)
+		}
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	endpoint, err := xnet.ParseURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []error
+	target := NewWebhookTarget("1", WebhookArgs{Enable: true, Endpoint: *endpoint, BatchSize: 2}, func(err error) {
+		errs = append(errs, err)
+	})
+	for _, name := range []string{"PutObject", "GetObject", "DeleteObject"} {
+		if err = target.Send(Entry{Version: Version, API: API{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Incomplete batches are sent on close.
+	target.Close()
+
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Fatalf("unexpected batches: %v", batches)
+	}
+	if batches[0][0].API.Name != "PutObject" || batches[1][0].API.Name != "DeleteObject" {
+		t.Fatalf("unexpected batches: %v", batches)
+	}
+}
+
+func TestWebhookTargetDropsEntries(t *testing.T) {
+	defer func(interval time.Duration) { webhookRetryInterval = interval }(webhookRetryInterval)
+	webhookRetryInterval = time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	endpoint, err := xnet.ParseURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []error
+	target := NewWebhookTarget("1", WebhookArgs{Enable: true, Endpoint: *endpoint, BatchSize: 1, MaxRetry: 2}, func(err error) {
+		errs = append(errs, err)
+	})
+	if err = target.Send(Entry{Version: Version, API: API{Name: "PutObject"}}); err != nil {
+		t.Fatal(err)
+	}
+	target.Close()
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"time"
 )
 
 const (
@@ -44,6 +45,9 @@ const (
 
 	// Total length of the alpha numeric table.
 	alphaNumericTableLen = byte(len(alphaNumericTable))
+
+	// Length of the session token of temporary credentials.
+	sessionTokenLen = 64
 )
 
 // Common errors generated for access and secret key validation.
@@ -62,10 +66,13 @@ func isSecretKeyValid(secretKey string) bool {
 	return len(secretKey) >= secretKeyMinLen
 }
 
-// Credentials holds access and secret keys.
+// Credentials holds access and secret keys. Temporary credentials
+// additionally hold a session token and an expiry.
 type Credentials struct {
-	AccessKey string `json:"accessKey,omitempty"`
-	SecretKey string `json:"secretKey,omitempty"`
+	AccessKey    string     `xml:"AccessKeyId" json:"accessKey,omitempty"`
+	SecretKey    string     `xml:"SecretAccessKey" json:"secretKey,omitempty"`
+	Expiration   *time.Time `xml:"Expiration,omitempty" json:"expiration,omitempty"`
+	SessionToken string     `xml:"SessionToken,omitempty" json:"sessionToken,omitempty"`
 }
 
 // IsValid - returns whether credential is valid or not.
@@ -73,6 +80,17 @@ func (cred Credentials) IsValid() bool {
 	return IsAccessKeyValid(cred.AccessKey) && isSecretKeyValid(cred.SecretKey)
 }
 
+// IsTemp - returns whether credential is temporary or not.
+func (cred Credentials) IsTemp() bool {
+	return cred.SessionToken != ""
+}
+
+// IsExpired - returns whether credential has expired or not. Static
+// credentials never expire.
+func (cred Credentials) IsExpired() bool {
+	return cred.Expiration != nil && !time.Now().Before(*cred.Expiration)
+}
+
 // Equal - returns whether two credentials are equal or not.
 func (cred Credentials) Equal(ccred Credentials) bool {
 	if !ccred.IsValid() {
@@ -81,19 +99,38 @@ func (cred Credentials) Equal(ccred Credentials) bool {
 	return cred.AccessKey == ccred.AccessKey && subtle.ConstantTimeCompare([]byte(cred.SecretKey), []byte(ccred.SecretKey)) == 1
 }
 
-// GetNewCredentials generates and returns new credential.
-func GetNewCredentials() (cred Credentials, err error) {
-	readBytes := func(size int) (data []byte, err error) {
-		data = make([]byte, size)
-		var n int
-		if n, err = rand.Read(data); err != nil {
-			return nil, err
-		} else if n != size {
-			return nil, fmt.Errorf("Not enough data. Expected to read: %v bytes, got: %v bytes", size, n)
-		}
-		return data, nil
+// readBytes - reads size random bytes.
+func readBytes(size int) (data []byte, err error) {
+	data = make([]byte, size)
+	var n int
+	if n, err = rand.Read(data); err != nil {
+		return nil, err
+	} else if n != size {
+		return nil, fmt.Errorf("Not enough data. Expected to read: %v bytes, got: %v bytes", size, n)
+	}
+	return data, nil
+}
+
+// GetNewTempCredentials generates and returns new temporary
+// credential which expires at given time.
+func GetNewTempCredentials(expiration time.Time) (cred Credentials, err error) {
+	if cred, err = GetNewCredentials(); err != nil {
+		return cred, err
+	}
+
+	tokenBytes, err := readBytes(sessionTokenLen)
+	if err != nil {
+		return cred, err
 	}
+	cred.SessionToken = base64.RawURLEncoding.EncodeToString(tokenBytes)
 
+	expiration = expiration.UTC()
+	cred.Expiration = &expiration
+	return cred, nil
+}
+
+// GetNewCredentials generates and returns new credential.
+func GetNewCredentials() (cred Credentials, err error) {
 	// Generate access key.
 	keyBytes, err := readBytes(accessKeyMaxLen)
 	if err != nil {
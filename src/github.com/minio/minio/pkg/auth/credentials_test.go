@@ -16,7 +16,10 @@
 
 package auth
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestIsAccessKeyValid(t *testing.T) {
 	testCases := []struct {
@@ -70,6 +73,34 @@ func TestGetNewCredentials(t *testing.T) {
 	}
 }
 
+func TestGetNewTempCredentials(t *testing.T) {
+	cred, err := GetNewTempCredentials(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get a new temporary credential")
+	}
+	if !cred.IsValid() || !cred.IsTemp() {
+		t.Fatalf("Failed to get new valid temporary credential")
+	}
+	if cred.IsExpired() {
+		t.Fatalf("Expected credential to expire in an hour")
+	}
+
+	cred, err = GetNewTempCredentials(time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatalf("Failed to get a new temporary credential")
+	}
+	if !cred.IsExpired() {
+		t.Fatalf("Expected credential to be expired")
+	}
+
+	if cred, err = GetNewCredentials(); err != nil {
+		t.Fatalf("Failed to get a new credential")
+	}
+	if cred.IsTemp() || cred.IsExpired() {
+		t.Fatalf("Expected static credential to never expire")
+	}
+}
+
 func TestCreateCredentials(t *testing.T) {
 	testCases := []struct {
 		accessKey   string
@@ -0,0 +1,182 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cors implements the cross-origin resource sharing (CORS)
+// configuration of buckets.
+package cors
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// Maximum number of rules of a CORS configuration.
+const maxRules = 100
+
+// ErrInvalidRules - the configuration does not contain between 1 and 100 rules.
+var ErrInvalidRules = errors.New("between 1 and 100 CORS rules must be specified")
+
+// Rule - CORS rule, allowing cross-origin requests of its origins.
+type Rule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty"`
+}
+
+// isAllowedMethod - returns whether method is a valid CORS method.
+func isAllowedMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodPost, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Validate - validates CORS rule.
+func (r Rule) Validate() error {
+	if len(r.ID) > 255 {
+		return errors.New("CORS rule ID must not be longer than 255 characters")
+	}
+	if len(r.AllowedOrigins) == 0 {
+		return errors.New("CORS rule must have at least one AllowedOrigin")
+	}
+	for _, origin := range r.AllowedOrigins {
+		if strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("AllowedOrigin '%v' can not have more than one wildcard", origin)
+		}
+	}
+	if len(r.AllowedMethods) == 0 {
+		return errors.New("CORS rule must have at least one AllowedMethod")
+	}
+	for _, method := range r.AllowedMethods {
+		if !isAllowedMethod(method) {
+			return fmt.Errorf("unsupported AllowedMethod '%v'", method)
+		}
+	}
+	for _, header := range r.AllowedHeaders {
+		if strings.Count(header, "*") > 1 {
+			return fmt.Errorf("AllowedHeader '%v' can not have more than one wildcard", header)
+		}
+	}
+	if r.MaxAgeSeconds < 0 {
+		return errors.New("MaxAgeSeconds must not be negative")
+	}
+	return nil
+}
+
+// matchOrigin - returns whether origin is allowed by the rule.
+func (r Rule) matchOrigin(origin string) bool {
+	for _, allowed := range r.AllowedOrigins {
+		if wildcard.MatchSimple(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMethod - returns whether method is allowed by the rule.
+func (r Rule) matchMethod(method string) bool {
+	for _, allowed := range r.AllowedMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHeaders - returns whether all headers are allowed by the rule.
+// Header names are case insensitive.
+func (r Rule) matchHeaders(headers []string) bool {
+	for _, header := range headers {
+		header = strings.ToLower(header)
+		found := false
+		for _, allowed := range r.AllowedHeaders {
+			if wildcard.MatchSimple(strings.ToLower(allowed), header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAnyOrigin - returns whether the rule allows all origins, in which
+// case responses allow any origin instead of echoing it.
+func (r Rule) IsAnyOrigin() bool {
+	for _, allowed := range r.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Config - bucket CORS configuration.
+type Config struct {
+	XMLName xml.Name `xml:"CORSConfiguration"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	Rules   []Rule   `xml:"CORSRule"`
+}
+
+// Validate - validates CORS configuration.
+func (c Config) Validate() error {
+	if len(c.Rules) == 0 || len(c.Rules) > maxRules {
+		return ErrInvalidRules
+	}
+	for _, rule := range c.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match - returns the first rule allowing a request from origin with
+// method and headers.
+func (c Config) Match(origin, method string, headers []string) (Rule, bool) {
+	for _, rule := range c.Rules {
+		if rule.matchOrigin(origin) && rule.matchMethod(method) && rule.matchHeaders(headers) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data          string
+		expectedRules int
+		expectErr     bool
+	}{
+		{`<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><CORSRule><AllowedOrigin>http://www.example.com</AllowedOrigin><AllowedMethod>PUT</AllowedMethod><AllowedMethod>POST</AllowedMethod><AllowedHeader>*</AllowedHeader><ExposeHeader>x-amz-request-id</ExposeHeader><MaxAgeSeconds>3000</MaxAgeSeconds></CORSRule></CORSConfiguration>`, 1, false},
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule><CORSRule><AllowedOrigin>https://*.example.com</AllowedOrigin><AllowedMethod>DELETE</AllowedMethod></CORSRule></CORSConfiguration>`, 2, false},
+		// Missing origin.
+		{`<CORSConfiguration><CORSRule><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, 0, true},
+		// Missing method.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin></CORSRule></CORSConfiguration>`, 0, true},
+		// Unsupported method.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>PATCH</AllowedMethod></CORSRule></CORSConfiguration>`, 0, true},
+		// Origin with multiple wildcards.
+		{`<CORSConfiguration><CORSRule><AllowedOrigin>http://*.*.com</AllowedOrigin><AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`, 0, true},
+		// Missing rule.
+		{`<CORSConfiguration></CORSConfiguration>`, 0, true},
+		// Invalid XML.
+		{`<CORSConfiguration>`, 0, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := ParseConfig(strings.NewReader(testCase.data))
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr && len(result.Rules) != testCase.expectedRules {
+			t.Fatalf("case %v: rules: expected: %v, got: %v\n", i+1, testCase.expectedRules, len(result.Rules))
+		}
+	}
+}
+
+func TestConfigMatch(t *testing.T) {
+	config := Config{Rules: []Rule{
+		{
+			ID:             "uploads",
+			AllowedOrigins: []string{"https://*.example.com"},
+			AllowedMethods: []string{"PUT", "POST"},
+			AllowedHeaders: []string{"Content-*", "x-amz-*"},
+		},
+		{
+			ID:             "reads",
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "HEAD"},
+		},
+	}}
+
+	testCases := []struct {
+		origin     string
+		method     string
+		headers    []string
+		expectedID string
+		expectedOk bool
+	}{
+		{"https://www.example.com", "PUT", nil, "uploads", true},
+		{"https://www.example.com", "POST", []string{"content-type", "X-Amz-Date"}, "uploads", true},
+		{"https://www.example.com", "PUT", []string{"authorization"}, "", false},
+		{"http://www.example.com", "PUT", nil, "", false},
+		{"http://other.org", "GET", nil, "reads", true},
+		{"http://other.org", "GET", []string{"range"}, "", false},
+		{"http://other.org", "DELETE", nil, "", false},
+	}
+
+	for i, testCase := range testCases {
+		rule, ok := config.Match(testCase.origin, testCase.method, testCase.headers)
+		if ok != testCase.expectedOk {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedOk, ok)
+		}
+		if rule.ID != testCase.expectedID {
+			t.Fatalf("case %v: rule: expected: %v, got: %v\n", i+1, testCase.expectedID, rule.ID)
+		}
+	}
+
+	if rule, _ := config.Match("http://other.org", "GET", nil); !rule.IsAnyOrigin() {
+		t.Fatal("Expected rule to allow any origin")
+	}
+}
@@ -37,22 +37,39 @@ const (
 	ObjectCreatedPut
 	ObjectRemovedAll
 	ObjectRemovedDelete
+	ObjectRestoreAll
+	ObjectRestorePost
+	ObjectRestoreCompleted
+	ObjectAccessedSelect
+	ObjectCreatedPutRetention
+	ObjectRemovedDeleteMarkerCreated
+	ObjectRemovedExpired
+	BucketCreated
+	BucketRemoved
 )
 
 // Expand - returns expanded values of abbreviated event type.
 func (name Name) Expand() []Name {
 	switch name {
 	case ObjectAccessedAll:
-		return []Name{ObjectAccessedGet, ObjectAccessedHead}
+		return []Name{ObjectAccessedGet, ObjectAccessedHead, ObjectAccessedSelect}
 	case ObjectCreatedAll:
-		return []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut}
+		return []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut, ObjectCreatedPutRetention}
 	case ObjectRemovedAll:
-		return []Name{ObjectRemovedDelete}
+		return []Name{ObjectRemovedDelete, ObjectRemovedDeleteMarkerCreated, ObjectRemovedExpired}
+	case ObjectRestoreAll:
+		return []Name{ObjectRestorePost, ObjectRestoreCompleted}
 	default:
 		return []Name{name}
 	}
 }
 
+// IsBucketEvent - returns whether event type is about a bucket rather
+// than an object in it.
+func (name Name) IsBucketEvent() bool {
+	return name == BucketCreated || name == BucketRemoved
+}
+
 // String - returns string representation of event type.
 func (name Name) String() string {
 	switch name {
@@ -62,6 +79,8 @@ func (name Name) String() string {
 		return "s3:ObjectAccessed:Get"
 	case ObjectAccessedHead:
 		return "s3:ObjectAccessed:Head"
+	case ObjectAccessedSelect:
+		return "s3:ObjectAccessed:Select"
 	case ObjectCreatedAll:
 		return "s3:ObjectCreated:*"
 	case ObjectCreatedCompleteMultipartUpload:
@@ -72,10 +91,26 @@ func (name Name) String() string {
 		return "s3:ObjectCreated:Post"
 	case ObjectCreatedPut:
 		return "s3:ObjectCreated:Put"
+	case ObjectCreatedPutRetention:
+		return "s3:ObjectCreated:PutRetention"
 	case ObjectRemovedAll:
 		return "s3:ObjectRemoved:*"
 	case ObjectRemovedDelete:
 		return "s3:ObjectRemoved:Delete"
+	case ObjectRemovedDeleteMarkerCreated:
+		return "s3:ObjectRemoved:DeleteMarkerCreated"
+	case ObjectRemovedExpired:
+		return "s3:ObjectRemoved:Expired"
+	case ObjectRestoreAll:
+		return "s3:ObjectRestore:*"
+	case ObjectRestorePost:
+		return "s3:ObjectRestore:Post"
+	case ObjectRestoreCompleted:
+		return "s3:ObjectRestore:Completed"
+	case BucketCreated:
+		return "s3:BucketCreated"
+	case BucketRemoved:
+		return "s3:BucketRemoved"
 	}
 
 	return ""
@@ -132,6 +167,8 @@ func ParseName(s string) (Name, error) {
 		return ObjectAccessedGet, nil
 	case "s3:ObjectAccessed:Head":
 		return ObjectAccessedHead, nil
+	case "s3:ObjectAccessed:Select":
+		return ObjectAccessedSelect, nil
 	case "s3:ObjectCreated:*":
 		return ObjectCreatedAll, nil
 	case "s3:ObjectCreated:CompleteMultipartUpload":
@@ -142,10 +179,26 @@ func ParseName(s string) (Name, error) {
 		return ObjectCreatedPost, nil
 	case "s3:ObjectCreated:Put":
 		return ObjectCreatedPut, nil
+	case "s3:ObjectCreated:PutRetention":
+		return ObjectCreatedPutRetention, nil
 	case "s3:ObjectRemoved:*":
 		return ObjectRemovedAll, nil
 	case "s3:ObjectRemoved:Delete":
 		return ObjectRemovedDelete, nil
+	case "s3:ObjectRemoved:DeleteMarkerCreated":
+		return ObjectRemovedDeleteMarkerCreated, nil
+	case "s3:ObjectRemoved:Expired":
+		return ObjectRemovedExpired, nil
+	case "s3:ObjectRestore:*":
+		return ObjectRestoreAll, nil
+	case "s3:ObjectRestore:Post":
+		return ObjectRestorePost, nil
+	case "s3:ObjectRestore:Completed":
+		return ObjectRestoreCompleted, nil
+	case "s3:BucketCreated":
+		return BucketCreated, nil
+	case "s3:BucketRemoved":
+		return BucketRemoved, nil
 	default:
 		return 0, &ErrInvalidEventName{s}
 	}
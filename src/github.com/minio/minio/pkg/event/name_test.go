@@ -28,10 +28,12 @@ func TestNameExpand(t *testing.T) {
 		name           Name
 		expectedResult []Name
 	}{
-		{ObjectAccessedAll, []Name{ObjectAccessedGet, ObjectAccessedHead}},
-		{ObjectCreatedAll, []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut}},
-		{ObjectRemovedAll, []Name{ObjectRemovedDelete}},
+		{ObjectAccessedAll, []Name{ObjectAccessedGet, ObjectAccessedHead, ObjectAccessedSelect}},
+		{ObjectCreatedAll, []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut, ObjectCreatedPutRetention}},
+		{ObjectRemovedAll, []Name{ObjectRemovedDelete, ObjectRemovedDeleteMarkerCreated, ObjectRemovedExpired}},
+		{ObjectRestoreAll, []Name{ObjectRestorePost, ObjectRestoreCompleted}},
 		{ObjectAccessedHead, []Name{ObjectAccessedHead}},
+		{BucketCreated, []Name{BucketCreated}},
 	}
 
 	for i, testCase := range testCases {
@@ -53,13 +55,22 @@ func TestNameString(t *testing.T) {
 		{ObjectAccessedAll, "s3:ObjectAccessed:*"},
 		{ObjectAccessedGet, "s3:ObjectAccessed:Get"},
 		{ObjectAccessedHead, "s3:ObjectAccessed:Head"},
+		{ObjectAccessedSelect, "s3:ObjectAccessed:Select"},
 		{ObjectCreatedAll, "s3:ObjectCreated:*"},
 		{ObjectCreatedCompleteMultipartUpload, "s3:ObjectCreated:CompleteMultipartUpload"},
 		{ObjectCreatedCopy, "s3:ObjectCreated:Copy"},
 		{ObjectCreatedPost, "s3:ObjectCreated:Post"},
 		{ObjectCreatedPut, "s3:ObjectCreated:Put"},
+		{ObjectCreatedPutRetention, "s3:ObjectCreated:PutRetention"},
 		{ObjectRemovedAll, "s3:ObjectRemoved:*"},
 		{ObjectRemovedDelete, "s3:ObjectRemoved:Delete"},
+		{ObjectRemovedDeleteMarkerCreated, "s3:ObjectRemoved:DeleteMarkerCreated"},
+		{ObjectRemovedExpired, "s3:ObjectRemoved:Expired"},
+		{ObjectRestoreAll, "s3:ObjectRestore:*"},
+		{ObjectRestorePost, "s3:ObjectRestore:Post"},
+		{ObjectRestoreCompleted, "s3:ObjectRestore:Completed"},
+		{BucketCreated, "s3:BucketCreated"},
+		{BucketRemoved, "s3:BucketRemoved"},
 		{blankName, ""},
 	}
 
@@ -200,6 +211,10 @@ func TestParseName(t *testing.T) {
 	}{
 		{"s3:ObjectAccessed:*", ObjectAccessedAll, false},
 		{"s3:ObjectRemoved:Delete", ObjectRemovedDelete, false},
+		{"s3:ObjectRemoved:DeleteMarkerCreated", ObjectRemovedDeleteMarkerCreated, false},
+		{"s3:ObjectCreated:PutRetention", ObjectCreatedPutRetention, false},
+		{"s3:BucketCreated", BucketCreated, false},
+		{"s3:ObjectRestore:Completed", ObjectRestoreCompleted, false},
 		{"", blankName, true},
 	}
 
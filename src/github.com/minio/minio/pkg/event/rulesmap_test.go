@@ -153,10 +153,10 @@ func TestRulesMapMatch(t *testing.T) {
 
 func TestNewRulesMap(t *testing.T) {
 	rulesMapCase1 := make(RulesMap)
-	rulesMapCase1.add([]Name{ObjectAccessedGet, ObjectAccessedHead}, "*", TargetID{"1", "webhook"})
+	rulesMapCase1.add([]Name{ObjectAccessedGet, ObjectAccessedHead, ObjectAccessedSelect}, "*", TargetID{"1", "webhook"})
 
 	rulesMapCase2 := make(RulesMap)
-	rulesMapCase2.add([]Name{ObjectAccessedGet, ObjectAccessedHead, ObjectCreatedPut}, "*", TargetID{"1", "webhook"})
+	rulesMapCase2.add([]Name{ObjectAccessedGet, ObjectAccessedHead, ObjectAccessedSelect, ObjectCreatedPut}, "*", TargetID{"1", "webhook"})
 
 	rulesMapCase3 := make(RulesMap)
 	rulesMapCase3.add([]Name{ObjectRemovedDelete}, "2010*.jpg", TargetID{"1", "webhook"})
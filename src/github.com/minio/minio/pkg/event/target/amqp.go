@@ -41,6 +41,8 @@ type AMQPArgs struct {
 	Internal     bool     `json:"internal"`
 	NoWait       bool     `json:"noWait"`
 	AutoDeleted  bool     `json:"autoDeleted"`
+	QueueDir     string   `json:"queueDir"`
+	QueueLimit   uint64   `json:"queueLimit"`
 }
 
 // AMQPTarget - AMQP target
@@ -49,6 +51,7 @@ type AMQPTarget struct {
 	args      AMQPArgs
 	conn      *amqp.Connection
 	connMutex sync.Mutex
+	store     *QueueStore
 }
 
 // ID - returns TargetID.
@@ -69,6 +72,10 @@ func (target *AMQPTarget) channel() (*amqp.Channel, error) {
 		return false
 	}
 
+	if err := target.connect(); err != nil {
+		return nil, err
+	}
+
 	target.connMutex.Lock()
 	defer target.connMutex.Unlock()
 
@@ -95,8 +102,36 @@ func (target *AMQPTarget) channel() (*amqp.Channel, error) {
 	return ch, nil
 }
 
-// Send - sends event to AMQP.
+// connect - connects to AMQP unless already connected.
+func (target *AMQPTarget) connect() error {
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
+
+	if target.conn != nil {
+		return nil
+	}
+
+	conn, err := amqp.Dial(target.args.URL.String())
+	if err != nil {
+		return err
+	}
+	target.conn = conn
+
+	return nil
+}
+
+// Send - sends event to AMQP. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
 func (target *AMQPTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to AMQP.
+func (target *AMQPTarget) send(eventData event.Event) error {
 	ch, err := target.channel()
 	if err != nil {
 		return err
@@ -130,21 +165,27 @@ func (target *AMQPTarget) Send(eventData event.Event) error {
 		})
 }
 
-// Close - does nothing and available for interface compatibility.
+// Close - stops sending stored events.
 func (target *AMQPTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
 	return nil
 }
 
 // NewAMQPTarget - creates new AMQP target.
 func NewAMQPTarget(id string, args AMQPArgs) (*AMQPTarget, error) {
-	conn, err := amqp.Dial(args.URL.String())
+	target := &AMQPTarget{
+		id:   event.TargetID{id, "amqp"},
+		args: args,
+	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
 	if err != nil {
 		return nil, err
 	}
+	target.store = store
 
-	return &AMQPTarget{
-		id:   event.TargetID{id, "amqp"},
-		args: args,
-		conn: conn,
-	}, nil
+	return target, nil
 }
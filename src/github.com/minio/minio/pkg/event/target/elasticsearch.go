@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/minio/minio/pkg/event"
@@ -30,17 +31,21 @@ import (
 
 // ElasticsearchArgs - Elasticsearch target arguments.
 type ElasticsearchArgs struct {
-	Enable bool     `json:"enable"`
-	Format string   `json:"format"`
-	URL    xnet.URL `json:"url"`
-	Index  string   `json:"index"`
+	Enable     bool     `json:"enable"`
+	Format     string   `json:"format"`
+	URL        xnet.URL `json:"url"`
+	Index      string   `json:"index"`
+	QueueDir   string   `json:"queueDir"`
+	QueueLimit uint64   `json:"queueLimit"`
 }
 
 // ElasticsearchTarget - Elasticsearch target.
 type ElasticsearchTarget struct {
-	id     event.TargetID
-	args   ElasticsearchArgs
-	client *elastic.Client
+	id        event.TargetID
+	args      ElasticsearchArgs
+	client    *elastic.Client
+	connMutex sync.Mutex
+	store     *QueueStore
 }
 
 // ID - returns target ID.
@@ -48,8 +53,22 @@ func (target *ElasticsearchTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to Elasticsearch.
-func (target *ElasticsearchTarget) Send(eventData event.Event) (err error) {
+// Send - sends event to Elasticsearch. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
+func (target *ElasticsearchTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to Elasticsearch.
+func (target *ElasticsearchTarget) send(eventData event.Event) (err error) {
+	if err = target.connect(); err != nil {
+		return err
+	}
+
 	var key string
 
 	remove := func() error {
@@ -96,37 +115,62 @@ func (target *ElasticsearchTarget) Send(eventData event.Event) (err error) {
 	return nil
 }
 
-// Close - does nothing and available for interface compatibility.
+// Close - stops sending stored events.
 func (target *ElasticsearchTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
 	return nil
 }
 
-// NewElasticsearchTarget - creates new Elasticsearch target.
-func NewElasticsearchTarget(id string, args ElasticsearchArgs) (*ElasticsearchTarget, error) {
-	client, err := elastic.NewClient(elastic.SetURL(args.URL.String()), elastic.SetSniff(false), elastic.SetMaxRetries(10))
+// connect - connects to Elasticsearch and creates the index unless
+// already connected.
+func (target *ElasticsearchTarget) connect() error {
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
+
+	if target.client != nil {
+		return nil
+	}
+
+	client, err := elastic.NewClient(elastic.SetURL(target.args.URL.String()), elastic.SetSniff(false), elastic.SetMaxRetries(10))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	exists, err := client.IndexExists(args.Index).Do(context.Background())
+	exists, err := client.IndexExists(target.args.Index).Do(context.Background())
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if !exists {
 		var createIndex *elastic.IndicesCreateResult
-		if createIndex, err = client.CreateIndex(args.Index).Do(context.Background()); err != nil {
-			return nil, err
+		if createIndex, err = client.CreateIndex(target.args.Index).Do(context.Background()); err != nil {
+			return err
 		}
 
 		if !createIndex.Acknowledged {
-			return nil, fmt.Errorf("index %v not created", args.Index)
+			return fmt.Errorf("index %v not created", target.args.Index)
 		}
 	}
 
-	return &ElasticsearchTarget{
-		id:     event.TargetID{id, "elasticsearch"},
-		args:   args,
-		client: client,
-	}, nil
+	target.client = client
+	return nil
+}
+
+// NewElasticsearchTarget - creates new Elasticsearch target.
+func NewElasticsearchTarget(id string, args ElasticsearchArgs) (*ElasticsearchTarget, error) {
+	target := &ElasticsearchTarget{
+		id:   event.TargetID{id, "elasticsearch"},
+		args: args,
+	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
+	if err != nil {
+		return nil, err
+	}
+	target.store = store
+
+	return target, nil
 }
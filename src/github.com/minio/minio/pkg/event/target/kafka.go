@@ -19,6 +19,7 @@ package target
 import (
 	"encoding/json"
 	"net/url"
+	"sync"
 
 	"github.com/minio/minio/pkg/event"
 	xnet "github.com/minio/minio/pkg/net"
@@ -28,16 +29,20 @@ import (
 
 // KafkaArgs - Kafka target arguments.
 type KafkaArgs struct {
-	Enable  bool        `json:"enable"`
-	Brokers []xnet.Host `json:"brokers"`
-	Topic   string      `json:"topic"`
+	Enable     bool        `json:"enable"`
+	Brokers    []xnet.Host `json:"brokers"`
+	Topic      string      `json:"topic"`
+	QueueDir   string      `json:"queueDir"`
+	QueueLimit uint64      `json:"queueLimit"`
 }
 
 // KafkaTarget - Kafka target.
 type KafkaTarget struct {
-	id       event.TargetID
-	args     KafkaArgs
-	producer sarama.SyncProducer
+	id        event.TargetID
+	args      KafkaArgs
+	producer  sarama.SyncProducer
+	connMutex sync.Mutex
+	store     *QueueStore
 }
 
 // ID - returns target ID.
@@ -45,8 +50,22 @@ func (target *KafkaTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to Kafka.
+// Send - sends event to Kafka. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
 func (target *KafkaTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to Kafka.
+func (target *KafkaTarget) send(eventData event.Event) error {
+	if err := target.connect(); err != nil {
+		return err
+	}
+
 	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
 	if err != nil {
 		return err
@@ -70,28 +89,56 @@ func (target *KafkaTarget) Send(eventData event.Event) error {
 
 // Close - closes underneath kafka connection.
 func (target *KafkaTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
+	if target.producer == nil {
+		return nil
+	}
+
 	return target.producer.Close()
 }
 
-// NewKafkaTarget - creates new Kafka target.
-func NewKafkaTarget(id string, args KafkaArgs) (*KafkaTarget, error) {
+// connect - creates the Kafka producer unless already created.
+func (target *KafkaTarget) connect() error {
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
+
+	if target.producer != nil {
+		return nil
+	}
+
 	config := sarama.NewConfig()
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Producer.Retry.Max = 10
 	config.Producer.Return.Successes = true
 
 	brokers := []string{}
-	for _, broker := range args.Brokers {
+	for _, broker := range target.args.Brokers {
 		brokers = append(brokers, broker.String())
 	}
 	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return err
+	}
+	target.producer = producer
+
+	return nil
+}
+
+// NewKafkaTarget - creates new Kafka target.
+func NewKafkaTarget(id string, args KafkaArgs) (*KafkaTarget, error) {
+	target := &KafkaTarget{
+		id:   event.TargetID{id, "kafka"},
+		args: args,
+	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
 	if err != nil {
 		return nil, err
 	}
+	target.store = store
 
-	return &KafkaTarget{
-		id:       event.TargetID{id, "kafka"},
-		args:     args,
-		producer: producer,
-	}, nil
+	return target, nil
 }
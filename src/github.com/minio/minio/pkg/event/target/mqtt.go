@@ -40,6 +40,8 @@ type MQTTArgs struct {
 	MaxReconnectInterval time.Duration  `json:"reconnectInterval"`
 	KeepAlive            time.Duration  `json:"keepAliveInterval"`
 	RootCAs              *x509.CertPool `json:"-"`
+	QueueDir             string         `json:"queueDir"`
+	QueueLimit           uint64         `json:"queueLimit"`
 }
 
 // MQTTTarget - MQTT target.
@@ -47,6 +49,7 @@ type MQTTTarget struct {
 	id     event.TargetID
 	args   MQTTArgs
 	client mqtt.Client
+	store  *QueueStore
 }
 
 // ID - returns target ID.
@@ -54,15 +57,34 @@ func (target *MQTTTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to MQTT.
+// Send - sends event to MQTT. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
 func (target *MQTTTarget) Send(eventData event.Event) error {
-	if !target.client.IsConnected() {
-		token := target.client.Connect()
-		if token.Wait() {
-			if err := token.Error(); err != nil {
-				return err
-			}
-		}
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// connect - connects to MQTT unless already connected.
+func (target *MQTTTarget) connect() error {
+	if target.client.IsConnected() {
+		return nil
+	}
+
+	token := target.client.Connect()
+	if token.Wait() {
+		return token.Error()
+	}
+
+	return nil
+}
+
+// send - sends event to MQTT.
+func (target *MQTTTarget) send(eventData event.Event) error {
+	if err := target.connect(); err != nil {
+		return err
 	}
 
 	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
@@ -85,8 +107,12 @@ func (target *MQTTTarget) Send(eventData event.Event) error {
 	return nil
 }
 
-// Close - does nothing and available for interface compatibility.
+// Close - stops sending stored events.
 func (target *MQTTTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
 	return nil
 }
 
@@ -102,15 +128,17 @@ func NewMQTTTarget(id string, args MQTTArgs) (*MQTTTarget, error) {
 		SetTLSConfig(&tls.Config{RootCAs: args.RootCAs}).
 		AddBroker(args.Broker.String())
 
-	client := mqtt.NewClient(options)
-	token := client.Connect()
-	if token.Wait() && token.Error() != nil {
-		return nil, token.Error()
-	}
-
-	return &MQTTTarget{
+	target := &MQTTTarget{
 		id:     event.TargetID{id, "mqtt"},
 		args:   args,
-		client: client,
-	}, nil
+		client: mqtt.NewClient(options),
+	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
+	if err != nil {
+		return nil, err
+	}
+	target.store = store
+
+	return target, nil
 }
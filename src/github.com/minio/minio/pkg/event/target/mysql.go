@@ -58,6 +58,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -77,15 +78,17 @@ const (
 
 // MySQLArgs - MySQL target arguments.
 type MySQLArgs struct {
-	Enable   bool     `json:"enable"`
-	Format   string   `json:"format"`
-	DSN      string   `json:"dsnString"`
-	Table    string   `json:"table"`
-	Host     xnet.URL `json:"host"`
-	Port     string   `json:"port"`
-	User     string   `json:"user"`
-	Password string   `json:"password"`
-	Database string   `json:"database"`
+	Enable     bool     `json:"enable"`
+	Format     string   `json:"format"`
+	DSN        string   `json:"dsnString"`
+	Table      string   `json:"table"`
+	Host       xnet.URL `json:"host"`
+	Port       string   `json:"port"`
+	User       string   `json:"user"`
+	Password   string   `json:"password"`
+	Database   string   `json:"database"`
+	QueueDir   string   `json:"queueDir"`
+	QueueLimit uint64   `json:"queueLimit"`
 }
 
 // MySQLTarget - MySQL target.
@@ -96,6 +99,8 @@ type MySQLTarget struct {
 	deleteStmt *sql.Stmt
 	insertStmt *sql.Stmt
 	db         *sql.DB
+	connMutex  sync.Mutex
+	store      *QueueStore
 }
 
 // ID - returns target ID.
@@ -103,8 +108,22 @@ func (target *MySQLTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to MySQL.
+// Send - sends event to MySQL. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
 func (target *MySQLTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to MySQL.
+func (target *MySQLTarget) send(eventData event.Event) error {
+	if err := target.connect(); err != nil {
+		return err
+	}
+
 	if target.args.Format == event.NamespaceFormat {
 		objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
 		if err != nil {
@@ -145,6 +164,10 @@ func (target *MySQLTarget) Send(eventData event.Event) error {
 
 // Close - closes underneath connections to MySQL database.
 func (target *MySQLTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
 	if target.updateStmt != nil {
 		// FIXME: log returned error. ignore time being.
 		_ = target.updateStmt.Close()
@@ -160,30 +183,37 @@ func (target *MySQLTarget) Close() error {
 		_ = target.insertStmt.Close()
 	}
 
+	if target.db == nil {
+		return nil
+	}
+
 	return target.db.Close()
 }
 
-// NewMySQLTarget - creates new MySQL target.
-func NewMySQLTarget(id string, args MySQLArgs) (*MySQLTarget, error) {
-	if args.DSN == "" {
-		config := mysql.Config{
-			User:   args.User,
-			Passwd: args.Password,
-			Net:    "tcp",
-			Addr:   args.Host.String() + ":" + args.Port,
-			DBName: args.Database,
-		}
+// connect - connects to the MySQL database, creates the table and
+// prepares the statements unless already connected.
+func (target *MySQLTarget) connect() error {
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
 
-		args.DSN = config.FormatDSN()
+	if target.db != nil {
+		return nil
 	}
 
+	args := target.args
 	db, err := sql.Open("mysql", args.DSN)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer func() {
+		if target.db == nil {
+			// FIXME: log returned error. ignore time being.
+			_ = db.Close()
+		}
+	}()
 
 	if err = db.Ping(); err != nil {
-		return nil, err
+		return err
 	}
 
 	if _, err = db.Exec(fmt.Sprintf(mysqlTableExists, args.Table)); err != nil {
@@ -193,7 +223,7 @@ func NewMySQLTarget(id string, args MySQLArgs) (*MySQLTarget, error) {
 		}
 
 		if _, err = db.Exec(fmt.Sprintf(createStmt, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -202,25 +232,51 @@ func NewMySQLTarget(id string, args MySQLArgs) (*MySQLTarget, error) {
 	case event.NamespaceFormat:
 		// insert or update statement
 		if updateStmt, err = db.Prepare(fmt.Sprintf(mysqlUpdateRow, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 		// delete statement
 		if deleteStmt, err = db.Prepare(fmt.Sprintf(mysqlDeleteRow, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 	case event.AccessFormat:
 		// insert statement
 		if insertStmt, err = db.Prepare(fmt.Sprintf(mysqlInsertRow, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return &MySQLTarget{
-		id:         event.TargetID{id, "mysql"},
-		args:       args,
-		updateStmt: updateStmt,
-		deleteStmt: deleteStmt,
-		insertStmt: insertStmt,
-		db:         db,
-	}, nil
+	target.updateStmt = updateStmt
+	target.deleteStmt = deleteStmt
+	target.insertStmt = insertStmt
+	target.db = db
+
+	return nil
+}
+
+// NewMySQLTarget - creates new MySQL target.
+func NewMySQLTarget(id string, args MySQLArgs) (*MySQLTarget, error) {
+	if args.DSN == "" {
+		config := mysql.Config{
+			User:   args.User,
+			Passwd: args.Password,
+			Net:    "tcp",
+			Addr:   args.Host.String() + ":" + args.Port,
+			DBName: args.Database,
+		}
+
+		args.DSN = config.FormatDSN()
+	}
+
+	target := &MySQLTarget{
+		id:   event.TargetID{id, "mysql"},
+		args: args,
+	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
+	if err != nil {
+		return nil, err
+	}
+	target.store = store
+
+	return target, nil
 }
@@ -19,6 +19,7 @@ package target
 import (
 	"encoding/json"
 	"net/url"
+	"sync"
 
 	"github.com/minio/minio/pkg/event"
 	xnet "github.com/minio/minio/pkg/net"
@@ -43,14 +44,18 @@ type NATSArgs struct {
 		Async              bool   `json:"async"`
 		MaxPubAcksInflight int    `json:"maxPubAcksInflight"`
 	} `json:"streaming"`
+	QueueDir   string `json:"queueDir"`
+	QueueLimit uint64 `json:"queueLimit"`
 }
 
 // NATSTarget - NATS target.
 type NATSTarget struct {
-	id       event.TargetID
-	args     NATSArgs
-	natsConn *nats.Conn
-	stanConn stan.Conn
+	id        event.TargetID
+	args      NATSArgs
+	natsConn  *nats.Conn
+	stanConn  stan.Conn
+	connMutex sync.Mutex
+	store     *QueueStore
 }
 
 // ID - returns target ID.
@@ -58,8 +63,18 @@ func (target *NATSTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to NATS.
-func (target *NATSTarget) Send(eventData event.Event) (err error) {
+// Send - sends event to NATS. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
+func (target *NATSTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to NATS.
+func (target *NATSTarget) send(eventData event.Event) (err error) {
 	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
 	if err != nil {
 		return err
@@ -71,14 +86,22 @@ func (target *NATSTarget) Send(eventData event.Event) (err error) {
 		return err
 	}
 
-	if target.stanConn != nil {
+	if err = target.connect(); err != nil {
+		return err
+	}
+
+	target.connMutex.Lock()
+	natsConn, stanConn := target.natsConn, target.stanConn
+	target.connMutex.Unlock()
+
+	if stanConn != nil {
 		if target.args.Streaming.Async {
-			_, err = target.stanConn.PublishAsync(target.args.Subject, data, nil)
+			_, err = stanConn.PublishAsync(target.args.Subject, data, nil)
 		} else {
-			err = target.stanConn.Publish(target.args.Subject, data)
+			err = stanConn.Publish(target.args.Subject, data)
 		}
 	} else {
-		err = target.natsConn.Publish(target.args.Subject, data)
+		err = natsConn.Publish(target.args.Subject, data)
 	}
 
 	return err
@@ -86,6 +109,13 @@ func (target *NATSTarget) Send(eventData event.Event) (err error) {
 
 // Close - closes underneath connections to NATS server.
 func (target *NATSTarget) Close() (err error) {
+	if target.store != nil {
+		target.store.Close()
+	}
+
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
+
 	if target.stanConn != nil {
 		err = target.stanConn.Close()
 	}
@@ -97,12 +127,29 @@ func (target *NATSTarget) Close() (err error) {
 	return err
 }
 
-// NewNATSTarget - creates new NATS target.
-func NewNATSTarget(id string, args NATSArgs) (*NATSTarget, error) {
-	var natsConn *nats.Conn
-	var stanConn stan.Conn
-	var err error
+// connect - connects to NATS unless already connected. A connection
+// closed after running out of reconnect attempts is connected again.
+func (target *NATSTarget) connect() (err error) {
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
+
+	if target.stanConn != nil {
+		if nc := target.stanConn.NatsConn(); nc != nil && !nc.IsClosed() {
+			return nil
+		}
+		// FIXME: log returned error. ignore time being.
+		_ = target.stanConn.Close()
+		target.stanConn = nil
+	}
+
+	if target.natsConn != nil {
+		if !target.natsConn.IsClosed() {
+			return nil
+		}
+		target.natsConn = nil
+	}
 
+	args := target.args
 	if args.Streaming.Enable {
 		scheme := "nats"
 		if args.Secure {
@@ -112,9 +159,8 @@ func NewNATSTarget(id string, args NATSArgs) (*NATSTarget, error) {
 
 		clientID := args.Streaming.ClientID
 		if clientID == "" {
-			clientID, err = getNewUUID()
-			if err != nil {
-				return nil, err
+			if clientID, err = getNewUUID(); err != nil {
+				return err
 			}
 		}
 
@@ -123,7 +169,7 @@ func NewNATSTarget(id string, args NATSArgs) (*NATSTarget, error) {
 			connOpts = append(connOpts, stan.MaxPubAcksInflight(args.Streaming.MaxPubAcksInflight))
 		}
 
-		stanConn, err = stan.Connect(args.Streaming.ClusterID, clientID, connOpts...)
+		target.stanConn, err = stan.Connect(args.Streaming.ClusterID, clientID, connOpts...)
 	} else {
 		options := nats.DefaultOptions
 		options.Url = "nats://" + args.Address.String()
@@ -131,16 +177,24 @@ func NewNATSTarget(id string, args NATSArgs) (*NATSTarget, error) {
 		options.Password = args.Password
 		options.Token = args.Token
 		options.Secure = args.Secure
-		natsConn, err = options.Connect()
+		target.natsConn, err = options.Connect()
 	}
+
+	return err
+}
+
+// NewNATSTarget - creates new NATS target.
+func NewNATSTarget(id string, args NATSArgs) (*NATSTarget, error) {
+	target := &NATSTarget{
+		id:   event.TargetID{id, "nats"},
+		args: args,
+	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
 	if err != nil {
 		return nil, err
 	}
+	target.store = store
 
-	return &NATSTarget{
-		id:       event.TargetID{id, "nats"},
-		args:     args,
-		stanConn: stanConn,
-		natsConn: natsConn,
-	}, nil
+	return target, nil
 }
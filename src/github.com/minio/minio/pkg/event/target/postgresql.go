@@ -59,6 +59,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/lib/pq" // Register postgres driver
@@ -87,6 +88,8 @@ type PostgreSQLArgs struct {
 	User             string   `json:"user"`     // default: user running minio
 	Password         string   `json:"password"` // default: no password
 	Database         string   `json:"database"` // default: same as user
+	QueueDir         string   `json:"queueDir"`
+	QueueLimit       uint64   `json:"queueLimit"`
 }
 
 // PostgreSQLTarget - PostgreSQL target.
@@ -97,6 +100,8 @@ type PostgreSQLTarget struct {
 	deleteStmt *sql.Stmt
 	insertStmt *sql.Stmt
 	db         *sql.DB
+	connMutex  sync.Mutex
+	store      *QueueStore
 }
 
 // ID - returns target ID.
@@ -104,8 +109,22 @@ func (target *PostgreSQLTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to PostgreSQL.
+// Send - sends event to PostgreSQL. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
 func (target *PostgreSQLTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to PostgreSQL.
+func (target *PostgreSQLTarget) send(eventData event.Event) error {
+	if err := target.connect(); err != nil {
+		return err
+	}
+
 	if target.args.Format == event.NamespaceFormat {
 		objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
 		if err != nil {
@@ -146,6 +165,10 @@ func (target *PostgreSQLTarget) Send(eventData event.Event) error {
 
 // Close - closes underneath connections to PostgreSQL database.
 func (target *PostgreSQLTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
 	if target.updateStmt != nil {
 		// FIXME: log returned error. ignore time being.
 		_ = target.updateStmt.Close()
@@ -161,11 +184,24 @@ func (target *PostgreSQLTarget) Close() error {
 		_ = target.insertStmt.Close()
 	}
 
+	if target.db == nil {
+		return nil
+	}
+
 	return target.db.Close()
 }
 
-// NewPostgreSQLTarget - creates new PostgreSQL target.
-func NewPostgreSQLTarget(id string, args PostgreSQLArgs) (*PostgreSQLTarget, error) {
+// connect - connects to the PostgreSQL database, creates the table and
+// prepares the statements unless already connected.
+func (target *PostgreSQLTarget) connect() error {
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
+
+	if target.db != nil {
+		return nil
+	}
+
+	args := target.args
 	params := []string{args.ConnectionString}
 	if !args.Host.IsEmpty() {
 		params = append(params, "host="+args.Host.String())
@@ -186,11 +222,17 @@ func NewPostgreSQLTarget(id string, args PostgreSQLArgs) (*PostgreSQLTarget, err
 
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer func() {
+		if target.db == nil {
+			// FIXME: log returned error. ignore time being.
+			_ = db.Close()
+		}
+	}()
 
 	if err = db.Ping(); err != nil {
-		return nil, err
+		return err
 	}
 
 	if _, err = db.Exec(fmt.Sprintf(psqlTableExists, args.Table)); err != nil {
@@ -200,7 +242,7 @@ func NewPostgreSQLTarget(id string, args PostgreSQLArgs) (*PostgreSQLTarget, err
 		}
 
 		if _, err = db.Exec(fmt.Sprintf(createStmt, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -209,25 +251,39 @@ func NewPostgreSQLTarget(id string, args PostgreSQLArgs) (*PostgreSQLTarget, err
 	case event.NamespaceFormat:
 		// insert or update statement
 		if updateStmt, err = db.Prepare(fmt.Sprintf(psqlUpdateRow, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 		// delete statement
 		if deleteStmt, err = db.Prepare(fmt.Sprintf(psqlDeleteRow, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 	case event.AccessFormat:
 		// insert statement
 		if insertStmt, err = db.Prepare(fmt.Sprintf(psqlInsertRow, args.Table)); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return &PostgreSQLTarget{
-		id:         event.TargetID{id, "postgresql"},
-		args:       args,
-		updateStmt: updateStmt,
-		deleteStmt: deleteStmt,
-		insertStmt: insertStmt,
-		db:         db,
-	}, nil
+	target.updateStmt = updateStmt
+	target.deleteStmt = deleteStmt
+	target.insertStmt = insertStmt
+	target.db = db
+
+	return nil
+}
+
+// NewPostgreSQLTarget - creates new PostgreSQL target.
+func NewPostgreSQLTarget(id string, args PostgreSQLArgs) (*PostgreSQLTarget, error) {
+	target := &PostgreSQLTarget{
+		id:   event.TargetID{id, "postgresql"},
+		args: args,
+	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
+	if err != nil {
+		return nil, err
+	}
+	target.store = store
+
+	return target, nil
 }
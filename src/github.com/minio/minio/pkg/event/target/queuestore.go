@@ -0,0 +1,263 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package target
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+const (
+	// Default maximum number of events in a queue store.
+	defaultQueueLimit = 10000
+
+	// File extension of stored events.
+	eventExt = ".event"
+)
+
+// Intervals of retrying to send stored events while a target is
+// unreachable. The interval doubles on each failure up to the maximum.
+var (
+	minRetryInterval = time.Second
+	maxRetryInterval = time.Minute
+)
+
+// errQueueLimitReached - the queue store of a target is full.
+var errQueueLimitReached = errors.New("the maximum queue limit is reached")
+
+// QueueStore - durable queue of the events of a target in a directory,
+// one file per event. Events are sent in the order they are stored.
+type QueueStore struct {
+	sync.Mutex
+	directory string
+	limit     uint64
+	entries   uint64
+	sequence  uint64
+
+	// Signals stored events to the replay loop.
+	eventCh chan struct{}
+	doneCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// open - creates the queue directory, counts the stored events and
+// continues the sequence after the last stored event.
+func (store *QueueStore) open() error {
+	if err := os.MkdirAll(store.directory, 0700); err != nil {
+		return err
+	}
+
+	keys, err := store.List()
+	if err != nil {
+		return err
+	}
+	store.entries = uint64(len(keys))
+	if len(keys) > 0 {
+		if sequence, err := strconv.ParseUint(keys[len(keys)-1], 10, 64); err == nil {
+			store.sequence = sequence
+		}
+	}
+	return nil
+}
+
+// Put - stores an event at the end of the queue.
+func (store *QueueStore) Put(eventData event.Event) error {
+	data, err := json.Marshal(eventData)
+	if err != nil {
+		return err
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	if store.entries >= store.limit {
+		return errQueueLimitReached
+	}
+
+	// Keys are zero padded so that they sort in the order of events,
+	// also across restarts.
+	store.sequence++
+	key := fmt.Sprintf("%020d", store.sequence)
+	path := filepath.Join(store.directory, key+eventExt)
+
+	// Write to a temporary file first, so that partially written
+	// events are never sent.
+	if err = ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	if err = os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	store.entries++
+
+	select {
+	case store.eventCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Get - returns the stored event of a key.
+func (store *QueueStore) Get(key string) (eventData event.Event, err error) {
+	data, err := ioutil.ReadFile(filepath.Join(store.directory, key+eventExt))
+	if err != nil {
+		return eventData, err
+	}
+
+	err = json.Unmarshal(data, &eventData)
+	return eventData, err
+}
+
+// Del - removes the stored event of a key.
+func (store *QueueStore) Del(key string) error {
+	store.Lock()
+	defer store.Unlock()
+
+	if err := os.Remove(filepath.Join(store.directory, key+eventExt)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if store.entries > 0 {
+		store.entries--
+	}
+	return nil
+}
+
+// List - returns the keys of stored events in queue order.
+func (store *QueueStore) List() ([]string, error) {
+	files, err := ioutil.ReadDir(store.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, file := range files {
+		if name := file.Name(); strings.HasSuffix(name, eventExt) {
+			keys = append(keys, strings.TrimSuffix(name, eventExt))
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Close - stops replaying stored events. Stored events are kept and
+// replayed once the queue store is opened again.
+func (store *QueueStore) Close() {
+	close(store.doneCh)
+	store.wg.Wait()
+}
+
+// replay - sends stored events in order with send until the queue store
+// is closed. An event is removed once sent, and sending is retried with
+// backoff while it fails.
+func (store *QueueStore) replay(send func(event.Event) error) {
+	defer store.wg.Done()
+
+	retryInterval := minRetryInterval
+	for {
+		keys, err := store.List()
+		if err != nil {
+			keys = nil
+		}
+
+		for len(keys) > 0 {
+			eventData, err := store.Get(keys[0])
+			if err != nil {
+				// Skip unreadable events, which are never sent.
+				if !os.IsNotExist(err) {
+					store.Del(keys[0])
+				}
+				keys = keys[1:]
+				continue
+			}
+
+			if err = send(eventData); err != nil {
+				select {
+				case <-time.After(retryInterval):
+				case <-store.doneCh:
+					return
+				}
+				if retryInterval *= 2; retryInterval > maxRetryInterval {
+					retryInterval = maxRetryInterval
+				}
+				continue
+			}
+
+			retryInterval = minRetryInterval
+			store.Del(keys[0])
+			keys = keys[1:]
+		}
+
+		select {
+		case <-store.eventCh:
+		case <-store.doneCh:
+			return
+		}
+	}
+}
+
+// newTargetStore - opens the queue store of a target if queueDir is set,
+// in which case the target connects on sending instead, so that an
+// unreachable target neither fails nor delays starting the server.
+// Without a queue store the target is connected right away, unless
+// connect is nil for targets without a connection.
+func newTargetStore(queueDir string, queueLimit uint64, id event.TargetID, connect func() error, send func(event.Event) error) (*QueueStore, error) {
+	if queueDir == "" {
+		if connect == nil {
+			return nil, nil
+		}
+		return nil, connect()
+	}
+
+	return newQueueStore(queueDir, queueLimit, id, send)
+}
+
+// newQueueStore - opens the queue store of a target in queueDir, and
+// starts replaying stored events with send.
+func newQueueStore(queueDir string, queueLimit uint64, id event.TargetID, send func(event.Event) error) (*QueueStore, error) {
+	if !filepath.IsAbs(queueDir) {
+		return nil, fmt.Errorf("queue directory %s of target %v must be an absolute path", queueDir, id)
+	}
+	if queueLimit == 0 {
+		queueLimit = defaultQueueLimit
+	}
+
+	store := &QueueStore{
+		directory: filepath.Join(queueDir, "minio-"+id.Name+"-"+id.ID),
+		limit:     queueLimit,
+		eventCh:   make(chan struct{}, 1),
+		doneCh:    make(chan struct{}),
+	}
+	if err := store.open(); err != nil {
+		return nil, err
+	}
+
+	store.wg.Add(1)
+	go store.replay(send)
+	return store, nil
+}
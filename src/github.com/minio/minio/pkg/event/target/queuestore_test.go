@@ -0,0 +1,221 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package target
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+func TestQueueStorePutListDel(t *testing.T) {
+	queueDir, err := ioutil.TempDir("", "minio-queuestore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(queueDir)
+
+	// A failing send keeps all events in the store.
+	send := func(event.Event) error { return errors.New("unreachable") }
+	store, err := newQueueStore(queueDir, 2, event.TargetID{ID: "1", Name: "webhook"}, send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, name := range []string{"a", "b"} {
+		if err = store.Put(event.Event{EventName: event.ObjectCreatedPut, S3: event.Metadata{Object: event.Object{Key: name}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Put(event.Event{EventName: event.ObjectCreatedPut}); err != errQueueLimitReached {
+		t.Fatalf("expected: %v, got: %v", errQueueLimitReached, err)
+	}
+
+	keys, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected: 2 keys, got: %v", keys)
+	}
+
+	eventData, err := store.Get(keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eventData.S3.Object.Key != "a" {
+		t.Fatalf("expected: a, got: %v", eventData.S3.Object.Key)
+	}
+
+	if err = store.Del(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Put(event.Event{EventName: event.ObjectCreatedPut}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = newQueueStore("relative/dir", 0, event.TargetID{ID: "1", Name: "webhook"}, send); err == nil {
+		t.Fatal("expected error for relative queue directory")
+	}
+}
+
+func TestQueueStoreSequence(t *testing.T) {
+	queueDir, err := ioutil.TempDir("", "minio-queuestore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(queueDir)
+
+	send := func(event.Event) error { return errors.New("unreachable") }
+	id := event.TargetID{ID: "1", Name: "webhook"}
+
+	store, err := newQueueStore(queueDir, 0, id, send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err = store.Put(event.Event{EventName: event.ObjectCreatedPut}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+
+	// The sequence continues after the stored events once reopened.
+	if store, err = newQueueStore(queueDir, 0, id, send); err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err = store.Put(event.Event{EventName: event.ObjectCreatedPut}); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"00000000000000000001", "00000000000000000002", "00000000000000000003"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, keys)
+	}
+}
+
+func TestNewTargetStore(t *testing.T) {
+	queueDir, err := ioutil.TempDir("", "minio-queuestore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(queueDir)
+
+	errUnreachable := errors.New("unreachable")
+	connected := false
+	connect := func() error {
+		connected = true
+		return errUnreachable
+	}
+	send := func(event.Event) error { return errUnreachable }
+	id := event.TargetID{ID: "1", Name: "webhook"}
+
+	// Without a queue store the target is connected right away.
+	if _, err = newTargetStore("", 0, id, connect, send); err != errUnreachable {
+		t.Fatalf("expected: %v, got: %v", errUnreachable, err)
+	}
+	if !connected {
+		t.Fatal("target without a queue store is not connected")
+	}
+
+	// With a queue store an unreachable target is not connected.
+	connected = false
+	store, err := newTargetStore(queueDir, 0, id, connect, send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if connected {
+		t.Fatal("target with a queue store is connected right away")
+	}
+}
+
+func TestQueueStoreReplay(t *testing.T) {
+	queueDir, err := ioutil.TempDir("", "minio-queuestore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(queueDir)
+
+	defer func(min, max time.Duration) {
+		minRetryInterval, maxRetryInterval = min, max
+	}(minRetryInterval, maxRetryInterval)
+	minRetryInterval, maxRetryInterval = time.Millisecond, 10*time.Millisecond
+
+	var mutex sync.Mutex
+	var sent []string
+	failures := 3
+	doneCh := make(chan struct{})
+	send := func(eventData event.Event) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if failures > 0 {
+			failures--
+			return errors.New("unreachable")
+		}
+		sent = append(sent, eventData.S3.Object.Key)
+		if len(sent) == 3 {
+			close(doneCh)
+		}
+		return nil
+	}
+
+	store, err := newQueueStore(queueDir, 0, event.TargetID{ID: "1", Name: "webhook"}, send)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, name := range []string{"a", "b", "c"} {
+		if err = store.Put(event.Event{EventName: event.ObjectCreatedPut, S3: event.Metadata{Object: event.Object{Key: name}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-doneCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("stored events are not sent")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(sent, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, sent)
+	}
+
+	// Sent events are removed once send returns.
+	for i := 0; i < 100; i++ {
+		if keys, _ := store.List(); len(keys) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("sent events are not removed from the queue store")
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/garyburd/redigo/redis"
@@ -29,18 +30,23 @@ import (
 
 // RedisArgs - Redis target arguments.
 type RedisArgs struct {
-	Enable   bool      `json:"enable"`
-	Format   string    `json:"format"`
-	Addr     xnet.Host `json:"address"`
-	Password string    `json:"password"`
-	Key      string    `json:"key"`
+	Enable     bool      `json:"enable"`
+	Format     string    `json:"format"`
+	Addr       xnet.Host `json:"address"`
+	Password   string    `json:"password"`
+	Key        string    `json:"key"`
+	QueueDir   string    `json:"queueDir"`
+	QueueLimit uint64    `json:"queueLimit"`
 }
 
 // RedisTarget - Redis target.
 type RedisTarget struct {
-	id   event.TargetID
-	args RedisArgs
-	pool *redis.Pool
+	id        event.TargetID
+	args      RedisArgs
+	pool      *redis.Pool
+	connected bool
+	connMutex sync.Mutex
+	store     *QueueStore
 }
 
 // ID - returns target ID.
@@ -48,8 +54,22 @@ func (target *RedisTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to Redis.
+// Send - sends event to Redis. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
 func (target *RedisTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to Redis.
+func (target *RedisTarget) send(eventData event.Event) error {
+	if err := target.connect(); err != nil {
+		return err
+	}
+
 	conn := target.pool.Get()
 	defer func() {
 		// FIXME: log returned error. ignore time being.
@@ -88,8 +108,52 @@ func (target *RedisTarget) Send(eventData event.Event) error {
 	return nil
 }
 
-// Close - does nothing and available for interface compatibility.
+// Close - stops sending stored events.
 func (target *RedisTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
+	return nil
+}
+
+// connect - checks that Redis is reachable and the key has the expected
+// type unless already checked. Connections are dialed by the pool.
+func (target *RedisTarget) connect() error {
+	target.connMutex.Lock()
+	defer target.connMutex.Unlock()
+
+	if target.connected {
+		return nil
+	}
+
+	conn := target.pool.Get()
+	defer func() {
+		// FIXME: log returned error. ignore time being.
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Do("PING"); err != nil {
+		return err
+	}
+
+	typeAvailable, err := redis.String(conn.Do("TYPE", target.args.Key))
+	if err != nil {
+		return err
+	}
+
+	if typeAvailable != "none" {
+		expectedType := "hash"
+		if target.args.Format == event.AccessFormat {
+			expectedType = "list"
+		}
+
+		if typeAvailable != expectedType {
+			return fmt.Errorf("expected type %v does not match with available type %v", expectedType, typeAvailable)
+		}
+	}
+
+	target.connected = true
 	return nil
 }
 
@@ -122,35 +186,17 @@ func NewRedisTarget(id string, args RedisArgs) (*RedisTarget, error) {
 		},
 	}
 
-	conn := pool.Get()
-	defer func() {
-		// FIXME: log returned error. ignore time being.
-		_ = conn.Close()
-	}()
-
-	if _, err := conn.Do("PING"); err != nil {
-		return nil, err
+	target := &RedisTarget{
+		id:   event.TargetID{id, "redis"},
+		args: args,
+		pool: pool,
 	}
 
-	typeAvailable, err := redis.String(conn.Do("TYPE", args.Key))
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, target.connect, target.send)
 	if err != nil {
 		return nil, err
 	}
+	target.store = store
 
-	if typeAvailable != "none" {
-		expectedType := "hash"
-		if args.Format == event.AccessFormat {
-			expectedType = "list"
-		}
-
-		if typeAvailable != expectedType {
-			return nil, fmt.Errorf("expected type %v does not match with available type %v", expectedType, typeAvailable)
-		}
-	}
-
-	return &RedisTarget{
-		id:   event.TargetID{id, "redis"},
-		args: args,
-		pool: pool,
-	}, nil
+	return target, nil
 }
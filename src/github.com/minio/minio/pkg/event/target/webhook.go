@@ -22,6 +22,8 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -33,9 +35,11 @@ import (
 
 // WebhookArgs - Webhook target arguments.
 type WebhookArgs struct {
-	Enable   bool           `json:"enable"`
-	Endpoint xnet.URL       `json:"endpoint"`
-	RootCAs  *x509.CertPool `json:"-"`
+	Enable     bool           `json:"enable"`
+	Endpoint   xnet.URL       `json:"endpoint"`
+	RootCAs    *x509.CertPool `json:"-"`
+	QueueDir   string         `json:"queueDir"`
+	QueueLimit uint64         `json:"queueLimit"`
 }
 
 // WebhookTarget - Webhook target.
@@ -43,6 +47,7 @@ type WebhookTarget struct {
 	id         event.TargetID
 	args       WebhookArgs
 	httpClient *http.Client
+	store      *QueueStore
 }
 
 // ID - returns target ID.
@@ -50,8 +55,18 @@ func (target WebhookTarget) ID() event.TargetID {
 	return target.id
 }
 
-// Send - sends event to Webhook.
+// Send - sends event to Webhook. If the target has a queue store, the event
+// is stored and sent in order once the target is reachable.
 func (target *WebhookTarget) Send(eventData event.Event) error {
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+
+	return target.send(eventData)
+}
+
+// send - sends event to Webhook.
+func (target *WebhookTarget) send(eventData event.Event) error {
 	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
 	if err != nil {
 		return err
@@ -76,8 +91,9 @@ func (target *WebhookTarget) Send(eventData event.Event) error {
 		return err
 	}
 
-	// FIXME: log returned error. ignore time being.
-	_ = resp.Body.Close()
+	// Drain the response body so that the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		return fmt.Errorf("sending event failed with %v", resp.Status)
@@ -86,14 +102,18 @@ func (target *WebhookTarget) Send(eventData event.Event) error {
 	return nil
 }
 
-// Close - does nothing and available for interface compatibility.
+// Close - stops sending stored events.
 func (target *WebhookTarget) Close() error {
+	if target.store != nil {
+		target.store.Close()
+	}
+
 	return nil
 }
 
 // NewWebhookTarget - creates new Webhook target.
-func NewWebhookTarget(id string, args WebhookArgs) *WebhookTarget {
-	return &WebhookTarget{
+func NewWebhookTarget(id string, args WebhookArgs) (*WebhookTarget, error) {
+	target := &WebhookTarget{
 		id:   event.TargetID{id, "webhook"},
 		args: args,
 		httpClient: &http.Client{
@@ -109,4 +129,12 @@ func NewWebhookTarget(id string, args WebhookArgs) *WebhookTarget {
 			},
 		},
 	}
+
+	store, err := newTargetStore(args.QueueDir, args.QueueLimit, target.id, nil, target.send)
+	if err != nil {
+		return nil, err
+	}
+	target.store = store
+
+	return target, nil
 }
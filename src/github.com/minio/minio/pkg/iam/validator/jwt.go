@@ -0,0 +1,247 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package validator validates the JSON web tokens presented to the
+// STS AssumeRoleWithWebIdentity API against the keys of an identity
+// provider.
+package validator
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+var (
+	// ErrTokenExpired is returned when the token has expired.
+	ErrTokenExpired = errors.New("jwt: token has expired")
+
+	// ErrInvalidToken is returned when the token is malformed, not
+	// signed by a known key or misses required claims.
+	ErrInvalidToken = errors.New("jwt: token is invalid")
+)
+
+// minKeysRefreshInterval is the minimum interval between two fetches
+// of the key set from an URL, so that tokens with unknown key IDs
+// cannot make the identity provider be flooded with requests.
+const minKeysRefreshInterval = time.Minute
+
+// JWT validates JSON web tokens issued by issuer for audience and
+// signed by any key of a JSON web key set, which is read from a local
+// file or fetched from an URL.
+type JWT struct {
+	location   string
+	audience   string
+	issuer     string
+	httpClient *http.Client
+
+	lock        sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+
+	// Serializes fetches of the key set on unknown key IDs.
+	refreshLock sync.Mutex
+}
+
+// isURL returns whether location is an http(s) URL.
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// NewJWT returns a validator using the JSON web key set at location,
+// either a file path or an http(s) URL, accepting only tokens issued
+// by issuer for audience.
+func NewJWT(location, audience, issuer string) (*JWT, error) {
+	if location == "" {
+		return nil, errors.New("jwks: location is missing")
+	}
+	if audience == "" {
+		return nil, errors.New("jwt: audience is missing")
+	}
+	if issuer == "" {
+		return nil, errors.New("jwt: issuer is missing")
+	}
+	v := &JWT{
+		location:   location,
+		audience:   audience,
+		issuer:     issuer,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := v.loadKeys(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// loadKeys reads the key set from its location and replaces the
+// current keys.
+func (v *JWT) loadKeys() error {
+	var r io.ReadCloser
+	if isURL(v.location) {
+		resp, err := v.httpClient.Get(v.location)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("jwks: fetching %s failed: %s", v.location, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		file, err := os.Open(v.location)
+		if err != nil {
+			return err
+		}
+		r = file
+	}
+	defer r.Close()
+
+	jwks, err := ReadJWKS(r)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		publicKey, err := key.PublicKey()
+		if err != nil {
+			return err
+		}
+		keys[key.Kid] = publicKey
+	}
+
+	v.lock.Lock()
+	v.keys = keys
+	v.lastRefresh = time.Now()
+	v.lock.Unlock()
+	return nil
+}
+
+// refreshKeys reloads the key set fetched from an URL on an unknown
+// key ID, at most once per minKeysRefreshInterval.
+func (v *JWT) refreshKeys(kid string) (crypto.PublicKey, bool, error) {
+	v.refreshLock.Lock()
+	defer v.refreshLock.Unlock()
+
+	// The key may have been fetched while waiting for the lock.
+	if key, ok := v.getKey(kid); ok {
+		return key, true, nil
+	}
+
+	v.lock.RLock()
+	lastRefresh := v.lastRefresh
+	v.lock.RUnlock()
+	if time.Since(lastRefresh) < minKeysRefreshInterval {
+		return nil, false, nil
+	}
+
+	if err := v.loadKeys(); err != nil {
+		return nil, false, err
+	}
+	key, ok := v.getKey(kid)
+	return key, ok, nil
+}
+
+// getKey returns the public key with given key ID. If the key set
+// has a single key, tokens without key ID are verified with it.
+func (v *JWT) getKey(kid string) (crypto.PublicKey, bool) {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// keyFunc returns the key verifying the signature of token. Keys
+// fetched from an URL are reloaded on an unknown key ID, as the
+// identity provider may have rotated its keys.
+func (v *JWT) keyFunc(token *jwtgo.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwtgo.SigningMethodRSA, *jwtgo.SigningMethodECDSA, *jwtgo.SigningMethodRSAPSS:
+	default:
+		return nil, fmt.Errorf("jwt: unexpected signing method %v", token.Header["alg"])
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.getKey(kid); ok {
+		return key, nil
+	}
+	if isURL(v.location) {
+		key, ok, err := v.refreshKeys(kid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("jwt: unknown key %q", kid)
+}
+
+// hasAudience returns whether the aud claim, a single audience or a
+// list of audiences, contains audience.
+func hasAudience(claims jwtgo.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate verifies the signature, the time claims, the issuer and
+// the audience of token and returns its claims and expiry. Tokens
+// must carry an expiry.
+func (v *JWT) Validate(token string) (claims map[string]interface{}, expiry time.Time, err error) {
+	mapClaims := jwtgo.MapClaims{}
+	if _, err = jwtgo.ParseWithClaims(token, &mapClaims, v.keyFunc); err != nil {
+		if verr, ok := err.(*jwtgo.ValidationError); ok && verr.Errors&jwtgo.ValidationErrorExpired != 0 {
+			return nil, expiry, ErrTokenExpired
+		}
+		return nil, expiry, ErrInvalidToken
+	}
+	if !mapClaims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, expiry, ErrInvalidToken
+	}
+	if !mapClaims.VerifyIssuer(v.issuer, true) || !hasAudience(mapClaims, v.audience) {
+		return nil, expiry, ErrInvalidToken
+	}
+	exp, ok := mapClaims["exp"].(float64)
+	if !ok {
+		return nil, expiry, ErrInvalidToken
+	}
+	return mapClaims, time.Unix(int64(exp), 0).UTC(), nil
+}
@@ -0,0 +1,191 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validator
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
+
+func newTestJWKS(t *testing.T, kid string, key *rsa.PrivateKey) []byte {
+	jwks, err := json.Marshal(JWKS{Keys: []JWK{{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwks
+}
+
+func newTestToken(t *testing.T, kid string, key interface{}, method jwtgo.SigningMethod, claims jwtgo.MapClaims) string {
+	token := jwtgo.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTValidate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ioutil.TempFile("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(newTestJWKS(t, "key-1", key)); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if _, err = NewJWT(file.Name(), "", "https://idp"); err == nil {
+		t.Fatal("Expected validator without audience to be rejected")
+	}
+	if _, err = NewJWT(file.Name(), "minio", ""); err == nil {
+		t.Fatal("Expected validator without issuer to be rejected")
+	}
+	v, err := NewJWT(file.Name(), "minio", "https://idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	newClaims := func(claims jwtgo.MapClaims) jwtgo.MapClaims {
+		validClaims := jwtgo.MapClaims{"sub": "ci", "exp": exp, "iss": "https://idp", "aud": "minio"}
+		for k, v := range claims {
+			if v == nil {
+				delete(validClaims, k)
+			} else {
+				validClaims[k] = v
+			}
+		}
+		return validClaims
+	}
+	testCases := []struct {
+		token string
+		err   error
+	}{
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(nil)), nil},
+		// A single key verifies tokens without key ID.
+		{newTestToken(t, "", key, jwtgo.SigningMethodRS256, newClaims(nil)), nil},
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(jwtgo.MapClaims{"aud": []string{"other", "minio"}})), nil},
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(jwtgo.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})), ErrTokenExpired},
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(jwtgo.MapClaims{"exp": nil})), ErrInvalidToken},
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(jwtgo.MapClaims{"aud": "other"})), ErrInvalidToken},
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(jwtgo.MapClaims{"aud": nil})), ErrInvalidToken},
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(jwtgo.MapClaims{"iss": "https://other"})), ErrInvalidToken},
+		{newTestToken(t, "key-1", key, jwtgo.SigningMethodRS256, newClaims(jwtgo.MapClaims{"iss": nil})), ErrInvalidToken},
+		{newTestToken(t, "key-1", otherKey, jwtgo.SigningMethodRS256, newClaims(nil)), ErrInvalidToken},
+		{newTestToken(t, "key-2", key, jwtgo.SigningMethodRS256, newClaims(nil)), ErrInvalidToken},
+		{newTestToken(t, "key-1", []byte("secret"), jwtgo.SigningMethodHS256, newClaims(nil)), ErrInvalidToken},
+		{"not-a-token", ErrInvalidToken},
+	}
+
+	for i, testCase := range testCases {
+		claims, expiry, err := v.Validate(testCase.token)
+		if err != testCase.err {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.err, err)
+		}
+		if err == nil {
+			if claims["sub"] != "ci" {
+				t.Fatalf("Test %d: unexpected claims %v", i+1, claims)
+			}
+			if expiry.Unix() != exp {
+				t.Fatalf("Test %d: expected expiry %d, got %v", i+1, exp, expiry)
+			}
+		}
+	}
+}
+
+// Tests that keys fetched from an URL are reloaded on an unknown key
+// ID, at most once per minKeysRefreshInterval.
+func TestJWTKeyRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := newTestJWKS(t, "old", oldKey)
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	v, err := NewJWT(server.URL, "minio", "https://idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks = newTestJWKS(t, "new", newKey)
+	token := newTestToken(t, "new", newKey, jwtgo.SigningMethodRS256, jwtgo.MapClaims{
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iss": "https://idp",
+		"aud": "minio",
+	})
+
+	// The key set was just fetched, so it is not fetched again.
+	if _, _, err = v.Validate(token); err != ErrInvalidToken {
+		t.Fatalf("Expected %v, got %v", ErrInvalidToken, err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("Expected key set to be fetched once, got %d", n)
+	}
+
+	v.lastRefresh = time.Now().Add(-minKeysRefreshInterval)
+	if _, _, err = v.Validate(token); err != nil {
+		t.Fatalf("Expected token signed by rotated key to be valid, got %v", err)
+	}
+	if _, _, err = v.Validate(newTestToken(t, "unknown", newKey, jwtgo.SigningMethodRS256, jwtgo.MapClaims{})); err != ErrInvalidToken {
+		t.Fatalf("Expected %v, got %v", ErrInvalidToken, err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("Expected key set to be fetched twice, got %d", n)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package kms implements key management services which generate
+// and unseal the data keys used for server-side encryption.
+package kms
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var (
+	// ErrKeyNotFound is returned when the requested master key does not exist.
+	ErrKeyNotFound = errors.New("kms: master key not found")
+
+	// ErrInvalidSealedKey is returned when a sealed key cannot be unsealed,
+	// either because it was modified or the context does not match.
+	ErrInvalidSealedKey = errors.New("kms: sealed key is invalid")
+)
+
+// Context is a set of key-value pairs which is bound to a sealed key.
+// A sealed key can only be unsealed with the context used to seal it.
+type Context map[string]string
+
+// marshal returns the canonical representation of the context.
+// The JSON encoding of maps is sorted by keys.
+func (c Context) marshal() []byte {
+	if len(c) == 0 {
+		return []byte("{}")
+	}
+	data, _ := json.Marshal(map[string]string(c)) // marshaling a map of strings cannot fail
+	return data
+}
+
+// KMS is a key management service generating data keys sealed
+// by named master keys which never leave the KMS.
+type KMS interface {
+	// GenerateKey generates a new random data key and returns it
+	// in plaintext and sealed by the master key keyID. The context
+	// must be provided again to unseal the key.
+	GenerateKey(keyID string, context Context) (key [32]byte, sealedKey []byte, err error)
+
+	// UnsealKey unseals the sealedKey with the master key keyID
+	// and returns the plaintext data key.
+	UnsealKey(keyID string, sealedKey []byte, context Context) (key [32]byte, err error)
+}
@@ -0,0 +1,152 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kms
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	sha256 "github.com/minio/sha256-simd"
+	"github.com/minio/sio"
+)
+
+var errInvalidMasterKey = errors.New("kms: master key must be of the form <key-id>:<64 hex characters>")
+
+// MasterKey is a named 256 bit master key of a local KMS.
+type MasterKey struct {
+	ID  string
+	Key [32]byte
+}
+
+// ParseMasterKey parses a master key of the form
+// <key-id>:<hex encoded 256 bit key>.
+func ParseMasterKey(s string) (MasterKey, error) {
+	v := strings.SplitN(s, ":", 2)
+	if len(v) != 2 || v[0] == "" {
+		return MasterKey{}, errInvalidMasterKey
+	}
+	key, err := hex.DecodeString(v[1])
+	if err != nil || len(key) != 32 {
+		return MasterKey{}, errInvalidMasterKey
+	}
+	masterKey := MasterKey{ID: v[0]}
+	copy(masterKey.Key[:], key)
+	return masterKey, nil
+}
+
+// ReadMasterKeys reads master keys from r, one key of the form
+// <key-id>:<hex encoded 256 bit key> per line. Empty lines and
+// lines starting with '#' are ignored.
+func ReadMasterKeys(r io.Reader) ([]MasterKey, error) {
+	var keys []MasterKey
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := ParseMasterKey(line)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("kms: no master key found")
+	}
+	return keys, nil
+}
+
+// LoadMasterKeys reads the master keys from the file at path.
+func LoadMasterKeys(path string) ([]MasterKey, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadMasterKeys(file)
+}
+
+// localKMS seals data keys with master keys held in memory.
+type localKMS struct {
+	keys map[string]MasterKey
+}
+
+// NewLocal returns a KMS sealing data keys with the given master keys.
+// It is meant for single node deployments and tests, production setups
+// should keep master keys in an external KMS.
+func NewLocal(keys ...MasterKey) KMS {
+	kms := localKMS{keys: make(map[string]MasterKey, len(keys))}
+	for _, key := range keys {
+		kms.keys[key.ID] = key
+	}
+	return kms
+}
+
+// deriveKey derives the key encryption key of a data key from the
+// master key and the context.
+func (kms localKMS) deriveKey(keyID string, context Context) ([]byte, error) {
+	masterKey, ok := kms.keys[keyID]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	mac := hmac.New(sha256.New, masterKey.Key[:])
+	mac.Write([]byte(keyID))
+	mac.Write(context.marshal())
+	return mac.Sum(nil), nil
+}
+
+func (kms localKMS) GenerateKey(keyID string, context Context) (key [32]byte, sealedKey []byte, err error) {
+	keyEncryptionKey, err := kms.deriveKey(keyID, context)
+	if err != nil {
+		return key, nil, err
+	}
+	if _, err = io.ReadFull(rand.Reader, key[:]); err != nil {
+		return key, nil, err
+	}
+
+	sealed := bytes.NewBuffer(nil) // sealedKey := 16 byte header + 32 byte payload + 16 byte tag
+	if _, err = sio.Encrypt(sealed, bytes.NewReader(key[:]), sio.Config{Key: keyEncryptionKey}); err != nil {
+		return key, nil, err
+	}
+	return key, sealed.Bytes(), nil
+}
+
+func (kms localKMS) UnsealKey(keyID string, sealedKey []byte, context Context) (key [32]byte, err error) {
+	keyEncryptionKey, err := kms.deriveKey(keyID, context)
+	if err != nil {
+		return key, err
+	}
+
+	plaintext := bytes.NewBuffer(nil)
+	n, err := sio.Decrypt(plaintext, bytes.NewReader(sealedKey), sio.Config{Key: keyEncryptionKey})
+	if err != nil || n != int64(len(key)) {
+		return key, ErrInvalidSealedKey
+	}
+	copy(key[:], plaintext.Bytes())
+	return key, nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kms
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testMasterKey = "my-key:6368616e676520746869732070617373776f726420746f206120736563726574"
+
+var parseMasterKeyTests = []struct {
+	key        string
+	shouldFail bool
+}{
+	{key: testMasterKey, shouldFail: false},                                                            // 0
+	{key: "6368616e676520746869732070617373776f726420746f206120736563726574", shouldFail: true},        // 1
+	{key: ":6368616e676520746869732070617373776f726420746f206120736563726574", shouldFail: true},       // 2
+	{key: "my-key:6368616e676520746869732070617373776f726420746f2061207365637265", shouldFail: true},   // 3
+	{key: "my-key:zz68616e676520746869732070617373776f726420746f206120736563726574", shouldFail: true}, // 4
+}
+
+func TestParseMasterKey(t *testing.T) {
+	for i, test := range parseMasterKeyTests {
+		key, err := ParseMasterKey(test.key)
+		if err != nil && !test.shouldFail {
+			t.Errorf("Test %d: Failed to parse master key: %v", i, err)
+		}
+		if err == nil && test.shouldFail {
+			t.Errorf("Test %d: Parsing should fail but succeeded", i)
+		}
+		if err == nil && key.ID != "my-key" {
+			t.Errorf("Test %d: Parsed invalid master key ID: %s", i, key.ID)
+		}
+	}
+}
+
+func TestReadMasterKeys(t *testing.T) {
+	file := "# master keys\n\n" + testMasterKey + "\nold-key:" + strings.Repeat("00", 32) + "\n"
+	keys, err := ReadMasterKeys(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].ID != "my-key" || keys[1].ID != "old-key" {
+		t.Fatalf("Read invalid master keys: %v", keys)
+	}
+
+	if _, err = ReadMasterKeys(strings.NewReader("# no keys\n")); err == nil {
+		t.Fatal("Expected an error for a file without keys")
+	}
+	if _, err = ReadMasterKeys(strings.NewReader("my-key:00\n")); err == nil {
+		t.Fatal("Expected an error for an invalid key")
+	}
+}
+
+func TestLocalKMS(t *testing.T) {
+	masterKey, err := ParseMasterKey(testMasterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kms := NewLocal(masterKey)
+	context := Context{"bucket": "bucket/object"}
+
+	key, sealedKey, err := kms.GenerateKey("my-key", context)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	unsealedKey, err := kms.UnsealKey("my-key", sealedKey, context)
+	if err != nil {
+		t.Fatalf("Failed to unseal key: %v", err)
+	}
+	if !bytes.Equal(key[:], unsealedKey[:]) {
+		t.Fatal("Unsealed key does not match the generated key")
+	}
+
+	if _, _, err = kms.GenerateKey("unknown-key", context); err != ErrKeyNotFound {
+		t.Fatalf("Expected %v, got %v", ErrKeyNotFound, err)
+	}
+	if _, err = kms.UnsealKey("unknown-key", sealedKey, context); err != ErrKeyNotFound {
+		t.Fatalf("Expected %v, got %v", ErrKeyNotFound, err)
+	}
+	if _, err = kms.UnsealKey("my-key", sealedKey, Context{"bucket": "bucket/other-object"}); err != ErrInvalidSealedKey {
+		t.Fatalf("Expected %v for a different context, got %v", ErrInvalidSealedKey, err)
+	}
+	sealedKey[len(sealedKey)-1] ^= 1
+	if _, err = kms.UnsealKey("my-key", sealedKey, context); err != ErrInvalidSealedKey {
+		t.Fatalf("Expected %v for a modified sealed key, got %v", ErrInvalidSealedKey, err)
+	}
+}
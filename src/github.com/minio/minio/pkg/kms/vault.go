@@ -0,0 +1,216 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultAppRole holds the credentials of a Vault AppRole.
+type VaultAppRole struct {
+	ID     string `json:"id"`     // The AppRole ID
+	Secret string `json:"secret"` // The AppRole secret ID
+}
+
+// VaultConfig represents the configuration of a KMS
+// speaking the HashiCorp Vault transit secrets API.
+type VaultConfig struct {
+	Endpoint string       `json:"endpoint"` // The Vault endpoint, e.g. https://vault.example.com:8200
+	AppRole  VaultAppRole `json:"approle"`  // The AppRole used to authenticate to Vault
+}
+
+// Validate returns an error if the configuration is incomplete.
+func (c *VaultConfig) Validate() error {
+	if c.Endpoint == "" {
+		return errors.New("kms: vault endpoint is missing")
+	}
+	if c.AppRole.ID == "" || c.AppRole.Secret == "" {
+		return errors.New("kms: vault AppRole ID and secret ID must be set")
+	}
+	return nil
+}
+
+// vaultService is a KMS using the transit secrets engine of Vault.
+// The transit key names are the master key IDs.
+type vaultService struct {
+	config     VaultConfig
+	httpClient *http.Client
+
+	lock  sync.RWMutex
+	token string
+}
+
+// vaultError is the error response of the Vault HTTP API.
+type vaultError struct {
+	Errors []string `json:"errors"`
+}
+
+// NewVault returns a KMS using the Vault transit secrets engine
+// at the configured endpoint. It authenticates with the AppRole
+// and renews its client token before the token lease expires.
+func NewVault(config VaultConfig) (KMS, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	vault := &vaultService{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	lease, err := vault.authenticate()
+	if err != nil {
+		return nil, err
+	}
+	if lease > 0 {
+		go vault.renewToken(lease)
+	}
+	return vault, nil
+}
+
+// authenticate logs in with the AppRole and stores the client token.
+// It returns the lease duration of the token.
+func (vault *vaultService) authenticate() (time.Duration, error) {
+	login := map[string]string{
+		"role_id":   vault.config.AppRole.ID,
+		"secret_id": vault.config.AppRole.Secret,
+	}
+	var response struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := vault.call("/v1/auth/approle/login", "", login, &response); err != nil {
+		return 0, err
+	}
+	if response.Auth.ClientToken == "" {
+		return 0, errors.New("kms: vault returned no client token")
+	}
+
+	vault.lock.Lock()
+	vault.token = response.Auth.ClientToken
+	vault.lock.Unlock()
+	return time.Duration(response.Auth.LeaseDuration) * time.Second, nil
+}
+
+// renewToken authenticates again whenever half of the token lease has passed.
+// On failure it retries after a short delay.
+func (vault *vaultService) renewToken(lease time.Duration) {
+	for {
+		time.Sleep(lease / 2)
+		newLease, err := vault.authenticate()
+		for err != nil {
+			time.Sleep(5 * time.Second)
+			newLease, err = vault.authenticate()
+		}
+		if newLease > 0 {
+			lease = newLease
+		}
+	}
+}
+
+// call sends a POST request with the JSON encoded body to the Vault API path
+// and decodes the JSON response into response.
+func (vault *vaultService) call(path, token string, body, response interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", vault.config.Endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := vault.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var vErr vaultError
+		if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&vErr); err != nil || len(vErr.Errors) == 0 {
+			return fmt.Errorf("kms: vault request failed: %s", resp.Status)
+		}
+		return fmt.Errorf("kms: vault request failed: %s", strings.Join(vErr.Errors, ", "))
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(response)
+}
+
+func (vault *vaultService) clientToken() string {
+	vault.lock.RLock()
+	defer vault.lock.RUnlock()
+	return vault.token
+}
+
+func (vault *vaultService) GenerateKey(keyID string, context Context) (key [32]byte, sealedKey []byte, err error) {
+	request := map[string]string{
+		"context": base64.StdEncoding.EncodeToString(context.marshal()),
+	}
+	var response struct {
+		Data struct {
+			Plaintext  string `json:"plaintext"`
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err = vault.call("/v1/transit/datakey/plaintext/"+url.PathEscape(keyID), vault.clientToken(), request, &response); err != nil {
+		return key, nil, err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil || len(plaintext) != len(key) || response.Data.Ciphertext == "" {
+		return key, nil, errors.New("kms: vault returned an invalid data key")
+	}
+	copy(key[:], plaintext)
+	return key, []byte(response.Data.Ciphertext), nil
+}
+
+func (vault *vaultService) UnsealKey(keyID string, sealedKey []byte, context Context) (key [32]byte, err error) {
+	request := map[string]string{
+		"ciphertext": string(sealedKey),
+		"context":    base64.StdEncoding.EncodeToString(context.marshal()),
+	}
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err = vault.call("/v1/transit/decrypt/"+url.PathEscape(keyID), vault.clientToken(), request, &response); err != nil {
+		return key, err
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil || len(plaintext) != len(key) {
+		return key, ErrInvalidSealedKey
+	}
+	copy(key[:], plaintext)
+	return key, nil
+}
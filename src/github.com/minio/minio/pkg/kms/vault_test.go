@@ -0,0 +1,154 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestVaultServer returns a server emulating the AppRole login and the
+// transit datakey and decrypt endpoints of Vault. Data keys are sealed by
+// a local KMS, the context is passed through as given by the client.
+func newTestVaultServer(t *testing.T, roleID, secretID string) *httptest.Server {
+	masterKey, err := ParseMasterKey(testMasterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kms := NewLocal(masterKey)
+	const token = "s.test-token"
+
+	writeError := func(w http.ResponseWriter, status int, msg string) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(vaultError{Errors: []string{msg}})
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if r.URL.Path == "/v1/auth/approle/login" {
+			if request["role_id"] != roleID || request["secret_id"] != secretID {
+				writeError(w, http.StatusBadRequest, "invalid role or secret ID")
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"auth": map[string]interface{}{"client_token": token, "lease_duration": 0},
+			})
+			return
+		}
+		if r.Header.Get("X-Vault-Token") != token {
+			writeError(w, http.StatusForbidden, "permission denied")
+			return
+		}
+		context := Context{"context": request["context"]}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/transit/datakey/plaintext/"):
+			key, sealedKey, err := kms.GenerateKey(strings.TrimPrefix(r.URL.Path, "/v1/transit/datakey/plaintext/"), context)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{
+					"plaintext":  base64.StdEncoding.EncodeToString(key[:]),
+					"ciphertext": "vault:v1:" + base64.StdEncoding.EncodeToString(sealedKey),
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/v1/transit/decrypt/"):
+			sealedKey, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(request["ciphertext"], "vault:v1:"))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			key, err := kms.UnsealKey(strings.TrimPrefix(r.URL.Path, "/v1/transit/decrypt/"), sealedKey, context)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key[:])},
+			})
+		default:
+			writeError(w, http.StatusNotFound, "unsupported path")
+		}
+	}))
+}
+
+func TestVaultConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config     VaultConfig
+		shouldFail bool
+	}{
+		{VaultConfig{Endpoint: "http://127.0.0.1:8200", AppRole: VaultAppRole{ID: "id", Secret: "secret"}}, false},
+		{VaultConfig{AppRole: VaultAppRole{ID: "id", Secret: "secret"}}, true},
+		{VaultConfig{Endpoint: "http://127.0.0.1:8200", AppRole: VaultAppRole{ID: "id"}}, true},
+		{VaultConfig{Endpoint: "http://127.0.0.1:8200"}, true},
+	}
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if err != nil && !testCase.shouldFail {
+			t.Errorf("Test %d: Failed to validate config: %v", i, err)
+		}
+		if err == nil && testCase.shouldFail {
+			t.Errorf("Test %d: Validation should fail but succeeded", i)
+		}
+	}
+}
+
+func TestVaultKMS(t *testing.T) {
+	server := newTestVaultServer(t, "role", "secret")
+	defer server.Close()
+
+	if _, err := NewVault(VaultConfig{Endpoint: server.URL, AppRole: VaultAppRole{ID: "role", Secret: "wrong"}}); err == nil {
+		t.Fatal("Expected authentication with an invalid secret ID to fail")
+	}
+
+	kms, err := NewVault(VaultConfig{Endpoint: server.URL + "/", AppRole: VaultAppRole{ID: "role", Secret: "secret"}})
+	if err != nil {
+		t.Fatalf("Failed to create vault KMS: %v", err)
+	}
+	context := Context{"bucket": "bucket/object"}
+
+	key, sealedKey, err := kms.GenerateKey("my-key", context)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	if !bytes.HasPrefix(sealedKey, []byte("vault:v1:")) {
+		t.Fatalf("Expected a vault ciphertext as sealed key, got %q", sealedKey)
+	}
+	unsealedKey, err := kms.UnsealKey("my-key", sealedKey, context)
+	if err != nil {
+		t.Fatalf("Failed to unseal key: %v", err)
+	}
+	if !bytes.Equal(key[:], unsealedKey[:]) {
+		t.Fatal("Unsealed key does not match the generated key")
+	}
+
+	if _, _, err = kms.GenerateKey("unknown-key", context); err == nil {
+		t.Fatal("Expected an error for an unknown key")
+	}
+	if _, err = kms.UnsealKey("my-key", sealedKey, Context{"bucket": "bucket/other-object"}); err == nil {
+		t.Fatal("Expected an error for a different context")
+	}
+}
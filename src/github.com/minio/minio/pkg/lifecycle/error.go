@@ -0,0 +1,57 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import "fmt"
+
+// IsLifecycleError - checks whether given error is lifecycle error or not.
+func IsLifecycleError(err error) bool {
+	switch err.(type) {
+	case ErrMalformedXML, *ErrMalformedXML:
+		return true
+	case ErrInvalidArgument, *ErrInvalidArgument:
+		return true
+	}
+
+	return false
+}
+
+// ErrMalformedXML - lifecycle configuration does not conform to the S3 lifecycle schema.
+type ErrMalformedXML struct {
+	Reason string
+}
+
+func (err ErrMalformedXML) Error() string {
+	return fmt.Sprintf("malformed lifecycle configuration: %v", err.Reason)
+}
+
+// ErrInvalidArgument - lifecycle configuration has an invalid value.
+type ErrInvalidArgument struct {
+	Reason string
+}
+
+func (err ErrInvalidArgument) Error() string {
+	return fmt.Sprintf("invalid lifecycle configuration: %v", err.Reason)
+}
+
+func errMalformedXML(format string, a ...interface{}) error {
+	return &ErrMalformedXML{Reason: fmt.Sprintf(format, a...)}
+}
+
+func errInvalidArgument(format string, a ...interface{}) error {
+	return &ErrInvalidArgument{Reason: fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// dateFormat - ISO 8601 format of dates in lifecycle configuration.
+const dateFormat = "2006-01-02T15:04:05.000Z"
+
+// Date - date of Expiration and Transition actions, it must be midnight UTC.
+type Date struct {
+	time.Time
+}
+
+// MarshalXML - encodes Date in ISO 8601 format.
+func (date Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(date.UTC().Format(dateFormat), start)
+}
+
+// UnmarshalXML - decodes ISO 8601 formatted date.
+func (date *Date) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		// Date only format is accepted as well.
+		if t, err = time.Parse("2006-01-02", s); err != nil {
+			return errMalformedXML("invalid date '%v'", s)
+		}
+	}
+
+	*date = Date{t.UTC()}
+	return nil
+}
+
+func (date Date) isValid() error {
+	if !date.Equal(date.Truncate(24 * time.Hour)) {
+		return errInvalidArgument("date '%v' must be at midnight UTC", date.Format(dateFormat))
+	}
+	return nil
+}
+
+// Expiration - expiration action of a rule.
+type Expiration struct {
+	Days                      int   `xml:"Days,omitempty"`
+	Date                      *Date `xml:"Date,omitempty"`
+	ExpiredObjectDeleteMarker bool  `xml:"ExpiredObjectDeleteMarker,omitempty"`
+}
+
+func (expiration Expiration) isValid() error {
+	count := 0
+	if expiration.Days != 0 {
+		count++
+	}
+	if expiration.Date != nil {
+		count++
+	}
+	if expiration.ExpiredObjectDeleteMarker {
+		count++
+	}
+	if count != 1 {
+		return errMalformedXML("'Expiration' must have exactly one of 'Days', 'Date' or 'ExpiredObjectDeleteMarker'")
+	}
+
+	if expiration.Days < 0 {
+		return errInvalidArgument("'Days' for 'Expiration' action must be a positive integer")
+	}
+
+	if expiration.Date != nil {
+		return expiration.Date.isValid()
+	}
+
+	return nil
+}
+
+// NoncurrentVersionExpiration - expiration action of noncurrent object versions.
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays int `xml:"NoncurrentDays"`
+}
+
+func (expiration NoncurrentVersionExpiration) isValid() error {
+	if expiration.NoncurrentDays <= 0 {
+		return errInvalidArgument("'NoncurrentDays' for 'NoncurrentVersionExpiration' action must be a positive integer")
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"encoding/xml"
+	"unicode/utf8"
+)
+
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// Tag - object tag key/value pair a rule filters on.
+type Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// IsEmpty - returns whether tag is not set.
+func (tag Tag) IsEmpty() bool {
+	return tag.Key == "" && tag.Value == ""
+}
+
+func (tag Tag) isValid() error {
+	if tag.Key == "" {
+		return errInvalidArgument("tag key must not be empty")
+	}
+
+	if utf8.RuneCountInString(tag.Key) > maxTagKeyLength {
+		return errInvalidArgument("tag key '%v' is longer than %v characters", tag.Key, maxTagKeyLength)
+	}
+
+	if utf8.RuneCountInString(tag.Value) > maxTagValueLength {
+		return errInvalidArgument("tag value '%v' is longer than %v characters", tag.Value, maxTagValueLength)
+	}
+
+	return nil
+}
+
+// And - logical AND of a prefix and tags a rule filters on.
+type And struct {
+	Prefix string `xml:"Prefix,omitempty"`
+	Tags   []Tag  `xml:"Tag,omitempty"`
+}
+
+// IsEmpty - returns whether And has no conditions.
+func (and And) IsEmpty() bool {
+	return and.Prefix == "" && len(and.Tags) == 0
+}
+
+func (and And) isValid() error {
+	if len(and.Tags) == 0 && and.Prefix != "" {
+		return errMalformedXML("'And' must have at least one tag, use 'Prefix' in 'Filter' instead")
+	}
+
+	keys := make(map[string]struct{})
+	for _, tag := range and.Tags {
+		if err := tag.isValid(); err != nil {
+			return err
+		}
+
+		if _, found := keys[tag.Key]; found {
+			return errInvalidArgument("duplicate tag key '%v' in 'And'", tag.Key)
+		}
+		keys[tag.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// Filter - identifies objects a rule applies to. At most one of Prefix,
+// Tag and And is set.
+type Filter struct {
+	XMLName xml.Name `xml:"Filter"`
+	Prefix  string   `xml:"Prefix,omitempty"`
+	Tag     *Tag     `xml:"Tag,omitempty"`
+	And     *And     `xml:"And,omitempty"`
+}
+
+// GetPrefix - returns object name prefix of the filter.
+func (filter Filter) GetPrefix() string {
+	if filter.And != nil {
+		return filter.And.Prefix
+	}
+	return filter.Prefix
+}
+
+// Tags - returns tags of the filter.
+func (filter Filter) Tags() []Tag {
+	if filter.And != nil {
+		return filter.And.Tags
+	}
+	if filter.Tag != nil {
+		return []Tag{*filter.Tag}
+	}
+	return nil
+}
+
+// HasTags - returns whether the filter has tag conditions.
+func (filter Filter) HasTags() bool {
+	return len(filter.Tags()) > 0
+}
+
+// TestTags - returns whether given object tags satisfy all tag conditions.
+func (filter Filter) TestTags(objTags map[string]string) bool {
+	for _, tag := range filter.Tags() {
+		if value, found := objTags[tag.Key]; !found || value != tag.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func (filter Filter) isValid() error {
+	count := 0
+	if filter.Prefix != "" {
+		count++
+	}
+	if filter.Tag != nil {
+		count++
+	}
+	if filter.And != nil {
+		count++
+	}
+	if count > 1 {
+		return errMalformedXML("'Filter' must have only one of 'Prefix', 'Tag' or 'And'")
+	}
+
+	if filter.Tag != nil {
+		return filter.Tag.isValid()
+	}
+
+	if filter.And != nil {
+		return filter.And.isValid()
+	}
+
+	return nil
+}
@@ -18,62 +18,242 @@ package lifecycle
 
 import (
 	"encoding/xml"
-	//"fmt"
 	"io"
+	"time"
 )
 
-type Filter struct {
-	Prefix string `xml:"Prefix"`
+// Maximum number of rules in a lifecycle configuration.
+const maxRules = 1000
+
+// Action - action to be applied on an object after evaluating lifecycle rules.
+type Action int
+
+const (
+	// NoneAction - no action required.
+	NoneAction Action = iota
+	// DeleteAction - object needs to be removed.
+	DeleteAction
+)
+
+// Lifecycle - bucket lifecycle configuration.
+type Lifecycle struct {
+	XMLName xml.Name `xml:"LifecycleConfiguration"`
+	Rules   []Rule   `xml:"Rule"`
+}
+
+// IsEmpty - returns whether lifecycle has no rules.
+func (lifecycle Lifecycle) IsEmpty() bool {
+	return len(lifecycle.Rules) == 0
 }
 
-type Transition struct {
-	Days         int    `xml:"Days"`
-	StorageClass string `xml:"StorageClass"`
+// HasExpiry - returns whether any enabled rule expires objects.
+func (lifecycle Lifecycle) HasExpiry() bool {
+	for _, rule := range lifecycle.Rules {
+		if rule.IsEnabled() && rule.Expiration != nil &&
+			(rule.Expiration.Days > 0 || rule.Expiration.Date != nil) {
+			return true
+		}
+	}
+	return false
 }
 
-type Expiration struct {
-	Days int `xml:"Days"`
+// HasTransition - returns whether any enabled rule transitions objects.
+func (lifecycle Lifecycle) HasTransition() bool {
+	for _, rule := range lifecycle.Rules {
+		if rule.IsEnabled() && len(rule.Transitions) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
-type Rule struct {
-	ID         string     `xml:"ID"`
-	Filter     Filter     `xml:"Filter"`
-	Status     string     `xml:"Status"`
-	Transition Transition `xml:"Transition"`
-	Expiration Expiration `xml:"Expiration"`
+// HasAbortMultipartUpload - returns whether any enabled rule aborts
+// incomplete multipart uploads.
+func (lifecycle Lifecycle) HasAbortMultipartUpload() bool {
+	for _, rule := range lifecycle.Rules {
+		if rule.IsEnabled() && rule.AbortIncompleteMultipartUpload != nil {
+			return true
+		}
+	}
+	return false
 }
 
-type LifecycleConfiguration struct {
-	Rule []Rule `xml:"Rule"`
+// FilterRules - returns enabled rules applicable to given object.
+func (lifecycle Lifecycle) FilterRules(objName string, objTags map[string]string) []Rule {
+	var rules []Rule
+	for _, rule := range lifecycle.Rules {
+		if rule.IsEnabled() && rule.Matches(objName, objTags) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
 }
 
-// Lifecycle - bucket lifecycle.
-type Lifecycle struct {
-	Configuration LifecycleConfiguration `xml:"LifecycleConfiguration"`
+// CommonPrefix - returns longest prefix shared by all enabled rules,
+// listing objects under it covers every object any rule may act on.
+func (lifecycle Lifecycle) CommonPrefix() string {
+	var prefixes []string
+	for _, rule := range lifecycle.Rules {
+		if rule.IsEnabled() {
+			prefixes = append(prefixes, rule.GetPrefix())
+		}
+	}
+
+	if len(prefixes) == 0 {
+		return ""
+	}
+
+	common := prefixes[0]
+	for _, prefix := range prefixes[1:] {
+		i := 0
+		for i < len(common) && i < len(prefix) && common[i] == prefix[i] {
+			i++
+		}
+		common = common[:i]
+	}
+	return common
+}
+
+// ComputeAction - returns action to be applied on given object
+// by evaluating all applicable rules against modTime.
+func (lifecycle Lifecycle) ComputeAction(objName string, objTags map[string]string, modTime time.Time) Action {
+	return lifecycle.computeAction(objName, objTags, modTime, time.Now().UTC())
+}
+
+func (lifecycle Lifecycle) computeAction(objName string, objTags map[string]string, modTime, now time.Time) Action {
+	if modTime.IsZero() {
+		return NoneAction
+	}
+
+	for _, rule := range lifecycle.FilterRules(objName, objTags) {
+		if rule.Expiration == nil {
+			continue
+		}
+
+		switch {
+		case rule.Expiration.Days > 0:
+			if !now.Before(ExpectedExpiryTime(modTime, rule.Expiration.Days)) {
+				return DeleteAction
+			}
+		case rule.Expiration.Date != nil:
+			if !now.Before(rule.Expiration.Date.Time) {
+				return DeleteAction
+			}
+		}
+	}
+	return NoneAction
+}
+
+// ComputeTransition - returns storage class given object needs to be
+// transitioned to by evaluating all applicable rules against modTime, or
+// empty string if no transition is due. When several transitions are due,
+// the one to the storage class of the highest tier wins.
+func (lifecycle Lifecycle) ComputeTransition(objName string, objTags map[string]string, modTime time.Time) string {
+	return lifecycle.computeTransition(objName, objTags, modTime, time.Now().UTC())
+}
+
+func (lifecycle Lifecycle) computeTransition(objName string, objTags map[string]string, modTime, now time.Time) string {
+	if modTime.IsZero() {
+		return ""
+	}
+
+	storageClass := ""
+	for _, rule := range lifecycle.FilterRules(objName, objTags) {
+		for _, transition := range rule.Transitions {
+			dueTime := ExpectedExpiryTime(modTime, transition.Days)
+			if transition.Date != nil {
+				dueTime = transition.Date.Time
+			}
+
+			if now.Before(dueTime) {
+				continue
+			}
+
+			if transitionTiers[transition.StorageClass] > transitionTiers[storageClass] {
+				storageClass = transition.StorageClass
+			}
+		}
+	}
+	return storageClass
+}
+
+// ComputeAbortMultipartUpload - returns whether a multipart upload of
+// given object initiated at given time needs to be aborted.
+func (lifecycle Lifecycle) ComputeAbortMultipartUpload(objName string, initiated time.Time) bool {
+	return lifecycle.computeAbortMultipartUpload(objName, initiated, time.Now().UTC())
+}
+
+func (lifecycle Lifecycle) computeAbortMultipartUpload(objName string, initiated, now time.Time) bool {
+	if initiated.IsZero() {
+		return false
+	}
+
+	for _, rule := range lifecycle.FilterRules(objName, nil) {
+		if rule.AbortIncompleteMultipartUpload == nil {
+			continue
+		}
+
+		if !now.Before(ExpectedExpiryTime(initiated, rule.AbortIncompleteMultipartUpload.DaysAfterInitiation)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpectedExpiryTime - returns the time an object created at modTime
+// expires after given days. As per AWS S3 specification, the result is
+// rounded up to the next midnight UTC.
+func ExpectedExpiryTime(modTime time.Time, days int) time.Time {
+	t := modTime.UTC().Add(time.Duration(days+1) * 24 * time.Hour)
+	return t.Truncate(24 * time.Hour)
 }
 
 // isValid - checks if lifecycle is valid or not.
 func (lifecycle Lifecycle) isValid() error {
+	if len(lifecycle.Rules) == 0 {
+		return errMalformedXML("lifecycle configuration must have at least one rule")
+	}
+
+	if len(lifecycle.Rules) > maxRules {
+		return errInvalidArgument("lifecycle configuration must not have more than %v rules", maxRules)
+	}
+
+	ids := make(map[string]struct{})
+	for _, rule := range lifecycle.Rules {
+		if err := rule.isValid(); err != nil {
+			return err
+		}
+
+		if rule.ID == "" {
+			continue
+		}
+
+		if _, found := ids[rule.ID]; found {
+			return errInvalidArgument("rule ID '%v' must be unique", rule.ID)
+		}
+		ids[rule.ID] = struct{}{}
+	}
 
 	return nil
 }
 
-// Validate - validates all rules are for given bucket or not.
+// Validate - validates lifecycle configuration for given bucket.
 func (lifecycle Lifecycle) Validate(bucketName string) error {
-	return nil
+	return lifecycle.isValid()
 }
 
-// ParseConfig - parses data in given reader to Lifecycle
+// ParseConfig - parses data in given reader to Lifecycle.
 func ParseConfig(reader io.Reader, bucketName string) (*Lifecycle, error) {
 	var lifecycle Lifecycle
 
 	decoder := xml.NewDecoder(reader)
-	err := decoder.Decode(&lifecycle)
-
-	if err != nil {
-		return nil, err
+	if err := decoder.Decode(&lifecycle); err != nil {
+		if IsLifecycleError(err) {
+			return nil, err
+		}
+		return nil, errMalformedXML("%v", err)
 	}
 
-	err = lifecycle.Validate(bucketName)
+	err := lifecycle.Validate(bucketName)
 	return &lifecycle, err
 }
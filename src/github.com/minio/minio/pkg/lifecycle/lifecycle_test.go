@@ -0,0 +1,325 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLifecycle(rules ...Rule) Lifecycle {
+	return Lifecycle{Rules: rules}
+}
+
+func TestExpectedExpiryTime(t *testing.T) {
+	testCases := []struct {
+		modTime        time.Time
+		days           int
+		expectedResult time.Time
+	}{
+		{
+			time.Date(2018, time.May, 1, 10, 30, 0, 0, time.UTC),
+			1,
+			time.Date(2018, time.May, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC),
+			30,
+			time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for i, testCase := range testCases {
+		result := ExpectedExpiryTime(testCase.modTime, testCase.days)
+
+		if !result.Equal(testCase.expectedResult) {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestLifecycleCommonPrefix(t *testing.T) {
+	testCases := []struct {
+		lifecycle      Lifecycle
+		expectedResult string
+	}{
+		{newTestLifecycle(), ""},
+		{newTestLifecycle(Rule{Status: Enabled, Filter: &Filter{Prefix: "logs/2018/"}}), "logs/2018/"},
+		{newTestLifecycle(
+			Rule{Status: Enabled, Filter: &Filter{Prefix: "logs/2018/"}},
+			Rule{Status: Enabled, Filter: &Filter{Prefix: "logs/2017/"}},
+		), "logs/201"},
+		{newTestLifecycle(
+			Rule{Status: Enabled, Filter: &Filter{Prefix: "logs/"}},
+			Rule{Status: Disabled, Filter: &Filter{Prefix: "tmp/"}},
+		), "logs/"},
+		{newTestLifecycle(
+			Rule{Status: Enabled, Filter: &Filter{Prefix: "logs/"}},
+			Rule{Status: Enabled, Filter: &Filter{Prefix: "tmp/"}},
+		), ""},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.lifecycle.CommonPrefix()
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestLifecycleComputeAction(t *testing.T) {
+	now := time.Date(2018, time.May, 10, 12, 0, 0, 0, time.UTC)
+	expiryRule := Rule{
+		Status:     Enabled,
+		Filter:     &Filter{Prefix: "logs/"},
+		Expiration: &Expiration{Days: 5},
+	}
+	disabledRule := expiryRule
+	disabledRule.Status = Disabled
+	taggedRule := expiryRule
+	taggedRule.Filter = &Filter{And: &And{Prefix: "logs/", Tags: []Tag{{Key: "class", Value: "tmp"}}}}
+	dateRule := Rule{
+		Status:     Enabled,
+		Expiration: &Expiration{Date: &Date{time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	testCases := []struct {
+		lifecycle      Lifecycle
+		objName        string
+		objTags        map[string]string
+		modTime        time.Time
+		expectedResult Action
+	}{
+		// Object older than expiration days.
+		{newTestLifecycle(expiryRule), "logs/a.log", nil, now.Add(-7 * 24 * time.Hour), DeleteAction},
+		// Object not yet expired.
+		{newTestLifecycle(expiryRule), "logs/a.log", nil, now.Add(-2 * 24 * time.Hour), NoneAction},
+		// Object outside rule prefix.
+		{newTestLifecycle(expiryRule), "data/a.log", nil, now.Add(-7 * 24 * time.Hour), NoneAction},
+		// Disabled rule.
+		{newTestLifecycle(disabledRule), "logs/a.log", nil, now.Add(-7 * 24 * time.Hour), NoneAction},
+		// Unknown modification time.
+		{newTestLifecycle(expiryRule), "logs/a.log", nil, time.Time{}, NoneAction},
+		// Object with matching tags.
+		{newTestLifecycle(taggedRule), "logs/a.log", map[string]string{"class": "tmp"}, now.Add(-7 * 24 * time.Hour), DeleteAction},
+		// Object without matching tags.
+		{newTestLifecycle(taggedRule), "logs/a.log", map[string]string{"class": "keep"}, now.Add(-7 * 24 * time.Hour), NoneAction},
+		{newTestLifecycle(taggedRule), "logs/a.log", nil, now.Add(-7 * 24 * time.Hour), NoneAction},
+		// Expiration date passed.
+		{newTestLifecycle(dateRule), "a.log", nil, now.Add(-time.Hour), DeleteAction},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.lifecycle.computeAction(testCase.objName, testCase.objTags, testCase.modTime, now)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestLifecycleComputeTransition(t *testing.T) {
+	now := time.Date(2018, time.May, 10, 12, 0, 0, 0, time.UTC)
+	transitionRule := Rule{
+		Status: Enabled,
+		Filter: &Filter{Prefix: "logs/"},
+		Transitions: []Transition{
+			{Days: 30, StorageClass: StandardIA},
+			{Days: 90, StorageClass: Glacier},
+		},
+	}
+	disabledRule := transitionRule
+	disabledRule.Status = Disabled
+	dateRule := Rule{
+		Status:      Enabled,
+		Transitions: []Transition{{Date: &Date{time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)}, StorageClass: Glacier}},
+	}
+
+	testCases := []struct {
+		lifecycle      Lifecycle
+		objName        string
+		modTime        time.Time
+		expectedResult string
+	}{
+		// Object not yet due for any transition.
+		{newTestLifecycle(transitionRule), "logs/a.log", now.Add(-7 * 24 * time.Hour), ""},
+		// Object due for first transition.
+		{newTestLifecycle(transitionRule), "logs/a.log", now.Add(-40 * 24 * time.Hour), StandardIA},
+		// Object due for both transitions.
+		{newTestLifecycle(transitionRule), "logs/a.log", now.Add(-100 * 24 * time.Hour), Glacier},
+		// Object outside rule prefix.
+		{newTestLifecycle(transitionRule), "data/a.log", now.Add(-100 * 24 * time.Hour), ""},
+		// Disabled rule.
+		{newTestLifecycle(disabledRule), "logs/a.log", now.Add(-100 * 24 * time.Hour), ""},
+		// Unknown modification time.
+		{newTestLifecycle(transitionRule), "logs/a.log", time.Time{}, ""},
+		// Transition date passed.
+		{newTestLifecycle(dateRule), "a.log", now.Add(-time.Hour), Glacier},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.lifecycle.computeTransition(testCase.objName, nil, testCase.modTime, now)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestLifecycleComputeAbortMultipartUpload(t *testing.T) {
+	now := time.Date(2018, time.May, 10, 12, 0, 0, 0, time.UTC)
+	abortRule := Rule{
+		Status:                         Enabled,
+		Filter:                         &Filter{Prefix: "uploads/"},
+		AbortIncompleteMultipartUpload: &AbortIncompleteMultipartUpload{DaysAfterInitiation: 7},
+	}
+	disabledRule := abortRule
+	disabledRule.Status = Disabled
+	expiryRule := Rule{
+		Status:     Enabled,
+		Expiration: &Expiration{Days: 1},
+	}
+
+	testCases := []struct {
+		lifecycle      Lifecycle
+		objName        string
+		initiated      time.Time
+		expectedResult bool
+	}{
+		// Upload not yet due.
+		{newTestLifecycle(abortRule), "uploads/a.bin", now.Add(-3 * 24 * time.Hour), false},
+		// Upload due.
+		{newTestLifecycle(abortRule), "uploads/a.bin", now.Add(-8 * 24 * time.Hour), true},
+		// Upload outside rule prefix.
+		{newTestLifecycle(abortRule), "data/a.bin", now.Add(-8 * 24 * time.Hour), false},
+		// Disabled rule.
+		{newTestLifecycle(disabledRule), "uploads/a.bin", now.Add(-8 * 24 * time.Hour), false},
+		// Rule without abort action.
+		{newTestLifecycle(expiryRule), "uploads/a.bin", now.Add(-8 * 24 * time.Hour), false},
+		// Unknown initiation time.
+		{newTestLifecycle(abortRule), "uploads/a.bin", time.Time{}, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.lifecycle.computeAbortMultipartUpload(testCase.objName, testCase.initiated, now)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data          string
+		expectedRules int
+		expectedErr   error
+	}{
+		// Filter with prefix.
+		{`<LifecycleConfiguration><Rule><ID>logs</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>30</Days></Expiration></Rule></LifecycleConfiguration>`, 1, nil},
+		// Legacy prefix.
+		{`<LifecycleConfiguration><Rule><Prefix>logs/</Prefix><Status>Enabled</Status><Expiration><Days>30</Days></Expiration></Rule></LifecycleConfiguration>`, 1, nil},
+		// Empty filter.
+		{`<LifecycleConfiguration><Rule><Filter></Filter><Status>Disabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`, 1, nil},
+		// Tag and And filters.
+		{`<LifecycleConfiguration><Rule><ID>a</ID><Filter><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Status>Enabled</Status><Expiration><Date>2018-06-01T00:00:00.000Z</Date></Expiration></Rule>` +
+			`<Rule><ID>b</ID><Filter><And><Prefix>x/</Prefix><Tag><Key>k1</Key><Value>v</Value></Tag><Tag><Key>k2</Key><Value>v</Value></Tag></And></Filter><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>10</NoncurrentDays></NoncurrentVersionExpiration></Rule></LifecycleConfiguration>`, 2, nil},
+		// Transitions in order followed by expiration.
+		{`<LifecycleConfiguration><Rule><Filter></Filter><Status>Enabled</Status><Transition><Days>30</Days><StorageClass>STANDARD_IA</StorageClass></Transition><Transition><Days>90</Days><StorageClass>GLACIER</StorageClass></Transition><Expiration><Days>365</Days></Expiration></Rule></LifecycleConfiguration>`, 1, nil},
+		// Not XML.
+		{`foo`, 0, &ErrMalformedXML{}},
+		// Wrong root element.
+		{`<Lifecycle><Rule><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></Lifecycle>`, 0, &ErrMalformedXML{}},
+		// No rules.
+		{`<LifecycleConfiguration></LifecycleConfiguration>`, 0, &ErrMalformedXML{}},
+		// Invalid status.
+		{`<LifecycleConfiguration><Rule><Status>On</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrMalformedXML{}},
+		// Both filter and legacy prefix.
+		{`<LifecycleConfiguration><Rule><Prefix>a</Prefix><Filter></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrMalformedXML{}},
+		// Filter with both prefix and tag.
+		{`<LifecycleConfiguration><Rule><Filter><Prefix>a</Prefix><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrMalformedXML{}},
+		// Both days and date in expiration.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>1</Days><Date>2018-06-01T00:00:00Z</Date></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrMalformedXML{}},
+		// Negative days.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>-1</Days></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+		// Date not at midnight.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Date>2018-06-01T10:00:00Z</Date></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+		// Duplicate rule IDs.
+		{`<LifecycleConfiguration><Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule><Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>2</Days></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+		// Rule without action.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+		// Transition to infrequent access too early.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Transition><Days>10</Days><StorageClass>STANDARD_IA</StorageClass></Transition></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+		// Transitions out of order.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition><Transition><Days>60</Days><StorageClass>STANDARD_IA</StorageClass></Transition></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+		// Expiration before transition.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition><Expiration><Days>10</Days></Expiration></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+		// Invalid storage class.
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Transition><Days>30</Days><StorageClass>TAPE</StorageClass></Transition></Rule></LifecycleConfiguration>`, 0, &ErrMalformedXML{}},
+		// Abort multipart upload with tags.
+		{`<LifecycleConfiguration><Rule><Filter><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`, 0, &ErrInvalidArgument{}},
+	}
+
+	for i, testCase := range testCases {
+		lc, err := ParseConfig(strings.NewReader(testCase.data), "mybucket")
+
+		if reflect.TypeOf(err) != reflect.TypeOf(testCase.expectedErr) {
+			t.Fatalf("case %v: error: expected: %T, got: %v\n", i+1, testCase.expectedErr, err)
+		}
+
+		if err == nil && len(lc.Rules) != testCase.expectedRules {
+			t.Fatalf("case %v: expected: %v rules, got: %v\n", i+1, testCase.expectedRules, len(lc.Rules))
+		}
+	}
+}
+
+func TestParseConfigTooManyRules(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("<LifecycleConfiguration>")
+	for i := 0; i <= maxRules; i++ {
+		fmt.Fprintf(&buf, "<Rule><ID>%v</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>", i)
+	}
+	buf.WriteString("</LifecycleConfiguration>")
+
+	if _, err := ParseConfig(&buf, "mybucket"); !IsLifecycleError(err) {
+		t.Fatalf("expected: lifecycle error, got: %v\n", err)
+	}
+}
+
+func TestLifecycleMarshalXML(t *testing.T) {
+	data := `<LifecycleConfiguration><Rule><ID>logs</ID><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter><Expiration><Date>2018-06-01T00:00:00.000Z</Date></Expiration></Rule></LifecycleConfiguration>`
+
+	lc, err := ParseConfig(strings.NewReader(data), "mybucket")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := xml.Marshal(lc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(result) != data {
+		t.Fatalf("expected: %v, got: %v\n", data, string(result))
+	}
+}
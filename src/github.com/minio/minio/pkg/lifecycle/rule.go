@@ -0,0 +1,192 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"encoding/xml"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule status values.
+const (
+	Enabled  = "Enabled"
+	Disabled = "Disabled"
+)
+
+// Maximum length of rule ID.
+const maxRuleIDLength = 255
+
+// AbortIncompleteMultipartUpload - removes multipart uploads not completed
+// within given days after initiation.
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+func (abort AbortIncompleteMultipartUpload) isValid() error {
+	if abort.DaysAfterInitiation <= 0 {
+		return errInvalidArgument("'DaysAfterInitiation' for 'AbortIncompleteMultipartUpload' action must be a positive integer")
+	}
+	return nil
+}
+
+// Rule - lifecycle rule.
+type Rule struct {
+	XMLName xml.Name `xml:"Rule"`
+	ID      string   `xml:"ID,omitempty"`
+	Status  string   `xml:"Status"`
+	Filter  *Filter  `xml:"Filter,omitempty"`
+	// Prefix - legacy object name prefix, only allowed without Filter.
+	Prefix string `xml:"Prefix,omitempty"`
+
+	Expiration                     *Expiration                     `xml:"Expiration,omitempty"`
+	Transitions                    []Transition                    `xml:"Transition,omitempty"`
+	NoncurrentVersionExpiration    *NoncurrentVersionExpiration    `xml:"NoncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransitions   []NoncurrentVersionTransition   `xml:"NoncurrentVersionTransition,omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+// IsEnabled - returns whether rule is enabled or not.
+func (rule Rule) IsEnabled() bool {
+	return rule.Status == Enabled
+}
+
+// GetPrefix - returns object name prefix the rule applies to.
+func (rule Rule) GetPrefix() string {
+	if rule.Filter != nil {
+		return rule.Filter.GetPrefix()
+	}
+	return rule.Prefix
+}
+
+// HasTags - returns whether the rule filters on object tags.
+func (rule Rule) HasTags() bool {
+	return rule.Filter != nil && rule.Filter.HasTags()
+}
+
+// Matches - returns whether the rule applies to given object.
+func (rule Rule) Matches(objName string, objTags map[string]string) bool {
+	if !strings.HasPrefix(objName, rule.GetPrefix()) {
+		return false
+	}
+	return rule.Filter == nil || rule.Filter.TestTags(objTags)
+}
+
+func (rule Rule) isValid() error {
+	if utf8.RuneCountInString(rule.ID) > maxRuleIDLength {
+		return errInvalidArgument("rule ID '%v' is longer than %v characters", rule.ID, maxRuleIDLength)
+	}
+
+	if rule.Status != Enabled && rule.Status != Disabled {
+		return errMalformedXML("rule status must be '%v' or '%v'", Enabled, Disabled)
+	}
+
+	if rule.Filter != nil && rule.Prefix != "" {
+		return errMalformedXML("rule must not have both 'Filter' and 'Prefix'")
+	}
+
+	if rule.Filter != nil {
+		if err := rule.Filter.isValid(); err != nil {
+			return err
+		}
+	}
+
+	if rule.Expiration == nil && len(rule.Transitions) == 0 &&
+		rule.NoncurrentVersionExpiration == nil && len(rule.NoncurrentVersionTransitions) == 0 &&
+		rule.AbortIncompleteMultipartUpload == nil {
+		return errInvalidArgument("rule must have at least one action")
+	}
+
+	if err := rule.validateActions(); err != nil {
+		return err
+	}
+
+	if rule.HasTags() {
+		if rule.AbortIncompleteMultipartUpload != nil {
+			return errInvalidArgument("'AbortIncompleteMultipartUpload' cannot be specified with tags")
+		}
+		if rule.Expiration != nil && rule.Expiration.ExpiredObjectDeleteMarker {
+			return errInvalidArgument("'ExpiredObjectDeleteMarker' cannot be specified with tags")
+		}
+	}
+
+	return nil
+}
+
+func (rule Rule) validateActions() error {
+	if rule.Expiration != nil {
+		if err := rule.Expiration.isValid(); err != nil {
+			return err
+		}
+	}
+
+	for _, transition := range rule.Transitions {
+		if err := transition.isValid(); err != nil {
+			return err
+		}
+
+		if rule.Expiration == nil {
+			continue
+		}
+
+		// Expiration must happen after every transition.
+		switch {
+		case transition.Date == nil && rule.Expiration.Days != 0:
+			if rule.Expiration.Days <= transition.Days {
+				return errInvalidArgument("'Days' in 'Expiration' action must be greater than 'Days' in 'Transition' action")
+			}
+		case transition.Date != nil && rule.Expiration.Date != nil:
+			if !rule.Expiration.Date.After(transition.Date.Time) {
+				return errInvalidArgument("'Date' in 'Expiration' action must be later than 'Date' in 'Transition' action")
+			}
+		case transition.Date != nil && rule.Expiration.Days != 0,
+			transition.Date == nil && rule.Expiration.Date != nil:
+			return errInvalidArgument("'Expiration' and 'Transition' actions must both use either 'Days' or 'Date'")
+		}
+	}
+
+	if err := validateTransitionOrder(rule.Transitions); err != nil {
+		return err
+	}
+
+	if rule.NoncurrentVersionExpiration != nil {
+		if err := rule.NoncurrentVersionExpiration.isValid(); err != nil {
+			return err
+		}
+	}
+
+	for _, transition := range rule.NoncurrentVersionTransitions {
+		if err := transition.isValid(); err != nil {
+			return err
+		}
+
+		if rule.NoncurrentVersionExpiration != nil &&
+			rule.NoncurrentVersionExpiration.NoncurrentDays <= transition.NoncurrentDays {
+			return errInvalidArgument("'NoncurrentDays' in 'NoncurrentVersionExpiration' action must be greater than 'NoncurrentDays' in 'NoncurrentVersionTransition' action")
+		}
+	}
+
+	if err := validateNoncurrentTransitionOrder(rule.NoncurrentVersionTransitions); err != nil {
+		return err
+	}
+
+	if rule.AbortIncompleteMultipartUpload != nil {
+		return rule.AbortIncompleteMultipartUpload.isValid()
+	}
+
+	return nil
+}
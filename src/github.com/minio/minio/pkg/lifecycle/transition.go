@@ -0,0 +1,167 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+// Storage classes objects can be transitioned to.
+const (
+	StandardIA         = "STANDARD_IA"
+	OneZoneIA          = "ONEZONE_IA"
+	IntelligentTiering = "INTELLIGENT_TIERING"
+	Glacier            = "GLACIER"
+	DeepArchive        = "DEEP_ARCHIVE"
+)
+
+// Minimum days objects stay in STANDARD before moving to an infrequent
+// access storage class.
+const minInfrequentAccessDays = 30
+
+// transitionTiers - order of storage classes, objects only move to a
+// storage class of a higher tier.
+var transitionTiers = map[string]int{
+	StandardIA:         1,
+	OneZoneIA:          1,
+	IntelligentTiering: 1,
+	Glacier:            2,
+	DeepArchive:        3,
+}
+
+func isValidStorageClass(storageClass string) error {
+	if _, found := transitionTiers[storageClass]; !found {
+		return errMalformedXML("invalid storage class '%v'", storageClass)
+	}
+	return nil
+}
+
+func isInfrequentAccess(storageClass string) bool {
+	return transitionTiers[storageClass] == transitionTiers[StandardIA]
+}
+
+// Transition - transition action of a rule.
+type Transition struct {
+	Days         int    `xml:"Days,omitempty"`
+	Date         *Date  `xml:"Date,omitempty"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+func (transition Transition) isValid() error {
+	if transition.Days != 0 && transition.Date != nil {
+		return errMalformedXML("'Transition' must not have both 'Days' and 'Date'")
+	}
+
+	if transition.Days < 0 {
+		return errInvalidArgument("'Days' for 'Transition' action must be a non-negative integer")
+	}
+
+	if transition.Date != nil {
+		if err := transition.Date.isValid(); err != nil {
+			return err
+		}
+	}
+
+	if err := isValidStorageClass(transition.StorageClass); err != nil {
+		return err
+	}
+
+	if transition.Date == nil && isInfrequentAccess(transition.StorageClass) && transition.Days < minInfrequentAccessDays {
+		return errInvalidArgument("'Days' for 'Transition' action to '%v' must be at least %v",
+			transition.StorageClass, minInfrequentAccessDays)
+	}
+
+	return nil
+}
+
+// NoncurrentVersionTransition - transition action of noncurrent object versions.
+type NoncurrentVersionTransition struct {
+	NoncurrentDays int    `xml:"NoncurrentDays"`
+	StorageClass   string `xml:"StorageClass"`
+}
+
+func (transition NoncurrentVersionTransition) isValid() error {
+	if transition.NoncurrentDays < 0 {
+		return errInvalidArgument("'NoncurrentDays' for 'NoncurrentVersionTransition' action must be a non-negative integer")
+	}
+
+	if err := isValidStorageClass(transition.StorageClass); err != nil {
+		return err
+	}
+
+	if isInfrequentAccess(transition.StorageClass) && transition.NoncurrentDays < minInfrequentAccessDays {
+		return errInvalidArgument("'NoncurrentDays' for 'NoncurrentVersionTransition' action to '%v' must be at least %v",
+			transition.StorageClass, minInfrequentAccessDays)
+	}
+
+	return nil
+}
+
+// validateTransitionOrder - checks storage class of every transition is
+// of a higher tier and happens later than its previous transitions.
+func validateTransitionOrder(transitions []Transition) error {
+	for i := range transitions {
+		for _, transition := range transitions[i+1:] {
+			prev := transitions[i]
+			if (prev.Date == nil) != (transition.Date == nil) {
+				return errInvalidArgument("'Transition' actions must all use either 'Days' or 'Date'")
+			}
+
+			if prev.StorageClass == transition.StorageClass {
+				return errInvalidArgument("duplicate 'Transition' to storage class '%v'", prev.StorageClass)
+			}
+
+			// Order the pair by time.
+			if transition.Date != nil && transition.Date.Before(prev.Date.Time) ||
+				transition.Date == nil && transition.Days < prev.Days {
+				prev, transition = transition, prev
+			}
+
+			if transitionTiers[prev.StorageClass] >= transitionTiers[transition.StorageClass] {
+				return errInvalidArgument("'Transition' to '%v' must happen before 'Transition' to '%v'",
+					transition.StorageClass, prev.StorageClass)
+			}
+
+			if transition.Date != nil && !transition.Date.After(prev.Date.Time) ||
+				transition.Date == nil && transition.Days == prev.Days {
+				return errInvalidArgument("'Transition' to '%v' and '%v' must not happen at the same time",
+					prev.StorageClass, transition.StorageClass)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateNoncurrentTransitionOrder(transitions []NoncurrentVersionTransition) error {
+	for i := range transitions {
+		for _, transition := range transitions[i+1:] {
+			prev := transitions[i]
+			if prev.StorageClass == transition.StorageClass {
+				return errInvalidArgument("duplicate 'NoncurrentVersionTransition' to storage class '%v'", prev.StorageClass)
+			}
+
+			if transition.NoncurrentDays < prev.NoncurrentDays {
+				prev, transition = transition, prev
+			}
+
+			if transitionTiers[prev.StorageClass] >= transitionTiers[transition.StorageClass] ||
+				transition.NoncurrentDays == prev.NoncurrentDays {
+				return errInvalidArgument("'NoncurrentVersionTransition' to '%v' must happen before 'NoncurrentVersionTransition' to '%v'",
+					prev.StorageClass, transition.StorageClass)
+			}
+		}
+	}
+
+	return nil
+}
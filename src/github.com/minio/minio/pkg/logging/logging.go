@@ -0,0 +1,147 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package logging implements the server access logging configuration
+// of buckets and the format of server access log records.
+package logging
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMissingTargetBucket - logging is enabled without a target bucket.
+var ErrMissingTargetBucket = errors.New("TargetBucket must be specified")
+
+// LoggingEnabled - target of the access logs of a bucket.
+type LoggingEnabled struct {
+	TargetBucket string `xml:"TargetBucket"`
+	TargetPrefix string `xml:"TargetPrefix"`
+}
+
+// Config - server access logging configuration of a bucket. Logging is
+// disabled if LoggingEnabled is nil.
+type Config struct {
+	XMLName        xml.Name        `xml:"BucketLoggingStatus"`
+	XMLNS          string          `xml:"xmlns,attr,omitempty"`
+	LoggingEnabled *LoggingEnabled `xml:"LoggingEnabled,omitempty"`
+}
+
+// IsEnabled - returns whether access logging is enabled.
+func (config Config) IsEnabled() bool {
+	return config.LoggingEnabled != nil
+}
+
+// Validate - validates the logging configuration.
+func (config Config) Validate() error {
+	if config.LoggingEnabled != nil && config.LoggingEnabled.TargetBucket == "" {
+		return ErrMissingTargetBucket
+	}
+
+	return nil
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Entry - record of a request in the server access log.
+type Entry struct {
+	BucketOwner    string
+	Bucket         string
+	Time           time.Time
+	RemoteIP       string
+	Requester      string
+	RequestID      string
+	Operation      string
+	Key            string
+	RequestURI     string
+	HTTPStatus     int
+	ErrorCode      string
+	BytesSent      int64
+	ObjectSize     int64
+	TotalTime      time.Duration
+	TurnAroundTime time.Duration
+	Referrer       string
+	UserAgent      string
+	VersionID      string
+}
+
+// field - returns value, or "-" if value is empty.
+func field(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// quotedField - returns value quoted, or "-" if value is empty.
+func quotedField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return strconv.Quote(value)
+}
+
+// sizeField - returns size, or "-" if size is not positive.
+func sizeField(size int64) string {
+	if size <= 0 {
+		return "-"
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+// String - returns the entry in server access log format, as used by
+// Amazon S3, without trailing newline.
+func (entry Entry) String() string {
+	fields := []string{
+		field(entry.BucketOwner),
+		field(entry.Bucket),
+		"[" + entry.Time.UTC().Format("02/Jan/2006:15:04:05 -0700") + "]",
+		field(entry.RemoteIP),
+		field(entry.Requester),
+		field(entry.RequestID),
+		field(entry.Operation),
+		field(url.QueryEscape(entry.Key)),
+		quotedField(entry.RequestURI),
+		field(strconv.Itoa(entry.HTTPStatus)),
+		field(entry.ErrorCode),
+		sizeField(entry.BytesSent),
+		sizeField(entry.ObjectSize),
+		strconv.FormatInt(int64(entry.TotalTime/time.Millisecond), 10),
+		strconv.FormatInt(int64(entry.TurnAroundTime/time.Millisecond), 10),
+		quotedField(entry.Referrer),
+		quotedField(entry.UserAgent),
+		field(entry.VersionID),
+	}
+
+	return strings.Join(fields, " ")
+}
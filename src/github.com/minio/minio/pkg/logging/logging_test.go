@@ -0,0 +1,100 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data          string
+		expectEnabled bool
+		expectErr     bool
+	}{
+		{`<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>mybucket/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, true, false},
+		{`<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket></LoggingEnabled></BucketLoggingStatus>`, true, false},
+		// Disabled logging.
+		{`<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/" />`, false, false},
+		// Missing target bucket.
+		{`<BucketLoggingStatus><LoggingEnabled><TargetPrefix>mybucket/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, false, true},
+		// Invalid XML.
+		{`<BucketLoggingStatus>`, false, true},
+	}
+
+	for i, testCase := range testCases {
+		config, err := ParseConfig(strings.NewReader(testCase.data))
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !expectErr && config.IsEnabled() != testCase.expectEnabled {
+			t.Fatalf("case %v: enabled: expected: %v, got: %v\n", i+1, testCase.expectEnabled, config.IsEnabled())
+		}
+	}
+}
+
+func TestEntryString(t *testing.T) {
+	testCases := []struct {
+		entry          Entry
+		expectedResult string
+	}{
+		{
+			Entry{
+				BucketOwner:    "minio",
+				Bucket:         "mybucket",
+				Time:           time.Date(2018, time.October, 2, 15, 4, 5, 0, time.UTC),
+				RemoteIP:       "192.168.1.10",
+				Requester:      "minio",
+				RequestID:      "1552F5F8A9F4D5A8",
+				Operation:      "REST.GET.OBJECT",
+				Key:            "photos/2018/a b.jpg",
+				RequestURI:     "GET /mybucket/photos/2018/a%20b.jpg HTTP/1.1",
+				HTTPStatus:     200,
+				BytesSent:      2048,
+				ObjectSize:     2048,
+				TotalTime:      25 * time.Millisecond,
+				TurnAroundTime: 10 * time.Millisecond,
+				UserAgent:      "Minio (linux; amd64) minio-go/v6.0.6",
+			},
+			`minio mybucket [02/Oct/2018:15:04:05 +0000] 192.168.1.10 minio 1552F5F8A9F4D5A8 REST.GET.OBJECT photos%2F2018%2Fa+b.jpg "GET /mybucket/photos/2018/a%20b.jpg HTTP/1.1" 200 - 2048 2048 25 10 - "Minio (linux; amd64) minio-go/v6.0.6" -`,
+		},
+		{
+			Entry{
+				Bucket:     "mybucket",
+				Time:       time.Date(2018, time.October, 2, 15, 4, 5, 0, time.FixedZone("IST", 19800)),
+				RemoteIP:   "192.168.1.10",
+				Operation:  "REST.GET.BUCKET",
+				RequestURI: "GET /mybucket HTTP/1.1",
+				HTTPStatus: 403,
+				ErrorCode:  "AccessDenied",
+				BytesSent:  243,
+			},
+			`- mybucket [02/Oct/2018:09:34:05 +0000] 192.168.1.10 - - REST.GET.BUCKET - "GET /mybucket HTTP/1.1" 403 AccessDenied 243 - 0 0 - - -`,
+		},
+	}
+
+	for i, testCase := range testCases {
+		if result := testCase.entry.String(); result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// +build ignore
+
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func main() {
+	// Note: YOUR-ACCESSKEYID, YOUR-SECRETACCESSKEY, alice and
+	// alice-secret-key are dummy values, please replace them with
+	// original values.
+
+	// API requests are secure (HTTPS) if secure=true and insecure (HTTPS) otherwise.
+	// New returns an Minio Admin client object.
+	madmClnt, err := madmin.New("your-minio.example.com:9000", "YOUR-ACCESSKEYID", "YOUR-SECRETACCESSKEY", true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Add a user and allow it to read all buckets.
+	if err = madmClnt.AddUser("alice", "alice-secret-key"); err != nil {
+		log.Fatalln(err)
+	}
+	if err = madmClnt.SetUserPolicy("alice", "readonly"); err != nil {
+		log.Fatalln(err)
+	}
+
+	users, err := madmClnt.ListUsers()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for accessKey, user := range users {
+		log.Println(accessKey, user.PolicyName, user.Status)
+	}
+}
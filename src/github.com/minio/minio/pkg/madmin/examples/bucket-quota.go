@@ -0,0 +1,50 @@
+// +build ignore
+
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func main() {
+	// Note: YOUR-ACCESSKEYID, YOUR-SECRETACCESSKEY are
+	// dummy values, please replace them with original values.
+
+	// API requests are secure (HTTPS) if secure=true and insecure (HTTPS) otherwise.
+	// New returns an Minio Admin client object.
+	madmClnt, err := madmin.New("your-minio.example.com:9000", "YOUR-ACCESSKEYID", "YOUR-SECRETACCESSKEY", true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Reject writes to mybucket beyond 10GiB.
+	quota := madmin.BucketQuota{Quota: 10 << 30, Type: madmin.HardQuota}
+	if err = madmClnt.SetBucketQuota("mybucket", quota); err != nil {
+		log.Fatalln(err)
+	}
+
+	usage, err := madmClnt.GetBucketUsage("mybucket")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("mybucket uses %d of %d bytes in %d objects\n", usage.Size, quota.Quota, usage.Objects)
+}
@@ -0,0 +1,114 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Key rotation summary constants
+const (
+	KeyRotationRunning  = "running"
+	KeyRotationFinished = "finished"
+	KeyRotationFailed   = "failed"
+)
+
+// KeyRotationOpts - options of a master key rotation. Objects sealed
+// by OldKeyID are re-sealed by NewKeyID. If OldKeyID is empty, objects
+// sealed by any master key other than NewKeyID are re-sealed. If
+// NewKeyID is empty, the server's default master key is used.
+type KeyRotationOpts struct {
+	OldKeyID string `json:"oldKeyID,omitempty"`
+	NewKeyID string `json:"newKeyID,omitempty"`
+}
+
+// KeyRotationStatus - status of a master key rotation. Bucket and
+// Object are the last object processed, from where an interrupted
+// rotation resumes.
+type KeyRotationStatus struct {
+	Summary       string          `json:"summary"`
+	FailureDetail string          `json:"detail,omitempty"`
+	StartTime     time.Time       `json:"startTime"`
+	LastUpdate    time.Time       `json:"lastUpdate"`
+	Settings      KeyRotationOpts `json:"settings"`
+
+	Bucket    string `json:"bucket,omitempty"`
+	Object    string `json:"object,omitempty"`
+	VersionID string `json:"versionId,omitempty"`
+
+	ObjectsScanned int64 `json:"objectsScanned"`
+	ObjectsRotated int64 `json:"objectsRotated"`
+	ObjectsFailed  int64 `json:"objectsFailed"`
+}
+
+// RotateKeys - starts re-sealing the object keys of all SSE-S3 and
+// SSE-KMS encrypted objects under a new master key. The object data
+// is not rewritten.
+func (adm *AdminClient) RotateKeys(opts KeyRotationOpts) (status KeyRotationStatus, err error) {
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return status, err
+	}
+
+	// Execute POST on /minio/admin/v1/rotate-keys to start the rotation.
+	resp, err := adm.executeMethod("POST", requestData{
+		relPath: "/v1/rotate-keys",
+		content: body,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return status, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return status, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return status, err
+	}
+
+	err = json.Unmarshal(respBytes, &status)
+	return status, err
+}
+
+// KeyRotationStatus - returns the status of the current or last
+// master key rotation.
+func (adm *AdminClient) KeyRotationStatus() (status KeyRotationStatus, err error) {
+	// Execute GET on /minio/admin/v1/rotate-keys to get the status.
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/rotate-keys"})
+	defer closeResponse(resp)
+	if err != nil {
+		return status, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return status, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return status, err
+	}
+
+	err = json.Unmarshal(respBytes, &status)
+	return status, err
+}
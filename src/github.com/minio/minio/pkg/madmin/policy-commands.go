@@ -0,0 +1,95 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// AddCannedPolicy - adds a custom canned policy, or replaces an
+// existing one. The policy is an identity policy document as used by
+// AWS IAM, which has no Principal and applies to the users and groups
+// it is set for.
+func (adm *AdminClient) AddCannedPolicy(policyName string, policy []byte) error {
+	queryValues := url.Values{}
+	queryValues.Set("name", policyName)
+
+	// Execute PUT on /minio/admin/v1/add-canned-policy to add policy.
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/add-canned-policy",
+		queryValues: queryValues,
+		content:     policy,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// RemoveCannedPolicy - removes a custom canned policy.
+func (adm *AdminClient) RemoveCannedPolicy(policyName string) error {
+	queryValues := url.Values{}
+	queryValues.Set("name", policyName)
+
+	// Execute DELETE on /minio/admin/v1/remove-canned-policy to remove policy.
+	resp, err := adm.executeMethod("DELETE", requestData{
+		relPath:     "/v1/remove-canned-policy",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// ListCannedPolicies - lists built-in and custom canned policies.
+func (adm *AdminClient) ListCannedPolicies() (map[string]json.RawMessage, error) {
+	// Execute GET on /minio/admin/v1/list-canned-policies to list policies.
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/list-canned-policies"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies map[string]json.RawMessage
+	if err = json.Unmarshal(respBytes, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
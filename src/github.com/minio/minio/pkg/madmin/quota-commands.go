@@ -0,0 +1,156 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// QuotaType - how a bucket quota is enforced.
+type QuotaType string
+
+const (
+	// HardQuota - writes exceeding the quota are rejected.
+	HardQuota QuotaType = "hard"
+	// FIFOQuota - oldest objects are removed to stay under the quota.
+	FIFOQuota QuotaType = "fifo"
+)
+
+// BucketQuota - maximum size in bytes of all objects of a bucket.
+type BucketQuota struct {
+	Quota uint64    `json:"quota"`
+	Type  QuotaType `json:"quotatype"`
+}
+
+// IsValid - returns whether the quota has a known type and a non zero size.
+func (q BucketQuota) IsValid() bool {
+	return q.Quota > 0 && (q.Type == HardQuota || q.Type == FIFOQuota)
+}
+
+// BucketUsage - size in bytes and number of objects of a bucket.
+type BucketUsage struct {
+	Size    uint64 `json:"size"`
+	Objects uint64 `json:"objects"`
+}
+
+// SetBucketQuota - sets the quota of a bucket, replacing an existing one.
+func (adm *AdminClient) SetBucketQuota(bucket string, quota BucketQuota) error {
+	data, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute PUT on /minio/admin/v1/set-bucket-quota to set quota.
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/set-bucket-quota",
+		queryValues: queryValues,
+		content:     data,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// GetBucketQuota - returns the quota of a bucket.
+func (adm *AdminClient) GetBucketQuota(bucket string) (quota BucketQuota, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute GET on /minio/admin/v1/get-bucket-quota to get quota.
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/get-bucket-quota",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return quota, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return quota, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return quota, err
+	}
+
+	err = json.Unmarshal(respBytes, &quota)
+	return quota, err
+}
+
+// RemoveBucketQuota - removes the quota of a bucket.
+func (adm *AdminClient) RemoveBucketQuota(bucket string) error {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute DELETE on /minio/admin/v1/remove-bucket-quota to remove quota.
+	resp, err := adm.executeMethod("DELETE", requestData{
+		relPath:     "/v1/remove-bucket-quota",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// GetBucketUsage - returns the size and number of objects of a bucket
+// as accounted by the server.
+func (adm *AdminClient) GetBucketUsage(bucket string) (usage BucketUsage, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	// Execute GET on /minio/admin/v1/bucket-usage to get usage.
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/bucket-usage",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return usage, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return usage, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return usage, err
+	}
+
+	err = json.Unmarshal(respBytes, &usage)
+	return usage, err
+}
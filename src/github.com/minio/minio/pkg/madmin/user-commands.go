@@ -0,0 +1,256 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// AccountStatus - account status.
+type AccountStatus string
+
+// Account status per user.
+const (
+	AccountEnabled  AccountStatus = "enabled"
+	AccountDisabled AccountStatus = "disabled"
+)
+
+// UserInfo - carries information about a user. SecretKey is only
+// sent to the server and never returned by ListUsers.
+type UserInfo struct {
+	SecretKey  string        `json:"secretKey,omitempty"`
+	PolicyName string        `json:"policyName,omitempty"`
+	MemberOf   []string      `json:"memberOf,omitempty"`
+	Status     AccountStatus `json:"status"`
+}
+
+// GroupInfo - carries information about a group.
+type GroupInfo struct {
+	PolicyName string        `json:"policyName,omitempty"`
+	Members    []string      `json:"members"`
+	Status     AccountStatus `json:"status"`
+}
+
+// GroupAddRemove - members to add to or remove from a group. A group
+// is created on first addition and removed with its last member.
+type GroupAddRemove struct {
+	Group    string   `json:"group"`
+	Members  []string `json:"members"`
+	IsRemove bool     `json:"isRemove"`
+}
+
+// AddUser - adds a user with the given credentials, or updates the
+// secret key of an existing user.
+func (adm *AdminClient) AddUser(accessKey, secretKey string) error {
+	// No TLS?
+	if !adm.secure {
+		return fmt.Errorf("credentials cannot be added over an insecure connection")
+	}
+
+	body, err := json.Marshal(UserInfo{
+		SecretKey: secretKey,
+		Status:    AccountEnabled,
+	})
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+
+	// Execute PUT on /minio/admin/v1/add-user to add a user.
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/add-user",
+		queryValues: queryValues,
+		content:     body,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// RemoveUser - removes a user and its policy mapping.
+func (adm *AdminClient) RemoveUser(accessKey string) error {
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+
+	// Execute DELETE on /minio/admin/v1/remove-user to remove a user.
+	resp, err := adm.executeMethod("DELETE", requestData{
+		relPath:     "/v1/remove-user",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// ListUsers - lists all users by their access keys.
+func (adm *AdminClient) ListUsers() (map[string]UserInfo, error) {
+	// Execute GET on /minio/admin/v1/list-users to list users.
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/list-users"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var users map[string]UserInfo
+	if err = json.Unmarshal(respBytes, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// SetUserStatus - enables or disables a user.
+func (adm *AdminClient) SetUserStatus(accessKey string, status AccountStatus) error {
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+	queryValues.Set("status", string(status))
+
+	// Execute PUT on /minio/admin/v1/set-user-status to set status.
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/set-user-status",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// SetUserPolicy - attaches a canned policy to a user, replacing any
+// previously attached policy. An empty policy name detaches it.
+func (adm *AdminClient) SetUserPolicy(accessKey, policyName string) error {
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+	queryValues.Set("name", policyName)
+
+	// Execute PUT on /minio/admin/v1/set-user-policy to set policy.
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/set-user-policy",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// UpdateGroupMembers - adds members to or removes members from a group.
+func (adm *AdminClient) UpdateGroupMembers(g GroupAddRemove) error {
+	body, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	// Execute PUT on /minio/admin/v1/update-group-members to update group.
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath: "/v1/update-group-members",
+		content: body,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// ListGroups - lists all groups by their names.
+func (adm *AdminClient) ListGroups() (map[string]GroupInfo, error) {
+	// Execute GET on /minio/admin/v1/list-groups to list groups.
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/list-groups"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups map[string]GroupInfo
+	if err = json.Unmarshal(respBytes, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// SetGroupPolicy - attaches a canned policy to a group, replacing any
+// previously attached policy. An empty policy name detaches it.
+func (adm *AdminClient) SetGroupPolicy(group, policyName string) error {
+	queryValues := url.Values{}
+	queryValues.Set("group", group)
+	queryValues.Set("name", policyName)
+
+	// Execute PUT on /minio/admin/v1/set-group-policy to set policy.
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/set-group-policy",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
@@ -41,9 +41,33 @@ const (
 	// DeleteBucketPolicyAction - DeleteBucketPolicy Rest API action.
 	DeleteBucketPolicyAction = "s3:DeleteBucketPolicy"
 
+	// DeleteBucketWebsiteAction - DeleteBucketWebsite Rest API action.
+	DeleteBucketWebsiteAction = "s3:DeleteBucketWebsite"
+
 	// DeleteObjectAction - DeleteObject Rest API action.
 	DeleteObjectAction = "s3:DeleteObject"
 
+	// DeleteObjectTaggingAction - DeleteObjectTagging Rest API action.
+	DeleteObjectTaggingAction = "s3:DeleteObjectTagging"
+
+	// DeleteObjectVersionAction - DeleteObject Rest API action with version ID.
+	DeleteObjectVersionAction = "s3:DeleteObjectVersion"
+
+	// GetBucketACLAction - GetBucketAcl Rest API action.
+	GetBucketACLAction = "s3:GetBucketAcl"
+
+	// GetBucketCORSAction - GetBucketCors Rest API action.
+	GetBucketCORSAction = "s3:GetBucketCORS"
+
+	// GetBucketEncryptionAction - GetBucketEncryption Rest API action.
+	GetBucketEncryptionAction = "s3:GetEncryptionConfiguration"
+
+	// GetBucketWebsiteAction - GetBucketWebsite Rest API action.
+	GetBucketWebsiteAction = "s3:GetBucketWebsite"
+
+	// GetBucketLoggingAction - GetBucketLogging Rest API action.
+	GetBucketLoggingAction = "s3:GetBucketLogging"
+
 	// GetBucketLocationAction - GetBucketLocation Rest API action.
 	GetBucketLocationAction = "s3:GetBucketLocation"
 
@@ -53,9 +77,27 @@ const (
 	// GetBucketPolicyAction - GetBucketPolicy Rest API action.
 	GetBucketPolicyAction = "s3:GetBucketPolicy"
 
+	// GetReplicationConfigurationAction - GetBucketReplication Rest API action.
+	GetReplicationConfigurationAction = "s3:GetReplicationConfiguration"
+
+	// GetBucketTaggingAction - GetBucketTagging Rest API action.
+	GetBucketTaggingAction = "s3:GetBucketTagging"
+
+	// GetBucketVersioningAction - GetBucketVersioning Rest API action.
+	GetBucketVersioningAction = "s3:GetBucketVersioning"
+
 	// GetObjectAction - GetObject Rest API action.
 	GetObjectAction = "s3:GetObject"
 
+	// GetObjectACLAction - GetObjectAcl Rest API action.
+	GetObjectACLAction = "s3:GetObjectAcl"
+
+	// GetObjectTaggingAction - GetObjectTagging Rest API action.
+	GetObjectTaggingAction = "s3:GetObjectTagging"
+
+	// GetObjectVersionAction - GetObject Rest API action with version ID.
+	GetObjectVersionAction = "s3:GetObjectVersion"
+
 	// HeadBucketAction - HeadBucket Rest API action. This action is unused in minio.
 	HeadBucketAction = "s3:HeadBucket"
 
@@ -65,6 +107,9 @@ const (
 	// ListBucketAction - ListBucket Rest API action.
 	ListBucketAction = "s3:ListBucket"
 
+	// ListBucketVersionsAction - ListObjectVersions Rest API action.
+	ListBucketVersionsAction = "s3:ListBucketVersions"
+
 	// ListBucketMultipartUploadsAction - ListMultipartUploads Rest API action.
 	ListBucketMultipartUploadsAction = "s3:ListBucketMultipartUploads"
 
@@ -75,14 +120,50 @@ const (
 	// ListMultipartUploadPartsAction - ListParts Rest API action.
 	ListMultipartUploadPartsAction = "s3:ListMultipartUploadParts"
 
+	// PutBucketACLAction - PutBucketAcl Rest API action.
+	PutBucketACLAction = "s3:PutBucketAcl"
+
+	// PutBucketCORSAction - PutBucketCors and DeleteBucketCors Rest API action.
+	PutBucketCORSAction = "s3:PutBucketCORS"
+
+	// PutBucketEncryptionAction - PutBucketEncryption and DeleteBucketEncryption Rest API action.
+	PutBucketEncryptionAction = "s3:PutEncryptionConfiguration"
+
+	// PutBucketWebsiteAction - PutBucketWebsite Rest API action.
+	PutBucketWebsiteAction = "s3:PutBucketWebsite"
+
+	// PutBucketLoggingAction - PutBucketLogging Rest API action.
+	PutBucketLoggingAction = "s3:PutBucketLogging"
+
 	// PutBucketNotificationAction - PutObjectNotification Rest API action.
 	PutBucketNotificationAction = "s3:PutBucketNotification"
 
 	// PutBucketPolicyAction - PutBucketPolicy Rest API action.
 	PutBucketPolicyAction = "s3:PutBucketPolicy"
 
+	// PutReplicationConfigurationAction - PutBucketReplication and DeleteBucketReplication Rest API action.
+	PutReplicationConfigurationAction = "s3:PutReplicationConfiguration"
+
+	// PutBucketTaggingAction - PutBucketTagging and DeleteBucketTagging Rest API action.
+	PutBucketTaggingAction = "s3:PutBucketTagging"
+
+	// PutBucketVersioningAction - PutBucketVersioning Rest API action.
+	PutBucketVersioningAction = "s3:PutBucketVersioning"
+
 	// PutObjectAction - PutObject Rest API action.
 	PutObjectAction = "s3:PutObject"
+
+	// PutObjectACLAction - PutObjectAcl Rest API action.
+	PutObjectACLAction = "s3:PutObjectAcl"
+
+	// PutObjectTaggingAction - PutObjectTagging Rest API action.
+	PutObjectTaggingAction = "s3:PutObjectTagging"
+
+	// ReplicateObjectAction - writing replicas of objects by bucket replication.
+	ReplicateObjectAction = "s3:ReplicateObject"
+
+	// RestoreObjectAction - RestoreObject Rest API action.
+	RestoreObjectAction = "s3:RestoreObject"
 )
 
 // isObjectAction - returns whether action is object type or not.
@@ -90,7 +171,13 @@ func (action Action) isObjectAction() bool {
 	switch action {
 	case AbortMultipartUploadAction, DeleteObjectAction, GetObjectAction:
 		fallthrough
-	case ListMultipartUploadPartsAction, PutObjectAction:
+	case GetObjectACLAction, ListMultipartUploadPartsAction, PutObjectAction:
+		fallthrough
+	case PutObjectACLAction, ReplicateObjectAction, RestoreObjectAction:
+		fallthrough
+	case DeleteObjectVersionAction, GetObjectVersionAction:
+		fallthrough
+	case DeleteObjectTaggingAction, GetObjectTaggingAction, PutObjectTaggingAction:
 		return true
 	}
 
@@ -113,6 +200,30 @@ func (action Action) IsValid() bool {
 	case ListMultipartUploadPartsAction, PutBucketNotificationAction:
 		fallthrough
 	case PutBucketPolicyAction, PutObjectAction:
+		fallthrough
+	case GetBucketACLAction, GetObjectACLAction, PutBucketACLAction, PutObjectACLAction:
+		fallthrough
+	case RestoreObjectAction:
+		fallthrough
+	case DeleteObjectVersionAction, GetBucketVersioningAction, GetObjectVersionAction:
+		fallthrough
+	case ListBucketVersionsAction, PutBucketVersioningAction:
+		fallthrough
+	case DeleteObjectTaggingAction, GetBucketTaggingAction, GetObjectTaggingAction:
+		fallthrough
+	case PutBucketTaggingAction, PutObjectTaggingAction:
+		fallthrough
+	case GetBucketEncryptionAction, PutBucketEncryptionAction:
+		fallthrough
+	case GetBucketCORSAction, PutBucketCORSAction:
+		fallthrough
+	case GetBucketWebsiteAction, PutBucketWebsiteAction, DeleteBucketWebsiteAction:
+		fallthrough
+	case GetBucketLoggingAction, PutBucketLoggingAction:
+		fallthrough
+	case GetReplicationConfigurationAction, PutReplicationConfigurationAction:
+		fallthrough
+	case ReplicateObjectAction:
 		return true
 	}
 
@@ -178,6 +289,23 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	DeleteObjectTaggingAction: condition.NewKeySet(
+		condition.S3ExistingObjectTag,
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	DeleteObjectVersionAction: condition.NewKeySet(
+		condition.S3ExistingObjectTag,
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	GetBucketLocationAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
@@ -188,15 +316,73 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	DeleteBucketWebsiteAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketWebsiteAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketLoggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetReplicationConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketCORSAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketEncryptionAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	GetBucketPolicyAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
 	),
 
+	GetBucketTaggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetBucketVersioningAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	GetObjectAction: condition.NewKeySet(
 		condition.S3XAmzServerSideEncryption,
 		condition.S3XAmzServerSideEncryptionAwsKMSKeyID,
 		condition.S3XAmzStorageClass,
+		condition.S3ExistingObjectTag,
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetObjectACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetObjectTaggingAction: condition.NewKeySet(
+		condition.S3ExistingObjectTag,
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	GetObjectVersionAction: condition.NewKeySet(
+		condition.S3ExistingObjectTag,
 		condition.AWSReferer,
 		condition.AWSSourceIP,
 	),
@@ -219,6 +405,14 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	ListBucketVersionsAction: condition.NewKeySet(
+		condition.S3Prefix,
+		condition.S3Delimiter,
+		condition.S3MaxKeys,
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	ListBucketMultipartUploadsAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
@@ -234,22 +428,85 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	PutBucketACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	PutBucketNotificationAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
 	),
 
+	PutBucketWebsiteAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketLoggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutReplicationConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketCORSAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketEncryptionAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	PutBucketPolicyAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
 	),
 
+	PutBucketTaggingAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutBucketVersioningAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutObjectACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	PutObjectAction: condition.NewKeySet(
 		condition.S3XAmzCopySource,
 		condition.S3XAmzServerSideEncryption,
 		condition.S3XAmzServerSideEncryptionAwsKMSKeyID,
 		condition.S3XAmzMetadataDirective,
 		condition.S3XAmzStorageClass,
+		condition.S3RequestObjectTag,
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	PutObjectTaggingAction: condition.NewKeySet(
+		condition.S3ExistingObjectTag,
+		condition.S3RequestObjectTag,
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	ReplicateObjectAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
+	RestoreObjectAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
 	),
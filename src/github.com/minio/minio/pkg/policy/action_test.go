@@ -32,7 +32,25 @@ func TestActionIsObjectAction(t *testing.T) {
 		{GetObjectAction, true},
 		{ListMultipartUploadPartsAction, true},
 		{PutObjectAction, true},
+		{GetObjectACLAction, true},
+		{PutObjectACLAction, true},
+		{RestoreObjectAction, true},
+		{ReplicateObjectAction, true},
+		{GetObjectVersionAction, true},
+		{DeleteObjectVersionAction, true},
+		{GetObjectTaggingAction, true},
+		{PutObjectTaggingAction, true},
+		{DeleteObjectTaggingAction, true},
 		{CreateBucketAction, false},
+		{GetBucketACLAction, false},
+		{PutBucketVersioningAction, false},
+		{ListBucketVersionsAction, false},
+		{PutBucketTaggingAction, false},
+		{PutBucketEncryptionAction, false},
+		{PutBucketCORSAction, false},
+		{PutBucketWebsiteAction, false},
+		{PutBucketLoggingAction, false},
+		{PutReplicationConfigurationAction, false},
 	}
 
 	for i, testCase := range testCases {
@@ -50,6 +68,22 @@ func TestActionIsValid(t *testing.T) {
 		expectedResult bool
 	}{
 		{AbortMultipartUploadAction, true},
+		{PutBucketACLAction, true},
+		{GetBucketVersioningAction, true},
+		{GetBucketTaggingAction, true},
+		{PutObjectTaggingAction, true},
+		{GetBucketEncryptionAction, true},
+		{PutBucketEncryptionAction, true},
+		{GetBucketCORSAction, true},
+		{PutBucketCORSAction, true},
+		{GetBucketWebsiteAction, true},
+		{PutBucketWebsiteAction, true},
+		{DeleteBucketWebsiteAction, true},
+		{GetBucketLoggingAction, true},
+		{PutBucketLoggingAction, true},
+		{GetReplicationConfigurationAction, true},
+		{PutReplicationConfigurationAction, true},
+		{ReplicateObjectAction, true},
 		{Action("foo"), false},
 	}
 
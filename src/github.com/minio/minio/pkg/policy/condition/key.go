@@ -63,6 +63,14 @@ const (
 	// S3MaxKeys - key representing max-keys query parameter of ListBucket API only.
 	S3MaxKeys = "s3:max-keys"
 
+	// S3ExistingObjectTag - key representing tags of an existing object, used as
+	// "s3:ExistingObjectTag/<tag-key>" in object APIs only.
+	S3ExistingObjectTag = "s3:ExistingObjectTag"
+
+	// S3RequestObjectTag - key representing tags of x-amz-tagging HTTP header, used as
+	// "s3:RequestObjectTag/<tag-key>" in PutObject and PutObjectTagging APIs only.
+	S3RequestObjectTag = "s3:RequestObjectTag"
+
 	// AWSReferer - key representing Referer header of any API.
 	AWSReferer = "aws:Referer"
 
@@ -81,7 +89,23 @@ func (key Key) IsValid() bool {
 		return true
 	}
 
-	return false
+	// Tag keys are valid only with a tag key suffix.
+	return key.Base() != key
+}
+
+// Base - returns key without tag key suffix i.e. "s3:ExistingObjectTag" for
+// "s3:ExistingObjectTag/<tag-key>", any other key is returned as is.
+func (key Key) Base() Key {
+	keyString := string(key)
+
+	for _, tagKey := range []Key{S3ExistingObjectTag, S3RequestObjectTag} {
+		prefix := string(tagKey) + "/"
+		if strings.HasPrefix(keyString, prefix) && len(keyString) > len(prefix) {
+			return tagKey
+		}
+	}
+
+	return key
 }
 
 // MarshalJSON - encodes Key to JSON data.
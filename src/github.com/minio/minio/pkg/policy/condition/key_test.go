@@ -38,6 +38,10 @@ func TestKeyIsValid(t *testing.T) {
 		{S3MaxKeys, true},
 		{AWSReferer, true},
 		{AWSSourceIP, true},
+		{Key("s3:ExistingObjectTag/env"), true},
+		{Key("s3:RequestObjectTag/env"), true},
+		{S3ExistingObjectTag, false},
+		{Key("s3:RequestObjectTag/"), false},
 		{Key("foo"), false},
 	}
 
@@ -83,6 +87,7 @@ func TestKeyName(t *testing.T) {
 	}{
 		{S3XAmzCopySource, "x-amz-copy-source"},
 		{AWSReferer, "Referer"},
+		{Key("s3:ExistingObjectTag/env"), "ExistingObjectTag/env"},
 	}
 
 	for i, testCase := range testCases {
@@ -212,3 +217,23 @@ func TestKeySetToSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyBase(t *testing.T) {
+	testCases := []struct {
+		key            Key
+		expectedResult Key
+	}{
+		{S3XAmzCopySource, S3XAmzCopySource},
+		{Key("s3:ExistingObjectTag/env"), S3ExistingObjectTag},
+		{Key("s3:RequestObjectTag/a/b"), S3RequestObjectTag},
+		{Key("s3:RequestObjectTag/"), Key("s3:RequestObjectTag/")},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.key.Base()
+
+		if testCase.expectedResult != result {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
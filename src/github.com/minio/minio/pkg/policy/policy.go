@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+
+	"github.com/minio/minio/pkg/policy/condition"
 )
 
 // DefaultVersion - default policy version as per AWS S3 specification.
@@ -45,12 +47,8 @@ type Policy struct {
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (policy Policy) IsAllowed(args Args) bool {
 	// Check all deny statements. If any one statement denies, return false.
-	for _, statement := range policy.Statements {
-		if statement.Effect == Deny {
-			if !statement.IsAllowed(args) {
-				return false
-			}
-		}
+	if policy.IsDenied(args) {
+		return false
 	}
 
 	// For owner, its allowed by default.
@@ -70,6 +68,34 @@ func (policy Policy) IsAllowed(args Args) bool {
 	return false
 }
 
+// HasConditionKey - returns whether any statement of the policy uses
+// given condition key, tag keys match their base key.
+func (policy Policy) HasConditionKey(key condition.Key) bool {
+	for _, statement := range policy.Statements {
+		for k := range statement.Conditions.Keys() {
+			if k.Base() == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsDenied - checks whether any deny statement of the policy explicitly
+// denies given policy args.
+func (policy Policy) IsDenied(args Args) bool {
+	for _, statement := range policy.Statements {
+		if statement.Effect == Deny {
+			if !statement.IsAllowed(args) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (policy Policy) IsEmpty() bool {
 	return len(policy.Statements) == 0
@@ -174,3 +200,49 @@ func ParseConfig(reader io.Reader, bucketName string) (*Policy, error) {
 	err := policy.Validate(bucketName)
 	return &policy, err
 }
+
+// identityStatement - statement of an identity policy, which has no
+// Principal as it applies to the user or group it is attached to.
+type identityStatement struct {
+	SID        ID                  `json:"Sid,omitempty"`
+	Effect     Effect              `json:"Effect"`
+	Principal  *json.RawMessage    `json:"Principal,omitempty"`
+	Actions    ActionSet           `json:"Action"`
+	Resources  ResourceSet         `json:"Resource"`
+	Conditions condition.Functions `json:"Condition,omitempty"`
+}
+
+// ParseIdentityConfig - parses data in given reader to Policy, as an
+// identity policy of users, groups or sessions. Principal is not
+// allowed in identity policies, their statements apply to every account
+// the policy is attached to and get principal '*'.
+func ParseIdentityConfig(reader io.Reader) (*Policy, error) {
+	var identityPolicy struct {
+		ID         ID `json:"ID,omitempty"`
+		Version    string
+		Statements []identityStatement `json:"Statement"`
+	}
+
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&identityPolicy); err != nil {
+		return nil, err
+	}
+
+	policy := Policy{
+		ID:      identityPolicy.ID,
+		Version: identityPolicy.Version,
+	}
+	for _, s := range identityPolicy.Statements {
+		if s.Principal != nil {
+			return nil, fmt.Errorf("Principal is not allowed in identity policy")
+		}
+
+		statement := NewStatement(s.Effect, NewPrincipal("*"), s.Actions, s.Resources, s.Conditions)
+		statement.SID = s.SID
+		policy.Statements = append(policy.Statements, statement)
+	}
+
+	err := policy.isValid()
+	return &policy, err
+}
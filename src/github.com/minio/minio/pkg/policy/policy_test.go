@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"net"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/minio/minio/pkg/policy/condition"
@@ -184,6 +185,93 @@ func TestPolicyIsAllowed(t *testing.T) {
 	}
 }
 
+func TestPolicyHasConditionKey(t *testing.T) {
+	func1, err := condition.NewStringEqualsFunc(condition.Key("s3:ExistingObjectTag/env"), "prod")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	func2, err := condition.NewStringEqualsFunc(condition.S3XAmzCopySource, "mybucket/myobject")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	newPolicy := func(functions condition.Functions) Policy {
+		return Policy{
+			Version: DefaultVersion,
+			Statements: []Statement{
+				NewStatement(
+					Allow,
+					NewPrincipal("*"),
+					NewActionSet(GetObjectAction),
+					NewResourceSet(NewResource("mybucket", "/myobject*")),
+					functions,
+				),
+			},
+		}
+	}
+
+	testCases := []struct {
+		policy         Policy
+		key            condition.Key
+		expectedResult bool
+	}{
+		{newPolicy(condition.NewFunctions(func1)), condition.S3ExistingObjectTag, true},
+		{newPolicy(condition.NewFunctions(func2)), condition.S3ExistingObjectTag, false},
+		{newPolicy(condition.NewFunctions()), condition.S3ExistingObjectTag, false},
+		{newPolicy(condition.NewFunctions(func2)), condition.S3XAmzCopySource, true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.policy.HasConditionKey(testCase.key)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestPolicyIsDenied(t *testing.T) {
+	denyPolicy := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				Allow,
+				NewPrincipal("*"),
+				NewActionSet(GetObjectAction, PutObjectAction),
+				NewResourceSet(NewResource("mybucket", "*")),
+				condition.NewFunctions(),
+			),
+			NewStatement(
+				Deny,
+				NewPrincipal("*"),
+				NewActionSet(PutObjectAction),
+				NewResourceSet(NewResource("mybucket", "*")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+
+	testCases := []struct {
+		policy         Policy
+		args           Args
+		expectedResult bool
+	}{
+		{denyPolicy, Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, false},
+		{denyPolicy, Args{Action: PutObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, true},
+		{denyPolicy, Args{Action: PutObjectAction, BucketName: "mybucket", IsOwner: true, ObjectName: "myobject"}, true},
+		{denyPolicy, Args{Action: PutObjectAction, BucketName: "yourbucket", ObjectName: "myobject"}, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.policy.IsDenied(testCase.args)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
 func TestPolicyIsEmpty(t *testing.T) {
 	case1Policy := Policy{
 		Version: DefaultVersion,
@@ -1087,3 +1175,86 @@ func TestPolicyValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestParseIdentityConfig(t *testing.T) {
+	// Identity policy document as accepted by AWS IAM.
+	awsPolicy := `{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Sid": "ListObjectsInBucket",
+            "Effect": "Allow",
+            "Action": ["s3:ListBucket"],
+            "Resource": ["arn:aws:s3:::mybucket"]
+        },
+        {
+            "Sid": "AllObjectActions",
+            "Effect": "Allow",
+            "Action": ["s3:GetObject", "s3:PutObject", "s3:DeleteObject"],
+            "Resource": ["arn:aws:s3:::mybucket/*"]
+        },
+        {
+            "Effect": "Deny",
+            "Action": "s3:DeleteObject",
+            "Resource": "arn:aws:s3:::mybucket/locked/*"
+        }
+    ]
+}`
+
+	p, err := ParseIdentityConfig(strings.NewReader(awsPolicy))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		args           Args
+		expectedResult bool
+	}{
+		{Args{AccountName: "alice", Action: ListBucketAction, BucketName: "mybucket"}, true},
+		{Args{AccountName: "bob", Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, true},
+		{Args{AccountName: "alice", Action: DeleteObjectAction, BucketName: "mybucket", ObjectName: "myobject"}, true},
+		{Args{AccountName: "alice", Action: DeleteObjectAction, BucketName: "mybucket", ObjectName: "locked/myobject"}, false},
+		{Args{AccountName: "alice", Action: GetObjectAction, BucketName: "yourbucket", ObjectName: "myobject"}, false},
+	}
+
+	for i, testCase := range testCases {
+		if result := p.IsAllowed(testCase.args); result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+
+	// Bucket policies require a principal.
+	if _, err = ParseConfig(strings.NewReader(awsPolicy), "mybucket"); err == nil {
+		t.Fatalf("expected error for bucket policy without principal")
+	}
+
+	// Stored identity policies are valid policies.
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+	var loaded Policy
+	if err = json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+	if !reflect.DeepEqual(loaded, *p) {
+		t.Fatalf("expected: %v, got: %v\n", *p, loaded)
+	}
+
+	invalidPolicies := []string{
+		// Principal is not allowed.
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`,
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`,
+		// Invalid version.
+		`{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`,
+		// Object action on bucket resource.
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket"]}]}`,
+		// Unknown field.
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotAction":["s3:GetObject"],"Resource":["arn:aws:s3:::mybucket/*"]}]}`,
+	}
+	for i, s := range invalidPolicies {
+		if _, err = ParseIdentityConfig(strings.NewReader(s)); err == nil {
+			t.Fatalf("case %v: expected error for %v", i+1, s)
+		}
+	}
+}
@@ -93,7 +93,12 @@ func (statement Statement) isValid() error {
 			}
 		}
 
-		keys := statement.Conditions.Keys()
+		// Tag keys are supported by their base keys.
+		keys := condition.NewKeySet()
+		for key := range statement.Conditions.Keys() {
+			keys.Add(key.Base())
+		}
+
 		keyDiff := keys.Difference(actionConditionKeyMap[action])
 		if !keyDiff.IsEmpty() {
 			return fmt.Errorf("unsupported condition keys '%v' used for action '%v'", keyDiff, action)
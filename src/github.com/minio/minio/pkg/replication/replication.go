@@ -0,0 +1,216 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package replication implements the replication configuration of buckets
+// and the remote targets objects are replicated to.
+package replication
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Maximum number of rules of a replication configuration.
+const maxRules = 1000
+
+// Status of a replication rule.
+const (
+	Enabled  = "Enabled"
+	Disabled = "Disabled"
+)
+
+// Replication status of an object.
+const (
+	StatusPending   = "PENDING"
+	StatusCompleted = "COMPLETED"
+	StatusFailed    = "FAILED"
+	StatusReplica   = "REPLICA"
+)
+
+// ErrInvalidRules - the configuration does not contain between 1 and 1000 rules.
+var ErrInvalidRules = errors.New("between 1 and 1000 replication rules must be specified")
+
+// Storage classes of replicated objects.
+var storageClasses = map[string]bool{
+	"STANDARD":           true,
+	"REDUCED_REDUNDANCY": true,
+	"STANDARD_IA":        true,
+	"ONEZONE_IA":         true,
+}
+
+// ARN - destination bucket of a replication rule on a remote target.
+type ARN struct {
+	Region   string
+	TargetID string
+	Bucket   string
+}
+
+// String - returns string representation.
+func (arn ARN) String() string {
+	return "arn:minio:replication:" + arn.Region + ":" + arn.TargetID + ":" + arn.Bucket
+}
+
+// ParseARN - parses string to ARN, which must be in the format of
+// arn:minio:replication:<REGION>:<TARGET-ID>:<BUCKET>.
+func ParseARN(s string) (ARN, error) {
+	tokens := strings.Split(s, ":")
+	if len(tokens) != 6 || tokens[0] != "arn" || tokens[1] != "minio" || tokens[2] != "replication" ||
+		tokens[4] == "" || tokens[5] == "" {
+		return ARN{}, fmt.Errorf("invalid replication destination ARN '%v'", s)
+	}
+
+	return ARN{
+		Region:   tokens[3],
+		TargetID: tokens[4],
+		Bucket:   tokens[5],
+	}, nil
+}
+
+// Destination - bucket and storage class replicated objects are written to.
+type Destination struct {
+	Bucket       string `xml:"Bucket"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+}
+
+// ARN - returns the parsed destination bucket ARN.
+func (d Destination) ARN() (ARN, error) {
+	return ParseARN(d.Bucket)
+}
+
+// Rule - replication rule, replicating objects with its prefix.
+type Rule struct {
+	ID          string      `xml:"ID,omitempty"`
+	Status      string      `xml:"Status"`
+	Prefix      string      `xml:"Prefix"`
+	Destination Destination `xml:"Destination"`
+}
+
+// Validate - validates replication rule.
+func (r Rule) Validate() error {
+	if len(r.ID) > 255 {
+		return errors.New("replication rule ID must not be longer than 255 characters")
+	}
+	if r.Status != Enabled && r.Status != Disabled {
+		return fmt.Errorf("replication rule status must be '%v' or '%v'", Enabled, Disabled)
+	}
+	if _, err := r.Destination.ARN(); err != nil {
+		return err
+	}
+	if r.Destination.StorageClass != "" && !storageClasses[r.Destination.StorageClass] {
+		return fmt.Errorf("unsupported storage class '%v'", r.Destination.StorageClass)
+	}
+	return nil
+}
+
+// Config - bucket replication configuration.
+type Config struct {
+	XMLName xml.Name `xml:"ReplicationConfiguration"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	Role    string   `xml:"Role,omitempty"`
+	Rules   []Rule   `xml:"Rule"`
+}
+
+// Validate - validates replication configuration.
+func (c Config) Validate() error {
+	if len(c.Rules) == 0 || len(c.Rules) > maxRules {
+		return ErrInvalidRules
+	}
+
+	ids := make(map[string]bool)
+	for i, rule := range c.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return fmt.Errorf("replication rule ID '%v' is not unique", rule.ID)
+			}
+			ids[rule.ID] = true
+		}
+
+		// Every object is replicated by at most one rule.
+		for _, other := range c.Rules[:i] {
+			if strings.HasPrefix(rule.Prefix, other.Prefix) || strings.HasPrefix(other.Prefix, rule.Prefix) {
+				return fmt.Errorf("replication rule prefixes '%v' and '%v' overlap", other.Prefix, rule.Prefix)
+			}
+		}
+	}
+	return nil
+}
+
+// Match - returns the enabled rule replicating objectName.
+func (c Config) Match(objectName string) (Rule, bool) {
+	for _, rule := range c.Rules {
+		if rule.Status == Enabled && strings.HasPrefix(objectName, rule.Prefix) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Target - remote S3 compatible endpoint objects are replicated to, with
+// the credentials to write to its buckets.
+type Target struct {
+	Enable    bool   `json:"enable"`
+	Endpoint  string `json:"endpoint"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Secure    bool   `json:"secure"`
+}
+
+// Validate - validates replication target.
+func (t Target) Validate() error {
+	if !t.Enable {
+		return nil
+	}
+	if t.Endpoint == "" || strings.Contains(t.Endpoint, "/") {
+		return fmt.Errorf("replication target endpoint '%v' must be in the format of host:port", t.Endpoint)
+	}
+	if t.AccessKey == "" || t.SecretKey == "" {
+		return errors.New("replication target credentials must not be empty")
+	}
+	return nil
+}
+
+// Targets - replication targets by target ID.
+type Targets map[string]Target
+
+// Validate - validates all replication targets.
+func (targets Targets) Validate() error {
+	for id, target := range targets {
+		if err := target.Validate(); err != nil {
+			return fmt.Errorf("replication target %v: %v", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replication
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data          string
+		expectedRules int
+		expectErr     bool
+	}{
+		{`<ReplicationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Role>arn:aws:iam::1:role/replication</Role><Rule><ID>docs</ID><Status>Enabled</Status><Prefix>docs/</Prefix><Destination><Bucket>arn:minio:replication::1:backup</Bucket><StorageClass>STANDARD_IA</StorageClass></Destination></Rule></ReplicationConfiguration>`, 1, false},
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix>a/</Prefix><Destination><Bucket>arn:minio:replication:us-east-1:1:backup</Bucket></Destination></Rule><Rule><Status>Disabled</Status><Prefix>b/</Prefix><Destination><Bucket>arn:minio:replication::2:backup</Bucket></Destination></Rule></ReplicationConfiguration>`, 2, false},
+		// Invalid status.
+		{`<ReplicationConfiguration><Rule><Status>On</Status><Prefix></Prefix><Destination><Bucket>arn:minio:replication::1:backup</Bucket></Destination></Rule></ReplicationConfiguration>`, 0, true},
+		// Invalid destination ARN.
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix></Prefix><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule></ReplicationConfiguration>`, 0, true},
+		// Unsupported storage class.
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix></Prefix><Destination><Bucket>arn:minio:replication::1:backup</Bucket><StorageClass>COLD</StorageClass></Destination></Rule></ReplicationConfiguration>`, 0, true},
+		// Duplicate rule ID.
+		{`<ReplicationConfiguration><Rule><ID>1</ID><Status>Enabled</Status><Prefix>a/</Prefix><Destination><Bucket>arn:minio:replication::1:backup</Bucket></Destination></Rule><Rule><ID>1</ID><Status>Enabled</Status><Prefix>b/</Prefix><Destination><Bucket>arn:minio:replication::1:backup</Bucket></Destination></Rule></ReplicationConfiguration>`, 0, true},
+		// Overlapping prefixes.
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix>a/</Prefix><Destination><Bucket>arn:minio:replication::1:backup</Bucket></Destination></Rule><Rule><Status>Enabled</Status><Prefix>a/b/</Prefix><Destination><Bucket>arn:minio:replication::1:backup</Bucket></Destination></Rule></ReplicationConfiguration>`, 0, true},
+		// Missing rule.
+		{`<ReplicationConfiguration></ReplicationConfiguration>`, 0, true},
+		// Invalid XML.
+		{`<ReplicationConfiguration>`, 0, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := ParseConfig(strings.NewReader(testCase.data))
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr && len(result.Rules) != testCase.expectedRules {
+			t.Fatalf("case %v: rules: expected: %v, got: %v\n", i+1, testCase.expectedRules, len(result.Rules))
+		}
+	}
+}
+
+func TestConfigMatch(t *testing.T) {
+	config := Config{
+		Rules: []Rule{
+			{ID: "docs", Status: Enabled, Prefix: "docs/", Destination: Destination{Bucket: "arn:minio:replication::1:backup"}},
+			{ID: "logs", Status: Disabled, Prefix: "logs/", Destination: Destination{Bucket: "arn:minio:replication::1:backup"}},
+		},
+	}
+
+	testCases := []struct {
+		objectName   string
+		expectedID   string
+		expectedBool bool
+	}{
+		{"docs/readme.md", "docs", true},
+		{"logs/2018.log", "", false},
+		{"photos/a.jpg", "", false},
+	}
+
+	for i, testCase := range testCases {
+		rule, ok := config.Match(testCase.objectName)
+		if ok != testCase.expectedBool || rule.ID != testCase.expectedID {
+			t.Fatalf("case %v: expected: %v %v, got: %v %v\n", i+1, testCase.expectedID, testCase.expectedBool, rule.ID, ok)
+		}
+	}
+}
+
+func TestParseARN(t *testing.T) {
+	testCases := []struct {
+		s           string
+		expectedARN ARN
+		expectErr   bool
+	}{
+		{"arn:minio:replication::1:backup", ARN{TargetID: "1", Bucket: "backup"}, false},
+		{"arn:minio:replication:us-east-1:dr:backup", ARN{Region: "us-east-1", TargetID: "dr", Bucket: "backup"}, false},
+		{"arn:minio:replication:::backup", ARN{}, true},
+		{"arn:minio:sqs::1:webhook", ARN{}, true},
+		{"arn:aws:s3:::backup", ARN{}, true},
+	}
+
+	for i, testCase := range testCases {
+		arn, err := ParseARN(testCase.s)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			if arn != testCase.expectedARN {
+				t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedARN, arn)
+			}
+			if arn.String() != testCase.s {
+				t.Fatalf("case %v: string: expected: %v, got: %v\n", i+1, testCase.s, arn.String())
+			}
+		}
+	}
+}
+
+func TestTargetsValidate(t *testing.T) {
+	testCases := []struct {
+		targets   Targets
+		expectErr bool
+	}{
+		{Targets{"1": {}}, false},
+		{Targets{"1": {Enable: true, Endpoint: "replica:9000", AccessKey: "minio", SecretKey: "minio123"}}, false},
+		{Targets{"1": {Enable: true, Endpoint: "http://replica:9000", AccessKey: "minio", SecretKey: "minio123"}}, true},
+		{Targets{"1": {Enable: true, Endpoint: "replica:9000"}}, true},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.targets.Validate()
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+// CSV file header info.
+const (
+	fileHeaderNone   = "NONE"
+	fileHeaderUse    = "USE"
+	fileHeaderIgnore = "IGNORE"
+)
+
+// CSVInput - CSV format of object data.
+type CSVInput struct {
+	FileHeaderInfo             string
+	RecordDelimiter            string
+	FieldDelimiter             string
+	QuoteCharacter             string
+	QuoteEscapeCharacter       string
+	Comments                   string
+	AllowQuotedRecordDelimiter bool
+}
+
+func (input *CSVInput) validate() error {
+	switch strings.ToUpper(input.FileHeaderInfo) {
+	case "", fileHeaderNone, fileHeaderUse, fileHeaderIgnore:
+	default:
+		return ErrInvalidFileHeaderInfo
+	}
+
+	// Only standard quoting and record delimiters are supported.
+	switch input.RecordDelimiter {
+	case "", "\n", "\r\n":
+	default:
+		return ErrInvalidRequestParameter
+	}
+	if (input.QuoteCharacter != "" && input.QuoteCharacter != `"`) ||
+		(input.QuoteEscapeCharacter != "" && input.QuoteEscapeCharacter != `"`) {
+		return ErrInvalidRequestParameter
+	}
+	if input.FieldDelimiter != "" && utf8.RuneCountInString(input.FieldDelimiter) != 1 {
+		return ErrInvalidRequestParameter
+	}
+	if input.Comments != "" && utf8.RuneCountInString(input.Comments) != 1 {
+		return ErrInvalidRequestParameter
+	}
+
+	return nil
+}
+
+// csvReader - reads records of CSV data.
+type csvReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func newCSVReader(reader io.Reader, input *CSVInput) (*csvReader, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	if input.FieldDelimiter != "" {
+		r.Comma, _ = utf8.DecodeRuneInString(input.FieldDelimiter)
+	}
+	if input.Comments != "" {
+		r.Comment, _ = utf8.DecodeRuneInString(input.Comments)
+	}
+
+	csvr := &csvReader{reader: r}
+	switch strings.ToUpper(input.FileHeaderInfo) {
+	case fileHeaderUse, fileHeaderIgnore:
+		header, err := r.Read()
+		if err != nil && err != io.EOF {
+			return nil, csvParsingError(err)
+		}
+		if strings.ToUpper(input.FileHeaderInfo) == fileHeaderUse {
+			csvr.columns = header
+		}
+	}
+
+	return csvr, nil
+}
+
+func (r *csvReader) Read() (sql.Record, error) {
+	values, err := r.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, csvParsingError(err)
+	}
+
+	return &csvRecord{columns: r.columns, values: values}, nil
+}
+
+func csvParsingError(err error) error {
+	if _, ok := err.(*csv.ParseError); ok {
+		return &selectError{"CSVParsingError", err.Error()}
+	}
+
+	return err
+}
+
+// csvRecord - record of CSV data whose fields are strings.
+type csvRecord struct {
+	columns []string
+	values  []string
+}
+
+// columnIndex - returns index of the field referred by the identifier.
+func (record *csvRecord) columnIndex(id sql.Identifier) int {
+	// Positional reference _1, _2 etc.
+	if !id.Quoted && strings.HasPrefix(id.Name, "_") {
+		if n, err := strconv.Atoi(id.Name[1:]); err == nil && n > 0 {
+			return n - 1
+		}
+	}
+
+	for i, column := range record.columns {
+		if column == id.Name {
+			return i
+		}
+	}
+	for i, column := range record.columns {
+		if id.Matches(column) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (record *csvRecord) Get(path []sql.Identifier) (*sql.Value, error) {
+	if len(path) != 1 {
+		return sql.NewNull(), nil
+	}
+
+	i := record.columnIndex(path[0])
+	if i < 0 || i >= len(record.values) {
+		return sql.NewNull(), nil
+	}
+
+	return sql.NewString(record.values[i]), nil
+}
+
+func (record *csvRecord) Fields() []sql.Field {
+	fields := make([]sql.Field, len(record.values))
+	for i, value := range record.values {
+		name := positionalName(i)
+		if i < len(record.columns) {
+			name = record.columns[i]
+		}
+		fields[i] = sql.Field{Name: name, Value: sql.NewString(value)}
+	}
+
+	return fields
+}
+
+// positionalName - returns name of nth (0-based) field without name.
+func positionalName(n int) string {
+	return "_" + strconv.Itoa(n+1)
+}
@@ -0,0 +1,58 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import "errors"
+
+// Errors of S3 Select request validation.
+var (
+	ErrInvalidExpressionType       = errors.New("expression type is not SQL")
+	ErrInvalidCompressionFormat    = errors.New("compression type must be NONE, GZIP or BZIP2")
+	ErrInvalidFileHeaderInfo       = errors.New("file header info must be NONE, USE or IGNORE")
+	ErrInvalidJSONType             = errors.New("JSON type must be DOCUMENT or LINES")
+	ErrInvalidQuoteFields          = errors.New("quote fields must be ALWAYS or ASNEEDED")
+	ErrInvalidRequestParameter     = errors.New("invalid select request parameter")
+	ErrMissingRequiredParameter    = errors.New("missing required select request parameter")
+	ErrObjectSerializationConflict = errors.New("serialization must be only one of CSV or JSON")
+)
+
+// selectError - error of evaluation sent in event stream with S3 Select
+// error code.
+type selectError struct {
+	code    string
+	message string
+}
+
+func (err *selectError) Error() string {
+	return err.message
+}
+
+// ErrorCode - returns S3 Select error code of the error.
+func (err *selectError) ErrorCode() string {
+	return err.code
+}
+
+// errorCode - returns S3 Select error code of err.
+func errorCode(err error) string {
+	if e, ok := err.(interface {
+		ErrorCode() string
+	}); ok {
+		return e.ErrorCode()
+	}
+
+	return "InternalError"
+}
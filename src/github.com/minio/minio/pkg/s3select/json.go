@@ -0,0 +1,164 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio/pkg/s3select/sql"
+	"github.com/tidwall/gjson"
+)
+
+// JSON types.
+const (
+	jsonTypeDocument = "DOCUMENT"
+	jsonTypeLines    = "LINES"
+)
+
+// JSONInput - JSON format of object data.
+type JSONInput struct {
+	Type string
+}
+
+func (input *JSONInput) validate() error {
+	switch strings.ToUpper(input.Type) {
+	case "", jsonTypeDocument, jsonTypeLines:
+		return nil
+	}
+
+	return ErrInvalidJSONType
+}
+
+// jsonReader - reads records of a JSON document or of line-delimited
+// JSON. Elements of top-level arrays of a document are read as records.
+type jsonReader struct {
+	decoder  *json.Decoder
+	document bool
+	elements []json.RawMessage
+}
+
+func newJSONReader(reader io.Reader, input *JSONInput) *jsonReader {
+	return &jsonReader{
+		decoder:  json.NewDecoder(reader),
+		document: strings.ToUpper(input.Type) != jsonTypeLines,
+	}
+}
+
+func (r *jsonReader) Read() (sql.Record, error) {
+	for len(r.elements) == 0 {
+		var raw json.RawMessage
+		if err := r.decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil, err
+			}
+			return nil, &selectError{"JSONParsingError", err.Error()}
+		}
+
+		if !r.document || !bytes.HasPrefix(raw, []byte("[")) {
+			return newJSONRecord(raw), nil
+		}
+
+		if err := json.Unmarshal(raw, &r.elements); err != nil {
+			return nil, &selectError{"JSONParsingError", err.Error()}
+		}
+	}
+
+	raw := r.elements[0]
+	r.elements = r.elements[1:]
+	return newJSONRecord(raw), nil
+}
+
+// jsonRecord - record of a JSON value.
+type jsonRecord struct {
+	result gjson.Result
+}
+
+func newJSONRecord(raw []byte) *jsonRecord {
+	return &jsonRecord{gjson.ParseBytes(raw)}
+}
+
+// isObject - returns whether the JSON value is an object.
+func isObject(result gjson.Result) bool {
+	return result.Type == gjson.JSON && strings.HasPrefix(result.Raw, "{")
+}
+
+// toValue - returns SQL value of the JSON value.
+func toValue(result gjson.Result) *sql.Value {
+	switch result.Type {
+	case gjson.True:
+		return sql.NewBool(true)
+	case gjson.False:
+		return sql.NewBool(false)
+	case gjson.Number:
+		if i, err := strconv.ParseInt(result.Raw, 10, 64); err == nil {
+			return sql.NewInt(i)
+		}
+		return sql.NewFloat(result.Float())
+	case gjson.String:
+		return sql.NewString(result.String())
+	case gjson.JSON:
+		return sql.NewJSON(result.Raw)
+	}
+
+	return sql.NewNull()
+}
+
+func (record *jsonRecord) Get(path []sql.Identifier) (*sql.Value, error) {
+	result := record.result
+	for _, id := range path {
+		if !isObject(result) {
+			return sql.NewNull(), nil
+		}
+
+		var exact, matched gjson.Result
+		var found bool
+		result.ForEach(func(key, value gjson.Result) bool {
+			if key.String() == id.Name {
+				exact, found = value, true
+				return false
+			}
+			if !matched.Exists() && id.Matches(key.String()) {
+				matched = value
+			}
+			return true
+		})
+		if !found {
+			exact = matched
+		}
+		result = exact
+	}
+
+	return toValue(result), nil
+}
+
+func (record *jsonRecord) Fields() []sql.Field {
+	if !isObject(record.result) {
+		return []sql.Field{{Name: positionalName(0), Value: toValue(record.result)}}
+	}
+
+	var fields []sql.Field
+	record.result.ForEach(func(key, value gjson.Result) bool {
+		fields = append(fields, sql.Field{Name: key.String(), Value: toValue(value)})
+		return true
+	})
+
+	return fields
+}
@@ -0,0 +1,239 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event stream messages are framed as
+//
+//   total length (4 bytes) | headers length (4 bytes) | prelude CRC (4 bytes) |
+//   headers | payload | message CRC (4 bytes)
+//
+// where each header is
+//
+//   name length (1 byte) | name | value type (1 byte) | value length (2 bytes) | value
+//
+// Refer https://docs.aws.amazon.com/AmazonS3/latest/API/RESTSelectObjectAppendix.html
+
+// Type of string header values.
+const stringHeaderType = 7
+
+// Interval of Progress and keep-alive Cont messages.
+const messageInterval = time.Second
+
+type header struct {
+	name  string
+	value string
+}
+
+// encodeMessage - returns event stream message of headers and payload.
+func encodeMessage(headers []header, payload []byte) []byte {
+	var headerBuf bytes.Buffer
+	for _, h := range headers {
+		headerBuf.WriteByte(byte(len(h.name)))
+		headerBuf.WriteString(h.name)
+		headerBuf.WriteByte(stringHeaderType)
+		binary.Write(&headerBuf, binary.BigEndian, uint16(len(h.value)))
+		headerBuf.WriteString(h.value)
+	}
+
+	totalLength := 4 + 4 + 4 + headerBuf.Len() + len(payload) + 4
+	buf := bytes.NewBuffer(make([]byte, 0, totalLength))
+	binary.Write(buf, binary.BigEndian, uint32(totalLength))
+	binary.Write(buf, binary.BigEndian, uint32(headerBuf.Len()))
+	binary.Write(buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes()))
+	buf.Write(headerBuf.Bytes())
+	buf.Write(payload)
+	binary.Write(buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes()))
+
+	return buf.Bytes()
+}
+
+func newRecordsMessage(payload []byte) []byte {
+	return encodeMessage([]header{
+		{":event-type", "Records"},
+		{":content-type", "application/octet-stream"},
+		{":message-type", "event"},
+	}, payload)
+}
+
+func newContinuationMessage() []byte {
+	return encodeMessage([]header{
+		{":event-type", "Cont"},
+		{":message-type", "event"},
+	}, nil)
+}
+
+func newStatsMessage(eventType string, payload []byte) []byte {
+	return encodeMessage([]header{
+		{":event-type", eventType},
+		{":content-type", "text/xml"},
+		{":message-type", "event"},
+	}, payload)
+}
+
+func newEndMessage() []byte {
+	return encodeMessage([]header{
+		{":event-type", "End"},
+		{":message-type", "event"},
+	}, nil)
+}
+
+func newErrorMessage(errorCode, errorMessage string) []byte {
+	return encodeMessage([]header{
+		{":error-code", errorCode},
+		{":error-message", errorMessage},
+		{":message-type", "error"},
+	}, nil)
+}
+
+// stats - number of bytes scanned, processed and returned by a request.
+type stats struct {
+	bytesScanned   int64
+	bytesProcessed int64
+	bytesReturned  int64
+}
+
+// statsXML - payload of Stats and Progress messages.
+type statsXML struct {
+	XMLName        xml.Name
+	BytesScanned   int64
+	BytesProcessed int64
+	BytesReturned  int64
+}
+
+// payload - returns stats as XML payload of Stats or Progress message.
+func (s *stats) payload(eventType string) []byte {
+	data, _ := xml.Marshal(statsXML{
+		XMLName:        xml.Name{Local: eventType},
+		BytesScanned:   atomic.LoadInt64(&s.bytesScanned),
+		BytesProcessed: atomic.LoadInt64(&s.bytesProcessed),
+		BytesReturned:  atomic.LoadInt64(&s.bytesReturned),
+	})
+
+	return append([]byte(xml.Header), data...)
+}
+
+// countingReader - counts bytes read.
+type countingReader struct {
+	reader io.Reader
+	count  *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
+
+// messageWriter - writes event stream messages to client. Progress
+// messages, if enabled, or keep-alive Cont messages are written
+// periodically until the writer is closed.
+type messageWriter struct {
+	writer io.Writer
+	stats  *stats
+
+	mutex   sync.Mutex
+	err     error
+	written bool // whether a message is written since last tick
+
+	doneCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newMessageWriter(writer io.Writer, s *stats, progressEnabled bool) *messageWriter {
+	w := &messageWriter{
+		writer: writer,
+		stats:  s,
+		doneCh: make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(messageInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.doneCh:
+				return
+			case <-ticker.C:
+				w.mutex.Lock()
+				written := w.written
+				w.written = false
+				w.mutex.Unlock()
+
+				switch {
+				case progressEnabled:
+					w.write(newStatsMessage("Progress", s.payload("Progress")))
+				case !written:
+					w.write(newContinuationMessage())
+				}
+			}
+		}
+	}()
+
+	return w
+}
+
+// write - writes the message and flushes it to client. Once a write
+// fails, subsequent writes are ignored and return the same error.
+func (w *messageWriter) write(message []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	if _, w.err = w.writer.Write(message); w.err != nil {
+		return w.err
+	}
+	if flusher, ok := w.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	w.written = true
+
+	return nil
+}
+
+func (w *messageWriter) writeRecords(payload []byte) error {
+	if err := w.write(newRecordsMessage(payload)); err != nil {
+		return err
+	}
+
+	atomic.AddInt64(&w.stats.bytesReturned, int64(len(payload)))
+	return nil
+}
+
+// close - stops periodic messages. Messages written afterwards are
+// written directly.
+func (w *messageWriter) close() {
+	close(w.doneCh)
+	w.wg.Wait()
+}
@@ -0,0 +1,100 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+// testMessage - decoded event stream message.
+type testMessage struct {
+	headers map[string]string
+	payload []byte
+}
+
+// decodeMessages - decodes event stream messages and verifies their
+// lengths and checksums.
+func decodeMessages(data []byte) ([]testMessage, error) {
+	var messages []testMessage
+	for len(data) > 0 {
+		if len(data) < 16 {
+			return nil, fmt.Errorf("short message of %d bytes", len(data))
+		}
+		totalLength := binary.BigEndian.Uint32(data[0:4])
+		headersLength := binary.BigEndian.Uint32(data[4:8])
+		if int(totalLength) > len(data) {
+			return nil, fmt.Errorf("message length %d exceeds data", totalLength)
+		}
+		if binary.BigEndian.Uint32(data[8:12]) != crc32.ChecksumIEEE(data[0:8]) {
+			return nil, fmt.Errorf("prelude CRC mismatch")
+		}
+		if binary.BigEndian.Uint32(data[totalLength-4:totalLength]) != crc32.ChecksumIEEE(data[0:totalLength-4]) {
+			return nil, fmt.Errorf("message CRC mismatch")
+		}
+
+		message := testMessage{headers: map[string]string{}}
+		headers := bytes.NewReader(data[12 : 12+headersLength])
+		for headers.Len() > 0 {
+			nameLength, _ := headers.ReadByte()
+			name := make([]byte, nameLength)
+			io.ReadFull(headers, name)
+			if valueType, _ := headers.ReadByte(); valueType != stringHeaderType {
+				return nil, fmt.Errorf("unexpected header value type %d", valueType)
+			}
+			var valueLength uint16
+			binary.Read(headers, binary.BigEndian, &valueLength)
+			value := make([]byte, valueLength)
+			io.ReadFull(headers, value)
+			message.headers[string(name)] = string(value)
+		}
+		message.payload = data[12+headersLength : totalLength-4]
+
+		messages = append(messages, message)
+		data = data[totalLength:]
+	}
+
+	return messages, nil
+}
+
+func TestEncodeMessage(t *testing.T) {
+	data := append(newRecordsMessage([]byte("a,b\n")), newEndMessage()...)
+	data = append(data, newErrorMessage("InternalError", "failed")...)
+
+	messages, err := decodeMessages(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+
+	if messages[0].headers[":event-type"] != "Records" || string(messages[0].payload) != "a,b\n" {
+		t.Fatalf("unexpected records message %v", messages[0])
+	}
+	if messages[1].headers[":event-type"] != "End" || len(messages[1].payload) != 0 {
+		t.Fatalf("unexpected end message %v", messages[1])
+	}
+	if messages[2].headers[":message-type"] != "error" || messages[2].headers[":error-code"] != "InternalError" ||
+		messages[2].headers[":error-message"] != "failed" {
+		t.Fatalf("unexpected error message %v", messages[2])
+	}
+}
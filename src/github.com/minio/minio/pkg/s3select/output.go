@@ -0,0 +1,145 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+// CSV quote fields.
+const (
+	quoteFieldsAlways   = "ALWAYS"
+	quoteFieldsAsNeeded = "ASNEEDED"
+)
+
+// CSVOutput - CSV format of output records.
+type CSVOutput struct {
+	QuoteFields          string
+	RecordDelimiter      string
+	FieldDelimiter       string
+	QuoteCharacter       string
+	QuoteEscapeCharacter string
+}
+
+func (output *CSVOutput) validate() error {
+	switch strings.ToUpper(output.QuoteFields) {
+	case "", quoteFieldsAlways, quoteFieldsAsNeeded:
+		return nil
+	}
+
+	return ErrInvalidQuoteFields
+}
+
+// JSONOutput - JSON format of output records.
+type JSONOutput struct {
+	RecordDelimiter string
+}
+
+// recordWriter - formats output records.
+type recordWriter interface {
+	write(buf *bytes.Buffer, fields []sql.Field)
+}
+
+// csvWriter - formats records as CSV.
+type csvWriter struct {
+	quoteAlways     bool
+	recordDelimiter string
+	fieldDelimiter  string
+	quote           string
+	quoteEscape     string
+}
+
+func newCSVWriter(output *CSVOutput) *csvWriter {
+	w := &csvWriter{
+		quoteAlways:     strings.ToUpper(output.QuoteFields) == quoteFieldsAlways,
+		recordDelimiter: output.RecordDelimiter,
+		fieldDelimiter:  output.FieldDelimiter,
+		quote:           output.QuoteCharacter,
+		quoteEscape:     output.QuoteEscapeCharacter,
+	}
+	if w.recordDelimiter == "" {
+		w.recordDelimiter = "\n"
+	}
+	if w.fieldDelimiter == "" {
+		w.fieldDelimiter = ","
+	}
+	if w.quote == "" {
+		w.quote = `"`
+	}
+	if w.quoteEscape == "" {
+		w.quoteEscape = w.quote
+	}
+
+	return w
+}
+
+func (w *csvWriter) write(buf *bytes.Buffer, fields []sql.Field) {
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteString(w.fieldDelimiter)
+		}
+
+		value := field.Value.String()
+		if !w.quoteAlways && !strings.Contains(value, w.fieldDelimiter) &&
+			!strings.Contains(value, w.quote) && !strings.ContainsAny(value, "\r\n") &&
+			!strings.Contains(value, w.recordDelimiter) {
+			buf.WriteString(value)
+			continue
+		}
+
+		buf.WriteString(w.quote)
+		buf.WriteString(strings.Replace(value, w.quote, w.quoteEscape+w.quote, -1))
+		buf.WriteString(w.quote)
+	}
+
+	buf.WriteString(w.recordDelimiter)
+}
+
+// jsonWriter - formats records as JSON objects.
+type jsonWriter struct {
+	recordDelimiter string
+}
+
+func newJSONWriter(output *JSONOutput) *jsonWriter {
+	w := &jsonWriter{recordDelimiter: output.RecordDelimiter}
+	if w.recordDelimiter == "" {
+		w.recordDelimiter = "\n"
+	}
+
+	return w
+}
+
+func (w *jsonWriter) write(buf *bytes.Buffer, fields []sql.Field) {
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(field.Name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, _ := field.Value.MarshalJSON()
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	buf.WriteString(w.recordDelimiter)
+}
@@ -0,0 +1,261 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package s3select implements SelectObjectContent API which filters
+// CSV and JSON object data by a SQL expression.
+package s3select
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+// Compression types of object data.
+const (
+	compressionNone  = "NONE"
+	compressionGZIP  = "GZIP"
+	compressionBZIP2 = "BZIP2"
+)
+
+const (
+	// Maximum size of SelectObjectContentRequest XML.
+	maxRequestSize = 256 * 1024
+
+	// Records are sent in messages of about this size.
+	maxRecordsPayloadSize = 128 * 1024
+)
+
+// InputSerialization - format of object data.
+type InputSerialization struct {
+	CompressionType string
+	CSV             *CSVInput  `xml:"CSV"`
+	JSON            *JSONInput `xml:"JSON"`
+}
+
+func (input *InputSerialization) validate() error {
+	switch strings.ToUpper(input.CompressionType) {
+	case "", compressionNone, compressionGZIP, compressionBZIP2:
+	default:
+		return ErrInvalidCompressionFormat
+	}
+
+	switch {
+	case input.CSV != nil && input.JSON != nil:
+		return ErrObjectSerializationConflict
+	case input.CSV != nil:
+		return input.CSV.validate()
+	case input.JSON != nil:
+		return input.JSON.validate()
+	}
+
+	return ErrMissingRequiredParameter
+}
+
+// OutputSerialization - format of output records.
+type OutputSerialization struct {
+	CSV  *CSVOutput  `xml:"CSV"`
+	JSON *JSONOutput `xml:"JSON"`
+}
+
+func (output *OutputSerialization) validate() error {
+	switch {
+	case output.CSV != nil && output.JSON != nil:
+		return ErrObjectSerializationConflict
+	case output.CSV != nil:
+		return output.CSV.validate()
+	case output.JSON != nil:
+		return nil
+	}
+
+	return ErrMissingRequiredParameter
+}
+
+// RequestProgress - whether Progress messages are sent.
+type RequestProgress struct {
+	Enabled bool
+}
+
+// SelectObjectContentRequest - request body of SelectObjectContent API.
+type SelectObjectContentRequest struct {
+	XMLName             xml.Name `xml:"SelectObjectContentRequest"`
+	Expression          string
+	ExpressionType      string
+	InputSerialization  InputSerialization
+	OutputSerialization OutputSerialization
+	RequestProgress     RequestProgress
+}
+
+// S3Select - parsed and validated SelectObjectContent request.
+type S3Select struct {
+	request   SelectObjectContentRequest
+	statement *sql.Select
+}
+
+// NewS3Select - parses SelectObjectContentRequest XML from reader.
+func NewS3Select(reader io.Reader) (*S3Select, error) {
+	var request SelectObjectContentRequest
+	if err := xml.NewDecoder(io.LimitReader(reader, maxRequestSize)).Decode(&request); err != nil {
+		return nil, err
+	}
+
+	if request.Expression == "" {
+		return nil, ErrMissingRequiredParameter
+	}
+	if !strings.EqualFold(request.ExpressionType, "SQL") {
+		return nil, ErrInvalidExpressionType
+	}
+	if err := request.InputSerialization.validate(); err != nil {
+		return nil, err
+	}
+	if err := request.OutputSerialization.validate(); err != nil {
+		return nil, err
+	}
+
+	statement, err := sql.Parse(request.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Select{request: request, statement: statement}, nil
+}
+
+// recordReader - reads input records.
+type recordReader interface {
+	// Read - returns next record, io.EOF at end of data.
+	Read() (sql.Record, error)
+}
+
+// openReader - returns reader of input records of object data.
+func (s3Select *S3Select) openReader(reader io.Reader, s *stats) (recordReader, error) {
+	switch strings.ToUpper(s3Select.request.InputSerialization.CompressionType) {
+	case compressionGZIP:
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, &selectError{"InvalidCompressionFormat", "object data is not in GZIP format"}
+		}
+		reader = gzipReader
+	case compressionBZIP2:
+		reader = bzip2.NewReader(reader)
+	}
+	// Bytes processed are counted after decompression.
+	reader = &countingReader{reader, &s.bytesProcessed}
+
+	if input := s3Select.request.InputSerialization.JSON; input != nil {
+		return newJSONReader(reader, input), nil
+	}
+
+	return newCSVReader(reader, s3Select.request.InputSerialization.CSV)
+}
+
+// newRecordWriter - returns writer of output records.
+func (s3Select *S3Select) newRecordWriter() recordWriter {
+	if output := s3Select.request.OutputSerialization.JSON; output != nil {
+		return newJSONWriter(output)
+	}
+
+	return newCSVWriter(s3Select.request.OutputSerialization.CSV)
+}
+
+// Evaluate - runs the query on object data read from reader and writes
+// output records, Stats and End messages or an error message as event
+// stream to writer.
+func (s3Select *S3Select) Evaluate(reader io.Reader, writer io.Writer) {
+	var s stats
+	reader = &countingReader{reader, &s.bytesScanned}
+
+	w := newMessageWriter(writer, &s, s3Select.request.RequestProgress.Enabled)
+	err := s3Select.evaluate(reader, w, &s)
+	w.close()
+	if err != nil {
+		w.write(newErrorMessage(errorCode(err), err.Error()))
+		return
+	}
+
+	if err = w.write(newStatsMessage("Stats", s.payload("Stats"))); err != nil {
+		return
+	}
+	w.write(newEndMessage())
+}
+
+func (s3Select *S3Select) evaluate(reader io.Reader, w *messageWriter, s *stats) error {
+	rr, err := s3Select.openReader(reader, s)
+	if err != nil {
+		return err
+	}
+
+	statement := s3Select.statement
+	rw := s3Select.newRecordWriter()
+	var buf bytes.Buffer
+	var count int64
+	for statement.IsAggregate() || statement.Limit() < 0 || count < statement.Limit() {
+		record, err := rr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		matched, err := statement.Filter(record)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		if statement.IsAggregate() {
+			if err = statement.Aggregate(record); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fields, err := statement.Project(record)
+		if err != nil {
+			return err
+		}
+		rw.write(&buf, fields)
+		count++
+
+		if buf.Len() >= maxRecordsPayloadSize {
+			if err = w.writeRecords(buf.Bytes()); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+
+	if statement.IsAggregate() {
+		fields, err := statement.AggregateResult()
+		if err != nil {
+			return err
+		}
+		rw.write(&buf, fields)
+	}
+
+	if buf.Len() > 0 {
+		return w.writeRecords(buf.Bytes())
+	}
+
+	return nil
+}
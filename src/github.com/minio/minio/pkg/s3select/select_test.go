@@ -0,0 +1,249 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3select
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const testCSV = `name,age,city
+alice,30,"Bengaluru, KA"
+bob,25,Palo Alto
+carol,35,Bengaluru
+`
+
+const testJSONLines = `{"name": "alice", "age": 30, "address": {"city": "Bengaluru"}}
+{"name": "bob", "age": 25, "address": {"city": "Palo Alto"}}
+{"name": "carol", "age": 35, "tags": ["a", "b"]}
+`
+
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func newTestRequest(expression, input, output string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Expression>` + xmlEscaper.Replace(expression) + `</Expression>
+  <ExpressionType>SQL</ExpressionType>
+  <InputSerialization>` + input + `</InputSerialization>
+  <OutputSerialization>` + output + `</OutputSerialization>
+</SelectObjectContentRequest>`
+}
+
+func gzipData(t *testing.T, data string) string {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestNewS3Select(t *testing.T) {
+	csvInput := `<CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV>`
+	csvOutput := `<CSV></CSV>`
+
+	testCases := []struct {
+		request     string
+		expectedErr error
+	}{
+		{newTestRequest("SELECT * FROM S3Object", csvInput, csvOutput), nil},
+		{newTestRequest("SELECT * FROM S3Object", `<JSON><Type>LINES</Type></JSON>`, `<JSON></JSON>`), nil},
+		{strings.Replace(newTestRequest("SELECT * FROM S3Object", csvInput, csvOutput), ">SQL<", ">XPATH<", 1), ErrInvalidExpressionType},
+		{newTestRequest("", csvInput, csvOutput), ErrMissingRequiredParameter},
+		{newTestRequest("SELECT * FROM S3Object", `<CompressionType>ZIP</CompressionType>`+csvInput, csvOutput), ErrInvalidCompressionFormat},
+		{newTestRequest("SELECT * FROM S3Object", `<CSV><FileHeaderInfo>FIRST</FileHeaderInfo></CSV>`, csvOutput), ErrInvalidFileHeaderInfo},
+		{newTestRequest("SELECT * FROM S3Object", `<CSV><QuoteCharacter>'</QuoteCharacter></CSV>`, csvOutput), ErrInvalidRequestParameter},
+		{newTestRequest("SELECT * FROM S3Object", `<JSON><Type>ARRAY</Type></JSON>`, csvOutput), ErrInvalidJSONType},
+		{newTestRequest("SELECT * FROM S3Object", csvInput+`<JSON></JSON>`, csvOutput), ErrObjectSerializationConflict},
+		{newTestRequest("SELECT * FROM S3Object", "", csvOutput), ErrMissingRequiredParameter},
+		{newTestRequest("SELECT * FROM S3Object", csvInput, `<CSV><QuoteFields>NEVER</QuoteFields></CSV>`), ErrInvalidQuoteFields},
+		{newTestRequest("SELECT * FROM S3Object", csvInput, ""), ErrMissingRequiredParameter},
+	}
+
+	for i, testCase := range testCases {
+		_, err := NewS3Select(strings.NewReader(testCase.request))
+		if err != testCase.expectedErr {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedErr, err)
+		}
+	}
+
+	// Invalid SQL.
+	if _, err := NewS3Select(strings.NewReader(newTestRequest("SELECT FROM S3Object", csvInput, csvOutput))); errorCode(err) != "ParseUnsupportedSyntax" {
+		t.Fatalf("expected parse error, got: %v", err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		expression     string
+		input          string
+		output         string
+		data           string
+		expectedResult string
+	}{
+		// CSV with header to CSV.
+		{
+			"SELECT name, city FROM S3Object WHERE CAST(age AS INT) >= 30",
+			`<CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV>`,
+			`<CSV></CSV>`,
+			testCSV,
+			"alice,\"Bengaluru, KA\"\ncarol,Bengaluru\n",
+		},
+		// CSV without header to JSON with positional columns and LIMIT.
+		{
+			"SELECT s._1, s._2 FROM S3Object s LIMIT 2",
+			`<CSV><FileHeaderInfo>IGNORE</FileHeaderInfo></CSV>`,
+			`<JSON></JSON>`,
+			testCSV,
+			"{\"_1\":\"alice\",\"_2\":\"30\"}\n{\"_1\":\"bob\",\"_2\":\"25\"}\n",
+		},
+		// Aggregates.
+		{
+			"SELECT COUNT(*), SUM(age), MAX(age) FROM S3Object WHERE city LIKE 'Bengaluru%'",
+			`<CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV>`,
+			`<CSV><QuoteFields>ALWAYS</QuoteFields><FieldDelimiter>;</FieldDelimiter></CSV>`,
+			testCSV,
+			"\"2\";\"65\";\"35\"\n",
+		},
+		// GZIP compressed CSV.
+		{
+			"SELECT name FROM S3Object WHERE city = 'Palo Alto'",
+			`<CompressionType>GZIP</CompressionType><CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV>`,
+			`<CSV></CSV>`,
+			gzipData(t, testCSV),
+			"bob\n",
+		},
+		// JSON lines with nested fields.
+		{
+			"SELECT s.name, s.address.city FROM S3Object s WHERE s.age < 35",
+			`<JSON><Type>LINES</Type></JSON>`,
+			`<JSON></JSON>`,
+			testJSONLines,
+			"{\"name\":\"alice\",\"city\":\"Bengaluru\"}\n{\"name\":\"bob\",\"city\":\"Palo Alto\"}\n",
+		},
+		// JSON document of array with SELECT *.
+		{
+			"SELECT * FROM S3Object[*] s WHERE s.tags IS NOT NULL",
+			`<JSON><Type>DOCUMENT</Type></JSON>`,
+			`<JSON></JSON>`,
+			`[` + strings.Replace(strings.TrimSpace(testJSONLines), "\n", ",", -1) + `]`,
+			"{\"name\":\"carol\",\"age\":35,\"tags\":[\"a\", \"b\"]}\n",
+		},
+		// JSON to CSV.
+		{
+			"SELECT AVG(age) FROM S3Object",
+			`<JSON><Type>LINES</Type></JSON>`,
+			`<CSV></CSV>`,
+			testJSONLines,
+			"30\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+		s3Select, err := NewS3Select(strings.NewReader(newTestRequest(testCase.expression, testCase.input, testCase.output)))
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+
+		var buf bytes.Buffer
+		s3Select.Evaluate(strings.NewReader(testCase.data), &buf)
+
+		messages, err := decodeMessages(buf.Bytes())
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+
+		var records string
+		var eventTypes []string
+		var stats struct {
+			BytesScanned   int64
+			BytesProcessed int64
+			BytesReturned  int64
+		}
+		for _, message := range messages {
+			if message.headers[":message-type"] == "error" {
+				t.Fatalf("case %v: unexpected error message: %v", i+1, message.headers[":error-message"])
+			}
+			switch eventType := message.headers[":event-type"]; eventType {
+			case "Records":
+				records += string(message.payload)
+			case "Stats":
+				if err = xml.Unmarshal(message.payload, &stats); err != nil {
+					t.Fatalf("case %v: unable to parse Stats message: %v", i+1, err)
+				}
+				eventTypes = append(eventTypes, eventType)
+			case "End":
+				eventTypes = append(eventTypes, eventType)
+			}
+		}
+
+		if records != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %q, got: %q", i+1, testCase.expectedResult, records)
+		}
+		if strings.Join(eventTypes, ",") != "Stats,End" {
+			t.Fatalf("case %v: expected Stats and End messages, got: %v", i+1, eventTypes)
+		}
+		// BytesProcessed counts decompressed bytes of compressed input.
+		compressed := strings.Contains(testCase.input, "<CompressionType>GZIP</CompressionType>")
+		if stats.BytesScanned != int64(len(testCase.data)) || stats.BytesReturned != int64(len(records)) ||
+			(!compressed && stats.BytesProcessed != stats.BytesScanned) || stats.BytesProcessed == 0 {
+			t.Fatalf("case %v: unexpected stats: %+v", i+1, stats)
+		}
+	}
+}
+
+func TestEvaluateError(t *testing.T) {
+	testCases := []struct {
+		expression   string
+		input        string
+		data         string
+		expectedCode string
+	}{
+		{"SELECT * FROM S3Object", `<CompressionType>GZIP</CompressionType><CSV></CSV>`, testCSV, "InvalidCompressionFormat"},
+		{"SELECT * FROM S3Object", `<CSV></CSV>`, "a,\"b\n", "CSVParsingError"},
+		{"SELECT * FROM S3Object", `<JSON><Type>LINES</Type></JSON>`, "{\"a\": }", "JSONParsingError"},
+		{"SELECT CAST(name AS INT) FROM S3Object", `<CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV>`, testCSV, "InvalidCast"},
+	}
+
+	for i, testCase := range testCases {
+		s3Select, err := NewS3Select(strings.NewReader(newTestRequest(testCase.expression, testCase.input, `<CSV></CSV>`)))
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+
+		var buf bytes.Buffer
+		s3Select.Evaluate(strings.NewReader(testCase.data), &buf)
+
+		messages, err := decodeMessages(buf.Bytes())
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+
+		last := messages[len(messages)-1]
+		if last.headers[":message-type"] != "error" || last.headers[":error-code"] != testCase.expectedCode {
+			t.Fatalf("case %v: expected error %v, got: %v", i+1, testCase.expectedCode, last.headers)
+		}
+	}
+}
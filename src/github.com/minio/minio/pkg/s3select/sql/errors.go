@@ -0,0 +1,47 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import "fmt"
+
+// Error - error of parsing or evaluating a SQL expression having S3
+// Select error code.
+type Error struct {
+	code    string
+	message string
+}
+
+func (err *Error) Error() string {
+	return err.message
+}
+
+// ErrorCode - returns S3 Select error code of the error.
+func (err *Error) ErrorCode() string {
+	return err.code
+}
+
+func errParse(format string, args ...interface{}) error {
+	return &Error{"ParseUnsupportedSyntax", fmt.Sprintf(format, args...)}
+}
+
+func errInvalidCast(format string, args ...interface{}) error {
+	return &Error{"InvalidCast", fmt.Sprintf(format, args...)}
+}
+
+func errInvalidArguments(format string, args ...interface{}) error {
+	return &Error{"EvaluatorInvalidArguments", fmt.Sprintf(format, args...)}
+}
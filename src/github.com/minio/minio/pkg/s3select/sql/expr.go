@@ -0,0 +1,565 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Identifier - a name in a column reference. Quoted identifiers are
+// matched case-sensitively.
+type Identifier struct {
+	Name   string
+	Quoted bool
+}
+
+// Matches - returns whether the identifier matches the name.
+func (id Identifier) Matches(name string) bool {
+	if id.Quoted {
+		return id.Name == name
+	}
+
+	return strings.EqualFold(id.Name, name)
+}
+
+// Field - named value of a record.
+type Field struct {
+	Name  string
+	Value *Value
+}
+
+// Record - input record SQL expressions are evaluated on.
+type Record interface {
+	// Get - returns value of the field at path. Missing fields are null.
+	Get(path []Identifier) (*Value, error)
+
+	// Fields - returns all fields of the record in input order.
+	Fields() []Field
+}
+
+// expr - SQL expression.
+type expr interface {
+	eval(record Record) (*Value, error)
+}
+
+type literal struct {
+	value *Value
+}
+
+func (e *literal) eval(record Record) (*Value, error) {
+	return e.value, nil
+}
+
+type columnRef struct {
+	path []Identifier
+}
+
+func (e *columnRef) eval(record Record) (*Value, error) {
+	return record.Get(e.path)
+}
+
+type notExpr struct {
+	operand expr
+}
+
+func (e *notExpr) eval(record Record) (*Value, error) {
+	v, err := e.operand.eval(record)
+	if err != nil || v.IsNull() {
+		return v, err
+	}
+
+	b, ok := v.toBool()
+	if !ok {
+		return nil, errInvalidArguments("NOT requires a boolean operand, got %q", v.String())
+	}
+
+	return NewBool(!b), nil
+}
+
+type negateExpr struct {
+	operand expr
+}
+
+func (e *negateExpr) eval(record Record) (*Value, error) {
+	v, err := e.operand.eval(record)
+	if err != nil || v.IsNull() {
+		return v, err
+	}
+
+	n, ok := v.toNumber()
+	if !ok {
+		return nil, errInvalidArguments("unary minus requires a numeric operand, got %q", v.String())
+	}
+	if n.kind == KindInt {
+		return NewInt(-n.i), nil
+	}
+
+	return NewFloat(-n.f), nil
+}
+
+// logicalExpr - AND and OR with three-valued logic.
+type logicalExpr struct {
+	and         bool
+	left, right expr
+}
+
+func (e *logicalExpr) eval(record Record) (*Value, error) {
+	left, err := e.left.eval(record)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit.
+	if !left.IsNull() {
+		if b, ok := left.toBool(); ok && b != e.and {
+			return NewBool(b), nil
+		}
+	}
+
+	right, err := e.right.eval(record)
+	if err != nil {
+		return nil, err
+	}
+
+	if left.IsNull() || right.IsNull() {
+		if !right.IsNull() {
+			if b, ok := right.toBool(); ok && b != e.and {
+				return NewBool(b), nil
+			}
+		}
+		return NewNull(), nil
+	}
+
+	x, xok := left.toBool()
+	y, yok := right.toBool()
+	if !xok || !yok {
+		return nil, errInvalidArguments("AND and OR require boolean operands")
+	}
+	if e.and {
+		return NewBool(x && y), nil
+	}
+
+	return NewBool(x || y), nil
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *comparisonExpr) eval(record Record) (*Value, error) {
+	left, err := e.left.eval(record)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(record)
+	if err != nil {
+		return nil, err
+	}
+	if left.IsNull() || right.IsNull() {
+		return NewNull(), nil
+	}
+
+	result, ok := compare(left, right)
+	if !ok {
+		// Values of different types are never equal.
+		return NewBool(e.op == "!=" || e.op == "<>"), nil
+	}
+
+	switch e.op {
+	case "=":
+		return NewBool(result == 0), nil
+	case "!=", "<>":
+		return NewBool(result != 0), nil
+	case "<":
+		return NewBool(result < 0), nil
+	case "<=":
+		return NewBool(result <= 0), nil
+	case ">":
+		return NewBool(result > 0), nil
+	}
+
+	return NewBool(result >= 0), nil
+}
+
+type arithmeticExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e *arithmeticExpr) eval(record Record) (*Value, error) {
+	left, err := e.left.eval(record)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(record)
+	if err != nil {
+		return nil, err
+	}
+	if left.IsNull() || right.IsNull() {
+		return NewNull(), nil
+	}
+
+	if e.op == "||" {
+		return NewString(left.String() + right.String()), nil
+	}
+
+	x, xok := left.toNumber()
+	y, yok := right.toNumber()
+	if !xok || !yok {
+		return nil, errInvalidArguments("operator %s requires numeric operands, got %q and %q", e.op, left.String(), right.String())
+	}
+
+	if x.kind == KindInt && y.kind == KindInt {
+		switch e.op {
+		case "+":
+			return NewInt(x.i + y.i), nil
+		case "-":
+			return NewInt(x.i - y.i), nil
+		case "*":
+			return NewInt(x.i * y.i), nil
+		}
+		if y.i == 0 {
+			return nil, errInvalidArguments("division by zero")
+		}
+		if e.op == "/" {
+			return NewInt(x.i / y.i), nil
+		}
+		return NewInt(x.i % y.i), nil
+	}
+
+	switch e.op {
+	case "+":
+		return NewFloat(x.float() + y.float()), nil
+	case "-":
+		return NewFloat(x.float() - y.float()), nil
+	case "*":
+		return NewFloat(x.float() * y.float()), nil
+	}
+	if y.float() == 0 {
+		return nil, errInvalidArguments("division by zero")
+	}
+	if e.op == "/" {
+		return NewFloat(x.float() / y.float()), nil
+	}
+
+	return NewFloat(math.Mod(x.float(), y.float())), nil
+}
+
+type isNullExpr struct {
+	not     bool
+	operand expr
+}
+
+func (e *isNullExpr) eval(record Record) (*Value, error) {
+	v, err := e.operand.eval(record)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBool(v.IsNull() != e.not), nil
+}
+
+type betweenExpr struct {
+	not                bool
+	operand, low, high expr
+}
+
+func (e *betweenExpr) eval(record Record) (*Value, error) {
+	lower := &comparisonExpr{">=", e.operand, e.low}
+	upper := &comparisonExpr{"<=", e.operand, e.high}
+	v, err := (&logicalExpr{true, lower, upper}).eval(record)
+	if err != nil || v.IsNull() || !e.not {
+		return v, err
+	}
+
+	return NewBool(!v.b), nil
+}
+
+type inExpr struct {
+	not     bool
+	operand expr
+	list    []expr
+}
+
+func (e *inExpr) eval(record Record) (*Value, error) {
+	v, err := e.operand.eval(record)
+	if err != nil || v.IsNull() {
+		return v, err
+	}
+
+	hasNull := false
+	for _, item := range e.list {
+		iv, err := item.eval(record)
+		if err != nil {
+			return nil, err
+		}
+		if iv.IsNull() {
+			hasNull = true
+			continue
+		}
+		if result, ok := compare(v, iv); ok && result == 0 {
+			return NewBool(!e.not), nil
+		}
+	}
+
+	if hasNull {
+		return NewNull(), nil
+	}
+
+	return NewBool(e.not), nil
+}
+
+type likeExpr struct {
+	not                      bool
+	operand, pattern, escape expr
+}
+
+func (e *likeExpr) eval(record Record) (*Value, error) {
+	v, err := e.operand.eval(record)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := e.pattern.eval(record)
+	if err != nil {
+		return nil, err
+	}
+	if v.IsNull() || pattern.IsNull() {
+		return NewNull(), nil
+	}
+
+	escape := rune(-1)
+	if e.escape != nil {
+		ev, err := e.escape.eval(record)
+		if err != nil {
+			return nil, err
+		}
+		runes := []rune(ev.String())
+		if len(runes) != 1 {
+			return nil, errInvalidArguments("LIKE escape must be a single character, got %q", ev.String())
+		}
+		escape = runes[0]
+	}
+
+	matched, err := likeMatch([]rune(v.String()), []rune(pattern.String()), escape)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBool(matched != e.not), nil
+}
+
+// likeMatch - matches text against LIKE pattern where '%' matches any
+// sequence and '_' matches any single character.
+func likeMatch(text, pattern []rune, escape rune) (bool, error) {
+	for p := 0; p < len(pattern); p++ {
+		if pattern[p] == escape {
+			if p+1 >= len(pattern) {
+				return false, errInvalidArguments("LIKE pattern ends with escape character")
+			}
+			p++
+		}
+	}
+
+	// Backtracking positions of the last '%'.
+	starPattern, starText := -1, -1
+	t, p := 0, 0
+	for t < len(text) {
+		if p < len(pattern) {
+			c, literal := pattern[p], false
+			width := 1
+			if c == escape {
+				c, literal, width = pattern[p+1], true, 2
+			}
+
+			switch {
+			case !literal && c == '%':
+				starPattern, starText = p+1, t
+				p++
+				continue
+			case (!literal && c == '_') || c == text[t]:
+				t++
+				p += width
+				continue
+			}
+		}
+
+		if starPattern < 0 {
+			return false, nil
+		}
+		starText++
+		t, p = starText, starPattern
+	}
+
+	for ; p < len(pattern); p++ {
+		if pattern[p] != '%' || pattern[p] == escape {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+type castExpr struct {
+	operand expr
+	kind    Kind
+}
+
+// castTypes - SQL data types of CAST.
+var castTypes = map[string]Kind{
+	"BOOL":    KindBool,
+	"BOOLEAN": KindBool,
+	"INT":     KindInt,
+	"INTEGER": KindInt,
+	"FLOAT":   KindFloat,
+	"DECIMAL": KindFloat,
+	"NUMERIC": KindFloat,
+	"STRING":  KindString,
+	"VARCHAR": KindString,
+}
+
+func (e *castExpr) eval(record Record) (*Value, error) {
+	v, err := e.operand.eval(record)
+	if err != nil || v.IsNull() {
+		return v, err
+	}
+
+	switch e.kind {
+	case KindBool:
+		if b, ok := v.toBool(); ok {
+			return NewBool(b), nil
+		}
+		if n, ok := v.toNumber(); ok {
+			return NewBool(n.float() != 0), nil
+		}
+	case KindInt:
+		if v.kind == KindBool {
+			if v.b {
+				return NewInt(1), nil
+			}
+			return NewInt(0), nil
+		}
+		if n, ok := v.toNumber(); ok {
+			if n.kind == KindInt {
+				return n, nil
+			}
+			if n.f >= math.MinInt64 && n.f <= math.MaxInt64 {
+				return NewInt(int64(n.f)), nil
+			}
+		}
+	case KindFloat:
+		if n, ok := v.toNumber(); ok {
+			return NewFloat(n.float()), nil
+		}
+	case KindString:
+		return NewString(v.String()), nil
+	}
+
+	return nil, errInvalidCast("cannot cast %q to %s", v.String(), kindNames[e.kind])
+}
+
+var kindNames = map[Kind]string{
+	KindBool:   "BOOL",
+	KindInt:    "INT",
+	KindFloat:  "FLOAT",
+	KindString: "STRING",
+}
+
+// aggregateExpr - aggregate function accumulating values of all records.
+type aggregateExpr struct {
+	name    string
+	operand expr // nil for COUNT(*)
+
+	count int64
+	sum   *Value
+	value *Value // MIN and MAX
+}
+
+func (e *aggregateExpr) accumulate(record Record) error {
+	if e.operand == nil {
+		e.count++
+		return nil
+	}
+
+	v, err := e.operand.eval(record)
+	if err != nil || v.IsNull() {
+		return err
+	}
+	e.count++
+
+	switch e.name {
+	case "SUM", "AVG":
+		n, ok := v.toNumber()
+		if !ok {
+			return errInvalidArguments("%s requires numeric values, got %q", e.name, v.String())
+		}
+		switch {
+		case e.sum == nil:
+			e.sum = n
+		case e.sum.kind == KindInt && n.kind == KindInt:
+			e.sum = NewInt(e.sum.i + n.i)
+		default:
+			e.sum = NewFloat(e.sum.float() + n.float())
+		}
+	case "MIN", "MAX":
+		// Numeric text is compared as number.
+		if n, ok := v.toNumber(); ok {
+			v = n
+		}
+		if e.value == nil {
+			e.value = v
+			break
+		}
+		result, ok := compare(v, e.value)
+		if !ok {
+			return errInvalidArguments("%s cannot compare %q and %q", e.name, v.String(), e.value.String())
+		}
+		if (e.name == "MIN" && result < 0) || (e.name == "MAX" && result > 0) {
+			e.value = v
+		}
+	}
+
+	return nil
+}
+
+func (e *aggregateExpr) eval(record Record) (*Value, error) {
+	switch e.name {
+	case "COUNT":
+		return NewInt(e.count), nil
+	case "SUM":
+		if e.sum != nil {
+			return e.sum, nil
+		}
+	case "AVG":
+		if e.sum != nil {
+			return NewFloat(e.sum.float() / float64(e.count)), nil
+		}
+	default:
+		if e.value != nil {
+			return e.value, nil
+		}
+	}
+
+	return NewNull(), nil
+}
+
+// positionalName - returns name of nth (0-based) column without name.
+func positionalName(n int) string {
+	return "_" + strconv.Itoa(n+1)
+}
@@ -0,0 +1,80 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import "testing"
+
+func TestLikeMatch(t *testing.T) {
+	testCases := []struct {
+		text           string
+		pattern        string
+		escape         rune
+		expectedResult bool
+		expectErr      bool
+	}{
+		{"minio", "minio", -1, true, false},
+		{"minio", "MINIO", -1, false, false},
+		{"minio", "m%", -1, true, false},
+		{"minio", "%o", -1, true, false},
+		{"minio", "%ni%", -1, true, false},
+		{"minio", "m_n_o", -1, true, false},
+		{"minio", "m_n_", -1, false, false},
+		{"minio", "%", -1, true, false},
+		{"", "%", -1, true, false},
+		{"", "_", -1, false, false},
+		{"mississippi", "%iss%ppi", -1, true, false},
+		{"50%", "50\\%", '\\', true, false},
+		{"500", "50\\%", '\\', false, false},
+		{"a_b", "a\\_b", '\\', true, false},
+		{"axb", "a\\_b", '\\', false, false},
+		{"abc", "abc\\", '\\', false, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := likeMatch([]rune(testCase.text), []rune(testCase.pattern), testCase.escape)
+		if expectErr := (err != nil); expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestValueMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		value          *Value
+		expectedResult string
+	}{
+		{NewNull(), "null"},
+		{NewBool(true), "true"},
+		{NewInt(-3), "-3"},
+		{NewFloat(1.25), "1.25"},
+		{NewString("a\"b"), `"a\"b"`},
+		{NewJSON(`{"a":[1,2]}`), `{"a":[1,2]}`},
+	}
+
+	for i, testCase := range testCases {
+		result, err := testCase.value.MarshalJSON()
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+		if string(result) != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, string(result))
+		}
+	}
+}
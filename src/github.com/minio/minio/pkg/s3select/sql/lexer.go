@@ -0,0 +1,149 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// is - returns whether the token is the given keyword or operator.
+func (t token) is(value string) bool {
+	switch t.kind {
+	case tokenIdent:
+		return strings.EqualFold(t.value, value)
+	case tokenOperator:
+		return t.value == value
+	}
+
+	return false
+}
+
+// Operators, longest first.
+var operators = []string{"<>", "!=", "<=", ">=", "||", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ".", "[", "]"}
+
+// tokenize - splits SQL text into tokens.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '\'' || r == '"':
+			// String literal in single quotes, quoted identifier in
+			// double quotes; the quote is escaped by doubling it.
+			var value []rune
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == r {
+					if j+1 < len(runes) && runes[j+1] == r {
+						value = append(value, r)
+						j++
+						continue
+					}
+					break
+				}
+				value = append(value, runes[j])
+			}
+			if j >= len(runes) {
+				return nil, errParse("unterminated quoted text at position %d", i)
+			}
+			kind := tokenString
+			if r == '"' {
+				kind = tokenQuotedIdent
+			}
+			tokens = append(tokens, token{kind, string(value), i})
+			i = j + 1
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			if j < len(runes) && (runes[j] == 'e' || runes[j] == 'E') {
+				k := j + 1
+				if k < len(runes) && (runes[k] == '+' || runes[k] == '-') {
+					k++
+				}
+				if k < len(runes) && unicode.IsDigit(runes[k]) {
+					for j = k; j < len(runes) && unicode.IsDigit(runes[j]); j++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[i:j]), i})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[i:j]), i})
+			i = j
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if hasPrefix(runes[i:], op) {
+					tokens = append(tokens, token{tokenOperator, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errParse("unexpected character %q at position %d", r, i)
+			}
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(runes)}), nil
+}
+
+// hasPrefix - returns whether runes start with ASCII operator op.
+func hasPrefix(runes []rune, op string) bool {
+	if len(runes) < len(op) {
+		return false
+	}
+	for i := 0; i < len(op); i++ {
+		if runes[i] != rune(op[i]) {
+			return false
+		}
+	}
+
+	return true
+}
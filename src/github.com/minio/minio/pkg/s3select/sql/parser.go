@@ -0,0 +1,611 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Name of the object in FROM clause.
+const s3Object = "S3Object"
+
+// Keywords which cannot be used as unquoted identifiers.
+var reservedWords = map[string]bool{
+	"AND": true, "AS": true, "BETWEEN": true, "CAST": true, "ESCAPE": true,
+	"FALSE": true, "FROM": true, "IN": true, "IS": true, "LIKE": true,
+	"LIMIT": true, "NOT": true, "NULL": true, "OR": true, "SELECT": true,
+	"TRUE": true, "WHERE": true,
+}
+
+// Aggregate functions.
+var aggregateFunctions = map[string]bool{
+	"AVG":   true,
+	"COUNT": true,
+	"MAX":   true,
+	"MIN":   true,
+	"SUM":   true,
+}
+
+type projection struct {
+	expr  expr
+	alias string
+}
+
+// Select - parsed SELECT statement.
+type Select struct {
+	projections []projection // nil for SELECT *
+	tableAlias  string
+	where       expr
+	limit       int64
+	aggregates  []*aggregateExpr
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+
+	columns    []*columnRef
+	aggregates []*aggregateExpr
+	// Number of column references outside of aggregate functions.
+	plainColumns int
+	inAggregate  bool
+}
+
+// Parse - parses SQL SELECT statement of S3 Select.
+func Parse(query string) (*Select, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	return p.parseSelect()
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+// backup - steps back over the token t returned by next.
+func (p *parser) backup(t token) {
+	if t.kind != tokenEOF {
+		p.pos--
+	}
+}
+
+// accept - consumes the next token if it is the given keyword or operator.
+func (p *parser) accept(value string) bool {
+	if p.peek().is(value) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(value string) error {
+	if !p.accept(value) {
+		return p.unexpected(value)
+	}
+
+	return nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return errParse("expected %s, got end of expression", expected)
+	}
+
+	return errParse("expected %s at position %d, got %q", expected, t.pos, t.value)
+}
+
+// isAlias - returns whether the token can be an alias.
+func isAlias(t token) bool {
+	return t.kind == tokenQuotedIdent || (t.kind == tokenIdent && !reservedWords[strings.ToUpper(t.value)])
+}
+
+func (p *parser) parseSelect() (*Select, error) {
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+
+	stmt := &Select{limit: -1}
+	if !p.accept("*") {
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			proj := projection{expr: e}
+			if p.accept("AS") {
+				if !isAlias(p.peek()) {
+					return nil, p.unexpected("alias")
+				}
+				proj.alias = p.next().value
+			} else if isAlias(p.peek()) {
+				proj.alias = p.next().value
+			}
+			stmt.projections = append(stmt.projections, proj)
+
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	stmt.aggregates = p.aggregates
+	if len(stmt.aggregates) > 0 && p.plainColumns > 0 {
+		return nil, errParse("aggregate and non-aggregate projections cannot be mixed")
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	if t := p.next(); t.kind != tokenIdent || !strings.EqualFold(t.value, s3Object) {
+		p.backup(t)
+		return nil, p.unexpected(s3Object)
+	}
+	if p.accept("[") {
+		if err := p.expect("*"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("AS") {
+		if !isAlias(p.peek()) {
+			return nil, p.unexpected("alias")
+		}
+		stmt.tableAlias = p.next().value
+	} else if isAlias(p.peek()) {
+		stmt.tableAlias = p.next().value
+	}
+
+	if p.accept("WHERE") {
+		aggregates := len(p.aggregates)
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if len(p.aggregates) > aggregates {
+			return nil, errParse("aggregate functions are not allowed in WHERE clause")
+		}
+		stmt.where = e
+	}
+
+	if p.accept("LIMIT") {
+		t := p.next()
+		limit, err := strconv.ParseInt(t.value, 10, 64)
+		if t.kind != tokenNumber || err != nil || limit < 0 {
+			return nil, errParse("invalid LIMIT %q", t.value)
+		}
+		stmt.limit = limit
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errParse("unexpected %q at position %d", t.value, t.pos)
+	}
+
+	// Column references may be qualified with the table alias.
+	for _, column := range p.columns {
+		if len(column.path) > 1 && !column.path[0].Quoted &&
+			(column.path[0].Matches(s3Object) || (stmt.tableAlias != "" && column.path[0].Matches(stmt.tableAlias))) {
+			column.path = column.path[1:]
+		}
+	}
+
+	return stmt, nil
+}
+
+// parseExpr - parses an expression; precedence from lowest is OR, AND,
+// NOT, comparison, additive, multiplicative and unary minus.
+func (p *parser) parseExpr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{false, left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{true, left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.accept("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"=", "!=", "<>", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return &comparisonExpr{op, left, right}, nil
+		}
+	}
+
+	if p.accept("IS") {
+		not := p.accept("NOT")
+		if err = p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{not, left}, nil
+	}
+
+	not := p.accept("NOT")
+	switch {
+	case p.accept("LIKE"):
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		e := &likeExpr{not: not, operand: left, pattern: pattern}
+		if p.accept("ESCAPE") {
+			if e.escape, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+		}
+		return e, nil
+
+	case p.accept("BETWEEN"):
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &betweenExpr{not, left, low, high}, nil
+
+	case p.accept("IN"):
+		if err = p.expect("("); err != nil {
+			return nil, err
+		}
+		e := &inExpr{not: not, operand: left}
+		for {
+			item, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			e.list = append(e.list, item)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+
+	if not {
+		return nil, p.unexpected("LIKE, BETWEEN or IN")
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if !t.is("+") && !t.is("-") && !t.is("||") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpr{t.value, left, right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if !t.is("*") && !t.is("/") && !t.is("%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpr{t.value, left, right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negateExpr{operand}, nil
+	}
+	p.accept("+")
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &literal{NewString(t.value)}, nil
+
+	case tokenNumber:
+		if i, err := strconv.ParseInt(t.value, 10, 64); err == nil {
+			return &literal{NewInt(i)}, nil
+		}
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, errParse("invalid number %q at position %d", t.value, t.pos)
+		}
+		return &literal{NewFloat(f)}, nil
+
+	case tokenQuotedIdent:
+		return p.parseColumnRef(Identifier{t.value, true})
+
+	case tokenIdent:
+		name := strings.ToUpper(t.value)
+		switch name {
+		case "NULL":
+			return &literal{NewNull()}, nil
+		case "TRUE":
+			return &literal{NewBool(true)}, nil
+		case "FALSE":
+			return &literal{NewBool(false)}, nil
+		case "CAST":
+			return p.parseCast()
+		}
+		if reservedWords[name] {
+			break
+		}
+		if p.peek().is("(") {
+			return p.parseFunction(name)
+		}
+		return p.parseColumnRef(Identifier{t.value, false})
+
+	case tokenOperator:
+		if t.value == "(" {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	}
+
+	p.backup(t)
+	return nil, p.unexpected("expression")
+}
+
+func (p *parser) parseColumnRef(id Identifier) (expr, error) {
+	column := &columnRef{path: []Identifier{id}}
+	for p.accept(".") {
+		t := p.next()
+		switch t.kind {
+		case tokenIdent:
+			column.path = append(column.path, Identifier{t.value, false})
+		case tokenQuotedIdent:
+			column.path = append(column.path, Identifier{t.value, true})
+		default:
+			p.backup(t)
+			return nil, p.unexpected("identifier")
+		}
+	}
+
+	p.columns = append(p.columns, column)
+	if !p.inAggregate {
+		p.plainColumns++
+	}
+
+	return column, nil
+}
+
+func (p *parser) parseCast() (expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect("AS"); err != nil {
+		return nil, err
+	}
+
+	t := p.next()
+	kind, ok := castTypes[strings.ToUpper(t.value)]
+	if t.kind != tokenIdent || !ok {
+		return nil, errParse("unsupported CAST type %q", t.value)
+	}
+	if err = p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return &castExpr{operand, kind}, nil
+}
+
+func (p *parser) parseFunction(name string) (expr, error) {
+	if !aggregateFunctions[name] {
+		return nil, errParse("unsupported function %s", name)
+	}
+	if p.inAggregate {
+		return nil, errParse("aggregate function %s cannot be nested", name)
+	}
+	p.next() // (
+
+	e := &aggregateExpr{name: name}
+	if name == "COUNT" && p.accept("*") {
+		// COUNT(*) counts all records.
+	} else {
+		p.inAggregate = true
+		operand, err := p.parseExpr()
+		p.inAggregate = false
+		if err != nil {
+			return nil, err
+		}
+		e.operand = operand
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	p.aggregates = append(p.aggregates, e)
+	return e, nil
+}
+
+// IsAggregate - returns whether the statement selects aggregate functions.
+func (stmt *Select) IsAggregate() bool {
+	return len(stmt.aggregates) > 0
+}
+
+// Limit - returns maximum number of output records, -1 if not limited.
+func (stmt *Select) Limit() int64 {
+	return stmt.limit
+}
+
+// Filter - returns whether the record matches WHERE clause.
+func (stmt *Select) Filter(record Record) (bool, error) {
+	if stmt.where == nil {
+		return true, nil
+	}
+
+	v, err := stmt.where.eval(record)
+	if err != nil {
+		return false, err
+	}
+
+	return v.isTrue(), nil
+}
+
+// Project - returns fields of the output record for the record.
+func (stmt *Select) Project(record Record) ([]Field, error) {
+	if stmt.projections == nil {
+		return record.Fields(), nil
+	}
+
+	fields := make([]Field, len(stmt.projections))
+	for i, proj := range stmt.projections {
+		v, err := proj.expr.eval(record)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = Field{proj.name(i), v}
+	}
+
+	return fields, nil
+}
+
+// Aggregate - accumulates the record in aggregate functions.
+func (stmt *Select) Aggregate(record Record) error {
+	for _, aggregate := range stmt.aggregates {
+		if err := aggregate.accumulate(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AggregateResult - returns fields of the output record of aggregate
+// functions after all records are aggregated.
+func (stmt *Select) AggregateResult() ([]Field, error) {
+	// Projections have no column references outside of aggregate
+	// functions, hence they are evaluated without record.
+	return stmt.Project(nil)
+}
+
+// name - returns name of the projection in output record.
+func (proj projection) name(n int) string {
+	if proj.alias != "" {
+		return proj.alias
+	}
+	if column, ok := proj.expr.(*columnRef); ok {
+		return column.path[len(column.path)-1].Name
+	}
+
+	return positionalName(n)
+}
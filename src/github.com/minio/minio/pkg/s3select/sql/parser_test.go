@@ -0,0 +1,247 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testRecord - record of named string fields, as read from CSV.
+type testRecord []Field
+
+func (record testRecord) Get(path []Identifier) (*Value, error) {
+	if len(path) == 1 {
+		for _, field := range record {
+			if path[0].Matches(field.Name) {
+				return field.Value, nil
+			}
+		}
+	}
+
+	return NewNull(), nil
+}
+
+func (record testRecord) Fields() []Field {
+	return record
+}
+
+func newTestRecord(values ...string) testRecord {
+	record := testRecord{}
+	for i := 0; i+1 < len(values); i += 2 {
+		record = append(record, Field{values[i], NewString(values[i+1])})
+	}
+
+	return record
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		query     string
+		expectErr bool
+	}{
+		{"SELECT * FROM S3Object", false},
+		{"select * from s3object s where s.name = 'minio'", false},
+		{"SELECT s._1, s._2 AS b FROM S3Object[*] AS s LIMIT 10", false},
+		{"SELECT COUNT(*), AVG(CAST(age AS FLOAT)) FROM S3Object WHERE age IS NOT NULL", false},
+		{"SELECT name FROM S3Object WHERE name LIKE 'a%' OR age BETWEEN 1 AND 10 OR id IN (1, 2)", false},
+		{"SELECT \"first name\" FROM S3Object", false},
+		// Missing FROM.
+		{"SELECT *", true},
+		// Unknown table.
+		{"SELECT * FROM table", true},
+		// Mixed aggregate and non-aggregate projections.
+		{"SELECT name, COUNT(*) FROM S3Object", true},
+		{"SELECT COUNT(name) + age FROM S3Object", true},
+		// Aggregate in WHERE clause.
+		{"SELECT * FROM S3Object WHERE COUNT(*) > 1", true},
+		// Nested aggregate.
+		{"SELECT SUM(COUNT(*)) FROM S3Object", true},
+		// Unsupported function and CAST type.
+		{"SELECT UPPER(name) FROM S3Object", true},
+		{"SELECT CAST(name AS BLOB) FROM S3Object", true},
+		// Invalid LIMIT.
+		{"SELECT * FROM S3Object LIMIT -1", true},
+		{"SELECT * FROM S3Object LIMIT 1.5", true},
+		// Trailing tokens.
+		{"SELECT * FROM S3Object WHERE", true},
+		{"SELECT * FROM S3Object GROUP BY name", true},
+		{"SELECT 'abc FROM S3Object", true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := Parse(testCase.query)
+		if expectErr := (err != nil); expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestSelectFilterProject(t *testing.T) {
+	record := newTestRecord("name", "minio", "age", "5", "price", "2.5", "empty", "")
+
+	testCases := []struct {
+		query          string
+		expectedMatch  bool
+		expectedFields []Field
+	}{
+		{"SELECT * FROM S3Object", true, record},
+		{"SELECT s.name, s.age + 1 AS next, price * 2 FROM S3Object s", true, []Field{
+			{"name", NewString("minio")},
+			{"next", NewInt(6)},
+			{"_3", NewFloat(5)},
+		}},
+		{"SELECT CAST(age AS INT), CAST(price AS INT), CAST(age AS STRING) FROM S3Object", true, []Field{
+			{"_1", NewInt(5)},
+			{"_2", NewInt(2)},
+			{"_3", NewString("5")},
+		}},
+		{"SELECT name FROM S3Object WHERE age > 4 AND price < 3", true, []Field{{"name", NewString("minio")}}},
+		{"SELECT name FROM S3Object WHERE age >= 10", false, nil},
+		{"SELECT name FROM S3Object WHERE NAME = 'minio'", true, []Field{{"name", NewString("minio")}}},
+		{"SELECT name FROM S3Object WHERE \"NAME\" = 'minio'", false, nil},
+		{"SELECT name FROM S3Object WHERE name LIKE 'm_n%'", true, []Field{{"name", NewString("minio")}}},
+		{"SELECT name FROM S3Object WHERE name NOT LIKE '%io'", false, nil},
+		{"SELECT name FROM S3Object WHERE age BETWEEN 1 AND 5", true, []Field{{"name", NewString("minio")}}},
+		{"SELECT name FROM S3Object WHERE age NOT IN (1, 2, 3)", true, []Field{{"name", NewString("minio")}}},
+		{"SELECT name FROM S3Object WHERE missing IS NULL AND empty IS NOT NULL", true, []Field{{"name", NewString("minio")}}},
+		// Comparison with null is not true.
+		{"SELECT name FROM S3Object WHERE missing = 1 OR NOT (missing <> 1)", false, nil},
+		{"SELECT name || '-' || age AS id FROM S3Object WHERE (age % 2) = 1", true, []Field{{"id", NewString("minio-5")}}},
+	}
+
+	for i, testCase := range testCases {
+		stmt, err := Parse(testCase.query)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+
+		matched, err := stmt.Filter(record)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+		if matched != testCase.expectedMatch {
+			t.Fatalf("case %v: match: expected: %v, got: %v", i+1, testCase.expectedMatch, matched)
+		}
+		if !matched {
+			continue
+		}
+
+		fields, err := stmt.Project(record)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(fields, testCase.expectedFields) {
+			t.Fatalf("case %v: fields: expected: %v, got: %v", i+1, testCase.expectedFields, fields)
+		}
+	}
+}
+
+func TestSelectEvalError(t *testing.T) {
+	record := newTestRecord("name", "minio", "age", "5")
+
+	testCases := []struct {
+		query        string
+		expectedCode string
+	}{
+		{"SELECT name FROM S3Object WHERE name + 1 > 2", "EvaluatorInvalidArguments"},
+		{"SELECT age / 0 FROM S3Object", "EvaluatorInvalidArguments"},
+		{"SELECT CAST(name AS INT) FROM S3Object", "InvalidCast"},
+	}
+
+	for i, testCase := range testCases {
+		stmt, err := Parse(testCase.query)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+
+		if _, err = stmt.Filter(record); err == nil {
+			_, err = stmt.Project(record)
+		}
+		sqlErr, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("case %v: expected *Error, got: %v", i+1, err)
+		}
+		if sqlErr.ErrorCode() != testCase.expectedCode {
+			t.Fatalf("case %v: code: expected: %v, got: %v", i+1, testCase.expectedCode, sqlErr.ErrorCode())
+		}
+	}
+}
+
+func TestSelectAggregate(t *testing.T) {
+	records := []testRecord{
+		newTestRecord("name", "a", "age", "5"),
+		newTestRecord("name", "b", "age", "10"),
+		newTestRecord("name", "c", "age", ""),
+		newTestRecord("name", "d"),
+	}
+
+	testCases := []struct {
+		query          string
+		expectedFields []Field
+	}{
+		{"SELECT COUNT(*), COUNT(age), COUNT(missing) FROM S3Object", []Field{
+			{"_1", NewInt(4)},
+			{"_2", NewInt(3)},
+			{"_3", NewInt(0)},
+		}},
+		{"SELECT SUM(age), MIN(age), MAX(age), MAX(name) AS last FROM S3Object WHERE age <> ''", []Field{
+			{"_1", NewInt(15)},
+			{"_2", NewInt(5)},
+			{"_3", NewInt(10)},
+			{"last", NewString("b")},
+		}},
+		{"SELECT AVG(age), SUM(age) / COUNT(age) FROM S3Object WHERE age > 0", []Field{
+			{"_1", NewFloat(7.5)},
+			{"_2", NewInt(7)},
+		}},
+		{"SELECT SUM(age), MIN(age) FROM S3Object WHERE age > 100", []Field{
+			{"_1", NewNull()},
+			{"_2", NewNull()},
+		}},
+	}
+
+	for i, testCase := range testCases {
+		stmt, err := Parse(testCase.query)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+		if !stmt.IsAggregate() {
+			t.Fatalf("case %v: expected aggregate statement", i+1)
+		}
+
+		for _, record := range records {
+			matched, err := stmt.Filter(record)
+			if err != nil {
+				t.Fatalf("case %v: unexpected error: %v", i+1, err)
+			}
+			if matched {
+				if err = stmt.Aggregate(record); err != nil {
+					t.Fatalf("case %v: unexpected error: %v", i+1, err)
+				}
+			}
+		}
+
+		fields, err := stmt.AggregateResult()
+		if err != nil {
+			t.Fatalf("case %v: unexpected error: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(fields, testCase.expectedFields) {
+			t.Fatalf("case %v: fields: expected: %v, got: %v", i+1, testCase.expectedFields, fields)
+		}
+	}
+}
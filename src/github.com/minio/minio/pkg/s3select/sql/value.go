@@ -0,0 +1,231 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sql
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Kind - type of a value.
+type Kind int
+
+// Kinds of values.
+const (
+	KindNull Kind = iota
+	KindBool
+	KindInt
+	KindFloat
+	KindString
+	// KindJSON is a JSON object or array kept as raw JSON text.
+	KindJSON
+)
+
+// Value - typed value of a field or an expression.
+type Value struct {
+	kind Kind
+	b    bool
+	i    int64
+	f    float64
+	s    string
+}
+
+// NewNull - returns null value.
+func NewNull() *Value {
+	return &Value{kind: KindNull}
+}
+
+// NewBool - returns boolean value.
+func NewBool(b bool) *Value {
+	return &Value{kind: KindBool, b: b}
+}
+
+// NewInt - returns integer value.
+func NewInt(i int64) *Value {
+	return &Value{kind: KindInt, i: i}
+}
+
+// NewFloat - returns float value.
+func NewFloat(f float64) *Value {
+	return &Value{kind: KindFloat, f: f}
+}
+
+// NewString - returns string value.
+func NewString(s string) *Value {
+	return &Value{kind: KindString, s: s}
+}
+
+// NewJSON - returns value of raw JSON object or array.
+func NewJSON(raw string) *Value {
+	return &Value{kind: KindJSON, s: raw}
+}
+
+// Kind - returns kind of the value.
+func (v *Value) Kind() Kind {
+	return v.kind
+}
+
+// IsNull - returns whether the value is null.
+func (v *Value) IsNull() bool {
+	return v.kind == KindNull
+}
+
+// String - returns the value formatted as text, as written to CSV output.
+func (v *Value) String() string {
+	switch v.kind {
+	case KindBool:
+		return strconv.FormatBool(v.b)
+	case KindInt:
+		return strconv.FormatInt(v.i, 10)
+	case KindFloat:
+		return strconv.FormatFloat(v.f, 'f', -1, 64)
+	case KindString, KindJSON:
+		return v.s
+	}
+
+	return ""
+}
+
+// MarshalJSON - encodes the value as JSON, as written to JSON output.
+func (v *Value) MarshalJSON() ([]byte, error) {
+	switch v.kind {
+	case KindNull:
+		return []byte("null"), nil
+	case KindString:
+		return json.Marshal(v.s)
+	case KindJSON:
+		return []byte(v.s), nil
+	case KindFloat:
+		if math.IsInf(v.f, 0) || math.IsNaN(v.f) {
+			return json.Marshal(v.String())
+		}
+	}
+
+	return []byte(v.String()), nil
+}
+
+// isNumber - returns whether the value is integer or float.
+func (v *Value) isNumber() bool {
+	return v.kind == KindInt || v.kind == KindFloat
+}
+
+// float - returns numeric value as float.
+func (v *Value) float() float64 {
+	if v.kind == KindInt {
+		return float64(v.i)
+	}
+
+	return v.f
+}
+
+// toNumber - returns the value as a number. Strings, as read from CSV,
+// are parsed as integer or float.
+func (v *Value) toNumber() (*Value, bool) {
+	switch v.kind {
+	case KindInt, KindFloat:
+		return v, true
+	case KindString:
+		s := strings.TrimSpace(v.s)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return NewInt(i), true
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return NewFloat(f), true
+		}
+	}
+
+	return nil, false
+}
+
+// toBool - returns the value as a boolean.
+func (v *Value) toBool() (bool, bool) {
+	switch v.kind {
+	case KindBool:
+		return v.b, true
+	case KindString:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v.s)); err == nil {
+			return b, true
+		}
+	}
+
+	return false, false
+}
+
+// isTrue - returns whether the value is boolean true. Null and
+// non-boolean values are not true.
+func (v *Value) isTrue() bool {
+	b, ok := v.toBool()
+	return ok && b
+}
+
+// compare - compares two non-null values. Numbers are compared
+// numerically with strings converted to numbers where possible,
+// everything else is compared as text. ok is false if the values are
+// not comparable.
+func compare(a, b *Value) (result int, ok bool) {
+	if a.isNumber() || b.isNumber() {
+		x, xok := a.toNumber()
+		y, yok := b.toNumber()
+		if xok && yok {
+			if x.kind == KindInt && y.kind == KindInt {
+				return compareInts(x.i, y.i), true
+			}
+			return compareFloats(x.float(), y.float()), true
+		}
+	}
+
+	if a.kind == KindBool || b.kind == KindBool {
+		x, xok := a.toBool()
+		y, yok := b.toBool()
+		if !xok || !yok {
+			return 0, false
+		}
+		switch {
+		case x == y:
+			return 0, true
+		case !x:
+			return -1, true
+		}
+		return 1, true
+	}
+
+	return strings.Compare(a.String(), b.String()), true
+}
+
+func compareInts(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+
+	return 0
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,105 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package sse implements the default server-side encryption
+// configuration of buckets.
+package sse
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Server-side encryption algorithms of a bucket.
+const (
+	AES256 = "AES256"
+	AWSKMS = "aws:kms"
+)
+
+// ErrInvalidRules - the configuration does not contain exactly one rule.
+var ErrInvalidRules = errors.New("exactly one server-side encryption rule must be specified")
+
+// ApplySSEByDefault - the encryption applied to objects uploaded without
+// server-side encryption headers.
+type ApplySSEByDefault struct {
+	SSEAlgorithm   string `xml:"SSEAlgorithm"`
+	KMSMasterKeyID string `xml:"KMSMasterKeyID,omitempty"`
+}
+
+// Rule - server-side encryption rule.
+type Rule struct {
+	DefaultEncryption ApplySSEByDefault `xml:"ApplyServerSideEncryptionByDefault"`
+}
+
+// Config - bucket server-side encryption configuration.
+type Config struct {
+	XMLName xml.Name `xml:"ServerSideEncryptionConfiguration"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	Rules   []Rule   `xml:"Rule"`
+}
+
+// Algorithm - returns the default server-side encryption algorithm.
+func (c Config) Algorithm() string {
+	if len(c.Rules) == 0 {
+		return ""
+	}
+	return c.Rules[0].DefaultEncryption.SSEAlgorithm
+}
+
+// KeyID - returns the KMS master key ID of aws:kms encryption, which
+// is empty if the default master key is used.
+func (c Config) KeyID() string {
+	if len(c.Rules) == 0 {
+		return ""
+	}
+	return c.Rules[0].DefaultEncryption.KMSMasterKeyID
+}
+
+// Validate - validates server-side encryption configuration.
+func (c Config) Validate() error {
+	if len(c.Rules) != 1 {
+		return ErrInvalidRules
+	}
+
+	rule := c.Rules[0].DefaultEncryption
+	switch rule.SSEAlgorithm {
+	case AES256:
+		if rule.KMSMasterKeyID != "" {
+			return errors.New("KMS master key ID is only allowed with aws:kms encryption")
+		}
+	case AWSKMS:
+	default:
+		return fmt.Errorf("invalid server-side encryption algorithm '%v'", rule.SSEAlgorithm)
+	}
+
+	return nil
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,170 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tagging
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"unicode/utf8"
+)
+
+// Limits on tags as per
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/object-tagging.html
+const (
+	MaxObjectTags     = 10
+	MaxBucketTags     = 50
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// ErrInvalidTag - tag set has an invalid tag.
+type ErrInvalidTag struct {
+	Reason string
+}
+
+func (err ErrInvalidTag) Error() string {
+	return fmt.Sprintf("invalid tag: %v", err.Reason)
+}
+
+func errInvalidTag(format string, a ...interface{}) error {
+	return ErrInvalidTag{Reason: fmt.Sprintf(format, a...)}
+}
+
+// Tag - key/value pair of a tag.
+type Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+func (tag Tag) isValid() error {
+	if tag.Key == "" {
+		return errInvalidTag("empty tag key")
+	}
+
+	if utf8.RuneCountInString(tag.Key) > maxTagKeyLength {
+		return errInvalidTag("tag key '%v' is longer than %v characters", tag.Key, maxTagKeyLength)
+	}
+
+	if utf8.RuneCountInString(tag.Value) > maxTagValueLength {
+		return errInvalidTag("tag value '%v' is longer than %v characters", tag.Value, maxTagValueLength)
+	}
+
+	return nil
+}
+
+// TagSet - set of tags.
+type TagSet struct {
+	Tags []Tag `xml:"Tag"`
+}
+
+// Tagging - tagging configuration of a bucket or an object.
+type Tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	TagSet  TagSet   `xml:"TagSet"`
+}
+
+// Validate - validates the tag set for at most maxTags unique tags.
+func (tagging Tagging) Validate(maxTags int) error {
+	if len(tagging.TagSet.Tags) > maxTags {
+		return errInvalidTag("more than %v tags", maxTags)
+	}
+
+	keys := make(map[string]struct{}, len(tagging.TagSet.Tags))
+	for _, tag := range tagging.TagSet.Tags {
+		if err := tag.isValid(); err != nil {
+			return err
+		}
+
+		if _, found := keys[tag.Key]; found {
+			return errInvalidTag("duplicate tag key '%v'", tag.Key)
+		}
+		keys[tag.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// IsEmpty - returns whether tag set is empty.
+func (tagging Tagging) IsEmpty() bool {
+	return len(tagging.TagSet.Tags) == 0
+}
+
+// ToMap - returns tags as key/value map.
+func (tagging Tagging) ToMap() map[string]string {
+	tags := make(map[string]string, len(tagging.TagSet.Tags))
+	for _, tag := range tagging.TagSet.Tags {
+		tags[tag.Key] = tag.Value
+	}
+
+	return tags
+}
+
+// String - returns URL query encoded tags as used by x-amz-tagging header.
+func (tagging Tagging) String() string {
+	values := make(url.Values)
+	for _, tag := range tagging.TagSet.Tags {
+		values.Set(tag.Key, tag.Value)
+	}
+
+	return values.Encode()
+}
+
+// ParseConfig - parses data in given reader to Tagging having at most maxTags tags.
+func ParseConfig(reader io.Reader, maxTags int) (*Tagging, error) {
+	var tagging Tagging
+	if err := xml.NewDecoder(reader).Decode(&tagging); err != nil {
+		return nil, err
+	}
+
+	if err := tagging.Validate(maxTags); err != nil {
+		return nil, err
+	}
+
+	return &tagging, nil
+}
+
+// ParseObjectTags - parses URL query encoded tags of x-amz-tagging header.
+func ParseObjectTags(s string) (*Tagging, error) {
+	values, err := url.ParseQuery(s)
+	if err != nil {
+		return nil, errInvalidTag("%v", err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var tagging Tagging
+	for _, key := range keys {
+		if len(values[key]) != 1 {
+			return nil, errInvalidTag("duplicate tag key '%v'", key)
+		}
+		tagging.TagSet.Tags = append(tagging.TagSet.Tags, Tag{Key: key, Value: values[key][0]})
+	}
+
+	if err = tagging.Validate(MaxObjectTags); err != nil {
+		return nil, err
+	}
+
+	return &tagging, nil
+}
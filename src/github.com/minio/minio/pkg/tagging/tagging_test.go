@@ -0,0 +1,82 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tagging
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data      string
+		maxTags   int
+		expectErr bool
+	}{
+		{`<Tagging><TagSet><Tag><Key>env</Key><Value>prod</Value></Tag></TagSet></Tagging>`, MaxObjectTags, false},
+		{`<Tagging><TagSet></TagSet></Tagging>`, MaxObjectTags, false},
+		{`<Tagging><TagSet><Tag><Key></Key><Value>prod</Value></Tag></TagSet></Tagging>`, MaxObjectTags, true},
+		{`<Tagging><TagSet><Tag><Key>env</Key><Value>a</Value></Tag><Tag><Key>env</Key><Value>b</Value></Tag></TagSet></Tagging>`, MaxObjectTags, true},
+		{`<Tagging><TagSet><Tag><Key>` + strings.Repeat("a", 129) + `</Key><Value></Value></Tag></TagSet></Tagging>`, MaxObjectTags, true},
+		{`<Tagging><TagSet><Tag><Key>a</Key><Value>` + strings.Repeat("a", 257) + `</Value></Tag></TagSet></Tagging>`, MaxObjectTags, true},
+		{`<Tagging><TagSet><Tag><Key>a</Key><Value></Value></Tag><Tag><Key>b</Key><Value></Value></Tag></TagSet></Tagging>`, 1, true},
+		{`<Tagging><TagSet>`, MaxObjectTags, true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := ParseConfig(strings.NewReader(testCase.data), testCase.maxTags)
+		if expectErr := err != nil; expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestParseObjectTags(t *testing.T) {
+	testCases := []struct {
+		s              string
+		expectedResult map[string]string
+		expectErr      bool
+	}{
+		{"", map[string]string{}, false},
+		{"env=prod&team=", map[string]string{"env": "prod", "team": ""}, false},
+		{"a%20b=c%2Fd", map[string]string{"a b": "c/d"}, false},
+		{"env=prod&env=dev", nil, true},
+		{"=prod", nil, true},
+		{"a=1&b=2&c=3&d=4&e=5&f=6&g=7&h=8&i=9&j=10&k=11", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		tagging, err := ParseObjectTags(testCase.s)
+		if expectErr := err != nil; expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+
+		if !testCase.expectErr {
+			if result := tagging.ToMap(); !reflect.DeepEqual(result, testCase.expectedResult) {
+				t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+			}
+		}
+	}
+}
+
+func TestTaggingString(t *testing.T) {
+	tagging := Tagging{TagSet: TagSet{Tags: []Tag{{"team", "a b"}, {"env", "prod"}}}}
+	if s := tagging.String(); s != "env=prod&team=a+b" {
+		t.Fatalf("expected: env=prod&team=a+b, got: %v", s)
+	}
+}
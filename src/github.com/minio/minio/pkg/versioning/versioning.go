@@ -0,0 +1,90 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package versioning
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Versioning states of a bucket.
+const (
+	Enabled   = "Enabled"
+	Suspended = "Suspended"
+)
+
+// MFA delete states of a bucket.
+const (
+	MFADeleteEnabled  = "Enabled"
+	MFADeleteDisabled = "Disabled"
+)
+
+// ErrMFADeleteNotSupported - MFA delete cannot be enabled.
+var ErrMFADeleteNotSupported = errors.New("MFA delete is not supported")
+
+// Versioning - bucket versioning configuration.
+type Versioning struct {
+	XMLName   xml.Name `xml:"VersioningConfiguration"`
+	XMLNS     string   `xml:"xmlns,attr,omitempty"`
+	Status    string   `xml:"Status,omitempty"`
+	MFADelete string   `xml:"MfaDelete,omitempty"`
+}
+
+// Enabled - returns whether versioning is enabled.
+func (v Versioning) Enabled() bool {
+	return v.Status == Enabled
+}
+
+// Suspended - returns whether versioning is suspended.
+func (v Versioning) Suspended() bool {
+	return v.Status == Suspended
+}
+
+// Validate - validates versioning configuration.
+func (v Versioning) Validate() error {
+	switch v.Status {
+	case Enabled, Suspended:
+	default:
+		return fmt.Errorf("invalid versioning status '%v'", v.Status)
+	}
+
+	switch v.MFADelete {
+	case "", MFADeleteDisabled:
+	case MFADeleteEnabled:
+		return ErrMFADeleteNotSupported
+	default:
+		return fmt.Errorf("invalid MFA delete status '%v'", v.MFADelete)
+	}
+
+	return nil
+}
+
+// ParseConfig - parses data in given reader to Versioning.
+func ParseConfig(reader io.Reader) (*Versioning, error) {
+	var v Versioning
+	if err := xml.NewDecoder(reader).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
@@ -0,0 +1,217 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package website implements the static website configuration of
+// buckets.
+package website
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Maximum number of routing rules of a website configuration.
+const maxRoutingRules = 50
+
+// ErrMissingIndexDocument - the configuration neither has an index
+// document nor redirects all requests.
+var ErrMissingIndexDocument = errors.New("IndexDocument or RedirectAllRequestsTo must be specified")
+
+// RedirectAllRequestsTo - redirects all requests to another host.
+type RedirectAllRequestsTo struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+// IndexDocument - object served for requests to directories.
+type IndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+// ErrorDocument - object served for requests returning an error.
+type ErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+// Condition - condition of a routing rule.
+type Condition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+	HTTPErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+// Redirect - redirect of a routing rule.
+type Redirect struct {
+	HostName             string `xml:"HostName,omitempty"`
+	HTTPRedirectCode     string `xml:"HttpRedirectCode,omitempty"`
+	Protocol             string `xml:"Protocol,omitempty"`
+	ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty"`
+}
+
+// RoutingRule - redirects requests matching its condition.
+type RoutingRule struct {
+	Condition *Condition `xml:"Condition,omitempty"`
+	Redirect  Redirect   `xml:"Redirect"`
+}
+
+// Config - bucket static website configuration.
+type Config struct {
+	XMLName               xml.Name               `xml:"WebsiteConfiguration"`
+	XMLNS                 string                 `xml:"xmlns,attr,omitempty"`
+	RedirectAllRequestsTo *RedirectAllRequestsTo `xml:"RedirectAllRequestsTo,omitempty"`
+	IndexDocument         *IndexDocument         `xml:"IndexDocument,omitempty"`
+	ErrorDocument         *ErrorDocument         `xml:"ErrorDocument,omitempty"`
+	RoutingRules          []RoutingRule          `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+func isValidProtocol(protocol string) bool {
+	return protocol == "" || protocol == "http" || protocol == "https"
+}
+
+// Validate - validates routing rule.
+func (r RoutingRule) Validate() error {
+	if r.Condition != nil && r.Condition.HTTPErrorCodeReturnedEquals != "" {
+		code, err := strconv.Atoi(r.Condition.HTTPErrorCodeReturnedEquals)
+		if err != nil || code < 400 || code > 599 {
+			return fmt.Errorf("invalid HttpErrorCodeReturnedEquals '%v'", r.Condition.HTTPErrorCodeReturnedEquals)
+		}
+	}
+	if r.Redirect.HTTPRedirectCode != "" {
+		code, err := strconv.Atoi(r.Redirect.HTTPRedirectCode)
+		if err != nil || code < 300 || code > 399 {
+			return fmt.Errorf("invalid HttpRedirectCode '%v'", r.Redirect.HTTPRedirectCode)
+		}
+	}
+	if !isValidProtocol(r.Redirect.Protocol) {
+		return fmt.Errorf("invalid Protocol '%v'", r.Redirect.Protocol)
+	}
+	if r.Redirect.ReplaceKeyPrefixWith != "" && r.Redirect.ReplaceKeyWith != "" {
+		return errors.New("ReplaceKeyPrefixWith and ReplaceKeyWith can not both be specified")
+	}
+	return nil
+}
+
+// Validate - validates website configuration.
+func (c Config) Validate() error {
+	if c.RedirectAllRequestsTo != nil {
+		if c.IndexDocument != nil || c.ErrorDocument != nil || len(c.RoutingRules) > 0 {
+			return errors.New("RedirectAllRequestsTo can not be specified with other elements")
+		}
+		if c.RedirectAllRequestsTo.HostName == "" {
+			return errors.New("RedirectAllRequestsTo must specify a HostName")
+		}
+		if !isValidProtocol(c.RedirectAllRequestsTo.Protocol) {
+			return fmt.Errorf("invalid Protocol '%v'", c.RedirectAllRequestsTo.Protocol)
+		}
+		return nil
+	}
+
+	if c.IndexDocument == nil {
+		return ErrMissingIndexDocument
+	}
+	if c.IndexDocument.Suffix == "" || strings.Contains(c.IndexDocument.Suffix, "/") {
+		return fmt.Errorf("invalid IndexDocument suffix '%v'", c.IndexDocument.Suffix)
+	}
+	if c.ErrorDocument != nil && c.ErrorDocument.Key == "" {
+		return errors.New("ErrorDocument must specify a Key")
+	}
+	if len(c.RoutingRules) > maxRoutingRules {
+		return fmt.Errorf("at most %v routing rules can be specified", maxRoutingRules)
+	}
+	for _, rule := range c.RoutingRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexKey - returns the key of the object served for key, which is
+// the index document of key if it names a directory.
+func (c Config) IndexKey(key string) string {
+	if c.IndexDocument != nil && (key == "" || strings.HasSuffix(key, "/")) {
+		return key + c.IndexDocument.Suffix
+	}
+	return key
+}
+
+// Route - returns the redirect location and status code of the first
+// routing rule matching key. Rules with an error code condition only
+// match if the request fails with that error code, others only match if
+// statusCode is zero. scheme and host are those of the request.
+func (c Config) Route(scheme, host, key string, statusCode int) (location string, redirectCode int, ok bool) {
+	if c.RedirectAllRequestsTo != nil {
+		protocol := c.RedirectAllRequestsTo.Protocol
+		if protocol == "" {
+			protocol = scheme
+		}
+		return protocol + "://" + c.RedirectAllRequestsTo.HostName + "/" + key, http.StatusMovedPermanently, true
+	}
+
+	for _, rule := range c.RoutingRules {
+		var prefix, errorCode string
+		if rule.Condition != nil {
+			prefix, errorCode = rule.Condition.KeyPrefixEquals, rule.Condition.HTTPErrorCodeReturnedEquals
+		}
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if (errorCode == "" && statusCode != 0) || (errorCode != "" && errorCode != strconv.Itoa(statusCode)) {
+			continue
+		}
+
+		redirect := rule.Redirect
+		switch {
+		case redirect.ReplaceKeyWith != "":
+			key = redirect.ReplaceKeyWith
+		case redirect.ReplaceKeyPrefixWith != "":
+			key = redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+		}
+		protocol := redirect.Protocol
+		if protocol == "" {
+			protocol = scheme
+		}
+		if redirect.HostName != "" {
+			host = redirect.HostName
+		}
+		redirectCode = http.StatusMovedPermanently
+		if redirect.HTTPRedirectCode != "" {
+			redirectCode, _ = strconv.Atoi(redirect.HTTPRedirectCode)
+		}
+		return protocol + "://" + host + "/" + key, redirectCode, true
+	}
+
+	return "", 0, false
+}
+
+// ParseConfig - parses data in given reader to Config.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
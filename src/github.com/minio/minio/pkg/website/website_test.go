@@ -0,0 +1,98 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package website
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data      string
+		expectErr bool
+	}{
+		{`<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`, false},
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>https</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, false},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition><Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, false},
+		// Missing index document.
+		{`<WebsiteConfiguration><ErrorDocument><Key>error.html</Key></ErrorDocument></WebsiteConfiguration>`, true},
+		// Index document suffix with a slash.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>dir/index.html</Suffix></IndexDocument></WebsiteConfiguration>`, true},
+		// Redirect of all requests with other elements.
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, true},
+		// Invalid redirect code.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><HttpRedirectCode>200</HttpRedirectCode></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, true},
+		// Both key replacements.
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><ReplaceKeyPrefixWith>a/</ReplaceKeyPrefixWith><ReplaceKeyWith>b</ReplaceKeyWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, true},
+		// Invalid XML.
+		{`<WebsiteConfiguration>`, true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := ParseConfig(strings.NewReader(testCase.data))
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+	}
+}
+
+func TestConfigRoute(t *testing.T) {
+	config := Config{
+		IndexDocument: &IndexDocument{Suffix: "index.html"},
+		RoutingRules: []RoutingRule{
+			{
+				Condition: &Condition{KeyPrefixEquals: "docs/"},
+				Redirect:  Redirect{ReplaceKeyPrefixWith: "documents/"},
+			},
+			{
+				Condition: &Condition{HTTPErrorCodeReturnedEquals: "404"},
+				Redirect:  Redirect{HostName: "example.com", Protocol: "https", ReplaceKeyWith: "not-found.html", HTTPRedirectCode: "302"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		key              string
+		statusCode       int
+		expectedLocation string
+		expectedCode     int
+		expectedOk       bool
+	}{
+		{"docs/a.html", 0, "http://bucket.host/documents/a.html", 301, true},
+		{"images/a.png", 0, "", 0, false},
+		{"images/a.png", 404, "https://example.com/not-found.html", 302, true},
+		{"images/a.png", 403, "", 0, false},
+	}
+
+	for i, testCase := range testCases {
+		location, code, ok := config.Route("http", "bucket.host", testCase.key, testCase.statusCode)
+		if ok != testCase.expectedOk || location != testCase.expectedLocation || code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: (%v, %v, %v), got: (%v, %v, %v)\n", i+1,
+				testCase.expectedLocation, testCase.expectedCode, testCase.expectedOk, location, code, ok)
+		}
+	}
+
+	if key := config.IndexKey("docs/"); key != "docs/index.html" {
+		t.Fatalf("expected index key docs/index.html, got %v", key)
+	}
+	if key := config.IndexKey("docs/a.html"); key != "docs/a.html" {
+		t.Fatalf("expected key docs/a.html, got %v", key)
+	}
+}